@@ -248,11 +248,22 @@ type InstanceServer interface {
 	GetNetworkACLsAllProjects() (acls []api.NetworkACL, err error)
 	GetNetworkACL(name string) (acl *api.NetworkACL, ETag string, err error)
 	GetNetworkACLLogfile(name string) (log io.ReadCloser, err error)
+	GetNetworkACLState(name string) (state *api.NetworkACLState, err error)
 	CreateNetworkACL(acl api.NetworkACLsPost) (op Operation, err error)
 	UpdateNetworkACL(name string, acl api.NetworkACLPut, ETag string) (op Operation, err error)
 	RenameNetworkACL(name string, acl api.NetworkACLPost) (op Operation, err error)
 	DeleteNetworkACL(name string) (op Operation, err error)
 
+	// Network address set functions ("network_address_sets" API extension)
+	GetNetworkAddressSetNames() (names []string, err error)
+	GetNetworkAddressSets() (sets []api.NetworkAddressSet, err error)
+	GetNetworkAddressSetsAllProjects() (sets []api.NetworkAddressSet, err error)
+	GetNetworkAddressSet(name string) (set *api.NetworkAddressSet, ETag string, err error)
+	CreateNetworkAddressSet(set api.NetworkAddressSetsPost) (op Operation, err error)
+	UpdateNetworkAddressSet(name string, set api.NetworkAddressSetPut, ETag string) (op Operation, err error)
+	RenameNetworkAddressSet(name string, set api.NetworkAddressSetPost) (op Operation, err error)
+	DeleteNetworkAddressSet(name string) (op Operation, err error)
+
 	// Network allocations functions ("network_allocations" API extension)
 	GetNetworkAllocations(allProjects bool) (allocations []api.NetworkAllocations, err error)
 
@@ -312,6 +323,8 @@ type InstanceServer interface {
 	CreateStoragePool(pool api.StoragePoolsPost) (op Operation, err error)
 	UpdateStoragePool(name string, pool api.StoragePoolPut, ETag string) (op Operation, err error)
 	DeleteStoragePool(name string) (op Operation, err error)
+	MigrateStoragePool(name string, pool api.StoragePoolMigratePost) (op Operation, err error)
+	ScrubStoragePool(name string) (op Operation, err error)
 
 	// Storage bucket functions ("storage_buckets" API extension)
 	GetStoragePoolBucketNames(poolName string) ([]string, error)
@@ -348,6 +361,7 @@ type InstanceServer interface {
 	CopyStoragePoolVolume(pool string, source InstanceServer, sourcePool string, volume api.StorageVolume, args *StoragePoolVolumeCopyArgs) (op RemoteOperation, err error)
 	MoveStoragePoolVolume(pool string, source InstanceServer, sourcePool string, volume api.StorageVolume, args *StoragePoolVolumeMoveArgs) (op RemoteOperation, err error)
 	MigrateStoragePoolVolume(pool string, volume api.StorageVolumePost) (op Operation, err error)
+	VerifyStoragePoolVolume(pool string, volType string, name string) (op Operation, err error)
 
 	// Storage volume snapshot functions ("storage_api_volume_snapshots" API extension)
 	CreateStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshot api.StorageVolumeSnapshotsPost) (op Operation, err error)
@@ -409,12 +423,23 @@ type InstanceServer interface {
 	GetReplicatorNames() (replicatorNames []string, err error)
 	GetReplicator(project string, name string) (replicator *api.Replicator, ETag string, err error)
 	GetReplicatorState(project string, name string) (replicatorState *api.ReplicatorState, err error)
+	GetReplicatorRuns(project string, name string) (runs []api.ReplicatorRun, err error)
 	CreateReplicator(project string, replicator api.ReplicatorsPost) (err error)
 	UpdateReplicator(project string, name string, replicator api.ReplicatorPut, ETag string) (err error)
 	DeleteReplicator(project string, name string) (err error)
 	RunReplicator(project string, name string, req api.ReplicatorStatePut) (op Operation, err error)
 	RenameReplicator(project string, name string, replicator api.ReplicatorPost) (err error)
 
+	// Backup target functions
+	GetBackupTargetNames() (names []string, err error)
+	GetBackupTargets() (targets []api.BackupTarget, err error)
+	GetBackupTarget(name string) (target *api.BackupTarget, ETag string, err error)
+	GetBackupTargetBackups(name string) (backups []api.BackupTargetBackup, err error)
+	CreateBackupTarget(target api.BackupTargetsPost) (err error)
+	UpdateBackupTarget(name string, target api.BackupTargetPut, ETag string) (err error)
+	RenameBackupTarget(name string, target api.BackupTargetPost) (err error)
+	DeleteBackupTarget(name string) (err error)
+
 	// Warning functions
 	GetWarningUUIDs() (uuids []string, err error)
 	GetWarnings() (warnings []api.Warning, err error)
@@ -469,6 +494,8 @@ type InstanceServer interface {
 	UpdatePlacementGroup(placementGroupName string, placementGroupPut api.PlacementGroupPut, ETag string) error
 	DeletePlacementGroup(placementGroupName string) error
 	RenamePlacementGroup(placementGroupName string, placementGroupPost api.PlacementGroupPost) error
+	GetPlacementGroupRebalance(placementGroupName string) (plan *api.PlacementGroupRebalance, err error)
+	RebalancePlacementGroup(placementGroupName string) (op Operation, err error)
 
 	// Internal functions (for internal use)
 	RawQuery(method string, path string, data any, queryETag string) (resp *api.Response, ETag string, err error)
@@ -664,6 +691,9 @@ type StoragePoolVolumeCopyArgs struct {
 
 	// API extension: custom_volume_refresh
 	Refresh bool
+
+	// API extension: storage_volume_clone
+	Clone bool
 }
 
 // The StoragePoolVolumeMoveArgs struct is used to pass additional options
@@ -683,6 +713,12 @@ type StoragePoolVolumeBackupArgs struct {
 
 	// Name to import backup as
 	Name string
+
+	// Backup target to import the backup from instead of BackupFile (requires "backup_targets" API extension)
+	BackupTarget string
+
+	// Key of the backup on the backup target
+	BackupTargetKey string
 }
 
 // The InstanceBackupArgs struct is used when creating a instance from a backup.
@@ -698,6 +734,18 @@ type InstanceBackupArgs struct {
 
 	// If set, it would override devices
 	Devices map[string]map[string]string
+
+	// Backup target to import the backup from instead of BackupFile (requires "backup_targets" API extension)
+	BackupTarget string
+
+	// Key of the backup on the backup target
+	BackupTargetKey string
+
+	// Parent backups of an incremental backup, oldest first (requires "backup_incremental" API extension)
+	ParentBackupFiles []io.ReadSeeker
+
+	// Keys of the parent backups of an incremental backup on the backup target, oldest first (requires "backup_incremental" API extension)
+	BackupTargetParentKeys []string
 }
 
 // The InstanceCopyArgs struct is used to pass additional options during instance copy.
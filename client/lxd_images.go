@@ -420,6 +420,13 @@ func (r *ProtocolLXD) CreateImage(image api.ImagesPost, args *ImageCreateArgs) (
 		}
 	}
 
+	if image.Sign {
+		err := r.CheckExtension("image_signatures")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the JSON based request
 	if args == nil {
 		op, _, err := r.queryOperation(http.MethodPost, "/images", image, "", true)
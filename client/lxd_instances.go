@@ -478,7 +478,7 @@ func (r *ProtocolLXD) CreateInstanceFromBackup(args InstanceBackupArgs) (Operati
 		return nil, err
 	}
 
-	if args.PoolName == "" && args.Name == "" && len(args.Devices) == 0 {
+	if args.PoolName == "" && args.Name == "" && len(args.Devices) == 0 && args.BackupTarget == "" && len(args.ParentBackupFiles) == 0 {
 		// Send the request
 		op, _, err := r.queryOperation(http.MethodPost, path, args.BackupFile, "", true)
 		if err != nil {
@@ -509,6 +509,43 @@ func (r *ProtocolLXD) CreateInstanceFromBackup(args InstanceBackupArgs) (Operati
 		}
 	}
 
+	if args.BackupTarget != "" {
+		err = r.CheckExtension("backup_targets")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(args.ParentBackupFiles) > 0 || len(args.BackupTargetParentKeys) > 0 {
+		err = r.CheckExtension("backup_incremental")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Parent backups are sent ahead of the backup.
+	body := args.BackupFile
+	parentSizes := make([]string, 0, len(args.ParentBackupFiles))
+	if len(args.ParentBackupFiles) > 0 {
+		readers := make([]io.Reader, 0, len(args.ParentBackupFiles)+1)
+		for _, parent := range args.ParentBackupFiles {
+			size, err := parent.Seek(0, io.SeekEnd)
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = parent.Seek(0, io.SeekStart)
+			if err != nil {
+				return nil, err
+			}
+
+			parentSizes = append(parentSizes, strconv.FormatInt(size, 10))
+			readers = append(readers, parent)
+		}
+
+		body = io.MultiReader(append(readers, args.BackupFile)...)
+	}
+
 	// Prepare the HTTP request
 	reqURL, err := r.setQueryAttributes(r.httpBaseURL.String() + "/1.0" + path)
 
@@ -516,7 +553,7 @@ func (r *ProtocolLXD) CreateInstanceFromBackup(args InstanceBackupArgs) (Operati
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, reqURL, args.BackupFile)
+	req, err := http.NewRequest(http.MethodPost, reqURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -546,6 +583,19 @@ func (r *ProtocolLXD) CreateInstanceFromBackup(args InstanceBackupArgs) (Operati
 		req.Header.Set("X-LXD-devices", devProps.Encode())
 	}
 
+	if args.BackupTarget != "" {
+		req.Header.Set("X-LXD-backup-target", args.BackupTarget)
+		req.Header.Set("X-LXD-backup-key", args.BackupTargetKey)
+	}
+
+	if len(parentSizes) > 0 {
+		req.Header.Set("X-LXD-backup-parent-sizes", strings.Join(parentSizes, ","))
+	}
+
+	if len(args.BackupTargetParentKeys) > 0 {
+		req.Header.Set("X-LXD-backup-parent-keys", strings.Join(args.BackupTargetParentKeys, ","))
+	}
+
 	// Send the request
 	resp, err := r.DoHTTP(req)
 	if err != nil {
@@ -2889,6 +2939,20 @@ func (r *ProtocolLXD) CreateInstanceBackup(instanceName string, backup api.Insta
 		return nil, err
 	}
 
+	if backup.Target != "" {
+		err = r.CheckExtension("backup_targets")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if backup.ParentSnapshot != "" {
+		err = r.CheckExtension("backup_incremental")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation(http.MethodPost, path+"/"+url.PathEscape(instanceName)+"/backups", backup, "", true)
 	if err != nil {
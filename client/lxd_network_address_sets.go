@@ -0,0 +1,166 @@
+package lxd
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// GetNetworkAddressSetNames returns a list of network address set names.
+func (r *ProtocolLXD) GetNetworkAddressSetNames() ([]string, error) {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/network-address-sets"
+	_, err = r.queryStruct(http.MethodGet, baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetNetworkAddressSets returns a list of network address set structs.
+func (r *ProtocolLXD) GetNetworkAddressSets() ([]api.NetworkAddressSet, error) {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return nil, err
+	}
+
+	sets := []api.NetworkAddressSet{}
+
+	// Fetch the raw value.
+	_, err = r.queryStruct(http.MethodGet, "/network-address-sets?recursion=1", nil, "", &sets)
+	if err != nil {
+		return nil, err
+	}
+
+	return sets, nil
+}
+
+// GetNetworkAddressSetsAllProjects returns a list of network address sets across all projects.
+func (r *ProtocolLXD) GetNetworkAddressSetsAllProjects() ([]api.NetworkAddressSet, error) {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return nil, err
+	}
+
+	sets := []api.NetworkAddressSet{}
+	u := api.NewURL().Path("network-address-sets").WithQuery("recursion", "1").WithQuery("all-projects", "true")
+	_, err = r.queryStruct(http.MethodGet, u.String(), nil, "", &sets)
+	if err != nil {
+		return nil, err
+	}
+
+	return sets, nil
+}
+
+// GetNetworkAddressSet returns a network address set entry for the provided name.
+func (r *ProtocolLXD) GetNetworkAddressSet(name string) (*api.NetworkAddressSet, string, error) {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return nil, "", err
+	}
+
+	set := api.NetworkAddressSet{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct(http.MethodGet, "/network-address-sets/"+url.PathEscape(name), nil, "", &set)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &set, etag, nil
+}
+
+// CreateNetworkAddressSet defines a new network address set using the provided struct.
+func (r *ProtocolLXD) CreateNetworkAddressSet(set api.NetworkAddressSetsPost) (Operation, error) {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation(http.MethodPost, "/network-address-sets", set, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// UpdateNetworkAddressSet updates the network address set to match the provided struct.
+func (r *ProtocolLXD) UpdateNetworkAddressSet(name string, set api.NetworkAddressSetPut, ETag string) (Operation, error) {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return nil, err
+	}
+
+	path := api.NewURL().Path("network-address-sets", name)
+
+	var op Operation
+
+	// Send the request.
+	if r.isClusterOperationNotification() {
+		// Use a synchronous request when handling a cluster operation notification.
+		op = noopOperation{}
+		_, _, err = r.query(http.MethodPut, path.String(), set, ETag)
+	} else {
+		op, _, err = r.queryOperation(http.MethodPut, path.String(), set, ETag, true)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// RenameNetworkAddressSet renames an existing network address set entry.
+func (r *ProtocolLXD) RenameNetworkAddressSet(name string, set api.NetworkAddressSetPost) (Operation, error) {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return nil, err
+	}
+
+	path := api.NewURL().Path("network-address-sets", name)
+
+	op, _, err := r.queryOperation(http.MethodPost, path.String(), set, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// DeleteNetworkAddressSet deletes an existing network address set.
+func (r *ProtocolLXD) DeleteNetworkAddressSet(name string) (Operation, error) {
+	err := r.CheckExtension("network_address_sets")
+	if err != nil {
+		return nil, err
+	}
+
+	path := api.NewURL().Path("network-address-sets", name)
+
+	var op Operation
+
+	// Send the request.
+	if r.isClusterOperationNotification() {
+		// Use a synchronous request when handling a cluster operation notification.
+		op = noopOperation{}
+		_, _, err = r.query(http.MethodDelete, path.String(), nil, "")
+	} else {
+		op, _, err = r.queryOperation(http.MethodDelete, path.String(), nil, "", true)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
@@ -147,3 +147,34 @@ func (r *ProtocolLXD) RenamePlacementGroup(placementGroupName string, placementG
 
 	return nil
 }
+
+// GetPlacementGroupRebalance returns the instance migrations that rebalancing the placement group would perform.
+func (r *ProtocolLXD) GetPlacementGroupRebalance(placementGroupName string) (*api.PlacementGroupRebalance, error) {
+	err := r.CheckExtension("placement_group_rebalance")
+	if err != nil {
+		return nil, err
+	}
+
+	plan := api.PlacementGroupRebalance{}
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("placement-groups", placementGroupName, "rebalance").String(), nil, "", &plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// RebalancePlacementGroup migrates the instances of the placement group so that they comply with its policy again.
+func (r *ProtocolLXD) RebalancePlacementGroup(placementGroupName string) (Operation, error) {
+	err := r.CheckExtension("placement_group_rebalance")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation(http.MethodPost, api.NewURL().Path("placement-groups", placementGroupName, "rebalance").String(), nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
@@ -97,6 +97,13 @@ func (r *ProtocolLXD) RunReplicator(project string, name string, req api.Replica
 		return nil, err
 	}
 
+	if req.Action == "promote" {
+		err = r.CheckExtension("replicator_promote")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	op, _, err := r.queryOperation(http.MethodPut, api.NewURL().Path("replicators", name, "state").Project(project).String(), req, "", true)
 	if err != nil {
 		return nil, err
@@ -132,3 +139,20 @@ func (r *ProtocolLXD) RenameReplicator(project string, name string, req api.Repl
 	_, _, err = r.query(http.MethodPost, api.NewURL().Path("replicators", name).Project(project).String(), req, "")
 	return err
 }
+
+// GetReplicatorRuns returns the recorded runs of a replicator, most recent first.
+func (r *ProtocolLXD) GetReplicatorRuns(project string, name string) ([]api.ReplicatorRun, error) {
+	err := r.CheckExtension("replicator_run_history")
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []api.ReplicatorRun{}
+	u := api.NewURL().Path("replicators", name, "runs").Project(project).WithQuery("recursion", "1")
+	_, err = r.queryStruct(http.MethodGet, u.String(), nil, "", &runs)
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
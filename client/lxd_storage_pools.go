@@ -174,3 +174,35 @@ func (r *ProtocolLXD) GetStoragePoolResources(name string) (*api.ResourcesStorag
 
 	return &res, nil
 }
+
+// MigrateStoragePool moves all volumes of a storage pool to another storage pool.
+func (r *ProtocolLXD) MigrateStoragePool(name string, pool api.StoragePoolMigratePost) (Operation, error) {
+	err := r.CheckExtension("storage_pool_migrate")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, api.NewURL().Path("storage-pools", name, "migrate").String(), pool, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// ScrubStoragePool checks the integrity of the data stored in a storage pool.
+func (r *ProtocolLXD) ScrubStoragePool(name string) (Operation, error) {
+	err := r.CheckExtension("storage_volume_verify")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, api.NewURL().Path("storage-pools", name, "scrub").String(), nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
@@ -411,6 +411,23 @@ func (r *ProtocolLXD) UpdateStoragePoolVolumeSnapshot(pool string, volumeType st
 	return op, nil
 }
 
+// VerifyStoragePoolVolume checks the integrity of a storage volume.
+// The checksum of block volumes is returned in the "checksum" field of the operation metadata.
+func (r *ProtocolLXD) VerifyStoragePoolVolume(pool string, volType string, name string) (Operation, error) {
+	err := r.CheckExtension("storage_volume_verify")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, api.NewURL().Path("storage-pools", pool, "volumes", volType, name, "verify").String(), nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // MigrateStoragePoolVolume requests that LXD prepares for a storage volume migration.
 func (r *ProtocolLXD) MigrateStoragePoolVolume(pool string, volume api.StorageVolumePost) (Operation, error) {
 	err := r.CheckExtension("storage_api_remote_volume_handling")
@@ -577,6 +594,10 @@ func (r *ProtocolLXD) CopyStoragePoolVolume(pool string, source InstanceServer,
 		return nil, errors.New("The target server is missing the required \"custom_volume_refresh\" API extension")
 	}
 
+	if args != nil && args.Clone && r.CheckExtension("storage_volume_clone") != nil {
+		return nil, errors.New("The target server is missing the required \"storage_volume_clone\" API extension")
+	}
+
 	destVolumeName := volume.Name
 	if args != nil && args.Name != "" {
 		destVolumeName = args.Name
@@ -591,6 +612,7 @@ func (r *ProtocolLXD) CopyStoragePoolVolume(pool string, source InstanceServer,
 			Pool:       sourcePool,
 			VolumeOnly: args != nil && args.VolumeOnly,
 			Refresh:    args != nil && args.Refresh,
+			Clone:      args != nil && args.Clone,
 		},
 	}
 
@@ -646,6 +668,10 @@ func (r *ProtocolLXD) CopyStoragePoolVolume(pool string, source InstanceServer,
 		return &rop, nil
 	}
 
+	if args != nil && args.Clone {
+		return nil, errors.New("Volume clones can only be created on the server of their source")
+	}
+
 	err = r.CheckExtension("storage_api_remote_volume_handling")
 	if err != nil {
 		return nil, err
@@ -968,6 +994,13 @@ func (r *ProtocolLXD) CreateStoragePoolVolumeBackup(pool string, volName string,
 		return nil, err
 	}
 
+	if backup.Target != "" {
+		err = r.CheckExtension("backup_targets")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation(http.MethodPost, "/storage-pools/"+url.PathEscape(pool)+"/volumes/custom/"+url.PathEscape(volName)+"/backups", backup, "", true)
 	if err != nil {
@@ -1086,6 +1119,11 @@ func (r *ProtocolLXD) createStoragePoolVolumeFromFile(pool string, args StorageP
 		req.Header.Set("X-LXD-type", fileType)
 	}
 
+	if args.BackupTarget != "" {
+		req.Header.Set("X-LXD-backup-target", args.BackupTarget)
+		req.Header.Set("X-LXD-backup-key", args.BackupTargetKey)
+	}
+
 	// Send the request.
 	resp, err := r.DoHTTP(req)
 	if err != nil {
@@ -1158,5 +1196,12 @@ func (r *ProtocolLXD) CreateStoragePoolVolumeFromBackup(pool string, args Storag
 		}
 	}
 
+	if args.BackupTarget != "" {
+		err := r.CheckExtension("backup_targets")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return r.createStoragePoolVolumeFromFile(pool, args, "")
 }
@@ -0,0 +1,282 @@
+package lxd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// ProtocolOCI implements an OCI distribution (container registry) API client.
+type ProtocolOCI struct {
+	http            *http.Client
+	httpHost        string
+	httpUserAgent   string
+	httpCertificate string
+
+	// Registry API endpoint and repository namespace derived from the URL.
+	registryURL *url.URL
+	namespace   string
+
+	// Optional credentials derived from the URL.
+	username string
+	password string
+
+	// Directory holding the converted images (either within the cache path or a temporary directory).
+	imagesPath string
+	tempPath   string
+
+	mu     sync.Mutex
+	tokens map[string]string
+	images map[string]*ociImage
+}
+
+// Disconnect removes the temporary files used by the OCI client.
+func (r *ProtocolOCI) Disconnect() {
+	if r.tempPath != "" {
+		_ = os.RemoveAll(r.tempPath)
+	}
+}
+
+// ociPruneCache removes the converted images and conversion records of the cache directory that weren't used for
+// longer than expiry.
+func ociPruneCache(imagesPath string, expiry time.Duration) error {
+	entries, err := os.ReadDir(imagesPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".tar" && ext != ".json") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		if time.Since(info.ModTime()) > expiry {
+			_ = os.Remove(filepath.Join(imagesPath, entry.Name()))
+		}
+	}
+
+	return nil
+}
+
+// GetConnectionInfo returns the basic connection information used to interact with the server.
+func (r *ProtocolOCI) GetConnectionInfo() (*ConnectionInfo, error) {
+	info := ConnectionInfo{}
+	info.Addresses = []string{r.httpHost}
+	info.Certificate = r.httpCertificate
+	info.Protocol = api.ImageRegistryProtocolOCI
+	info.URL = r.httpHost
+
+	return &info, nil
+}
+
+// GetHTTPClient returns the http client used for the connection. This can be used to set custom http options.
+func (r *ProtocolOCI) GetHTTPClient() (*http.Client, error) {
+	if r.http == nil {
+		return nil, errors.New("HTTP client is not set, bad connection")
+	}
+
+	return r.http, nil
+}
+
+// DoHTTP performs a Request.
+func (r *ProtocolOCI) DoHTTP(req *http.Request) (*http.Response, error) {
+	// Set the user agent
+	if r.httpUserAgent != "" {
+		req.Header.Set("User-Agent", r.httpUserAgent)
+	}
+
+	return r.http.Do(req)
+}
+
+// doRegistry performs a GET request against the registry API of the given repository. The request is retried once
+// with a token if the registry requires authentication.
+func (r *ProtocolOCI) doRegistry(repository string, path string, accept []string) (*http.Response, error) {
+	reqURL := r.registryURL.JoinPath("v2", repository, path)
+
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodGet, reqURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+
+		r.mu.Lock()
+		token := r.tokens[repository]
+		r.mu.Unlock()
+
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		} else if r.username != "" {
+			req.SetBasicAuth(r.username, r.password)
+		}
+
+		return req, nil
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+
+		err = r.authenticate(repository, challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err = newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = r.DoHTTP(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, api.StatusErrorf(resp.StatusCode, "Failed fetching %q of %q from OCI registry: %s", path, repository, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// authenticate gets a bearer token for pulling from the given repository, as requested by the challenge returned by
+// the registry.
+func (r *ProtocolOCI) authenticate(repository string, challenge string) error {
+	scheme, params := parseAuthChallenge(challenge)
+	if !strings.EqualFold(scheme, "bearer") {
+		if r.username != "" {
+			return api.StatusErrorf(http.StatusUnauthorized, "Invalid credentials for OCI registry %q", r.registryURL.Host)
+		}
+
+		return api.StatusErrorf(http.StatusUnauthorized, "OCI registry %q requires credentials", r.registryURL.Host)
+	}
+
+	if params["realm"] == "" {
+		return fmt.Errorf("OCI registry %q returned an authentication challenge without realm", r.registryURL.Host)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return fmt.Errorf("Invalid authentication realm %q: %w", params["realm"], err)
+	}
+
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + repository + ":pull"
+	}
+
+	query := tokenURL.Query()
+	query.Set("scope", scope)
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return fmt.Errorf("Failed getting OCI registry token: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return api.StatusErrorf(resp.StatusCode, "Failed getting OCI registry token: %s", resp.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&token)
+	if err != nil {
+		return fmt.Errorf("Failed parsing OCI registry token: %w", err)
+	}
+
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+
+	if token.Token == "" {
+		return errors.New("OCI registry returned an empty token")
+	}
+
+	r.mu.Lock()
+	r.tokens[repository] = token.Token
+	r.mu.Unlock()
+
+	return nil
+}
+
+// parseAuthChallenge parses a WWW-Authenticate header such as `Bearer realm="https://example.com/token",scope="x"`
+// into its scheme and parameters.
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+
+	for rest != "" {
+		var key string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			// Quoted values may contain commas.
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+
+		if key != "" {
+			params[key] = strings.TrimSpace(value)
+		}
+	}
+
+	return scheme, params
+}
@@ -0,0 +1,769 @@
+package lxd
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/ioprogress"
+	"github.com/canonical/lxd/shared/osarch"
+)
+
+// OCI and Docker media types of image manifests and indexes.
+const (
+	ociMediaTypeManifest        = "application/vnd.oci.image.manifest.v1+json"
+	ociMediaTypeIndex           = "application/vnd.oci.image.index.v1+json"
+	dockerMediaTypeManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	dockerMediaTypeManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+// ociManifestMaxSize is the maximum size of the manifests and image configurations fetched from the registry.
+const ociManifestMaxSize = 4 * 1024 * 1024
+
+var ociRepositoryRegex = regexp.MustCompile(`^[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*(/[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*)*$`)
+var ociTagRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+var ociDigestRegex = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ociDescriptor references a manifest or a blob of the registry.
+type ociDescriptor struct {
+	MediaType string       `json:"mediaType"`
+	Digest    string       `json:"digest"`
+	Size      int64        `json:"size"`
+	Platform  *ociPlatform `json:"platform,omitempty"`
+}
+
+// ociPlatform is the platform an image manifest of an image index applies to.
+type ociPlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// ociManifest is an image manifest or an image index (when Manifests is set).
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Config    ociDescriptor   `json:"config"`
+	Layers    []ociDescriptor `json:"layers"`
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+// ociImageConfig is the part of the image configuration used to build the LXD image.
+type ociImageConfig struct {
+	Architecture string     `json:"architecture"`
+	OS           string     `json:"os"`
+	Variant      string     `json:"variant,omitempty"`
+	Created      *time.Time `json:"created,omitempty"`
+	Config       struct {
+		Entrypoint []string `json:"Entrypoint"`
+		Cmd        []string `json:"Cmd"`
+		Env        []string `json:"Env"`
+		WorkingDir string   `json:"WorkingDir"`
+	} `json:"config"`
+}
+
+// ociImage is an image of the registry along with its LXD representation. The fingerprint and size of the LXD
+// image are only known once the image was converted.
+type ociImage struct {
+	repository string
+	digest     string
+	manifest   ociManifest
+	info       api.Image
+}
+
+// ociConversion records the result of the conversion of an image manifest to a LXD image.
+type ociConversion struct {
+	Fingerprint string `json:"fingerprint"`
+	Size        int64  `json:"size"`
+}
+
+// Image handling functions
+
+// GetImages isn't supported by the OCI protocol, as registries can't list images across repositories.
+func (r *ProtocolOCI) GetImages() ([]api.Image, error) {
+	return nil, errors.New("Listing images is not supported by the OCI protocol")
+}
+
+// GetImageFingerprints isn't supported by the OCI protocol, as registries can't list images across repositories.
+func (r *ProtocolOCI) GetImageFingerprints() ([]string, error) {
+	return nil, errors.New("Listing images is not supported by the OCI protocol")
+}
+
+// GetImagesWithFilter isn't supported by the OCI protocol, as registries can't list images across repositories.
+func (r *ProtocolOCI) GetImagesWithFilter(filters []string) ([]api.Image, error) {
+	return nil, errors.New("GetImagesWithFilter is not supported by the OCI protocol")
+}
+
+// GetImage returns an Image struct for the provided fingerprint, image alias target or image reference (such as
+// "alpine:3.20"). The fingerprint of an image is only known once it was resolved from its reference, and the image
+// is converted if its fingerprint isn't known yet.
+func (r *ProtocolOCI) GetImage(fingerprint string) (*api.Image, string, error) {
+	img, err := r.getImage(fingerprint)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed getting image: %w", err)
+	}
+
+	if img.info.Fingerprint == "" {
+		err = r.convert(img, nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("Failed getting image: %w", err)
+		}
+	}
+
+	info := img.info
+	return &info, "", nil
+}
+
+// GetImageFile downloads an image from the registry, returning an ImageFileResponse struct.
+// The image is returned as a unified image in the metadata file.
+func (r *ProtocolOCI) GetImageFile(fingerprint string, req ImageFileRequest) (*ImageFileResponse, error) {
+	// Quick checks.
+	if req.MetaFile == nil {
+		return nil, errors.New("No file requested")
+	}
+
+	img, err := r.getImage(fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	if img.info.Fingerprint == "" {
+		err = r.convert(img, req.ProgressHandler)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	expectedFingerprint := img.info.Fingerprint
+
+	imagePath, err := r.getImagePath(img)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the image again if it was already handed out or removed from the cache.
+	f, err := os.Open(imagePath)
+	if errors.Is(err, os.ErrNotExist) {
+		err = r.convert(img, req.ProgressHandler)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err = os.Open(imagePath)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	// The converted image is only kept until handed out, so that the cache only holds the conversion records.
+	defer func() { _ = os.Remove(imagePath) }()
+
+	_, err = req.MetaFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(req.MetaFile, hash), f)
+	if err != nil {
+		return nil, err
+	}
+
+	imageFingerprint := hex.EncodeToString(hash.Sum(nil))
+	if imageFingerprint != expectedFingerprint {
+		return nil, fmt.Errorf("Image fingerprint mismatch. Got %s expected %s", imageFingerprint, expectedFingerprint)
+	}
+
+	return &ImageFileResponse{MetaName: img.info.Filename, MetaSize: size}, nil
+}
+
+// GetImageSecret isn't relevant for the OCI protocol.
+func (r *ProtocolOCI) GetImageSecret(fingerprint string) (string, error) {
+	return "", errors.New("Private images are not supported by the OCI protocol")
+}
+
+// GetPrivateImage isn't relevant for the OCI protocol.
+func (r *ProtocolOCI) GetPrivateImage(fingerprint string, secret string) (*api.Image, string, error) {
+	return nil, "", errors.New("Private images are not supported by the OCI protocol")
+}
+
+// GetPrivateImageFile isn't relevant for the OCI protocol.
+func (r *ProtocolOCI) GetPrivateImageFile(fingerprint string, secret string, req ImageFileRequest) (*ImageFileResponse, error) {
+	return nil, errors.New("Private images are not supported by the OCI protocol")
+}
+
+// GetImageAliases isn't supported by the OCI protocol, as registries can't list images across repositories.
+func (r *ProtocolOCI) GetImageAliases() ([]api.ImageAliasesEntry, error) {
+	return nil, errors.New("Listing image aliases is not supported by the OCI protocol")
+}
+
+// GetImageAliasNames isn't supported by the OCI protocol, as registries can't list images across repositories.
+func (r *ProtocolOCI) GetImageAliasNames() ([]string, error) {
+	return nil, errors.New("Listing image aliases is not supported by the OCI protocol")
+}
+
+// GetImageAlias resolves an image reference (such as "alpine:3.20") as an ImageAliasesEntry struct.
+func (r *ProtocolOCI) GetImageAlias(name string) (*api.ImageAliasesEntry, string, error) {
+	return r.GetImageAliasType("", name)
+}
+
+// GetImageAliasType resolves an image reference (such as "alpine:3.20") as an ImageAliasesEntry struct.
+// OCI images are only available as containers. Only the manifest and configuration of the image are fetched, so the
+// target of the alias is the digest of the image manifest, which GetImage accepts in place of a fingerprint.
+func (r *ProtocolOCI) GetImageAliasType(imageType string, name string) (*api.ImageAliasesEntry, string, error) {
+	if imageType != "" && imageType != "container" {
+		return nil, "", api.StatusErrorf(http.StatusNotFound, "OCI images are only available as containers")
+	}
+
+	img, err := r.resolve(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	alias := api.ImageAliasesEntry{
+		Name:   name,
+		Type:   "container",
+		Target: img.digest,
+	}
+
+	return &alias, "", nil
+}
+
+// GetImageAliasArchitectures returns a map of architectures / targets.
+// Only the image matching the local architecture is resolved from multi-platform images.
+func (r *ProtocolOCI) GetImageAliasArchitectures(imageType string, name string) (map[string]*api.ImageAliasesEntry, error) {
+	if imageType != "" && imageType != "container" {
+		return nil, api.StatusErrorf(http.StatusNotFound, "OCI images are only available as containers")
+	}
+
+	img, err := r.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	alias := api.ImageAliasesEntry{
+		Name:   name,
+		Type:   "container",
+		Target: img.digest,
+	}
+
+	return map[string]*api.ImageAliasesEntry{img.info.Architecture: &alias}, nil
+}
+
+// ExportImage exports (copies) an image to a remote server.
+func (r *ProtocolOCI) ExportImage(fingerprint string, image api.ImageExportPost) (Operation, error) {
+	return nil, errors.New("Exporting images is not supported by the OCI protocol")
+}
+
+// getImage returns a previously resolved image by manifest digest or (partial) fingerprint, or resolves the given
+// image reference.
+func (r *ProtocolOCI) getImage(fingerprint string) (*ociImage, error) {
+	if ociDigestRegex.MatchString(fingerprint) {
+		r.mu.Lock()
+		img := r.images[fingerprint]
+		r.mu.Unlock()
+
+		if img == nil {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Unknown OCI image manifest %q", fingerprint)
+		}
+
+		return img, nil
+	}
+
+	r.mu.Lock()
+	var matches []*ociImage
+	for _, img := range r.images {
+		if img.info.Fingerprint != "" && strings.HasPrefix(img.info.Fingerprint, fingerprint) {
+			matches = append(matches, img)
+		}
+	}
+
+	r.mu.Unlock()
+
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("Ambiguous image fingerprint %q", fingerprint)
+	} else if len(matches) == 1 {
+		return matches[0], nil
+	}
+
+	return r.resolve(fingerprint)
+}
+
+// parseReference splits an image reference such as "alpine:3.20" or "app@sha256:<digest>" into the repository
+// and the tag or digest to fetch.
+func (r *ProtocolOCI) parseReference(reference string) (string, string, error) {
+	repository, ref, found := strings.Cut(reference, "@")
+	if !found {
+		ref = "latest"
+	}
+
+	// Drop the tag, if any, when a digest is provided.
+	colon := strings.LastIndex(repository, ":")
+	if colon > strings.LastIndex(repository, "/") {
+		if !found {
+			ref = repository[colon+1:]
+		}
+
+		repository = repository[:colon]
+	}
+
+	if r.namespace != "" {
+		repository = r.namespace + "/" + repository
+	} else if r.registryURL.Host == "registry-1.docker.io" && !strings.Contains(repository, "/") {
+		// Official images of the Docker Hub live in the "library" namespace.
+		repository = "library/" + repository
+	}
+
+	if !ociRepositoryRegex.MatchString(repository) {
+		return "", "", api.StatusErrorf(http.StatusNotFound, "Invalid OCI image reference %q", reference)
+	}
+
+	if !ociTagRegex.MatchString(ref) && !ociDigestRegex.MatchString(ref) {
+		return "", "", api.StatusErrorf(http.StatusNotFound, "Invalid tag or digest in OCI image reference %q", reference)
+	}
+
+	return repository, ref, nil
+}
+
+// resolve fetches the manifest and configuration of the image with the given reference. The image isn't converted,
+// so its fingerprint is only set if a previous conversion was recorded in the cache.
+func (r *ProtocolOCI) resolve(reference string) (*ociImage, error) {
+	repository, ref, err := r.parseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, digest, err := r.getManifest(repository, ref, true)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	img := r.images[digest]
+	r.mu.Unlock()
+
+	if img != nil {
+		return img, nil
+	}
+
+	// Get the image configuration.
+	var buf bytes.Buffer
+	err = r.getBlob(repository, manifest.Config, &buf, ociManifestMaxSize, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting OCI image configuration: %w", err)
+	}
+
+	var config ociImageConfig
+	err = json.Unmarshal(buf.Bytes(), &config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing OCI image configuration: %w", err)
+	}
+
+	if config.OS != "" && config.OS != "linux" {
+		return nil, fmt.Errorf("Unsupported OCI image operating system %q", config.OS)
+	}
+
+	architecture, err := ociArchitectureName(config.Architecture, config.Variant)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := time.Unix(0, 0).UTC()
+	if config.Created != nil {
+		createdAt = config.Created.UTC().Truncate(time.Second)
+	}
+
+	img = &ociImage{
+		repository: repository,
+		digest:     digest,
+		manifest:   *manifest,
+		info: api.Image{
+			Aliases:      []api.ImageAlias{{Name: reference}},
+			Architecture: architecture,
+			Public:       true,
+			Filename:     strings.ReplaceAll(repository, "/", "_") + ".tar",
+			Type:         "container",
+			CreatedAt:    createdAt,
+			UploadedAt:   createdAt,
+			Properties:   ociImageProperties(repository, ref, architecture, config),
+		},
+	}
+
+	// Use the outcome of a previous conversion if available.
+	conversion, err := r.loadConversion(img)
+	if err != nil {
+		return nil, err
+	}
+
+	if conversion != nil {
+		img.info.Fingerprint = conversion.Fingerprint
+		img.info.Size = conversion.Size
+	}
+
+	r.mu.Lock()
+	r.images[digest] = img
+	r.mu.Unlock()
+
+	return img, nil
+}
+
+// getManifest fetches the manifest with the given tag or digest and returns it along with its digest. If the
+// manifest is an image index, the manifest matching the local architecture is returned when selectPlatform is true.
+func (r *ProtocolOCI) getManifest(repository string, ref string, selectPlatform bool) (*ociManifest, string, error) {
+	accept := []string{ociMediaTypeManifest, ociMediaTypeIndex, dockerMediaTypeManifest, dockerMediaTypeManifestList}
+
+	resp, err := r.doRegistry(repository, "manifests/"+ref, accept)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, ociManifestMaxSize))
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed reading OCI image manifest: %w", err)
+	}
+
+	hash := sha256.Sum256(content)
+	digest := "sha256:" + hex.EncodeToString(hash[:])
+	if strings.HasPrefix(ref, "sha256:") && ref != digest {
+		return nil, "", fmt.Errorf("OCI image manifest digest mismatch. Got %s expected %s", digest, ref)
+	}
+
+	var manifest ociManifest
+	err = json.Unmarshal(content, &manifest)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed parsing OCI image manifest: %w", err)
+	}
+
+	if manifest.MediaType == "" {
+		manifest.MediaType, _, _ = mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	}
+
+	switch manifest.MediaType {
+	case ociMediaTypeManifest, dockerMediaTypeManifest:
+		return &manifest, digest, nil
+	case ociMediaTypeIndex, dockerMediaTypeManifestList:
+		if !selectPlatform {
+			return nil, "", errors.New("Nested OCI image indexes are not supported")
+		}
+
+		localArchitecture, err := osarch.ArchitectureGetLocal()
+		if err != nil {
+			return nil, "", err
+		}
+
+		for _, entry := range manifest.Manifests {
+			if entry.Platform == nil || entry.Platform.OS != "linux" {
+				continue
+			}
+
+			architecture, err := ociArchitectureName(entry.Platform.Architecture, entry.Platform.Variant)
+			if err != nil || architecture != localArchitecture {
+				continue
+			}
+
+			return r.getManifest(repository, entry.Digest, false)
+		}
+
+		return nil, "", api.StatusErrorf(http.StatusNotFound, "No OCI image available for architecture %q", localArchitecture)
+	default:
+		return nil, "", fmt.Errorf("Unsupported OCI image manifest type %q", manifest.MediaType)
+	}
+}
+
+// getBlob downloads the blob with the given descriptor to the target, verifying its digest. At most maxSize bytes
+// are read if maxSize is positive.
+func (r *ProtocolOCI) getBlob(repository string, desc ociDescriptor, target io.Writer, maxSize int64, progress func(ioprogress.ProgressData), description string) error {
+	if !ociDigestRegex.MatchString(desc.Digest) {
+		return fmt.Errorf("Unsupported OCI blob digest %q", desc.Digest)
+	}
+
+	resp, err := r.doRegistry(repository, "blobs/"+desc.Digest, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var body io.Reader = ioprogress.NewProgressReader(resp.Body, ioprogress.WithLength(desc.Size), ioprogress.WithDescriptiveProgressHandler(description, progress))
+	if maxSize > 0 {
+		body = io.LimitReader(body, maxSize)
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(target, hash), body)
+	if err != nil {
+		return err
+	}
+
+	digest := "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	if digest != desc.Digest {
+		return fmt.Errorf("OCI blob digest mismatch. Got %s expected %s", digest, desc.Digest)
+	}
+
+	return nil
+}
+
+// getImagesPath returns the directory holding the converted images, creating a temporary one if no cache is used.
+func (r *ProtocolOCI) getImagesPath() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.imagesPath != "" {
+		return r.imagesPath, nil
+	}
+
+	tempPath, err := os.MkdirTemp("", "lxd_oci_")
+	if err != nil {
+		return "", err
+	}
+
+	r.tempPath = tempPath
+	r.imagesPath = tempPath
+
+	return r.imagesPath, nil
+}
+
+// getImagePath returns the path of the converted image.
+func (r *ProtocolOCI) getImagePath(img *ociImage) (string, error) {
+	imagesPath, err := r.getImagesPath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(imagesPath, strings.TrimPrefix(img.digest, "sha256:")+".tar"), nil
+}
+
+// loadConversion returns the recorded outcome of a previous conversion of the image, if any. The record is marked
+// as used, so that it isn't evicted from the cache while the image is in use.
+func (r *ProtocolOCI) loadConversion(img *ociImage) (*ociConversion, error) {
+	imagePath, err := r.getImagePath(img)
+	if err != nil {
+		return nil, err
+	}
+
+	recordPath := strings.TrimSuffix(imagePath, ".tar") + ".json"
+	content, err := os.ReadFile(recordPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(recordPath, now, now)
+
+	var conversion ociConversion
+	err = json.Unmarshal(content, &conversion)
+	if err != nil || conversion.Fingerprint == "" {
+		// Ignore broken records, the image is converted again.
+		return nil, nil
+	}
+
+	return &conversion, nil
+}
+
+// convert downloads the layers of the image and merges them into a unified LXD image, recording its fingerprint.
+func (r *ProtocolOCI) convert(img *ociImage, progress func(ioprogress.ProgressData)) error {
+	imagePath, err := r.getImagePath(img)
+	if err != nil {
+		return err
+	}
+
+	// Layer contents are spooled to a file while merging the layers.
+	spool, err := os.CreateTemp(filepath.Dir(imagePath), "spool_")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+
+	rootfs := newOCIRootfs(spool)
+	for i, layer := range img.manifest.Layers {
+		description := fmt.Sprintf("layer %d/%d", i+1, len(img.manifest.Layers))
+
+		err := r.applyLayer(img.repository, layer, rootfs, progress, description)
+		if err != nil {
+			return fmt.Errorf("Failed applying OCI image %s: %w", description, err)
+		}
+	}
+
+	metadata, err := yaml.Marshal(api.ImageMetadata{
+		Architecture: img.info.Architecture,
+		CreationDate: img.info.CreatedAt.Unix(),
+		Properties:   img.info.Properties,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Write the image to a temporary file and move it in place once complete.
+	f, err := os.CreateTemp(filepath.Dir(imagePath), "image_")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	hash := sha256.New()
+	w := bufio.NewWriter(io.MultiWriter(f, hash))
+
+	err = rootfs.write(w, metadata, img.info.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("Failed writing image: %w", err)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(f.Name(), imagePath)
+	if err != nil {
+		return err
+	}
+
+	conversion := ociConversion{
+		Fingerprint: hex.EncodeToString(hash.Sum(nil)),
+		Size:        size,
+	}
+
+	content, err := json.Marshal(conversion)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(strings.TrimSuffix(imagePath, ".tar")+".json", content, 0600)
+	if err != nil {
+		return err
+	}
+
+	img.info.Fingerprint = conversion.Fingerprint
+	img.info.Size = conversion.Size
+
+	return nil
+}
+
+// applyLayer downloads a layer and applies it on top of the rootfs.
+func (r *ProtocolOCI) applyLayer(repository string, layer ociDescriptor, rootfs *ociRootfs, progress func(ioprogress.ProgressData), description string) error {
+	blob, err := os.CreateTemp(filepath.Dir(rootfs.spool.Name()), "layer_")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = blob.Close()
+		_ = os.Remove(blob.Name())
+	}()
+
+	err = r.getBlob(repository, layer, blob, -1, progress, description)
+	if err != nil {
+		return err
+	}
+
+	_, err = blob.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	// Detect the compression from the content, as registries are not consistent about layer media types.
+	reader := bufio.NewReader(blob)
+	magic, _ := reader.Peek(4)
+
+	var layerReader io.Reader = reader
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = gzipReader.Close() }()
+
+		layerReader = gzipReader
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return errors.New("Zstandard compressed layers are not supported")
+	}
+
+	return rootfs.apply(tar.NewReader(layerReader))
+}
+
+// ociArchitectureName returns the LXD architecture name of the given OCI architecture and variant.
+func ociArchitectureName(architecture string, variant string) (string, error) {
+	if architecture == "arm" && variant != "" && variant != "v6" {
+		architecture = "armv7l"
+	}
+
+	id, err := osarch.ArchitectureId(architecture)
+	if err != nil {
+		return "", err
+	}
+
+	return osarch.ArchitectureName(id)
+}
+
+// ociImageProperties returns the LXD image properties describing the OCI image.
+// The runtime configuration of the image is recorded in the oci.* properties.
+func ociImageProperties(repository string, ref string, architecture string, config ociImageConfig) map[string]string {
+	properties := map[string]string{
+		"architecture": architecture,
+		"description":  repository + " (" + ref + ")",
+		"os":           repository,
+		"release":      ref,
+	}
+
+	command := append(append([]string{}, config.Config.Entrypoint...), config.Config.Cmd...)
+	if len(command) > 0 {
+		properties["oci.entrypoint"] = shellquote.Join(command...)
+	}
+
+	if config.Config.WorkingDir != "" {
+		properties["oci.cwd"] = config.Config.WorkingDir
+	}
+
+	for _, env := range config.Config.Env {
+		key, value, found := strings.Cut(env, "=")
+		if found && key != "" {
+			properties["oci.env."+key] = value
+		}
+	}
+
+	return properties
+}
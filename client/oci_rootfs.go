@@ -0,0 +1,238 @@
+package lxd
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// OCI layers mark removed files with whiteout files. An opaque whiteout hides all lower entries of its directory.
+const (
+	ociWhiteoutPrefix = ".wh."
+	ociWhiteoutOpaque = ".wh..wh..opq"
+)
+
+// ociRootfsEntry is an entry of the merged rootfs. The content of regular files is kept in the spool file.
+type ociRootfsEntry struct {
+	header *tar.Header
+	offset int64
+}
+
+// ociRootfs merges the layers of an OCI image into a single rootfs.
+type ociRootfs struct {
+	spool     *os.File
+	spoolSize int64
+	entries   map[string]*ociRootfsEntry
+}
+
+// newOCIRootfs returns an empty rootfs using the given file to hold the content of regular files.
+func newOCIRootfs(spool *os.File) *ociRootfs {
+	return &ociRootfs{
+		spool:   spool,
+		entries: map[string]*ociRootfsEntry{},
+	}
+}
+
+// ociCleanPath returns the path relative to the rootfs of a layer entry, or an empty string for the rootfs itself.
+func ociCleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// apply applies a layer on top of the rootfs. Whiteouts only apply to the lower layers, so they are processed before
+// adding the entries of the layer.
+func (r *ociRootfs) apply(tr *tar.Reader) error {
+	var added []*ociRootfsEntry
+	var opaqueDirs []string
+	var whiteouts []string
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		name := ociCleanPath(hdr.Name)
+		dir, base := path.Split(name)
+		dir = strings.TrimSuffix(dir, "/")
+
+		if base == ociWhiteoutOpaque {
+			opaqueDirs = append(opaqueDirs, dir)
+			continue
+		} else if strings.HasPrefix(base, ociWhiteoutPrefix) {
+			whiteouts = append(whiteouts, path.Join(dir, strings.TrimPrefix(base, ociWhiteoutPrefix)))
+			continue
+		}
+
+		if name == "" {
+			continue
+		}
+
+		entry := &ociRootfsEntry{header: hdr}
+		hdr.Name = name
+
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			entry.offset = r.spoolSize
+
+			n, err := io.Copy(r.spool, tr)
+			if err != nil {
+				return err
+			}
+
+			r.spoolSize += n
+		case tar.TypeLink:
+			hdr.Linkname = ociCleanPath(hdr.Linkname)
+		case tar.TypeDir, tar.TypeSymlink, tar.TypeChar, tar.TypeBlock, tar.TypeFifo:
+		default:
+			// Skip unsupported entries.
+			continue
+		}
+
+		added = append(added, entry)
+	}
+
+	for _, dir := range opaqueDirs {
+		r.removeChildren(dir)
+	}
+
+	for _, name := range whiteouts {
+		delete(r.entries, name)
+		r.removeChildren(name)
+	}
+
+	for _, entry := range added {
+		name := entry.header.Name
+
+		// A directory replaced by another type of entry loses its content.
+		existing := r.entries[name]
+		if existing != nil && existing.header.Typeflag == tar.TypeDir && entry.header.Typeflag != tar.TypeDir {
+			r.removeChildren(name)
+		}
+
+		r.entries[name] = entry
+	}
+
+	return nil
+}
+
+// removeChildren removes the entries below the given directory (all entries for the rootfs itself).
+func (r *ociRootfs) removeChildren(dir string) {
+	for name := range r.entries {
+		if dir == "" || strings.HasPrefix(name, dir+"/") {
+			delete(r.entries, name)
+		}
+	}
+}
+
+// write writes the rootfs as a unified LXD image, with the given metadata.yaml content. The output only depends on
+// the content of the rootfs, so that converting the same image again yields the same fingerprint. Entries are
+// written in path order, with hard links last so that their targets already exist when unpacking.
+func (r *ociRootfs) write(w io.Writer, metadata []byte, createdAt time.Time) error {
+	// Add the parent directories missing from the layers.
+	for _, name := range slices.Collect(maps.Keys(r.entries)) {
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			if r.entries[dir] != nil {
+				continue
+			}
+
+			r.entries[dir] = &ociRootfsEntry{header: &tar.Header{Name: dir, Typeflag: tar.TypeDir, Mode: 0755, ModTime: createdAt}}
+		}
+	}
+
+	names := make([]string, 0, len(r.entries))
+	var links []string
+	for name, entry := range r.entries {
+		if entry.header.Typeflag == tar.TypeLink {
+			if r.entries[entry.header.Linkname] == nil {
+				// Skip hard links whose target was removed.
+				continue
+			}
+
+			links = append(links, name)
+		} else {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+	slices.Sort(links)
+
+	tw := tar.NewWriter(w)
+
+	err := tw.WriteHeader(&tar.Header{Name: "metadata.yaml", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(metadata)), ModTime: createdAt})
+	if err != nil {
+		return err
+	}
+
+	_, err = tw.Write(metadata)
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{Name: "rootfs/", Typeflag: tar.TypeDir, Mode: 0755, ModTime: createdAt})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range append(names, links...) {
+		entry := r.entries[name]
+		src := entry.header
+
+		// Only keep the attributes relevant to the rootfs, using numeric ownership.
+		hdr := &tar.Header{
+			Name:     "rootfs/" + name,
+			Typeflag: src.Typeflag,
+			Mode:     src.Mode,
+			Uid:      src.Uid,
+			Gid:      src.Gid,
+			ModTime:  src.ModTime.Truncate(time.Second),
+			Devmajor: src.Devmajor,
+			Devminor: src.Devminor,
+		}
+
+		switch src.Typeflag {
+		case tar.TypeDir:
+			hdr.Name += "/"
+		case tar.TypeReg:
+			hdr.Size = src.Size
+		case tar.TypeSymlink:
+			hdr.Linkname = src.Linkname
+		case tar.TypeLink:
+			hdr.Linkname = "rootfs/" + src.Linkname
+		}
+
+		// Keep extended attributes such as file capabilities.
+		for key, value := range src.PAXRecords {
+			if strings.HasPrefix(key, "SCHILY.xattr.") {
+				if hdr.PAXRecords == nil {
+					hdr.PAXRecords = map[string]string{}
+					hdr.Format = tar.FormatPAX
+				}
+
+				hdr.PAXRecords[key] = value
+			}
+		}
+
+		err := tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+
+		if hdr.Size > 0 {
+			_, err = io.Copy(tw, io.NewSectionReader(r.spool, entry.offset, hdr.Size))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tw.Close()
+}
@@ -0,0 +1,832 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+	"github.com/canonical/lxd/shared/termios"
+	"github.com/canonical/lxd/shared/units"
+)
+
+type cmdBackupTarget struct {
+	global *cmdGlobal
+}
+
+func (c *cmdBackupTarget) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("backup-target")
+	cmd.Short = "Manage backup targets"
+	cmd.Long = cli.FormatSection("Description", `Manage backup targets
+
+Backup targets are S3 compatible storage locations that instance and custom volume backups can be uploaded to
+with "lxc export --backup-target" and "lxc storage volume export --backup-target".`)
+
+	// Create
+	backupTargetCreateCmd := cmdBackupTargetCreate{global: c.global}
+	cmd.AddCommand(backupTargetCreateCmd.command())
+
+	// List
+	backupTargetListCmd := cmdBackupTargetList{global: c.global}
+	cmd.AddCommand(backupTargetListCmd.command())
+
+	// List backups
+	backupTargetListBackupsCmd := cmdBackupTargetListBackups{global: c.global}
+	cmd.AddCommand(backupTargetListBackupsCmd.command())
+
+	// Delete
+	backupTargetDeleteCmd := cmdBackupTargetDelete{global: c.global}
+	cmd.AddCommand(backupTargetDeleteCmd.command())
+
+	// Edit
+	backupTargetEditCmd := cmdBackupTargetEdit{global: c.global}
+	cmd.AddCommand(backupTargetEditCmd.command())
+
+	// Show
+	backupTargetShowCmd := cmdBackupTargetShow{global: c.global}
+	cmd.AddCommand(backupTargetShowCmd.command())
+
+	// Get
+	backupTargetGetCmd := cmdBackupTargetGet{global: c.global}
+	cmd.AddCommand(backupTargetGetCmd.command())
+
+	// Set
+	backupTargetSetCmd := cmdBackupTargetSet{global: c.global}
+	cmd.AddCommand(backupTargetSetCmd.command())
+
+	// Unset
+	backupTargetUnsetCmd := cmdBackupTargetUnset{global: c.global, backupTargetSet: &backupTargetSetCmd}
+	cmd.AddCommand(backupTargetUnsetCmd.command())
+
+	// Rename
+	backupTargetRenameCmd := cmdBackupTargetRename{global: c.global}
+	cmd.AddCommand(backupTargetRenameCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdBackupTargetCreate struct {
+	global *cmdGlobal
+
+	flagDescription string
+}
+
+func (c *cmdBackupTargetCreate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", "[<remote>:]<backup target> [key=value...]")
+	cmd.Short = "Create backup targets"
+	cmd.Long = cli.FormatSection("Description", `Create backup targets`)
+	cmd.Example = cli.FormatSection("", `lxc backup-target create s3-backups s3.endpoint=https://s3.example.com s3.bucket=backups s3.access_key=KEY s3.secret_key=SECRET
+    Create a backup target called "s3-backups" storing backups in the "backups" bucket.
+
+lxc backup-target create s3-backups < config.yaml
+    Create a backup target called "s3-backups" with the configuration from config.yaml.`)
+
+	cmd.Flags().StringVarP(&c.flagDescription, "description", "d", "", cli.FormatStringFlagLabel("Backup target description"))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, ":", true, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdBackupTargetCreate) run(cmd *cobra.Command, args []string) error {
+	var stdinData api.BackupTargetPut
+
+	// Quick checks
+	exit, err := c.global.CheckArgs(cmd, args, 1, -1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.Unmarshal(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing backup target name")
+	}
+
+	target := api.BackupTargetsPost{
+		Name:            resource.name,
+		BackupTargetPut: stdinData,
+	}
+
+	if c.flagDescription != "" {
+		target.Description = c.flagDescription
+	}
+
+	if target.Config == nil {
+		target.Config = map[string]string{}
+	}
+
+	for i := 1; i < len(args); i++ {
+		entry := strings.SplitN(args[i], "=", 2)
+		if len(entry) < 2 {
+			return fmt.Errorf("Bad key=value pair: %s", args[i])
+		}
+
+		target.Config[entry[0]] = entry[1]
+	}
+
+	err = resource.server.CreateBackupTarget(target)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Backup target %s created\n", resource.name)
+	}
+
+	return nil
+}
+
+// List.
+type cmdBackupTargetList struct {
+	global *cmdGlobal
+
+	flagFormat string
+}
+
+func (c *cmdBackupTargetList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", "[<remote>:]")
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = "List backup targets"
+	cmd.Long = cli.FormatSection("Description", `List backup targets`)
+
+	cmd.RunE = c.run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, ":", true, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdBackupTargetList) run(cmd *cobra.Command, args []string) error {
+	// Quick checks
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	targets, err := resource.server.GetBackupTargets()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, target := range targets {
+		details := []string{
+			target.Name,
+			target.Config["s3.endpoint"],
+			target.Config["s3.bucket"],
+			target.Description,
+		}
+
+		data = append(data, details)
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		"NAME",
+		"ENDPOINT",
+		"BUCKET",
+		"DESCRIPTION",
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, targets)
+}
+
+// List backups.
+type cmdBackupTargetListBackups struct {
+	global *cmdGlobal
+
+	flagFormat string
+}
+
+func (c *cmdBackupTargetListBackups) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list-backups", "[<remote>:]<backup target>")
+	cmd.Short = "List the backups stored on backup targets"
+	cmd.Long = cli.FormatSection("Description", `List the backups of the current project stored on backup targets
+
+The listed keys can be used with "lxc import --backup-target" and "lxc storage volume import --backup-target".`)
+
+	cmd.RunE = c.run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("backup_target", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdBackupTargetListBackups) run(cmd *cobra.Command, args []string) error {
+	// Quick checks
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing backup target name")
+	}
+
+	backups, err := resource.server.GetBackupTargetBackups(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, backup := range backups {
+		details := []string{
+			backup.Key,
+			units.GetByteSizeStringIEC(backup.Size, 2),
+			backup.CreatedAt.UTC().Format("2006/01/02 15:04 MST"),
+		}
+
+		data = append(data, details)
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		"KEY",
+		"SIZE",
+		"CREATED AT",
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, backups)
+}
+
+// Delete.
+type cmdBackupTargetDelete struct {
+	global *cmdGlobal
+}
+
+func (c *cmdBackupTargetDelete) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", "[<remote>:]<backup target>")
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = "Delete backup targets"
+	cmd.Long = cli.FormatSection("Description", `Delete backup targets
+
+The backups stored on the backup target are left untouched.`)
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("backup_target", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdBackupTargetDelete) run(cmd *cobra.Command, args []string) error {
+	// Quick checks
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing backup target name")
+	}
+
+	err = resource.server.DeleteBackupTarget(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Backup target %s deleted\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdBackupTargetEdit struct {
+	global *cmdGlobal
+}
+
+func (c *cmdBackupTargetEdit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", "[<remote>:]<backup target>")
+	cmd.Short = "Edit backup target configurations as YAML"
+	cmd.Long = cli.FormatSection("Description", `Edit backup target configurations as YAML`)
+	cmd.Example = cli.FormatSection("", `lxc backup-target edit <backup target> < target.yaml
+    Update a backup target using the content of target.yaml.`)
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("backup_target", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdBackupTargetEdit) helpTemplate() string {
+	return `### This is a YAML representation of a backup target.
+### Any line starting with a '#' will be ignored.
+###
+### A backup target consists of a set of configuration items.
+###
+### An example would look like:
+### description: Off-site backups
+### config:
+###   s3.endpoint: https://s3.example.com
+###   s3.bucket: backups
+###   s3.access_key: KEY
+###   s3.secret_key: SECRET
+###   `
+}
+
+func (c *cmdBackupTargetEdit) run(cmd *cobra.Command, args []string) error {
+	// Quick checks
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing backup target name")
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.BackupTargetPut{}
+		err = yaml.Unmarshal(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateBackupTarget(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	target, etag, err := resource.server.GetBackupTarget(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(target.Writable())
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := shared.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.BackupTargetPut{}
+		err = yaml.Unmarshal(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateBackupTarget(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Config parsing error: %s\n", err)
+			fmt.Println("Press enter to open the editor again or ctrl+c to abort change")
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = shared.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Show.
+type cmdBackupTargetShow struct {
+	global *cmdGlobal
+}
+
+func (c *cmdBackupTargetShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", "[<remote>:]<backup target>")
+	cmd.Short = "Show backup target configurations"
+	cmd.Long = cli.FormatSection("Description", `Show backup target configurations`)
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("backup_target", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdBackupTargetShow) run(cmd *cobra.Command, args []string) error {
+	// Quick checks
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing backup target name")
+	}
+
+	target, _, err := resource.server.GetBackupTarget(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&target)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Get.
+type cmdBackupTargetGet struct {
+	global *cmdGlobal
+
+	flagIsProperty bool
+}
+
+func (c *cmdBackupTargetGet) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("get", "[<remote>:]<backup target> <key>")
+	cmd.Short = "Get values for backup target configuration keys"
+	cmd.Long = cli.FormatSection("Description", `Get values for backup target configuration keys`)
+
+	cmd.RunE = c.run
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, "Get the key as a backup target property")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("backup_target", toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpBackupTargetConfig(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdBackupTargetGet) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing backup target name")
+	}
+
+	target, _, err := resource.server.GetBackupTarget(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if c.flagIsProperty {
+		w := target.Writable()
+		res, err := getFieldByJSONTag(&w, args[1])
+		if err != nil {
+			return fmt.Errorf("The property %q does not exist on the backup target %q: %v", args[1], resource.name, err)
+		}
+
+		fmt.Printf("%v\n", res)
+	} else {
+		fmt.Printf("%s\n", target.Config[args[1]])
+	}
+
+	return nil
+}
+
+// Set.
+type cmdBackupTargetSet struct {
+	global *cmdGlobal
+
+	flagIsProperty bool
+}
+
+func (c *cmdBackupTargetSet) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("set", "[<remote>:]<backup target> <key>=<value>...")
+	cmd.Short = "Set backup target configuration keys"
+	cmd.Long = cli.FormatSection("Description", `Set backup target configuration keys`)
+
+	cmd.RunE = c.run
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, "Set the key as a backup target property")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("backup_target", toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpBackupTargetConfig(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdBackupTargetSet) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing backup target name")
+	}
+
+	// Get the backup target
+	target, etag, err := resource.server.GetBackupTarget(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Set the configuration key
+	keys, err := getConfig(args[1:]...)
+	if err != nil {
+		return err
+	}
+
+	writable := target.Writable()
+	if c.flagIsProperty {
+		if cmd.Name() == "unset" {
+			for k := range keys {
+				err := unsetFieldByJSONTag(&writable, k)
+				if err != nil {
+					return fmt.Errorf("Error unsetting property: %v", err)
+				}
+			}
+		} else {
+			err := unpackKVToWritable(&writable, keys)
+			if err != nil {
+				return fmt.Errorf("Error setting properties: %v", err)
+			}
+		}
+	} else {
+		maps.Copy(writable.Config, keys)
+	}
+
+	return resource.server.UpdateBackupTarget(resource.name, writable, etag)
+}
+
+// Unset.
+type cmdBackupTargetUnset struct {
+	global          *cmdGlobal
+	backupTargetSet *cmdBackupTargetSet
+
+	flagIsProperty bool
+}
+
+func (c *cmdBackupTargetUnset) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("unset", "[<remote>:]<backup target> <key>")
+	cmd.Short = "Unset backup target configuration keys"
+	cmd.Long = cli.FormatSection("Description", `Unset backup target configuration keys`)
+
+	cmd.RunE = c.run
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, "Unset the key as a backup target property")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("backup_target", toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpBackupTargetConfig(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdBackupTargetUnset) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	c.backupTargetSet.flagIsProperty = c.flagIsProperty
+
+	args = append(args, "")
+	return c.backupTargetSet.run(cmd, args)
+}
+
+// Rename.
+type cmdBackupTargetRename struct {
+	global *cmdGlobal
+}
+
+func (c *cmdBackupTargetRename) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rename", "[<remote>:]<backup target> <new-name>")
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = "Rename backup targets"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("backup_target", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdBackupTargetRename) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing backup target name")
+	}
+
+	err = resource.server.RenameBackupTarget(resource.name, api.BackupTargetPost{Name: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Backup target %s renamed to %s\n", resource.name, args[1])
+	}
+
+	return nil
+}
@@ -76,6 +76,9 @@ var topLevelInstanceServerResourceNameFuncs = map[string]func(server lxd.Instanc
 	"network_acl": func(server lxd.InstanceServer) ([]string, error) {
 		return server.GetNetworkACLNames()
 	},
+	"network_address_set": func(server lxd.InstanceServer) ([]string, error) {
+		return server.GetNetworkAddressSetNames()
+	},
 	"network_zone": func(server lxd.InstanceServer) ([]string, error) {
 		return server.GetNetworkZoneNames()
 	},
@@ -100,6 +103,9 @@ var topLevelInstanceServerResourceNameFuncs = map[string]func(server lxd.Instanc
 	"cluster_link": func(server lxd.InstanceServer) ([]string, error) {
 		return server.GetClusterLinkNames()
 	},
+	"backup_target": func(server lxd.InstanceServer) ([]string, error) {
+		return server.GetBackupTargetNames()
+	},
 	"replicator": func(server lxd.InstanceServer) ([]string, error) {
 		return server.GetReplicatorNames()
 	},
@@ -329,6 +335,29 @@ func (g *cmdGlobal) cmpClusterLinkConfig(linkName string) ([]string, cobra.Shell
 	return results, cobra.ShellCompDirectiveNoFileComp
 }
 
+// cmpBackupTargetConfig provides shell completion for backup target configs.
+// It takes a backup target name and returns a list of backup target config keys along with a shell completion directive.
+func (g *cmdGlobal) cmpBackupTargetConfig(targetName string) ([]string, cobra.ShellCompDirective) {
+	// Parse remote
+	resources, err := g.ParseServers(targetName)
+	if err != nil || len(resources) == 0 {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	resource := resources[0]
+	target, _, err := resource.server.GetBackupTarget(resource.name)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	results := make([]string, 0, len(target.Config))
+	for k := range target.Config {
+		results = append(results, k)
+	}
+
+	return results, cobra.ShellCompDirectiveNoFileComp
+}
+
 // cmpReplicatorConfig provides shell completion for replicator configs.
 // It takes a replicator name and returns a list of replicator config keys along with a shell completion directive.
 func (g *cmdGlobal) cmpReplicatorConfig(replicatorName string) ([]string, cobra.ShellCompDirective) {
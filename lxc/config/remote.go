@@ -162,7 +162,7 @@ func (c *Config) getPrivateRemoteByName(name string) (*Remote, error) {
 	}
 
 	// Check the remote is private.
-	if remote.Public || remote.Protocol == "simplestreams" {
+	if remote.Public || remote.Protocol == "simplestreams" || remote.Protocol == "oci" {
 		return nil, errors.New("The remote is not a private LXD server")
 	}
 
@@ -285,6 +285,16 @@ func (c *Config) GetImageServer(name string) (lxd.ImageServer, error) {
 		return d, nil
 	}
 
+	// HTTPs (OCI registry)
+	if remote.Protocol == "oci" {
+		d, err := lxd.ConnectOCI(remote.Addr, args)
+		if err != nil {
+			return nil, err
+		}
+
+		return d, nil
+	}
+
 	// HTTPs (public LXD)
 	if remote.Public {
 		d, err := lxd.ConnectPublicLXD(remote.Addr, args)
@@ -396,7 +406,7 @@ func (c *Config) getConnectionArgs(name string) (*lxd.ConnectionArgs, error) {
 	}
 
 	// Stop here if no client certificate involved
-	if remote.Protocol == "simplestreams" || slices.Contains([]string{api.AuthenticationMethodOIDC}, remote.AuthType) {
+	if slices.Contains([]string{"simplestreams", "oci"}, remote.Protocol) || slices.Contains([]string{api.AuthenticationMethodOIDC}, remote.AuthType) {
 		return &args, nil
 	}
 
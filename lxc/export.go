@@ -22,15 +22,23 @@ type cmdExport struct {
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
 	flagExportVersion        string
+	flagBackupTarget         string
+	flagParentSnapshot       string
 }
 
 func (c *cmdExport) command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("export", "[<remote>:]<instance> [target] [--instance-only] [--optimized-storage]")
+	cmd.Use = usage("export", "[<remote>:]<instance> [target] [--instance-only] [--optimized-storage] [--backup-target <backup target>] [--parent-snapshot <snapshot>]")
 	cmd.Short = "Export instance backups"
 	cmd.Long = cli.FormatSection("Description", `Export instances as backup tarballs.`)
 	cmd.Example = cli.FormatSection("", `lxc export u1 backup0.tar.gz
-    Download a backup tarball of the u1 instance.`)
+    Download a backup tarball of the u1 instance.
+
+lxc export u1 backup0 --backup-target s3-backups
+    Upload a backup of the u1 instance named backup0 to the s3-backups backup target.
+
+lxc export u1 backup1.tar.gz --optimized-storage --parent-snapshot snap0
+    Download an incremental backup of the u1 instance that only contains the changes made since its snap0 snapshot.`)
 
 	cmd.RunE = c.run
 	cmd.Flags().BoolVar(&c.flagInstanceOnly, "instance-only", false,
@@ -39,6 +47,8 @@ func (c *cmdExport) command() *cobra.Command {
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", cli.FormatStringFlagLabel(`Compression algorithm to use (none for uncompressed)`))
 	cmd.Flags().StringVar(&c.flagExportVersion, "export-version", "",
 		cli.FormatStringFlagLabel("Use a different metadata format version than the latest one supported by the server (to support imports on older LXD versions)"))
+	cmd.Flags().StringVar(&c.flagBackupTarget, "backup-target", "", cli.FormatStringFlagLabel("Upload the backup to this backup target instead of downloading it (the target argument is then the backup name)"))
+	cmd.Flags().StringVar(&c.flagParentSnapshot, "parent-snapshot", "", cli.FormatStringFlagLabel("Only include the changes made since this snapshot (requires --optimized-storage)"))
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
 		if len(args) > 0 {
@@ -80,6 +90,7 @@ func (c *cmdExport) run(cmd *cobra.Command, args []string) error {
 		InstanceOnly:         instanceOnly,
 		OptimizedStorage:     c.flagOptimizedStorage,
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		ParentSnapshot:       c.flagParentSnapshot,
 	}
 
 	req.Version, err = getExportVersion(d, c.flagExportVersion)
@@ -87,6 +98,15 @@ func (c *cmdExport) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if c.flagBackupTarget != "" {
+		req.Target = c.flagBackupTarget
+		if len(args) > 1 {
+			req.Name = args[1]
+		}
+
+		return c.exportToBackupTarget(d, name, req)
+	}
+
 	op, err := d.CreateInstanceBackup(name, req)
 	if err != nil {
 		return fmt.Errorf("Create instance backup: %w", err)
@@ -212,3 +232,33 @@ func (c *cmdExport) run(cmd *cobra.Command, args []string) error {
 	exportProgress.Done("Backup exported successfully!")
 	return nil
 }
+
+// exportToBackupTarget creates an instance backup that the server uploads directly to a backup target.
+func (c *cmdExport) exportToBackupTarget(d lxd.InstanceServer, name string, req api.InstanceBackupsPost) error {
+	op, err := d.CreateInstanceBackup(name, req)
+	if err != nil {
+		return fmt.Errorf("Create instance backup: %w", err)
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: "Backing up instance: %s",
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	key, _ := op.Get().Metadata["backup_key"].(string)
+	progress.Done(fmt.Sprintf("Backup uploaded to backup target %q as %q", req.Target, key))
+
+	return nil
+}
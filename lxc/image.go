@@ -1075,6 +1075,16 @@ func (c *cmdImageInfo) run(cmd *cobra.Command, args []string) error {
 		fmt.Printf("    Alias: %s\n", info.UpdateSource.Alias)
 	}
 
+	if info.Signature != nil {
+		fmt.Println("Signature:")
+		fmt.Printf("    Type: %s\n", info.Signature.Type)
+		fmt.Printf("    Status: %s\n", info.Signature.Status)
+
+		if info.Signature.KeyFingerprint != "" {
+			fmt.Printf("    Key fingerprint: %s\n", info.Signature.KeyFingerprint)
+		}
+	}
+
 	if len(info.Profiles) == 0 {
 		fmt.Print("Profiles: []\n")
 	} else {
@@ -15,21 +15,31 @@ import (
 type cmdImport struct {
 	global *cmdGlobal
 
-	flagStorage string
-	flagDevice  []string
+	flagStorage      string
+	flagDevice       []string
+	flagBackupTarget string
+	flagParent       []string
 }
 
 func (c *cmdImport) command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("import", "[<remote>:] <backup file> [<instance name>]")
+	cmd.Use = usage("import", "[<remote>:] <backup file>|<backup key> [<instance name>]")
 	cmd.Short = "Import instance backups"
 	cmd.Long = cli.FormatSection("Description", `Import backups of instances including their snapshots.`)
 	cmd.Example = cli.FormatSection("", `lxc import backup0.tar.gz
-    Create a new instance using backup0.tar.gz as the source.`)
+    Create a new instance using backup0.tar.gz as the source.
+
+lxc import instances/u1/backup0 u2 --backup-target s3-backups
+    Create a new instance named u2 from the instances/u1/backup0 backup stored on the s3-backups backup target.
+
+lxc import backup2.tar.gz --parent backup0.tar.gz --parent backup1.tar.gz
+    Create a new instance from the incremental backup2.tar.gz backup and its parent backups, oldest first.`)
 
 	cmd.RunE = c.run
 	cmd.Flags().StringVarP(&c.flagStorage, "storage", "s", "", cli.FormatStringFlagLabel("Storage pool name"))
 	cmd.Flags().StringArrayVarP(&c.flagDevice, "device", "d", nil, cli.FormatStringFlagLabel("New key/value to apply to a specific device"))
+	cmd.Flags().StringVar(&c.flagBackupTarget, "backup-target", "", cli.FormatStringFlagLabel("Import the backup with the given key from this backup target"))
+	cmd.Flags().StringArrayVar(&c.flagParent, "parent", nil, cli.FormatStringFlagLabel("Parent backup file (or key) of an incremental backup, oldest first (can be repeated)"))
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
 		if len(args) > 1 {
@@ -83,11 +93,12 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 
 	resource := resources[0]
 
+	// Backups stored on a backup target are read by the server directly.
 	var file *os.File
-	if srcFile == "-" {
+	if c.flagBackupTarget == "" && srcFile == "-" {
 		file = os.Stdin
 		c.global.flagQuiet = true
-	} else {
+	} else if c.flagBackupTarget == "" {
 		file, err = os.Open(shared.HostPathFollow(srcFile))
 		if err != nil {
 			return err
@@ -96,11 +107,6 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 		defer func() { _ = file.Close() }()
 	}
 
-	fstat, err := file.Stat()
-	if err != nil {
-		return err
-	}
-
 	progress := cli.ProgressRenderer{
 		Format: "Importing instance: %s",
 		Quiet:  c.global.flagQuiet,
@@ -114,10 +120,36 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 	}
 
 	createArgs := lxd.InstanceBackupArgs{
-		BackupFile: ioprogress.NewProgressReader(file, ioprogress.WithLength(fstat.Size()), ioprogress.WithProgressUpdater(&progress)),
-		PoolName:   c.flagStorage,
-		Name:       instanceName,
-		Devices:    deviceMap,
+		PoolName: c.flagStorage,
+		Name:     instanceName,
+		Devices:  deviceMap,
+	}
+
+	if file != nil {
+		fstat, err := file.Stat()
+		if err != nil {
+			return err
+		}
+
+		createArgs.BackupFile = ioprogress.NewProgressReader(file, ioprogress.WithLength(fstat.Size()), ioprogress.WithProgressUpdater(&progress))
+	} else {
+		createArgs.BackupTarget = c.flagBackupTarget
+		createArgs.BackupTargetKey = srcFile
+		createArgs.BackupTargetParentKeys = c.flagParent
+	}
+
+	// Parent backup files of incremental backups are uploaded ahead of the backup.
+	if file != nil {
+		for _, parent := range c.flagParent {
+			parentFile, err := os.Open(shared.HostPathFollow(parent))
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = parentFile.Close() }()
+
+			createArgs.ParentBackupFiles = append(createArgs.ParentBackupFiles, parentFile)
+		}
 	}
 
 	op, err := resource.server.CreateInstanceFromBackup(createArgs)
@@ -384,8 +384,13 @@ func (c *cmdInit) create(conf *config.Config, args []string, launch bool) (lxd.I
 						return nil, "", fmt.Errorf("Image registry %q not found", registryName)
 					}
 
-					req.Source.Server = remoteConfig.Addr                        //nolint:staticcheck
-					req.Source.Protocol = api.ImageRegistryProtocolSimpleStreams //nolint:staticcheck
+					protocol := remoteConfig.Protocol
+					if protocol == "" {
+						protocol = api.ImageRegistryProtocolLXD
+					}
+
+					req.Source.Server = remoteConfig.Addr //nolint:staticcheck
+					req.Source.Protocol = protocol        //nolint:staticcheck
 				} else {
 					// Registry exists on the server, use it directly.
 					req.Source.ImageRegistry = registryName
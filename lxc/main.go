@@ -148,6 +148,10 @@ For help with any of those, simply call them with --help.`)
 	aliasCmd := cmdAlias{global: &globalCmd}
 	app.AddCommand(aliasCmd.command())
 
+	// backup-target sub-command
+	backupTargetCmd := cmdBackupTarget{global: &globalCmd}
+	app.AddCommand(backupTargetCmd.command())
+
 	// cluster sub-command
 	clusterCmd := cmdCluster{global: &globalCmd}
 	app.AddCommand(clusterCmd.command())
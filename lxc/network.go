@@ -103,6 +103,10 @@ func (c *cmdNetwork) command() *cobra.Command {
 	networkACLCmd := cmdNetworkACL{global: c.global}
 	cmd.AddCommand(networkACLCmd.command())
 
+	// Address set
+	networkAddressSetCmd := cmdNetworkAddressSet{global: c.global}
+	cmd.AddCommand(networkAddressSetCmd.command())
+
 	// Forward
 	networkForwardCmd := cmdNetworkForward{global: c.global}
 	cmd.AddCommand(networkForwardCmd.command())
@@ -1005,6 +1009,37 @@ func (c *cmdNetworkInfo) run(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Chassis: %s\n", state.OVN.Chassis)
 	}
 
+	// WireGuard information.
+	if state.WireGuard != nil {
+		const layout = "2006/01/02 15:04 MST"
+
+		fmt.Println("")
+		fmt.Println("WireGuard:")
+		fmt.Printf("  Tunnel: %s\n", state.WireGuard.Tunnel)
+		fmt.Printf("  Public key: %s\n", state.WireGuard.PublicKey)
+		if state.WireGuard.NextPublicKey != "" {
+			fmt.Printf("  Next public key: %s\n", state.WireGuard.NextPublicKey)
+		}
+
+		fmt.Printf("  Listen port: %d\n", state.WireGuard.ListenPort)
+
+		if len(state.WireGuard.Peers) > 0 {
+			fmt.Println("  Peers:")
+			for _, peer := range state.WireGuard.Peers {
+				latestHandshake := "never"
+				if !peer.LatestHandshake.IsZero() {
+					latestHandshake = peer.LatestHandshake.Local().Format(layout)
+				}
+
+				fmt.Printf("    %s:\n", peer.PublicKey)
+				fmt.Printf("      Endpoint: %s\n", peer.Endpoint)
+				fmt.Printf("      Latest handshake: %s\n", latestHandshake)
+				fmt.Printf("      Bytes received: %s\n", units.GetByteSizeString(peer.BytesReceived, 2))
+				fmt.Printf("      Bytes sent: %s\n", units.GetByteSizeString(peer.BytesSent, 2))
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -268,15 +268,28 @@ func (c *cmdNetworkACLShow) run(cmd *cobra.Command, args []string) error {
 type cmdNetworkACLShowLog struct {
 	global     *cmdGlobal
 	networkACL *cmdNetworkACL
+
+	flagCounters bool
+	flagFormat   string
 }
 
 func (c *cmdNetworkACLShowLog) command() *cobra.Command {
 	cmd := &cobra.Command{}
 	cmd.Use = usage("show-log", "[<remote>:]<ACL>")
 	cmd.Short = "Show network ACL log"
-	cmd.Long = cli.FormatSection("Description", cmd.Short)
+	cmd.Long = cli.FormatSection("Description", `Show network ACL log
+
+When --counters is used, the packet and byte counters of the ACL rules are shown instead.`)
+	cmd.Example = cli.FormatSection("", `lxc network acl show-log my-acl
+    Show the log entries of the logged rules of the ACL.
+
+lxc network acl show-log my-acl --counters
+    Show the packet and byte counters of the rules of the ACL.`)
 	cmd.RunE = c.run
 
+	cmd.Flags().BoolVar(&c.flagCounters, "counters", false, "Show the packet and byte counters of the rules")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format of the counters (csv|json|table|yaml|compact)"))
+
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
 			return c.global.cmpTopLevelResource("network_acl", toComplete)
@@ -306,6 +319,10 @@ func (c *cmdNetworkACLShowLog) run(cmd *cobra.Command, args []string) error {
 		return errors.New("Missing network ACL name")
 	}
 
+	if c.flagCounters {
+		return c.showCounters(resource.server, resource.name)
+	}
+
 	// Get the ACL log.
 	log, err := resource.server.GetNetworkACLLogfile(resource.name)
 	if err != nil {
@@ -318,6 +335,46 @@ func (c *cmdNetworkACLShowLog) run(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+// showCounters shows the packet and byte counters of the rules of the ACL.
+func (c *cmdNetworkACLShowLog) showCounters(server lxd.InstanceServer, aclName string) error {
+	netACL, _, err := server.GetNetworkACL(aclName)
+	if err != nil {
+		return err
+	}
+
+	aclState, err := server.GetNetworkACLState(aclName)
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	addRules := func(direction string, rules []api.NetworkACLRule, counters []api.NetworkACLRuleCounters) {
+		for i, rule := range rules {
+			var ruleCounters api.NetworkACLRuleCounters
+			if i < len(counters) {
+				ruleCounters = counters[i]
+			}
+
+			data = append(data, []string{direction, strconv.Itoa(i), rule.Action, rule.State, rule.Description, strconv.FormatUint(ruleCounters.Packets, 10), strconv.FormatUint(ruleCounters.Bytes, 10)})
+		}
+	}
+
+	addRules("ingress", netACL.Ingress, aclState.Ingress)
+	addRules("egress", netACL.Egress, aclState.Egress)
+
+	header := []string{
+		"DIRECTION",
+		"RULE",
+		"ACTION",
+		"STATE",
+		"DESCRIPTION",
+		"PACKETS",
+		"BYTES",
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, aclState)
+}
+
 // Get.
 type cmdNetworkACLGet struct {
 	global     *cmdGlobal
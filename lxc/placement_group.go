@@ -65,6 +65,10 @@ func (c *cmdPlacementGroup) command() *cobra.Command {
 	placementGroupRenameCmd := cmdPlacementGroupRename{global: c.global, placementGroup: c}
 	cmd.AddCommand(placementGroupRenameCmd.command())
 
+	// Rebalance.
+	placementGroupRebalanceCmd := cmdPlacementGroupRebalance{global: c.global, placementGroup: c}
+	cmd.AddCommand(placementGroupRebalanceCmd.command())
+
 	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
 	cmd.Args = cobra.NoArgs
 	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
@@ -778,3 +782,115 @@ func (c *cmdPlacementGroupRename) run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// Rebalance.
+type cmdPlacementGroupRebalance struct {
+	global         *cmdGlobal
+	placementGroup *cmdPlacementGroup
+
+	flagDryRun bool
+	flagFormat string
+}
+
+func (c *cmdPlacementGroupRebalance) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rebalance", "[<remote>:]<placement_group>")
+	cmd.Short = "Rebalance placement group"
+	cmd.Long = cli.FormatSection("Description", `Rebalance placement group
+
+Migrates the instances of the placement group so that their placement complies with the placement group policy again.
+Running virtual machines are live-migrated where possible, other running instances are stopped and started again.
+
+Use --dry-run to show the planned migrations without performing them.`)
+	cmd.Example = cli.FormatSection("", `lxc placement-group rebalance pg1 --dry-run
+    Show the instance migrations needed to rebalance placement group "pg1".
+
+lxc placement-group rebalance pg1
+    Rebalance placement group "pg1".`)
+	cmd.RunE = c.run
+
+	cmd.Flags().BoolVar(&c.flagDryRun, "dry-run", false, "Show the planned migrations without performing them")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact), used with --dry-run"))
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("placement_group", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdPlacementGroupRebalance) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing placement group name")
+	}
+
+	if c.flagDryRun {
+		plan, err := resource.server.GetPlacementGroupRebalance(resource.name)
+		if err != nil {
+			return err
+		}
+
+		if len(plan.Moves) == 0 && (c.flagFormat == "table" || c.flagFormat == "compact") {
+			fmt.Printf("Placement group %s is already balanced\n", resource.name)
+			return nil
+		}
+
+		data := make([][]string, 0, len(plan.Moves))
+		for _, move := range plan.Moves {
+			data = append(data, []string{move.Instance, move.Source, move.Target, strconv.FormatBool(move.Live)})
+		}
+
+		header := []string{"INSTANCE", "SOURCE", "TARGET", "LIVE"}
+
+		return cli.RenderTable(c.flagFormat, header, data, plan.Moves)
+	}
+
+	// Rebalance the placement group.
+	op, err := resource.server.RebalancePlacementGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: "Rebalancing placement group: %s",
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Placement group %s rebalanced\n", resource.name)
+	}
+
+	return nil
+}
@@ -24,6 +24,7 @@ type cmdPublish struct {
 	flagMakePublic           bool
 	flagForce                bool
 	flagReuse                bool
+	flagSign                 bool
 }
 
 func (c *cmdPublish) command() *cobra.Command {
@@ -39,6 +40,7 @@ func (c *cmdPublish) command() *cobra.Command {
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", cli.FormatStringFlagLabel("Compression algorithm to use (`none` for uncompressed)"))
 	cmd.Flags().StringVar(&c.flagExpiresAt, "expire", "", cli.FormatStringFlagLabel("Image expiration date (format: rfc3339)"))
 	cmd.Flags().BoolVar(&c.flagReuse, "reuse", false, "If the image alias already exists, delete and create a new one")
+	cmd.Flags().BoolVar(&c.flagSign, "sign", false, "Sign the image with the server key")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -217,6 +219,7 @@ func (c *cmdPublish) run(cmd *cobra.Command, args []string) error {
 			Name: cName,
 		},
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		Sign:                 c.flagSign,
 	}
 
 	req.Properties = properties
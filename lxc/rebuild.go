@@ -177,8 +177,13 @@ func (c *cmdRebuild) rebuild(conf *config.Config, args []string) error {
 						return fmt.Errorf("Image registry %q not found", registryName)
 					}
 
-					req.Source.Server = remoteConfig.Addr                        //nolint:staticcheck
-					req.Source.Protocol = api.ImageRegistryProtocolSimpleStreams //nolint:staticcheck
+					protocol := remoteConfig.Protocol
+					if protocol == "" {
+						protocol = api.ImageRegistryProtocolLXD
+					}
+
+					req.Source.Server = remoteConfig.Addr //nolint:staticcheck
+					req.Source.Protocol = protocol        //nolint:staticcheck
 				} else {
 					// Registry exists on the server, use it directly.
 					req.Source.ImageRegistry = registryName
@@ -7,6 +7,7 @@ import (
 	"maps"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	cli "github.com/canonical/lxd/shared/cmd"
 	"github.com/canonical/lxd/shared/termios"
+	"github.com/canonical/lxd/shared/units"
 )
 
 type cmdReplicator struct {
@@ -47,6 +49,10 @@ func (c *cmdReplicator) command() *cobra.Command {
 	replicatorGetCmd := cmdReplicatorGet{global: c.global}
 	cmd.AddCommand(replicatorGetCmd.command())
 
+	// History.
+	replicatorHistoryCmd := cmdReplicatorHistory{global: c.global}
+	cmd.AddCommand(replicatorHistoryCmd.command())
+
 	// Info.
 	replicatorInfoCmd := cmdReplicatorInfo{global: c.global}
 	cmd.AddCommand(replicatorInfoCmd.command())
@@ -55,6 +61,10 @@ func (c *cmdReplicator) command() *cobra.Command {
 	replicatorListCmd := cmdReplicatorList{global: c.global}
 	cmd.AddCommand(replicatorListCmd.command())
 
+	// Promote.
+	replicatorPromoteCmd := cmdReplicatorPromote{global: c.global}
+	cmd.AddCommand(replicatorPromoteCmd.command())
+
 	// Rename.
 	replicatorRenameCmd := cmdReplicatorRename{global: c.global}
 	cmd.AddCommand(replicatorRenameCmd.command())
@@ -779,6 +789,103 @@ func (c *cmdReplicatorEdit) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// History.
+type cmdReplicatorHistory struct {
+	global     *cmdGlobal
+	flagFormat string
+}
+
+func (c *cmdReplicatorHistory) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("history", "[<remote>:]<replicator>")
+	cmd.Short = "Show the run history of a replicator"
+	cmd.Long = cli.FormatSection("Description", `Show the run history of a replicator
+
+Lists the recorded runs of the replicator, most recent first, including the amount of data
+transferred and the instances that failed to replicate along with their error.`)
+	cmd.Example = cli.FormatSection("", `lxc replicator history my-replicator
+    Show the run history of the replicator "my-replicator".`)
+
+	cmd.RunE = c.run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", "Format (csv|json|table|yaml|compact)")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("replicator", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdReplicatorHistory) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing replicator name")
+	}
+
+	runs, err := resource.server.GetReplicatorRuns(c.global.flagProject, resource.name)
+	if err != nil {
+		return err
+	}
+
+	const layout = "2006/01/02 15:04 MST"
+
+	data := [][]string{}
+	for _, run := range runs {
+		failed := []string{}
+		for _, inst := range run.Instances {
+			if inst.Status != api.ReplicatorStatusFailed {
+				continue
+			}
+
+			name := inst.Name
+			if inst.Location != "" {
+				name += " (" + inst.Location + ")"
+			}
+
+			failed = append(failed, name+": "+inst.Error)
+		}
+
+		data = append(data, []string{
+			strconv.FormatInt(run.ID, 10),
+			run.StartedAt.Local().Format(layout),
+			run.FinishedAt.Local().Format(layout),
+			run.Status,
+			strconv.Itoa(len(run.Instances)),
+			units.GetByteSizeStringIEC(run.BytesTransferred, 2),
+			strings.Join(failed, "\n"),
+		})
+	}
+
+	header := []string{
+		"ID",
+		"STARTED",
+		"FINISHED",
+		"STATUS",
+		"INSTANCES",
+		"TRANSFERRED",
+		"FAILED INSTANCES",
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, runs)
+}
+
 // Info.
 type cmdReplicatorInfo struct {
 	global *cmdGlobal
@@ -903,6 +1010,78 @@ func (c *cmdReplicatorInfo) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Promote.
+type cmdReplicatorPromote struct {
+	global    *cmdGlobal
+	flagForce bool
+}
+
+func (c *cmdReplicatorPromote) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("promote", "[<remote>:]<replicator>")
+	cmd.Short = "Promote the standby project of a replicator"
+	cmd.Long = cli.FormatSection("Description", `Promote the standby project of a replicator
+
+Promotes the local standby project to leader and starts its instances in boot priority order.
+If the project on the target cluster is still the leader, its instances are stopped, restored into
+the local project and the target project is demoted to standby before the local project is promoted.
+If the target cluster can't be reached, the project is only promoted with --force.`)
+	cmd.Example = cli.FormatSection("", `lxc replicator promote my-replicator
+    Promote the project of the replicator "my-replicator" to leader.
+
+lxc replicator promote my-replicator --force
+    Promote the project of the replicator "my-replicator" to leader, even if its target cluster can't be reached.`)
+
+	cmd.RunE = c.run
+	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, "Promote the project even if the target cluster can't be reached")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("replicator", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdReplicatorPromote) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing replicator name")
+	}
+
+	op, err := resource.server.RunReplicator(c.global.flagProject, resource.name, api.ReplicatorStatePut{Action: "promote", Force: c.flagForce})
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Replicator %s promoted\n", resource.name)
+	}
+
+	return nil
+}
+
 // Rename.
 type cmdReplicatorRename struct {
 	global *cmdGlobal
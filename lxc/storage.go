@@ -58,6 +58,14 @@ func (c *cmdStorage) command() *cobra.Command {
 	storageListCmd := cmdStorageList{global: c.global, storage: c}
 	cmd.AddCommand(storageListCmd.command())
 
+	// Migrate
+	storageMigrateCmd := cmdStorageMigrate{global: c.global, storage: c}
+	cmd.AddCommand(storageMigrateCmd.command())
+
+	// Scrub
+	storageScrubCmd := cmdStorageScrub{global: c.global, storage: c}
+	cmd.AddCommand(storageScrubCmd.command())
+
 	// Set
 	storageSetCmd := cmdStorageSet{global: c.global, storage: c}
 	cmd.AddCommand(storageSetCmd.command())
@@ -550,6 +558,9 @@ func (c *cmdStorageInfo) run(cmd *cobra.Command, args []string) error {
 	descriptionstring := "description"
 	totalspacestring := "total space"
 	spaceusedstring := "space used"
+	spaceprovisionedstring := "space provisioned"
+	metadatatotalspacestring := "metadata total space"
+	metadataspaceusedstring := "metadata space used"
 
 	// Initialize the usedby map
 	poolusedby[usedbystring] = make(map[string][]string)
@@ -624,12 +635,25 @@ func (c *cmdStorageInfo) run(cmd *cobra.Command, args []string) error {
 	poolinfo[infostring][namestring] = pool.Name
 	poolinfo[infostring][driverstring] = pool.Driver
 	poolinfo[infostring][descriptionstring] = pool.Description
-	if c.flagBytes {
-		poolinfo[infostring][totalspacestring] = strconv.FormatUint(res.Space.Total, 10)
-		poolinfo[infostring][spaceusedstring] = strconv.FormatUint(res.Space.Used, 10)
-	} else {
-		poolinfo[infostring][totalspacestring] = units.GetByteSizeStringIEC(int64(res.Space.Total), 2)
-		poolinfo[infostring][spaceusedstring] = units.GetByteSizeStringIEC(int64(res.Space.Used), 2)
+
+	formatSize := func(size uint64) string {
+		if c.flagBytes {
+			return strconv.FormatUint(size, 10)
+		}
+
+		return units.GetByteSizeStringIEC(int64(size), 2)
+	}
+
+	poolinfo[infostring][totalspacestring] = formatSize(res.Space.Total)
+	poolinfo[infostring][spaceusedstring] = formatSize(res.Space.Used)
+
+	if res.Space.Provisioned > 0 {
+		poolinfo[infostring][spaceprovisionedstring] = formatSize(res.Space.Provisioned)
+	}
+
+	if res.Metadata != nil {
+		poolinfo[infostring][metadatatotalspacestring] = formatSize(res.Metadata.Total)
+		poolinfo[infostring][metadataspaceusedstring] = formatSize(res.Metadata.Used)
 	}
 
 	poolinfodata, err := yaml.Marshal(poolinfo)
@@ -775,6 +799,203 @@ func (c *cmdStorageList) stateColumnData(pool api.StoragePool) string {
 	return strings.ToUpper(pool.Status)
 }
 
+// Migrate.
+type cmdStorageMigrate struct {
+	global  *cmdGlobal
+	storage *cmdStorage
+}
+
+func (c *cmdStorageMigrate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("migrate", "[<remote>:]<pool> <target pool>")
+	cmd.Short = "Move all volumes of a storage pool to another storage pool"
+	cmd.Long = cli.FormatSection("Description", `Move all volumes of a storage pool to another storage pool
+
+Instances with their snapshots, custom volumes with their snapshots and image volumes are moved to the target pool.
+Profile root disk devices using the storage pool are updated to use the target pool.
+The instances on the storage pool and the instances using its custom volumes must be stopped.
+
+An interrupted migration can be resumed by running the command again.`)
+	cmd.Example = cli.FormatSection("", `lxc storage migrate old-pool new-pool
+    Move all volumes of "old-pool" to "new-pool".`)
+
+	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("storage_pool", toComplete)
+		}
+
+		remote, _, err := c.global.conf.ParseRemote(args[0])
+		if err != nil {
+			return handleCompletionError(err)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpTopLevelResourceInRemote(remote, "storage_pool", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdStorageMigrate) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing pool name")
+	}
+
+	// Targeting
+	if c.storage.flagTarget != "" {
+		if !resource.server.IsClustered() {
+			return errors.New("To use --target, the destination remote must be a cluster")
+		}
+
+		resource.server = resource.server.UseTarget(c.storage.flagTarget)
+	}
+
+	// Migrate the pool
+	op, err := resource.server.MigrateStoragePool(resource.name, api.StoragePoolMigratePost{Pool: args[1]})
+	if err != nil {
+		return err
+	}
+
+	// Register progress handler
+	progress := cli.ProgressRenderer{
+		Format: "Migrating storage pool: %s",
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Storage pool %s migrated to %s\n", resource.name, args[1])
+	}
+
+	return nil
+}
+
+// Scrub.
+type cmdStorageScrub struct {
+	global  *cmdGlobal
+	storage *cmdStorage
+}
+
+func (c *cmdStorageScrub) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("scrub", "[<remote>:]<pool>")
+	cmd.Short = "Check the integrity of the data stored in a storage pool"
+	cmd.Long = cli.FormatSection("Description", `Check the integrity of the data stored in a storage pool
+
+The storage driver's own scrubbing is used where available (ZFS, Btrfs and Ceph RBD).
+Otherwise, all the instance and custom volumes of the pool are read back.
+A warning is raised if corrupted data is found.`)
+	cmd.Example = cli.FormatSection("", `lxc storage scrub default
+    Check the integrity of the data stored in pool "default".`)
+
+	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("storage_pool", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdStorageScrub) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing pool name")
+	}
+
+	// Targeting
+	if c.storage.flagTarget != "" {
+		if !resource.server.IsClustered() {
+			return errors.New("To use --target, the destination remote must be a cluster")
+		}
+
+		resource.server = resource.server.UseTarget(c.storage.flagTarget)
+	}
+
+	// Scrub the pool
+	op, err := resource.server.ScrubStoragePool(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Register progress handler
+	progress := cli.ProgressRenderer{
+		Format: "Scrubbing storage pool: %s",
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Storage pool %s scrubbed\n", resource.name)
+	}
+
+	return nil
+}
+
 // Set.
 type cmdStorageSet struct {
 	global  *cmdGlobal
@@ -147,6 +147,10 @@ Unless specified through a prefix, all volume operations affect "custom" (user c
 	storageVolumeUnsetCmd := cmdStorageVolumeUnset{global: c.global, storage: c.storage, storageVolume: c, storageVolumeSet: &storageVolumeSetCmd}
 	cmd.AddCommand(storageVolumeUnsetCmd.command())
 
+	// Verify
+	storageVolumeVerifyCmd := cmdStorageVolumeVerify{global: c.global, storage: c.storage, storageVolume: c}
+	cmd.AddCommand(storageVolumeVerifyCmd.command())
+
 	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
 	cmd.Args = cobra.NoArgs
 	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
@@ -382,6 +386,7 @@ type cmdStorageVolumeCopy struct {
 	flagVolumeOnly    bool
 	flagTargetProject string
 	flagRefresh       bool
+	flagClone         bool
 }
 
 func (c *cmdStorageVolumeCopy) command() *cobra.Command {
@@ -397,6 +402,7 @@ func (c *cmdStorageVolumeCopy) command() *cobra.Command {
 	cmd.Flags().BoolVar(&c.flagVolumeOnly, "volume-only", false, "Copy the volume without its snapshots")
 	cmd.Flags().StringVar(&c.flagTargetProject, "target-project", "", cli.FormatStringFlagLabel("Copy to a project different from the source"))
 	cmd.Flags().BoolVar(&c.flagRefresh, "refresh", false, "Refresh and update the existing storage volume copies")
+	cmd.Flags().BoolVar(&c.flagClone, "clone", false, "Create a copy-on-write clone of the volume without its snapshots")
 	cmd.RunE = c.run
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -472,6 +478,10 @@ func (c *cmdStorageVolumeCopy) run(cmd *cobra.Command, args []string) error {
 		return errors.New("Cannot set --volume-only when copying a snapshot")
 	}
 
+	if c.flagClone && c.flagRefresh {
+		return errors.New("Cannot set --refresh when creating a clone")
+	}
+
 	// If the volume is in local storage, set the target to its location (or provide a helpful error
 	// message if the target is incorrect). If the volume is in remote storage (and the source server is clustered) we
 	// can use any provided target. Note that for standalone servers, this will set the target to "none".
@@ -554,6 +564,7 @@ func (c *cmdStorageVolumeCopy) run(cmd *cobra.Command, args []string) error {
 		args.Mode = mode
 		args.VolumeOnly = c.flagVolumeOnly
 		args.Refresh = c.flagRefresh
+		args.Clone = c.flagClone
 
 		if c.flagTargetProject != "" {
 			dstServer = dstServer.UseProject(c.flagTargetProject)
@@ -1502,6 +1513,14 @@ func (c *cmdStorageVolumeInfo) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if vol.Config["limits.iops"] != "" {
+		fmt.Printf("IOPS limit: %s\n", vol.Config["limits.iops"])
+	}
+
+	if vol.Config["limits.bandwidth"] != "" {
+		fmt.Printf("Bandwidth limit: %s/s\n", vol.Config["limits.bandwidth"])
+	}
+
 	if shared.TimeIsSet(vol.CreatedAt) {
 		fmt.Printf("Created: %s\n", vol.CreatedAt.Local().Format(layout))
 	}
@@ -2422,6 +2441,110 @@ func (c *cmdStorageVolumeUnset) run(cmd *cobra.Command, args []string) error {
 	return c.storageVolumeSet.run(cmd, args)
 }
 
+// Verify.
+type cmdStorageVolumeVerify struct {
+	global        *cmdGlobal
+	storage       *cmdStorage
+	storageVolume *cmdStorageVolume
+}
+
+func (c *cmdStorageVolumeVerify) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("verify", "[<remote>:]<pool> [<type>/]<volume>")
+	cmd.Short = "Verify the integrity of storage volumes"
+	cmd.Long = cli.FormatSection("Description", `Verify the integrity of storage volumes
+
+All the data of the volume is read back. The SHA-256 checksum of block volumes is printed.
+A warning is raised if corrupted data is found.`)
+	cmd.Example = cli.FormatSection("", `lxc storage volume verify default data
+    Verify the custom volume "data" in pool "default".
+
+lxc storage volume verify default virtual-machine/v1
+    Verify the root volume of virtual machine "v1" in pool "default".`)
+
+	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("storage_pool", toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpStoragePoolVolumes(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdStorageVolumeVerify) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return errors.New("Missing pool name")
+	}
+
+	client := resource.server
+
+	// Parse the input
+	volName, volType := parseVolume("custom", args[1])
+
+	// If a target was specified, verify the volume on the given member.
+	if c.storage.flagTarget != "" {
+		client = client.UseTarget(c.storage.flagTarget)
+	}
+
+	op, err := client.VerifyStoragePoolVolume(resource.name, volType, volName)
+	if err != nil {
+		return err
+	}
+
+	// Register progress handler
+	progress := cli.ProgressRenderer{
+		Format: "Verifying storage volume: %s",
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Storage volume %s verified\n", args[1])
+
+		checksum, ok := op.Get().Metadata["checksum"].(string)
+		if ok {
+			fmt.Printf("SHA-256 checksum: %s\n", checksum)
+		}
+	}
+
+	return nil
+}
+
 // Snapshot.
 type cmdStorageVolumeSnapshot struct {
 	global        *cmdGlobal
@@ -2654,11 +2777,12 @@ type cmdStorageVolumeExport struct {
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
 	flagExportVersion        string
+	flagBackupTarget         string
 }
 
 func (c *cmdStorageVolumeExport) command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("export", "[<remote>:]<pool> <volume> [<path>]")
+	cmd.Use = usage("export", "[<remote>:]<pool> <volume> [<path>|<backup name>]")
 	cmd.Short = "Export custom storage volume"
 	cmd.Long = cli.FormatSection("Description", cmd.Short)
 
@@ -2666,6 +2790,7 @@ func (c *cmdStorageVolumeExport) command() *cobra.Command {
 	cmd.Flags().BoolVar(&c.flagOptimizedStorage, "optimized-storage", false, "Use storage driver optimized format (can only be restored on a similar pool)")
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", cli.FormatStringFlagLabel("Define a compression algorithm: for backup or none"))
 	cmd.Flags().StringVar(&c.flagExportVersion, "export-version", "", cli.FormatStringFlagLabel("Use a different metadata format version than the latest one supported by the server (to support imports on older LXD versions)"))
+	cmd.Flags().StringVar(&c.flagBackupTarget, "backup-target", "", cli.FormatStringFlagLabel("Upload the backup to this backup target instead of downloading it (the path argument is then the backup name)"))
 	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
 	cmd.RunE = c.run
 
@@ -2729,6 +2854,13 @@ func (c *cmdStorageVolumeExport) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if c.flagBackupTarget != "" {
+		req.Target = c.flagBackupTarget
+		if len(args) > 2 {
+			req.Name = args[2]
+		}
+	}
+
 	op, err := d.CreateStoragePoolVolumeBackup(name, volName, req)
 	if err != nil {
 		return fmt.Errorf("Failed creating storage volume backup for volume %q: %w", volName, err)
@@ -2753,6 +2885,13 @@ func (c *cmdStorageVolumeExport) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Backups uploaded to a backup target aren't stored on the server.
+	if req.Target != "" {
+		key, _ := op.Get().Metadata["backup_key"].(string)
+		progress.Done(fmt.Sprintf("Backup uploaded to backup target %q as %q", req.Target, key))
+		return nil
+	}
+
 	progress.Done("")
 
 	err = op.Wait()
@@ -2826,17 +2965,22 @@ type cmdStorageVolumeImport struct {
 	storage       *cmdStorage
 	storageVolume *cmdStorageVolume
 
-	flagType string
+	flagType         string
+	flagBackupTarget string
 }
 
 func (c *cmdStorageVolumeImport) command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("import", "[<remote>:]<pool> <import file> [<volume name>]")
+	cmd.Use = usage("import", "[<remote>:]<pool> <import file>|<backup key> [<volume name>]")
 	cmd.Short = "Import storage volumes"
 	cmd.Long = cli.FormatSection("Description", `Import custom volume backups, iso images, or tarballs.`)
 	cmd.Example = cli.FormatSection("", `lxc storage volume import default backup0.tar.gz
-		Create a new custom volume using backup0.tar.gz with included snapshots as the source.`)
+		Create a new custom volume using backup0.tar.gz with included snapshots as the source.
+
+lxc storage volume import default volumes/default/vol1/backup0 vol2 --backup-target s3-backups
+		Create a new custom volume named vol2 from the volumes/default/vol1/backup0 backup stored on the s3-backups backup target.`)
 	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+	cmd.Flags().StringVar(&c.flagBackupTarget, "backup-target", "", cli.FormatStringFlagLabel("Import the backup with the given key from this backup target"))
 	cmd.RunE = c.run
 	cmd.Flags().StringVar(&c.flagType, "type", "", cli.FormatStringFlagLabel(`Type of the import file. Valid options are:
 - backup: custom volume backup (default option)
@@ -2879,6 +3023,10 @@ func (c *cmdStorageVolumeImport) run(cmd *cobra.Command, args []string) error {
 		d = d.UseTarget(c.storage.flagTarget)
 	}
 
+	if c.flagBackupTarget != "" {
+		return c.importFromBackupTarget(d, pool, args)
+	}
+
 	file, err := os.Open(shared.HostPathFollow(args[1]))
 	if err != nil {
 		return err
@@ -2957,3 +3105,39 @@ func (c *cmdStorageVolumeImport) run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// importFromBackupTarget creates a custom volume from a backup that the server reads directly from a backup target.
+func (c *cmdStorageVolumeImport) importFromBackupTarget(d lxd.InstanceServer, pool string, args []string) error {
+	if c.flagType != "" && c.flagType != "backup" {
+		return errors.New("Only backups can be imported from a backup target")
+	}
+
+	createArgs := lxd.StoragePoolVolumeBackupArgs{
+		BackupTarget:    c.flagBackupTarget,
+		BackupTargetKey: args[1],
+	}
+
+	if len(args) >= 3 {
+		createArgs.Name = args[2]
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: "Importing custom volume: %s",
+		Quiet:  c.global.flagQuiet,
+	}
+
+	defer progress.Done("")
+
+	op, err := d.CreateStoragePoolVolumeFromBackup(pool, createArgs)
+	if err != nil {
+		return err
+	}
+
+	// Wait for operation to finish.
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -427,8 +427,8 @@ func getImgInfo(conf *config.Config, imageRemote string, imageRef string, imageP
 		return nil, nil, err
 	}
 
-	// Optimisation for simplestreams
-	if conf.Remotes[imageRemote].Protocol == "simplestreams" {
+	// Optimisation for simplestreams and OCI registries
+	if conf.Remotes[imageRemote].Protocol == "simplestreams" || conf.Remotes[imageRemote].Protocol == "oci" {
 		imageInfo = &api.Image{}
 		imageInfo.Fingerprint = imageRef
 		imageInfo.Public = true
@@ -0,0 +1,8 @@
+package api
+
+// FilesystemsFreezePost contains the mount points of the filesystems that the lxd-agent freezes.
+type FilesystemsFreezePost struct {
+	// Mount points of the filesystems to freeze. The root filesystem can't be frozen.
+	// Example: ["/srv/data"]
+	MountPoints []string `json:"mount_points" yaml:"mount_points"`
+}
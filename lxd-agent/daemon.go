@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/canonical/lxd/lxd/events"
 )
@@ -30,6 +31,11 @@ type Daemon struct {
 	devlxdRunning bool
 	devlxdMu      sync.Mutex
 	devlxdEnabled bool
+
+	// Filesystems frozen on request of LXD, and the timer thawing them automatically.
+	filesystemsFrozen    []string
+	filesystemsThawTimer *time.Timer
+	filesystemsMu        sync.Mutex
 }
 
 // newDaemon returns a new Daemon object with the given configuration.
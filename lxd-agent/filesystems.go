@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	agentAPI "github.com/canonical/lxd/lxd-agent/api"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// filesystemsThawTimeout is how long filesystems stay frozen before being thawed automatically. It prevents the
+// guest from hanging forever if LXD never requests the filesystems to be thawed.
+const filesystemsThawTimeout = time.Minute
+
+// Only these filesystems are frozen, as they are the ones backed by the instance disks and supporting freezing.
+var freezableFSTypes = []string{"btrfs", "ext2", "ext3", "ext4", "xfs"}
+
+var filesystemsFreezeCmd = APIEndpoint{
+	Name: "filesystemsFreeze",
+	Path: "filesystems/freeze",
+
+	Post:   APIEndpointAction{Handler: filesystemsFreezePost},
+	Delete: APIEndpointAction{Handler: filesystemsFreezeDelete},
+}
+
+// filesystemsFreezePost freezes the requested filesystems of the instance so that a consistent snapshot of its disks
+// can be taken. The root filesystem is never frozen, as the lxd-agent itself and the tools it runs live on it.
+func filesystemsFreezePost(d *Daemon, r *http.Request) response.Response {
+	req := agentAPI.FilesystemsFreezePost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	for _, mountPoint := range req.MountPoints {
+		if !filepath.IsAbs(mountPoint) {
+			return response.BadRequest(fmt.Errorf("Mount point %q must be an absolute path", mountPoint))
+		}
+
+		if filepath.Clean(mountPoint) == "/" {
+			return response.BadRequest(errors.New("The root filesystem cannot be frozen"))
+		}
+	}
+
+	d.filesystemsMu.Lock()
+	defer d.filesystemsMu.Unlock()
+
+	if len(d.filesystemsFrozen) > 0 {
+		return response.Conflict(errors.New("Filesystems are already frozen"))
+	}
+
+	mountPoints, err := freezableMountPoints(req.MountPoints)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Freeze nested mounts before their parents, so that the parents can still be traversed while freezing.
+	slices.Reverse(mountPoints)
+	for _, mountPoint := range mountPoints {
+		_, err := shared.RunCommand(context.Background(), "fsfreeze", "--freeze", mountPoint)
+		if err != nil {
+			thawFilesystems(d.filesystemsFrozen)
+			d.filesystemsFrozen = nil
+
+			return response.InternalError(fmt.Errorf("Failed freezing filesystem %q: %w", mountPoint, err))
+		}
+
+		d.filesystemsFrozen = append(d.filesystemsFrozen, mountPoint)
+	}
+
+	d.filesystemsThawTimer = time.AfterFunc(filesystemsThawTimeout, func() {
+		d.filesystemsMu.Lock()
+		defer d.filesystemsMu.Unlock()
+
+		if len(d.filesystemsFrozen) > 0 {
+			logger.Warn("Thawing filesystems that stayed frozen for too long", logger.Ctx{"timeout": filesystemsThawTimeout})
+			thawFilesystems(d.filesystemsFrozen)
+			d.filesystemsFrozen = nil
+		}
+	})
+
+	return response.EmptySyncResponse
+}
+
+// filesystemsFreezeDelete thaws the filesystems frozen by filesystemsFreezePost.
+func filesystemsFreezeDelete(d *Daemon, r *http.Request) response.Response {
+	d.filesystemsMu.Lock()
+	defer d.filesystemsMu.Unlock()
+
+	if d.filesystemsThawTimer != nil {
+		d.filesystemsThawTimer.Stop()
+		d.filesystemsThawTimer = nil
+	}
+
+	thawFilesystems(d.filesystemsFrozen)
+	d.filesystemsFrozen = nil
+
+	return response.EmptySyncResponse
+}
+
+// thawFilesystems thaws the given mount points in the reverse order they were frozen in.
+func thawFilesystems(frozen []string) {
+	for _, mountPoint := range slices.Backward(frozen) {
+		_, err := shared.RunCommand(context.Background(), "fsfreeze", "--unfreeze", mountPoint)
+		if err != nil {
+			logger.Error("Failed thawing filesystem", logger.Ctx{"mountPoint": mountPoint, "err": err})
+		}
+	}
+}
+
+// freezableMountPoints returns the requested mount points in mount order, and fails if any of them isn't the
+// mount point of a writable freezable filesystem. Filesystems mounted multiple times are only returned once, as
+// they can only be frozen once.
+func freezableMountPoints(requested []string) ([]string, error) {
+	mountInfoFile, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, fmt.Errorf("Error opening /proc/self/mountinfo: %w", err)
+	}
+
+	defer mountInfoFile.Close()
+
+	remaining := make(map[string]bool, len(requested))
+	for _, mountPoint := range requested {
+		remaining[filepath.Clean(mountPoint)] = true
+	}
+
+	var mountPoints []string
+	devices := make(map[string]bool)
+	scanner := bufio.NewScanner(mountInfoFile)
+
+	for scanner.Scan() {
+		// The optional fields are terminated by a "-" separator, followed by the filesystem type.
+		fields := strings.Fields(scanner.Text())
+		separator := slices.Index(fields, "-")
+		if len(fields) < 6 || separator < 0 || separator+1 >= len(fields) {
+			continue
+		}
+
+		device := fields[2]
+		mountPoint := fields[4]
+		mountOptions := strings.Split(fields[5], ",")
+		fsType := fields[separator+1]
+
+		if !remaining[mountPoint] || !slices.Contains(freezableFSTypes, fsType) || slices.Contains(mountOptions, "ro") {
+			continue
+		}
+
+		delete(remaining, mountPoint)
+		if devices[device] {
+			continue
+		}
+
+		devices[device] = true
+		mountPoints = append(mountPoints, mountPoint)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading /proc/self/mountinfo: %w", err)
+	}
+
+	if len(remaining) > 0 {
+		missing := slices.Sorted(maps.Keys(remaining))
+		return nil, fmt.Errorf("Not mount points of writable %s filesystems: %s", strings.Join(freezableFSTypes, ", "), strings.Join(missing, ", "))
+	}
+
+	return mountPoints, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"net/http"
 	"slices"
 	"strconv"
@@ -15,6 +16,7 @@ import (
 	"github.com/canonical/lxd/lxd/metrics"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/storage/s3"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/logger"
@@ -113,6 +115,32 @@ func metricsServer(d *Daemon) *http.Server {
 	}
 }
 
+// storageBucketsServer returns the HTTP server of the built-in S3 gateway serving the buckets of local storage pools.
+func storageBucketsServer(d *Daemon) *http.Server {
+	gateway := &s3.Gateway{
+		Credentials: func(ctx context.Context, accessKey string) (*s3.Credentials, error) {
+			return storageBucketCredentials(ctx, d.State(), accessKey)
+		},
+		OpenBucket: func(ctx context.Context, bucketName string) (*s3.Bucket, func(), error) {
+			return storageBucketOpen(ctx, d.State(), bucketName)
+		},
+		GetBucket: func(ctx context.Context, bucketName string) (*s3.Bucket, error) {
+			bucket, _, _, err := storageBucketLoad(ctx, d.State(), bucketName)
+			return bucket, err
+		},
+	}
+
+	// No read or write timeouts as objects can be large.
+	return &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-d.setupChan
+			gateway.ServeHTTP(w, r)
+		}),
+		IdleTimeout:       30 * time.Second,
+		ReadHeaderTimeout: 3 * time.Second,
+	}
+}
+
 type lxdHTTPServer struct {
 	r *http.ServeMux
 	d *Daemon
@@ -53,6 +53,9 @@ var api10Cmd = APIEndpoint{
 var api10 = []APIEndpoint{
 	api10Cmd,
 	api10ResourcesCmd,
+	backupTargetCmd,
+	backupTargetBackupsCmd,
+	backupTargetsCmd,
 	certificateCmd,
 	certificatesCmd,
 	clusterCmd,
@@ -68,6 +71,8 @@ var api10 = []APIEndpoint{
 	replicatorCmd,
 	replicatorsCmd,
 	replicatorStateCmd,
+	replicatorRunsCmd,
+	replicatorRunCmd,
 	instanceBackupCmd,
 	instanceBackupExportCmd,
 	instanceBackupsCmd,
@@ -100,6 +105,9 @@ var api10 = []APIEndpoint{
 	networkACLCmd,
 	networkACLsCmd,
 	networkACLLogCmd,
+	networkACLStateCmd,
+	networkAddressSetCmd,
+	networkAddressSetsCmd,
 	networkAllocationsCmd,
 	networkForwardCmd,
 	networkForwardsCmd,
@@ -125,6 +133,8 @@ var api10 = []APIEndpoint{
 	projectStateCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
+	storagePoolMigrateCmd,
+	storagePoolScrubCmd,
 	storagePoolsCmd,
 	storagePoolBucketsCmd,
 	storagePoolBucketCmd,
@@ -139,6 +149,7 @@ var api10 = []APIEndpoint{
 	storagePoolVolumeTypeCustomBackupCmd,
 	storagePoolVolumeTypeCustomBackupExportCmd,
 	storagePoolVolumeTypeStateCmd,
+	storagePoolVolumeTypeVerifyCmd,
 	warningsCmd,
 	warningCmd,
 	metricsCmd,
@@ -162,6 +173,7 @@ var api10 = []APIEndpoint{
 	oidcSessionCmd,
 	placementGroupsCmd,
 	placementGroupCmd,
+	placementGroupRebalanceCmd,
 }
 
 // swagger:operation GET /1.0?public server server_get_untrusted
@@ -1203,6 +1215,14 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	value, ok = nodeChanged["core.storage_buckets_address"]
+	if ok {
+		err := s.Endpoints.StorageBucketsUpdateAddress(value, s.Endpoints.NetworkCert())
+		if err != nil {
+			return err
+		}
+	}
+
 	value, ok = nodeChanged["storage.backups_volume"]
 	if ok {
 		oldValue := oldNodeConfig["storage.backups_volume"]
@@ -1951,7 +1951,16 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 				return err
 			}
 
-			filteredCandidates, err := placement.Filter(ctx, tx, candidateMembers, *apiPlacementGroup, true)
+			// Ignore the instances on the evacuated cluster member, which is not necessarily the local member when healing.
+			var evacuatedMemberID *int64
+			for _, member := range allMembers {
+				if member.Name == inst.Location() {
+					evacuatedMemberID = &member.ID
+					break
+				}
+			}
+
+			filteredCandidates, err := placement.Filter(ctx, tx, candidateMembers, *apiPlacementGroup, evacuatedMemberID)
 			if err != nil {
 				// If no candidates remain due to placement constraints, signal not found so caller can skip instance during evacuation.
 				if api.StatusErrorCheck(err, http.StatusConflict) {
@@ -27,6 +27,7 @@ import (
 	deviceConfig "github.com/canonical/lxd/lxd/device/config"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
@@ -49,6 +50,9 @@ var apiInternal = []APIEndpoint{
 	internalClusterHandoverCmd,
 	internalClusterHealCmd,
 	internalClusterLinkRefreshVolatileAddressesCmd,
+	internalReplicatorMigrateCmd,
+	internalReplicatorMigrateVolumeCmd,
+	internalReplicatorLiveSnapshotCmd,
 	internalReplicatorRunSchedulerCmd,
 	internalClusterRaftNodeCmd,
 	internalClusterRebalanceCmd,
@@ -68,6 +72,7 @@ var apiInternal = []APIEndpoint{
 	internalPruneTokenCmd,
 	internalOperationWaitCmd,
 	internalSnapshotScheduledTaskCmd,
+	internalBackupScheduledTaskCmd,
 }
 
 var internalShutdownCmd = APIEndpoint{
@@ -149,6 +154,24 @@ var internalReplicatorRunSchedulerCmd = APIEndpoint{
 	Post: APIEndpointAction{Handler: internalRunReplicatorScheduler, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
 }
 
+var internalReplicatorMigrateCmd = APIEndpoint{
+	Path: "replicators/migrate",
+
+	Post: APIEndpointAction{Handler: internalReplicatorMigrate, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+var internalReplicatorMigrateVolumeCmd = APIEndpoint{
+	Path: "replicators/migrate-volume",
+
+	Post: APIEndpointAction{Handler: internalReplicatorMigrateVolume, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+var internalReplicatorLiveSnapshotCmd = APIEndpoint{
+	Path: "replicators/live-snapshot",
+
+	Post: APIEndpointAction{Handler: internalReplicatorLiveSnapshot, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
 var internalImageOptimizeCmd = APIEndpoint{
 	Path: "image-optimize",
 
@@ -189,12 +212,44 @@ var internalSnapshotScheduledTaskCmd = APIEndpoint{
 	Post: APIEndpointAction{Handler: internalSnapshotScheduledTask, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
 }
 
+var internalBackupScheduledTaskCmd = APIEndpoint{
+	Path: "testing/backup-scheduled-task",
+
+	Post: APIEndpointAction{Handler: internalBackupScheduledTask, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
 type internalImageOptimizePost struct {
 	Image   api.Image `json:"image"    yaml:"image"`
 	Pool    string    `json:"pool"     yaml:"pool"`
 	Project string    `json:"project"  yaml:"project"`
 }
 
+// internalReplicatorMigratePost is sent by the cluster member running a replicator to the member hosting an instance.
+type internalReplicatorMigratePost struct {
+	Project           string                 `json:"project"            yaml:"project"`
+	Instance          string                 `json:"instance"           yaml:"instance"`
+	Target            api.InstancePostTarget `json:"target"             yaml:"target"`
+	BandwidthLimit    int64                  `json:"bandwidth_limit"    yaml:"bandwidth_limit"`
+	AllowInconsistent bool                   `json:"allow_inconsistent" yaml:"allow_inconsistent"`
+}
+
+// internalReplicatorLiveSnapshotPost is sent by the cluster member running a replicator to the member hosting a
+// running instance, to take the temporary snapshot that the instance is replicated from.
+type internalReplicatorLiveSnapshotPost struct {
+	Project  string `json:"project"  yaml:"project"`
+	Instance string `json:"instance" yaml:"instance"`
+	Mode     string `json:"mode"     yaml:"mode"`
+}
+
+// internalReplicatorMigrateVolumePost is sent by the cluster member running a replicator to the member hosting a custom volume.
+type internalReplicatorMigrateVolumePost struct {
+	Project        string                      `json:"project"         yaml:"project"`
+	Pool           string                      `json:"pool"            yaml:"pool"`
+	Volume         string                      `json:"volume"          yaml:"volume"`
+	Target         api.StorageVolumePostTarget `json:"target"          yaml:"target"`
+	BandwidthLimit int64                       `json:"bandwidth_limit" yaml:"bandwidth_limit"`
+}
+
 type internalWarningCreatePost struct {
 	Location   string      `json:"location"    yaml:"location"`
 	Project    string      `json:"project"     yaml:"project"`
@@ -295,6 +350,105 @@ func internalRunReplicatorScheduler(d *Daemon, r *http.Request) response.Respons
 	return response.EmptySyncResponse
 }
 
+// internalReplicatorMigrate push-migrates a local instance to a replicator target on behalf of the cluster
+// member running the replicator, applying the replicator bandwidth limit.
+func internalReplicatorMigrate(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := internalReplicatorMigratePost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, req.Project, req.Instance)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if inst.Location() != s.ServerName {
+		return response.BadRequest(fmt.Errorf("Instance %q is not located on this cluster member", req.Instance))
+	}
+
+	opArgs, err := replicatorMigrationSourceOperationArgs(s, inst, &req.Target, req.BandwidthLimit, req.AllowInconsistent)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, opArgs)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// internalReplicatorLiveSnapshot takes the temporary snapshot that a running instance located on this cluster member
+// is replicated from, on behalf of the cluster member running the replicator.
+func internalReplicatorLiveSnapshot(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := internalReplicatorLiveSnapshotPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, req.Project, req.Instance)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if inst.Location() != s.ServerName {
+		return response.BadRequest(fmt.Errorf("Instance %q is not located on this cluster member", req.Instance))
+	}
+
+	err = replicatorCreateLiveSnapshot(r.Context(), inst, req.Mode)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// internalReplicatorMigrateVolume push-migrates a custom volume located on this cluster member to the target of a
+// replicator run, applying the replicator's bandwidth limit.
+func internalReplicatorMigrateVolume(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := internalReplicatorMigrateVolumePost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	pool, err := storagePools.LoadByName(s, req.Pool)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Check the volume exists on this cluster member.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.GetStoragePoolVolume(ctx, pool.ID(), req.Project, cluster.StoragePoolVolumeTypeCustom, req.Volume, true)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	opArgs, err := replicatorVolumeMigrationSourceOperationArgs(s, req.Project, s.ServerName, req.Pool, req.Volume, &req.Target, req.BandwidthLimit)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, opArgs)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
 func internalWaitReady(d *Daemon, r *http.Request) response.Response {
 	// Check that we're not shutting down.
 	isClosing := d.State().ShutdownCtx.Err() != nil
@@ -1190,3 +1344,12 @@ func internalSnapshotScheduledTask(d *Daemon, r *http.Request) response.Response
 
 	return response.EmptySyncResponse
 }
+
+func internalBackupScheduledTask(d *Daemon, r *http.Request) response.Response {
+	err := autoCreateAndPruneScheduledBackups(r.Context(), d.State())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -21,6 +22,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/locking"
 	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/network/acl"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
@@ -288,6 +290,17 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	wg.Wait()
 	close(instMetricsCh)
 
+	// Add the network ACL metrics so that they are cached along with the instance metrics, as getting the counters
+	// of the ACL rules requires querying the firewall.
+	for _, project := range projectsToFetch {
+		projectName := *project.Project
+		if newMetrics[projectName] == nil {
+			newMetrics[projectName] = metrics.NewMetricSet(nil)
+		}
+
+		networkACLMetrics(r.Context(), s, projectName, newMetrics[projectName])
+	}
+
 	// Put the new data in the global cache and in response.
 	metricsCacheLock.Lock()
 
@@ -451,6 +464,9 @@ func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *met
 		}
 	}
 
+	// Storage pool capacity
+	storagePoolCapacityMetrics(out)
+
 	// Daemon uptime
 	out.AddSamples(metrics.UptimeSeconds, metrics.Sample{Value: time.Since(s.StartTime).Seconds()})
 
@@ -487,3 +503,47 @@ func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *met
 
 	return out
 }
+
+// networkACLMetrics adds the counters of the network ACL rules of a project on this cluster member to the metric set.
+func networkACLMetrics(ctx context.Context, s *state.State, projectName string, out *metrics.MetricSet) {
+	var aclNames []string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		aclNames, err = tx.GetNetworkACLs(ctx, projectName)
+
+		return err
+	})
+	if err != nil {
+		logger.Warn("Failed getting network ACLs", logger.Ctx{"project": projectName, "err": err})
+		return
+	}
+
+	for _, aclName := range aclNames {
+		netACL, err := acl.LoadByName(ctx, s, projectName, aclName)
+		if err != nil {
+			logger.Warn("Failed loading network ACL", logger.Ctx{"project": projectName, "networkACL": aclName, "err": err})
+			continue
+		}
+
+		// Only get the counters of this member, as each member provides its own metrics.
+		aclState, err := netACL.GetState(ctx, request.ClientTypeNotifier)
+		if err != nil {
+			logger.Warn("Failed getting network ACL state", logger.Ctx{"project": projectName, "networkACL": aclName, "err": err})
+			continue
+		}
+
+		addRuleSamples := func(direction string, counters []api.NetworkACLRuleCounters) {
+			for i, ruleCounters := range counters {
+				labels := map[string]string{"project": projectName, "name": aclName, "direction": direction, "rule": strconv.Itoa(i)}
+
+				out.AddSamples(metrics.NetworkACLRuleBytesTotal, metrics.Sample{Labels: labels, Value: float64(ruleCounters.Bytes)})
+				out.AddSamples(metrics.NetworkACLRulePacketsTotal, metrics.Sample{Labels: labels, Value: float64(ruleCounters.Packets)})
+			}
+		}
+
+		addRuleSamples("egress", aclState.Egress)
+		addRuleSamples("ingress", aclState.Ingress)
+	}
+}
@@ -7,7 +7,12 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +25,7 @@ import (
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/operationtype"
 	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/request"
@@ -30,7 +36,10 @@ import (
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/ioprogress"
 	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/validate"
 	"github.com/canonical/lxd/shared/version"
 )
@@ -65,6 +74,37 @@ var replicatorStateCmd = APIEndpoint{
 	Put: APIEndpointAction{Handler: replicatorStatePut, AccessHandler: allowPermission(entity.TypeReplicator, auth.EntitlementCanEdit, "name")},
 }
 
+var replicatorRunsCmd = APIEndpoint{
+	Path:            "replicators/{name}/runs",
+	MetricsType:     entity.TypeReplicator,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: replicatorRunsGet, AccessHandler: allowPermission(entity.TypeReplicator, auth.EntitlementCanView, "name")},
+}
+
+var replicatorRunCmd = APIEndpoint{
+	Path:            "replicators/{name}/runs/{id}",
+	MetricsType:     entity.TypeReplicator,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: replicatorRunGet, AccessHandler: allowPermission(entity.TypeReplicator, auth.EntitlementCanView, "name")},
+}
+
+// replicatorRunsRetainDefault is the number of runs kept in the run history of a replicator when runs.retain is not set.
+const replicatorRunsRetainDefault = 10
+
+// Modes of the live.snapshot replicator configuration key.
+const (
+	replicatorLiveSnapshotDisabled        = "disabled"
+	replicatorLiveSnapshotCrashConsistent = "crash-consistent"
+	replicatorLiveSnapshotFrozen          = "frozen"
+)
+
+// replicatorLiveSnapshotName is the name of the temporary snapshot that running instances are replicated from.
+// It is reserved, so that the snapshot left behind by an interrupted run can be replaced without deleting a snapshot
+// created by a user.
+const replicatorLiveSnapshotName = "replicator-live"
+
 // swagger:operation GET /1.0/replicators replicators replicators_get
 //
 //	Get the replicators
@@ -376,6 +416,45 @@ func replicatorValidateConfig(ctx context.Context, s *state.State, config map[st
 		//  shortdesc: Cron expression for the replication schedule.
 		//  scope: global
 		"schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=bandwidth.limit)
+		// Specify the value in bytes per second with a unit suffix, for example, `100MB` for 100 megabytes per second.
+		// The limit applies to each instance transfer of a replicator run.
+		// If not set, transfers are not rate limited.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum transfer rate of a replicator run.
+		//  scope: global
+		"bandwidth.limit": validate.Optional(validate.IsSize),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=runs.retain)
+		// Older runs are deleted from the run history when a run finishes. At least one run must be kept.
+		// ---
+		//  type: integer
+		//  defaultdesc: `10`
+		//  shortdesc: Number of runs to keep in the run history.
+		//  scope: global
+		"runs.retain": validate.Optional(validate.IsInRange(1, math.MaxUint32)),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=live.snapshot)
+		// Controls how running instances are replicated. Possible values are:
+		//
+		// - `disabled`: The volumes of running instances are transferred while they are in use.
+		// - `crash-consistent`: Running instances are replicated from a temporary snapshot, which captures their
+		//   volumes as they would be after a power loss.
+		// - `frozen`: Like `crash-consistent`, but the instance is frozen while the temporary snapshot is taken.
+		//   Containers are frozen through the cgroup freezer. Virtual machines freeze the filesystems listed in
+		//   {config:option}`instance-miscellaneous:agent.freeze_mounts` through the `lxd-agent`, which must be running.
+		//   The root filesystem of a virtual machine is never frozen.
+		//
+		// The temporary snapshot is named `replicator-live`, which is reserved and can't be used for other snapshots.
+		// It is restored on the target cluster once the instance is replicated, and then deleted on both clusters.
+		// ---
+		//  type: string
+		//  defaultdesc: `disabled`
+		//  shortdesc: How running instances are replicated.
+		//  scope: global
+		"live.snapshot": validate.Optional(validate.IsOneOf(replicatorLiveSnapshotDisabled, replicatorLiveSnapshotCrashConsistent, replicatorLiveSnapshotFrozen)),
 	}
 
 	for k, v := range config {
@@ -598,6 +677,9 @@ func replicatorPost(d *Daemon, r *http.Request) response.Response {
 //	Triggers a replicator run using the specified action.
 //	The "restore" action requires all local project instances to be stopped;
 //	it returns 400 if any instance is running to prevent partial restores.
+//	The "promote" action promotes the local standby project to leader and starts its instances,
+//	performing a controlled failback from the target cluster if its project is still the leader.
+//	If the target cluster can't be reached, the project is only promoted if "force" is set.
 //
 //	---
 //	consumes:
@@ -643,7 +725,7 @@ func replicatorStatePut(d *Daemon, r *http.Request) response.Response {
 	}
 
 	switch req.Action {
-	case "start", "restore":
+	case "start", "restore", "promote":
 	default:
 		return response.BadRequest(fmt.Errorf("Unknown action %q", req.Action))
 	}
@@ -671,12 +753,48 @@ func replicatorStatePut(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	clusterLinkName := apiReplicator.Config["cluster"]
-	if clusterLinkName == "" {
+	if apiReplicator.Config["cluster"] == "" {
 		return response.BadRequest(fmt.Errorf("Replicator %q has no cluster link configured", name))
 	}
 
-	opArgs, err := prepareReplicatorRunOperation(r.Context(), s, projectName, name, clusterLinkName, restore, dbReplicator.Row.ID)
+	if req.Action == "promote" {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+			if err != nil {
+				return err
+			}
+
+			if dbProject.ReplicaMode != api.ReplicatorProjectModeStandby {
+				return api.StatusErrorf(http.StatusBadRequest, "Local project must be in standby mode to be promoted")
+			}
+
+			return nil
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		run := func(ctx context.Context, op *operations.Operation) error {
+			return replicatorPromote(ctx, s, op, apiReplicator, dbReplicator.Row.ID, req.Force)
+		}
+
+		replicatorURL := entity.ReplicatorURL(projectName, name)
+		op, err := operations.ScheduleUserOperationFromRequest(s, r, operations.OperationArgs{
+			ProjectName:       projectName,
+			EntityURL:         replicatorURL,
+			Type:              operationtype.ReplicatorPromote,
+			Class:             operationtype.OperationClassTask,
+			ConflictReference: replicatorURL.String(), // Prevents runs of the replicator during the promotion.
+			RunHook:           run,
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.OperationResponse(op)
+	}
+
+	opArgs, err := prepareReplicatorRunOperation(r.Context(), s, apiReplicator, dbReplicator.Row.ID, restore)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -986,6 +1104,222 @@ func replicatorStateGet(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponse(true, api.ReplicatorState{Status: status})
 }
 
+// swagger:operation GET /1.0/replicators/{name}/runs replicators replicator_runs_get
+//
+//	Get the replicator runs
+//
+//	Returns a list of the recorded runs of the replicator (URLs), most recent first.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/replicators/foo/runs/2?project=default",
+//	              "/1.0/replicators/foo/runs/1?project=default"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/replicators/{name}/runs?recursion=1 replicators replicator_runs_get_recursion1
+//
+//	Get the replicator runs
+//
+//	Returns a list of the recorded runs of the replicator (structs), most recent first.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of replicator runs
+//	          items:
+//	            $ref: "#/definitions/ReplicatorRun"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func replicatorRunsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := request.ProjectParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	name := r.PathValue("name")
+	var runs []dbCluster.ReplicatorRun
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbReplicator, err := dbCluster.GetReplicator(ctx, tx.Tx(), name, projectName)
+		if err != nil {
+			return err
+		}
+
+		runs, err = dbCluster.GetReplicatorRuns(ctx, tx.Tx(), dbReplicator.Row.ID, nil)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading runs of replicator %q: %w", name, err))
+	}
+
+	recursion, _ := util.IsRecursionRequest(r)
+	if recursion > 0 {
+		apiRuns := make([]*api.ReplicatorRun, 0, len(runs))
+		for _, run := range runs {
+			apiRuns = append(apiRuns, run.ToAPI())
+		}
+
+		return response.SyncResponse(true, apiRuns)
+	}
+
+	urls := make([]string, 0, len(runs))
+	for _, run := range runs {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "replicators", name, "runs", strconv.FormatInt(run.Row.ID, 10)).Project(projectName).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation GET /1.0/replicators/{name}/runs/{id} replicators replicator_run_get
+//
+//	Get the replicator run
+//
+//	Gets a specific recorded run of the replicator.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Replicator run
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ReplicatorRun"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func replicatorRunGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := request.ProjectParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	name := r.PathValue("name")
+	runID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid replicator run ID %q", r.PathValue("id")))
+	}
+
+	var runs []dbCluster.ReplicatorRun
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbReplicator, err := dbCluster.GetReplicator(ctx, tx.Tx(), name, projectName)
+		if err != nil {
+			return err
+		}
+
+		runs, err = dbCluster.GetReplicatorRuns(ctx, tx.Tx(), dbReplicator.Row.ID, &runID)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading run of replicator %q: %w", name, err))
+	}
+
+	if len(runs) == 0 {
+		return response.NotFound(fmt.Errorf("Replicator run %d not found", runID))
+	}
+
+	return response.SyncResponse(true, runs[0].ToAPI())
+}
+
 // runScheduledReplicatorsTask returns a background task that checks replicator schedules every minute
 // and triggers replication for any replicator whose cron expression matches the current time.
 func runScheduledReplicatorsTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
@@ -1011,45 +1345,119 @@ func runScheduledReplicatorsTask(stateFunc func() *state.State) (task.Func, task
 	return f, schedule
 }
 
-// prepareReplicatorRunOperation builds the operation used to run a replicator.
-func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectName string, name string, clusterLinkName string, restore bool, replicatorID int64) (operations.OperationArgs, error) {
-	// Load all DB state in a single transaction before any network I/O.
-	var clusterLink *api.ClusterLink
-	var targetCert *x509.Certificate
-	var sourceProject *api.Project
-	var allInsts []instance.Instance
-	var nodeAddressByName map[string]string
+func init() {
+	operations.RegisterDurableOperationRunHook(operationtype.ReplicatorRunVolumeForward, replicatorRunVolumeForwardOperationRunHook)
+	operations.RegisterDurableOperationRunHook(operationtype.ReplicatorRunProjectSync, replicatorRunProjectSyncOperationRunHook)
+	operations.RegisterDurableOperationRunHook(operationtype.ReplicatorRunInstanceForward, replicatorRunInstanceForwardOperationRunHook)
+	operations.RegisterDurableOperationRunHook(operationtype.ReplicatorRunInstanceRestore, replicatorRunInstanceRestoreOperationRunHook)
+	operations.RegisterDurableOperationRunHook(operationtype.ReplicatorRunReconcile, replicatorRunReconcileOperationRunHook)
+	operations.RegisterDurableOperationRunHook(operationtype.ReplicatorFinalize, replicatorFinalizeOperationRunHook)
+}
+
+// Inputs of the durable child operations of a replicator run.
+const (
+	operationInputKeyReplicatorName     operations.InputKey = "replicator"
+	operationInputKeyReplicatorInstance operations.InputKey = "instance"
+	operationInputKeyReplicatorPool     operations.InputKey = "pool"
+	operationInputKeyReplicatorVolume   operations.InputKey = "volume"
+	operationInputKeyReplicatorLocation operations.InputKey = "location"
+	operationInputKeyReplicatorSkipped  operations.InputKey = "skipped"
+)
+
+// replicatorRun holds the state used by the operations of a replicator run. The operations are durable, so each of
+// them loads it from the database when it runs rather than relying on state captured when the run was prepared.
+type replicatorRun struct {
+	s *state.State
+
+	replicator       *api.Replicator
+	replicatorID     int64
+	bandwidthLimit   int64
+	liveSnapshotMode string
+
+	clusterLink       *api.ClusterLink
+	targetCert        *x509.Certificate
+	sourceProject     *api.Project
+	nodeAddressByName map[string]string
+	checkpoints       map[string]dbCluster.ReplicatorInstanceRow
+}
+
+// replicatorLoad loads the replicator with the given name along with its database ID.
+func replicatorLoad(ctx context.Context, s *state.State, projectName string, name string) (*api.Replicator, int64, error) {
+	var replicator *api.Replicator
+	var replicatorID int64
 	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-		var err error
-		_, clusterLink, targetCert, err = lxdCluster.LoadClusterLinkAndCert(ctx, tx.Tx(), clusterLinkName)
+		dbReplicator, err := dbCluster.GetReplicator(ctx, tx.Tx(), name, projectName)
 		if err != nil {
 			return err
 		}
 
-		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		config, err := dbCluster.ReplicatorsConfigStore().GetByEntityIDs(ctx, tx.Tx(), dbReplicator.Row.ID)
 		if err != nil {
-			return err
+			return fmt.Errorf("Failed loading replicator config: %w", err)
 		}
 
-		sourceProject, err = dbProject.ToAPI(ctx, tx.Tx())
-		if err != nil {
-			return err
-		}
+		replicator = dbReplicator.ToAPI(config)
+		replicatorID = dbReplicator.Row.ID
+		return nil
+	})
+	if err != nil {
+		return nil, -1, err
+	}
 
-		// Load all instances in the project across all cluster members as
-		// instance.Instance. instance.Load() only performs in-memory config
-		// expansion and is safe for non-local instances.
-		err = tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
-			inst, err := instance.Load(s, dbInst, p)
-			if err != nil {
-				return fmt.Errorf("Failed loading instance %q: %w", dbInst.Name, err)
-			}
+	return replicator, replicatorID, nil
+}
 
-			allInsts = append(allInsts, inst)
-			return nil
-		}, dbCluster.InstanceFilter{Project: &projectName})
+// replicatorRunsRetain returns the number of runs of the replicator to keep in its history.
+func replicatorRunsRetain(replicator *api.Replicator) (int, error) {
+	if replicator.Config["runs.retain"] == "" {
+		return replicatorRunsRetainDefault, nil
+	}
+
+	runsRetain, err := strconv.Atoi(replicator.Config["runs.retain"])
+	if err != nil {
+		return -1, fmt.Errorf("Failed parsing replicator run retention: %w", err)
+	}
+
+	return runsRetain, nil
+}
+
+// replicatorLoadRun loads the state used by the operations of a run of the given replicator.
+func replicatorLoadRun(ctx context.Context, s *state.State, replicator *api.Replicator, replicatorID int64) (*replicatorRun, error) {
+	run := &replicatorRun{
+		s:                s,
+		replicator:       replicator,
+		replicatorID:     replicatorID,
+		liveSnapshotMode: replicator.Config["live.snapshot"],
+	}
+
+	var err error
+	if replicator.Config["bandwidth.limit"] != "" {
+		run.bandwidthLimit, err = units.ParseByteSizeString(replicator.Config["bandwidth.limit"])
 		if err != nil {
-			return fmt.Errorf("Failed listing project instances: %w", err)
+			return nil, fmt.Errorf("Failed parsing replicator bandwidth limit: %w", err)
+		}
+	}
+
+	if run.liveSnapshotMode == "" {
+		run.liveSnapshotMode = replicatorLiveSnapshotDisabled
+	}
+
+	// Load all DB state in a single transaction before any network I/O.
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		_, run.clusterLink, run.targetCert, err = lxdCluster.LoadClusterLinkAndCert(ctx, tx.Tx(), replicator.Config["cluster"])
+		if err != nil {
+			return err
+		}
+
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), replicator.Project)
+		if err != nil {
+			return err
+		}
+
+		run.sourceProject, err = dbProject.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
 		}
 
 		// Pre-load node addresses for forwarding to other cluster members.
@@ -1058,41 +1466,135 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 			return fmt.Errorf("Failed listing cluster members: %w", err)
 		}
 
-		nodeAddressByName = make(map[string]string, len(nodes))
+		run.nodeAddressByName = make(map[string]string, len(nodes))
 		for _, node := range nodes {
-			nodeAddressByName[node.Name] = node.Address
+			run.nodeAddressByName[node.Name] = node.Address
+		}
+
+		// Load the per-instance checkpoints of previous runs.
+		replicatorInstances, err := dbCluster.GetReplicatorInstances(ctx, tx.Tx(), &replicatorID)
+		if err != nil {
+			return fmt.Errorf("Failed loading replicator checkpoints: %w", err)
+		}
+
+		run.checkpoints = make(map[string]dbCluster.ReplicatorInstanceRow, len(replicatorInstances))
+		for _, replicatorInstance := range replicatorInstances {
+			run.checkpoints[replicatorInstance.InstanceName] = replicatorInstance.Row
 		}
 
 		return nil
 	})
 	if err != nil {
-		return operations.OperationArgs{}, fmt.Errorf("Failed loading replicator state: %w", err)
+		return nil, fmt.Errorf("Failed loading replicator state: %w", err)
+	}
+
+	return run, nil
+}
+
+// replicatorRunFromOperation loads the state of the replicator run that the given child operation belongs to.
+func replicatorRunFromOperation(ctx context.Context, op *operations.Operation) (*replicatorRun, error) {
+	name, err := operations.GetOperationInputValue[string](op, operationInputKeyReplicatorName)
+	if err != nil {
+		return nil, err
 	}
 
-	clusterCert := s.Endpoints.NetworkCert()
+	replicator, replicatorID, err := replicatorLoad(ctx, op.State(), op.Project(), name)
+	if err != nil {
+		return nil, err
+	}
 
+	return replicatorLoadRun(ctx, op.State(), replicator, replicatorID)
+}
+
+// connectTarget connects to the target cluster of the replicator, using the replicated project.
+func (r *replicatorRun) connectTarget(ctx context.Context) (lxd.InstanceServer, error) {
 	// Mutual TLS is safe to assume here: replicatorValidateConfig rejects public cluster links, which
 	// are the only type that connects without presenting a client certificate.
-	connArgs := lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert)
-	targetClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, connArgs)
+	dstClient, err := lxdCluster.ConnectCluster(ctx, *r.clusterLink, lxdCluster.GetClusterLinkConnectionArgs(r.s.Endpoints.NetworkCert(), r.targetCert))
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to target cluster: %w", err)
+	}
+
+	return dstClient.UseProject(r.replicator.Project), nil
+}
+
+// targetCertPEM returns the PEM encoded certificate of the target cluster.
+func (r *replicatorRun) targetCertPEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: r.targetCert.Raw}))
+}
+
+// replicatorRunChildOperationArgs returns the arguments of a durable child operation of a replicator run.
+// Child operations are only given the replicator name and the provided inputs, and load everything else when they
+// run. This allows them to be restarted on another cluster member. The stage is assigned by replicatorRunStages.
+func replicatorRunChildOperationArgs(projectName string, name string, opType operationtype.Type, entityURL *api.URL, inputs map[operations.InputKey]any) (*operations.OperationArgs, error) {
+	args := &operations.OperationArgs{
+		ProjectName: projectName,
+		EntityURL:   entityURL,
+		Type:        opType,
+		Class:       operationtype.OperationClassDurable,
+	}
+
+	err := args.SetInputValue(operationInputKeyReplicatorName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range inputs {
+		err = args.SetInputValue(key, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+// prepareReplicatorRunOperation builds the operation used to run a replicator.
+// The replicator must be loaded before its last run status is updated for the new run, because the
+// previous run status decides whether the new run resumes an interrupted run.
+func prepareReplicatorRunOperation(ctx context.Context, s *state.State, replicator *api.Replicator, replicatorID int64, restore bool) (operations.OperationArgs, error) {
+	projectName := replicator.Project
+	name := replicator.Name
+
+	run, err := replicatorLoadRun(ctx, s, replicator, replicatorID)
+	if err != nil {
+		return operations.OperationArgs{}, err
+	}
+
+	// Load all instances in the project across all cluster members as
+	// instance.Instance. instance.Load() only performs in-memory config
+	// expansion and is safe for non-local instances.
+	var allInsts []instance.Instance
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q: %w", dbInst.Name, err)
+			}
+
+			allInsts = append(allInsts, inst)
+			return nil
+		}, dbCluster.InstanceFilter{Project: &projectName})
+	})
 	if err != nil {
-		return operations.OperationArgs{}, fmt.Errorf("Failed connecting to target cluster: %w", err)
+		return operations.OperationArgs{}, fmt.Errorf("Failed listing project instances: %w", err)
 	}
 
-	targetClient = targetClient.UseProject(projectName)
+	targetClient, err := run.connectTarget(ctx)
+	if err != nil {
+		return operations.OperationArgs{}, err
+	}
 
 	targetProject, _, err := targetClient.GetProject(projectName)
 	if err != nil {
 		return operations.OperationArgs{}, fmt.Errorf("Failed getting target project: %w", err)
 	}
 
-	err = validateReplicatorModes(sourceProject.ReplicaMode, targetProject.ReplicaMode, restore)
+	err = validateReplicatorModes(run.sourceProject.ReplicaMode, targetProject.ReplicaMode, restore)
 	if err != nil {
 		return operations.OperationArgs{}, api.StatusErrorf(http.StatusBadRequest, "%s", err)
 	}
 
-	targetCertPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: targetCert.Raw}))
-
 	// In restore mode, all project instances across all cluster members must be stopped
 	// before proceeding. The restore operation refreshes each existing instance from the
 	// current leader cluster and creates any that only exist on the leader; a running instance
@@ -1122,323 +1624,594 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 	}
 
 	replicatorURL := entity.ReplicatorURL(projectName, name)
-	projectURL := entity.ProjectURL(projectName)
 
-	// Forward replication: iterate over all loaded instances directly.
-	if !restore {
-		childArgs := make([]*operations.OperationArgs, 0, len(allInsts))
+	var childArgs []*operations.OperationArgs
+	if restore {
+		childArgs, err = replicatorPlanRestoreRun(projectName, name, iterNames)
+		if err != nil {
+			return operations.OperationArgs{}, err
+		}
+	} else {
+		objects, err := replicatorLoadProjectObjects(ctx, s, run.sourceProject)
+		if err != nil {
+			return operations.OperationArgs{}, fmt.Errorf("Failed loading project objects: %w", err)
+		}
+
+		// If the previous run failed or was interrupted, resume it by skipping the instances that it
+		// already replicated. Only the immediately preceding run is resumed, so instances that keep
+		// failing cannot prevent the others from being refreshed on later runs.
+		var resumeAfter time.Time
+		if replicator.LastRunStatus == api.ReplicatorStatusFailed || replicator.LastRunStatus == api.ReplicatorStatusRunning {
+			resumeAfter = replicator.LastRunAt
+		}
 
-		var stage uint16
+		instNames := make([]string, 0, len(allInsts))
 		for _, inst := range allInsts {
-			memberAddress := nodeAddressByName[inst.Location()]
+			instNames = append(instNames, inst.Name())
+		}
 
-			copyFunc := func(ctx context.Context, op *operations.Operation) error {
-				dstClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
-				if err != nil {
-					return fmt.Errorf("Failed connecting to target cluster: %w", err)
-				}
+		childArgs, err = replicatorPlanForwardRun(projectName, name, objects.volumes, instNames, run.checkpoints, resumeAfter)
+		if err != nil {
+			return operations.OperationArgs{}, err
+		}
+	}
 
-				dstClient = dstClient.UseProject(projectName)
+	return operations.OperationArgs{
+		ProjectName:       projectName,
+		EntityURL:         replicatorURL,
+		Type:              operationtype.ReplicatorRun,
+		Class:             operationtype.OperationClassDurable,
+		ConflictReference: replicatorURL.String(), // Prevents concurrent runs; paired with ConflictActionFail on the operation type to enforce cluster-wide exclusivity.
+		Children:          childArgs,
+	}, nil
+}
 
-				return replicateInstance(ctx, s, op, inst, memberAddress, dstClient, targetCertPEM)
-			}
+// replicatorRunStages assigns stages to the child operations of a replicator run.
+// Child operation stages must be consecutive, so a stage to which no child operation was added is not counted.
+type replicatorRunStages struct {
+	children []*operations.OperationArgs
+	stage    uint16
+	used     bool
+}
 
-			childArgs = append(childArgs, &operations.OperationArgs{
-				ProjectName: projectName,
-				EntityURL:   entity.InstanceURL(projectName, inst.Name()),
-				Type:        operationtype.ReplicatorRunInstanceForward,
-				Class:       operationtype.OperationClassTask,
-				RunHook:     copyFunc,
-				Stage:       stage,
-			})
+// add adds a child operation to the current stage.
+func (r *replicatorRunStages) add(args *operations.OperationArgs) {
+	args.Stage = r.stage
+	r.children = append(r.children, args)
+	r.used = true
+}
+
+// next starts a new stage, unless the current stage is empty.
+func (r *replicatorRunStages) next() {
+	if r.used {
+		r.stage++
+		r.used = false
+	}
+}
+
+// replicatorPlanForwardRun returns the child operations of a forward replicator run.
+// Instances whose checkpoint is not before resumeAfter were already replicated by the interrupted run being resumed
+// and are skipped. A zero resumeAfter replicates all instances.
+func replicatorPlanForwardRun(projectName string, name string, volumes []*db.StorageVolume, instNames []string, checkpoints map[string]dbCluster.ReplicatorInstanceRow, resumeAfter time.Time) ([]*operations.OperationArgs, error) {
+	replicatorURL := entity.ReplicatorURL(projectName, name)
+	projectURL := entity.ProjectURL(projectName)
+	stages := &replicatorRunStages{}
+
+	// Custom volumes are replicated first, as profiles and instances on the target cluster can only reference
+	// volumes that exist there. Profiles and network objects are then synced before the instances using them.
+	for _, vol := range volumes {
+		args, err := replicatorRunChildOperationArgs(projectName, name, operationtype.ReplicatorRunVolumeForward, entity.StorageVolumeURL(projectName, vol.Location, vol.Pool, vol.Type, vol.Name), map[operations.InputKey]any{
+			operationInputKeyReplicatorPool:     vol.Pool,
+			operationInputKeyReplicatorVolume:   vol.Name,
+			operationInputKeyReplicatorLocation: vol.Location,
+		})
+		if err != nil {
+			return nil, err
 		}
 
-		stage++
-		childArgs = append(childArgs, replicatorFinalizeOperationArgs(s, projectName, replicatorURL, replicatorID, stage))
+		stages.add(args)
+	}
 
-		return operations.OperationArgs{
-			ProjectName:       projectName,
-			EntityURL:         replicatorURL,
-			Type:              operationtype.ReplicatorRun,
-			Class:             operationtype.OperationClassTask,
-			ConflictReference: replicatorURL.String(), // Prevents concurrent runs; paired with ConflictActionFail on the operation type to enforce cluster-wide exclusivity.
-			Children:          childArgs,
-		}, nil
+	stages.next()
+	args, err := replicatorRunChildOperationArgs(projectName, name, operationtype.ReplicatorRunProjectSync, projectURL, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	// Restore mode: iterate over the current leader cluster's instance list.
-	childArgs := make([]*operations.OperationArgs, 0, len(iterNames))
+	stages.add(args)
+
+	stages.next()
+	var skipped []string
+	for _, instName := range instNames {
+		checkpoint := checkpoints[instName]
+		if !resumeAfter.IsZero() && !checkpoint.RunDate.IsZero() && !checkpoint.RunDate.Before(resumeAfter) {
+			logger.Info("Skipping instance already replicated by the interrupted replicator run", logger.Ctx{"replicator": name, "project": projectName, "instance": instName})
+			skipped = append(skipped, instName)
+			continue
+		}
+
+		args, err := replicatorRunChildOperationArgs(projectName, name, operationtype.ReplicatorRunInstanceForward, entity.InstanceURL(projectName, instName), map[operations.InputKey]any{
+			operationInputKeyReplicatorInstance: instName,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		stages.add(args)
+	}
+
+	// Items deleted from the local project are only removed from the target once everything else was replicated.
+	stages.next()
+	args, err = replicatorRunChildOperationArgs(projectName, name, operationtype.ReplicatorRunReconcile, projectURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stages.add(args)
+
+	// The skipped instances are recorded in the run history by the finalize operation.
+	stages.next()
+	args, err = replicatorRunChildOperationArgs(projectName, name, operationtype.ReplicatorFinalize, replicatorURL, map[operations.InputKey]any{
+		operationInputKeyReplicatorSkipped: skipped,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stages.add(args)
+
+	return stages.children, nil
+}
+
+// replicatorPlanRestoreRun returns the child operations of a replicator run in restore mode, which restores the
+// given instances of the current leader cluster.
+func replicatorPlanRestoreRun(projectName string, name string, instNames []string) ([]*operations.OperationArgs, error) {
+	projectURL := entity.ProjectURL(projectName)
+	stages := &replicatorRunStages{}
+
+	for _, instName := range instNames {
+		// The instance may exist only on the current leader cluster, in which case this operation creates it
+		// locally and there is nothing to name yet. The project is the primary entity here, and the instance
+		// URL reaches clients through the metadata.
+		args, err := replicatorRunChildOperationArgs(projectName, name, operationtype.ReplicatorRunInstanceRestore, projectURL, map[operations.InputKey]any{
+			operationInputKeyReplicatorInstance: instName,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		args.Metadata = map[string]any{
+			api.MetadataEntityURL: entity.InstanceURL(projectName, instName).String(),
+		}
+
+		stages.add(args)
+	}
+
+	stages.next()
+	args, err := replicatorRunChildOperationArgs(projectName, name, operationtype.ReplicatorFinalize, entity.ReplicatorURL(projectName, name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stages.add(args)
+
+	return stages.children, nil
+}
+
+// replicatorRunInstanceForwardOperationRunHook is the run hook of the durable replicator run child operation that
+// replicates an instance to the target cluster.
+func replicatorRunInstanceForwardOperationRunHook(ctx context.Context, op *operations.Operation) error {
+	s := op.State()
+
+	run, err := replicatorRunFromOperation(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	instName, err := operations.GetOperationInputValue[string](op, operationInputKeyReplicatorInstance)
+	if err != nil {
+		return err
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, op.Project(), instName)
+	if err != nil {
+		return fmt.Errorf("Failed loading instance %q: %w", instName, err)
+	}
+
+	dstClient, err := run.connectTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The checkpoint is loaded when the operation runs, so that a restarted operation uses the snapshot recorded by
+	// an interrupted attempt that completed the transfer as the base of the incremental transfer.
+	checkpoint := run.checkpoints[instName]
+
+	err = replicateInstance(ctx, s, op, inst, run.nodeAddressByName[inst.Location()], dstClient, run.targetCertPEM(), checkpoint.SnapshotName, run.bandwidthLimit, run.liveSnapshotMode)
+	if err != nil {
+		return err
+	}
+
+	return replicatorCheckpointInstance(s, run.replicatorID, inst)
+}
+
+// replicatorRunInstanceRestoreOperationRunHook is the run hook of the durable replicator run child operation that
+// restores an instance from the current leader cluster.
+func replicatorRunInstanceRestoreOperationRunHook(ctx context.Context, op *operations.Operation) error {
+	s := op.State()
+	projectName := op.Project()
+
+	run, err := replicatorRunFromOperation(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	instName, err := operations.GetOperationInputValue[string](op, operationInputKeyReplicatorInstance)
+	if err != nil {
+		return err
+	}
+
+	dstClient, err := run.connectTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	// In restore mode the local copy is stale; fetch current metadata from
+	// the current leader cluster so the restore uses up-to-date config/state.
+	freshInst, _, err := dstClient.GetInstance(instName)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			// Instance was deleted on the current leader cluster after failover; skip it rather
+			// than failing the whole run, since the deletion is intentional.
+			logger.Warn("Skipping restore of instance deleted on current leader cluster", logger.Ctx{"instance": instName})
+			return nil
+		}
+
+		return fmt.Errorf("Failed getting instance %q from current leader cluster: %w", instName, err)
+	}
 
 	// Use our cluster certificate so the leader can verify TLS when
 	// pushing data back to us.
-	localCertPEM := string(clusterCert.PublicKey())
+	localCertPEM := string(s.Endpoints.NetworkCert().PublicKey())
+
+	// If the instance lives on a remote cluster member, forward the restore
+	// migration to that member so the refresh runs where the storage volume is.
+	// Instances on the local member (including unclustered servers) are handled
+	// directly below. This mirrors the logic in replicateInstance.
+	var memberAddress string
+	inst, err := instance.LoadByProjectAndName(s, projectName, instName)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return fmt.Errorf("Failed loading instance %q: %w", instName, err)
+	} else if err == nil && inst.Location() != s.ServerName {
+		memberAddress = run.nodeAddressByName[inst.Location()]
+	}
+
+	if memberAddress != "" {
+		memberClient, err := lxdCluster.Connect(ctx, memberAddress, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+		if err != nil {
+			return fmt.Errorf("Failed connecting to hosting cluster member for instance %q: %w", instName, err)
+		}
 
-	var stage uint16
-	for _, instName := range iterNames {
-		copyFunc := func(ctx context.Context, op *operations.Operation) error {
-			dstClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
-			if err != nil {
-				return fmt.Errorf("Failed connecting to target cluster: %w", err)
+		memberClient = memberClient.UseProject(projectName)
+
+		// Set up a push-mode migration sink on the hosting cluster member.
+		restoreOp, err := memberClient.CreateInstance(api.InstancesPost{
+			Name:        instName,
+			InstancePut: freshInst.Writable(),
+			Type:        api.InstanceType(freshInst.Type),
+			Source: api.InstanceSource{
+				Type:    api.SourceTypeMigration,
+				Mode:    "push",
+				Refresh: true,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("Failed requesting restore on hosting cluster member for instance %q: %w", instName, err)
+		}
+
+		restoreOpCancelled := false
+		defer func() {
+			if !restoreOpCancelled {
+				_ = restoreOp.Cancel()
 			}
+		}()
 
-			dstClient = dstClient.UseProject(projectName)
+		restoreOpAPI := restoreOp.Get()
+		restoreSecrets, err := restoreOpAPI.WebsocketSecrets()
+		if err != nil {
+			return fmt.Errorf("Failed getting websocket secrets from hosting cluster member for instance %q: %w", instName, err)
+		}
 
-			// In restore mode the local copy is stale; fetch current metadata from
-			// the current leader cluster so the restore uses up-to-date config/state.
-			freshInst, _, err := dstClient.GetInstance(instName)
-			if err != nil {
-				if api.StatusErrorCheck(err, http.StatusNotFound) {
-					// Instance was deleted on the current leader cluster after failover; skip it rather
-					// than failing the whole run, since the deletion is intentional.
-					logger.Warn("Skipping restore of instance deleted on current leader cluster", logger.Ctx{"instance": instName})
-					return nil
-				}
+		// Tell the current leader cluster to push-migrate the instance to the hosting cluster member's sink.
+		remoteMigrateOp, err := dstClient.MigrateInstance(instName, api.InstancePost{
+			Migration: true,
+			Target: &api.InstancePostTarget{
+				Operation:   restoreOp.URL().String(),
+				Websockets:  restoreSecrets,
+				Certificate: localCertPEM,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("Failed starting push migration on current leader cluster for instance %q: %w", instName, err)
+		}
 
-				return fmt.Errorf("Failed getting instance %q from current leader cluster: %w", instName, err)
-			}
+		restoreOpCancelled = true
 
-			// If the instance lives on a remote cluster member, forward the restore
-			// migration to that member so the refresh runs where the storage volume is.
-			// Instances on the local member (including unclustered servers) are handled
-			// directly below. This mirrors the logic in replicateInstance.
-			var memberAddress string
-			for _, inst := range allInsts {
-				if inst.Name() == instName {
-					if inst.Location() != s.ServerName {
-						memberAddress = nodeAddressByName[inst.Location()]
-					}
+		err = remoteMigrateOp.Wait()
+		if err != nil {
+			return fmt.Errorf("Restore of instance %q failed on current leader cluster: %w", instName, err)
+		}
 
-					break
-				}
-			}
+		return restoreOp.Wait()
+	}
 
-			if memberAddress != "" {
-				memberClient, err := lxdCluster.Connect(ctx, memberAddress, s.Endpoints.NetworkCert(), s.ServerCert(), true)
-				if err != nil {
-					return fmt.Errorf("Failed connecting to hosting cluster member for instance %q: %w", instName, err)
-				}
+	// Load profiles for the instance to pass to the migration sink.
+	var profiles []api.Profile
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		profiles, err = instanceProfilesFromNames(ctx, tx, projectName, freshInst.Profiles)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading profiles for instance %q: %w", instName, err)
+	}
+
+	// Set up a push-mode migration sink locally so the leader pushes data to us.
+	migrateReq := &api.InstancesPost{
+		InstancePut: api.InstancePut{
+			Architecture: freshInst.Architecture,
+			Config:       freshInst.Config,
+			Devices:      freshInst.Devices,
+			Description:  freshInst.Description,
+			Ephemeral:    freshInst.Ephemeral,
+			Profiles:     freshInst.Profiles,
+			Stateful:     freshInst.Stateful,
+		},
+		Name: instName,
+		Type: api.InstanceType(freshInst.Type),
+		Source: api.InstanceSource{
+			Type:    api.SourceTypeMigration,
+			Mode:    "push",
+			Refresh: true,
+		},
+	}
 
-				memberClient = memberClient.UseProject(projectName)
-
-				// Set up a push-mode migration sink on the hosting cluster member.
-				restoreOp, err := memberClient.CreateInstance(api.InstancesPost{
-					Name:        instName,
-					InstancePut: freshInst.Writable(),
-					Type:        api.InstanceType(freshInst.Type),
-					Source: api.InstanceSource{
-						Type:    api.SourceTypeMigration,
-						Mode:    "push",
-						Refresh: true,
-					},
-				})
-				if err != nil {
-					return fmt.Errorf("Failed requesting restore on hosting cluster member for instance %q: %w", instName, err)
-				}
+	result, err := prepareInstanceMigrationSink(ctx, s, projectName, profiles, migrateReq, "")
+	if err != nil {
+		return fmt.Errorf("Failed preparing migration sink for instance %q: %w", instName, err)
+	}
 
-				restoreOpCancelled := false
-				defer func() {
-					if !restoreOpCancelled {
-						_ = restoreOp.Cancel()
-					}
-				}()
+	defer result.revert.Fail()
 
-				restoreOpAPI := restoreOp.Get()
-				restoreSecrets, err := restoreOpAPI.WebsocketSecrets()
-				if err != nil {
-					return fmt.Errorf("Failed getting websocket secrets from hosting cluster member for instance %q: %w", instName, err)
-				}
+	// Schedule the sink operation so it gets an ID and can accept websocket connections.
+	sinkOpArgs := operations.OperationArgs{
+		ProjectName: projectName,
+		EntityURL:   api.NewURL().Path(version.APIVersion, "projects", projectName),
+		Type:        operationtype.InstanceCreate,
+		Class:       operationtype.OperationClassWebsocket,
+		Metadata:    result.sink.Metadata(),
+		ConnectHook: result.sink.Connect,
+		RunHook:     result.run,
+	}
+
+	var sinkOp *operations.Operation
+	if op.Requestor() != nil {
+		sinkOp, err = operations.ScheduleUserOperationFromOperation(s, op, sinkOpArgs)
+	} else {
+		sinkOp, err = operations.ScheduleServerOperation(s, sinkOpArgs)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed scheduling migration sink operation for instance %q: %w", instName, err)
+	}
+
+	_, sinkOpAPI := sinkOp.Render()
+	sinkSecrets, err := sinkOpAPI.WebsocketSecrets()
+	if err != nil {
+		return fmt.Errorf("Failed getting websocket secrets from local sink for instance %q: %w", instName, err)
+	}
+
+	// Build the operation URL using a reachable address for this server.
+	// For clustered members the address from the nodes table is already a
+	// concrete, registered address. For unclustered servers the nodes table
+	// stores the sentinel "0.0.0.0", so fall back to the configured HTTPS
+	// address. Return an error if we still cannot determine a concrete address,
+	// because the leader would not be able to reach us.
+	localAddress := run.nodeAddressByName[s.ServerName]
+	if util.IsWildCardAddress(localAddress) {
+		localAddress = s.LocalConfig.ClusterAddress()
+		if localAddress == "" {
+			localAddress = s.LocalConfig.HTTPSAddress()
+		}
+
+		if util.IsWildCardAddress(localAddress) || localAddress == "" {
+			_ = sinkOp.Cancel()
+			return errors.New("Cannot restore to this server: configure a concrete address using cluster.https_address or core.https_address")
+		}
+	}
+
+	sinkOpURL := "https://" + localAddress + sinkOp.URL()
+
+	// Tell the current leader cluster to push-migrate the instance to our local sink.
+	remoteMigrateOp, err := dstClient.MigrateInstance(instName, api.InstancePost{
+		Migration: true,
+		Target: &api.InstancePostTarget{
+			Operation:   sinkOpURL,
+			Websockets:  sinkSecrets,
+			Certificate: localCertPEM,
+		},
+	})
+	if err != nil {
+		_ = sinkOp.Cancel()
+		return fmt.Errorf("Failed starting push migration on current leader cluster for instance %q: %w", instName, err)
+	}
+
+	remoteErr := remoteMigrateOp.Wait()
+	if remoteErr != nil {
+		_ = sinkOp.Cancel()
+		return fmt.Errorf("Restore of instance %q failed on current leader cluster: %w", instName, remoteErr)
+	}
+
+	sinkErr := sinkOp.Wait(context.Background())
+	if sinkErr != nil {
+		return fmt.Errorf("Restore of instance %q failed: %w", instName, sinkErr)
+	}
+
+	result.revert.Success()
+	return nil
+}
+
+// replicatorFinalizeOperationRunHook is the run hook of the durable replicator run child operation that records the
+// outcome of the run once all other child operations have completed.
+func replicatorFinalizeOperationRunHook(ctx context.Context, op *operations.Operation) error {
+	s := op.State()
+	projectName := op.Project()
+
+	name, err := operations.GetOperationInputValue[string](op, operationInputKeyReplicatorName)
+	if err != nil {
+		return err
+	}
 
-				// Tell the current leader cluster to push-migrate the instance to the hosting cluster member's sink.
-				remoteMigrateOp, err := dstClient.MigrateInstance(instName, api.InstancePost{
-					Migration: true,
-					Target: &api.InstancePostTarget{
-						Operation:   restoreOp.URL().String(),
-						Websockets:  restoreSecrets,
-						Certificate: localCertPEM,
-					},
-				})
-				if err != nil {
-					return fmt.Errorf("Failed starting push migration on current leader cluster for instance %q: %w", instName, err)
-				}
+	replicator, replicatorID, err := replicatorLoad(ctx, s, projectName, name)
+	if err != nil {
+		return err
+	}
 
-				restoreOpCancelled = true
+	runsRetain, err := replicatorRunsRetain(replicator)
+	if err != nil {
+		return err
+	}
 
-				err = remoteMigrateOp.Wait()
-				if err != nil {
-					return fmt.Errorf("Restore of instance %q failed on current leader cluster: %w", instName, err)
-				}
+	_, parentOp := op.Parent().Render()
+	run := dbCluster.ReplicatorRunRow{
+		ReplicatorID: replicatorID,
+		StartDate:    parentOp.CreatedAt,
+		EndDate:      time.Now(),
+		Status:       api.ReplicatorStatusCompleted,
+	}
 
-				return restoreOp.Wait()
-			}
+	// Iterate over all operations for the bulk replicator run and record the outcome of each replicated item.
+	// If any operations (that are not this one) have failed, then the replicator run has failed overall.
+	var runInstances []dbCluster.ReplicatorRunInstanceRow
+	failedInstances := map[string]string{}
+	for _, child := range op.Parent().Children() {
+		if child.ID() == op.ID() {
+			continue
+		}
 
-			// Load profiles for the instance to pass to the migration sink.
-			var profiles []api.Profile
-			err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-				profiles, err = instanceProfilesFromNames(ctx, tx, projectName, freshInst.Profiles)
-				return err
-			})
-			if err != nil {
-				return fmt.Errorf("Failed loading profiles for instance %q: %w", instName, err)
-			}
+		_, childOp := child.Render()
 
-			// Set up a push-mode migration sink locally so the leader pushes data to us.
-			migrateReq := &api.InstancesPost{
-				InstancePut: api.InstancePut{
-					Architecture: freshInst.Architecture,
-					Config:       freshInst.Config,
-					Devices:      freshInst.Devices,
-					Description:  freshInst.Description,
-					Ephemeral:    freshInst.Ephemeral,
-					Profiles:     freshInst.Profiles,
-					Stateful:     freshInst.Stateful,
-				},
-				Name: instName,
-				Type: api.InstanceType(freshInst.Type),
-				Source: api.InstanceSource{
-					Type:    api.SourceTypeMigration,
-					Mode:    "push",
-					Refresh: true,
-				},
-			}
+		// Child operations that don't replicate an instance or a custom volume are identified by their description.
+		itemName, itemLocation := replicatorRunItem(childOp.Metadata)
+		if itemName == "" {
+			itemName = childOp.Description
+		}
 
-			result, err := prepareInstanceMigrationSink(ctx, s, projectName, profiles, migrateReq, "")
-			if err != nil {
-				return fmt.Errorf("Failed preparing migration sink for instance %q: %w", instName, err)
-			}
+		runInstance := dbCluster.ReplicatorRunInstanceRow{
+			InstanceName:     itemName,
+			Location:         itemLocation,
+			Status:           api.ReplicatorStatusCompleted,
+			BytesTransferred: replicatorBytesTransferred(childOp.Metadata),
+		}
 
-			defer result.revert.Fail()
-
-			// Schedule the sink operation so it gets an ID and can accept websocket connections.
-			sinkOpArgs := operations.OperationArgs{
-				ProjectName: projectName,
-				EntityURL:   api.NewURL().Path(version.APIVersion, "projects", projectName),
-				Type:        operationtype.InstanceCreate,
-				Class:       operationtype.OperationClassWebsocket,
-				Metadata:    result.sink.Metadata(),
-				ConnectHook: result.sink.Connect,
-				RunHook:     result.run,
+		if child.Status() != api.Success {
+			run.Status = api.ReplicatorStatusFailed
+			runInstance.Status = api.ReplicatorStatusFailed
+			runInstance.Error = childOp.Err
+			if runInstance.Error == "" {
+				runInstance.Error = child.Status().String()
 			}
 
-			var sinkOp *operations.Operation
-			if op.Requestor() != nil {
-				sinkOp, err = operations.ScheduleUserOperationFromOperation(s, op, sinkOpArgs)
-			} else {
-				sinkOp, err = operations.ScheduleServerOperation(s, sinkOpArgs)
-			}
+			failedInstances[runInstance.InstanceName] = runInstance.Error
+		}
 
-			if err != nil {
-				return fmt.Errorf("Failed scheduling migration sink operation for instance %q: %w", instName, err)
-			}
+		run.BytesTransferred += runInstance.BytesTransferred
+		runInstances = append(runInstances, runInstance)
+	}
 
-			_, sinkOpAPI := sinkOp.Render()
-			sinkSecrets, err := sinkOpAPI.WebsocketSecrets()
-			if err != nil {
-				return fmt.Errorf("Failed getting websocket secrets from local sink for instance %q: %w", instName, err)
-			}
+	// Only the finalize operation of a resumed forward run has skipped instances.
+	skipped, err := operations.GetOperationInputValue[[]string](op, operationInputKeyReplicatorSkipped)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
 
-			// Build the operation URL using a reachable address for this server.
-			// For clustered members the address from the nodes table is already a
-			// concrete, registered address. For unclustered servers the nodes table
-			// stores the sentinel "0.0.0.0", so fall back to the configured HTTPS
-			// address. Return an error if we still cannot determine a concrete address,
-			// because the leader would not be able to reach us.
-			localAddress := nodeAddressByName[s.ServerName]
-			if util.IsWildCardAddress(localAddress) {
-				localAddress = s.LocalConfig.ClusterAddress()
-				if localAddress == "" {
-					localAddress = s.LocalConfig.HTTPSAddress()
-				}
+	for _, instName := range skipped {
+		runInstances = append(runInstances, dbCluster.ReplicatorRunInstanceRow{
+			InstanceName: instName,
+			Status:       api.ReplicatorStatusSkipped,
+		})
+	}
 
-				if util.IsWildCardAddress(localAddress) || localAddress == "" {
-					_ = sinkOp.Cancel()
-					return errors.New("Cannot restore to this server: configure a concrete address using cluster.https_address or core.https_address")
-				}
-			}
+	// Use a fresh context so the status write always completes, even if the operation context was cancelled.
+	// Only the status is updated here; last_run_date was already set when the operation started.
+	var runID int64
+	err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := dbCluster.UpdateReplicatorLastRunStatus(ctx, tx.Tx(), replicatorID, run.Status)
+		if err != nil {
+			return err
+		}
 
-			sinkOpURL := "https://" + localAddress + sinkOp.URL()
+		runID, err = dbCluster.CreateReplicatorRun(ctx, tx.Tx(), run, runInstances)
+		if err != nil {
+			return err
+		}
 
-			// Tell the current leader cluster to push-migrate the instance to our local sink.
-			remoteMigrateOp, err := dstClient.MigrateInstance(instName, api.InstancePost{
-				Migration: true,
-				Target: &api.InstancePostTarget{
-					Operation:   sinkOpURL,
-					Websockets:  sinkSecrets,
-					Certificate: localCertPEM,
-				},
-			})
-			if err != nil {
-				_ = sinkOp.Cancel()
-				return fmt.Errorf("Failed starting push migration on current leader cluster for instance %q: %w", instName, err)
-			}
+		return dbCluster.PruneReplicatorRuns(ctx, tx.Tx(), replicatorID, runsRetain)
+	})
+	if err != nil {
+		return err
+	}
 
-			remoteErr := remoteMigrateOp.Wait()
-			if remoteErr != nil {
-				_ = sinkOp.Cancel()
-				return fmt.Errorf("Restore of instance %q failed on current leader cluster: %w", instName, remoteErr)
-			}
+	eventCtx := map[string]any{
+		"run":               runID,
+		"bytes_transferred": run.BytesTransferred,
+	}
 
-			sinkErr := sinkOp.Wait(context.Background())
-			if sinkErr != nil {
-				return fmt.Errorf("Restore of instance %q failed: %w", instName, sinkErr)
-			}
+	if run.Status == api.ReplicatorStatusFailed {
+		eventCtx["failed_instances"] = failedInstances
+		s.Events.SendLifecycle(projectName, lifecycle.ReplicatorRunFailed.Event(ctx, name, projectName, eventCtx))
+	} else {
+		s.Events.SendLifecycle(projectName, lifecycle.ReplicatorRunCompleted.Event(ctx, name, projectName, eventCtx))
+	}
 
-			result.revert.Success()
-			return nil
-		}
+	return nil
+}
 
-		// The instance may exist only on the current leader cluster, in which case this operation creates it
-		// locally and there is nothing to name yet. The project is the primary entity here, and the instance
-		// URL reaches clients through the metadata.
-		childArgs = append(childArgs, &operations.OperationArgs{
-			ProjectName: projectName,
-			EntityURL:   projectURL,
-			Type:        operationtype.ReplicatorRunInstanceRestore,
-			Class:       operationtype.OperationClassTask,
-			Metadata: map[string]any{
-				api.MetadataEntityURL: entity.InstanceURL(projectName, instName).String(),
-			},
-			RunHook: copyFunc,
-			Stage:   stage,
-		})
+// replicatorRunItem returns the name and location of the item replicated by a replicator run child operation, based
+// on the entity URL recorded in its metadata. Instances are identified by their name and custom volumes by their pool
+// and volume name, along with their cluster member on local storage pools. Empty strings are returned for other
+// entities.
+func replicatorRunItem(metadata map[string]any) (string, string) {
+	entityURL, _ := metadata[api.MetadataEntityURL].(string)
+	u, err := url.Parse(entityURL)
+	if err != nil {
+		return "", ""
 	}
 
-	stage++
-	childArgs = append(childArgs, replicatorFinalizeOperationArgs(s, projectName, replicatorURL, replicatorID, stage))
+	entityType, _, location, pathArgs, err := entity.ParseURL(*u)
+	if err != nil {
+		return "", ""
+	}
 
-	return operations.OperationArgs{
-		ProjectName:       projectName,
-		EntityURL:         replicatorURL,
-		Type:              operationtype.ReplicatorRun,
-		Class:             operationtype.OperationClassTask,
-		ConflictReference: replicatorURL.String(), // Prevents concurrent runs; paired with ConflictActionFail on the operation type to enforce cluster-wide exclusivity.
-		Children:          childArgs,
-	}, nil
+	switch entityType {
+	case entity.TypeInstance:
+		return pathArgs[0], ""
+	case entity.TypeStorageVolume:
+		return pathArgs[0] + "/" + pathArgs[2], location
+	default:
+		return "", ""
+	}
 }
 
-func replicatorFinalizeOperationArgs(s *state.State, projectName string, replicatorURL *api.URL, replicatorID int64, stage uint16) *operations.OperationArgs {
-	return &operations.OperationArgs{
-		ProjectName: projectName,
-		Type:        operationtype.ReplicatorFinalize,
-		Class:       operationtype.OperationClassTask,
-		EntityURL:   replicatorURL,
-		RunHook: func(_ context.Context, op *operations.Operation) error {
-			// Iterate over all operations for the bulk replicator run.
-			// If any operations (that are not this one) have failed, then the replicator run has failed overall.
-			runStatus := api.ReplicatorStatusCompleted
-			for _, child := range op.Parent().Children() {
-				if child.ID() != op.ID() && child.Status() != api.Success {
-					runStatus = api.ReplicatorStatusFailed
-					break
-				}
-			}
-
-			// Use a fresh context so the status write always completes, even if the operation context was cancelled.
-			// Only the status is updated here; last_run_date was already set when the operation started.
-			return s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
-				return dbCluster.UpdateReplicatorLastRunStatus(ctx, tx.Tx(), replicatorID, runStatus)
-			})
-		},
-		Stage: stage,
+// replicatorBytesTransferred returns the number of bytes transferred recorded in the given operation metadata.
+// The value is a float64 when the metadata was received from another cluster member.
+func replicatorBytesTransferred(metadata map[string]any) int64 {
+	switch n := metadata["bytes_transferred"].(type) {
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	default:
+		return 0
 	}
 }
 
@@ -1455,12 +2228,68 @@ func replicatorCheckInstancesStopped(allInsts []instance.Instance) error {
 	return nil
 }
 
+// replicatorCheckpointInstance records that the given instance was successfully replicated, along with the name
+// of its newest snapshot. That snapshot now exists on both clusters and is the base of the next incremental transfer.
+func replicatorCheckpointInstance(s *state.State, replicatorID int64, inst instance.Instance) error {
+	snapshots, err := inst.Snapshots()
+	if err != nil {
+		return fmt.Errorf("Failed loading snapshots of instance %q: %w", inst.Name(), err)
+	}
+
+	var snapshotName string
+	if len(snapshots) > 0 {
+		_, snapshotName, _ = api.GetParentAndSnapshotName(snapshots[len(snapshots)-1].Name())
+	}
+
+	// Use a fresh context so the checkpoint is always recorded, even if the operation context was cancelled.
+	err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.SetReplicatorInstance(ctx, tx.Tx(), dbCluster.ReplicatorInstanceRow{
+			ReplicatorID: replicatorID,
+			InstanceID:   int64(inst.ID()),
+			SnapshotName: snapshotName,
+			RunDate:      time.Now(),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("Failed recording replicator checkpoint for instance %q: %w", inst.Name(), err)
+	}
+
+	return nil
+}
+
 // replicateInstance handles forward replication of a single instance to the
 // destination cluster. It handles both instances on the local cluster member
 // and instances on other cluster members.
-func replicateInstance(ctx context.Context, s *state.State, op *operations.Operation, inst instance.Instance, memberAddress string, dstClient lxd.InstanceServer, targetCertPEM string) error {
+//
+// The refresh migration only transfers the changes since the newest snapshot that exists on both sides.
+// baseSnapshot is the snapshot recorded by the last successful replication of the instance, and is kept
+// by the snapshot expiry task so that the transfer stays incremental. bandwidthLimit is in bytes per
+// second, zero meaning no limit.
+//
+// Unless liveSnapshotMode is disabled, a running instance is replicated from a temporary snapshot: its volume
+// is transferred without waiting for it to be consistent, and the instance is then restored from the snapshot
+// on the target cluster. The temporary snapshot is deleted on both clusters afterwards.
+func replicateInstance(ctx context.Context, s *state.State, op *operations.Operation, inst instance.Instance, memberAddress string, dstClient lxd.InstanceServer, targetCertPEM string, baseSnapshot string, bandwidthLimit int64, liveSnapshotMode string) error {
 	instName := inst.Name()
 	projectName := inst.Project().Name
+
+	// The power state recorded in the database is used, as the instance may be located on another cluster member.
+	liveSnapshot := liveSnapshotMode != replicatorLiveSnapshotDisabled && inst.LocalConfig()["volatile.last_state.power"] == instance.PowerStateRunning
+	liveSnapshotRestored := false
+
+	// An instance that lost the base snapshot on the target would be refreshed from an older snapshot that may
+	// have diverged from the one on the source, so the run fails instead. Missing instances are fully transferred.
+	if baseSnapshot != "" {
+		targetSnapshots, err := dstClient.GetInstanceSnapshotNames(instName)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return fmt.Errorf("Failed getting snapshots of instance %q on target: %w", instName, err)
+		}
+
+		if err == nil && !slices.Contains(targetSnapshots, baseSnapshot) {
+			return fmt.Errorf("Base snapshot %q of instance %q is missing on target, delete the instance on the target to transfer it again", baseSnapshot, instName)
+		}
+	}
+
 	// Snapshotting is unconditional; the only exception is when the instance already has a
 	// snapshot schedule defined, since scheduled snapshots provide point-in-time history so
 	// an extra one here would be redundant.
@@ -1495,6 +2324,22 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 			}
 		}
 
+		// Take the temporary snapshot on the hosting cluster member, which can freeze the instance.
+		if liveSnapshot {
+			_, _, err = memberClient.RawQuery(http.MethodPost, "/internal/replicators/live-snapshot", internalReplicatorLiveSnapshotPost{
+				Project:  projectName,
+				Instance: instName,
+				Mode:     liveSnapshotMode,
+			}, "")
+			if err != nil {
+				return fmt.Errorf("Failed creating temporary snapshot of instance %q on hosting cluster member: %w", instName, err)
+			}
+
+			defer func() {
+				replicatorDeleteLiveSnapshot(context.Background(), s, projectName, instName, memberClient, dstClient, liveSnapshotRestored)
+			}()
+		}
+
 		// Get instance metadata from the hosting cluster member.
 		srcInstInfo, _, err := memberClient.GetInstance(instName)
 		if err != nil {
@@ -1529,27 +2374,46 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 			return fmt.Errorf("Failed getting websocket secrets from destination for instance %q: %w", instName, err)
 		}
 
-		// Tell the hosting cluster member to push-migrate the instance to the destination.
-		srcMigrateOp, err := memberClient.MigrateInstance(instName, api.InstancePost{
-			Migration: true,
-			Target: &api.InstancePostTarget{
+		// Tell the hosting cluster member to push-migrate the instance to the destination. This goes
+		// through the internal API so that the bandwidth limit is applied by the hosting cluster member.
+		srcMigrateOp, _, err := memberClient.RawOperation(http.MethodPost, "/internal/replicators/migrate", internalReplicatorMigratePost{
+			Project:  projectName,
+			Instance: instName,
+			Target: api.InstancePostTarget{
 				Operation:   destOp.URL().String(),
 				Websockets:  destSecrets,
 				Certificate: targetCertPEM,
 			},
-		})
+			BandwidthLimit:    bandwidthLimit,
+			AllowInconsistent: liveSnapshot,
+		}, "")
 		if err != nil {
 			return fmt.Errorf("Failed starting push migration for instance %q: %w", instName, err)
 		}
 
 		err = srcMigrateOp.Wait()
+		_ = op.ExtendMetadata(map[string]any{"bytes_transferred": replicatorBytesTransferred(srcMigrateOp.Get().Metadata)})
 		if err != nil {
 			return fmt.Errorf("Replication of instance %q failed on hosting cluster member: %w", instName, err)
 		}
 
 		destOpCancelled = true
 
-		return destOp.Wait()
+		err = destOp.Wait()
+		if err != nil {
+			return err
+		}
+
+		if liveSnapshot {
+			err = replicatorRestoreLiveSnapshot(dstClient, instName)
+			if err != nil {
+				return err
+			}
+
+			liveSnapshotRestored = true
+		}
+
+		return nil
 	}
 
 	// Local instance: handle replication directly.
@@ -1559,69 +2423,264 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 			return fmt.Errorf("Failed generating snapshot name for instance %q: %w", instName, err)
 		}
 
-		err = inst.Snapshot(ctx, snapName, nil, false, api.DiskVolumesModeRoot, nil)
-		if err != nil {
-			return fmt.Errorf("Failed creating snapshot of instance %q: %w", instName, err)
+		err = inst.Snapshot(ctx, snapName, nil, false, api.DiskVolumesModeRoot, nil)
+		if err != nil {
+			return fmt.Errorf("Failed creating snapshot of instance %q: %w", instName, err)
+		}
+	}
+
+	if liveSnapshot {
+		err := replicatorCreateLiveSnapshot(ctx, inst, liveSnapshotMode)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			replicatorDeleteLiveSnapshot(context.Background(), s, projectName, instName, nil, dstClient, liveSnapshotRestored)
+		}()
+	}
+
+	srcRenderRes, _, err := inst.Render()
+	if err != nil {
+		return fmt.Errorf("Failed rendering source instance %q: %w", instName, err)
+	}
+
+	srcInstInfo, ok := srcRenderRes.(*api.Instance)
+	if !ok {
+		return fmt.Errorf("Unexpected result from source instance render for %q", instName)
+	}
+
+	// Set up a push-mode migration sink on the destination. In push mode the
+	// leader (source) connects outward to the destination, so the destination
+	// does not need to reach back into the leader. This is required when the
+	// destination project is restricted, which disallows pull-mode migrations.
+	destOp, err := dstClient.CreateInstance(api.InstancesPost{
+		Name:        instName,
+		InstancePut: srcInstInfo.Writable(),
+		Type:        api.InstanceType(srcInstInfo.Type),
+		Source: api.InstanceSource{
+			Type:    api.SourceTypeMigration,
+			Mode:    "push",
+			Refresh: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed requesting instance create on destination: %w", err)
+	}
+
+	// Guard against leaving the destination sink operation running if we fail
+	// before starting the source; disarmed once the source op is scheduled.
+	destOpCancelled := false
+	defer func() {
+		if !destOpCancelled {
+			_ = destOp.Cancel()
+		}
+	}()
+
+	destOpAPI := destOp.Get()
+	destSecrets, err := destOpAPI.WebsocketSecrets()
+	if err != nil {
+		return fmt.Errorf("Failed getting websocket secrets from destination for instance %q: %w", instName, err)
+	}
+
+	pushTarget := &api.InstancePostTarget{
+		Operation:   destOp.URL().String(),
+		Websockets:  destSecrets,
+		Certificate: targetCertPEM,
+	}
+
+	migrArgs, err := replicatorMigrationSourceOperationArgs(s, inst, pushTarget, bandwidthLimit, liveSnapshot)
+	if err != nil {
+		return err
+	}
+
+	var srcOp *operations.Operation
+	if op.Requestor() != nil {
+		srcOp, err = operations.ScheduleUserOperationFromOperation(s, op, migrArgs)
+	} else {
+		srcOp, err = operations.ScheduleServerOperation(s, migrArgs)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	destOpCancelled = true // source is now connected via websockets; cancel would interrupt an in-flight transfer
+
+	err = srcOp.Wait(context.Background())
+	_ = op.ExtendMetadata(map[string]any{"bytes_transferred": replicatorBytesTransferred(srcOp.Metadata())})
+	if err != nil {
+		return fmt.Errorf("Replication of instance %q failed on source: %w", instName, err)
+	}
+
+	err = destOp.Wait()
+	if err != nil {
+		return err
+	}
+
+	if liveSnapshot {
+		err = replicatorRestoreLiveSnapshot(dstClient, instName)
+		if err != nil {
+			return err
+		}
+
+		liveSnapshotRestored = true
+	}
+
+	return nil
+}
+
+// replicatorLiveSnapshotSource is the part of a local instance used to take the temporary snapshot it is replicated
+// from.
+type replicatorLiveSnapshotSource interface {
+	Name() string
+	Project() api.Project
+	Type() instancetype.Type
+	IsRunning() bool
+	IsFrozen() bool
+	Freeze(ctx context.Context) error
+	Unfreeze(ctx context.Context) error
+	Snapshot(ctx context.Context, name string, expiry *time.Time, stateful bool, diskVolumesMode string, progressReporter ioprogress.ProgressReporter) error
+}
+
+// replicatorFilesystemFreezer is implemented by virtual machines, whose filesystems are frozen through the lxd-agent.
+type replicatorFilesystemFreezer interface {
+	FreezeFilesystems(ctx context.Context) error
+	UnfreezeFilesystems(ctx context.Context) error
+}
+
+// replicatorLiveSnapshotClient is the part of a client of a cluster used to restore and delete the temporary
+// snapshot of a replicated instance.
+type replicatorLiveSnapshotClient interface {
+	UpdateInstance(name string, instance api.InstancePut, ETag string) (lxd.Operation, error)
+	DeleteInstanceSnapshot(instanceName string, name string, diskVolumesMode string) (lxd.Operation, error)
+}
+
+// replicatorCreateLiveSnapshot creates the temporary snapshot that the given running local instance is replicated
+// from, replacing any snapshot left behind by an interrupted run.
+func replicatorCreateLiveSnapshot(ctx context.Context, inst instance.Instance, mode string) error {
+	snapshots, err := inst.Snapshots()
+	if err != nil {
+		return fmt.Errorf("Failed loading snapshots of instance %q: %w", inst.Name(), err)
+	}
+
+	for _, snapshot := range snapshots {
+		_, snapshotName, _ := api.GetParentAndSnapshotName(snapshot.Name())
+		if snapshotName != replicatorLiveSnapshotName {
+			continue
+		}
+
+		err = snapshot.Delete(ctx, true, "", nil)
+		if err != nil {
+			return fmt.Errorf("Failed deleting leftover temporary snapshot of instance %q: %w", inst.Name(), err)
+		}
+	}
+
+	return replicatorTakeLiveSnapshot(ctx, inst, mode)
+}
+
+// replicatorTakeLiveSnapshot takes the temporary snapshot of the given running local instance. In frozen mode,
+// containers are frozen through the cgroup freezer and virtual machines freeze their filesystems through the
+// lxd-agent while the snapshot is taken.
+func replicatorTakeLiveSnapshot(ctx context.Context, inst replicatorLiveSnapshotSource, mode string) error {
+	if mode == replicatorLiveSnapshotFrozen && inst.IsRunning() && !inst.IsFrozen() {
+		switch inst.Type() {
+		case instancetype.Container:
+			err := inst.Freeze(ctx)
+			if err != nil {
+				return fmt.Errorf("Failed freezing instance %q: %w", inst.Name(), err)
+			}
+
+			defer func() {
+				err := inst.Unfreeze(ctx)
+				if err != nil {
+					logger.Warn("Failed unfreezing instance after replicator snapshot", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+				}
+			}()
+
+		case instancetype.VM:
+			vm, ok := inst.(replicatorFilesystemFreezer)
+			if !ok {
+				return fmt.Errorf("Instance %q is not a virtual machine", inst.Name())
+			}
+
+			err := vm.FreezeFilesystems(ctx)
+			if err != nil {
+				return fmt.Errorf("Failed freezing filesystems of instance %q through the VM agent: %w", inst.Name(), err)
+			}
+
+			defer func() {
+				err := vm.UnfreezeFilesystems(ctx)
+				if err != nil {
+					logger.Warn("Failed thawing filesystems after replicator snapshot", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+				}
+			}()
 		}
 	}
 
-	srcRenderRes, _, err := inst.Render()
+	err := inst.Snapshot(ctx, replicatorLiveSnapshotName, nil, false, api.DiskVolumesModeRoot, nil)
 	if err != nil {
-		return fmt.Errorf("Failed rendering source instance %q: %w", instName, err)
+		return fmt.Errorf("Failed creating temporary snapshot of instance %q: %w", inst.Name(), err)
 	}
 
-	srcInstInfo, ok := srcRenderRes.(*api.Instance)
-	if !ok {
-		return fmt.Errorf("Unexpected result from source instance render for %q", instName)
-	}
+	return nil
+}
 
-	// Set up a push-mode migration sink on the destination. In push mode the
-	// leader (source) connects outward to the destination, so the destination
-	// does not need to reach back into the leader. This is required when the
-	// destination project is restricted, which disallows pull-mode migrations.
-	destOp, err := dstClient.CreateInstance(api.InstancesPost{
-		Name:        instName,
-		InstancePut: srcInstInfo.Writable(),
-		Type:        api.InstanceType(srcInstInfo.Type),
-		Source: api.InstanceSource{
-			Type:    api.SourceTypeMigration,
-			Mode:    "push",
-			Refresh: true,
-		},
-	})
+// replicatorRestoreLiveSnapshot restores the given replicated instance on the target cluster from the temporary
+// snapshot it was replicated from, as its volume was transferred while the instance was running.
+func replicatorRestoreLiveSnapshot(dstClient replicatorLiveSnapshotClient, instName string) error {
+	err := replicatorWaitOperation(dstClient.UpdateInstance(instName, api.InstancePut{Restore: replicatorLiveSnapshotName}, ""))
 	if err != nil {
-		return fmt.Errorf("Failed requesting instance create on destination: %w", err)
+		return fmt.Errorf("Failed restoring instance %q from temporary snapshot on target: %w", instName, err)
 	}
 
-	// Guard against leaving the destination sink operation running if we fail
-	// before starting the source; disarmed once the source op is scheduled.
-	destOpCancelled := false
-	defer func() {
-		if !destOpCancelled {
-			_ = destOp.Cancel()
+	return nil
+}
+
+// replicatorDeleteLiveSnapshot deletes the temporary snapshot of the given instance, through memberClient if the
+// instance is located on another cluster member. It is also deleted on the target cluster once the instance was
+// restored from it there. Failures are only logged, as the next run replaces any leftover snapshot.
+func replicatorDeleteLiveSnapshot(ctx context.Context, s *state.State, projectName string, instName string, memberClient replicatorLiveSnapshotClient, dstClient replicatorLiveSnapshotClient, restored bool) {
+	l := logger.AddContext(logger.Ctx{"project": projectName, "instance": instName, "snapshot": replicatorLiveSnapshotName})
+
+	var err error
+	if memberClient != nil {
+		err = replicatorWaitOperation(memberClient.DeleteInstanceSnapshot(instName, replicatorLiveSnapshotName, ""))
+	} else {
+		var snapshot instance.Instance
+		snapshot, err = instance.LoadByProjectAndName(s, projectName, instName+shared.SnapshotDelimiter+replicatorLiveSnapshotName)
+		if err == nil {
+			err = snapshot.Delete(ctx, true, "", nil)
 		}
-	}()
+	}
 
-	destOpAPI := destOp.Get()
-	destSecrets, err := destOpAPI.WebsocketSecrets()
 	if err != nil {
-		return fmt.Errorf("Failed getting websocket secrets from destination for instance %q: %w", instName, err)
+		l.Warn("Failed deleting temporary replicator snapshot", logger.Ctx{"err": err})
 	}
 
-	pushTarget := &api.InstancePostTarget{
-		Operation:   destOp.URL().String(),
-		Websockets:  destSecrets,
-		Certificate: targetCertPEM,
+	if !restored {
+		return
+	}
+
+	err = replicatorWaitOperation(dstClient.DeleteInstanceSnapshot(instName, replicatorLiveSnapshotName, ""))
+	if err != nil {
+		l.Warn("Failed deleting temporary replicator snapshot on target", logger.Ctx{"err": err})
 	}
+}
 
-	srcMigration, err := newMigrationSource(inst, false, false, false, "", pushTarget)
+// replicatorMigrationSourceOperationArgs returns the arguments of an operation that push-migrates the given local
+// instance to the migration sink described by pushTarget, limiting the transfer to bandwidthLimit bytes per second.
+func replicatorMigrationSourceOperationArgs(s *state.State, inst instance.Instance, pushTarget *api.InstancePostTarget, bandwidthLimit int64, allowInconsistent bool) (operations.OperationArgs, error) {
+	srcMigration, err := newMigrationSource(inst, false, false, allowInconsistent, "", pushTarget)
 	if err != nil {
-		return fmt.Errorf("Failed setting up migration source for instance %q: %w", instName, err)
+		return operations.OperationArgs{}, fmt.Errorf("Failed setting up migration source for instance %q: %w", inst.Name(), err)
 	}
 
-	migrArgs := operations.OperationArgs{
-		ProjectName: projectName,
-		EntityURL:   entity.InstanceURL(projectName, instName),
+	srcMigration.limiter = util.NewRateLimiter(bandwidthLimit)
+
+	return operations.OperationArgs{
+		ProjectName: inst.Project().Name,
+		EntityURL:   entity.InstanceURL(inst.Project().Name, inst.Name()),
 		Type:        operationtype.InstanceMigrate,
 		Class:       operationtype.OperationClassTask,
 		RunHook: func(ctx context.Context, innerOp *operations.Operation) error {
@@ -1635,29 +2694,14 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 				}
 			}()
 
-			return srcMigration.Do(ctx, s, innerOp)
-		},
-	}
-
-	var srcOp *operations.Operation
-	if op.Requestor() != nil {
-		srcOp, err = operations.ScheduleUserOperationFromOperation(s, op, migrArgs)
-	} else {
-		srcOp, err = operations.ScheduleServerOperation(s, migrArgs)
-	}
-
-	if err != nil {
-		return err
-	}
-
-	destOpCancelled = true // source is now connected via websockets; cancel would interrupt an in-flight transfer
+			err := srcMigration.Do(ctx, s, innerOp)
 
-	err = srcOp.Wait(context.Background())
-	if err != nil {
-		return fmt.Errorf("Replication of instance %q failed on source: %w", instName, err)
-	}
+			// Record the amount of data sent so that it can be reported in the replicator run history.
+			_ = innerOp.ExtendMetadata(map[string]any{"bytes_transferred": srcMigration.bytesSent.Load()})
 
-	return destOp.Wait()
+			return err
+		},
+	}, nil
 }
 
 // runScheduledReplicators loads all replicators, checks their schedule config key against the current
@@ -1770,12 +2814,11 @@ func replicatorIsScheduledNow(spec string, now time.Time) bool {
 // It blocks until the operation completes so that last_run_date is persisted before the next scheduler
 // tick and operation results are visible to callers.
 func triggerScheduledReplicator(ctx context.Context, s *state.State, replicator *api.Replicator, row *dbCluster.Replicator) error {
-	clusterLinkName := replicator.Config["cluster"]
-	if clusterLinkName == "" {
+	if replicator.Config["cluster"] == "" {
 		return fmt.Errorf("Replicator %q has no cluster link configured", replicator.Name)
 	}
 
-	opArgs, err := prepareReplicatorRunOperation(ctx, s, replicator.Project, replicator.Name, clusterLinkName, false, row.Row.ID)
+	opArgs, err := prepareReplicatorRunOperation(ctx, s, replicator, row.Row.ID, false)
 	if err != nil {
 		return err
 	}
@@ -1812,6 +2855,318 @@ func triggerScheduledReplicator(ctx context.Context, s *state.State, replicator
 	return op.Wait(ctx)
 }
 
+// replicatorPromote promotes the standby project of a replicator to leader and starts its instances.
+//
+// If the project on the target cluster is still the leader, a controlled failback is performed first: the
+// target instances are stopped, a final restore run brings the local project up to date and the target
+// project is demoted to standby. If the target cluster cannot be reached, the local project is only promoted
+// as is (failover) when force is set. Once the local project is the leader, the replicator copies instances to
+// the target cluster on subsequent runs, so the replication direction is reversed without further changes.
+func replicatorPromote(ctx context.Context, s *state.State, op *operations.Operation, replicator *api.Replicator, replicatorID int64, force bool) error {
+	projectName := replicator.Project
+	clusterLinkName := replicator.Config["cluster"]
+
+	var clusterLink *api.ClusterLink
+	var targetCert *x509.Certificate
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		_, clusterLink, targetCert, err = lxdCluster.LoadClusterLinkAndCert(ctx, tx.Tx(), clusterLinkName)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading cluster link %q: %w", clusterLinkName, err)
+	}
+
+	// The interface is only set when connected, so that it is nil rather than holding a nil client otherwise.
+	var targetClient replicatorPromoteClient
+	client, connectErr := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(s.Endpoints.NetworkCert(), targetCert))
+	if connectErr == nil {
+		defer client.Disconnect()
+
+		targetClient = client.UseProject(projectName)
+	}
+
+	restore := func(ctx context.Context) error {
+		return replicatorFailbackRestore(ctx, s, op, replicator, replicatorID)
+	}
+
+	failback, err := replicatorPromoteTarget(ctx, projectName, targetClient, connectErr, force, restore)
+	if err != nil {
+		return err
+	}
+
+	err = projectPromote(ctx, s, projectName, false)
+	if err != nil {
+		return err
+	}
+
+	failedInstances, err := replicatorStartPromotedInstances(ctx, s, projectName)
+	if err != nil {
+		return err
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.ReplicatorPromoted.Event(ctx, replicator.Name, projectName, map[string]any{
+		"failback":         failback,
+		"failed_instances": failedInstances,
+	}))
+
+	if len(failedInstances) > 0 {
+		_ = op.ExtendMetadata(map[string]any{"failed_instances": failedInstances})
+
+		return fmt.Errorf("Project %q was promoted but %d instance(s) failed to start", projectName, len(failedInstances))
+	}
+
+	return nil
+}
+
+// replicatorFailbackClient is the part of a client of the target cluster used for a controlled failback.
+type replicatorFailbackClient interface {
+	GetInstances(args lxd.GetInstancesArgs) ([]api.Instance, error)
+	UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (lxd.Operation, error)
+	UpdateProjectState(name string, state api.ProjectStatePut, force bool) (lxd.Operation, error)
+}
+
+// replicatorPromoteClient is the part of a client of the target cluster used to promote a replicator project.
+type replicatorPromoteClient interface {
+	replicatorFailbackClient
+	GetProject(name string) (*api.Project, string, error)
+}
+
+// replicatorPromoteTarget prepares the target cluster for the promotion of the local project, and returns whether
+// a controlled failback was performed. targetClient is nil if connecting to the target cluster failed with
+// connectErr, in which case an error is returned unless force is set.
+func replicatorPromoteTarget(ctx context.Context, projectName string, targetClient replicatorPromoteClient, connectErr error, force bool, restore func(ctx context.Context) error) (bool, error) {
+	if targetClient == nil {
+		if !force {
+			return false, api.StatusErrorf(http.StatusServiceUnavailable, "Failed connecting to target cluster, use force to promote the project without failback: %w", connectErr)
+		}
+
+		logger.Warn("Failed connecting to target cluster, promoting replicator project without failback", logger.Ctx{"project": projectName, "err": connectErr})
+		return false, nil
+	}
+
+	targetProject, _, err := targetClient.GetProject(projectName)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting target project: %w", err)
+	}
+
+	if targetProject.ReplicaMode != api.ReplicatorProjectModeLeader {
+		return false, nil
+	}
+
+	err = replicatorFailback(ctx, projectName, targetClient, restore)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// replicatorFailback hands the leader role over from the target cluster in a controlled way.
+// The running instances on the target cluster are stopped, their latest state is restored into the local
+// project and the target project is demoted to standby. If any step fails, the stopped instances are started
+// again so that the target cluster remains the active site.
+func replicatorFailback(ctx context.Context, projectName string, targetClient replicatorFailbackClient, restore func(ctx context.Context) error) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	targetInsts, err := targetClient.GetInstances(lxd.GetInstancesArgs{InstanceType: api.InstanceTypeAny})
+	if err != nil {
+		return fmt.Errorf("Failed listing instances on target cluster: %w", err)
+	}
+
+	for _, targetInst := range targetInsts {
+		if targetInst.StatusCode != api.Running {
+			continue
+		}
+
+		stopOp, err := targetClient.UpdateInstanceState(targetInst.Name, api.InstanceStatePut{Action: "stop", Timeout: -1}, "")
+		if err == nil {
+			err = stopOp.WaitContext(ctx)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed stopping instance %q on target cluster: %w", targetInst.Name, err)
+		}
+
+		reverter.Add(func() {
+			startOp, err := targetClient.UpdateInstanceState(targetInst.Name, api.InstanceStatePut{Action: "start", Timeout: -1}, "")
+			if err == nil {
+				err = startOp.Wait()
+			}
+
+			if err != nil {
+				logger.Warn("Failed restarting instance on target cluster after failed failback", logger.Ctx{"project": projectName, "instance": targetInst.Name, "err": err})
+			}
+		})
+	}
+
+	// Bring the local project up to date with a final restore run.
+	err = restore(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Demote the old leader. This requires replica.cluster to be set on the target project so that it
+	// accepts the instances replicated from this cluster from now on.
+	demoteOp, err := targetClient.UpdateProjectState(projectName, api.ProjectStatePut{ReplicaMode: api.ReplicatorProjectModeStandby}, false)
+	if err == nil {
+		err = demoteOp.WaitContext(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed demoting project on target cluster: %w", err)
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// replicatorFailbackRestore runs the final restore run of a controlled failback as a child of the promote operation.
+// The promote operation holds the conflict reference of the replicator, which prevents other runs in the meantime,
+// so the restore run is scheduled without one.
+func replicatorFailbackRestore(ctx context.Context, s *state.State, op *operations.Operation, replicator *api.Replicator, replicatorID int64) error {
+	restoreArgs, err := prepareReplicatorRunOperation(ctx, s, replicator, replicatorID, true)
+	if err != nil {
+		return err
+	}
+
+	restoreArgs.ConflictReference = ""
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.UpdateReplicatorLastRun(ctx, tx.Tx(), replicatorID, time.Now(), api.ReplicatorStatusRunning)
+	})
+	if err != nil {
+		logger.Warn("Failed updating replicator last run status to running", logger.Ctx{"replicator": replicator.Name, "project": replicator.Project, "err": err})
+	}
+
+	var restoreOp *operations.Operation
+	if op.Requestor() != nil {
+		restoreOp, err = operations.ScheduleUserOperationFromOperation(s, op, restoreArgs)
+	} else {
+		restoreOp, err = operations.ScheduleServerOperation(s, restoreArgs)
+	}
+
+	if err != nil {
+		_ = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.UpdateReplicatorLastRunStatus(ctx, tx.Tx(), replicatorID, api.ReplicatorStatusFailed)
+		})
+
+		return fmt.Errorf("Failed scheduling replicator restore run: %w", err)
+	}
+
+	err = restoreOp.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed restoring instances from target cluster: %w", err)
+	}
+
+	return nil
+}
+
+// replicatorStartPromotedInstances starts the instances of a newly promoted project across all cluster members
+// in boot.autostart.priority order. Failures don't prevent the remaining instances from being started and are
+// returned as a map of instance name to error message.
+func replicatorStartPromotedInstances(ctx context.Context, s *state.State, projectName string) (map[string]string, error) {
+	var insts []instance.Instance
+	var nodeAddressByName map[string]string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		err := tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q: %w", dbInst.Name, err)
+			}
+
+			insts = append(insts, inst)
+			return nil
+		}, dbCluster.InstanceFilter{Project: &projectName})
+		if err != nil {
+			return fmt.Errorf("Failed listing project instances: %w", err)
+		}
+
+		nodes, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed listing cluster members: %w", err)
+		}
+
+		nodeAddressByName = make(map[string]string, len(nodes))
+		for _, node := range nodes {
+			nodeAddressByName[node.Name] = node.Address
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(instanceAutostartList(insts))
+
+	return replicatorStartInstances(ctx, insts, func(ctx context.Context, inst instance.Instance) error {
+		if inst.Location() == s.ServerName {
+			if inst.IsRunning() {
+				return nil
+			}
+
+			return inst.Start(ctx, false, nil)
+		}
+
+		memberClient, err := lxdCluster.Connect(ctx, nodeAddressByName[inst.Location()], s.Endpoints.NetworkCert(), s.ServerCert(), true)
+		if err != nil {
+			return err
+		}
+
+		startOp, err := memberClient.UseProject(projectName).UpdateInstanceState(inst.Name(), api.InstanceStatePut{Action: "start", Timeout: -1}, "")
+		if err != nil {
+			return err
+		}
+
+		return startOp.WaitContext(ctx)
+	})
+}
+
+// replicatorPromotedInstance is the part of an instance of a promoted project used to decide whether to start it.
+type replicatorPromotedInstance interface {
+	Name() string
+	Project() api.Project
+	ExpandedConfig() map[string]string
+}
+
+// replicatorStartInstances starts the given instances in order using the start function, waiting for their
+// boot.autostart.delay in between. Instances with security.protection.start or an explicit boot.autostart=false
+// are left stopped. Failures don't prevent the remaining instances from being started and are returned as a map
+// of instance name to error message.
+func replicatorStartInstances[T replicatorPromotedInstance](ctx context.Context, insts []T, start func(ctx context.Context, inst T) error) (map[string]string, error) {
+	failedInstances := map[string]string{}
+
+	for _, inst := range insts {
+		config := inst.ExpandedConfig()
+		if shared.IsTrue(config["security.protection.start"]) || shared.IsFalse(config["boot.autostart"]) {
+			continue
+		}
+
+		err := start(ctx, inst)
+		if err != nil {
+			logger.Warn("Failed starting instance of promoted project", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+			failedInstances[inst.Name()] = err.Error()
+			continue
+		}
+
+		// Wait the auto-start delay if set.
+		autoStartDelay, err := strconv.Atoi(config["boot.autostart.delay"])
+		if err == nil && autoStartDelay > 0 {
+			timer := time.NewTimer(time.Duration(autoStartDelay) * time.Second)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+
+	return failedInstances, nil
+}
+
 // validateReplicatorModes checks the source and target replica modes for a run.
 func validateReplicatorModes(sourceMode string, targetMode string, restore bool) error {
 	if restore {
@@ -1,11 +1,26 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/ioprogress"
 )
 
 func TestReplicatorIsScheduledNow(t *testing.T) {
@@ -66,3 +81,627 @@ func TestReplicatorIsScheduledNow(t *testing.T) {
 		})
 	}
 }
+
+func TestReplicatorRunItem(t *testing.T) {
+	tests := []struct {
+		name         string
+		metadata     map[string]any
+		wantName     string
+		wantLocation string
+	}{
+		{name: "instance URL", metadata: map[string]any{api.MetadataEntityURL: "/1.0/instances/c1?project=p1"}, wantName: "c1"},
+		{name: "custom volume URL", metadata: map[string]any{api.MetadataEntityURL: "/1.0/storage-pools/pool1/volumes/custom/vol1?project=p1"}, wantName: "pool1/vol1"},
+		{name: "local custom volume URL", metadata: map[string]any{api.MetadataEntityURL: "/1.0/storage-pools/pool1/volumes/custom/vol1?project=p1&target=member1"}, wantName: "pool1/vol1", wantLocation: "member1"},
+		{name: "project URL", metadata: map[string]any{api.MetadataEntityURL: "/1.0/projects/p1"}},
+		{name: "missing URL", metadata: map[string]any{}},
+		{name: "non-string URL", metadata: map[string]any{api.MetadataEntityURL: 1}},
+		{name: "invalid URL", metadata: map[string]any{api.MetadataEntityURL: "/not/an/entity"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, location := replicatorRunItem(tt.metadata)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantLocation, location)
+		})
+	}
+}
+
+func TestReplicatorBytesTransferred(t *testing.T) {
+	// Local operations store an int64, while metadata decoded from another cluster member holds a float64.
+	assert.Equal(t, int64(1024), replicatorBytesTransferred(map[string]any{"bytes_transferred": int64(1024)}))
+	assert.Equal(t, int64(2048), replicatorBytesTransferred(map[string]any{"bytes_transferred": float64(2048)}))
+	assert.Equal(t, int64(0), replicatorBytesTransferred(map[string]any{}))
+	assert.Equal(t, int64(0), replicatorBytesTransferred(nil))
+}
+
+// replicatorRunPlan returns the type of each child operation of a replicator run, grouped by stage.
+func replicatorRunPlan(t *testing.T, children []*operations.OperationArgs) [][]operationtype.Type {
+	t.Helper()
+
+	var plan [][]operationtype.Type
+	for _, child := range children {
+		// Child operation stages must be consecutive, starting at 0.
+		require.LessOrEqual(t, int(child.Stage), len(plan))
+		if int(child.Stage) == len(plan) {
+			plan = append(plan, nil)
+		}
+
+		plan[child.Stage] = append(plan[child.Stage], child.Type)
+	}
+
+	return plan
+}
+
+func TestReplicatorPlanForwardRun(t *testing.T) {
+	volumes := []*db.StorageVolume{
+		{StorageVolume: api.StorageVolume{Name: "vol1", Pool: "pool1", Type: "custom"}},
+	}
+
+	tests := []struct {
+		name      string
+		volumes   []*db.StorageVolume
+		instNames []string
+		want      [][]operationtype.Type
+	}{
+		{
+			name:      "custom volumes and instances",
+			volumes:   volumes,
+			instNames: []string{"c1", "c2"},
+			want: [][]operationtype.Type{
+				{operationtype.ReplicatorRunVolumeForward},
+				{operationtype.ReplicatorRunProjectSync},
+				{operationtype.ReplicatorRunInstanceForward, operationtype.ReplicatorRunInstanceForward},
+				{operationtype.ReplicatorRunReconcile},
+				{operationtype.ReplicatorFinalize},
+			},
+		},
+		{
+			name:      "no custom volumes",
+			instNames: []string{"c1"},
+			want: [][]operationtype.Type{
+				{operationtype.ReplicatorRunProjectSync},
+				{operationtype.ReplicatorRunInstanceForward},
+				{operationtype.ReplicatorRunReconcile},
+				{operationtype.ReplicatorFinalize},
+			},
+		},
+		{
+			name: "no custom volumes nor instances",
+			want: [][]operationtype.Type{
+				{operationtype.ReplicatorRunProjectSync},
+				{operationtype.ReplicatorRunReconcile},
+				{operationtype.ReplicatorFinalize},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			children, err := replicatorPlanForwardRun("p1", "r1", tt.volumes, tt.instNames, nil, time.Time{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, replicatorRunPlan(t, children))
+		})
+	}
+}
+
+func TestReplicatorPlanForwardRunResume(t *testing.T) {
+	lastRunAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	checkpoints := map[string]dbCluster.ReplicatorInstanceRow{
+		"c1": {RunDate: lastRunAt.Add(-time.Hour)},  // Replicated by an earlier run.
+		"c2": {RunDate: lastRunAt},                  // Replicated by the interrupted run.
+		"c3": {RunDate: lastRunAt.Add(time.Minute)}, // Replicated by the interrupted run.
+		"c4": {},                                    // Never replicated.
+	}
+
+	// replicatorRunInstances returns the names of the instances replicated by a run.
+	replicatorRunInstances := func(children []*operations.OperationArgs) []string {
+		var names []string
+		for _, child := range children {
+			if child.Type == operationtype.ReplicatorRunInstanceForward {
+				name, _ := replicatorRunItem(map[string]any{api.MetadataEntityURL: child.EntityURL.String()})
+				names = append(names, name)
+			}
+		}
+
+		return names
+	}
+
+	tests := []struct {
+		name        string
+		instNames   []string
+		resumeAfter time.Time
+		want        []string
+	}{
+		{
+			name:      "no run to resume",
+			instNames: []string{"c1", "c2", "c3", "c4", "c5"},
+			want:      []string{"c1", "c2", "c3", "c4", "c5"},
+		},
+		{
+			name:        "resume interrupted run",
+			instNames:   []string{"c1", "c2", "c3", "c4", "c5"},
+			resumeAfter: lastRunAt,
+			want:        []string{"c1", "c4", "c5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			children, err := replicatorPlanForwardRun("p1", "r1", nil, tt.instNames, checkpoints, tt.resumeAfter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, replicatorRunInstances(children))
+		})
+	}
+
+	// If the interrupted run replicated all instances, the instance stage is dropped and the stages stay consecutive.
+	children, err := replicatorPlanForwardRun("p1", "r1", nil, []string{"c2", "c3"}, checkpoints, lastRunAt)
+	require.NoError(t, err)
+	assert.Equal(t, [][]operationtype.Type{
+		{operationtype.ReplicatorRunProjectSync},
+		{operationtype.ReplicatorRunReconcile},
+		{operationtype.ReplicatorFinalize},
+	}, replicatorRunPlan(t, children))
+}
+
+func TestReplicatorPlanRestoreRun(t *testing.T) {
+	children, err := replicatorPlanRestoreRun("p1", "r1", []string{"c1", "c2"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]operationtype.Type{
+		{operationtype.ReplicatorRunInstanceRestore, operationtype.ReplicatorRunInstanceRestore},
+		{operationtype.ReplicatorFinalize},
+	}, replicatorRunPlan(t, children))
+
+	// Without instances to restore, the run only finalizes the replicator.
+	children, err = replicatorPlanRestoreRun("p1", "r1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]operationtype.Type{{operationtype.ReplicatorFinalize}}, replicatorRunPlan(t, children))
+}
+
+// replicatorTestLiveInstance is a stand-in for a running local instance that is replicated from a temporary snapshot.
+// It records the calls that change the instance.
+type replicatorTestLiveInstance struct {
+	instType     instancetype.Type
+	frozen       bool
+	failSnapshot bool
+
+	calls []string
+}
+
+func (i *replicatorTestLiveInstance) Name() string {
+	return "c1"
+}
+
+func (i *replicatorTestLiveInstance) Project() api.Project {
+	return api.Project{Name: "p1"}
+}
+
+func (i *replicatorTestLiveInstance) Type() instancetype.Type {
+	return i.instType
+}
+
+func (i *replicatorTestLiveInstance) IsRunning() bool {
+	return true
+}
+
+func (i *replicatorTestLiveInstance) IsFrozen() bool {
+	return i.frozen
+}
+
+func (i *replicatorTestLiveInstance) Freeze(ctx context.Context) error {
+	i.calls = append(i.calls, "freeze")
+	return nil
+}
+
+func (i *replicatorTestLiveInstance) Unfreeze(ctx context.Context) error {
+	i.calls = append(i.calls, "unfreeze")
+	return nil
+}
+
+func (i *replicatorTestLiveInstance) FreezeFilesystems(ctx context.Context) error {
+	i.calls = append(i.calls, "freeze-filesystems")
+	return nil
+}
+
+func (i *replicatorTestLiveInstance) UnfreezeFilesystems(ctx context.Context) error {
+	i.calls = append(i.calls, "unfreeze-filesystems")
+	return nil
+}
+
+func (i *replicatorTestLiveInstance) Snapshot(ctx context.Context, name string, expiry *time.Time, stateful bool, diskVolumesMode string, progressReporter ioprogress.ProgressReporter) error {
+	i.calls = append(i.calls, "snapshot "+name)
+	if i.failSnapshot {
+		return errors.New("No space left on device")
+	}
+
+	return nil
+}
+
+// replicatorTestOperation is a remote operation that has already completed.
+type replicatorTestOperation struct{}
+
+func (op replicatorTestOperation) AddHandler(function func(api.Operation)) (*lxd.EventTarget, error) {
+	return nil, nil
+}
+
+func (op replicatorTestOperation) Cancel() error {
+	return nil
+}
+
+func (op replicatorTestOperation) Get() api.Operation {
+	return api.Operation{Status: api.Success.String(), StatusCode: api.Success}
+}
+
+func (op replicatorTestOperation) GetWebsocket(secret string) (*websocket.Conn, error) {
+	return nil, errors.New("Operation has no websockets")
+}
+
+func (op replicatorTestOperation) RemoveHandler(target *lxd.EventTarget) error {
+	return nil
+}
+
+func (op replicatorTestOperation) Refresh() error {
+	return nil
+}
+
+func (op replicatorTestOperation) URL() *url.URL {
+	return &url.URL{Path: "/1.0/operations/test"}
+}
+
+func (op replicatorTestOperation) Wait() error {
+	return nil
+}
+
+func (op replicatorTestOperation) WaitContext(ctx context.Context) error {
+	return nil
+}
+
+// replicatorTestLiveClient is a stand-in for the client of a cluster holding a temporary snapshot.
+// It records its calls, and fails those on the snapshot if fail is set.
+type replicatorTestLiveClient struct {
+	fail  bool
+	calls []string
+}
+
+func (c *replicatorTestLiveClient) UpdateInstance(name string, instance api.InstancePut, ETag string) (lxd.Operation, error) {
+	c.calls = append(c.calls, "restore "+name+"/"+instance.Restore)
+	if c.fail {
+		return nil, errors.New("Snapshot not found")
+	}
+
+	return replicatorTestOperation{}, nil
+}
+
+func (c *replicatorTestLiveClient) DeleteInstanceSnapshot(instanceName string, name string, diskVolumesMode string) (lxd.Operation, error) {
+	c.calls = append(c.calls, "delete "+instanceName+"/"+name)
+	if c.fail {
+		return nil, errors.New("Snapshot not found")
+	}
+
+	return replicatorTestOperation{}, nil
+}
+
+func TestReplicatorTakeLiveSnapshot(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         string
+		instType     instancetype.Type
+		frozen       bool
+		failSnapshot bool
+		want         []string
+		wantErr      string
+	}{
+		{
+			name:     "crash-consistent container",
+			mode:     replicatorLiveSnapshotCrashConsistent,
+			instType: instancetype.Container,
+			want:     []string{"snapshot replicator-live"},
+		},
+		{
+			name:     "crash-consistent virtual machine",
+			mode:     replicatorLiveSnapshotCrashConsistent,
+			instType: instancetype.VM,
+			want:     []string{"snapshot replicator-live"},
+		},
+		{
+			name:     "frozen container",
+			mode:     replicatorLiveSnapshotFrozen,
+			instType: instancetype.Container,
+			want:     []string{"freeze", "snapshot replicator-live", "unfreeze"},
+		},
+		{
+			name:     "frozen container that is already frozen",
+			mode:     replicatorLiveSnapshotFrozen,
+			instType: instancetype.Container,
+			frozen:   true,
+			want:     []string{"snapshot replicator-live"},
+		},
+		{
+			name:     "frozen virtual machine",
+			mode:     replicatorLiveSnapshotFrozen,
+			instType: instancetype.VM,
+			want:     []string{"freeze-filesystems", "snapshot replicator-live", "unfreeze-filesystems"},
+		},
+		{
+			name:         "failed snapshot of frozen container",
+			mode:         replicatorLiveSnapshotFrozen,
+			instType:     instancetype.Container,
+			failSnapshot: true,
+			want:         []string{"freeze", "snapshot replicator-live", "unfreeze"},
+			wantErr:      `Failed creating temporary snapshot of instance "c1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := &replicatorTestLiveInstance{instType: tt.instType, frozen: tt.frozen, failSnapshot: tt.failSnapshot}
+
+			// The instance is always thawed once the snapshot was taken, even if that failed.
+			err := replicatorTakeLiveSnapshot(context.Background(), inst, tt.mode)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, inst.calls)
+		})
+	}
+}
+
+func TestReplicatorRestoreLiveSnapshot(t *testing.T) {
+	target := &replicatorTestLiveClient{}
+	err := replicatorRestoreLiveSnapshot(target, "c1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"restore c1/replicator-live"}, target.calls)
+
+	target = &replicatorTestLiveClient{fail: true}
+	err = replicatorRestoreLiveSnapshot(target, "c1")
+	assert.ErrorContains(t, err, `Failed restoring instance "c1" from temporary snapshot on target`)
+}
+
+func TestReplicatorDeleteLiveSnapshot(t *testing.T) {
+	tests := []struct {
+		name       string
+		restored   bool
+		memberFail bool
+		wantTarget []string
+	}{
+		{
+			name: "not restored on target",
+		},
+		{
+			name:       "restored on target",
+			restored:   true,
+			wantTarget: []string{"delete c1/replicator-live"},
+		},
+		{
+			name:       "failed deletion on cluster member",
+			restored:   true,
+			memberFail: true,
+			wantTarget: []string{"delete c1/replicator-live"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member := &replicatorTestLiveClient{fail: tt.memberFail}
+			target := &replicatorTestLiveClient{}
+
+			// The snapshot on the target is only deleted once the instance was restored from it, and regardless of
+			// whether the snapshot could be deleted on the hosting cluster member.
+			replicatorDeleteLiveSnapshot(context.Background(), nil, "p1", "c1", member, target, tt.restored)
+			assert.Equal(t, []string{"delete c1/replicator-live"}, member.calls)
+			assert.Equal(t, tt.wantTarget, target.calls)
+		})
+	}
+}
+
+// replicatorTestPromoteClient is a stand-in for the target cluster of a replicator whose project is promoted.
+// It records the calls that change the target cluster, and fails those listed in fail.
+type replicatorTestPromoteClient struct {
+	replicaMode string
+	instances   []api.Instance
+	fail        []string
+	calls       []string
+}
+
+// call records a call and returns its outcome.
+func (c *replicatorTestPromoteClient) call(action string) (lxd.Operation, error) {
+	c.calls = append(c.calls, action)
+	if slices.Contains(c.fail, action) {
+		return nil, fmt.Errorf("Failed %s", action)
+	}
+
+	return replicatorTestOperation{}, nil
+}
+
+func (c *replicatorTestPromoteClient) GetInstances(args lxd.GetInstancesArgs) ([]api.Instance, error) {
+	return c.instances, nil
+}
+
+func (c *replicatorTestPromoteClient) UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (lxd.Operation, error) {
+	return c.call(state.Action + " " + name)
+}
+
+func (c *replicatorTestPromoteClient) UpdateProjectState(name string, state api.ProjectStatePut, force bool) (lxd.Operation, error) {
+	return c.call("set " + name + " " + state.ReplicaMode)
+}
+
+func (c *replicatorTestPromoteClient) GetProject(name string) (*api.Project, string, error) {
+	return &api.Project{Name: name, ReplicaMode: c.replicaMode}, "", nil
+}
+
+// restore returns a stand-in for the restore run of a failback, which is recorded as a call.
+func (c *replicatorTestPromoteClient) restore(ctx context.Context) error {
+	_, err := c.call("restore")
+	return err
+}
+
+func TestReplicatorPromoteTarget(t *testing.T) {
+	instances := []api.Instance{
+		{Name: "c1", StatusCode: api.Running},
+		{Name: "c2", StatusCode: api.Stopped},
+	}
+
+	tests := []struct {
+		name         string
+		unreachable  bool
+		force        bool
+		replicaMode  string
+		wantErr      string
+		wantFailback bool
+		wantCalls    []string
+	}{
+		{
+			name:        "unreachable target",
+			unreachable: true,
+			wantErr:     "use force to promote the project without failback",
+		},
+		{
+			name:        "forced failover to unreachable target",
+			unreachable: true,
+			force:       true,
+		},
+		{
+			name:        "target already demoted",
+			replicaMode: api.ReplicatorProjectModeStandby,
+		},
+		{
+			name:         "controlled failback",
+			replicaMode:  api.ReplicatorProjectModeLeader,
+			wantFailback: true,
+			wantCalls:    []string{"stop c1", "restore", "set p1 standby"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &replicatorTestPromoteClient{replicaMode: tt.replicaMode, instances: instances}
+
+			var targetClient replicatorPromoteClient = client
+			var connectErr error
+			if tt.unreachable {
+				targetClient = nil
+				connectErr = errors.New("Connection refused")
+			}
+
+			failback, err := replicatorPromoteTarget(context.Background(), "p1", targetClient, connectErr, tt.force, client.restore)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantFailback, failback)
+			assert.Equal(t, tt.wantCalls, client.calls)
+		})
+	}
+}
+
+func TestReplicatorFailback(t *testing.T) {
+	instances := []api.Instance{
+		{Name: "c1", StatusCode: api.Running},
+		{Name: "c2", StatusCode: api.Stopped},
+		{Name: "c3", StatusCode: api.Running},
+	}
+
+	tests := []struct {
+		name      string
+		fail      []string
+		wantCalls []string
+	}{
+		{
+			name:      "failed stopping instance",
+			fail:      []string{"stop c3"},
+			wantCalls: []string{"stop c1", "stop c3", "start c1"},
+		},
+		{
+			name:      "failed restore run",
+			fail:      []string{"restore"},
+			wantCalls: []string{"stop c1", "stop c3", "restore", "start c3", "start c1"},
+		},
+		{
+			name:      "failed demoting target project",
+			fail:      []string{"set p1 standby"},
+			wantCalls: []string{"stop c1", "stop c3", "restore", "set p1 standby", "start c3", "start c1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &replicatorTestPromoteClient{instances: instances, fail: tt.fail}
+
+			// The instances stopped on the target cluster are started again if any step of the failback fails.
+			err := replicatorFailback(context.Background(), "p1", client, client.restore)
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantCalls, client.calls)
+		})
+	}
+}
+
+// replicatorTestPromotedInstance is a stand-in for an instance of a promoted project.
+type replicatorTestPromotedInstance struct {
+	name   string
+	config map[string]string
+}
+
+func (i replicatorTestPromotedInstance) Name() string {
+	return i.name
+}
+
+func (i replicatorTestPromotedInstance) Project() api.Project {
+	return api.Project{Name: "p1"}
+}
+
+func (i replicatorTestPromotedInstance) ExpandedConfig() map[string]string {
+	return i.config
+}
+
+func TestReplicatorStartInstances(t *testing.T) {
+	insts := []replicatorTestPromotedInstance{
+		{name: "c1"},
+		{name: "c2", config: map[string]string{"security.protection.start": "true"}},
+		{name: "c3", config: map[string]string{"boot.autostart": "false"}},
+		{name: "c4"},
+		{name: "c5", config: map[string]string{"boot.autostart": "true"}},
+	}
+
+	var started []string
+	start := func(ctx context.Context, inst replicatorTestPromotedInstance) error {
+		started = append(started, inst.Name())
+		if inst.Name() == "c4" {
+			return errors.New("Instance failed to start")
+		}
+
+		return nil
+	}
+
+	// Protected instances and instances that don't start automatically are left stopped, and a failure to start
+	// an instance doesn't prevent the following ones from being started.
+	failed, err := replicatorStartInstances(context.Background(), insts, start)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c1", "c4", "c5"}, started)
+	assert.Equal(t, map[string]string{"c4": "Instance failed to start"}, failed)
+}
+
+func TestReplicatorStartInstancesCancel(t *testing.T) {
+	insts := []replicatorTestPromotedInstance{
+		{name: "c1", config: map[string]string{"boot.autostart.delay": "60"}},
+		{name: "c2"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var started []string
+	start := func(ctx context.Context, inst replicatorTestPromotedInstance) error {
+		started = append(started, inst.Name())
+		cancel()
+		return nil
+	}
+
+	// Cancelling the promotion stops waiting for the auto-start delay, and the remaining instances aren't started.
+	_, err := replicatorStartInstances(ctx, insts, start)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"c1"}, started)
+}
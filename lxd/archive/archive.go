@@ -78,52 +78,60 @@ func ExtractWithFds(s *state.State, cmd string, args []string, allowedCmds []str
 // The returned cancelFunc should be called when finished with reader to clean up any resources used.
 // This can be done before reading to the end of the tarball if desired.
 func CompressedTarReader(s *state.State, ctx context.Context, r io.ReadSeeker, unpacker []string, outputPath string) (*tar.Reader, context.CancelFunc, error) {
-	ctx, cancelFunc := context.WithCancel(ctx)
-
 	_, err := r.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	decompressed, cancelFunc, err := DecompressedReader(s, ctx, r, unpacker, outputPath)
 	if err != nil {
 		return nil, cancelFunc, err
 	}
 
-	var tr *tar.Reader
+	return tar.NewReader(decompressed), cancelFunc, nil
+}
 
-	if len(unpacker) > 0 {
-		cmdPath, err := exec.LookPath(unpacker[0])
-		if err != nil {
-			return nil, cancelFunc, fmt.Errorf("Failed starting unpack: Failed finding executable: %w", err)
-		}
+// DecompressedReader returns a reader of the decompressed content of the supplied (optionally compressed) stream.
+// The unpacker arguments are those returned by DetectCompressionFile().
+// The returned cancelFunc should be called when finished with reader to clean up any resources used.
+func DecompressedReader(s *state.State, ctx context.Context, r io.Reader, unpacker []string, outputPath string) (io.Reader, context.CancelFunc, error) {
+	ctx, cancelFunc := context.WithCancel(ctx)
 
-		err = apparmor.ArchiveLoad(s, outputPath, []string{cmdPath})
-		if err != nil {
-			return nil, cancelFunc, fmt.Errorf("Failed starting unpack: Failed loading profile: %w", err)
-		}
+	if len(unpacker) == 0 {
+		return r, cancelFunc, nil
+	}
 
-		pipeReader, pipeWriter := io.Pipe()
-		p := subprocess.NewProcessWithFds(unpacker[0], unpacker[1:], io.NopCloser(r), pipeWriter, nil)
-		p.SetApparmor(apparmor.ArchiveProfileName(outputPath))
-		err = p.Start(ctx)
-		if err != nil {
-			return nil, cancelFunc, fmt.Errorf("Failed starting unpack: Failed running: %s: %w", unpacker[0], err)
-		}
+	cmdPath, err := exec.LookPath(unpacker[0])
+	if err != nil {
+		return nil, cancelFunc, fmt.Errorf("Failed starting unpack: Failed finding executable: %w", err)
+	}
 
-		ctxCancelFunc := cancelFunc
+	err = apparmor.ArchiveLoad(s, outputPath, []string{cmdPath})
+	if err != nil {
+		return nil, cancelFunc, fmt.Errorf("Failed starting unpack: Failed loading profile: %w", err)
+	}
 
-		// Now that unpacker process has started, wrap context cancel function with one that waits for
-		// the unpacker process to complete.
-		cancelFunc = func() {
-			ctxCancelFunc()
-			_ = pipeWriter.Close()
-			_, _ = p.Wait(ctx)
-			_ = apparmor.ArchiveUnload(s.OS, outputPath)
-			_ = apparmor.ArchiveDelete(s.OS, outputPath)
-		}
+	pipeReader, pipeWriter := io.Pipe()
+	p := subprocess.NewProcessWithFds(unpacker[0], unpacker[1:], io.NopCloser(r), pipeWriter, nil)
+	p.SetApparmor(apparmor.ArchiveProfileName(outputPath))
+	err = p.Start(ctx)
+	if err != nil {
+		return nil, cancelFunc, fmt.Errorf("Failed starting unpack: Failed running: %s: %w", unpacker[0], err)
+	}
 
-		tr = tar.NewReader(pipeReader)
-	} else {
-		tr = tar.NewReader(r)
+	ctxCancelFunc := cancelFunc
+
+	// Now that unpacker process has started, wrap context cancel function with one that waits for
+	// the unpacker process to complete.
+	cancelFunc = func() {
+		ctxCancelFunc()
+		_ = pipeWriter.Close()
+		_, _ = p.Wait(ctx)
+		_ = apparmor.ArchiveUnload(s.OS, outputPath)
+		_ = apparmor.ArchiveDelete(s.OS, outputPath)
 	}
 
-	return tr, cancelFunc, nil
+	return pipeReader, cancelFunc, nil
 }
 
 // doUnpack unpacks the specified file to the given path.
@@ -7,14 +7,19 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.yaml.in/yaml/v2"
 
 	"github.com/canonical/lxd/lxd/backup"
 	backupConfig "github.com/canonical/lxd/lxd/backup/config"
+	"github.com/canonical/lxd/lxd/cluster"
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/operationtype"
@@ -25,9 +30,12 @@ import (
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/storage/s3"
 	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/ioprogress"
@@ -36,7 +44,7 @@ import (
 )
 
 // Create a new backup.
-func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, sourceInst instance.Instance, version uint32, op *operations.Operation) error {
+func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, sourceInst instance.Instance, version uint32, parentSnapshot string, op *operations.Operation) error {
 	projectName := sourceInst.Project().Name
 	l := logger.AddContext(logger.Ctx{"project": projectName, "instance": sourceInst.Name(), "name": args.Name})
 	l.Debug("Instance backup started")
@@ -77,31 +85,10 @@ func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, s
 	}
 
 	// Detect compression method.
-	var compress string
 	b.SetCompressionAlgorithm(args.CompressionAlgorithm)
-	if b.CompressionAlgorithm() != "" {
-		compress = b.CompressionAlgorithm()
-	} else {
-		var p *api.Project
-		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			project, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
-			if err != nil {
-				return err
-			}
-
-			p, err = project.ToAPI(ctx, tx.Tx())
-
-			return err
-		})
-		if err != nil {
-			return err
-		}
-
-		if p.Config["backups.compression_algorithm"] != "" {
-			compress = p.Config["backups.compression_algorithm"]
-		} else {
-			compress = s.GlobalConfig.BackupsCompressionAlgorithm()
-		}
+	compress, err := backupCompressionAlgorithm(ctx, s, projectName, b.CompressionAlgorithm())
+	if err != nil {
+		return err
 	}
 
 	// Create the target path if needed.
@@ -133,63 +120,177 @@ func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, s
 	revert.Add(func() { _ = os.Remove(target) })
 
 	// Get IDMap to unshift container as the tarball is created.
-	var idmap *idmap.IdmapSet
-	if sourceInst.Type() == instancetype.Container {
-		c, ok := sourceInst.(instance.Container)
-		if !ok {
-			return errors.New("Invalid instance type")
-		}
+	idmap, err := backupInstanceIdmap(sourceInst)
+	if err != nil {
+		return err
+	}
+
+	// Create the tarball.
+	writerWrapper := ioprogress.NewProgressWriterWrapper(ioprogress.WithProgressReporter("create_backup", op))
+	err = backupWriteTarball(s, l, writerWrapper(tarFileWriter), compress, idmap, func(tarWriter *instancewriter.InstanceTarWriter) error {
+		return backupWriteInstance(l, sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), parentSnapshot, version, tarWriter)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tarFileWriter.Close()
+	if err != nil {
+		return fmt.Errorf("Error closing tar file: %w", err)
+	}
+
+	revert.Success()
+	s.Events.SendLifecycle(projectName, lifecycle.InstanceBackupCreated.Event(ctx, args.Name, b.Instance(), nil))
+
+	return nil
+}
+
+// backupCreateOnTarget creates an instance backup and uploads it to the backup target under the given key, without
+// storing it on the server.
+func backupCreateOnTarget(ctx context.Context, s *state.State, target *backup.Target, key string, sourceInst instance.Instance, req api.InstanceBackupsPost, op *operations.Operation) error {
+	projectName := sourceInst.Project().Name
+	l := logger.AddContext(logger.Ctx{"project": projectName, "instance": sourceInst.Name(), "backup_target": target.Name(), "key": key})
+	l.Debug("Instance backup upload started")
+	defer l.Debug("Instance backup upload finished")
+
+	pool, err := storagePools.LoadByInstance(s, sourceInst)
+	if err != nil {
+		return fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	// Ignore requests for optimized backups when pool driver doesn't support it.
+	optimized := req.OptimizedStorage && pool.Driver().Info().OptimizedBackups
+
+	compress, err := backupCompressionAlgorithm(ctx, s, projectName, req.CompressionAlgorithm)
+	if err != nil {
+		return err
+	}
+
+	idmap, err := backupInstanceIdmap(sourceInst)
+	if err != nil {
+		return err
+	}
+
+	w, err := target.Writer(ctx, projectName, key)
+	if err != nil {
+		return err
+	}
+
+	writerWrapper := ioprogress.NewProgressWriterWrapper(ioprogress.WithProgressReporter("create_backup", op))
+	err = backupWriteTarball(s, l, writerWrapper(w), compress, idmap, func(tarWriter *instancewriter.InstanceTarWriter) error {
+		return backupWriteInstance(l, sourceInst, pool, optimized, !req.InstanceOnly, req.ParentSnapshot, req.Version, tarWriter)
+	})
+	if err != nil {
+		w.Abort()
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("Failed uploading backup to backup target %q: %w", target.Name(), err)
+	}
+
+	fullName := sourceInst.Name() + shared.SnapshotDelimiter + req.Name
+	s.Events.SendLifecycle(projectName, lifecycle.InstanceBackupCreated.Event(ctx, fullName, sourceInst, logger.Ctx{"backup_target": target.Name(), "key": key}))
+
+	return nil
+}
 
-		idmap, err = c.DiskIdmap()
+// backupCompressionAlgorithm returns the compression algorithm to use for a backup: the requested one if set, or
+// else the one configured for the project or the server.
+func backupCompressionAlgorithm(ctx context.Context, s *state.State, projectName string, requested string) (string, error) {
+	if requested != "" {
+		return requested, nil
+	}
+
+	var p *api.Project
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		project, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
 		if err != nil {
-			return fmt.Errorf("Error getting container IDMAP: %w", err)
+			return err
 		}
+
+		p, err = project.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return "", err
 	}
 
-	// Create the tarball.
+	if p.Config["backups.compression_algorithm"] != "" {
+		return p.Config["backups.compression_algorithm"], nil
+	}
+
+	return s.GlobalConfig.BackupsCompressionAlgorithm(), nil
+}
+
+// volumeBackupCompressionAlgorithm returns the compression algorithm to use for a custom volume backup: the requested
+// one if set, or else the one configured for the server. Unlike instance backups, the project configuration isn't
+// used, to keep the default of existing volume backups unchanged.
+func volumeBackupCompressionAlgorithm(s *state.State, requested string) string {
+	if requested != "" {
+		return requested
+	}
+
+	return s.GlobalConfig.BackupsCompressionAlgorithm()
+}
+
+// backupInstanceIdmap returns the IDMap used to unshift the files of a container as its backup is created, or nil
+// for other instance types.
+func backupInstanceIdmap(inst instance.Instance) (*idmap.IdmapSet, error) {
+	if inst.Type() != instancetype.Container {
+		return nil, nil
+	}
+
+	c, ok := inst.(instance.Container)
+	if !ok {
+		return nil, errors.New("Invalid instance type")
+	}
+
+	idmap, err := c.DiskIdmap()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting container IDMAP: %w", err)
+	}
+
+	return idmap, nil
+}
+
+// backupWriteTarball writes the backup tarball filled by writeContent to w, compressed with the given algorithm.
+func backupWriteTarball(s *state.State, l logger.Logger, w io.Writer, compress string, idmap *idmap.IdmapSet, writeContent func(tarWriter *instancewriter.InstanceTarWriter) error) error {
 	tarPipeReader, tarPipeWriter := io.Pipe()
 	defer func() { _ = tarPipeWriter.Close() }() // Ensure that go routine below always ends.
 	tarWriter := instancewriter.NewInstanceTarWriter(tarPipeWriter, idmap)
 
 	// Setup tar writer go routine, with optional compression.
-	tarWriterRes := make(chan error)
-	var compressErr error
-
-	writerWrapper := ioprogress.NewProgressWriterWrapper(ioprogress.WithProgressReporter("create_backup", op))
-	go func(resCh chan<- error) {
+	tarWriterRes := make(chan error, 1)
+	go func() {
 		l.Debug("Started backup tarball writer")
 		defer l.Debug("Finished backup tarball writer")
-		if compress != "none" {
-			compressErr = compressFile(s.OS, compress, tarPipeReader, writerWrapper(tarFileWriter))
 
-			// If a compression error occurred, close the tarPipeWriter to end the export.
-			if compressErr != nil {
-				_ = tarPipeWriter.Close()
-			}
+		var err error
+		if compress != "none" {
+			err = compressFile(s.OS, compress, tarPipeReader, w)
 		} else {
-			_, err = io.Copy(writerWrapper(tarFileWriter), tarPipeReader)
+			_, err = io.Copy(w, tarPipeReader)
 		}
 
-		resCh <- err
-	}(tarWriterRes)
-
-	// Write index file.
-	l.Debug("Adding backup index file")
-	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), version, tarWriter)
+		// If an error occurred, close the pipe to end the export.
+		if err != nil {
+			_ = tarPipeReader.CloseWithError(err)
+		}
 
-	// Check compression errors.
-	if compressErr != nil {
-		return compressErr
-	}
+		tarWriterRes <- err
+	}()
 
-	// Check backupWriteIndex for errors.
+	err := writeContent(tarWriter)
 	if err != nil {
-		return fmt.Errorf("Error writing backup index file: %w", err)
-	}
+		// Abort the tarball so that it doesn't get completed as a valid one. Compression errors are propagated to
+		// the tarball writes so they are reported by writeContent.
+		_ = tarPipeWriter.CloseWithError(err)
+		<-tarWriterRes
 
-	err = pool.BackupInstance(sourceInst, tarWriter, b.OptimizedStorage(), !b.InstanceOnly(), version, nil)
-	if err != nil {
-		return fmt.Errorf("Backup create: %w", err)
+		return err
 	}
 
 	// Close off the tarball file.
@@ -209,19 +310,207 @@ func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, s
 		return fmt.Errorf("Error writing tarball: %w", err)
 	}
 
-	err = tarFileWriter.Close()
+	return nil
+}
+
+// backupWriteInstance writes the index file, the content of an instance and then the checksum trailer to the
+// backup tarball.
+func backupWriteInstance(l logger.Logger, sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, parentSnapshot string, version uint32, tarWriter *instancewriter.InstanceTarWriter) error {
+	l.Debug("Adding backup index file")
+	err := backupWriteIndex(sourceInst, pool, optimized, snapshots, parentSnapshot, version, tarWriter)
 	if err != nil {
-		return fmt.Errorf("Error closing tar file: %w", err)
+		return fmt.Errorf("Error writing backup index file: %w", err)
 	}
 
-	revert.Success()
-	s.Events.SendLifecycle(projectName, lifecycle.InstanceBackupCreated.Event(ctx, args.Name, b.Instance(), nil))
+	err = pool.BackupInstance(sourceInst, tarWriter, optimized, snapshots, parentSnapshot, version, nil)
+	if err != nil {
+		return fmt.Errorf("Backup create: %w", err)
+	}
+
+	err = tarWriter.WriteChecksum(backup.ChecksumPath)
+	if err != nil {
+		return fmt.Errorf("Error writing backup checksum: %w", err)
+	}
 
 	return nil
 }
 
+func init() {
+	operations.RegisterDurableOperationRunHook(operationtype.BackupCreate, backupCreateOperationRunHook)
+}
+
+const (
+	operationInputKeyBackupCreateInstance operations.InputKey = "instance"
+	operationInputKeyBackupCreateRequest  operations.InputKey = "request"
+)
+
+// backupCreateMetadataStarted is set in the metadata of a backup creation operation once it started creating the
+// backup. It tells a restarted operation that a backup with the same name was left over by the interrupted attempt.
+const backupCreateMetadataStarted = "backup_started"
+
+// backupCreateMemberRetryInterval is the interval at which a restarted backup creation operation checks whether the
+// cluster member hosting the instance is back online.
+const backupCreateMemberRetryInterval = 10 * time.Second
+
+// backupCreateMemberWaitTimeout is how long a restarted backup creation operation waits for the cluster member
+// hosting the instance to be back online before failing.
+const backupCreateMemberWaitTimeout = 30 * time.Minute
+
+// backupCreateOperationRunHook is the run hook of the durable [operationtype.BackupCreate] operation.
+// If the operation is restarted on another cluster member, the backup is created by the member hosting the instance
+// once it is back online (or once the instance was moved to an online member). The operation fails if the member
+// stays offline for longer than backupCreateMemberWaitTimeout.
+func backupCreateOperationRunHook(ctx context.Context, op *operations.Operation) error {
+	s := op.State()
+
+	instName, err := operations.GetOperationInputValue[string](op, operationInputKeyBackupCreateInstance)
+	if err != nil {
+		return err
+	}
+
+	req, err := operations.GetOperationInputValue[api.InstanceBackupsPost](op, operationInputKeyBackupCreateRequest)
+	if err != nil {
+		return err
+	}
+
+	// Record that the backup creation started, so that a restarted operation replaces the backup it left behind
+	// rather than failing on a name conflict. This is only done on restart so that existing backups are never
+	// replaced by a new request.
+	metadata := op.Metadata()
+	resumed, _ := metadata[backupCreateMetadataStarted].(bool)
+	if !resumed {
+		metadata[backupCreateMetadataStarted] = true
+		err = op.UpdateMetadata(metadata)
+		if err != nil {
+			return fmt.Errorf("Failed updating operation metadata: %w", err)
+		}
+
+		err = op.Persist()
+		if err != nil {
+			return fmt.Errorf("Failed persisting operation metadata: %w", err)
+		}
+	}
+
+	projectName := op.Project()
+	fullName := instName + shared.SnapshotDelimiter + req.Name
+	deadline := time.Now().Add(backupCreateMemberWaitTimeout)
+
+	for {
+		inst, err := instance.LoadByProjectAndName(s, projectName, instName)
+		if err != nil {
+			return err
+		}
+
+		if !s.ServerClustered || inst.Location() == s.ServerName {
+			// Backups uploaded to a backup target are simply overwritten when the operation is restarted.
+			if req.Target != "" {
+				target, err := backup.LoadTarget(ctx, s, req.Target)
+				if err != nil {
+					return err
+				}
+
+				err = backupCreateOnTarget(ctx, s, target, backup.InstanceBackupKey(instName, req.Name), inst, req, op)
+				if err != nil {
+					return fmt.Errorf("Create backup: %w", err)
+				}
+
+				return nil
+			}
+
+			if resumed {
+				b, err := instance.BackupLoadByName(s, projectName, fullName)
+				if err == nil {
+					logger.Warn("Removing instance backup left over by interrupted operation", logger.Ctx{"project": projectName, "instance": instName, "name": fullName})
+					err = b.Delete(ctx)
+					if err != nil {
+						return fmt.Errorf("Failed removing leftover backup %q: %w", req.Name, err)
+					}
+				} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+					return err
+				}
+			}
+
+			args := db.InstanceBackup{
+				Name:                 fullName,
+				InstanceID:           inst.ID(),
+				CreationDate:         time.Now(),
+				ExpiryDate:           req.ExpiresAt,
+				InstanceOnly:         req.InstanceOnly,
+				OptimizedStorage:     req.OptimizedStorage,
+				CompressionAlgorithm: req.CompressionAlgorithm,
+			}
+
+			err = backupCreate(ctx, s, args, inst, req.Version, req.ParentSnapshot, op)
+			if err != nil {
+				return fmt.Errorf("Create backup: %w", err)
+			}
+
+			return nil
+		}
+
+		var member db.NodeInfo
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			member, err = tx.GetNodeByName(ctx, inst.Location())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed loading cluster member %q: %w", inst.Location(), err)
+		}
+
+		if !member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			return backupCreateOnMember(ctx, s, member.Address, projectName, instName, req, resumed && req.Target == "")
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Cluster member %q hosting instance %q is still offline after %s", inst.Location(), instName, backupCreateMemberWaitTimeout)
+		}
+
+		logger.Warn("Waiting for cluster member hosting the instance to create backup", logger.Ctx{"project": projectName, "instance": instName, "name": fullName, "member": inst.Location()})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backupCreateMemberRetryInterval):
+		}
+	}
+}
+
+// backupCreateOnMember creates the instance backup on the cluster member at the given address.
+// If replace is true, any existing backup with the same name is deleted first.
+func backupCreateOnMember(ctx context.Context, s *state.State, address string, projectName string, instName string, req api.InstanceBackupsPost, replace bool) error {
+	client, err := cluster.Connect(ctx, address, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to cluster member %q: %w", address, err)
+	}
+
+	client = client.UseProject(projectName)
+
+	if replace {
+		_, _, err = client.GetInstanceBackup(instName, req.Name)
+		if err == nil {
+			deleteOp, err := client.DeleteInstanceBackup(instName, req.Name)
+			if err == nil {
+				err = deleteOp.WaitContext(ctx)
+			}
+
+			if err != nil {
+				return fmt.Errorf("Failed removing leftover backup %q: %w", req.Name, err)
+			}
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+	}
+
+	createOp, err := client.CreateInstanceBackup(instName, req)
+	if err != nil {
+		return fmt.Errorf("Failed creating backup on cluster member %q: %w", address, err)
+	}
+
+	return createOp.WaitContext(ctx)
+}
+
 // backupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
-func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, version uint32, tarWriter *instancewriter.InstanceTarWriter) error {
+func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, parentSnapshot string, version uint32, tarWriter *instancewriter.InstanceTarWriter) error {
 	driverInfo := pool.Driver().Info()
 
 	// Indicate whether the driver will include a driver-specific optimized header.
@@ -257,6 +546,25 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		return fmt.Errorf("Failed generating instance backup config: %w", err)
 	}
 
+	// Incremental backups are identified on import by the volume UUID of their parent snapshot.
+	parentUUID := ""
+	if parentSnapshot != "" {
+		rootVol, err := config.RootVolume()
+		if err != nil {
+			return fmt.Errorf("Failed getting the root volume: %w", err)
+		}
+
+		for _, snap := range rootVol.Snapshots {
+			if snap != nil && snap.Name == parentSnapshot {
+				parentUUID = snap.Config["volatile.uuid"]
+			}
+		}
+
+		if parentUUID == "" {
+			return fmt.Errorf("Failed getting the volume UUID of parent snapshot %q", parentSnapshot)
+		}
+	}
+
 	// Downgrade the config in case the old backup format was requested.
 	config, err = backup.ConvertFormat(config, version)
 	if err != nil {
@@ -284,6 +592,17 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		}
 	}
 
+	// Incremental backups only store the snapshots taken after their parent snapshot.
+	if parentSnapshot != "" {
+		parentIndex := slices.Index(indexInfo.Snapshots, parentSnapshot)
+		if parentIndex < 0 {
+			return api.StatusErrorf(http.StatusNotFound, "Parent snapshot %q not found", parentSnapshot)
+		}
+
+		indexInfo.Snapshots = indexInfo.Snapshots[parentIndex+1:]
+		indexInfo.Parent = parentUUID
+	}
+
 	// Convert to YAML.
 	indexData, err := yaml.Marshal(&indexInfo)
 	if err != nil {
@@ -438,15 +757,8 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 	}
 
 	// Detect compression method.
-	var compress string
-
 	backupRow.CompressionAlgorithm = args.CompressionAlgorithm
-
-	if backupRow.CompressionAlgorithm != "" {
-		compress = backupRow.CompressionAlgorithm
-	} else {
-		compress = s.GlobalConfig.BackupsCompressionAlgorithm()
-	}
+	compress := volumeBackupCompressionAlgorithm(s, backupRow.CompressionAlgorithm)
 
 	// Create the target path if needed.
 	backupsPathBase := s.BackupsStoragePath(projectName)
@@ -477,73 +789,152 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 	revert.Add(func() { _ = os.Remove(target) })
 
 	// Create the tarball.
-	tarPipeReader, tarPipeWriter := io.Pipe()
-	defer func() { _ = tarPipeWriter.Close() }() // Ensure that go routine below always ends.
-	tarWriter := instancewriter.NewInstanceTarWriter(tarPipeWriter, nil)
+	err = backupWriteTarball(s, l, tarFileWriter, compress, nil, func(tarWriter *instancewriter.InstanceTarWriter) error {
+		return volumeBackupWriteVolume(l, projectName, volumeName, pool, backupRow.OptimizedStorage, !backupRow.VolumeOnly, version, tarWriter)
+	})
+	if err != nil {
+		return err
+	}
 
-	// Setup tar writer go routine, with optional compression.
-	tarWriterRes := make(chan error)
-	var compressErr error
+	err = tarFileWriter.Close()
+	if err != nil {
+		return fmt.Errorf("Error closing tar file: %w", err)
+	}
 
-	go func(resCh chan<- error) {
-		l.Debug("Started backup tarball writer")
-		defer l.Debug("Finished backup tarball writer")
-		if compress != "none" {
-			compressErr = compressFile(s.OS, compress, tarPipeReader, tarFileWriter)
+	revert.Success()
+	return nil
+}
 
-			// If a compression error occurred, close the tarPipeWriter to end the export.
-			if compressErr != nil {
-				_ = tarPipeWriter.Close()
-			}
-		} else {
-			_, err = io.Copy(tarFileWriter, tarPipeReader)
-		}
+// volumeBackupCreateOnTarget creates a custom volume backup and uploads it to the backup target under the given key,
+// without storing it on the server.
+func volumeBackupCreateOnTarget(ctx context.Context, s *state.State, target *backup.Target, key string, projectName string, poolName string, volumeName string, req api.StoragePoolVolumeBackupsPost) error {
+	l := logger.AddContext(logger.Ctx{"project": projectName, "storage_volume": volumeName, "backup_target": target.Name(), "key": key})
+	l.Debug("Volume backup upload started")
+	defer l.Debug("Volume backup upload finished")
 
-		resCh <- err
-	}(tarWriterRes)
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return fmt.Errorf("Failed loading storage pool %q: %w", poolName, err)
+	}
 
-	// Write index file.
-	l.Debug("Adding backup index file")
-	err = volumeBackupWriteIndex(projectName, volumeName, pool, backupRow.OptimizedStorage, !backupRow.VolumeOnly, version, tarWriter)
+	// Ignore requests for optimized backups when pool driver doesn't support it.
+	optimized := req.OptimizedStorage && pool.Driver().Info().OptimizedBackups
 
-	// Check compression errors.
-	if compressErr != nil {
-		return compressErr
-	}
+	compress := volumeBackupCompressionAlgorithm(s, req.CompressionAlgorithm)
 
-	// Check backupWriteIndex for errors.
+	w, err := target.Writer(ctx, projectName, key)
 	if err != nil {
-		return fmt.Errorf("Error writing backup index file: %w", err)
+		return err
 	}
 
-	err = pool.BackupCustomVolume(projectName, volumeName, tarWriter, backupRow.OptimizedStorage, !backupRow.VolumeOnly, nil)
+	err = backupWriteTarball(s, l, w, compress, nil, func(tarWriter *instancewriter.InstanceTarWriter) error {
+		return volumeBackupWriteVolume(l, projectName, volumeName, pool, optimized, !req.VolumeOnly, req.Version, tarWriter)
+	})
 	if err != nil {
-		return fmt.Errorf("Backup create: %w", err)
+		w.Abort()
+		return err
 	}
 
-	// Close off the tarball file.
-	err = tarWriter.Close()
+	err = w.Close()
 	if err != nil {
-		return fmt.Errorf("Error closing tarball writer: %w", err)
+		return fmt.Errorf("Failed uploading backup to backup target %q: %w", target.Name(), err)
 	}
 
-	// Close off the tarball pipe writer (this will end the go routine above).
-	err = tarPipeWriter.Close()
+	return nil
+}
+
+// backupImportSource returns the backup data of an import request. This is either the request body or, when the
+// X-LXD-backup-target header is set, the backup stored on that backup target under the X-LXD-backup-key header.
+func backupImportSource(ctx context.Context, s *state.State, r *http.Request, projectName string) (io.ReadCloser, error) {
+	targetName := r.Header.Get("X-LXD-backup-target")
+	if targetName == "" {
+		return r.Body, nil
+	}
+
+	target, err := backup.LoadTarget(ctx, s, targetName)
 	if err != nil {
-		return fmt.Errorf("Error closing tarball pipe writer: %w", err)
+		return nil, err
 	}
 
-	err = <-tarWriterRes
+	reader, _, err := target.Reader(ctx, projectName, r.Header.Get("X-LXD-backup-key"))
 	if err != nil {
-		return fmt.Errorf("Error writing tarball: %w", err)
+		return nil, err
 	}
 
-	err = tarFileWriter.Close()
+	return reader, nil
+}
+
+// backupImportParents returns the parent backups of an incremental instance backup being imported, oldest first, along
+// with the function closing them. Parent backups are read from the backup target when importing from one, using the
+// keys from the X-LXD-backup-parent-keys header. Otherwise they are read from the start of the uploaded data using the
+// sizes from the X-LXD-backup-parent-sizes header.
+func backupImportParents(ctx context.Context, s *state.State, r *http.Request, projectName string, data io.Reader) ([]io.Reader, func(), error) {
+	var parents []io.Reader
+	var closers []io.Closer
+
+	closeParents := func() {
+		for _, closer := range closers {
+			_ = closer.Close()
+		}
+	}
+
+	targetName := r.Header.Get("X-LXD-backup-target")
+	if targetName == "" {
+		for _, size := range shared.SplitNTrimSpace(r.Header.Get("X-LXD-backup-parent-sizes"), ",", -1, true) {
+			parentSize, err := strconv.ParseInt(size, 10, 64)
+			if err != nil || parentSize <= 0 {
+				return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Invalid parent backup size %q", size)
+			}
+
+			parents = append(parents, io.LimitReader(data, parentSize))
+		}
+
+		return parents, closeParents, nil
+	}
+
+	keys := shared.SplitNTrimSpace(r.Header.Get("X-LXD-backup-parent-keys"), ",", -1, true)
+	if len(keys) == 0 {
+		return nil, closeParents, nil
+	}
+
+	target, err := backup.LoadTarget(ctx, s, targetName)
 	if err != nil {
-		return fmt.Errorf("Error closing tar file: %w", err)
+		return nil, nil, err
+	}
+
+	for _, key := range keys {
+		reader, _, err := target.Reader(ctx, projectName, key)
+		if err != nil {
+			closeParents()
+			return nil, nil, err
+		}
+
+		parents = append(parents, reader)
+		closers = append(closers, reader)
+	}
+
+	return parents, closeParents, nil
+}
+
+// volumeBackupWriteVolume writes the index file, the content of a custom volume and then the checksum trailer to
+// the backup tarball.
+func volumeBackupWriteVolume(l logger.Logger, projectName string, volumeName string, pool storagePools.Pool, optimized bool, snapshots bool, version uint32, tarWriter *instancewriter.InstanceTarWriter) error {
+	l.Debug("Adding backup index file")
+	err := volumeBackupWriteIndex(projectName, volumeName, pool, optimized, snapshots, version, tarWriter)
+	if err != nil {
+		return fmt.Errorf("Error writing backup index file: %w", err)
+	}
+
+	err = pool.BackupCustomVolume(projectName, volumeName, tarWriter, optimized, snapshots, nil)
+	if err != nil {
+		return fmt.Errorf("Backup create: %w", err)
+	}
+
+	err = tarWriter.WriteChecksum(backup.ChecksumPath)
+	if err != nil {
+		return fmt.Errorf("Error writing backup checksum: %w", err)
 	}
 
-	revert.Success()
 	return nil
 }
 
@@ -665,3 +1056,452 @@ func pruneExpiredStorageVolumeBackups(ctx context.Context, s *state.State) error
 
 	return nil
 }
+
+// scheduledBackupPrefix is the name prefix of the backups created by `backups.schedule`.
+// Only backups with this prefix are deleted to honour `backups.retain`.
+const scheduledBackupPrefix = "scheduled-"
+
+func autoCreateAndPruneScheduledBackupsTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	// `f` creates the scheduled instance and custom volume backups and prunes the old ones.
+	f := func(ctx context.Context) {
+		err := autoCreateAndPruneScheduledBackups(ctx, stateFunc())
+		if err != nil {
+			logger.Error("Failed running scheduled backup task", logger.Ctx{"err": err})
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoCreateAndPruneScheduledBackups creates the backups of the local instances and custom volumes whose
+// `backups.schedule` is due and then prunes their old scheduled backups.
+func autoCreateAndPruneScheduledBackups(ctx context.Context, s *state.State) error {
+	var instances []instance.Instance
+	var volumes, remoteVolumes []db.StorageVolumeArgs
+	var memberCount int
+	var onlineMemberIDs []int64
+
+	// Projects restricting backup creation are skipped.
+	allowedProjects := map[string]bool{}
+	projectAllowsBackups := func(tx *db.ClusterTx, projectName string) bool {
+		allowed, ok := allowedProjects[projectName]
+		if !ok {
+			allowed = limits.AllowBackupCreation(tx, projectName) == nil
+			allowedProjects[projectName] = allowed
+		}
+
+		return allowed
+	}
+
+	// Get list of instances on the local member that are due to have backups created. Only the schedules are
+	// loaded from the database, so that only the due instances are loaded.
+	var dueInstances []db.InstanceConfigValue
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		schedules, err := tx.GetLocalInstancesExpandedConfigValue(ctx, "backups.schedule")
+		if err != nil {
+			return err
+		}
+
+		for _, schedule := range schedules {
+			if !snapshotIsScheduledNow(schedule.Value, int64(schedule.ID)) || !projectAllowsBackups(tx, schedule.Project) {
+				continue
+			}
+
+			dueInstances = append(dueInstances, schedule)
+		}
+
+		allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, dbCluster.StoragePoolVolumeTypeCustom, true)
+		if err != nil {
+			return fmt.Errorf("Failed getting volumes for auto custom volume backup task: %w", err)
+		}
+
+		for _, v := range allVolumes {
+			schedule := v.Config["backups.schedule"]
+			if schedule == "" || !snapshotIsScheduledNow(schedule, v.ID) {
+				continue
+			}
+
+			if !projectAllowsBackups(tx, v.ProjectName) {
+				continue
+			}
+
+			if v.NodeID < 0 {
+				// Keep a separate list of remote volumes in order to select a member to
+				// perform the backup later.
+				remoteVolumes = append(remoteVolumes, v)
+			} else {
+				logger.Debug("Scheduling local auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+				volumes = append(volumes, v) // Always include local volumes.
+			}
+		}
+
+		if len(remoteVolumes) > 0 {
+			members, err := tx.GetNodes(ctx)
+			if err != nil {
+				return fmt.Errorf("Failed getting cluster members: %w", err)
+			}
+
+			memberCount = len(members)
+
+			// Filter to online members.
+			for _, member := range members {
+				if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+					continue
+				}
+
+				onlineMemberIDs = append(onlineMemberIDs, member.ID)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting backup schedule info: %w", err)
+	}
+
+	for _, due := range dueInstances {
+		inst, err := instance.LoadByProjectAndName(s, due.Project, due.Name)
+		if err != nil {
+			logger.Error("Failed loading instance for backup task", logger.Ctx{"instance": due.Name, "project": due.Project, "err": err})
+			continue
+		}
+
+		logger.Debug("Scheduling auto instance backup", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name})
+		instances = append(instances, inst)
+	}
+
+	if len(remoteVolumes) > 0 {
+		// Skip backing up remote custom volumes if there are no online members, as we can't be sure that the
+		// cluster isn't partitioned and we may end up creating the backup on multiple members.
+		if memberCount > 1 && len(onlineMemberIDs) <= 0 {
+			logger.Error("Skipping remote volumes for auto custom volume backup task due to no online members")
+		} else {
+			localMemberID := s.DB.Cluster.GetNodeID()
+
+			for _, v := range remoteVolumes {
+				// If there are multiple cluster members, a stable random member is chosen to create the backup
+				// from. This spreads the load across the online cluster members.
+				if memberCount > 1 {
+					selectedMemberID, err := util.GetStableRandomInt64FromList(v.ID, onlineMemberIDs)
+					if err != nil {
+						logger.Error("Failed scheduling remote auto custom volume backup task", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+						continue
+					}
+
+					if localMemberID != selectedMemberID {
+						continue
+					}
+				}
+
+				logger.Debug("Scheduling remote auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+				volumes = append(volumes, v)
+			}
+		}
+	}
+
+	if len(instances) == 0 && len(volumes) == 0 {
+		return nil
+	}
+
+	opRun := func(ctx context.Context, op *operations.Operation) error {
+		for _, inst := range instances {
+			err := ctx.Err()
+			if err != nil {
+				return err
+			}
+
+			autoCreateInstanceBackup(ctx, s, inst, op)
+		}
+
+		for _, v := range volumes {
+			err := ctx.Err()
+			if err != nil {
+				return err
+			}
+
+			autoCreateVolumeBackup(ctx, s, v, op)
+		}
+
+		return nil
+	}
+
+	args := operations.OperationArgs{
+		Type:    operationtype.BackupsCreateScheduled,
+		Class:   operationtype.OperationClassTask,
+		RunHook: opRun,
+	}
+
+	op, err := operations.ScheduleServerOperation(s, args)
+	if err != nil {
+		return fmt.Errorf("Failed creating scheduled backups operation: %w", err)
+	}
+
+	err = op.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed creating scheduled backups: %w", err)
+	}
+
+	return nil
+}
+
+// scheduledBackupName returns the name of a scheduled backup created now.
+// Scheduled backups are named after their creation time, so sorting their names sorts them from oldest to newest.
+func scheduledBackupName() string {
+	return scheduledBackupPrefix + time.Now().UTC().Format("20060102-150405")
+}
+
+// autoCreateInstanceBackup creates a scheduled backup of the instance and prunes its old scheduled backups.
+// Failures are reported with a lifecycle event rather than returned, so they don't prevent the backup of other
+// instances.
+func autoCreateInstanceBackup(ctx context.Context, s *state.State, inst instance.Instance, op *operations.Operation) {
+	projectName := inst.Project().Name
+	config := inst.ExpandedConfig()
+	backupName := scheduledBackupName()
+	fullName := inst.Name() + shared.SnapshotDelimiter + backupName
+	retain, _ := strconv.Atoi(config["backups.retain"])
+
+	req := api.InstanceBackupsPost{
+		Name:                 backupName,
+		OptimizedStorage:     shared.IsTrue(config["backups.optimized"]),
+		CompressionAlgorithm: config["backups.compression"],
+		Version:              backupConfig.DefaultMetadataVersion,
+		Target:               config["backups.target"],
+	}
+
+	err := func() error {
+		if req.Target != "" {
+			target, err := backup.LoadTarget(ctx, s, req.Target)
+			if err != nil {
+				return fmt.Errorf("Failed loading backup target %q: %w", req.Target, err)
+			}
+
+			err = backupCreateOnTarget(ctx, s, target, backup.InstanceBackupKey(inst.Name(), backupName), inst, req, op)
+			if err != nil {
+				return err
+			}
+
+			return pruneScheduledTargetBackups(ctx, target, projectName, backup.InstanceBackupKey(inst.Name(), scheduledBackupPrefix), retain, config["backups.expiry"])
+		}
+
+		expiry, err := shared.GetExpiry(time.Now(), config["backups.expiry"])
+		if err != nil {
+			return err
+		}
+
+		args := db.InstanceBackup{
+			Name:                 fullName,
+			InstanceID:           inst.ID(),
+			CreationDate:         time.Now(),
+			ExpiryDate:           expiry,
+			OptimizedStorage:     req.OptimizedStorage,
+			CompressionAlgorithm: req.CompressionAlgorithm,
+		}
+
+		err = backupCreate(ctx, s, args, inst, req.Version, "", op)
+		if err != nil {
+			return err
+		}
+
+		return pruneScheduledInstanceBackups(ctx, s, inst, retain)
+	}()
+	if err != nil {
+		logger.Error("Failed creating scheduled instance backup", logger.Ctx{"project": projectName, "instance": inst.Name(), "name": backupName, "err": err})
+		s.Events.SendLifecycle(projectName, lifecycle.InstanceBackupFailed.Event(ctx, fullName, inst, logger.Ctx{"error": err.Error()}))
+	}
+}
+
+// pruneScheduledInstanceBackups deletes the oldest scheduled backups of the instance so that at most retain of them
+// are kept. Nothing is deleted if retain is zero.
+func pruneScheduledInstanceBackups(ctx context.Context, s *state.State, inst instance.Instance, retain int) error {
+	if retain <= 0 {
+		return nil
+	}
+
+	var backupNames []string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		backupNames, err = tx.GetInstanceBackups(ctx, inst.Project().Name, inst.Name())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading instance backups: %w", err)
+	}
+
+	scheduledNames := scheduledBackupNames(backupNames)
+	for _, name := range scheduledNames[:max(len(scheduledNames)-retain, 0)] {
+		b, err := instance.BackupLoadByName(s, inst.Project().Name, name)
+		if err != nil {
+			return err
+		}
+
+		err = b.Delete(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed deleting instance backup %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// autoCreateVolumeBackup creates a scheduled backup of the custom volume and prunes its old scheduled backups.
+// Failures are reported with a lifecycle event rather than returned, so they don't prevent the backup of other
+// volumes.
+func autoCreateVolumeBackup(ctx context.Context, s *state.State, v db.StorageVolumeArgs, op *operations.Operation) {
+	backupName := scheduledBackupName()
+	fullName := v.Name + shared.SnapshotDelimiter + backupName
+	retain, _ := strconv.Atoi(v.Config["backups.retain"])
+
+	req := api.StoragePoolVolumeBackupsPost{
+		Name:                 backupName,
+		OptimizedStorage:     shared.IsTrue(v.Config["backups.optimized"]),
+		CompressionAlgorithm: v.Config["backups.compression"],
+		Version:              backupConfig.DefaultMetadataVersion,
+		Target:               v.Config["backups.target"],
+	}
+
+	err := func() error {
+		if req.Target != "" {
+			target, err := backup.LoadTarget(ctx, s, req.Target)
+			if err != nil {
+				return fmt.Errorf("Failed loading backup target %q: %w", req.Target, err)
+			}
+
+			key := backup.VolumeBackupKey(v.PoolName, v.Name, backupName)
+			err = volumeBackupCreateOnTarget(ctx, s, target, key, v.ProjectName, v.PoolName, v.Name, req)
+			if err != nil {
+				return err
+			}
+
+			s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupCreated.Event(v.PoolName, dbCluster.StoragePoolVolumeTypeNameCustom, fullName, v.ProjectName, op.EventLifecycleRequestor(), logger.Ctx{"type": dbCluster.StoragePoolVolumeTypeNameCustom, "backup_target": target.Name(), "key": key}))
+
+			return pruneScheduledTargetBackups(ctx, target, v.ProjectName, backup.VolumeBackupKey(v.PoolName, v.Name, scheduledBackupPrefix), retain, v.Config["backups.expiry"])
+		}
+
+		expiry, err := shared.GetExpiry(time.Now(), v.Config["backups.expiry"])
+		if err != nil {
+			return err
+		}
+
+		args := db.StoragePoolVolumeBackup{
+			Name:                 fullName,
+			VolumeID:             v.ID,
+			CreationDate:         time.Now(),
+			ExpiryDate:           expiry,
+			OptimizedStorage:     req.OptimizedStorage,
+			CompressionAlgorithm: req.CompressionAlgorithm,
+		}
+
+		err = volumeBackupCreate(s, args, v.ProjectName, v.PoolName, v.Name, req.Version)
+		if err != nil {
+			return err
+		}
+
+		s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupCreated.Event(v.PoolName, dbCluster.StoragePoolVolumeTypeNameCustom, fullName, v.ProjectName, op.EventLifecycleRequestor(), logger.Ctx{"type": dbCluster.StoragePoolVolumeTypeNameCustom}))
+
+		return pruneScheduledVolumeBackups(ctx, s, v, retain)
+	}()
+	if err != nil {
+		logger.Error("Failed creating scheduled custom volume backup", logger.Ctx{"project": v.ProjectName, "pool": v.PoolName, "volume": v.Name, "name": backupName, "err": err})
+		s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupFailed.Event(v.PoolName, dbCluster.StoragePoolVolumeTypeNameCustom, fullName, v.ProjectName, op.EventLifecycleRequestor(), logger.Ctx{"error": err.Error()}))
+	}
+}
+
+// pruneScheduledVolumeBackups deletes the oldest scheduled backups of the custom volume so that at most retain of
+// them are kept. Nothing is deleted if retain is zero.
+func pruneScheduledVolumeBackups(ctx context.Context, s *state.State, v db.StorageVolumeArgs, retain int) error {
+	if retain <= 0 {
+		return nil
+	}
+
+	var volumeBackups []db.StoragePoolVolumeBackup
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		volumeBackups, err = tx.GetStoragePoolVolumeBackups(ctx, v.ProjectName, v.Name, v.PoolID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading custom volume backups: %w", err)
+	}
+
+	backupsByName := make(map[string]db.StoragePoolVolumeBackup, len(volumeBackups))
+	backupNames := make([]string, 0, len(volumeBackups))
+	for _, b := range volumeBackups {
+		backupsByName[b.Name] = b
+		backupNames = append(backupNames, b.Name)
+	}
+
+	scheduledNames := scheduledBackupNames(backupNames)
+	for _, name := range scheduledNames[:max(len(scheduledNames)-retain, 0)] {
+		b := backupsByName[name]
+		volBackup := backup.NewVolumeBackup(s, v.ProjectName, v.PoolName, v.Name, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage)
+		err = volBackup.Delete()
+		if err != nil {
+			return fmt.Errorf("Failed deleting custom volume backup %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// scheduledBackupNames returns the full names of the scheduled backups among the given backup names, from oldest to
+// newest.
+func scheduledBackupNames(backupNames []string) []string {
+	scheduledNames := make([]string, 0, len(backupNames))
+	for _, name := range backupNames {
+		_, backupName, _ := api.GetParentAndSnapshotName(name)
+		if strings.HasPrefix(backupName, scheduledBackupPrefix) {
+			scheduledNames = append(scheduledNames, name)
+		}
+	}
+
+	slices.Sort(scheduledNames)
+
+	return scheduledNames
+}
+
+// pruneScheduledTargetBackups deletes the scheduled backups stored on the backup target under the given key prefix
+// that are older than the expiry expression, as well as the oldest ones so that at most retain of them are kept.
+func pruneScheduledTargetBackups(ctx context.Context, target *backup.Target, projectName string, prefix string, retain int, expiry string) error {
+	objects, err := target.List(ctx, projectName, prefix)
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(objects, func(a s3.Object, b s3.Object) int { return strings.Compare(a.Key, b.Key) })
+
+	for i, object := range objects {
+		expired := false
+		if expiry != "" {
+			expiresAt, err := shared.GetExpiry(object.LastModified, expiry)
+			if err != nil {
+				return err
+			}
+
+			expired = expiresAt.Before(time.Now())
+		}
+
+		if !expired && (retain <= 0 || len(objects)-i <= retain) {
+			continue
+		}
+
+		err = target.Delete(ctx, projectName, object.Key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
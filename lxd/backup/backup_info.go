@@ -1,8 +1,10 @@
 package backup
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 
 	"go.yaml.in/yaml/v2"
 
@@ -37,6 +39,96 @@ type Info struct {
 	OptimizedHeader  *bool          `json:"optimized_header,omitempty" yaml:"optimized_header,omitempty"` // Optional field to handle older optimized backups that don't have this field.
 	Type             config.Type    `json:"type,omitempty" yaml:"type,omitempty"`                         // Type of backup.
 	Config           *config.Config `json:"config,omitempty" yaml:"config,omitempty"`                     // Equivalent of backup.yaml but embedded in index for quick retrieval.
+	Parent           string         `json:"parent,omitempty" yaml:"parent,omitempty"`                     // Volume UUID of the snapshot an incremental backup is based on.
+	Parents          []Link         `json:"-" yaml:"-"`                                                   // Parents is set during import of incremental backups to their parent backups, oldest first.
+}
+
+// Link is a backup of an incremental backup chain along with its data.
+type Link struct {
+	Info Info
+	Data io.ReadSeeker
+}
+
+// Chain returns the backups to restore in order, starting with the full backup and ending with the backup itself.
+// The snapshots of each backup are the ones whose volume data it stores.
+func (b Info) Chain(data io.ReadSeeker) []Link {
+	return append(slices.Clone(b.Parents), Link{Info: b, Data: data})
+}
+
+// SetParents sets the parent backups of an incremental backup, oldest first, after checking that they form its chain.
+// Each backup of the chain must be based on the last snapshot stored in the backups preceding it.
+func (b *Info) SetParents(parents []Link) error {
+	if b.Parent == "" {
+		if len(parents) > 0 {
+			return errors.New("Parent backups can only be used when importing incremental backups")
+		}
+
+		return nil
+	}
+
+	if len(parents) == 0 {
+		return errors.New("Incremental backups can only be imported along with their parent backups")
+	}
+
+	if parents[0].Info.Parent != "" {
+		return errors.New("The first parent backup must be a full backup")
+	}
+
+	lastUUID := ""
+	for i, link := range append(slices.Clone(parents), Link{Info: *b}) {
+		if link.Info.OptimizedStorage == nil || !*link.Info.OptimizedStorage || link.Info.Backend != b.Backend || link.Info.Type != b.Type {
+			return fmt.Errorf("Backup %d of the chain isn't an optimized %q backup of the same type", i, b.Backend)
+		}
+
+		if i > 0 && (lastUUID == "" || link.Info.Parent != lastUUID) {
+			return fmt.Errorf("Backup %d of the chain isn't based on the last snapshot of the previous backups", i)
+		}
+
+		if len(link.Info.Snapshots) > 0 {
+			lastUUID = link.Info.snapshotUUID(link.Info.Snapshots[len(link.Info.Snapshots)-1])
+		}
+	}
+
+	b.Parents = parents
+
+	return nil
+}
+
+// snapshotUUID returns the volume UUID of a snapshot of the backup.
+func (b Info) snapshotUUID(snapName string) string {
+	if b.Config == nil {
+		return ""
+	}
+
+	rootVol, err := b.Config.RootVolume()
+	if err != nil {
+		return ""
+	}
+
+	for _, snap := range rootVol.Snapshots {
+		if snap != nil && snap.Name == snapName {
+			return snap.Config["volatile.uuid"]
+		}
+	}
+
+	return ""
+}
+
+// RestoredSnapshots returns the names of the snapshots restored from the backup. For incremental backups, these
+// are all the snapshots the instance had when the backup was taken, including the ones stored in its parents.
+func (b Info) RestoredSnapshots() []string {
+	if b.Parent == "" || b.Config == nil {
+		return b.Snapshots
+	}
+
+	snapshots := make([]string, 0, len(b.Config.Snapshots))
+	for _, snap := range b.Config.Snapshots {
+		if snap != nil {
+			snapshots = append(snapshots, snap.Name)
+		}
+	}
+
+	return snapshots
 }
 
 // GetInfo extracts backup information from a given ReadSeeker.
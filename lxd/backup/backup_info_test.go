@@ -0,0 +1,87 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/backup/config"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// testBackupInfo returns the information of an optimized ZFS backup of instance c1 with the given snapshots, the
+// snapshot volume UUIDs being their names prefixed by "uuid-".
+func testBackupInfo(parent string, allSnapshots []string, storedSnapshots []string) Info {
+	optimized := true
+
+	rootVol := &config.Volume{StorageVolume: api.StorageVolume{Name: "c1", Type: "container"}}
+	instSnapshots := make([]*api.InstanceSnapshot, 0, len(allSnapshots))
+	for _, snapName := range allSnapshots {
+		rootVol.Snapshots = append(rootVol.Snapshots, &api.StorageVolumeSnapshot{Name: snapName, Config: map[string]string{"volatile.uuid": "uuid-" + snapName}})
+		instSnapshots = append(instSnapshots, &api.InstanceSnapshot{Name: snapName})
+	}
+
+	return Info{
+		Name:             "c1",
+		Backend:          "zfs",
+		Type:             config.TypeContainer,
+		OptimizedStorage: &optimized,
+		Snapshots:        storedSnapshots,
+		Parent:           parent,
+		Config: &config.Config{
+			Instance:  &api.Instance{Name: "c1"},
+			Snapshots: instSnapshots,
+			Volumes:   []*config.Volume{rootVol},
+		},
+	}
+}
+
+func TestInfoSetParents(t *testing.T) {
+	full := testBackupInfo("", []string{"snap0", "snap1"}, []string{"snap0", "snap1"})
+	incremental1 := testBackupInfo("uuid-snap1", []string{"snap1", "snap2"}, []string{"snap2"})
+	unchanged := testBackupInfo("uuid-snap2", []string{"snap1", "snap2"}, nil)
+
+	tests := []struct {
+		name    string
+		info    Info
+		parents []Info
+		wantErr bool
+	}{
+		{name: "Full backup", info: full},
+		{name: "Full backup with parents", info: full, parents: []Info{full}, wantErr: true},
+		{name: "Incremental backup", info: incremental1, parents: []Info{full}},
+		{name: "Incremental backup without parents", info: incremental1, wantErr: true},
+		{name: "Incremental backup without new snapshots", info: unchanged, parents: []Info{full, incremental1}},
+		{name: "Incremental backup missing a parent", info: unchanged, parents: []Info{full}, wantErr: true},
+		{name: "Incremental backup with incremental first parent", info: unchanged, parents: []Info{incremental1}, wantErr: true},
+		{name: "Incremental backup not based on the last snapshot", info: testBackupInfo("uuid-snap0", []string{"snap0", "snap2"}, []string{"snap2"}), parents: []Info{full}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parents := make([]Link, 0, len(test.parents))
+			for _, parent := range test.parents {
+				parents = append(parents, Link{Info: parent})
+			}
+
+			info := test.info
+			err := info.SetParents(parents)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, info.Chain(nil), len(parents)+1)
+		})
+	}
+}
+
+func TestInfoRestoredSnapshots(t *testing.T) {
+	full := testBackupInfo("", []string{"snap0", "snap1"}, []string{"snap0", "snap1"})
+	assert.Equal(t, []string{"snap0", "snap1"}, full.RestoredSnapshots())
+
+	incremental := testBackupInfo("uuid-snap1", []string{"snap1", "snap2"}, []string{"snap2"})
+	assert.Equal(t, []string{"snap1", "snap2"}, incremental.RestoredSnapshots())
+}
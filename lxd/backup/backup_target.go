@@ -0,0 +1,279 @@
+package backup
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/storage/s3"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+// targetConfigKeys are the validators of the backup target configuration keys.
+var targetConfigKeys = map[string]func(value string) error{
+	// lxdmeta:generate(entities=backup-target; group=conf; key=s3.endpoint)
+	// The URL of the S3 compatible storage, for example `https://s3.example.com`.
+	// Requests use path-style addressing (`<endpoint>/<bucket>/<key>`).
+	// ---
+	//  type: string
+	//  required: yes
+	//  shortdesc: URL of the S3 endpoint
+	"s3.endpoint": validate.Required(validate.IsRequestURL),
+
+	// lxdmeta:generate(entities=backup-target; group=conf; key=s3.bucket)
+	// The bucket must already exist.
+	// ---
+	//  type: string
+	//  required: yes
+	//  shortdesc: Name of the bucket to store backups in
+	"s3.bucket": validate.Required(validate.IsNotEmpty, validate.IsURLSegmentSafe),
+
+	// lxdmeta:generate(entities=backup-target; group=conf; key=s3.prefix)
+	// Backups are stored under `<prefix>/<project>/`.
+	// ---
+	//  type: string
+	//  required: no
+	//  shortdesc: Prefix of the keys of the stored backups
+	"s3.prefix": validate.Optional(validateTargetKey),
+
+	// lxdmeta:generate(entities=backup-target; group=conf; key=s3.region)
+	//
+	// ---
+	//  type: string
+	//  defaultdesc: `us-east-1`
+	//  required: no
+	//  shortdesc: Region used to sign requests
+	"s3.region": validate.IsAny,
+
+	// lxdmeta:generate(entities=backup-target; group=conf; key=s3.access_key)
+	//
+	// ---
+	//  type: string
+	//  required: yes
+	//  shortdesc: Access key of the S3 credentials
+	"s3.access_key": validate.Required(validate.IsNotEmpty),
+
+	// lxdmeta:generate(entities=backup-target; group=conf; key=s3.secret_key)
+	// The secret key is only shown to users allowed to edit the server configuration.
+	// ---
+	//  type: string
+	//  required: yes
+	//  shortdesc: Secret key of the S3 credentials
+	"s3.secret_key": validate.Required(validate.IsNotEmpty),
+
+	// lxdmeta:generate(entities=backup-target; group=conf; key=s3.ca_certificate)
+	// Use this option when the endpoint uses a certificate that isn't trusted by the system.
+	// ---
+	//  type: string
+	//  required: no
+	//  shortdesc: PEM encoded CA certificate of the S3 endpoint
+	"s3.ca_certificate": validate.Optional(validate.IsX509Certificate),
+}
+
+// ValidateTargetConfig validates the configuration of a backup target.
+func ValidateTargetConfig(config map[string]string) error {
+	for key, validator := range targetConfigKeys {
+		err := validator(config[key])
+		if err != nil {
+			return fmt.Errorf("Invalid backup target configuration key %q value: %w", key, err)
+		}
+	}
+
+	for key := range config {
+		// lxdmeta:generate(entities=backup-target; group=conf; key=user.*)
+		// User keys can be used in search.
+		// ---
+		//  type: string
+		//  required: no
+		//  shortdesc: Free form user key/value storage
+		if strings.HasPrefix(key, "user.") {
+			continue
+		}
+
+		_, ok := targetConfigKeys[key]
+		if !ok {
+			return fmt.Errorf("Invalid backup target configuration key %q", key)
+		}
+	}
+
+	return nil
+}
+
+// validateTargetKey checks that a key can be used to address objects of a backup target.
+func validateTargetKey(key string) error {
+	if key == "" {
+		return errors.New("Key cannot be empty")
+	}
+
+	if strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return errors.New("Key cannot start with a slash nor contain back slashes")
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "." || segment == ".." {
+			return errors.New("Key cannot contain relative path segments")
+		}
+	}
+
+	return nil
+}
+
+// Target is a remote S3 compatible storage that backups are uploaded to.
+// The backups of each project are stored under their own prefix, and keys are relative to it.
+type Target struct {
+	name   string
+	prefix string
+	client *s3.Client
+}
+
+// LoadTarget loads the backup target with the given name.
+func LoadTarget(ctx context.Context, s *state.State, name string) (*Target, error) {
+	var config map[string]string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		target, err := cluster.GetBackupTargetWithConfig(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		config = target.Config
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTarget(name, config)
+}
+
+// NewTarget returns the backup target for the given configuration.
+func NewTarget(name string, config map[string]string) (*Target, error) {
+	httpClient := http.DefaultClient
+	if config["s3.ca_certificate"] != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config["s3.ca_certificate"])) {
+			return nil, fmt.Errorf("Invalid CA certificate for backup target %q", name)
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		httpClient = &http.Client{Transport: transport}
+	}
+
+	client, err := s3.NewClient(config["s3.endpoint"], config["s3.bucket"], config["s3.region"], config["s3.access_key"], config["s3.secret_key"], httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("Failed setting up backup target %q: %w", name, err)
+	}
+
+	prefix := strings.Trim(config["s3.prefix"], "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	return &Target{name: name, prefix: prefix, client: client}, nil
+}
+
+// Name returns the name of the backup target.
+func (t *Target) Name() string {
+	return t.name
+}
+
+// Check checks that the bucket of the backup target can be accessed.
+func (t *Target) Check(ctx context.Context) error {
+	err := t.client.CheckBucket(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed accessing bucket of backup target %q: %w", t.name, err)
+	}
+
+	return nil
+}
+
+// objectKey returns the full key of a project backup.
+func (t *Target) objectKey(projectName string, key string) (string, error) {
+	err := validateTargetKey(key)
+	if err != nil {
+		return "", api.StatusErrorf(http.StatusBadRequest, "Invalid backup key %q: %w", key, err)
+	}
+
+	return t.prefix + projectName + "/" + key, nil
+}
+
+// Writer returns a writer uploading a project backup to the given key.
+func (t *Target) Writer(ctx context.Context, projectName string, key string) (*s3.ObjectWriter, error) {
+	objectKey, err := t.objectKey(projectName, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.client.NewObjectWriter(ctx, objectKey), nil
+}
+
+// Reader returns the content of a project backup along with its size.
+func (t *Target) Reader(ctx context.Context, projectName string, key string) (io.ReadCloser, int64, error) {
+	objectKey, err := t.objectKey(projectName, key)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	reader, size, err := t.client.GetObject(ctx, objectKey)
+	if err != nil {
+		if s3.IsNotFound(err) {
+			return nil, -1, api.StatusErrorf(http.StatusNotFound, "Backup %q not found on backup target %q", key, t.name)
+		}
+
+		return nil, -1, fmt.Errorf("Failed reading backup %q from backup target %q: %w", key, t.name, err)
+	}
+
+	return reader, size, nil
+}
+
+// Delete deletes a project backup.
+func (t *Target) Delete(ctx context.Context, projectName string, key string) error {
+	objectKey, err := t.objectKey(projectName, key)
+	if err != nil {
+		return err
+	}
+
+	err = t.client.DeleteObject(ctx, objectKey)
+	if err != nil && !s3.IsNotFound(err) {
+		return fmt.Errorf("Failed deleting backup %q from backup target %q: %w", key, t.name, err)
+	}
+
+	return nil
+}
+
+// List returns the project backups whose key starts with the given prefix, with their keys relative to the project.
+func (t *Target) List(ctx context.Context, projectName string, prefix string) ([]s3.Object, error) {
+	projectPrefix := t.prefix + projectName + "/"
+
+	objects, err := t.client.ListObjects(ctx, projectPrefix+prefix)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing backups of backup target %q: %w", t.name, err)
+	}
+
+	for i := range objects {
+		objects[i].Key = strings.TrimPrefix(objects[i].Key, projectPrefix)
+	}
+
+	return objects, nil
+}
+
+// InstanceBackupKey returns the key of an instance backup.
+func InstanceBackupKey(instanceName string, backupName string) string {
+	return path.Join("instances", instanceName, backupName)
+}
+
+// VolumeBackupKey returns the key of a custom volume backup.
+func VolumeBackupKey(poolName string, volumeName string, backupName string) string {
+	return path.Join("volumes", poolName, volumeName, backupName)
+}
@@ -0,0 +1,160 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/storage/s3"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestValidateTargetConfig(t *testing.T) {
+	valid := map[string]string{
+		"s3.endpoint":   "https://s3.example.com",
+		"s3.bucket":     "backups",
+		"s3.access_key": "key",
+		"s3.secret_key": "secret",
+	}
+
+	tests := []struct {
+		name    string
+		changes map[string]string
+		wantErr bool
+	}{
+		{name: "Valid", changes: nil},
+		{name: "Valid with prefix and user key", changes: map[string]string{"s3.prefix": "lxd/site1", "user.foo": "bar"}},
+		{name: "Missing endpoint", changes: map[string]string{"s3.endpoint": ""}, wantErr: true},
+		{name: "Invalid endpoint", changes: map[string]string{"s3.endpoint": "s3.example.com"}, wantErr: true},
+		{name: "Missing secret key", changes: map[string]string{"s3.secret_key": ""}, wantErr: true},
+		{name: "Bucket with slash", changes: map[string]string{"s3.bucket": "a/b"}, wantErr: true},
+		{name: "Relative prefix", changes: map[string]string{"s3.prefix": "lxd/../other"}, wantErr: true},
+		{name: "Absolute prefix", changes: map[string]string{"s3.prefix": "/lxd"}, wantErr: true},
+		{name: "Invalid CA certificate", changes: map[string]string{"s3.ca_certificate": "foo"}, wantErr: true},
+		{name: "Unknown key", changes: map[string]string{"s3.foo": "bar"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := map[string]string{}
+			for k, v := range valid {
+				config[k] = v
+			}
+
+			for k, v := range test.changes {
+				config[k] = v
+			}
+
+			err := ValidateTargetConfig(config)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// newTestTarget returns a backup target using a local S3 gateway serving a single bucket.
+func newTestTarget(t *testing.T, prefix string) *Target {
+	bucket := &s3.Bucket{Name: "backups", Path: t.TempDir(), CreatedAt: time.Now()}
+	creds := &s3.Credentials{AccessKey: "admin", SecretKey: "adminsecret", Bucket: bucket.Name}
+
+	gateway := &s3.Gateway{
+		Credentials: func(ctx context.Context, accessKey string) (*s3.Credentials, error) {
+			if accessKey != creds.AccessKey {
+				return nil, nil
+			}
+
+			return creds, nil
+		},
+		OpenBucket: func(ctx context.Context, bucketName string) (*s3.Bucket, func(), error) {
+			if bucketName != bucket.Name {
+				return nil, nil, nil
+			}
+
+			return bucket, func() {}, nil
+		},
+	}
+
+	server := httptest.NewServer(gateway)
+	t.Cleanup(server.Close)
+
+	target, err := NewTarget("test", map[string]string{
+		"s3.endpoint":   server.URL,
+		"s3.bucket":     bucket.Name,
+		"s3.prefix":     prefix,
+		"s3.access_key": creds.AccessKey,
+		"s3.secret_key": creds.SecretKey,
+	})
+	require.NoError(t, err)
+
+	return target
+}
+
+func TestTarget(t *testing.T) {
+	ctx := context.Background()
+	target := newTestTarget(t, "/lxd/")
+
+	require.NoError(t, target.Check(ctx))
+
+	// Upload backups in two projects.
+	for _, projectName := range []string{"default", "other"} {
+		w, err := target.Writer(ctx, projectName, InstanceBackupKey("c1", "backup0"))
+		require.NoError(t, err)
+
+		_, err = io.WriteString(w, "backup of "+projectName)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+
+	// Backups are listed per project, with keys relative to it.
+	objects, err := target.List(ctx, "default", "")
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "instances/c1/backup0", objects[0].Key)
+	assert.Equal(t, int64(len("backup of default")), objects[0].Size)
+
+	reader, size, err := target.Reader(ctx, "other", "instances/c1/backup0")
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "backup of other", string(content))
+	assert.Equal(t, int64(len(content)), size)
+
+	// Invalid and missing keys.
+	_, _, err = target.Reader(ctx, "default", "../other/instances/c1/backup0")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusBadRequest))
+
+	_, _, err = target.Reader(ctx, "default", VolumeBackupKey("pool", "vol", "backup0"))
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	// Aborted uploads don't create a backup.
+	w, err := target.Writer(ctx, "default", "instances/c2/backup0")
+	require.NoError(t, err)
+
+	_, err = io.Copy(w, strings.NewReader("partial"))
+	require.NoError(t, err)
+	w.Abort()
+
+	objects, err = target.List(ctx, "default", "instances/c2/")
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+
+	// Deleting a missing backup succeeds.
+	require.NoError(t, target.Delete(ctx, "default", "instances/c1/backup0"))
+	require.NoError(t, target.Delete(ctx, "default", "instances/c1/backup0"))
+
+	objects, err = target.List(ctx, "default", "")
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
@@ -2,8 +2,13 @@ package backup
 
 import (
 	"archive/tar"
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 
@@ -12,6 +17,9 @@ import (
 	"github.com/canonical/lxd/shared"
 )
 
+// ChecksumPath is the path of the trailer file of backup tarballs, which contains the checksum of the tarball.
+const ChecksumPath = "backup/checksum"
+
 // TarReader rewinds backup file handle r and returns new tar reader and process cleanup function.
 func TarReader(s *state.State, r io.ReadSeeker, outputPath string) (*tar.Reader, context.CancelFunc, error) {
 	_, err := r.Seek(0, io.SeekStart)
@@ -54,3 +62,100 @@ func ValidateBackupName(backupName string) (string, error) {
 
 	return backupName, nil
 }
+
+// ChecksumVerifier verifies the checksum trailer of a backup tarball while the tarball is written to it, such as
+// when storing an uploaded backup, so that the backup doesn't need to be read again to be verified.
+type ChecksumVerifier struct {
+	pipeWriter *io.PipeWriter
+	res        chan error
+}
+
+// NewChecksumVerifier returns a ChecksumVerifier for a backup tarball stored at outputPath.
+func NewChecksumVerifier(s *state.State, outputPath string) *ChecksumVerifier {
+	pipeReader, pipeWriter := io.Pipe()
+	v := &ChecksumVerifier{
+		pipeWriter: pipeWriter,
+		res:        make(chan error, 1),
+	}
+
+	go func() {
+		err := verifyChecksum(s, pipeReader, outputPath)
+
+		// Consume the rest of the tarball so that writes don't block.
+		_, _ = io.Copy(io.Discard, pipeReader)
+
+		v.res <- err
+	}()
+
+	return v
+}
+
+// Write passes the next part of the tarball to the verifier.
+func (v *ChecksumVerifier) Write(p []byte) (int, error) {
+	return v.pipeWriter.Write(p)
+}
+
+// Close signals the end of the tarball and returns an error if it doesn't match its checksum.
+func (v *ChecksumVerifier) Close() error {
+	_ = v.pipeWriter.Close()
+	return <-v.res
+}
+
+// verifyChecksum decompresses the backup tarball read from r and checks it against its checksum trailer.
+// Backups without a checksum trailer and those whose format doesn't allow streaming aren't verified.
+func verifyChecksum(s *state.State, r io.Reader, outputPath string) error {
+	br := bufio.NewReader(r)
+
+	// Invalid backups are rejected when reading their index.
+	header, err := br.Peek(263)
+	if err != nil {
+		return nil
+	}
+
+	_, extension, unpacker, err := shared.DetectCompressionFile(bytes.NewReader(header))
+	if err != nil || !strings.HasPrefix(extension, ".tar") {
+		return nil
+	}
+
+	decompressed, cancelFunc, err := archive.DecompressedReader(s, context.Background(), br, unpacker, outputPath)
+	if err != nil {
+		return err
+	}
+
+	defer cancelFunc()
+
+	return verifyTarChecksum(decompressed)
+}
+
+// verifyTarChecksum checks the tarball read from r against the SHA-256 checksum in its trailer file, which covers
+// the tarball up to and including the trailer's header.
+func verifyTarChecksum(r io.Reader) error {
+	hash := sha256.New()
+	tr := tar.NewReader(io.TeeReader(r, hash))
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil // End of archive.
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed reading backup tarball: %w", err)
+		}
+
+		if hdr.Name != ChecksumPath {
+			continue
+		}
+
+		expected := hex.EncodeToString(hash.Sum(nil))
+
+		checksum, err := io.ReadAll(io.LimitReader(tr, int64(len(expected))))
+		if err != nil {
+			return fmt.Errorf("Failed reading backup checksum: %w", err)
+		}
+
+		if string(checksum) != expected {
+			return errors.New("Backup tarball doesn't match its checksum")
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackupTarball returns a backup tarball with the given files, followed by a checksum trailer if withChecksum
+// is true.
+func testBackupTarball(t *testing.T, files map[string]string, withChecksum bool) []byte {
+	var buf bytes.Buffer
+	hash := sha256.New()
+	tw := tar.NewWriter(io.MultiWriter(&buf, hash))
+
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0600, Size: int64(len(content))})
+		require.NoError(t, err)
+
+		_, err = io.WriteString(tw, content)
+		require.NoError(t, err)
+	}
+
+	if withChecksum {
+		err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: ChecksumPath, Mode: 0600, Size: int64(hex.EncodedLen(sha256.Size))})
+		require.NoError(t, err)
+
+		_, err = io.WriteString(tw, hex.EncodeToString(hash.Sum(nil)))
+		require.NoError(t, err)
+	}
+
+	err := tw.Close()
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+func TestVerifyTarChecksum(t *testing.T) {
+	files := map[string]string{
+		"backup/index.yaml":           "name: c1\n",
+		"backup/container/rootfs/foo": "hello",
+	}
+
+	// Backups matching their checksum are accepted.
+	tarball := testBackupTarball(t, files, true)
+	assert.NoError(t, verifyTarChecksum(bytes.NewReader(tarball)))
+
+	// Backups without a checksum trailer aren't verified.
+	assert.NoError(t, verifyTarChecksum(bytes.NewReader(testBackupTarball(t, files, false))))
+
+	// Backups whose content changed are rejected.
+	corrupted := bytes.Replace(tarball, []byte("hello"), []byte("jello"), 1)
+	assert.NotEqual(t, tarball, corrupted)
+	assert.Error(t, verifyTarChecksum(bytes.NewReader(corrupted)))
+}
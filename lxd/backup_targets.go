@@ -0,0 +1,665 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/backup"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/validate"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var backupTargetsCmd = APIEndpoint{
+	Path:        "backup-targets",
+	MetricsType: entity.TypeServer,
+
+	Get:  APIEndpointAction{Handler: backupTargetsGet, AccessHandler: allowAuthenticated},
+	Post: APIEndpointAction{Handler: backupTargetsPost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+var backupTargetCmd = APIEndpoint{
+	Path:        "backup-targets/{name}",
+	MetricsType: entity.TypeServer,
+
+	Get:    APIEndpointAction{Handler: backupTargetGet, AccessHandler: allowAuthenticated},
+	Post:   APIEndpointAction{Handler: backupTargetPost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: backupTargetPatch, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+	Put:    APIEndpointAction{Handler: backupTargetPut, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: backupTargetDelete, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+var backupTargetBackupsCmd = APIEndpoint{
+	Path:        "backup-targets/{name}/backups",
+	MetricsType: entity.TypeServer,
+
+	Get: APIEndpointAction{Handler: backupTargetBackupsGet, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanView)},
+}
+
+// backupTargetURL returns the URL of a backup target.
+func backupTargetURL(name string) string {
+	return api.NewURL().Path(version.APIVersion, "backup-targets", name).String()
+}
+
+// backupTargetHideSecrets removes the secret key from the backup target configuration unless the requestor can edit
+// the server configuration.
+func backupTargetHideSecrets(ctx context.Context, s *state.State, targets ...*api.BackupTarget) error {
+	err := s.Authorizer.CheckPermission(ctx, entity.ServerURL(), auth.EntitlementCanEdit)
+	if err == nil {
+		return nil
+	}
+
+	if !auth.IsDeniedError(err) {
+		return err
+	}
+
+	for _, target := range targets {
+		delete(target.Config, "s3.secret_key")
+	}
+
+	return nil
+}
+
+// swagger:operation GET /1.0/backup-targets backup-targets backup_targets_get
+//
+//	Get the backup targets
+//
+//	Returns a list of backup targets (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/backup-targets/s3-backups"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/backup-targets?recursion=1 backup-targets backup_targets_get_recursion1
+//
+//	Get the backup targets
+//
+//	Returns a list of backup targets (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Backup targets
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of backup targets
+//	          items:
+//	            $ref: "#/definitions/BackupTarget"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion, _ := util.IsRecursionRequest(r)
+
+	var targets []dbCluster.BackupTargetRow
+	var allConfigs map[int64]map[string]string
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		targets, err = dbCluster.GetBackupTargets(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		if recursion != 0 && len(targets) > 0 {
+			allConfigs, err = dbCluster.BackupTargetsConfigStore().GetAll(ctx, tx.Tx())
+			if err != nil {
+				return fmt.Errorf("Failed loading backup target configs: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion == 0 {
+		urls := make([]string, 0, len(targets))
+		for _, target := range targets {
+			urls = append(urls, backupTargetURL(target.Name))
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	apiTargets := make([]*api.BackupTarget, 0, len(targets))
+	for _, target := range targets {
+		apiTargets = append(apiTargets, target.ToAPI(allConfigs))
+	}
+
+	err = backupTargetHideSecrets(r.Context(), s, apiTargets...)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, apiTargets)
+}
+
+// swagger:operation POST /1.0/backup-targets backup-targets backup_targets_post
+//
+//	Add a backup target
+//
+//	Creates a new backup target.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: backup_target
+//	    description: Backup target
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BackupTargetsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.BackupTargetsPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = validateBackupTargetName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Config == nil {
+		req.Config = map[string]string{}
+	}
+
+	err = backupTargetValidate(r.Context(), req.Name, req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, err := dbCluster.CreateBackupTarget(ctx, tx.Tx(), dbCluster.BackupTargetRow{Name: req.Name, Description: req.Description})
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.BackupTargetsConfigStore().Set(ctx, tx.Tx(), id, req.Config)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.BackupTargetCreated.Event(req.Name, request.CreateRequestor(r.Context()), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/backup-targets/{name} backup-targets backup_target_get
+//
+//	Get the backup target
+//
+//	Gets a specific backup target.
+//	The secret key is only included if the requestor can edit the server configuration.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Backup target
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/BackupTarget"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name := r.PathValue("name")
+
+	var target *api.BackupTarget
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		target, err = dbCluster.GetBackupTargetWithConfig(ctx, tx.Tx(), name)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = backupTargetHideSecrets(r.Context(), s, target)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, target, target.Writable())
+}
+
+// updateBackupTarget is shared between [backupTargetPut] and [backupTargetPatch].
+func updateBackupTarget(s *state.State, r *http.Request, isPatch bool) response.Response {
+	name := r.PathValue("name")
+
+	var target *api.BackupTarget
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		target, err = dbCluster.GetBackupTargetWithConfig(ctx, tx.Tx(), name)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate ETag.
+	err = util.EtagCheck(r, target.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.BackupTargetPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Config == nil {
+		req.Config = map[string]string{}
+	}
+
+	if isPatch {
+		// Populate request config with current values.
+		for k, v := range target.Config {
+			_, ok := req.Config[k]
+			if !ok {
+				req.Config[k] = v
+			}
+		}
+
+		if req.Description == "" {
+			req.Description = target.Description
+		}
+	}
+
+	if !maps.Equal(target.Config, req.Config) {
+		err = backupTargetValidate(r.Context(), name, req.Config)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbTarget, err := dbCluster.GetBackupTarget(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		dbTarget.Description = req.Description
+		err = dbCluster.UpdateBackupTarget(ctx, tx.Tx(), *dbTarget)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.BackupTargetsConfigStore().Set(ctx, tx.Tx(), dbTarget.ID, req.Config)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.BackupTargetUpdated.Event(name, request.CreateRequestor(r.Context()), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation PATCH /1.0/backup-targets/{name} backup-targets backup_target_patch
+//
+//	Partially update the backup target
+//
+//	Updates a subset of the backup target configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: backup_target
+//	    description: Backup target configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BackupTargetPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetPatch(d *Daemon, r *http.Request) response.Response {
+	return updateBackupTarget(d.State(), r, true)
+}
+
+// swagger:operation PUT /1.0/backup-targets/{name} backup-targets backup_target_put
+//
+//	Update the backup target
+//
+//	Updates the entire backup target configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: backup_target
+//	    description: Backup target configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BackupTargetPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetPut(d *Daemon, r *http.Request) response.Response {
+	return updateBackupTarget(d.State(), r, false)
+}
+
+// swagger:operation POST /1.0/backup-targets/{name} backup-targets backup_target_post
+//
+//	Rename the backup target
+//
+//	Renames the backup target.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: backup_target
+//	    description: Rename backup target request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BackupTargetPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name := r.PathValue("name")
+
+	req := api.BackupTargetPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = validateBackupTargetName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.RenameBackupTarget(ctx, tx.Tx(), name, req.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.BackupTargetRenamed.Event(req.Name, request.CreateRequestor(r.Context()), logger.Ctx{"old_name": name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/backup-targets/{name} backup-targets backup_target_delete
+//
+//	Delete the backup target
+//
+//	Removes the backup target. The backups stored on it are kept.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name := r.PathValue("name")
+
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.DeleteBackupTarget(ctx, tx.Tx(), name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.BackupTargetDeleted.Event(name, request.CreateRequestor(r.Context()), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/backup-targets/{name}/backups backup-targets backup_target_backups_get
+//
+//	Get the backups of a project
+//
+//	Returns the backups of the project stored on the backup target.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Backups
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of backups
+//	          items:
+//	            $ref: "#/definitions/BackupTargetBackup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetBackupsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	target, err := backup.LoadTarget(r.Context(), s, r.PathValue("name"))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	objects, err := target.List(r.Context(), request.ProjectParam(r), "")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	backups := make([]api.BackupTargetBackup, 0, len(objects))
+	for _, object := range objects {
+		backups = append(backups, api.BackupTargetBackup{Key: object.Key, Size: object.Size, CreatedAt: object.LastModified})
+	}
+
+	return response.SyncResponse(true, backups)
+}
+
+// validateBackupTargetName checks that the name can be used for a backup target.
+func validateBackupTargetName(name string) error {
+	if name == "" {
+		return errors.New("Backup target name cannot be empty")
+	}
+
+	err := validate.IsURLSegmentSafe(name)
+	if err != nil {
+		return err
+	}
+
+	// Defend against path traversal attacks.
+	if !shared.IsFileName(name) {
+		return fmt.Errorf("Invalid name %q, may not contain slashes or consecutive dots", name)
+	}
+
+	return validate.IsEntityName(name)
+}
+
+// backupTargetValidate validates the backup target configuration and checks that its bucket can be accessed.
+func backupTargetValidate(ctx context.Context, name string, config map[string]string) error {
+	err := backup.ValidateTargetConfig(config)
+	if err != nil {
+		return err
+	}
+
+	target, err := backup.NewTarget(name, config)
+	if err != nil {
+		return err
+	}
+
+	return target.Check(ctx)
+}
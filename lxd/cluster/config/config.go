@@ -15,8 +15,10 @@ import (
 
 	"github.com/canonical/lxd/lxd/config"
 	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/storage/encryption"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/trust"
 	"github.com/canonical/lxd/shared/validate"
 )
 
@@ -185,6 +187,16 @@ func (c *Config) ImagesRemoteCacheExpiryDays() int64 {
 	return c.m.GetInt64("images.remote_cache_expiry")
 }
 
+// ImagesSignatureTrustedKeys returns the keys trusted to sign remote images.
+func (c *Config) ImagesSignatureTrustedKeys() string {
+	return c.m.GetString("images.signature.trusted_keys")
+}
+
+// StorageEncryptionKeyProvider returns the key provider used to wrap the keys of new encrypted volumes.
+func (c *Config) StorageEncryptionKeyProvider() string {
+	return c.m.GetString("storage.encryption.key_provider")
+}
+
 // InstancesNICHostname returns hostname mode to use for instance NICs.
 func (c *Config) InstancesNICHostname() string {
 	return c.m.GetString("instances.nic.host_name")
@@ -624,6 +636,18 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: When an unused cached remote image is flushed
 		"images.remote_cache_expiry": {Type: config.Int64, Default: "10"},
 
+		// lxdmeta:generate(entities=server; group=images; key=images.signature.trusted_keys)
+		// Specify a list of SSH public keys (one per line, in the `authorized_keys` format), ASCII armored OpenPGP public key blocks or PEM encoded certificates.
+		// When set, images downloaded from remote servers must carry a valid detached signature made by one of those keys.
+		//
+		// Keys listed after a `[<server URL>]` line are only trusted for images downloaded from that server.
+		// Keys listed before any such line are trusted for images downloaded from all servers.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Keys trusted to sign remote images
+		"images.signature.trusted_keys": {Validator: imageSignatureTrustedKeysValidator},
+
 		// lxdmeta:generate(entities=server; group=miscellaneous; key=instances.nic.host_name)
 		// Possible values are `random` and `mac`.
 		//
@@ -713,14 +737,14 @@ var ConfigSchema = config.Schema{
 
 		// lxdmeta:generate(entities=server; group=loki; key=loki.types)
 		// Specify a comma-separated list of events to send to the Loki server.
-		// The events can be any combination of `lifecycle`, `logging`, `ovn`, and `security`.
+		// The events can be any combination of `lifecycle`, `logging`, `network-acl`, `ovn`, and `security`.
 		// ---
 		//  type: string
 		//  scope: global
 		//  defaultdesc: `lifecycle,logging`
 		//  shortdesc: Events to send to the Loki server
 		"loki.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf(
-			api.EventTypeLifecycle, api.EventTypeLogging, api.EventTypeOVN, api.EventTypeSecurity,
+			api.EventTypeLifecycle, api.EventTypeLogging, api.EventTypeNetworkACL, api.EventTypeOVN, api.EventTypeSecurity,
 		))), Default: "lifecycle,logging"},
 
 		// lxdmeta:generate(entities=server; group=oidc; key=oidc.client.id)
@@ -876,6 +900,18 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: OVN SSL client key
 		"network.ovn.client_key": {Default: ""},
 
+		// lxdmeta:generate(entities=server; group=miscellaneous; key=storage.encryption.key_provider)
+		// The key provider wraps the keys of new encrypted storage volumes (see `block.encryption`).
+		// The `file` provider uses a key encryption key stored in `storage-encryption.key` in the LXD directory, which must be identical on all cluster members.
+		// It is generated automatically on standalone servers only, and must be copied to all members of a cluster.
+		// Existing keys are always unwrapped with the key provider that wrapped them.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `file`
+		//  shortdesc: Key provider used to wrap the keys of encrypted storage volumes
+		"storage.encryption.key_provider": {Default: encryption.DefaultKeyProvider, Validator: validate.IsOneOf(encryption.Providers()...)},
+
 		// lxdmeta:generate(entities=server; group=miscellaneous; key=volatile.uuid)
 		// This UUID is used as a stable identifier for the cluster. It cannot be changed.
 		// ---
@@ -942,6 +978,12 @@ func imageMinimalReplicaValidator(value string) error {
 	return nil
 }
 
+func imageSignatureTrustedKeysValidator(value string) error {
+	_, err := trust.ParseImageSignatureKeys(value, "")
+
+	return err
+}
+
 func maxVotersValidator(value string) error {
 	n, err := strconv.Atoi(value)
 	if err != nil {
@@ -1536,6 +1536,7 @@ func (d *Daemon) init() error {
 		ClusterAddress:       localClusterAddress,
 		DebugAddress:         debugAddress,
 		MetricsServer:        metricsServer(d),
+		StorageBucketsServer: storageBucketsServer(d),
 		VsockServer:          vSockServer(d),
 		VsockSupport:         false,
 	}
@@ -1897,6 +1898,14 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	storageBucketsAddress := d.localConfig.StorageBucketsAddress()
+	if storageBucketsAddress != "" {
+		err = d.endpoints.UpStorageBuckets(storageBucketsAddress)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Apply all patches that need to be run after networks are initialised.
 	err = patchesApply(d, patchPostNetworks)
 	if err != nil {
@@ -2020,6 +2029,9 @@ func (d *Daemon) init() error {
 		// Prune expired custom volume snapshots and take snapshots of custom volumes (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d.State))
 
+		// Take scheduled backups of instances and custom volumes and prune old ones (minutely check of configurable cron expression)
+		d.tasks.Add(autoCreateAndPruneScheduledBackupsTask(d.State))
+
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d.State))
 
@@ -2034,6 +2046,12 @@ func (d *Daemon) init() error {
 
 		// Synchronize operations with the database (minutely)
 		d.tasks.Add(synchronizeOperationsTask(d.State))
+
+		// Check the storage pool capacity thresholds (every 5 minutes)
+		d.tasks.Add(storagePoolCapacityTask(d.State))
+
+		// Re-resolve the domain names of the network address sets (every minute)
+		d.tasks.Add(networkAddressSetsRefreshTask(d.State))
 	}
 
 	// Load Ubuntu Pro configuration before starting any instances.
@@ -2403,7 +2421,7 @@ func (d *Daemon) setupSyslogSocket(enable bool) error {
 
 	logger.Debug("Starting syslog socket")
 
-	err := StartSyslogListener(ctx, d.events)
+	err := StartSyslogListener(ctx, d.State())
 	if err != nil {
 		return err
 	}
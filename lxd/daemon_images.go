@@ -27,6 +27,7 @@ import (
 	"github.com/canonical/lxd/shared/ioprogress"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/trust"
 	"github.com/canonical/lxd/shared/version"
 )
 
@@ -82,7 +83,7 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 	fp := alias
 
 	// Attempt to resolve the alias
-	if slices.Contains([]string{"lxd", "simplestreams"}, protocol) {
+	if slices.Contains([]string{"lxd", "simplestreams", "oci"}, protocol) {
 		// Use the proxy from args if provided (e.g., a no-op proxy for intra-cluster connections),
 		// otherwise fall back to the daemon-level proxy.
 		proxy := args.Proxy
@@ -109,6 +110,14 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 			if ok {
 				remote = server.UseProject(args.SourceProjectName)
 			}
+		} else if protocol == "oci" {
+			// Setup OCI registry client
+			remote, err = lxd.ConnectOCI(args.Server, clientArgs)
+			if err != nil {
+				return nil, fmt.Errorf("Failed connecting to OCI registry %q: %w", args.Server, err)
+			}
+
+			defer remote.Disconnect()
 		} else {
 			// Setup simplestreams client
 			remote, err = lxd.ConnectSimpleStreams(args.Server, clientArgs)
@@ -187,6 +196,28 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 
 	var imgInfo *api.Image
 
+	// Whether the image record exists in the project but the image must be downloaded again.
+	var imgRecordExists bool
+
+	// checkLocalSignature checks the signature of an image already available locally against the keys trusted for
+	// the remote server, so that requesting a remote image can't bypass the signature check when an image with the
+	// same fingerprint was added from elsewhere. The signature provided by the remote server for the same image is
+	// preferred, as it may have been added or changed since.
+	checkLocalSignature := func(img *api.Image) error {
+		if args.Server == "" {
+			return nil
+		}
+
+		signature := img.Signature
+		if info != nil && info.Fingerprint == img.Fingerprint && info.Signature != nil {
+			signature = info.Signature
+		}
+
+		_, err := imageSignatureCheck(s.GlobalConfig.ImagesSignatureTrustedKeys(), args.Server, img.Fingerprint, signature, true)
+
+		return err
+	}
+
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Check if the image already exists in this project (partial hash match).
 		_, imgInfo, err = tx.GetImage(ctx, fp, cluster.ImageFilter{Project: &args.ProjectName})
@@ -194,6 +225,11 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 		return err
 	})
 	if err == nil {
+		err = checkLocalSignature(imgInfo)
+		if err != nil {
+			return nil, err
+		}
+
 		var nodeAddress string
 
 		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -202,7 +238,13 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 
 			return err
 		})
-		if err != nil {
+		if err != nil && info != nil && info.Fingerprint == imgInfo.Fingerprint && api.StatusErrorCheck(err, http.StatusServiceUnavailable) {
+			// The members holding the image are offline, for example because the download was interrupted by a
+			// member failure and restarted on this member. Download the image again from its source.
+			l.Warn("Image isn't available on any online member, downloading it again", logger.Ctx{"fingerprint": imgInfo.Fingerprint})
+			imgRecordExists = true
+			imgInfo = nil
+		} else if err != nil {
 			return nil, fmt.Errorf("Failed locating image %q in the cluster: %w", imgInfo.Fingerprint, err)
 		}
 
@@ -229,8 +271,14 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 			return err
 		})
 		if err == nil {
+			err = checkLocalSignature(imgInfo)
+			if err != nil {
+				return nil, err
+			}
+
 			var nodeAddress string
 			otherProject := imgInfo.Project
+			otherImgInfo := imgInfo
 
 			err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 				// Check if the image is available locally or it's on another node. Do this before creating
@@ -261,6 +309,13 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 					return err
 				}
 
+				if otherImgInfo.Signature != nil {
+					err = tx.CreateImageSignature(ctx, id, *otherImgInfo.Signature)
+					if err != nil {
+						return fmt.Errorf("Failed recording image signature for project: %w", err)
+					}
+				}
+
 				return tx.CreateImageSource(ctx, id, args.Server, args.Protocol, args.Certificate, alias)
 			})
 			if err != nil {
@@ -358,10 +413,16 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 
 	// Validate the protocol. This is after handling cached images to allow previously downloaded images that were
 	// retrieved using the now deprecated "direct" protocol.
-	if !slices.Contains([]string{"lxd", "simplestreams"}, protocol) {
+	if !slices.Contains([]string{"lxd", "simplestreams", "oci"}, protocol) {
 		return nil, fmt.Errorf("Unsupported protocol: %v", protocol)
 	}
 
+	// Check the image signature against the trusted keys before downloading anything.
+	info.Signature, err = imageSignatureCheck(s.GlobalConfig.ImagesSignatureTrustedKeys(), args.Server, info.Fingerprint, info.Signature, true)
+	if err != nil {
+		return nil, err
+	}
+
 	// Begin downloading
 	if op != nil {
 		l = l.AddContext(logger.Ctx{"trigger": op.URL(), "operation": op.ID()})
@@ -490,8 +551,27 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 	}
 
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// The image record already exists in the project, just add the node ID to the image.
+		if imgRecordExists {
+			return tx.AddImageToLocalNode(ctx, args.ProjectName, info.Fingerprint)
+		}
+
 		// Create the database entry
-		return tx.CreateImage(ctx, args.ProjectName, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, nil)
+		err := tx.CreateImage(ctx, args.ProjectName, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, nil)
+		if err != nil {
+			return err
+		}
+
+		if info.Signature == nil {
+			return nil
+		}
+
+		id, _, err := tx.GetImage(ctx, info.Fingerprint, cluster.ImageFilter{Project: &args.ProjectName})
+		if err != nil {
+			return err
+		}
+
+		return tx.CreateImageSignature(ctx, id, *info.Signature)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("Failed creating image record: %w", err)
@@ -501,7 +581,7 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 	reverter.Success()
 
 	// Record the image source
-	if alias != fp {
+	if alias != fp && !imgRecordExists {
 		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 			id, _, err := tx.GetImage(ctx, fp, cluster.ImageFilter{Project: &args.ProjectName})
 			if err != nil {
@@ -546,3 +626,51 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 
 	return info, nil
 }
+
+// imageSignatureCheck checks the detached signature of an image against the trusted keys (as set in
+// "images.signature.trusted_keys") for the given remote server and returns the signature along with its verification
+// status. If enforce is true, unsigned images and images whose signature can't be verified are rejected whenever
+// trusted keys are configured for the server.
+func imageSignatureCheck(trustedKeys string, server string, fingerprint string, signature *api.ImageSignature, enforce bool) (*api.ImageSignature, error) {
+	keyring, err := trust.ParseImageSignatureKeys(trustedKeys, server)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing trusted image signing keys: %w", err)
+	}
+
+	if signature == nil || signature.Signature == "" {
+		if enforce && !keyring.Empty() {
+			return nil, api.StatusErrorf(http.StatusForbidden, "Image %q isn't signed by a trusted key", fingerprint)
+		}
+
+		return nil, nil
+	}
+
+	sigType, err := trust.ImageSignatureType(signature.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid signature for image %q: %w", fingerprint, err)
+	}
+
+	result := &api.ImageSignature{
+		Signature: signature.Signature,
+		Type:      sigType,
+		Status:    api.ImageSignatureStatusUnverified,
+	}
+
+	if keyring.Empty() {
+		return result, nil
+	}
+
+	_, keyFingerprint, err := keyring.Verify(fingerprint, signature.Signature)
+	if err != nil {
+		if enforce {
+			return nil, api.StatusErrorf(http.StatusForbidden, "Failed verifying signature of image %q: %v", fingerprint, err)
+		}
+
+		return result, nil
+	}
+
+	result.KeyFingerprint = keyFingerprint
+	result.Status = api.ImageSignatureStatusVerified
+
+	return result, nil
+}
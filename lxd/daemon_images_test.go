@@ -0,0 +1,69 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/trust"
+)
+
+func TestImageSignatureCheck(t *testing.T) {
+	fingerprint := strings.Repeat("a", 64)
+	otherFingerprint := strings.Repeat("b", 64)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	publicKey, err := ssh.NewPublicKey(key.Public())
+	require.NoError(t, err)
+
+	trustedKeys := string(ssh.MarshalAuthorizedKey(publicKey))
+
+	signature, err := trust.SignImage(fingerprint, key)
+	require.NoError(t, err)
+
+	otherSignature, err := trust.SignImage(otherFingerprint, key)
+	require.NoError(t, err)
+
+	// Images signed by a trusted key are verified.
+	result, err := imageSignatureCheck(trustedKeys, "https://images.example.com", fingerprint, &api.ImageSignature{Signature: signature}, true)
+	require.NoError(t, err)
+	assert.Equal(t, api.ImageSignatureStatusVerified, result.Status)
+	assert.Equal(t, ssh.FingerprintSHA256(publicKey), result.KeyFingerprint)
+
+	// Unsigned images are rejected once trusted keys are set.
+	_, err = imageSignatureCheck(trustedKeys, "https://images.example.com", fingerprint, nil, true)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden))
+
+	// Signatures of another image are rejected.
+	_, err = imageSignatureCheck(trustedKeys, "https://images.example.com", fingerprint, &api.ImageSignature{Signature: otherSignature}, true)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden))
+
+	// Malformed signatures are rejected.
+	_, err = imageSignatureCheck(trustedKeys, "https://images.example.com", fingerprint, &api.ImageSignature{Signature: "invalid"}, true)
+	assert.Error(t, err)
+
+	// Keys trusted for another server don't apply, so unsigned images are accepted and signatures aren't verified.
+	scopedKeys := "[https://other.example.com]\n" + trustedKeys
+	result, err = imageSignatureCheck(scopedKeys, "https://images.example.com", fingerprint, nil, true)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	result, err = imageSignatureCheck(scopedKeys, "https://images.example.com", fingerprint, &api.ImageSignature{Signature: signature}, true)
+	require.NoError(t, err)
+	assert.Equal(t, api.ImageSignatureStatusUnverified, result.Status)
+
+	// Signatures that can't be verified are only recorded when not enforced.
+	result, err = imageSignatureCheck(trustedKeys, "", fingerprint, &api.ImageSignature{Signature: otherSignature}, false)
+	require.NoError(t, err)
+	assert.Equal(t, api.ImageSignatureStatusUnverified, result.Status)
+}
@@ -0,0 +1,102 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// BackupTargetRow represents a single row of the backup_targets table.
+// db:model backup_targets
+type BackupTargetRow struct {
+	ID          int64  `db:"id"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (BackupTargetRow) APIName() string {
+	return "Backup target"
+}
+
+// ToAPI converts the database [BackupTargetRow] struct to API type [api.BackupTarget].
+func (r *BackupTargetRow) ToAPI(allConfigs map[int64]map[string]string) *api.BackupTarget {
+	config := allConfigs[r.ID]
+	if config == nil {
+		config = map[string]string{}
+	}
+
+	return &api.BackupTarget{
+		Name:        r.Name,
+		Description: r.Description,
+		Config:      config,
+	}
+}
+
+// BackupTargetsConfigStore returns a [query.EntityConfigStore] for backup targets.
+func BackupTargetsConfigStore() *query.EntityConfigStore {
+	return &query.EntityConfigStore{
+		EntityTable:               "backup_targets",
+		ConfigTable:               "backup_targets_config",
+		ConfigTableEntityIDColumn: "backup_target_id",
+	}
+}
+
+// GetBackupTargets returns all backup targets.
+func GetBackupTargets(ctx context.Context, tx *sql.Tx) ([]BackupTargetRow, error) {
+	return query.Select[BackupTargetRow](ctx, tx, "ORDER BY name")
+}
+
+// GetBackupTarget returns the backup target with the given name.
+func GetBackupTarget(ctx context.Context, tx *sql.Tx, name string) (*BackupTargetRow, error) {
+	target, err := query.SelectOne[BackupTargetRow](ctx, tx, "WHERE name = ?", name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading backup target: %w", err)
+	}
+
+	return target, nil
+}
+
+// GetBackupTargetWithConfig returns the API representation of the backup target with the given name.
+func GetBackupTargetWithConfig(ctx context.Context, tx *sql.Tx, name string) (*api.BackupTarget, error) {
+	target, err := GetBackupTarget(ctx, tx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := BackupTargetsConfigStore().GetByEntityIDs(ctx, tx, target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading backup target config: %w", err)
+	}
+
+	return target.ToAPI(config), nil
+}
+
+// CreateBackupTarget adds a new backup target to the database.
+func CreateBackupTarget(ctx context.Context, tx *sql.Tx, object BackupTargetRow) (int64, error) {
+	return query.Create(ctx, tx, object)
+}
+
+// UpdateBackupTarget updates the backup target row by its ID.
+func UpdateBackupTarget(ctx context.Context, tx *sql.Tx, object BackupTargetRow) error {
+	return query.UpdateByPrimaryKey(ctx, tx, object)
+}
+
+// DeleteBackupTarget deletes the backup target with the given name.
+func DeleteBackupTarget(ctx context.Context, tx *sql.Tx, name string) error {
+	return query.DeleteOne[BackupTargetRow, *BackupTargetRow](ctx, tx, "WHERE name = ?", name)
+}
+
+// RenameBackupTarget renames the backup target with the given name.
+func RenameBackupTarget(ctx context.Context, tx *sql.Tx, name string, to string) error {
+	target, err := GetBackupTarget(ctx, tx, name)
+	if err != nil {
+		return err
+	}
+
+	target.Name = to
+	return query.UpdateByPrimaryKey(ctx, tx, *target)
+}
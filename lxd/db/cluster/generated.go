@@ -61,6 +61,63 @@ func (a AuthGroupsRow) UpdateStmt() string {
 	return "UPDATE auth_groups SET name = ?, description = ? "
 }
 
+// TableName returns the table name for [BackupTargetRow] entities.
+func (b BackupTargetRow) TableName() string {
+	return "backup_targets"
+}
+
+// SelectColumns returns a slice of column names for [BackupTargetRow] entities.
+func (b BackupTargetRow) SelectColumns() []string {
+	return []string{
+		"backup_targets.id",
+		"backup_targets.name",
+		"backup_targets.description",
+	}
+}
+
+// Joins returns a slice of join expressions for [BackupTargetRow].
+func (b BackupTargetRow) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [BackupTargetRow].
+// This returns references to struct fields in definition order.
+func (b *BackupTargetRow) ScanArgs() []any {
+	return []any{&b.ID, &b.Name, &b.Description}
+}
+
+// CreateValues returns a list of values from [BackupTargetRow] entities matching the bind arguments in [CreateStmt].
+func (b BackupTargetRow) CreateValues() []any {
+	return []any{b.Name, b.Description}
+}
+
+// UpdateValues returns a list of values from [BackupTargetRow] entities matching the columns in [UpdateStmt].
+func (b BackupTargetRow) UpdateValues() []any {
+	return []any{b.Name, b.Description}
+}
+
+// PKColumns returns the column names for the primary key of a [BackupTargetRow] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (b BackupTargetRow) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [BackupTargetRow] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (b BackupTargetRow) PKValues() []any {
+	return []any{b.ID}
+}
+
+// CreateStmt returns a query that creates a [BackupTargetRow] entity.
+func (b BackupTargetRow) CreateStmt() string {
+	return "INSERT INTO backup_targets (name, description) VALUES (?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [BackupTargetRow] by primary key.
+func (b BackupTargetRow) UpdateStmt() string {
+	return "UPDATE backup_targets SET name = ?, description = ? "
+}
+
 // TableName returns the table name for [CertificatesRow] entities.
 func (c CertificatesRow) TableName() string {
 	return "certificates"
@@ -973,6 +1030,102 @@ func (r *Replicator) ScanArgs() []any {
 	return []any{&r.Row.ID, &r.Row.Name, &r.Row.ProjectID, &r.Row.Description, &r.Row.LastRunDate, &r.Row.LastRunStatus, &r.ProjectName}
 }
 
+// TableName returns the table name for [ReplicatorInstance] entities.
+func (r ReplicatorInstance) TableName() string {
+	return "replicators_instances"
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (r ReplicatorInstance) APIName() string {
+	return r.Row.APIName()
+}
+
+// SelectColumns returns a slice of column names for [ReplicatorInstance] entities.
+func (r ReplicatorInstance) SelectColumns() []string {
+	return []string{
+		"replicators_instances.id",
+		"replicators_instances.replicator_id",
+		"replicators_instances.instance_id",
+		"replicators_instances.snapshot_name",
+		"replicators_instances.run_date",
+		"instances.name",
+		"projects.name",
+	}
+}
+
+// Joins returns a slice of join expressions for [ReplicatorInstance].
+func (r ReplicatorInstance) Joins() []string {
+	return []string{
+		"JOIN instances ON replicators_instances.instance_id = instances.id",
+		"JOIN projects ON instances.project_id = projects.id",
+	}
+}
+
+// ScanArgs implements [query.ScanArger] for [ReplicatorInstance].
+// This returns references to struct fields in definition order.
+func (r *ReplicatorInstance) ScanArgs() []any {
+	return []any{&r.Row.ID, &r.Row.ReplicatorID, &r.Row.InstanceID, &r.Row.SnapshotName, &r.Row.RunDate, &r.InstanceName, &r.ProjectName}
+}
+
+// TableName returns the table name for [ReplicatorInstanceRow] entities.
+func (r ReplicatorInstanceRow) TableName() string {
+	return "replicators_instances"
+}
+
+// SelectColumns returns a slice of column names for [ReplicatorInstanceRow] entities.
+func (r ReplicatorInstanceRow) SelectColumns() []string {
+	return []string{
+		"replicators_instances.id",
+		"replicators_instances.replicator_id",
+		"replicators_instances.instance_id",
+		"replicators_instances.snapshot_name",
+		"replicators_instances.run_date",
+	}
+}
+
+// Joins returns a slice of join expressions for [ReplicatorInstanceRow].
+func (r ReplicatorInstanceRow) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [ReplicatorInstanceRow].
+// This returns references to struct fields in definition order.
+func (r *ReplicatorInstanceRow) ScanArgs() []any {
+	return []any{&r.ID, &r.ReplicatorID, &r.InstanceID, &r.SnapshotName, &r.RunDate}
+}
+
+// CreateValues returns a list of values from [ReplicatorInstanceRow] entities matching the bind arguments in [CreateStmt].
+func (r ReplicatorInstanceRow) CreateValues() []any {
+	return []any{r.ReplicatorID, r.InstanceID, r.SnapshotName, r.RunDate}
+}
+
+// UpdateValues returns a list of values from [ReplicatorInstanceRow] entities matching the columns in [UpdateStmt].
+func (r ReplicatorInstanceRow) UpdateValues() []any {
+	return []any{r.ReplicatorID, r.InstanceID, r.SnapshotName, r.RunDate}
+}
+
+// PKColumns returns the column names for the primary key of a [ReplicatorInstanceRow] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (r ReplicatorInstanceRow) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [ReplicatorInstanceRow] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (r ReplicatorInstanceRow) PKValues() []any {
+	return []any{r.ID}
+}
+
+// CreateStmt returns a query that creates a [ReplicatorInstanceRow] entity.
+func (r ReplicatorInstanceRow) CreateStmt() string {
+	return "INSERT INTO replicators_instances (replicator_id, instance_id, snapshot_name, run_date) VALUES (?, ?, ?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [ReplicatorInstanceRow] by primary key.
+func (r ReplicatorInstanceRow) UpdateStmt() string {
+	return "UPDATE replicators_instances SET replicator_id = ?, instance_id = ?, snapshot_name = ?, run_date = ? "
+}
+
 // TableName returns the table name for [ReplicatorRow] entities.
 func (r ReplicatorRow) TableName() string {
 	return "replicators"
@@ -1032,3 +1185,124 @@ func (r ReplicatorRow) CreateStmt() string {
 func (r ReplicatorRow) UpdateStmt() string {
 	return "UPDATE replicators SET name = ?, project_id = ?, description = ?, last_run_date = ?, last_run_status = ? "
 }
+
+// TableName returns the table name for [ReplicatorRunInstanceRow] entities.
+func (r ReplicatorRunInstanceRow) TableName() string {
+	return "replicators_runs_instances"
+}
+
+// SelectColumns returns a slice of column names for [ReplicatorRunInstanceRow] entities.
+func (r ReplicatorRunInstanceRow) SelectColumns() []string {
+	return []string{
+		"replicators_runs_instances.id",
+		"replicators_runs_instances.replicator_run_id",
+		"replicators_runs_instances.instance_name",
+		"replicators_runs_instances.location",
+		"replicators_runs_instances.status",
+		"replicators_runs_instances.error",
+		"replicators_runs_instances.bytes_transferred",
+	}
+}
+
+// Joins returns a slice of join expressions for [ReplicatorRunInstanceRow].
+func (r ReplicatorRunInstanceRow) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [ReplicatorRunInstanceRow].
+// This returns references to struct fields in definition order.
+func (r *ReplicatorRunInstanceRow) ScanArgs() []any {
+	return []any{&r.ID, &r.ReplicatorRunID, &r.InstanceName, &r.Location, &r.Status, &r.Error, &r.BytesTransferred}
+}
+
+// CreateValues returns a list of values from [ReplicatorRunInstanceRow] entities matching the bind arguments in [CreateStmt].
+func (r ReplicatorRunInstanceRow) CreateValues() []any {
+	return []any{r.ReplicatorRunID, r.InstanceName, r.Location, r.Status, r.Error, r.BytesTransferred}
+}
+
+// UpdateValues returns a list of values from [ReplicatorRunInstanceRow] entities matching the columns in [UpdateStmt].
+func (r ReplicatorRunInstanceRow) UpdateValues() []any {
+	return []any{r.ReplicatorRunID, r.InstanceName, r.Location, r.Status, r.Error, r.BytesTransferred}
+}
+
+// PKColumns returns the column names for the primary key of a [ReplicatorRunInstanceRow] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (r ReplicatorRunInstanceRow) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [ReplicatorRunInstanceRow] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (r ReplicatorRunInstanceRow) PKValues() []any {
+	return []any{r.ID}
+}
+
+// CreateStmt returns a query that creates a [ReplicatorRunInstanceRow] entity.
+func (r ReplicatorRunInstanceRow) CreateStmt() string {
+	return "INSERT INTO replicators_runs_instances (replicator_run_id, instance_name, location, status, error, bytes_transferred) VALUES (?, ?, ?, ?, ?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [ReplicatorRunInstanceRow] by primary key.
+func (r ReplicatorRunInstanceRow) UpdateStmt() string {
+	return "UPDATE replicators_runs_instances SET replicator_run_id = ?, instance_name = ?, location = ?, status = ?, error = ?, bytes_transferred = ? "
+}
+
+// TableName returns the table name for [ReplicatorRunRow] entities.
+func (r ReplicatorRunRow) TableName() string {
+	return "replicators_runs"
+}
+
+// SelectColumns returns a slice of column names for [ReplicatorRunRow] entities.
+func (r ReplicatorRunRow) SelectColumns() []string {
+	return []string{
+		"replicators_runs.id",
+		"replicators_runs.replicator_id",
+		"replicators_runs.start_date",
+		"replicators_runs.end_date",
+		"replicators_runs.status",
+		"replicators_runs.bytes_transferred",
+	}
+}
+
+// Joins returns a slice of join expressions for [ReplicatorRunRow].
+func (r ReplicatorRunRow) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [ReplicatorRunRow].
+// This returns references to struct fields in definition order.
+func (r *ReplicatorRunRow) ScanArgs() []any {
+	return []any{&r.ID, &r.ReplicatorID, &r.StartDate, &r.EndDate, &r.Status, &r.BytesTransferred}
+}
+
+// CreateValues returns a list of values from [ReplicatorRunRow] entities matching the bind arguments in [CreateStmt].
+func (r ReplicatorRunRow) CreateValues() []any {
+	return []any{r.ReplicatorID, r.StartDate, r.EndDate, r.Status, r.BytesTransferred}
+}
+
+// UpdateValues returns a list of values from [ReplicatorRunRow] entities matching the columns in [UpdateStmt].
+func (r ReplicatorRunRow) UpdateValues() []any {
+	return []any{r.ReplicatorID, r.StartDate, r.EndDate, r.Status, r.BytesTransferred}
+}
+
+// PKColumns returns the column names for the primary key of a [ReplicatorRunRow] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (r ReplicatorRunRow) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [ReplicatorRunRow] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (r ReplicatorRunRow) PKValues() []any {
+	return []any{r.ID}
+}
+
+// CreateStmt returns a query that creates a [ReplicatorRunRow] entity.
+func (r ReplicatorRunRow) CreateStmt() string {
+	return "INSERT INTO replicators_runs (replicator_id, start_date, end_date, status, bytes_transferred) VALUES (?, ?, ?, ?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [ReplicatorRunRow] by primary key.
+func (r ReplicatorRunRow) UpdateStmt() string {
+	return "UPDATE replicators_runs SET replicator_id = ?, start_date = ?, end_date = ?, status = ?, bytes_transferred = ? "
+}
@@ -114,6 +114,12 @@ func (img *Image) ToAPI(ctx context.Context, tx *sql.Tx, profileProject string)
 		image.UpdateSource.ImageType = image.Type
 	}
 
+	// Add signature info.
+	image.Signature, err = GetImageSignature(ctx, tx, img.ID)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return nil, err
+	}
+
 	// Get effective project profiles.
 	if profileProject != "" {
 		enabled, err := ProjectHasProfiles(context.Background(), tx, profileProject)
@@ -167,6 +173,7 @@ var ImageSourceProtocol = map[int]string{
 	0: "lxd",
 	1: "direct", // Deprecated: kept for backward compatibility with existing data.
 	2: "simplestreams",
+	3: "oci",
 }
 
 // GetImageSource returns the image source with the given ID.
@@ -217,3 +224,31 @@ func GetImageSource(ctx context.Context, tx *sql.Tx, imageID int) (int, *api.Ima
 
 	return source.ID, result, nil
 }
+
+// GetImageSignature returns the signature of the image with the given ID.
+func GetImageSignature(ctx context.Context, tx *sql.Tx, imageID int) (*api.ImageSignature, error) {
+	q := `SELECT signature, type, key_fingerprint, status FROM images_signatures WHERE image_id=?`
+
+	signatures := []api.ImageSignature{}
+	err := query.Scan(ctx, tx, q, func(scan func(dest ...any) error) error {
+		signature := api.ImageSignature{}
+
+		err := scan(&signature.Signature, &signature.Type, &signature.KeyFingerprint, &signature.Status)
+		if err != nil {
+			return err
+		}
+
+		signatures = append(signatures, signature)
+
+		return nil
+	}, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(signatures) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Image signature not found")
+	}
+
+	return &signatures[0], nil
+}
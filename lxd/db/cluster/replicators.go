@@ -37,6 +37,33 @@ type Replicator struct {
 	ProjectName string `db:"projects.name"`
 }
 
+// ReplicatorInstanceRow represents a single row of the replicators_instances table.
+// Each row is a checkpoint recording the last successful replication of an instance by a replicator.
+// db:model replicators_instances
+type ReplicatorInstanceRow struct {
+	ID           int64     `db:"id"`
+	ReplicatorID int64     `db:"replicator_id"`
+	InstanceID   int64     `db:"instance_id"`
+	SnapshotName string    `db:"snapshot_name"`
+	RunDate      time.Time `db:"run_date"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (ReplicatorInstanceRow) APIName() string {
+	return "Replicator instance"
+}
+
+// ReplicatorInstance contains [ReplicatorInstanceRow] with additional joins.
+// db:model replicators_instances
+type ReplicatorInstance struct {
+	Row ReplicatorInstanceRow
+
+	// db:join JOIN instances ON replicators_instances.instance_id = instances.id
+	InstanceName string `db:"instances.name"`
+	// db:join JOIN projects ON instances.project_id = projects.id
+	ProjectName string `db:"projects.name"`
+}
+
 // ReplicatorsConfigStore returns a [query.EntityConfigStore] for replicators.
 func ReplicatorsConfigStore() *query.EntityConfigStore {
 	return &query.EntityConfigStore{
@@ -152,3 +179,165 @@ func UpdateReplicatorLastRunStatus(ctx context.Context, tx *sql.Tx, id int64, st
 	_, err := tx.ExecContext(ctx, `UPDATE replicators SET last_run_status=? WHERE id=?`, status, id)
 	return err
 }
+
+// GetReplicatorInstances returns the instance checkpoints of the replicator with the given ID.
+// If replicatorID is nil, the checkpoints of all replicators are returned.
+func GetReplicatorInstances(ctx context.Context, tx *sql.Tx, replicatorID *int64) ([]ReplicatorInstance, error) {
+	var args []any
+	clause := "ORDER BY replicators_instances.id"
+	if replicatorID != nil {
+		clause = "WHERE replicators_instances.replicator_id = ? " + clause
+		args = append(args, *replicatorID)
+	}
+
+	checkpoints, err := query.Select[ReplicatorInstance](ctx, tx, clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading replicator instances: %w", err)
+	}
+
+	return checkpoints, nil
+}
+
+// SetReplicatorInstance creates or replaces the checkpoint of an instance for a replicator.
+func SetReplicatorInstance(ctx context.Context, tx *sql.Tx, object ReplicatorInstanceRow) error {
+	_, err := query.CreateOrReplace(ctx, tx, object)
+	return err
+}
+
+// ReplicatorRunRow represents a single row of the replicators_runs table.
+// Each row records a finished run of a replicator.
+// db:model replicators_runs
+type ReplicatorRunRow struct {
+	ID               int64     `db:"id"`
+	ReplicatorID     int64     `db:"replicator_id"`
+	StartDate        time.Time `db:"start_date"`
+	EndDate          time.Time `db:"end_date"`
+	Status           string    `db:"status"`
+	BytesTransferred int64     `db:"bytes_transferred"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (ReplicatorRunRow) APIName() string {
+	return "Replicator run"
+}
+
+// ReplicatorRunInstanceRow represents a single row of the replicators_runs_instances table.
+// Each row records the outcome of the replication of an instance during a replicator run.
+// The instance is referenced by name so that the history is kept after the instance is deleted.
+// Location is the cluster member of custom volumes on local storage pools, whose names are only unique per member.
+// db:model replicators_runs_instances
+type ReplicatorRunInstanceRow struct {
+	ID               int64  `db:"id"`
+	ReplicatorRunID  int64  `db:"replicator_run_id"`
+	InstanceName     string `db:"instance_name"`
+	Location         string `db:"location"`
+	Status           string `db:"status"`
+	Error            string `db:"error"`
+	BytesTransferred int64  `db:"bytes_transferred"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (ReplicatorRunInstanceRow) APIName() string {
+	return "Replicator run instance"
+}
+
+// ReplicatorRun contains a [ReplicatorRunRow] with the outcome of each instance replicated by the run.
+type ReplicatorRun struct {
+	Row       ReplicatorRunRow
+	Instances []ReplicatorRunInstanceRow
+}
+
+// ToAPI converts the [ReplicatorRun] to an [api.ReplicatorRun].
+func (r *ReplicatorRun) ToAPI() *api.ReplicatorRun {
+	run := &api.ReplicatorRun{
+		ID:               r.Row.ID,
+		StartedAt:        r.Row.StartDate,
+		FinishedAt:       r.Row.EndDate,
+		Status:           r.Row.Status,
+		BytesTransferred: r.Row.BytesTransferred,
+		Instances:        make([]api.ReplicatorRunInstance, 0, len(r.Instances)),
+	}
+
+	for _, inst := range r.Instances {
+		run.Instances = append(run.Instances, api.ReplicatorRunInstance{
+			Name:             inst.InstanceName,
+			Location:         inst.Location,
+			Status:           inst.Status,
+			Error:            inst.Error,
+			BytesTransferred: inst.BytesTransferred,
+		})
+	}
+
+	return run
+}
+
+// CreateReplicatorRun records a finished replicator run along with the outcome of each of its instances.
+func CreateReplicatorRun(ctx context.Context, tx *sql.Tx, run ReplicatorRunRow, instances []ReplicatorRunInstanceRow) (int64, error) {
+	runID, err := query.Create(ctx, tx, run)
+	if err != nil {
+		return -1, fmt.Errorf("Failed creating replicator run: %w", err)
+	}
+
+	for i := range instances {
+		instances[i].ReplicatorRunID = runID
+	}
+
+	err = query.CreateMany(ctx, tx, instances)
+	if err != nil {
+		return -1, fmt.Errorf("Failed creating replicator run instances: %w", err)
+	}
+
+	return runID, nil
+}
+
+// GetReplicatorRuns returns the recorded runs of the replicator with the given ID, most recent first.
+// If runID is not nil, only the run with that ID is returned.
+func GetReplicatorRuns(ctx context.Context, tx *sql.Tx, replicatorID int64, runID *int64) ([]ReplicatorRun, error) {
+	clause := "WHERE replicators_runs.replicator_id = ?"
+	args := []any{replicatorID}
+	if runID != nil {
+		clause += " AND replicators_runs.id = ?"
+		args = append(args, *runID)
+	}
+
+	rows, err := query.Select[ReplicatorRunRow](ctx, tx, clause+" ORDER BY replicators_runs.id DESC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading replicator runs: %w", err)
+	}
+
+	runs := make([]ReplicatorRun, 0, len(rows))
+	runIndexByID := make(map[int64]int, len(rows))
+	for i, row := range rows {
+		runs = append(runs, ReplicatorRun{Row: row})
+		runIndexByID[row.ID] = i
+	}
+
+	if len(runs) == 0 {
+		return runs, nil
+	}
+
+	instanceClause := "WHERE replicators_runs_instances.replicator_run_id IN (SELECT id FROM replicators_runs " + clause + ") ORDER BY replicators_runs_instances.id"
+	err = query.SelectFunc[ReplicatorRunInstanceRow](ctx, tx, instanceClause, func(inst ReplicatorRunInstanceRow) error {
+		i, ok := runIndexByID[inst.ReplicatorRunID]
+		if ok {
+			runs[i].Instances = append(runs[i].Instances, inst)
+		}
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading replicator run instances: %w", err)
+	}
+
+	return runs, nil
+}
+
+// PruneReplicatorRuns deletes all but the given number of most recent runs of the replicator with the given ID.
+func PruneReplicatorRuns(ctx context.Context, tx *sql.Tx, replicatorID int64, retain int) error {
+	_, err := query.DeleteMany[ReplicatorRunRow](ctx, tx, "WHERE replicator_id = ? AND id NOT IN (SELECT id FROM replicators_runs WHERE replicator_id = ? ORDER BY id DESC LIMIT ?)", replicatorID, replicatorID, retain)
+	if err != nil {
+		return fmt.Errorf("Failed pruning replicator runs: %w", err)
+	}
+
+	return nil
+}
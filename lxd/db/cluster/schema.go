@@ -29,6 +29,20 @@ CREATE TABLE auth_groups_permissions (
     FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE,
     UNIQUE (auth_group_id, entity_type, entitlement, entity_id)
 );
+CREATE TABLE backup_targets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL,
+	UNIQUE (name)
+);
+CREATE TABLE backup_targets_config (
+	backup_target_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	FOREIGN KEY (backup_target_id) REFERENCES backup_targets (id) ON DELETE CASCADE,
+	PRIMARY KEY (backup_target_id,
+    key)
+) WITHOUT ROWID;
 CREATE TABLE certificates (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -174,6 +188,16 @@ CREATE TABLE "images_properties" (
     value TEXT,
     FOREIGN KEY (image_id) REFERENCES "images" (id) ON DELETE CASCADE
 );
+CREATE TABLE images_signatures (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	image_id INTEGER NOT NULL,
+	signature TEXT NOT NULL,
+	type TEXT NOT NULL,
+	key_fingerprint TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	UNIQUE (image_id),
+	FOREIGN KEY (image_id) REFERENCES images (id) ON DELETE CASCADE
+);
 CREATE TABLE "images_source" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     image_id INTEGER NOT NULL,
@@ -316,6 +340,23 @@ CREATE TABLE "networks_acls_config" (
     UNIQUE (network_acl_id, key),
     FOREIGN KEY (network_acl_id) REFERENCES "networks_acls" (id) ON DELETE CASCADE
 );
+CREATE TABLE networks_address_sets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	project_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL,
+	addresses TEXT NOT NULL,
+	UNIQUE (project_id, name),
+	FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+CREATE TABLE networks_address_sets_config (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	network_address_set_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	UNIQUE (network_address_set_id, key),
+	FOREIGN KEY (network_address_set_id) REFERENCES networks_address_sets (id) ON DELETE CASCADE
+);
 CREATE TABLE "networks_config" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     network_id INTEGER NOT NULL,
@@ -630,6 +671,36 @@ CREATE TABLE replicators_config (
 	PRIMARY KEY (replicator_id,
     key)
 ) WITHOUT ROWID;
+CREATE TABLE replicators_instances (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_id INTEGER NOT NULL,
+	instance_id INTEGER NOT NULL,
+	snapshot_name TEXT NOT NULL,
+	run_date DATETIME NOT NULL,
+	UNIQUE (replicator_id, instance_id),
+	FOREIGN KEY (replicator_id) REFERENCES replicators (id) ON DELETE CASCADE,
+	FOREIGN KEY (instance_id) REFERENCES instances (id) ON DELETE CASCADE
+);
+CREATE TABLE replicators_runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_id INTEGER NOT NULL,
+	start_date DATETIME NOT NULL,
+	end_date DATETIME NOT NULL,
+	status TEXT NOT NULL,
+	bytes_transferred INTEGER NOT NULL DEFAULT 0,
+	FOREIGN KEY (replicator_id) REFERENCES replicators (id) ON DELETE CASCADE
+);
+CREATE TABLE replicators_runs_instances (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_run_id INTEGER NOT NULL,
+	instance_name TEXT NOT NULL,
+	location TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	error TEXT NOT NULL DEFAULT '',
+	bytes_transferred INTEGER NOT NULL DEFAULT 0,
+	UNIQUE (replicator_run_id, instance_name, location),
+	FOREIGN KEY (replicator_run_id) REFERENCES replicators_runs (id) ON DELETE CASCADE
+);
 CREATE TABLE secrets (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     entity_type INTEGER NOT NULL,
@@ -676,6 +747,7 @@ CREATE TABLE "storage_buckets_keys" (
 	UNIQUE (storage_bucket_id, name),
 	FOREIGN KEY (storage_bucket_id) REFERENCES "storage_buckets" (id) ON DELETE CASCADE
 );
+CREATE INDEX storage_buckets_keys_access_key_idx ON storage_buckets_keys (access_key);
 CREATE UNIQUE INDEX storage_buckets_unique_storage_pool_id_node_id_name ON "storage_buckets" (storage_pool_id, IFNULL(node_id, -1), name);
 CREATE TABLE "storage_pools" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
@@ -826,5 +898,5 @@ CREATE TABLE "warnings" (
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (88, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (94, strftime("%s"))
 `
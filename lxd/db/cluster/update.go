@@ -132,6 +132,133 @@ var updates = map[int]schema.Update{
 	86: updateFromV85,
 	87: updateFromV86,
 	88: updateFromV87,
+	89: updateFromV88,
+	90: updateFromV89,
+	91: updateFromV90,
+	92: updateFromV91,
+	93: updateFromV92,
+	94: updateFromV93,
+}
+
+func updateFromV93(ctx context.Context, tx *sql.Tx) error {
+	// Add networks_address_sets and networks_address_sets_config to record the named address sets that network
+	// ACL rules can reference.
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE networks_address_sets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	project_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL,
+	addresses TEXT NOT NULL,
+	UNIQUE (project_id, name),
+	FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+
+CREATE TABLE networks_address_sets_config (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	network_address_set_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	UNIQUE (network_address_set_id, key),
+	FOREIGN KEY (network_address_set_id) REFERENCES networks_address_sets (id) ON DELETE CASCADE
+);
+`)
+
+	return err
+}
+
+func updateFromV92(ctx context.Context, tx *sql.Tx) error {
+	// Add backup_targets and backup_targets_config to record the remote locations backups can be uploaded to.
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE backup_targets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL,
+	UNIQUE (name)
+);
+
+CREATE TABLE backup_targets_config (
+	backup_target_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	FOREIGN KEY (backup_target_id) REFERENCES backup_targets (id) ON DELETE CASCADE,
+	PRIMARY KEY (backup_target_id, key)
+) WITHOUT ROWID;
+`)
+
+	return err
+}
+
+func updateFromV91(ctx context.Context, tx *sql.Tx) error {
+	// Add an index on the access key of storage bucket keys, used by the S3 gateway of local storage pools to
+	// authenticate requests.
+	_, err := tx.ExecContext(ctx, `CREATE INDEX storage_buckets_keys_access_key_idx ON storage_buckets_keys (access_key);`)
+
+	return err
+}
+
+func updateFromV90(ctx context.Context, tx *sql.Tx) error {
+	// Add images_signatures to record the detached signature of images and its verification status.
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE images_signatures (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	image_id INTEGER NOT NULL,
+	signature TEXT NOT NULL,
+	type TEXT NOT NULL,
+	key_fingerprint TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	UNIQUE (image_id),
+	FOREIGN KEY (image_id) REFERENCES images (id) ON DELETE CASCADE
+);
+`)
+
+	return err
+}
+
+func updateFromV89(ctx context.Context, tx *sql.Tx) error {
+	// Add replicators_runs and replicators_runs_instances to record the history of replicator runs.
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE replicators_runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_id INTEGER NOT NULL,
+	start_date DATETIME NOT NULL,
+	end_date DATETIME NOT NULL,
+	status TEXT NOT NULL,
+	bytes_transferred INTEGER NOT NULL DEFAULT 0,
+	FOREIGN KEY (replicator_id) REFERENCES replicators (id) ON DELETE CASCADE
+);
+CREATE TABLE replicators_runs_instances (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_run_id INTEGER NOT NULL,
+	instance_name TEXT NOT NULL,
+	location TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	error TEXT NOT NULL DEFAULT '',
+	bytes_transferred INTEGER NOT NULL DEFAULT 0,
+	UNIQUE (replicator_run_id, instance_name, location),
+	FOREIGN KEY (replicator_run_id) REFERENCES replicators_runs (id) ON DELETE CASCADE
+);
+`)
+
+	return err
+}
+
+func updateFromV88(ctx context.Context, tx *sql.Tx) error {
+	// Add replicators_instances to checkpoint the last successful replication of each instance by a replicator.
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE replicators_instances (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_id INTEGER NOT NULL,
+	instance_id INTEGER NOT NULL,
+	snapshot_name TEXT NOT NULL,
+	run_date DATETIME NOT NULL,
+	UNIQUE (replicator_id, instance_id),
+	FOREIGN KEY (replicator_id) REFERENCES replicators (id) ON DELETE CASCADE,
+	FOREIGN KEY (instance_id) REFERENCES instances (id) ON DELETE CASCADE
+);
+`)
+
+	return err
 }
 
 func updateFromV87(ctx context.Context, tx *sql.Tx) error {
@@ -80,6 +80,25 @@ func (c *ClusterTx) CreateImageSource(ctx context.Context, id int, server string
 	return err
 }
 
+// CreateImageSignature records the signature of the image with the given ID.
+func (c *ClusterTx) CreateImageSignature(ctx context.Context, id int, signature api.ImageSignature) error {
+	_, err := query.UpsertObject(c.tx, "images_signatures", []string{
+		"image_id",
+		"signature",
+		"type",
+		"key_fingerprint",
+		"status",
+	}, []any{
+		id,
+		signature.Signature,
+		signature.Type,
+		signature.KeyFingerprint,
+		signature.Status,
+	})
+
+	return err
+}
+
 // GetCachedImageSourceFingerprint tries to find a source entry of a locally
 // cached image that matches the given remote details (server, protocol and
 // alias). Return the fingerprint linked to the matching entry, if any.
@@ -361,7 +380,7 @@ WHERE images.fingerprint = ?
 	}
 
 	if len(addresses) == 0 {
-		return "", errors.New("Image not available on any online member")
+		return "", api.StatusErrorf(http.StatusServiceUnavailable, "Image not available on any online member")
 	}
 
 	if slices.Contains(addresses, localAddress) {
@@ -1092,6 +1092,72 @@ WHERE instances_config.id IN (
 	return nil
 }
 
+// InstanceConfigValue is the value of a config key of an instance, after expansion with its profiles.
+type InstanceConfigValue struct {
+	ID      int
+	Project string
+	Name    string
+	Value   string
+}
+
+// GetLocalInstancesExpandedConfigValue returns the value of the config key for the instances of the local member
+// that set it, either in their own config or through one of their profiles, without loading the instances.
+func (c *ClusterTx) GetLocalInstancesExpandedConfigValue(ctx context.Context, key string) ([]InstanceConfigValue, error) {
+	nodeID := c.GetNodeID()
+
+	// The instance config takes precedence over the profiles, which are applied in order.
+	q := `
+SELECT instances.id, projects.name, instances.name, instances_config.value, -1
+  FROM instances
+  JOIN projects ON projects.id = instances.project_id
+  JOIN instances_config ON instances_config.instance_id = instances.id
+  WHERE instances.node_id = ? AND instances_config.key = ?
+UNION ALL
+SELECT instances.id, projects.name, instances.name, profiles_config.value, instances_profiles.apply_order
+  FROM instances
+  JOIN projects ON projects.id = instances.project_id
+  JOIN instances_profiles ON instances_profiles.instance_id = instances.id
+  JOIN profiles_config ON profiles_config.profile_id = instances_profiles.profile_id
+  WHERE instances.node_id = ? AND profiles_config.key = ?
+`
+
+	values := map[int]InstanceConfigValue{}
+	orders := map[int]int{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var value InstanceConfigValue
+		var order int
+
+		err := scan(&value.ID, &value.Project, &value.Name, &value.Value, &order)
+		if err != nil {
+			return err
+		}
+
+		current, ok := orders[value.ID]
+		if ok && (current == -1 || (order != -1 && order < current)) {
+			return nil
+		}
+
+		orders[value.ID] = order
+		values[value.ID] = value
+
+		return nil
+	}, nodeID, key, nodeID, key)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading %q config of local instances: %w", key, err)
+	}
+
+	result := make([]InstanceConfigValue, 0, len(values))
+	for _, value := range values {
+		if value.Value != "" {
+			result = append(result, value)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return result, nil
+}
+
 // CreateInstanceConfig inserts a new config for the instance with the given ID.
 func CreateInstanceConfig(ctx context.Context, tx *sql.Tx, id int, config map[string]string) error {
 	sql := "INSERT INTO instances_config (instance_id, key, value) values (?, ?, ?)"
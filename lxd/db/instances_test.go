@@ -546,6 +546,54 @@ func TestGetLocalInstancesInProject(t *testing.T) {
 	assert.Equal(t, map[string]map[string]string{"root": {"type": "disk", "x": "y"}}, cluster.DevicesToAPI(c3Devices))
 }
 
+// The expanded value of a config key is loaded for the local instances that set it.
+func TestGetLocalInstancesExpandedConfigValue(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	nodeID1 := int64(1) // This is the default local member
+
+	nodeID2, err := tx.CreateNode("node2", "1.2.3.4:666")
+	require.NoError(t, err)
+
+	addContainer(t, tx, nodeID1, "c1")
+	addContainer(t, tx, nodeID1, "c2")
+	addContainer(t, tx, nodeID1, "c3")
+	addContainer(t, tx, nodeID2, "c4")
+
+	_, err = tx.Tx().Exec(`
+INSERT INTO profiles (name, project_id, description) VALUES ('p1', 1, ''), ('p2', 1, '');
+INSERT INTO profiles_config (profile_id, key, value)
+  SELECT id, 'backups.schedule', '@hourly' FROM profiles WHERE name = 'p1';
+INSERT INTO profiles_config (profile_id, key, value)
+  SELECT id, 'backups.schedule', '@weekly' FROM profiles WHERE name = 'p2';
+`)
+	require.NoError(t, err)
+
+	addContainerProfile := func(container string, profile string, order int) {
+		_, err := tx.Tx().Exec("INSERT INTO instances_profiles (instance_id, profile_id, apply_order) SELECT ?, id, ? FROM profiles WHERE name = ?", getContainerID(t, tx, container), order, profile)
+		require.NoError(t, err)
+	}
+
+	// The instance config overrides the profiles.
+	addContainerConfig(t, tx, "c1", "backups.schedule", "@daily")
+	addContainerProfile("c1", "p1", 0)
+
+	// The last profile applied wins.
+	addContainerProfile("c2", "p2", 1)
+	addContainerProfile("c2", "p1", 0)
+
+	// Instances of other members are ignored.
+	addContainerConfig(t, tx, "c4", "backups.schedule", "@daily")
+
+	values, err := tx.GetLocalInstancesExpandedConfigValue(context.Background(), "backups.schedule")
+	require.NoError(t, err)
+	assert.Equal(t, []db.InstanceConfigValue{
+		{ID: 1, Project: api.ProjectDefaultName, Name: "c1", Value: "@daily"},
+		{ID: 2, Project: api.ProjectDefaultName, Name: "c2", Value: "@weekly"},
+	}, values)
+}
+
 func addContainer(t *testing.T, tx *db.ClusterTx, nodeID int64, name string) {
 	stmt := `
 INSERT INTO instances(node_id, name, architecture, type, project_id, description) VALUES (?, ?, 1, ?, 1, '')
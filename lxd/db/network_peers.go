@@ -349,6 +349,29 @@ func (c *ClusterTx) GetNetworkPeerNames(ctx context.Context, networkID int64) (m
 	return peers, nil
 }
 
+// GetNetworkPeerSourceNetworkIDs returns the IDs of the networks that have a peer linked to the given network ID.
+// This includes the peers that are mutually created with a peer of the given network, as well as the peers left
+// in errored state after their mutual peer was removed from the given network.
+func (c *ClusterTx) GetNetworkPeerSourceNetworkIDs(ctx context.Context, networkID int64) ([]int64, error) {
+	q := `
+	SELECT DISTINCT network_id
+	FROM networks_peers
+	WHERE networks_peers.target_network_id = ?
+	`
+
+	ids, err := query.SelectIntegers(ctx, c.tx, q, networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	networkIDs := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		networkIDs = append(networkIDs, int64(id))
+	}
+
+	return networkIDs, nil
+}
+
 // UpdateNetworkPeer updates an existing Network Peer.
 func (c *ClusterTx) UpdateNetworkPeer(ctx context.Context, networkID int64, peerID int64, info api.NetworkPeerPut) error {
 	// Update existing Network peer record.
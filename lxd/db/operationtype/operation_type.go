@@ -147,6 +147,19 @@ const (
 	ProjectReplicaModeUpdate
 	ReplicatorRunInstanceRestore
 	ReplicatorFinalize
+	ReplicatorPromote
+	ReplicatorRunVolumeForward
+	ReplicatorRunProjectSync
+	ReplicatorRunReconcile
+	PlacementGroupRebalance
+	BackupsCreateScheduled
+	StoragePoolMigrate
+	StoragePoolScrub
+	VolumeVerify
+	NetworkAddressSetCreate
+	NetworkAddressSetUpdate
+	NetworkAddressSetDelete
+	NetworkAddressSetRename
 
 	// upperBound is used only to enforce consistency in the package on init.
 	// Make sure it's always the last item in this list.
@@ -247,6 +260,8 @@ func (t Type) Description() string {
 		return "Moving storage volume"
 	case VolumeSnapshotCopy:
 		return "Copying storage volume snapshot"
+	case VolumeVerify:
+		return "Verifying storage volume"
 	case VolumeSnapshotCreate:
 		return "Creating storage volume snapshot"
 	case VolumeSnapshotDelete:
@@ -318,6 +333,8 @@ func (t Type) Description() string {
 		return "Just chilling"
 	case SnapshotsCreateScheduled:
 		return "Creating scheduled instance snapshots"
+	case BackupsCreateScheduled:
+		return "Creating scheduled backups"
 	case SynchronizeOperations:
 		return "Synchronizing operations"
 	case StoragePoolCreate:
@@ -326,6 +343,10 @@ func (t Type) Description() string {
 		return "Updating storage pool"
 	case StoragePoolDelete:
 		return "Deleting storage pool"
+	case StoragePoolMigrate:
+		return "Migrating storage pool"
+	case StoragePoolScrub:
+		return "Scrubbing storage pool"
 	case NetworkCreate:
 		return "Creating network"
 	case NetworkUpdate:
@@ -342,6 +363,14 @@ func (t Type) Description() string {
 		return "Deleting network ACL"
 	case NetworkACLRename:
 		return "Renaming network ACL"
+	case NetworkAddressSetCreate:
+		return "Creating network address set"
+	case NetworkAddressSetUpdate:
+		return "Updating network address set"
+	case NetworkAddressSetDelete:
+		return "Deleting network address set"
+	case NetworkAddressSetRename:
+		return "Renaming network address set"
 	case StorageBucketCreate:
 		return "Creating storage bucket"
 	case StorageBucketUpdate:
@@ -402,6 +431,16 @@ func (t Type) Description() string {
 		return "Updating project replica mode"
 	case ReplicatorFinalize:
 		return "Finalizing replicator"
+	case ReplicatorPromote:
+		return "Promoting replicator project"
+	case ReplicatorRunVolumeForward:
+		return "Replicating custom volume"
+	case ReplicatorRunProjectSync:
+		return "Replicating project configuration"
+	case ReplicatorRunReconcile:
+		return "Removing replicated items deleted from project"
+	case PlacementGroupRebalance:
+		return "Rebalancing placement group"
 
 	// It should never be possible to reach the default clause.
 	// See the init function.
@@ -418,7 +457,7 @@ func (t Type) EntityType() entity.Type {
 		ImagesSynchronize, RemoveExpiredOIDCSessions, RemoveExpiredTokens, RemoveOrphanedOperations,
 		WarningsPruneResolved, ClusterMemberEvacuate, ClusterMemberRestore, LogsExpire, InstanceTypesUpdate,
 		BackupsExpire, SnapshotsExpire, ClusterJoinToken, CertificateAddToken, RenewServerCertificate,
-		ClusterHeal, ImagesUpdate, VolumeSnapshotsCreateScheduled, SnapshotsCreateScheduled,
+		ClusterHeal, ImagesUpdate, VolumeSnapshotsCreateScheduled, SnapshotsCreateScheduled, BackupsCreateScheduled,
 		SynchronizeOperations, RefreshClusterLinkVolatileAddresses,
 		StoragePoolCreate, Wait:
 		return entity.TypeServer
@@ -428,7 +467,7 @@ func (t Type) EntityType() entity.Type {
 	// (the entity being created is not yet referenceable).
 	case VolumeCreate, ProjectRename, InstanceCreate, ImageDownload, ImageUploadToken, CustomVolumeBackupRestore,
 		InstanceStateUpdateBulk, BackupRestore, ProjectDelete, NetworkCreate, NetworkACLCreate, StorageBucketCreate,
-		NetworkZoneCreate, ProjectReplicaModeUpdate, ReplicatorRunInstanceRestore:
+		NetworkZoneCreate, ProjectReplicaModeUpdate, ReplicatorRunInstanceRestore, ReplicatorRunProjectSync, ReplicatorRunReconcile:
 		return entity.TypeProject
 
 	// Storage bucket operations.
@@ -436,7 +475,8 @@ func (t Type) EntityType() entity.Type {
 		return entity.TypeStorageBucket
 
 	// Volume operations.
-	case VolumeMigrate, VolumeMove, VolumeSnapshotCreate, CustomVolumeBackupCreate, VolumeCopy, VolumeUpdate, VolumeDelete:
+	case VolumeMigrate, VolumeMove, VolumeSnapshotCreate, CustomVolumeBackupCreate, VolumeCopy, VolumeUpdate, VolumeDelete,
+		ReplicatorRunVolumeForward, VolumeVerify:
 		return entity.TypeStorageVolume
 
 	// Volume snapshot operations
@@ -467,7 +507,7 @@ func (t Type) EntityType() entity.Type {
 		return entity.TypeStorageVolumeBackup
 
 	// Storage pool operations.
-	case StoragePoolUpdate, StoragePoolDelete:
+	case StoragePoolUpdate, StoragePoolDelete, StoragePoolMigrate, StoragePoolScrub:
 		return entity.TypeStoragePool
 
 	// Profile operations.
@@ -482,6 +522,11 @@ func (t Type) EntityType() entity.Type {
 	case NetworkACLUpdate, NetworkACLDelete, NetworkACLRename:
 		return entity.TypeNetworkACL
 
+	// Network address set operations.
+	// Address sets are not a distinct entity type, they are managed with the network ACL permissions of their project.
+	case NetworkAddressSetCreate, NetworkAddressSetUpdate, NetworkAddressSetDelete, NetworkAddressSetRename:
+		return entity.TypeProject
+
 	// Network load balancer operations.
 	case NetworkLoadBalancerCreate, NetworkLoadBalancerUpdate, NetworkLoadBalancerDelete, NetworkLoadBalancerPoolCreate, NetworkLoadBalancerPoolUpdate, NetworkLoadBalancerPoolDelete:
 		return entity.TypeNetwork
@@ -498,9 +543,13 @@ func (t Type) EntityType() entity.Type {
 	case NetworkZoneUpdate, NetworkZoneDelete, NetworkZoneRecordCreate, NetworkZoneRecordUpdate, NetworkZoneRecordDelete:
 		return entity.TypeNetworkZone
 	// Replicator operations.
-	case ReplicatorRun, ReplicatorFinalize:
+	case ReplicatorRun, ReplicatorFinalize, ReplicatorPromote:
 		return entity.TypeReplicator
 
+	// Placement group operations.
+	case PlacementGroupRebalance:
+		return entity.TypePlacementGroup
+
 	// It should never be possible to reach the default clause.
 	// See the init function.
 	default:
@@ -537,6 +586,14 @@ func (t Type) ConflictAction() ConflictAction {
 		return ConflictActionFail // Enforces cluster-wide evacuation exclusivity when used with a shared ConflictReference; this prevents evacuation race conditions.
 	case ReplicatorRun:
 		return ConflictActionFail // Prevents concurrent runs of the same replicator; the replicator URL is used as the per-replicator conflict reference.
+	case ReplicatorPromote:
+		return ConflictActionFail // Prevents runs of a replicator during its promotion; the replicator URL is used as the conflict reference.
+	case PlacementGroupRebalance:
+		return ConflictActionFail // Prevents concurrent rebalancing of the same placement group; the placement group URL is used as the conflict reference.
+	case StoragePoolMigrate:
+		return ConflictActionFail // Prevents concurrent migrations of the same storage pool; the storage pool URL is used as the conflict reference.
+	case StoragePoolScrub:
+		return ConflictActionFail // Prevents concurrent scrubs of the same storage pool; the storage pool URL is used as the conflict reference.
 	}
 
 	return ConflictActionNone
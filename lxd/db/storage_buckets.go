@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/canonical/lxd/lxd/db/query"
@@ -284,12 +285,23 @@ func (c *ClusterTx) CreateStoragePoolBucket(ctx context.Context, poolID int64, p
 	var err error
 	var bucketID int64
 
+	driver, err := c.GetStoragePoolDriver(ctx, poolID)
+	if err != nil {
+		return -1, err
+	}
+
+	// Buckets on local storage pools belong to this member.
+	var nodeID any
+	if !slices.Contains(StorageRemoteDriverNames(), driver) {
+		nodeID = c.nodeID
+	}
+
 	// Insert a new Storage Bucket record.
 	result, err := c.tx.ExecContext(ctx, `
 		INSERT INTO storage_buckets
 		(storage_pool_id, node_id, name, description, project_id)
 		VALUES (?, ?, ?, ?, (SELECT id FROM projects WHERE name = ?))
-		`, poolID, nil, info.Name, info.Description, projectName)
+		`, poolID, nodeID, info.Name, info.Description, projectName)
 	if err != nil {
 		if query.IsConflictErr(err) {
 			return -1, api.StatusErrorf(http.StatusConflict, "A bucket for that name already exists")
@@ -399,7 +411,8 @@ func (c *ClusterTx) DeleteStoragePoolBucket(ctx context.Context, poolID int64, b
 
 // StorageBucketKeyFilter used for filtering storage bucket keys with GetStoragePoolBucketKeys().
 type StorageBucketKeyFilter struct {
-	Name *string
+	Name      *string
+	AccessKey *string
 }
 
 // StorageBucketKey represents a database storage bucket key record.
@@ -439,6 +452,11 @@ func (c *ClusterTx) GetStoragePoolBucketKeys(ctx context.Context, bucketID int64
 				args = append(args, *filter.Name)
 			}
 
+			if filter.AccessKey != nil {
+				qFilters = append(qFilters, "storage_buckets_keys.access_key = ?")
+				args = append(args, *filter.AccessKey)
+			}
+
 			if qFilters == nil {
 				return nil, errors.New("Invalid storage bucket key filter")
 			}
@@ -61,6 +61,14 @@ const (
 	// OIDCAuthenticationUnavailable warnings are created when OIDC is configured on LXD but LXD is unable to use those
 	// settings to initialize the OIDC verifier.
 	OIDCAuthenticationUnavailable
+	// StoragePoolCorrupted represents corrupted data found when scrubbing a storage pool.
+	StoragePoolCorrupted
+	// StorageVolumeCorrupted represents corrupted data found when verifying a storage volume.
+	StorageVolumeCorrupted
+	// StoragePoolSpaceThreshold represents the used space of a storage pool exceeding its configured threshold.
+	StoragePoolSpaceThreshold
+	// StoragePoolMetadataThreshold represents the used thin pool metadata of a storage pool exceeding its configured threshold.
+	StoragePoolMetadataThreshold
 )
 
 // TypeNames associates a warning code to its name.
@@ -92,6 +100,10 @@ var TypeNames = map[Type]string{
 	StoragePoolUnvailable:                  "Storage pool unavailable",
 	UnableToUpdateClusterCertificate:       "Cannot update cluster certificate",
 	OIDCAuthenticationUnavailable:          "Failed applying OIDC settings",
+	StoragePoolCorrupted:                   "Corrupted data found in storage pool",
+	StorageVolumeCorrupted:                 "Corrupted data found in storage volume",
+	StoragePoolSpaceThreshold:              "Storage pool space usage above threshold",
+	StoragePoolMetadataThreshold:           "Storage pool metadata usage above threshold",
 }
 
 // Severity returns the severity of the warning type.
@@ -151,6 +163,14 @@ func (t Type) Severity() Severity {
 		return SeverityLow
 	case OIDCAuthenticationUnavailable:
 		return SeverityModerate
+	case StoragePoolCorrupted:
+		return SeverityHigh
+	case StorageVolumeCorrupted:
+		return SeverityHigh
+	case StoragePoolSpaceThreshold:
+		return SeverityHigh
+	case StoragePoolMetadataThreshold:
+		return SeverityHigh
 	}
 
 	return SeverityLow
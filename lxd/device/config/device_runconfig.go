@@ -82,6 +82,7 @@ type DiskLimits struct {
 	ReadIOps   int64
 	WriteBytes int64
 	WriteIOps  int64
+	Group      string // Name of the throttle group shared by disks with the same limits.
 }
 
 // RunConfig represents run-time config used for device setup/cleanup.
@@ -17,6 +17,8 @@ import (
 
 	"github.com/canonical/lxd/lxd/idmap"
 	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/state"
 	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
 	"github.com/canonical/lxd/lxd/storage/filesystem"
 	"github.com/canonical/lxd/lxd/subprocess"
@@ -24,6 +26,40 @@ import (
 	"github.com/canonical/lxd/shared/revert"
 )
 
+// DiskApplyLimits applies the current I/O limits of the named disk devices to a running instance, such as after a
+// change of the limits set on the storage volume backing them.
+func DiskApplyLimits(s *state.State, inst instance.Instance, devNames []string) error {
+	devices := inst.ExpandedDevices()
+	for _, devName := range devNames {
+		conf, ok := devices[devName]
+		if !ok {
+			continue
+		}
+
+		dev, err := load(inst, s, inst.Project().Name, devName, conf.Clone(), nil, nil)
+		if err != nil {
+			return err
+		}
+
+		d, ok := dev.(*disk)
+		if !ok {
+			continue
+		}
+
+		err = d.applyLimits()
+		if err != nil {
+			return fmt.Errorf("Failed applying I/O limits of disk device %q: %w", devName, err)
+		}
+
+		// The limits of all the disks of a container are applied together.
+		if inst.Type() == instancetype.Container {
+			break
+		}
+	}
+
+	return nil
+}
+
 // DiskMount mounts a disk device.
 func DiskMount(srcPath string, dstPath string, recursive bool, propagation string, mountOptions []string, fsName string) error {
 	var err error
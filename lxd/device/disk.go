@@ -143,15 +143,20 @@ func (d *disk) sourceIsLocalPath(source string) bool {
 }
 
 func (d *disk) sourceVolumeFields() (volumeName string, volumeType storageDrivers.VolumeType, dbVolumeType cluster.StoragePoolVolumeType, err error) {
-	volumeName = d.config["source"]
+	return diskSourceVolumeFields(d.config)
+}
+
+// diskSourceVolumeFields returns the name and type of the storage volume used as the source of a disk device.
+func diskSourceVolumeFields(dev deviceConfig.Device) (volumeName string, volumeType storageDrivers.VolumeType, dbVolumeType cluster.StoragePoolVolumeType, err error) {
+	volumeName = dev["source"]
 
-	if d.config["source.snapshot"] != "" {
-		volumeName = volumeName + shared.SnapshotDelimiter + d.config["source.snapshot"]
+	if dev["source.snapshot"] != "" {
+		volumeName = volumeName + shared.SnapshotDelimiter + dev["source.snapshot"]
 	}
 
 	volumeTypeName := cluster.StoragePoolVolumeTypeNameCustom
-	if d.config["source.type"] != "" {
-		volumeTypeName = d.config["source.type"]
+	if dev["source.type"] != "" {
+		volumeTypeName = dev["source.type"]
 	}
 
 	dbVolumeType, err = cluster.StoragePoolVolumeTypeFromName(volumeTypeName)
@@ -556,6 +561,18 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 				if err != nil {
 					return err
 				}
+
+				// Volumes holding the buckets of local storage pools are reserved for the built-in S3 gateway.
+				if volumeType == storageDrivers.VolumeTypeCustom {
+					bucket, err := storagePools.VolumeUsedByLocalBucket(d.state, d.pool.Name(), storageProjectName, volumeName)
+					if err != nil {
+						return err
+					}
+
+					if bucket != nil {
+						return fmt.Errorf("Custom volume %q holds storage bucket %q and cannot be attached", volumeName, bucket.Name)
+					}
+				}
 			}
 		}
 
@@ -1047,22 +1064,13 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 	}
 
 	// Add I/O limits if set.
-	var diskLimits *deviceConfig.DiskLimits
-	if d.config["limits.read"] != "" || d.config["limits.write"] != "" || d.config["limits.max"] != "" {
-		// Parse the limits into usable values.
-		readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
-		if err != nil {
-			return nil, err
-		}
-
-		diskLimits = &deviceConfig.DiskLimits{
-			ReadBytes:  readBps,
-			ReadIOps:   readIops,
-			WriteBytes: writeBps,
-			WriteIOps:  writeIops,
-		}
+	limits, err := d.diskLimits(deviceConfig.Devices{d.name: d.config})
+	if err != nil {
+		return nil, err
 	}
 
+	diskLimits := limits[d.name]
+
 	if filters.IsRootDisk(d.config) {
 		// Handle previous requests for setting new quotas.
 		err := d.applyDeferredQuota()
@@ -1391,6 +1399,21 @@ func (d *disk) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 				}
 			}
 
+			// Check the pool allows allocating the extra space before growing the root disk.
+			if newRootDiskDeviceSize != oldRootDiskDeviceSize {
+				pool, err := storagePools.LoadByInstance(d.state, d.inst)
+				if err != nil {
+					return err
+				}
+
+				releaseOvercommit, err := pool.CheckInstanceQuota(d.inst, oldRootDiskDeviceSize)
+				if err != nil {
+					return err
+				}
+
+				defer releaseOvercommit()
+			}
+
 			err := d.applyQuota(false)
 			if err != nil {
 				if !errors.Is(err, storageDrivers.ErrInUse) {
@@ -1410,45 +1433,47 @@ func (d *disk) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 
 	// Only apply IO limits if instance is running.
 	if isRunning {
-		runConf := deviceConfig.RunConfig{}
-
-		switch d.inst.Type() {
-		case instancetype.Container:
-			err := d.generateLimits(&runConf)
-			if err != nil {
-				return err
-			}
+		err := d.applyLimits()
+		if err != nil {
+			return err
+		}
+	}
 
-		case instancetype.VM:
-			// Parse the limits into usable values.
-			readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
-			if err != nil {
-				return err
-			}
+	return nil
+}
 
-			// Apply the limits to a minimal mount entry.
-			diskLimits := &deviceConfig.DiskLimits{
-				ReadBytes:  readBps,
-				ReadIOps:   readIops,
-				WriteBytes: writeBps,
-				WriteIOps:  writeIops,
-			}
+// applyLimits applies the current I/O limits of the disk to the running instance, clearing them if none are set.
+func (d *disk) applyLimits() error {
+	runConf := deviceConfig.RunConfig{}
 
-			runConf.Mounts = []deviceConfig.MountEntryItem{
-				{
-					DevName: d.name,
-					Limits:  diskLimits,
-				},
-			}
+	switch d.inst.Type() {
+	case instancetype.Container:
+		err := d.generateLimits(&runConf)
+		if err != nil {
+			return err
 		}
 
-		err := d.inst.DeviceEventHandler(&runConf)
+	case instancetype.VM:
+		limits, err := d.diskLimits(deviceConfig.Devices{d.name: d.config})
 		if err != nil {
 			return err
 		}
+
+		// Apply the limits to a minimal mount entry, clearing them if none are set.
+		diskLimits := limits[d.name]
+		if diskLimits == nil {
+			diskLimits = &deviceConfig.DiskLimits{}
+		}
+
+		runConf.Mounts = []deviceConfig.MountEntryItem{
+			{
+				DevName: d.name,
+				Limits:  diskLimits,
+			},
+		}
 	}
 
-	return nil
+	return d.inst.DeviceEventHandler(&runConf)
 }
 
 // applyDeferredQuota attempts to apply the deferred quota specified in the volatile "apply_quota" key if set.
@@ -1518,15 +1543,13 @@ func (d *disk) applyQuota(remount bool) error {
 // generateLimits adds a set of cgroup rules to apply specified limits to the supplied RunConfig.
 func (d *disk) generateLimits(runConf *deviceConfig.RunConfig) error {
 	// Disk throttle limits.
-	hasDiskLimits := false
-	for _, dev := range d.inst.ExpandedDevices().Filter(filters.IsDisk) {
-		if dev["limits.read"] != "" || dev["limits.write"] != "" || dev["limits.max"] != "" {
-			hasDiskLimits = true
-			break
-		}
+	devices := d.inst.ExpandedDevices().Filter(filters.IsDisk)
+	limits, err := d.diskLimits(devices)
+	if err != nil {
+		return err
 	}
 
-	if !hasDiskLimits {
+	if len(limits) == 0 {
 		return nil
 	}
 
@@ -1534,7 +1557,7 @@ func (d *disk) generateLimits(runConf *deviceConfig.RunConfig) error {
 		return errors.New("Cannot apply disk limits as blkio cgroup controller is missing")
 	}
 
-	diskLimits, err := d.getDiskLimits()
+	diskLimits, err := d.getDiskLimits(devices, limits)
 	if err != nil {
 		return err
 	}
@@ -2198,8 +2221,8 @@ func (d *disk) postStop() error {
 	return nil
 }
 
-// getDiskLimits calculates Block I/O limits.
-func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
+// getDiskLimits calculates the Block I/O limits of the supplied disk devices from their I/O limits.
+func (d *disk) getDiskLimits(devices deviceConfig.Devices, limits map[string]*deviceConfig.DiskLimits) (map[string]diskBlockLimit, error) {
 	result := map[string]diskBlockLimit{}
 
 	// Build a list of all valid block devices
@@ -2230,13 +2253,14 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 
 	// Process all the limits
 	blockLimits := map[string][]diskBlockLimit{}
-	for devName, dev := range d.inst.ExpandedDevices().Filter(filters.IsDisk) {
-		// Parse the user input
-		readBps, readIops, writeBps, writeIops, err := d.parseLimit(dev)
-		if err != nil {
-			return nil, err
+	for devName, dev := range devices {
+		diskLimits := limits[devName]
+		if diskLimits == nil {
+			diskLimits = &deviceConfig.DiskLimits{}
 		}
 
+		readBps, readIops, writeBps, writeIops := diskLimits.ReadBytes, diskLimits.ReadIOps, diskLimits.WriteBytes, diskLimits.WriteIOps
+
 		// Set the source path
 		source := d.getDevicePath(devName, dev)
 		if dev["source"] == "" {
@@ -2347,6 +2371,147 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 	return result, nil
 }
 
+// diskLimits returns the I/O limits of the supplied disk devices keyed by device name, leaving out those without any.
+// Disk devices without limits of their own use the limits of the storage volume backing them if any. The volumes
+// are loaded in a single query, and failing to load them only skips their limits so the instance can still start.
+func (d *disk) diskLimits(devices deviceConfig.Devices) (map[string]*deviceConfig.DiskLimits, error) {
+	limits := make(map[string]*deviceConfig.DiskLimits, len(devices))
+	volumeDevices := map[string]db.StorageVolumeFilter{}
+	volumeFilters := make([]db.StorageVolumeFilter, 0, len(devices))
+
+	for devName, dev := range devices {
+		if dev["limits.read"] != "" || dev["limits.write"] != "" || dev["limits.max"] != "" {
+			// Parse the limits into usable values.
+			readBps, readIops, writeBps, writeIops, err := d.parseLimit(dev)
+			if err != nil {
+				return nil, err
+			}
+
+			limits[devName] = &deviceConfig.DiskLimits{
+				ReadBytes:  readBps,
+				ReadIOps:   readIops,
+				WriteBytes: writeBps,
+				WriteIOps:  writeIops,
+			}
+
+			continue
+		}
+
+		if dev["pool"] == "" || d.inst == nil {
+			continue
+		}
+
+		var volumeName string
+		var dbVolumeType cluster.StoragePoolVolumeType
+		if filters.IsRootDisk(dev) {
+			volumeType, err := storagePools.InstanceTypeToVolumeType(d.inst.Type())
+			if err != nil {
+				return nil, err
+			}
+
+			dbVolumeType, err = storagePools.VolumeTypeToDBType(volumeType)
+			if err != nil {
+				return nil, err
+			}
+
+			volumeName = d.inst.Name()
+		} else if dev["source"] != "" {
+			var err error
+			volumeName, _, dbVolumeType, err = diskSourceVolumeFields(dev)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			continue
+		}
+
+		instProj := d.inst.Project()
+		storageProjectName := project.StorageVolumeProjectFromRecord(&instProj, dbVolumeType)
+
+		volumeFilter := db.StorageVolumeFilter{
+			Type:    &dbVolumeType,
+			Project: &storageProjectName,
+			Name:    &volumeName,
+		}
+
+		volumeDevices[devName] = volumeFilter
+		volumeFilters = append(volumeFilters, volumeFilter)
+	}
+
+	if len(volumeFilters) == 0 {
+		return limits, nil
+	}
+
+	var dbVolumes []*db.StorageVolume
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		dbVolumes, err = tx.GetStorageVolumes(ctx, true, volumeFilters...)
+		return err
+	})
+	if err != nil {
+		d.logger.Warn("Failed loading storage volumes for I/O limits", logger.Ctx{"err": err})
+		return limits, nil
+	}
+
+	for devName, volumeFilter := range volumeDevices {
+		for _, dbVolume := range dbVolumes {
+			if dbVolume.Pool != devices[devName]["pool"] || dbVolume.Project != *volumeFilter.Project || dbVolume.Type != volumeFilter.Type.String() || dbVolume.Name != *volumeFilter.Name {
+				continue
+			}
+
+			volumeLimits, err := diskVolumeLimits(dbVolume)
+			if err != nil {
+				d.logger.Warn("Skipping I/O limits of storage volume", logger.Ctx{"volume": dbVolume.Name, "err": err})
+				break
+			}
+
+			if volumeLimits != nil {
+				limits[devName] = volumeLimits
+			}
+
+			break
+		}
+	}
+
+	return limits, nil
+}
+
+// diskVolumeLimits returns the I/O limits set on a storage volume, or nil if it has none.
+func diskVolumeLimits(dbVolume *db.StorageVolume) (*deviceConfig.DiskLimits, error) {
+	if dbVolume.Config["limits.iops"] == "" && dbVolume.Config["limits.bandwidth"] == "" {
+		return nil, nil
+	}
+
+	limits := &deviceConfig.DiskLimits{}
+
+	if dbVolume.Config["limits.iops"] != "" {
+		iops, err := strconv.ParseInt(dbVolume.Config["limits.iops"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid IOPS limit: %w", err)
+		}
+
+		limits.ReadIOps = iops
+		limits.WriteIOps = iops
+	}
+
+	if dbVolume.Config["limits.bandwidth"] != "" {
+		bps, err := units.ParseByteSizeString(dbVolume.Config["limits.bandwidth"])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid bandwidth limit: %w", err)
+		}
+
+		limits.ReadBytes = bps
+		limits.WriteBytes = bps
+	}
+
+	// Disks backed by the same volume share their limits.
+	if dbVolume.Config["volatile.uuid"] != "" {
+		limits.Group = "lxd_vol_" + dbVolume.Config["volatile.uuid"]
+	}
+
+	return limits, nil
+}
+
 // parseLimit parses the disk configuration for its I/O limits and returns the I/O bytes/iops limits.
 func (d *disk) parseLimit(dev deviceConfig.Device) (readBps int64, readIops int64, writeBps int64, writeIops int64, err error) {
 	readSpeed := dev["limits.read"]
@@ -0,0 +1,60 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/db"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestDiskLimits(t *testing.T) {
+	d := &disk{}
+
+	devices := deviceConfig.Devices{
+		"data": {"type": "disk", "source": "/srv", "path": "/srv", "limits.read": "10MB", "limits.write": "20iops"},
+		"logs": {"type": "disk", "source": "/var/log", "path": "/mnt", "limits.max": "5MB"},
+		"tmp":  {"type": "disk", "source": "/tmp", "path": "/tmp"},
+	}
+
+	// Devices with limits of their own use those, and devices without any are left out.
+	limits, err := d.diskLimits(devices)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*deviceConfig.DiskLimits{
+		"data": {ReadBytes: 10000000, WriteIOps: 20},
+		"logs": {ReadBytes: 5000000, WriteBytes: 5000000},
+	}, limits)
+
+	// Invalid device limits are rejected.
+	_, err = d.diskLimits(deviceConfig.Devices{"data": {"type": "disk", "limits.read": "invalid"}})
+	assert.Error(t, err)
+}
+
+func TestDiskVolumeLimits(t *testing.T) {
+	volume := func(config map[string]string) *db.StorageVolume {
+		return &db.StorageVolume{StorageVolume: api.StorageVolume{Name: "vol1", Config: config}}
+	}
+
+	// Volumes without limits don't limit the disks backed by them.
+	limits, err := diskVolumeLimits(volume(map[string]string{"volatile.uuid": "uuid1"}))
+	require.NoError(t, err)
+	assert.Nil(t, limits)
+
+	// Volume limits apply to both reads and writes, and are shared by the disks backed by the volume.
+	limits, err = diskVolumeLimits(volume(map[string]string{"limits.iops": "100", "limits.bandwidth": "10MiB", "volatile.uuid": "uuid1"}))
+	require.NoError(t, err)
+	assert.Equal(t, &deviceConfig.DiskLimits{
+		ReadBytes:  10485760,
+		ReadIOps:   100,
+		WriteBytes: 10485760,
+		WriteIOps:  100,
+		Group:      "lxd_vol_uuid1",
+	}, limits)
+
+	// Invalid volume limits are reported.
+	_, err = diskVolumeLimits(volume(map[string]string{"limits.iops": "many"}))
+	assert.Error(t, err)
+}
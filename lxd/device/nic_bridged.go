@@ -630,22 +630,30 @@ func (d *nicBridged) Start() (*deviceConfig.RunConfig, error) {
 		}
 
 		if brNetfilterEnabled {
-			var listenAddresses map[int64]string
+			var listenAddresses, loadBalancerListenAddresses map[int64]string
 
 			err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 				listenAddresses, err = tx.GetNetworkForwardListenAddresses(ctx, d.network.ID(), true)
+				if err != nil {
+					return fmt.Errorf("Failed loading network forwards: %w", err)
+				}
 
-				return err
+				loadBalancerListenAddresses, err = tx.GetNetworkLoadBalancerListenAddresses(ctx, d.network.ID(), true)
+				if err != nil {
+					return fmt.Errorf("Failed loading network load balancers: %w", err)
+				}
+
+				return nil
 			})
 			if err != nil {
-				return nil, fmt.Errorf("Failed loading network forwards: %w", err)
+				return nil, err
 			}
 
-			// If br_netfilter is enabled and bridge has forwards, we enable hairpin mode on NIC's
-			// bridge port in case any of the forwards target this NIC and the instance attempts to
-			// connect to the forward's listener. Without hairpin mode on the target of the forward
-			// will not be able to connect to the listener.
-			if len(listenAddresses) > 0 {
+			// If br_netfilter is enabled and bridge has forwards or load balancers, we enable hairpin mode
+			// on NIC's bridge port in case any of the forwards or load balancers target this NIC and the
+			// instance attempts to connect to the listener. Without hairpin mode on the target of the
+			// forward will not be able to connect to the listener.
+			if len(listenAddresses) > 0 || len(loadBalancerListenAddresses) > 0 {
 				link := &ip.Link{Name: saveData["host_name"]}
 				err = link.BridgeLinkSetHairpin(true)
 				if err != nil {
@@ -692,6 +700,11 @@ func (d *nicBridged) postStart() error {
 		return err
 	}
 
+	// Pick up the instance in the load balancer pools it belongs to.
+	if d.network != nil {
+		network.LoadBalancerRefresh(d.network.Name())
+	}
+
 	return nil
 }
 
@@ -873,6 +886,11 @@ func (d *nicBridged) postStop() error {
 		d.removeFilters(d.config)
 	}
 
+	// Stop targeting the instance in the load balancer pools it belongs to.
+	if d.network != nil {
+		network.LoadBalancerRefresh(d.network.Name())
+	}
+
 	return nil
 }
 
@@ -1799,5 +1817,10 @@ func (d *nicBridged) Register() error {
 		return err
 	}
 
+	// Pick up the instance in the load balancer pools it belongs to.
+	if d.network != nil {
+		network.LoadBalancerRefresh(d.network.Name())
+	}
+
 	return nil
 }
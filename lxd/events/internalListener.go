@@ -41,6 +41,7 @@ func (l *InternalListener) startListener() {
 	filteredEvents := []string{
 		api.EventTypeLifecycle,
 		api.EventTypeLogging,
+		api.EventTypeNetworkACL,
 		api.EventTypeOVN,
 		api.EventTypeSecurity,
 	}
@@ -32,6 +32,7 @@ type ACLRule struct {
 	Action          string
 	Log             bool   // Whether or not to log matched packets.
 	LogName         string // Log label name (requires Log be true).
+	CounterName     string // Name used to identify the rule's counters (optional).
 	Source          string
 	Destination     string
 	Protocol        string
@@ -41,6 +42,18 @@ type ACLRule struct {
 	ICMPCode        string
 }
 
+// ACLRuleCounters represents the packet and byte counters of an ACL rule.
+type ACLRuleCounters struct {
+	Packets uint64 `json:"packets"`
+	Bytes   uint64 `json:"bytes"`
+}
+
+// AddressSet represents a named set of addresses that ACL rules can reference using "$<Name>" subjects.
+type AddressSet struct {
+	Name      string
+	Addresses []net.IPNet
+}
+
 // AddressForward represents a NAT address forward.
 type AddressForward struct {
 	ListenAddress net.IP
@@ -49,3 +62,24 @@ type AddressForward struct {
 	ListenPorts   []uint64
 	TargetPorts   []uint64
 }
+
+// LoadBalancer represents a NAT load balancer listen address and port spreading traffic over a set of targets.
+type LoadBalancer struct {
+	ListenAddress net.IP
+	Protocol      string
+	ListenPort    uint64
+	Targets       []LoadBalancerTarget
+}
+
+// LoadBalancerTarget represents a load balancer target.
+type LoadBalancerTarget struct {
+	Address net.IP
+	Port    uint64
+}
+
+// NetworkPeer represents a routed peering between the subnets of a network and the subnets of a target network.
+type NetworkPeer struct {
+	TargetNetwork string
+	LocalSubnets  []*net.IPNet
+	TargetSubnets []*net.IPNet
+}
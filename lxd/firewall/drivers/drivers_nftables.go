@@ -92,11 +92,11 @@ func (d Nftables) Compat() (bool, error) {
 
 // nftGenericItem represents some common fields amongst the different nftables types.
 type nftGenericItem struct {
-	itemType string // Type of item (table, chain or rule). Populated by LXD.
+	itemType string // Type of item (table, chain, set or rule). Populated by LXD.
 	Family   string `json:"family"` // Family of item (ip, ip6, bridge etc).
 	Table    string `json:"table"`  // Table the item belongs to (for chains and rules).
 	Chain    string `json:"chain"`  // Chain the item belongs to (for rules).
-	Name     string `json:"name"`   // Name of item (for tables and chains).
+	Name     string `json:"name"`   // Name of item (for tables, chains and sets).
 }
 
 // nftParseRuleset parses the ruleset and returns the generic parts as a slice of items.
@@ -129,6 +129,7 @@ func (d Nftables) nftParseRuleset() ([]nftGenericItem, error) {
 	for _, item := range v.Nftables {
 		rule, foundRule := item["rule"]
 		chain, foundChain := item["chain"]
+		set, foundSet := item["set"]
 		table, foundTable := item["table"]
 		if foundRule {
 			rule.itemType = "rule"
@@ -136,6 +137,9 @@ func (d Nftables) nftParseRuleset() ([]nftGenericItem, error) {
 		} else if foundChain {
 			chain.itemType = "chain"
 			items = append(items, chain)
+		} else if foundSet {
+			set.itemType = "set"
+			items = append(items, set)
 		} else if foundTable {
 			table.itemType = "table"
 			items = append(items, table)
@@ -318,6 +322,8 @@ func (d Nftables) NetworkClear(networkName string, _ bool, _ []uint) error {
 		"fwd", "pstrt", "in", "out", // Chains used for network operation rules.
 		"aclin", "aclout", "aclfwd", "acl", // Chains used by ACL rules.
 		"fwdprert", "fwdout", "fwdpstrt", // Chains used by Address Forward rules.
+		"lbprert", "lbout", "lbpstrt", // Chains used by Load Balancer rules.
+		"peerfwd", "peerpstrt", // Chains used by Network Peer rules.
 		"egress", // Chains added for limits.priority option
 	}
 
@@ -654,32 +660,41 @@ func (d Nftables) InstanceClearNetPrio(projectName string, instanceName string,
 // NetworkApplyACLRules applies ACL rules to the existing firewall chains.
 func (d Nftables) NetworkApplyACLRules(networkName string, rules []ACLRule) error {
 	nftRules := make([]string, 0)
-	for _, rule := range rules {
-		// First try generating rules with IPv4 or IP agnostic criteria.
-		nftRule, partial, err := d.aclRuleCriteriaToRules(networkName, 4, &rule)
-		if err != nil {
-			return err
-		}
+	for _, aclRule := range rules {
+		for _, rule := range d.aclRuleSplitAddressSets(aclRule) {
+			// Rules referencing address sets match both IP versions, even if one of them can't match anything.
+			hasAddressSet := d.aclRuleHasAddressSet(&rule)
 
-		if nftRule != "" {
-			nftRules = append(nftRules, nftRule)
-		}
-
-		if partial {
-			// If we couldn't fully generate the ruleset with only IPv4 or IP agnostic criteria, then
-			// fill in the remaining parts using IPv6 criteria.
-			nftRule, _, err = d.aclRuleCriteriaToRules(networkName, 6, &rule)
+			// First try generating rules with IPv4 or IP agnostic criteria.
+			nftRule, partial, err := d.aclRuleCriteriaToRules(networkName, 4, &rule)
 			if err != nil {
 				return err
 			}
 
-			if nftRule == "" {
-				return errors.New("Invalid empty rule generated")
+			if nftRule != "" {
+				nftRules = append(nftRules, nftRule)
 			}
 
-			nftRules = append(nftRules, nftRule)
-		} else if nftRule == "" {
-			return errors.New("Invalid empty rule generated")
+			if partial {
+				// If we couldn't fully generate the ruleset with only IPv4 or IP agnostic criteria, then
+				// fill in the remaining parts using IPv6 criteria.
+				nftRule, _, err = d.aclRuleCriteriaToRules(networkName, 6, &rule)
+				if err != nil {
+					return err
+				}
+
+				if nftRule == "" {
+					if hasAddressSet {
+						continue
+					}
+
+					return errors.New("Invalid empty rule generated")
+				}
+
+				nftRules = append(nftRules, nftRule)
+			} else if nftRule == "" {
+				return errors.New("Invalid empty rule generated")
+			}
 		}
 	}
 
@@ -783,7 +798,7 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 			// with at least some subjects in the same family as ipVersion. So if the icmpIPVersion
 			// doesn't match the ipVersion then it means the rule contains mixed-version subjects
 			// which is invalid when using an IP version specific ICMP protocol.
-			if rule.Source != "" || rule.Destination != "" {
+			if (rule.Source != "" || rule.Destination != "") && !d.aclRuleHasAddressSet(rule) {
 				return "", false, fmt.Errorf("Invalid use of %q protocol with non-IPv%d source/destination criteria", rule.Protocol, ipVersion)
 			}
 
@@ -801,6 +816,11 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 		}
 	}
 
+	// Handle counters.
+	if rule.CounterName != "" {
+		args = append(args, "counter")
+	}
+
 	// Handle logging.
 	if rule.Log {
 		args = append(args, "log")
@@ -819,9 +839,204 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 
 	args = append(args, action)
 
+	// The comment is used to find the rule's counters and must be the last statement of the rule.
+	if rule.CounterName != "" {
+		args = append(args, "comment", `"`+rule.CounterName+`"`)
+	}
+
 	return strings.Join(args, " "), isPartialRule, nil
 }
 
+// NetworkACLRuleCounters returns the counters of the ACL rules applied to the network, indexed by counter name.
+// The counters of the rules generated for each IP family are added together.
+func (d Nftables) NetworkACLRuleCounters(networkName string) (map[string]ACLRuleCounters, error) {
+	counters := make(map[string]ACLRuleCounters)
+
+	chain := "acl" + nftablesChainSeparator + networkName
+	ruleset, err := d.nftParseRuleset()
+	if err != nil {
+		return nil, err
+	}
+
+	chainFound := false
+	for _, item := range ruleset {
+		if item.itemType == "chain" && item.Family == "inet" && item.Table == nftablesNamespace && item.Name == chain {
+			chainFound = true
+			break
+		}
+	}
+
+	// Nothing to report if the ACL rules haven't been applied to the network.
+	if !chainFound {
+		return counters, nil
+	}
+
+	// Use -nn flags to avoid doing DNS lookups of IPs mentioned in the rules.
+	stdout, err := shared.RunCommand(context.TODO(), "nft", "--json", "-nn", "list", "chain", "inet", nftablesNamespace, chain)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing nftables chain %q: %w", chain, err)
+	}
+
+	// This only extracts the comment and the counter statement of the rules, see man libnftables-json for more info.
+	v := &struct {
+		Nftables []struct {
+			Rule *struct {
+				Comment string `json:"comment"`
+				Expr    []struct {
+					Counter *ACLRuleCounters `json:"counter"`
+				} `json:"expr"`
+			} `json:"rule"`
+		} `json:"nftables"`
+	}{}
+
+	err = json.Unmarshal([]byte(stdout), v)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing nftables chain %q: %w", chain, err)
+	}
+
+	for _, item := range v.Nftables {
+		if item.Rule == nil || item.Rule.Comment == "" {
+			continue
+		}
+
+		for _, expr := range item.Rule.Expr {
+			if expr.Counter == nil {
+				continue
+			}
+
+			ruleCounters := counters[item.Rule.Comment]
+			ruleCounters.Packets += expr.Counter.Packets
+			ruleCounters.Bytes += expr.Counter.Bytes
+			counters[item.Rule.Comment] = ruleCounters
+		}
+	}
+
+	return counters, nil
+}
+
+// aclRuleHasAddressSet returns whether the source or destination subjects of the rule reference an address set.
+func (d Nftables) aclRuleHasAddressSet(rule *ACLRule) bool {
+	for _, subjects := range []string{rule.Source, rule.Destination} {
+		for _, subject := range shared.SplitNTrimSpace(subjects, ",", -1, true) {
+			if strings.HasPrefix(subject, "$") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// aclRuleSplitAddressSets splits an ACL rule whose subjects reference address sets into rules whose source and
+// destination reference either a single address set or only other subjects. This is because nftables named sets
+// cannot be combined with other elements in a single match.
+func (d Nftables) aclRuleSplitAddressSets(rule ACLRule) []ACLRule {
+	if !d.aclRuleHasAddressSet(&rule) {
+		return []ACLRule{rule}
+	}
+
+	splitSubjects := func(subjects string) []string {
+		if subjects == "" {
+			return []string{""}
+		}
+
+		var others []string
+		var groups []string
+		for _, subject := range shared.SplitNTrimSpace(subjects, ",", -1, true) {
+			if strings.HasPrefix(subject, "$") {
+				groups = append(groups, subject)
+			} else {
+				others = append(others, subject)
+			}
+		}
+
+		if len(others) > 0 {
+			groups = append([]string{strings.Join(others, ",")}, groups...)
+		}
+
+		return groups
+	}
+
+	var rules []ACLRule
+	for _, source := range splitSubjects(rule.Source) {
+		for _, destination := range splitSubjects(rule.Destination) {
+			splitRule := rule
+			splitRule.Source = source
+			splitRule.Destination = destination
+			rules = append(rules, splitRule)
+		}
+	}
+
+	return rules
+}
+
+// NetworkApplyAddressSets creates or updates the named sets of the address sets referenced by ACL rules.
+// The sets are shared by the networks whose ACL rules reference them.
+func (d Nftables) NetworkApplyAddressSets(sets []AddressSet) error {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	nftSets := make([]map[string]any, 0, len(sets))
+	for _, set := range sets {
+		subnets := make([]*net.IPNet, 0, len(set.Addresses))
+		for i := range set.Addresses {
+			subnets = append(subnets, &set.Addresses[i])
+		}
+
+		nftSets = append(nftSets, map[string]any{
+			"name":         set.Name,
+			"ip4Addresses": strings.Join(nftablesSubnetsOfFamily("ip", subnets), ", "),
+			"ip6Addresses": strings.Join(nftablesSubnetsOfFamily("ip6", subnets), ", "),
+		})
+	}
+
+	tplFields := map[string]any{
+		"namespace": nftablesNamespace,
+		"family":    "inet",
+		"sets":      nftSets,
+	}
+
+	config := &strings.Builder{}
+	err := nftablesNetAddressSets.Execute(config, tplFields)
+	if err != nil {
+		return fmt.Errorf("Failed running %q template: %w", nftablesNetAddressSets.Name(), err)
+	}
+
+	err = shared.RunCommandWithFds(context.TODO(), strings.NewReader(config.String()), nil, "nft", "-f", "-")
+	if err != nil {
+		return fmt.Errorf("Failed applying address sets: %w", err)
+	}
+
+	return nil
+}
+
+// NetworkDeleteAddressSets deletes the named sets of the specified address sets if they exist.
+// The sets must not be referenced by ACL rules anymore.
+func (d Nftables) NetworkDeleteAddressSets(names []string) error {
+	ruleset, err := d.nftParseRuleset()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		for _, setName := range []string{name + "_ip4", name + "_ip6"} {
+			for _, item := range ruleset {
+				if item.itemType != "set" || item.Family != "inet" || item.Table != nftablesNamespace || item.Name != setName {
+					continue
+				}
+
+				_, err = shared.RunCommand(context.TODO(), "nft", "delete", "set", item.Family, nftablesNamespace, item.Name)
+				if err != nil {
+					return fmt.Errorf("Failed deleting nftables set %q: %w", item.Name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // aclRuleSubjectToACLMatch converts direction (source/destination) and subject criteria list into xtables args.
 // Returns nil if none of the subjects are appropriate for the ipVersion.
 func (d Nftables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, subjectCriteria ...string) ([]string, bool, error) {
@@ -831,6 +1046,22 @@ func (d Nftables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, sub
 
 	// For each criterion check if value looks like IP CIDR.
 	for _, subjectCriterion := range subjectCriteria {
+		setName, isAddressSet := strings.CutPrefix(subjectCriterion, "$")
+		if isAddressSet {
+			// Named sets can't be mixed with other elements, see aclRuleSplitAddressSets.
+			if len(subjectCriteria) > 1 {
+				return nil, false, fmt.Errorf("Address set subject %q cannot be combined with other subjects", subjectCriterion)
+			}
+
+			ipFamily := "ip"
+			if ipVersion == 6 {
+				ipFamily = "ip6"
+			}
+
+			// The address set is split into an IPv4 and an IPv6 set, so the rule is always partial.
+			return []string{ipFamily, direction, fmt.Sprintf("@%s_ip%d", setName, ipVersion)}, true, nil
+		}
+
 		if validate.IsNetworkRange(subjectCriterion) == nil {
 			criterionParts := strings.SplitN(subjectCriterion, "-", 2)
 
@@ -1038,3 +1269,195 @@ func (d Nftables) NetworkApplyForwards(networkName string, rules []AddressForwar
 
 	return nil
 }
+
+// NetworkApplyLoadBalancers apply network load balancer rules to firewall.
+// Connections to each listen address and port are spread over the targets, using a round robin for TCP and a
+// hash of the source address for UDP so that datagrams from the same client reach the same target.
+func (d Nftables) NetworkApplyLoadBalancers(networkName string, rules []LoadBalancer) error {
+	var dnatRules []map[string]any
+	var snatRules []map[string]any
+
+	snatTargets := make(map[string]struct{})
+
+	for ruleIndex, rule := range rules {
+		// Validate the rule.
+		if rule.ListenAddress == nil {
+			return fmt.Errorf("Invalid rule %d, listen address is required", ruleIndex)
+		}
+
+		if rule.Protocol == "" || rule.ListenPort == 0 {
+			return fmt.Errorf("Invalid rule %d, protocol and listen port are required", ruleIndex)
+		}
+
+		// Without any target the traffic isn't load balanced.
+		if len(rule.Targets) == 0 {
+			continue
+		}
+
+		ipFamily := "ip"
+		if rule.ListenAddress.To4() == nil {
+			ipFamily = "ip6"
+		}
+
+		targetDests := make([]string, 0, len(rule.Targets))
+		for targetIndex, target := range rule.Targets {
+			if target.Address == nil || target.Port == 0 {
+				return fmt.Errorf("Invalid rule %d, target %d address and port are required", ruleIndex, targetIndex)
+			}
+
+			if (target.Address.To4() == nil) != (rule.ListenAddress.To4() == nil) {
+				return fmt.Errorf("Invalid rule %d, target %d address family doesn't match listen address", ruleIndex, targetIndex)
+			}
+
+			targetHost := target.Address.String()
+			targetPort := strconv.FormatUint(target.Port, 10)
+
+			if len(rule.Targets) == 1 {
+				if ipFamily == "ip6" {
+					targetDests = append(targetDests, "["+targetHost+"]:"+targetPort)
+				} else {
+					targetDests = append(targetDests, targetHost+":"+targetPort)
+				}
+			} else {
+				targetDests = append(targetDests, fmt.Sprintf("%d : %s . %s", targetIndex, targetHost, targetPort))
+			}
+
+			// Apply a masquerade rule for each target for instance <-> instance traffic.
+			// Requires instance's bridge port has hairpin mode enabled when br_netfilter is loaded.
+			snatKey := rule.Protocol + "/" + net.JoinHostPort(targetHost, targetPort)
+			_, found := snatTargets[snatKey]
+			if !found {
+				snatTargets[snatKey] = struct{}{}
+				snatRules = append(snatRules, map[string]any{
+					"ipFamily":   ipFamily,
+					"protocol":   rule.Protocol,
+					"targetHost": targetHost,
+					"targetPort": targetPort,
+				})
+			}
+		}
+
+		targetDest := targetDests[0]
+		if len(targetDests) > 1 {
+			selector := fmt.Sprintf("numgen inc mod %d", len(targetDests))
+			if rule.Protocol == "udp" {
+				selector = fmt.Sprintf("jhash %s saddr mod %d", ipFamily, len(targetDests))
+			}
+
+			targetDest = selector + " map { " + strings.Join(targetDests, ", ") + " }"
+		}
+
+		dnatRules = append(dnatRules, map[string]any{
+			"ipFamily":      ipFamily,
+			"protocol":      rule.Protocol,
+			"listenAddress": rule.ListenAddress.String(),
+			"listenPort":    rule.ListenPort,
+			"targetDest":    targetDest,
+		})
+	}
+
+	// Apply rules or remove chains if no rules generated.
+	if len(dnatRules) > 0 {
+		tplFields := map[string]any{
+			"namespace":      nftablesNamespace,
+			"chainSeparator": nftablesChainSeparator,
+			"family":         "inet",
+			"networkName":    networkName,
+			"dnatRules":      dnatRules,
+			"snatRules":      snatRules,
+		}
+
+		config := &strings.Builder{}
+		err := nftablesNetLoadBalancer.Execute(config, tplFields)
+		if err != nil {
+			return fmt.Errorf("Failed running %q template: %w", nftablesNetLoadBalancer.Name(), err)
+		}
+
+		err = shared.RunCommandWithFds(context.TODO(), strings.NewReader(config.String()), nil, "nft", "-f", "-")
+		if err != nil {
+			return err
+		}
+	} else {
+		err := d.removeChains([]string{"inet"}, networkName, "lbprert", "lbout", "lbpstrt")
+		if err != nil {
+			return fmt.Errorf("Failed clearing nftables load balancer rules for network %q: %w", networkName, err)
+		}
+	}
+
+	return nil
+}
+
+// NetworkApplyPeers applies network peer rules to firewall.
+// Traffic forwarded from the network to each peer network is only allowed between their subnets, and isn't
+// masqueraded so that the peer network sees the original source addresses.
+func (d Nftables) NetworkApplyPeers(networkName string, peers []NetworkPeer) error {
+	var rules []map[string]any
+	targetNetworks := make([]string, 0, len(peers))
+
+	for peerIndex, peer := range peers {
+		if peer.TargetNetwork == "" {
+			return fmt.Errorf("Invalid peer %d, target network is required", peerIndex)
+		}
+
+		targetNetworks = append(targetNetworks, peer.TargetNetwork)
+
+		for _, ipFamily := range []string{"ip", "ip6"} {
+			localSubnets := nftablesSubnetsOfFamily(ipFamily, peer.LocalSubnets)
+			targetSubnets := nftablesSubnetsOfFamily(ipFamily, peer.TargetSubnets)
+
+			// Only allow traffic for families where both networks have subnets.
+			if len(localSubnets) == 0 || len(targetSubnets) == 0 {
+				continue
+			}
+
+			rules = append(rules, map[string]any{
+				"ipFamily":      ipFamily,
+				"targetNetwork": peer.TargetNetwork,
+				"localSubnets":  strings.Join(localSubnets, ", "),
+				"targetSubnets": strings.Join(targetSubnets, ", "),
+			})
+		}
+	}
+
+	// Apply rules or remove chains if no peers.
+	if len(targetNetworks) > 0 {
+		tplFields := map[string]any{
+			"namespace":      nftablesNamespace,
+			"chainSeparator": nftablesChainSeparator,
+			"family":         "inet",
+			"networkName":    networkName,
+			"rules":          rules,
+			"targetNetworks": targetNetworks,
+		}
+
+		config := &strings.Builder{}
+		err := nftablesNetPeer.Execute(config, tplFields)
+		if err != nil {
+			return fmt.Errorf("Failed running %q template: %w", nftablesNetPeer.Name(), err)
+		}
+
+		err = shared.RunCommandWithFds(context.TODO(), strings.NewReader(config.String()), nil, "nft", "-f", "-")
+		if err != nil {
+			return err
+		}
+	} else {
+		err := d.removeChains([]string{"inet"}, networkName, "peerfwd", "peerpstrt")
+		if err != nil {
+			return fmt.Errorf("Failed clearing nftables peer rules for network %q: %w", networkName, err)
+		}
+	}
+
+	return nil
+}
+
+// nftablesSubnetsOfFamily returns the string representation of the subnets of the given nftables IP family.
+func nftablesSubnetsOfFamily(ipFamily string, subnets []*net.IPNet) []string {
+	familySubnets := make([]string, 0, len(subnets))
+	for _, subnet := range subnets {
+		if (subnet.IP.To4() != nil) == (ipFamily == "ip") {
+			familySubnets = append(familySubnets, subnet.String())
+		}
+	}
+
+	return familySubnets
+}
@@ -121,6 +121,70 @@ table {{.family}} {{.namespace}} {
 }
 `))
 
+var nftablesNetLoadBalancer = template.Must(template.New("nftablesNetLoadBalancer").Parse(`
+add table {{.family}} {{.namespace}}
+add chain {{.family}} {{.namespace}} lbprert{{.chainSeparator}}{{.networkName}} {type nat hook prerouting priority -100; policy accept;}
+add chain {{.family}} {{.namespace}} lbout{{.chainSeparator}}{{.networkName}} {type nat hook output priority -100; policy accept;}
+add chain {{.family}} {{.namespace}} lbpstrt{{.chainSeparator}}{{.networkName}} {type nat hook postrouting priority 100; policy accept;}
+flush chain {{.family}} {{.namespace}} lbprert{{.chainSeparator}}{{.networkName}}
+flush chain {{.family}} {{.namespace}} lbout{{.chainSeparator}}{{.networkName}}
+flush chain {{.family}} {{.namespace}} lbpstrt{{.chainSeparator}}{{.networkName}}
+
+table {{.family}} {{.namespace}} {
+	chain lbprert{{.chainSeparator}}{{.networkName}} {
+		type nat hook prerouting priority -100; policy accept;
+		{{- range .dnatRules}}
+		{{.ipFamily}} daddr {{.listenAddress}} {{.protocol}} dport {{.listenPort}} dnat {{.ipFamily}} to {{.targetDest}}
+		{{- end}}
+	}
+
+	chain lbout{{.chainSeparator}}{{.networkName}} {
+		type nat hook output priority -100; policy accept;
+		{{- range .dnatRules}}
+		{{.ipFamily}} daddr {{.listenAddress}} {{.protocol}} dport {{.listenPort}} dnat {{.ipFamily}} to {{.targetDest}}
+		{{- end}}
+	}
+
+	chain lbpstrt{{.chainSeparator}}{{.networkName}} {
+		type nat hook postrouting priority 100; policy accept;
+		{{- range .snatRules}}
+		{{.ipFamily}} saddr {{.targetHost}} {{.ipFamily}} daddr {{.targetHost}} {{.protocol}} dport {{.targetPort}} masquerade
+		{{- end}}
+	}
+}
+`))
+
+// nftablesNetPeer defines the rules of the routed peerings of a network.
+// Traffic forwarded from the network to a peer network is only allowed between the subnets of both networks.
+// Peer traffic keeps its source address: mapping the source address onto itself binds the connection's NAT before
+// the network's outbound NAT chain is reached, which runs at a later priority of the same hook.
+var nftablesNetPeer = template.Must(template.New("nftablesNetPeer").Parse(`
+add table {{.family}} {{.namespace}}
+add chain {{.family}} {{.namespace}} peerfwd{{.chainSeparator}}{{.networkName}} {type filter hook forward priority filter; policy accept;}
+add chain {{.family}} {{.namespace}} peerpstrt{{.chainSeparator}}{{.networkName}} {type nat hook postrouting priority 99; policy accept;}
+flush chain {{.family}} {{.namespace}} peerfwd{{.chainSeparator}}{{.networkName}}
+flush chain {{.family}} {{.namespace}} peerpstrt{{.chainSeparator}}{{.networkName}}
+
+table {{.family}} {{.namespace}} {
+	chain peerfwd{{.chainSeparator}}{{.networkName}} {
+		type filter hook forward priority filter; policy accept;
+		{{- range .rules}}
+		iifname "{{$.networkName}}" oifname "{{.targetNetwork}}" {{.ipFamily}} saddr { {{.localSubnets}} } {{.ipFamily}} daddr { {{.targetSubnets}} } accept
+		{{- end}}
+		{{- range .targetNetworks}}
+		iifname "{{$.networkName}}" oifname "{{.}}" drop
+		{{- end}}
+	}
+
+	chain peerpstrt{{.chainSeparator}}{{.networkName}} {
+		type nat hook postrouting priority 99; policy accept;
+		{{- range .rules}}
+		oifname "{{.targetNetwork}}" {{.ipFamily}} saddr { {{.localSubnets}} } {{.ipFamily}} daddr { {{.targetSubnets}} } snat {{.ipFamily}} to {{.ipFamily}} saddr
+		{{- end}}
+	}
+}
+`))
+
 var nftablesNetACLSetup = template.Must(template.New("nftablesNetACLSetup").Parse(`
 add table {{.family}} {{.namespace}}
 add chain {{.family}} {{.namespace}} acl{{.chainSeparator}}{{.networkName}}
@@ -186,6 +250,24 @@ table {{.family}} {{.namespace}} {
 }
 `))
 
+// nftablesNetAddressSets defines the named sets holding the addresses of the address sets referenced by ACL rules.
+// Each address set is split into an IPv4 and an IPv6 set, named "<name>_ip4" and "<name>_ip6".
+var nftablesNetAddressSets = template.Must(template.New("nftablesNetAddressSets").Parse(`
+add table {{.family}} {{.namespace}}
+{{- range .sets}}
+add set {{$.family}} {{$.namespace}} {{.name}}_ip4 {type ipv4_addr; flags interval; auto-merge;}
+add set {{$.family}} {{$.namespace}} {{.name}}_ip6 {type ipv6_addr; flags interval; auto-merge;}
+flush set {{$.family}} {{$.namespace}} {{.name}}_ip4
+flush set {{$.family}} {{$.namespace}} {{.name}}_ip6
+{{- if .ip4Addresses}}
+add element {{$.family}} {{$.namespace}} {{.name}}_ip4 { {{.ip4Addresses}} }
+{{- end}}
+{{- if .ip6Addresses}}
+add element {{$.family}} {{$.namespace}} {{.name}}_ip6 { {{.ip6Addresses}} }
+{{- end}}
+{{- end}}
+`))
+
 // nftablesInstanceBridgeFilter defines the rules needed for MAC, IPv4 and IPv6 bridge security filtering.
 // To prevent instances from using IPs that are different from their assigned IPs we use ARP and NDP filtering
 // to prevent neighbour advertisements that are not allowed. However in order for DHCPv4 & DHCPv6 to work back to
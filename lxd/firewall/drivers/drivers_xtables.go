@@ -26,6 +26,9 @@ const iptablesChainNICFilterPrefix = "lxd_nic"
 // iptablesChainACLFilterPrefix chain used for ACL specific filtering rules.
 const iptablesChainACLFilterPrefix = "lxd_acl"
 
+// iptablesChainPeerFilterPrefix chain used for network peer specific filtering rules.
+const iptablesChainPeerFilterPrefix = "lxd_peer"
+
 // iptablesCommentPrefix is used to prefix the rule comment.
 const iptablesCommentPrefix = "generated for"
 
@@ -160,6 +163,16 @@ func (d Xtables) networkForwardIPTablesComment(networkName string) string {
 	return "LXD network-forward " + networkName
 }
 
+// networkLoadBalancerIPTablesComment returns the iptables comment that is added to each network load balancer related rule.
+func (d Xtables) networkLoadBalancerIPTablesComment(networkName string) string {
+	return "LXD network-load-balancer " + networkName
+}
+
+// networkPeerIPTablesComment returns the iptables comment that is added to each network peer related rule.
+func (d Xtables) networkPeerIPTablesComment(networkName string) string {
+	return "LXD network-peer " + networkName
+}
+
 // networkSetupNICFilteringChain creates the NIC filtering chain if it doesn't exist, and adds the jump rules to
 // the INPUT and FORWARD filter chains. Must be called after networkSetupForwardingPolicy so that the rules are
 // prepended before the default fowarding policy rules.
@@ -694,6 +707,11 @@ func (d Xtables) aclRuleCriteriaToArgs(networkName string, ipVersion uint, rule
 
 	actionArgs = append(args, "-j", strings.ToUpper(action))
 
+	// The comment is used to find the rule's counters.
+	if rule.CounterName != "" {
+		actionArgs = append(actionArgs, "-m", "comment", "--comment", rule.CounterName)
+	}
+
 	// Handle logging.
 	if rule.Log {
 		logArgs = append(args, "-j", "LOG")
@@ -707,6 +725,76 @@ func (d Xtables) aclRuleCriteriaToArgs(networkName string, ipVersion uint, rule
 	return actionArgs, logArgs, nil
 }
 
+// NetworkApplyAddressSets is not supported by xtables, which has no equivalent of nftables named sets.
+func (d Xtables) NetworkApplyAddressSets(sets []AddressSet) error {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	return errors.New("Address sets are not supported with the xtables firewall driver")
+}
+
+// NetworkDeleteAddressSets does nothing as address sets are never applied with xtables.
+func (d Xtables) NetworkDeleteAddressSets(names []string) error {
+	return nil
+}
+
+// NetworkACLRuleCounters returns the counters of the ACL rules applied to the network, indexed by counter name.
+// The counters of the rules generated for each IP family are added together.
+func (d Xtables) NetworkACLRuleCounters(networkName string) (map[string]ACLRuleCounters, error) {
+	counters := make(map[string]ACLRuleCounters)
+
+	chain := iptablesChainACLFilterPrefix + "_" + networkName
+	for _, ipVersion := range []uint{4, 6} {
+		cmd := "iptables"
+		if ipVersion == 6 {
+			cmd = "ip6tables"
+		}
+
+		exists, _, err := d.iptablesChainExists(ipVersion, "filter", chain)
+		if err != nil || !exists {
+			continue // Nothing to report if the ACL rules haven't been applied for this IP family.
+		}
+
+		// Dump the rules of the chain with their counters ("-c <packets> <bytes>").
+		rules, err := shared.RunCommand(context.TODO(), cmd, "-w", "-t", "filter", "-S", chain, "-v")
+		if err != nil {
+			return nil, fmt.Errorf("Failed listing %q chain %q in table %q: %w", cmd, chain, "filter", err)
+		}
+
+		for _, rule := range shared.SplitNTrimSpace(strings.TrimSpace(rules), "\n", -1, true) {
+			fields := strings.Fields(rule)
+
+			var counterName string
+			var ruleCounters ACLRuleCounters
+			for i := 0; i < len(fields)-1; i++ {
+				switch fields[i] {
+				case "--comment":
+					counterName = strings.Trim(fields[i+1], `"`)
+				case "-c":
+					if i+2 >= len(fields) {
+						continue
+					}
+
+					ruleCounters.Packets, _ = strconv.ParseUint(fields[i+1], 10, 64)
+					ruleCounters.Bytes, _ = strconv.ParseUint(fields[i+2], 10, 64)
+				}
+			}
+
+			if counterName == "" {
+				continue
+			}
+
+			total := counters[counterName]
+			total.Packets += ruleCounters.Packets
+			total.Bytes += ruleCounters.Bytes
+			counters[counterName] = total
+		}
+	}
+
+	return counters, nil
+}
+
 // aclRuleSubjectToACLMatch converts direction (source/destination) and subject criteria list into xtables args.
 // Returns nil if none of the subjects are appropriate for the ipVersion.
 func (d Xtables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, subjectCriteria ...string) ([]string, error) {
@@ -765,6 +853,8 @@ func (d Xtables) NetworkClear(networkName string, remove bool, ipVersions []uint
 	comments := []string{
 		d.networkIPTablesComment(networkName),
 		d.networkForwardIPTablesComment(networkName),
+		d.networkLoadBalancerIPTablesComment(networkName),
+		d.networkPeerIPTablesComment(networkName),
 	}
 
 	for _, ipVersion := range ipVersions {
@@ -788,6 +878,20 @@ func (d Xtables) NetworkClear(networkName string, remove bool, ipVersions []uint
 			}
 		}
 
+		// Remove peer chain and rules.
+		peerFilterChain := iptablesChainPeerFilterPrefix + "_" + networkName
+		exists, hasRules, err = d.iptablesChainExists(ipVersion, "filter", peerFilterChain)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			err = d.iptablesChainDelete(ipVersion, "filter", peerFilterChain, hasRules)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Remove network specific chains (and any rules in them) if deleting.
 		if remove {
 			// Remove the NIC filter chain if it exists.
@@ -1667,3 +1771,222 @@ func (d Xtables) NetworkApplyForwards(networkName string, rules []AddressForward
 	reverter.Success()
 	return nil
 }
+
+// NetworkApplyLoadBalancers apply network load balancer rules to firewall.
+// Connections to each listen address and port are spread over the targets in turn using the statistic module.
+func (d Xtables) NetworkApplyLoadBalancers(networkName string, rules []LoadBalancer) error {
+	// Validate all rules first.
+	for i, rule := range rules {
+		if rule.ListenAddress == nil {
+			return fmt.Errorf("Invalid rule %d, listen address is required", i)
+		}
+
+		if rule.Protocol == "" || rule.ListenPort == 0 {
+			return fmt.Errorf("Invalid rule %d, protocol and listen port are required", i)
+		}
+
+		for j, target := range rule.Targets {
+			if target.Address == nil || target.Port == 0 {
+				return fmt.Errorf("Invalid rule %d, target %d address and port are required", i, j)
+			}
+
+			if (target.Address.To4() == nil) != (rule.ListenAddress.To4() == nil) {
+				return fmt.Errorf("Invalid rule %d, target %d address family doesn't match listen address", i, j)
+			}
+		}
+	}
+
+	comment := d.networkLoadBalancerIPTablesComment(networkName)
+
+	clearNetworkLoadBalancers := func() error {
+		for _, ipVersion := range []uint{4, 6} {
+			err := d.iptablesClear(ipVersion, []string{comment}, "nat")
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Clear any load balancer rules associated to the network.
+	err := clearNetworkLoadBalancers()
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Clear all network load balancers if we fail, otherwise the load balancers are only partially applied.
+	reverter.Add(func() {
+		err := clearNetworkLoadBalancers()
+		if err != nil {
+			logger.Error("Failed clearing firewall rules after failing to apply network load balancers", logger.Ctx{"network_name": networkName, "err": err})
+		}
+	})
+
+	for _, rule := range rules {
+		ipVersion := uint(4)
+		if rule.ListenAddress.To4() == nil {
+			ipVersion = 6
+		}
+
+		listenAddressStr := rule.ListenAddress.String()
+		listenPortStr := strconv.FormatUint(rule.ListenPort, 10)
+		targetsLen := len(rule.Targets)
+
+		// The rules are prepended, so add them from the last target to the first one.
+		// The Nth rule from the end matches every Nth remaining connection, so that the targets are used in turn.
+		for i := targetsLen - 1; i >= 0; i-- {
+			target := rule.Targets[i]
+			targetAddressStr := target.Address.String()
+			targetPortStr := strconv.FormatUint(target.Port, 10)
+
+			targetDest := targetAddressStr + ":" + targetPortStr
+			if ipVersion == 6 {
+				targetDest = "[" + targetAddressStr + "]:" + targetPortStr
+			}
+
+			// instance <-> instance.
+			// Requires instance's bridge port has hairpin mode enabled when br_netfilter is loaded.
+			err := d.iptablesPrepend(ipVersion, comment, "nat", "POSTROUTING", "-p", rule.Protocol, "--source", targetAddressStr, "--destination", targetAddressStr, "--dport", targetPortStr, "-j", "MASQUERADE")
+			if err != nil {
+				return err
+			}
+
+			args := []string{"-p", rule.Protocol, "--destination", listenAddressStr, "--dport", listenPortStr}
+			if i < targetsLen-1 {
+				args = append(args, "-m", "statistic", "--mode", "nth", "--every", strconv.Itoa(targetsLen-i), "--packet", "0")
+			}
+
+			args = append(args, "-j", "DNAT", "--to-destination", targetDest)
+
+			// outbound <-> instance.
+			err = d.iptablesPrepend(ipVersion, comment, "nat", "PREROUTING", args...)
+			if err != nil {
+				return err
+			}
+
+			// host <-> instance.
+			err = d.iptablesPrepend(ipVersion, comment, "nat", "OUTPUT", args...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// NetworkApplyPeers applies network peer rules to firewall.
+// Traffic forwarded from the network to each peer network is only allowed between their subnets, and isn't
+// masqueraded so that the peer network sees the original source addresses.
+func (d Xtables) NetworkApplyPeers(networkName string, peers []NetworkPeer) error {
+	for i, peer := range peers {
+		if peer.TargetNetwork == "" {
+			return fmt.Errorf("Invalid peer %d, target network is required", i)
+		}
+	}
+
+	chain := iptablesChainPeerFilterPrefix + "_" + networkName
+	comment := d.networkPeerIPTablesComment(networkName)
+
+	clearNetworkPeers := func() error {
+		for _, ipVersion := range []uint{4, 6} {
+			// Clear the jump rule before removing the chain it jumps to.
+			err := d.iptablesClear(ipVersion, []string{comment}, "filter", "nat")
+			if err != nil {
+				return err
+			}
+
+			exists, hasRules, err := d.iptablesChainExists(ipVersion, "filter", chain)
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				err = d.iptablesChainDelete(ipVersion, "filter", chain, hasRules)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	// Clear any peer rules associated to the network.
+	err := clearNetworkPeers()
+	if err != nil {
+		return err
+	}
+
+	if len(peers) == 0 {
+		return nil
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Clear all network peers if we fail, otherwise the peers are only partially applied.
+	reverter.Add(func() {
+		err := clearNetworkPeers()
+		if err != nil {
+			logger.Error("Failed clearing firewall rules after failing to apply network peers", logger.Ctx{"network_name": networkName, "err": err})
+		}
+	})
+
+	for _, ipVersion := range []uint{4, 6} {
+		// Skip IP families the host doesn't support.
+		if ipVersion == 6 && !shared.PathExists("/proc/sys/net/ipv6") {
+			continue
+		}
+
+		err := d.iptablesChainCreate(ipVersion, "filter", chain)
+		if err != nil {
+			return err
+		}
+
+		// The peer chain only returns or drops, so that traffic allowed between the peer subnets still goes
+		// through the ACL and forwarding policy rules of the network.
+		err = d.iptablesPrepend(ipVersion, comment, "filter", "FORWARD", "-i", networkName, "-j", chain)
+		if err != nil {
+			return err
+		}
+
+		for _, peer := range peers {
+			for _, localSubnet := range peer.LocalSubnets {
+				if (localSubnet.IP.To4() == nil) != (ipVersion == 6) {
+					continue
+				}
+
+				for _, targetSubnet := range peer.TargetSubnets {
+					if (targetSubnet.IP.To4() == nil) != (ipVersion == 6) {
+						continue
+					}
+
+					err = d.iptablesAppend(ipVersion, comment, "filter", chain, "-o", peer.TargetNetwork, "--source", localSubnet.String(), "--destination", targetSubnet.String(), "-j", "RETURN")
+					if err != nil {
+						return err
+					}
+
+					// Returning from the built-in chain skips the masquerade rule of the network.
+					err = d.iptablesPrepend(ipVersion, comment, "nat", "POSTROUTING", "-o", peer.TargetNetwork, "--source", localSubnet.String(), "--destination", targetSubnet.String(), "-j", "RETURN")
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			err = d.iptablesAppend(ipVersion, comment, "filter", chain, "-o", peer.TargetNetwork, "-j", "DROP")
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
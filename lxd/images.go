@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"crypto"
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/hex"
@@ -61,6 +62,7 @@ import (
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/osarch"
 	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/trust"
 	"github.com/canonical/lxd/shared/validate"
 	"github.com/canonical/lxd/shared/version"
 )
@@ -720,9 +722,41 @@ func imgPostInstanceInfo(s *state.State, req api.ImagesPost, op *operations.Oper
 	info.Architecture, _ = osarch.ArchitectureName(c.Architecture())
 	info.Properties = meta.Properties
 
+	// Sign the image with the server key if requested.
+	if req.Sign {
+		key, ok := s.ServerCert().KeyPair().PrivateKey.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("Server key can't be used to sign images")
+		}
+
+		signature, err := trust.SignImage(info.Fingerprint, key)
+		if err != nil {
+			return nil, err
+		}
+
+		info.Signature, err = imageSignatureCheck(s.GlobalConfig.ImagesSignatureTrustedKeys(), "", info.Fingerprint, &api.ImageSignature{Signature: signature}, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Create the database entry
-		return tx.CreateImage(ctx, imageProject, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, nil)
+		err := tx.CreateImage(ctx, imageProject, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, nil)
+		if err != nil {
+			return err
+		}
+
+		if info.Signature == nil {
+			return nil
+		}
+
+		id, _, err := tx.GetImage(ctx, info.Fingerprint, dbCluster.ImageFilter{Project: &imageProject})
+		if err != nil {
+			return err
+		}
+
+		return tx.CreateImageSignature(ctx, id, *info.Signature)
 	})
 	if err != nil {
 		return nil, err
@@ -1390,6 +1424,10 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("Image download from client-specified URL is not supported"))
 	}
 
+	if !imageUpload && req.Sign && req.Source.Type == api.SourceTypeImage {
+		return response.BadRequest(errors.New("Only images created from instances can be signed"))
+	}
+
 	if !imageUpload && req.Source.Mode == "push" {
 		metadata := map[string]any{
 			"aliases":    req.Aliases,
@@ -1442,6 +1480,46 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 
 	isClusterNotification := requestor.IsClusterNotification()
 
+	// Image copies from a remote server are durable operations, so that they are restarted on another cluster member
+	// if this one fails. In-cluster image copies are driven by the cluster member that requested them.
+	if !imageUpload && req.Source.Type == api.SourceTypeImage && !isClusterNotification {
+		// Project to associate profiles with.
+		profileProject := dbProject.Name
+
+		// If "features.profiles" is disabled for the project, associate the profiles with the "default" project.
+		if shared.IsFalseOrEmpty(projectConfig["features.profiles"]) {
+			profileProject = api.ProjectDefaultName
+		}
+
+		args := operations.OperationArgs{
+			ProjectName: dbProject.Name,
+			EntityURL:   api.NewURL().Path(version.APIVersion, "projects", dbProject.Name),
+			Type:        operationtype.ImageDownload,
+			Class:       operationtype.OperationClassDurable,
+		}
+
+		inputs := map[operations.InputKey]any{
+			operationInputKeyImageDownloadRequest:        req,
+			operationInputKeyImageDownloadImageProject:   imageProject,
+			operationInputKeyImageDownloadProfileProject: profileProject,
+			operationInputKeyImageDownloadBudget:         budget,
+		}
+
+		for key, value := range inputs {
+			err = args.SetInputValue(key, value)
+			if err != nil {
+				return response.InternalError(err)
+			}
+		}
+
+		imageOp, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+		if err != nil {
+			return response.InternalError(err)
+		}
+
+		return response.OperationResponse(imageOp)
+	}
+
 	// Begin background operation
 	run := func(ctx context.Context, op *operations.Operation) error {
 		var err error
@@ -1524,43 +1602,7 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-			imgID, _, err := tx.GetImageByFingerprintPrefix(ctx, info.Fingerprint, dbCluster.ImageFilter{Project: &dbProject.Name})
-			if err != nil {
-				return fmt.Errorf("Fetch image %q: %w", info.Fingerprint, err)
-			}
-
-			for _, alias := range req.Aliases {
-				_, _, err := tx.GetImageAlias(ctx, dbProject.Name, alias.Name, true)
-				if !response.IsNotFoundError(err) {
-					if err != nil {
-						return fmt.Errorf("Fetch image alias %q: %w", alias.Name, err)
-					}
-
-					return fmt.Errorf("Alias already exists: %s", alias.Name)
-				}
-
-				err = tx.CreateImageAlias(ctx, dbProject.Name, alias.Name, imgID, alias.Description)
-				if err != nil {
-					return fmt.Errorf("Add new image alias to the database: %w", err)
-				}
-			}
-
-			return nil
-		})
-		if err != nil {
-			return err
-		}
-
-		// Sync the images between each node in the cluster on demand
-		err = imageSyncBetweenNodes(ctx, s, dbProject.Name, info.Fingerprint)
-		if err != nil {
-			return fmt.Errorf("Failed syncing image between nodes: %w", err)
-		}
-
-		s.Events.SendLifecycle(dbProject.Name, lifecycle.ImageCreated.Event(info.Fingerprint, dbProject.Name, op.EventLifecycleRequestor(), logger.Ctx{"type": info.Type}))
-
-		return nil
+		return imagesPostFinalize(ctx, s, op, dbProject.Name, info, req.Aliases, false)
 	}
 
 	var metadata map[string]any
@@ -1592,6 +1634,131 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 	return response.OperationResponse(imageOp)
 }
 
+func init() {
+	operations.RegisterDurableOperationRunHook(operationtype.ImageDownload, imageDownloadOperationRunHook)
+}
+
+const (
+	operationInputKeyImageDownloadRequest        operations.InputKey = "request"
+	operationInputKeyImageDownloadImageProject   operations.InputKey = "image_project"
+	operationInputKeyImageDownloadProfileProject operations.InputKey = "profile_project"
+	operationInputKeyImageDownloadBudget         operations.InputKey = "budget"
+)
+
+// imageDownloadMetadataStarted is set in the metadata of an image download operation once it started. It tells a
+// restarted operation that aliases pointing to the image may have been created by the interrupted attempt.
+const imageDownloadMetadataStarted = "download_started"
+
+// imageDownloadOperationRunHook is the run hook of the durable [operationtype.ImageDownload] operation copying an
+// image from a remote server. When it is restarted on another cluster member, an image that was already downloaded is
+// reused (or downloaded again if no online member holds it) and aliases already pointing to the image are kept.
+func imageDownloadOperationRunHook(ctx context.Context, op *operations.Operation) error {
+	s := op.State()
+	projectName := op.Project()
+
+	// Record that the download started, so that a restarted operation keeps the aliases it created rather than
+	// failing on a name conflict. This is only done on restart so that existing aliases are never reused by a new
+	// request.
+	metadata := op.Metadata()
+	resumed, _ := metadata[imageDownloadMetadataStarted].(bool)
+	if !resumed {
+		metadata[imageDownloadMetadataStarted] = true
+		err := op.UpdateMetadata(metadata)
+		if err != nil {
+			return fmt.Errorf("Failed updating operation metadata: %w", err)
+		}
+
+		err = op.Persist()
+		if err != nil {
+			return fmt.Errorf("Failed persisting operation metadata: %w", err)
+		}
+	}
+
+	req, err := operations.GetOperationInputValue[api.ImagesPost](op, operationInputKeyImageDownloadRequest)
+	if err != nil {
+		return err
+	}
+
+	imageProject, err := operations.GetOperationInputValue[string](op, operationInputKeyImageDownloadImageProject)
+	if err != nil {
+		return err
+	}
+
+	profileProject, err := operations.GetOperationInputValue[string](op, operationInputKeyImageDownloadProfileProject)
+	if err != nil {
+		return err
+	}
+
+	budget, err := operations.GetOperationInputValue[int64](op, operationInputKeyImageDownloadBudget)
+	if err != nil {
+		return err
+	}
+
+	info, err := imgPostRemoteInfo(ctx, s, req, op, profileProject, imageProject, budget, nil)
+
+	// Set the metadata if possible, even if there is an error
+	if info != nil {
+		_ = op.ExtendMetadata(map[string]any{
+			"fingerprint": info.Fingerprint,
+			"size":        strconv.FormatInt(info.Size, 10),
+		})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return imagesPostFinalize(ctx, s, op, projectName, info, req.Aliases, resumed)
+}
+
+// imagesPostFinalize creates the aliases of an image added by an images POST request, syncs the image between the
+// cluster members and sends the lifecycle event.
+// If resumed is true, aliases already pointing to the image are kept, as they were created by the interrupted attempt
+// of the durable operation.
+func imagesPostFinalize(ctx context.Context, s *state.State, op *operations.Operation, projectName string, info *api.Image, aliases []api.ImageAlias, resumed bool) error {
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		imgID, _, err := tx.GetImageByFingerprintPrefix(ctx, info.Fingerprint, dbCluster.ImageFilter{Project: &projectName})
+		if err != nil {
+			return fmt.Errorf("Fetch image %q: %w", info.Fingerprint, err)
+		}
+
+		for _, alias := range aliases {
+			_, entry, err := tx.GetImageAlias(ctx, projectName, alias.Name, true)
+			if !response.IsNotFoundError(err) {
+				if err != nil {
+					return fmt.Errorf("Fetch image alias %q: %w", alias.Name, err)
+				}
+
+				if resumed && entry.Target == info.Fingerprint {
+					continue
+				}
+
+				return fmt.Errorf("Alias already exists: %s", alias.Name)
+			}
+
+			err = tx.CreateImageAlias(ctx, projectName, alias.Name, imgID, alias.Description)
+			if err != nil {
+				return fmt.Errorf("Add new image alias to the database: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Sync the images between each node in the cluster on demand
+	err = imageSyncBetweenNodes(ctx, s, projectName, info.Fingerprint)
+	if err != nil {
+		return fmt.Errorf("Failed syncing image between nodes: %w", err)
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.ImageCreated.Event(info.Fingerprint, projectName, op.EventLifecycleRequestor(), logger.Ctx{"type": info.Type}))
+
+	return nil
+}
+
 func getImageMetadata(fname string) (*api.ImageMetadata, string, error) {
 	var tr *tar.Reader
 	var result api.ImageMetadata
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -153,6 +154,23 @@ func instanceCreateFromImage(ctx context.Context, s *state.State, img *api.Image
 		}
 	}
 
+	// Apply the runtime configuration of OCI images, unless already set by the instance or its profiles.
+	for k, v := range img.Properties {
+		key := k
+		if strings.HasPrefix(k, "oci.env.") {
+			key = "environment." + strings.TrimPrefix(k, "oci.env.")
+		} else if k != "oci.entrypoint" && k != "oci.cwd" {
+			continue
+		}
+
+		_, found := args.Config[key]
+		if found || slices.ContainsFunc(args.Profiles, func(p api.Profile) bool { return p.Config[key] != "" }) {
+			continue
+		}
+
+		args.Config[key] = v
+	}
+
 	// Set the BaseImage field (regardless of previous value).
 	args.BaseImage = img.Fingerprint
 
@@ -605,12 +623,33 @@ func pruneExpiredAndAutoCreateInstanceSnapshots(ctx context.Context, s *state.St
 		}
 
 		if len(expiredSnaps) > 0 {
+			// Snapshots used by replicators as the base of the next incremental transfer must be kept,
+			// otherwise the next replicator run would have to send the whole instance again.
+			replicatorInstances, err := dbCluster.GetReplicatorInstances(ctx, tx.Tx(), nil)
+			if err != nil {
+				return fmt.Errorf("Failed loading replicator checkpoints: %w", err)
+			}
+
+			replicatorBaseSnapshots := make(map[string]bool, len(replicatorInstances))
+			for _, replicatorInstance := range replicatorInstances {
+				if replicatorInstance.Row.SnapshotName == "" {
+					continue
+				}
+
+				replicatorBaseSnapshots[replicatorInstance.ProjectName+"/"+replicatorInstance.InstanceName+"/"+replicatorInstance.Row.SnapshotName] = true
+			}
+
 			expiredSnapshots := make([]dbCluster.Instance, 0, len(expiredSnaps))
 			parents := make(map[string]*dbCluster.Instance, 0)
 
 			// Enrich expired snapshot list with info from parent (opportunistically loading
 			// the parent info from the DB if not already loaded).
 			for _, snapshot := range expiredSnaps {
+				if replicatorBaseSnapshots[snapshot.Project+"/"+snapshot.Instance+"/"+snapshot.Name] {
+					logger.Debug("Skipping expiry of instance snapshot used as replicator base", logger.Ctx{"instance": snapshot.Instance, "project": snapshot.Project, "snapshot": snapshot.Name})
+					continue
+				}
+
 				parentInstanceKey := snapshot.Project + "/" + snapshot.Instance
 				parent, ok := parents[parentInstanceKey]
 				if !ok {
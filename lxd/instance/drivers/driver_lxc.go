@@ -28,6 +28,7 @@ import (
 
 	"github.com/flosch/pongo2"
 	"github.com/google/uuid"
+	"github.com/kballard/go-shellquote"
 	liblxc "github.com/lxc/go-lxc"
 	"github.com/pkg/sftp"
 	yaml "go.yaml.in/yaml/v2"
@@ -89,6 +90,29 @@ func lxcSetConfigItem(c *liblxc.Container, key string, value string) error {
 	return nil
 }
 
+// lxcInitCommand converts a shell-quoted command to the format of lxc.init.cmd, where arguments are separated by
+// spaces and quoted with single or double quotes without any escaping.
+func lxcInitCommand(command string) (string, error) {
+	args, err := shellquote.Split(command)
+	if err != nil {
+		return "", fmt.Errorf("Invalid init command %q: %w", command, err)
+	}
+
+	quotedArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		switch {
+		case !strings.Contains(arg, "'"):
+			quotedArgs = append(quotedArgs, "'"+arg+"'")
+		case !strings.Contains(arg, `"`):
+			quotedArgs = append(quotedArgs, `"`+arg+`"`)
+		default:
+			return "", fmt.Errorf("Init command argument %q cannot contain both single and double quotes", arg)
+		}
+	}
+
+	return strings.Join(quotedArgs, " "), nil
+}
+
 func lxcStatusCode(state liblxc.State) api.StatusCode {
 	return map[int]api.StatusCode{
 		1: api.Stopped,
@@ -985,6 +1009,26 @@ func (d *lxc) initLXC(config bool) (*liblxc.Container, error) {
 		}
 	}
 
+	// Setup the init command of containers created from OCI images
+	if d.expandedConfig["oci.entrypoint"] != "" {
+		initCmd, err := lxcInitCommand(d.expandedConfig["oci.entrypoint"])
+		if err != nil {
+			return nil, err
+		}
+
+		err = lxcSetConfigItem(cc, "lxc.init.cmd", initCmd)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if d.expandedConfig["oci.cwd"] != "" {
+		err = lxcSetConfigItem(cc, "lxc.init.cwd", d.expandedConfig["oci.cwd"])
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for k, v := range d.expandedConfig {
 		// Setup environment
 		environmentKey, found := strings.CutPrefix(k, "environment.")
@@ -4688,7 +4688,7 @@ func (d *qemu) addDriveConfig(busAllocate busAllocator, bootIndexes map[string]i
 				return errors.New("Failed getting QEMU device id")
 			}
 
-			err = m.SetBlockThrottle(qemuDevID, int(driveConf.Limits.ReadBytes), int(driveConf.Limits.WriteBytes), int(driveConf.Limits.ReadIOps), int(driveConf.Limits.WriteIOps))
+			err = m.SetBlockThrottle(qemuDevID, driveConf.Limits.Group, int(driveConf.Limits.ReadBytes), int(driveConf.Limits.WriteBytes), int(driveConf.Limits.ReadIOps), int(driveConf.Limits.WriteIOps))
 			if err != nil {
 				return fmt.Errorf("Failed applying limits for disk device %q: %w", driveConf.DevName, err)
 			}
@@ -5612,6 +5612,58 @@ func (d *qemu) Unfreeze(ctx context.Context) error {
 	return nil
 }
 
+// FreezeFilesystems asks the lxd-agent to freeze the guest filesystems listed in agent.freeze_mounts, so that a
+// consistent snapshot of its disks can be taken. This is a noop if no filesystems are listed.
+// The guest thaws its filesystems by itself if UnfreezeFilesystems isn't called in time.
+func (d *qemu) FreezeFilesystems(ctx context.Context) error {
+	mountPoints := shared.SplitNTrimSpace(d.expandedConfig["agent.freeze_mounts"], ",", -1, true)
+	if len(mountPoints) == 0 {
+		return nil
+	}
+
+	return d.agentFilesystemsFreeze(ctx, http.MethodPost, agentAPI.FilesystemsFreezePost{MountPoints: mountPoints})
+}
+
+// UnfreezeFilesystems asks the lxd-agent to thaw the filesystems frozen by FreezeFilesystems.
+func (d *qemu) UnfreezeFilesystems(ctx context.Context) error {
+	if d.expandedConfig["agent.freeze_mounts"] == "" {
+		return nil
+	}
+
+	return d.agentFilesystemsFreeze(ctx, http.MethodDelete, nil)
+}
+
+// agentFilesystemsFreeze sends a request with the given method and body to the filesystem freeze endpoint of the
+// lxd-agent.
+func (d *qemu) agentFilesystemsFreeze(ctx context.Context, method string, data any) error {
+	if !d.IsRunning() {
+		return errors.New("Instance is not running")
+	}
+
+	client, err := d.getAgentClient()
+	if err != nil {
+		return err
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, agentConnectTimeout)
+	defer cancel()
+
+	agent, err := lxd.ConnectLXDHTTPWithContext(connectCtx, nil, client)
+	if err != nil {
+		d.logger.Error("Failed connecting to lxd-agent", logger.Ctx{"err": err})
+		return errors.New("Failed connecting to lxd-agent")
+	}
+
+	defer agent.Disconnect()
+
+	_, _, err = agent.RawQuery(method, "/1.0/filesystems/freeze", data, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // IsPrivileged does not apply to virtual machines. Always returns false.
 func (d *qemu) IsPrivileged() bool {
 	return false
@@ -8726,7 +8778,7 @@ func (d *qemu) DeviceEventHandler(runConf *deviceConfig.RunConfig) error {
 		devID := qemuDeviceIDPrefix + filesystem.PathNameEncode(mount.DevName)
 
 		// Apply the limits.
-		err = m.SetBlockThrottle(devID, int(mount.Limits.ReadBytes), int(mount.Limits.WriteBytes), int(mount.Limits.ReadIOps), int(mount.Limits.WriteIOps))
+		err = m.SetBlockThrottle(devID, mount.Limits.Group, int(mount.Limits.ReadBytes), int(mount.Limits.WriteBytes), int(mount.Limits.ReadIOps), int(mount.Limits.WriteIOps))
 		if err != nil {
 			return fmt.Errorf("Failed applying limits for disk device %q: %w", mount.DevName, err)
 		}
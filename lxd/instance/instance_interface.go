@@ -212,6 +212,10 @@ type VM interface {
 
 	FirmwarePath() string
 
+	// Guest filesystems freezing through the lxd-agent.
+	FreezeFilesystems(ctx context.Context) error
+	UnfreezeFilesystems(ctx context.Context) error
+
 	// UEFI vars handling.
 	UEFIVars() (*api.InstanceUEFIVars, error)
 	UEFIVarsUpdate(newUEFIVarsSet api.InstanceUEFIVars) error
@@ -624,6 +624,20 @@ func SuitableArchitectures(ctx context.Context, s *state.State, tx *db.ClusterTx
 				if err != nil {
 					return nil, err
 				}
+			} else if req.Source.Protocol == "oci" {
+				// Remote OCI registry.
+				remote, err = lxd.ConnectOCI(req.Source.Server, &lxd.ConnectionArgs{
+					TLSServerCert: req.Source.Certificate,
+					UserAgent:     version.UserAgent,
+					Proxy:         s.Proxy,
+					CachePath:     s.OS.CacheDir,
+					CacheExpiry:   time.Hour,
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				defer remote.Disconnect()
 			} else {
 				return nil, api.StatusErrorf(http.StatusBadRequest, "Unsupported remote image server protocol %q", req.Source.Protocol)
 			}
@@ -3,10 +3,13 @@ package instancetype
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/kballard/go-shellquote"
+
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/units"
@@ -93,6 +96,65 @@ var HugePageSizeSuffix = [...]string{"64KB", "1MB", "2MB", "1GB"}
 
 // InstanceConfigKeysAny is a map of config key to validator. (keys applying to containers AND virtual machines).
 var InstanceConfigKeysAny = map[string]func(value string) error{
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic backups.
+	//
+	// See {ref}`instances-backup-scheduled` for more information.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Schedule for automatic instance backups
+	"backups.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.expiry)
+	// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: Time until scheduled backups are deleted
+	"backups.expiry": func(value string) error {
+		// Validate expression
+		_, err := shared.GetExpiry(time.Time{}, value)
+		return err
+	},
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.retain)
+	// When more scheduled backups exist, the oldest ones are deleted after each scheduled backup.
+	// Backups that were created manually are never deleted.
+	// ---
+	//  type: integer
+	//  defaultdesc: `0` (unlimited)
+	//  liveupdate: no
+	//  shortdesc: Number of scheduled backups to keep
+	"backups.retain": validate.Optional(validate.IsUint32),
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.optimized)
+	// Optimized backups use the storage driver's native format, which is faster to create and restore but can only be restored on a storage pool using the same driver.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  shortdesc: Whether scheduled backups use the storage driver's optimized format
+	"backups.optimized": validate.Optional(validate.IsBool),
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.compression)
+	// Specify the compression algorithm, for example `gzip` or `zstd`, or `none` to disable compression.
+	// ---
+	//  type: string
+	//  defaultdesc: same as `backups.compression_algorithm` of the project or the server
+	//  liveupdate: no
+	//  shortdesc: Compression algorithm for scheduled backups
+	"backups.compression": validate.Optional(validate.IsCompressionAlgorithm),
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.target)
+	// Set this option to upload scheduled backups to a {ref}`backup target <instances-backup-target>` instead of storing them on the server.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: Backup target to upload scheduled backups to
+	"backups.target": validate.IsAny,
+
 	// lxdmeta:generate(entities=instance; group=boot; key=boot.autostart)
 	// If set to `true`, the instance will always be auto-started, unless `security.protection.start` is also enabled.
 	// If set to `false`, the instance will not be started on LXD start up.
@@ -691,6 +753,38 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 	//  shortdesc: Percentage of memory to have in sync before stopping the instance
 	"migration.incremental.memory.goal": validate.Optional(validate.IsUint32),
 
+	// lxdmeta:generate(entities=instance; group=miscellaneous; key=oci.cwd)
+	// This option is set from the working directory of the image when creating a container from an OCI image.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: container
+	//  shortdesc: Working directory of the container init command
+	"oci.cwd": validate.Optional(validate.IsAbsFilePath),
+
+	// lxdmeta:generate(entities=instance; group=miscellaneous; key=oci.entrypoint)
+	// The command is run as the init process of the container instead of `/sbin/init`.
+	// Arguments are separated by spaces and can be quoted as in a shell.
+	//
+	// This option is set from the entrypoint and command of the image when creating a container from an OCI image.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: container
+	//  shortdesc: Command to run as the container init process
+	"oci.entrypoint": validate.Optional(func(value string) error {
+		args, err := shellquote.Split(value)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			return errors.New("The entrypoint must not be empty")
+		}
+
+		return nil
+	}),
+
 	// Caller is responsible for full validation of any raw.* value.
 
 	// lxdmeta:generate(entities=instance; group=raw; key=raw.lxc)
@@ -1159,6 +1253,29 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to use the name and MTU of the default network interfaces
 	"agent.nic_config": validate.Optional(validate.IsBool),
 
+	// lxdmeta:generate(entities=instance; group=miscellaneous; key=agent.freeze_mounts)
+	// Specify a comma-separated list of mount points inside the virtual machine, for example, `/srv/data`.
+	// The `lxd-agent` freezes these filesystems while a consistent snapshot of the instance is taken, for example,
+	// by a replicator with {config:option}`replicator-conf:live.snapshot` set to `frozen`.
+	// The root filesystem can't be frozen, as the `lxd-agent` runs from it.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Guest filesystems to freeze for consistent snapshots
+	"agent.freeze_mounts": validate.Optional(validate.IsListOf(func(value string) error {
+		err := validate.IsAbsFilePath(value)
+		if err != nil {
+			return err
+		}
+
+		if filepath.Clean(value) == "/" {
+			return errors.New("The root filesystem cannot be frozen")
+		}
+
+		return nil
+	})),
+
 	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.apply_nvram)
 	//
 	// ---
@@ -22,6 +22,7 @@ import (
 	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	storagePools "github.com/canonical/lxd/lxd/storage"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
@@ -292,6 +293,19 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	if req.Target != "" {
+		// Check that the backup target exists.
+		_, err = backup.LoadTarget(r.Context(), s, req.Target)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Backups uploaded to a backup target aren't tracked by the server, so name them after their creation time.
+		if req.Name == "" {
+			req.Name = "backup-" + time.Now().UTC().Format("20060102-150405")
+		}
+	}
+
 	if req.Name == "" {
 		// come up with a name.
 		backups, err := inst.Backups()
@@ -340,42 +354,60 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
-	fullName := name + shared.SnapshotDelimiter + backupName
-	// We keep the req.ContainerOnly for backward compatibility.
-	instanceOnly := req.InstanceOnly || req.ContainerOnly //nolint:staticcheck,unused
-
-	backup := func(ctx context.Context, op *operations.Operation) error {
-		args := db.InstanceBackup{
-			Name:                 fullName,
-			InstanceID:           inst.ID(),
-			CreationDate:         time.Now(),
-			ExpiryDate:           req.ExpiresAt,
-			InstanceOnly:         instanceOnly,
-			OptimizedStorage:     req.OptimizedStorage,
-			CompressionAlgorithm: req.CompressionAlgorithm,
+	// The operation is durable and its run hook only gets the instance name and the request, so record the
+	// validated backup name in the request. We keep the req.ContainerOnly for backward compatibility.
+	req.Name = backupName
+	req.InstanceOnly = req.InstanceOnly || req.ContainerOnly //nolint:staticcheck,unused
+
+	if req.ParentSnapshot != "" {
+		// Incremental backups only contain the volume data written since their parent snapshot.
+		if !req.OptimizedStorage || req.InstanceOnly {
+			return response.BadRequest(errors.New("Incremental backups must be optimized storage backups including snapshots"))
 		}
 
-		err := backupCreate(ctx, s, args, inst, req.Version, op)
+		pool, err := storagePools.LoadByInstance(s, inst)
 		if err != nil {
-			return fmt.Errorf("Create backup: %w", err)
+			return response.SmartError(err)
 		}
 
-		return nil
+		if !pool.Driver().Info().IncrementalBackups {
+			return response.BadRequest(fmt.Errorf("Storage pool driver %q doesn't support incremental backups", pool.Driver().Info().Name))
+		}
+
+		_, err = instance.LoadByProjectAndName(s, projectName, name+shared.SnapshotDelimiter+req.ParentSnapshot)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed loading parent snapshot %q: %w", req.ParentSnapshot, err))
+		}
 	}
 
 	metadata := map[string]any{
 		api.MetadataEntityURL: api.NewURL().Path(version.APIVersion, "instances", name, "backups", backupName).Project(inst.Project().Name).String(),
 	}
 
+	if req.Target != "" {
+		metadata[api.MetadataEntityURL] = api.NewURL().Path(version.APIVersion, "instances", name).Project(inst.Project().Name).String()
+		metadata["backup_target"] = req.Target
+		metadata["backup_key"] = backup.InstanceBackupKey(name, backupName)
+	}
+
 	args := operations.OperationArgs{
 		ProjectName: projectName,
 		EntityURL:   api.NewURL().Path(version.APIVersion, "instances", name).Project(projectName),
 		Type:        operationtype.BackupCreate,
-		Class:       operationtype.OperationClassTask,
-		RunHook:     backup,
+		Class:       operationtype.OperationClassDurable,
 		Metadata:    metadata,
 	}
 
+	err = args.SetInputValue(operationInputKeyBackupCreateInstance, name)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = args.SetInputValue(operationInputKeyBackupCreateRequest, req)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
 	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
 	if err != nil {
 		return response.InternalError(err)
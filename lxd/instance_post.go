@@ -11,7 +11,9 @@ import (
 	"net/http"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/cluster"
 	"github.com/canonical/lxd/lxd/db"
@@ -28,6 +30,7 @@ import (
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/version"
 )
 
@@ -399,6 +402,36 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 				finalName = inst.Name()
 			}
 
+			instanceURL := api.NewURL().Path(version.APIVersion, "instances", name).Project(projectName)
+
+			// Moves between pools or projects on this member are durable operations, so that an interrupted move
+			// is completed or cleaned up rather than leaving a partial copy behind.
+			if !needsClusterMove {
+				args := operations.OperationArgs{
+					ProjectName: projectName,
+					EntityURL:   instanceURL,
+					Type:        operationtype.InstanceMigrate,
+					Class:       operationtype.OperationClassDurable,
+				}
+
+				err = args.SetInputValue(operationInputKeyInstanceMoveInstance, name)
+				if err != nil {
+					return response.InternalError(err)
+				}
+
+				err = args.SetInputValue(operationInputKeyInstanceMoveRequest, req)
+				if err != nil {
+					return response.InternalError(err)
+				}
+
+				op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+				if err != nil {
+					return response.InternalError(err)
+				}
+
+				return response.OperationResponse(op)
+			}
+
 			// Setup the instance move operation.
 			run := func(ctx context.Context, op *operations.Operation) error {
 				currentInst := inst
@@ -430,7 +463,6 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 				return nil
 			}
 
-			instanceURL := api.NewURL().Path(version.APIVersion, "instances", name).Project(projectName)
 			args := operations.OperationArgs{
 				ProjectName: projectName,
 				EntityURL:   instanceURL,
@@ -693,7 +725,21 @@ func instancePostMigration(ctx context.Context, s *state.State, inst instance.In
 		}
 
 		statefulStart = true
-		err := inst.Stop(ctx, true)
+
+		// Record that the moved instance must be started, in case the operation is restarted once stopped.
+		if op != nil && op.Class() == operationtype.OperationClassDurable {
+			err = op.ExtendMetadata(map[string]any{instanceMoveMetadataStart: true})
+			if err != nil {
+				return fmt.Errorf("Failed updating operation metadata: %w", err)
+			}
+
+			err = op.Persist()
+			if err != nil {
+				return fmt.Errorf("Failed persisting operation metadata: %w", err)
+			}
+		}
+
+		err = inst.Stop(ctx, true)
 		if err != nil {
 			return err
 		}
@@ -755,6 +801,297 @@ func instancePostMigration(ctx context.Context, s *state.State, inst instance.In
 	return nil
 }
 
+func init() {
+	operations.RegisterDurableOperationRunHook(operationtype.InstanceMigrate, instanceMoveOperationRunHook)
+}
+
+const (
+	operationInputKeyInstanceMoveInstance operations.InputKey = "instance"
+	operationInputKeyInstanceMoveRequest  operations.InputKey = "request"
+)
+
+const (
+	// instanceMoveMetadataStarted is set in the metadata of an instance move operation once it started moving the
+	// instance. It tells a restarted operation that the copy of the instance was left over by the interrupted attempt.
+	instanceMoveMetadataStarted = "move_started"
+
+	// instanceMoveMetadataCopyName records the name of the copy created by an instance move operation.
+	instanceMoveMetadataCopyName = "move_copy_name"
+
+	// instanceMoveMetadataStart is set once an instance move operation stopped the running instance, so that a
+	// restarted operation starts the moved instance.
+	instanceMoveMetadataStart = "move_start"
+)
+
+// instanceMemberRetryInterval is the interval at which a restarted instance move or copy operation checks whether
+// the cluster member hosting the instance is back online.
+const instanceMemberRetryInterval = 10 * time.Second
+
+// instanceMemberWaitTimeout is how long a restarted instance move or copy operation waits for the cluster member
+// hosting the instance to be back online before failing.
+const instanceMemberWaitTimeout = 30 * time.Minute
+
+// instanceMoveOperationRunHook is the run hook of the durable [operationtype.InstanceMigrate] operation, used to
+// move an instance between pools or projects on the cluster member hosting it.
+// A restarted operation removes the copy left over by the interrupted attempt if the original instance still exists,
+// and otherwise completes the move by renaming the copy. If the operation is restarted on another cluster member, the
+// member hosting the instance carries out the move once it is back online.
+func instanceMoveOperationRunHook(ctx context.Context, op *operations.Operation) error {
+	s := op.State()
+
+	name, err := operations.GetOperationInputValue[string](op, operationInputKeyInstanceMoveInstance)
+	if err != nil {
+		return err
+	}
+
+	req, err := operations.GetOperationInputValue[api.InstancePost](op, operationInputKeyInstanceMoveRequest)
+	if err != nil {
+		return err
+	}
+
+	projectName := op.Project()
+
+	targetProject := req.Project
+	if targetProject == "" {
+		targetProject = projectName
+	}
+
+	finalName := req.Name
+	if finalName == "" {
+		finalName = name
+	}
+
+	metadata := op.Metadata()
+	resumed, _ := metadata[instanceMoveMetadataStarted].(bool)
+	copyName, _ := metadata[instanceMoveMetadataCopyName].(string)
+	start, _ := metadata[instanceMoveMetadataStart].(bool)
+	deadline := time.Now().Add(instanceMemberWaitTimeout)
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		// The original instance is only missing once the interrupted attempt deleted it, leaving the copy to be
+		// renamed and started.
+		if resumed && api.StatusErrorCheck(err, http.StatusNotFound) {
+			return instanceMoveFinish(ctx, s, op, targetProject, copyName, finalName, start, deadline)
+		}
+
+		return err
+	}
+
+	// Record the name of the copy before creating it, so that a restarted operation can find it.
+	if !resumed {
+		copyName = finalName
+		if finalName == name && targetProject == projectName {
+			copyName, err = instance.MoveTemporaryName(inst)
+			if err != nil {
+				return err
+			}
+		}
+
+		metadata[instanceMoveMetadataStarted] = true
+		metadata[instanceMoveMetadataCopyName] = copyName
+		err = op.UpdateMetadata(metadata)
+		if err != nil {
+			return fmt.Errorf("Failed updating operation metadata: %w", err)
+		}
+
+		err = op.Persist()
+		if err != nil {
+			return fmt.Errorf("Failed persisting operation metadata: %w", err)
+		}
+	}
+
+	client, err := instanceMemberConnect(ctx, s, inst, deadline)
+	if err != nil {
+		return err
+	}
+
+	if resumed {
+		err = instanceMoveRemoveCopy(ctx, s, op, client, inst, targetProject, copyName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if client != nil {
+		moveOp, err := client.MigrateInstance(name, req)
+		if err == nil {
+			err = moveOp.WaitContext(ctx)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed moving instance %q on cluster member %q: %w", name, inst.Location(), err)
+		}
+	} else {
+		err = instancePostMigration(ctx, s, inst, req, nil, "", op)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !start {
+		return nil
+	}
+
+	return instanceMoveStart(ctx, s, op, targetProject, finalName, deadline)
+}
+
+// instanceMemberConnect returns a client for the cluster member hosting the instance, or nil if the instance is hosted
+// by this member. It waits for the member to be back online for up to the deadline.
+func instanceMemberConnect(ctx context.Context, s *state.State, inst instance.Instance, deadline time.Time) (lxd.InstanceServer, error) {
+	if !s.ServerClustered || inst.Location() == s.ServerName {
+		return nil, nil
+	}
+
+	for {
+		var member db.NodeInfo
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+			member, err = tx.GetNodeByName(ctx, inst.Location())
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading cluster member %q: %w", inst.Location(), err)
+		}
+
+		if !member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			client, err := cluster.Connect(ctx, member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+			if err != nil {
+				return nil, fmt.Errorf("Failed connecting to cluster member %q: %w", member.Name, err)
+			}
+
+			return client.UseProject(inst.Project().Name), nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Cluster member %q hosting instance %q is still offline after %s", inst.Location(), inst.Name(), instanceMemberWaitTimeout)
+		}
+
+		logger.Warn("Waiting for cluster member hosting the instance", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "member": inst.Location()})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(instanceMemberRetryInterval):
+		}
+	}
+}
+
+// instanceMoveRemoveCopy removes the copy of the instance left over by an interrupted move, after pointing the
+// permissions that were moved to the copy back to the original instance.
+func instanceMoveRemoveCopy(ctx context.Context, s *state.State, op *operations.Operation, client lxd.InstanceServer, inst instance.Instance, projectName string, copyName string) error {
+	leftover, err := instance.LoadByProjectAndName(s, projectName, copyName)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		q := `UPDATE auth_groups_permissions SET entity_id = ? WHERE entity_type = ? AND entity_id = ?`
+		_, err := tx.Tx().ExecContext(ctx, q, inst.ID(), dbCluster.EntityType(entity.TypeInstance), leftover.ID())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed restoring instance permissions: %w", err)
+	}
+
+	logger.Warn("Removing instance copy left over by interrupted move", logger.Ctx{"project": projectName, "instance": inst.Name(), "copy": copyName})
+
+	if client != nil {
+		deleteOp, err := client.UseProject(projectName).DeleteInstance(copyName, true)
+		if err == nil {
+			err = deleteOp.WaitContext(ctx)
+		}
+	} else {
+		err = leftover.Delete(ctx, true, "", op)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed removing leftover instance copy %q: %w", copyName, err)
+	}
+
+	return nil
+}
+
+// instanceMoveFinish completes a move that was interrupted after the original instance was deleted, by renaming the
+// copy to its final name and starting it if needed.
+func instanceMoveFinish(ctx context.Context, s *state.State, op *operations.Operation, projectName string, copyName string, name string, start bool, deadline time.Time) error {
+	if copyName != "" && copyName != name {
+		inst, err := instance.LoadByProjectAndName(s, projectName, copyName)
+		if err == nil {
+			client, err := instanceMemberConnect(ctx, s, inst, deadline)
+			if err != nil {
+				return err
+			}
+
+			if client != nil {
+				renameOp, err := client.RenameInstance(copyName, api.InstancePost{Name: name})
+				if err == nil {
+					err = renameOp.WaitContext(ctx)
+				}
+			} else {
+				err = inst.Rename(ctx, name, false) // Don't apply templates when moving.
+			}
+
+			if err != nil {
+				return fmt.Errorf("Failed renaming instance copy %q: %w", copyName, err)
+			}
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+	}
+
+	if !start {
+		return nil
+	}
+
+	return instanceMoveStart(ctx, s, op, projectName, name, deadline)
+}
+
+// instanceMoveStart starts the moved instance unless it is already running.
+func instanceMoveStart(ctx context.Context, s *state.State, op *operations.Operation, projectName string, name string, deadline time.Time) error {
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return err
+	}
+
+	client, err := instanceMemberConnect(ctx, s, inst, deadline)
+	if err != nil {
+		return err
+	}
+
+	if client == nil {
+		if inst.IsRunning() {
+			return nil
+		}
+
+		return inst.Start(ctx, true, op)
+	}
+
+	instState, _, err := client.GetInstanceState(name)
+	if err != nil {
+		return err
+	}
+
+	if instState.StatusCode == api.Running {
+		return nil
+	}
+
+	startOp, err := client.UpdateInstanceState(name, api.InstanceStatePut{Action: "start", Stateful: true}, "")
+	if err == nil {
+		err = startOp.WaitContext(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed starting instance %q: %w", name, err)
+	}
+
+	return nil
+}
+
 // Migrate an instance to another cluster node (supports both local and remote storage).
 // Source and target members must be online.
 func instancePostClusteringMigrate(s *state.State, srcPool storagePools.Pool, srcInst instance.Instance, req api.InstancePost, targetArgs *db.InstanceArgs, srcMember db.NodeInfo, newMember db.NodeInfo, targetGroupName string) (func(ctx context.Context, op *operations.Operation) error, error) {
@@ -333,6 +333,10 @@ func instanceSnapshotsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Invalid snapshot name: %w", err))
 	}
 
+	if req.Name == replicatorLiveSnapshotName {
+		return response.BadRequest(fmt.Errorf("Snapshot name %q is reserved for replicators", req.Name))
+	}
+
 	snapshot := func(ctx context.Context, op *operations.Operation) error {
 		return inst.Snapshot(ctx, req.Name, req.ExpiresAt, req.Stateful, req.DiskVolumesMode, op)
 	}
@@ -739,6 +743,10 @@ func snapshotPost(s *state.State, r *http.Request, snapInst instance.Instance) r
 		return response.BadRequest(fmt.Errorf("Invalid snapshot name: %w", err))
 	}
 
+	if newName == replicatorLiveSnapshotName {
+		return response.BadRequest(fmt.Errorf("Snapshot name %q is reserved for replicators", newName))
+	}
+
 	fullName := parentName + shared.SnapshotDelimiter + newName
 
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
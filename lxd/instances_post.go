@@ -11,6 +11,7 @@ import (
 	"os"
 	"slices"
 	"strconv"
+	"time"
 
 	petname "github.com/dustinkirkland/golang-petname"
 	"github.com/google/uuid"
@@ -695,19 +696,7 @@ func createFromCopy(r *http.Request, s *state.State, projectName string, profile
 		return response.BadRequest(errors.New("Instance type should not be specified or should match source type"))
 	}
 
-	args := db.InstanceArgs{
-		Project:      targetProject,
-		Architecture: source.Architecture(),
-		BaseImage:    req.Source.BaseImage,
-		Config:       req.Config,
-		Type:         source.Type(),
-		Description:  req.Description,
-		Devices:      deviceConfig.NewDevices(req.Devices),
-		Ephemeral:    req.Ephemeral,
-		Name:         req.Name,
-		Profiles:     profiles,
-		Stateful:     req.Stateful,
-	}
+	args := instanceCopyArgs(source, targetProject, profiles, req)
 
 	// Define client here to allow reuse.
 	var targetClient lxd.InstanceServer
@@ -763,16 +752,7 @@ func createFromCopy(r *http.Request, s *state.State, projectName string, profile
 		}
 
 		// Actually create the instance.
-		targetInst, err := instanceCreateAsCopy(ctx, s, instanceCreateAsCopyOpts{
-			sourceInstance: source,
-			targetInstance: args,
-			// We keep the ContainerOnly for backward compatibility.
-			instanceOnly:             req.Source.InstanceOnly || req.Source.ContainerOnly, //nolint:staticcheck,unused
-			refresh:                  req.Source.Refresh,
-			applyTemplateTrigger:     true,
-			allowInconsistent:        req.Source.AllowInconsistent,
-			overrideSnapshotProfiles: req.Source.OverrideSnapshotProfiles,
-		}, op)
+		targetInst, err := instanceCopyLocal(ctx, s, source, args, req, op)
 		if err != nil {
 			return err
 		}
@@ -818,6 +798,25 @@ func createFromCopy(r *http.Request, s *state.State, projectName string, profile
 		},
 	}
 
+	// Copies that stay on this member are durable operations, so that an interrupted copy is redone rather than
+	// leaving a partial copy behind. Copies that are then moved to another member are driven by this member.
+	if !s.ServerClustered || targetMemberInfo == nil || targetMemberInfo.Name == s.ServerName {
+		opArgs.Class = operationtype.OperationClassDurable
+		opArgs.RunHook = nil
+
+		inputs := map[operations.InputKey]any{
+			operationInputKeyInstanceCopyRequest:  req,
+			operationInputKeyInstanceCopyProfiles: profiles,
+		}
+
+		for key, value := range inputs {
+			err = opArgs.SetInputValue(key, value)
+			if err != nil {
+				return response.InternalError(err)
+			}
+		}
+	}
+
 	op, err := operations.ScheduleUserOperationFromRequest(s, r, opArgs)
 	if err != nil {
 		return response.InternalError(err)
@@ -826,36 +825,203 @@ func createFromCopy(r *http.Request, s *state.State, projectName string, profile
 	return response.OperationResponse(op)
 }
 
-func createFromBackup(s *state.State, r *http.Request, projectName string, data io.Reader, pool string, instanceName string, devices map[string]map[string]string) response.Response {
+// instanceCopyArgs returns the arguments of the instance created as a copy of the source instance.
+func instanceCopyArgs(source instance.Instance, projectName string, profiles []api.Profile, req *api.InstancesPost) db.InstanceArgs {
+	return db.InstanceArgs{
+		Project:      projectName,
+		Architecture: source.Architecture(),
+		BaseImage:    req.Source.BaseImage,
+		Config:       req.Config,
+		Type:         source.Type(),
+		Description:  req.Description,
+		Devices:      deviceConfig.NewDevices(req.Devices),
+		Ephemeral:    req.Ephemeral,
+		Name:         req.Name,
+		Profiles:     profiles,
+		Stateful:     req.Stateful,
+	}
+}
+
+// instanceCopyLocal creates the instance as a copy of the source instance on this member.
+func instanceCopyLocal(ctx context.Context, s *state.State, source instance.Instance, args db.InstanceArgs, req *api.InstancesPost, op *operations.Operation) (instance.Instance, error) {
+	return instanceCreateAsCopy(ctx, s, instanceCreateAsCopyOpts{
+		sourceInstance: source,
+		targetInstance: args,
+		// We keep the ContainerOnly for backward compatibility.
+		instanceOnly:             req.Source.InstanceOnly || req.Source.ContainerOnly, //nolint:staticcheck,unused
+		refresh:                  req.Source.Refresh,
+		applyTemplateTrigger:     true,
+		allowInconsistent:        req.Source.AllowInconsistent,
+		overrideSnapshotProfiles: req.Source.OverrideSnapshotProfiles,
+	}, op)
+}
+
+func init() {
+	operations.RegisterDurableOperationRunHook(operationtype.InstanceCopy, instanceCopyOperationRunHook)
+	operations.RegisterDurableOperationRunHook(operationtype.SnapshotCopy, instanceCopyOperationRunHook)
+}
+
+const (
+	operationInputKeyInstanceCopyRequest  operations.InputKey = "request"
+	operationInputKeyInstanceCopyProfiles operations.InputKey = "profiles"
+)
+
+// instanceCopyMetadataStarted is set in the metadata of an instance copy operation once it started copying the
+// instance. It tells a restarted operation that the instance with the same name was left over by the interrupted
+// attempt.
+const instanceCopyMetadataStarted = "copy_started"
+
+// instanceCopyOperationRunHook is the run hook of the durable [operationtype.InstanceCopy] and
+// [operationtype.SnapshotCopy] operations, used for copies that stay on the cluster member running them.
+// A restarted operation removes the instance left over by the interrupted attempt before copying again, unless
+// refreshing an existing instance. If the operation is restarted on another cluster member and the source isn't on
+// remote storage, the copy is made by the member hosting the source once it is back online.
+func instanceCopyOperationRunHook(ctx context.Context, op *operations.Operation) error {
+	s := op.State()
+
+	req, err := operations.GetOperationInputValue[api.InstancesPost](op, operationInputKeyInstanceCopyRequest)
+	if err != nil {
+		return err
+	}
+
+	profiles, err := operations.GetOperationInputValue[[]api.Profile](op, operationInputKeyInstanceCopyProfiles)
+	if err != nil {
+		return err
+	}
+
+	// Record that the copy started, so that a restarted operation replaces the instance it left behind rather than
+	// failing on a name conflict. This is only done on restart so that existing instances are never replaced.
+	metadata := op.Metadata()
+	resumed, _ := metadata[instanceCopyMetadataStarted].(bool)
+	if !resumed {
+		metadata[instanceCopyMetadataStarted] = true
+		err = op.UpdateMetadata(metadata)
+		if err != nil {
+			return fmt.Errorf("Failed updating operation metadata: %w", err)
+		}
+
+		err = op.Persist()
+		if err != nil {
+			return fmt.Errorf("Failed persisting operation metadata: %w", err)
+		}
+	}
+
+	projectName := op.Project()
+
+	sourceProject := req.Source.Project
+	if sourceProject == "" {
+		sourceProject = projectName
+	}
+
+	source, err := instance.LoadByProjectAndName(s, sourceProject, req.Source.Source)
+	if err != nil {
+		return err
+	}
+
+	pool, err := storagePools.LoadByInstance(s, source)
+	if err != nil {
+		return err
+	}
+
+	// Copies on remote storage can be made by any member.
+	var client lxd.InstanceServer
+	if !pool.Driver().Info().Remote {
+		client, err = instanceMemberConnect(ctx, s, source, time.Now().Add(instanceMemberWaitTimeout))
+		if err != nil {
+			return err
+		}
+	}
+
+	if resumed && !req.Source.Refresh {
+		inst, err := instance.LoadByProjectAndName(s, projectName, req.Name)
+		if err == nil {
+			logger.Warn("Removing instance left over by interrupted copy", logger.Ctx{"project": projectName, "instance": req.Name})
+			if client != nil {
+				var deleteOp lxd.Operation
+				deleteOp, err = client.UseProject(projectName).DeleteInstance(req.Name, true)
+				if err == nil {
+					err = deleteOp.WaitContext(ctx)
+				}
+			} else {
+				err = inst.Delete(ctx, true, "", op)
+			}
+
+			if err != nil {
+				return fmt.Errorf("Failed removing leftover instance %q: %w", req.Name, err)
+			}
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+	}
+
+	if client != nil {
+		createOp, err := client.UseProject(projectName).UseTarget(source.Location()).CreateInstance(req)
+		if err == nil {
+			err = createOp.WaitContext(ctx)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed copying instance %q on cluster member %q: %w", req.Source.Source, source.Location(), err)
+		}
+
+		return nil
+	}
+
+	args := instanceCopyArgs(source, projectName, profiles, &req)
+	_, err = instanceCopyLocal(ctx, s, source, args, &req, op)
+	if err != nil {
+		return err
+	}
+
+	return instanceCreateFinish(ctx, s, &req, args, nil, op)
+}
+
+// createBackupFile stores backup data in a temporary file of the backups directory, verifying its checksum and
+// converting squashfs backups to tarballs, and returns it along with the function removing the temporary files.
+func createBackupFile(s *state.State, backupsPath string, data io.Reader) (*os.File, func(), error) {
 	revert := revert.New()
 	defer revert.Fail()
 
-	backupsPath := s.BackupsStoragePath(projectName)
+	var tmpPaths []string
+	cleanup := func() {
+		for _, path := range tmpPaths {
+			_ = os.Remove(path)
+		}
+	}
+
+	revert.Add(cleanup)
 
 	// Create temporary file to store uploaded backup data.
 	backupFile, err := os.CreateTemp(backupsPath, backup.WorkingDirPrefix+"_")
 	if err != nil {
-		return response.InternalError(err)
+		return nil, nil, err
 	}
 
-	defer func() { _ = os.Remove(backupFile.Name()) }()
+	tmpPaths = append(tmpPaths, backupFile.Name())
 	revert.Add(func() { _ = backupFile.Close() })
 
-	// Stream uploaded backup data into temporary file.
-	_, err = io.Copy(backupFile, data)
+	// Stream uploaded backup data into temporary file, verifying its checksum on the way.
+	verifier := backup.NewChecksumVerifier(s, backupFile.Name())
+	_, err = io.Copy(io.MultiWriter(backupFile, verifier), data)
 	if err != nil {
-		return response.InternalError(err)
+		_ = verifier.Close()
+		return nil, nil, err
+	}
+
+	err = verifier.Close()
+	if err != nil {
+		return nil, nil, api.StatusErrorf(http.StatusBadRequest, "%w", err)
 	}
 
 	// Detect squashfs compression and convert to tarball.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
-		return response.InternalError(err)
+		return nil, nil, err
 	}
 
 	_, algo, decomArgs, err := shared.DetectCompressionFile(backupFile)
 	if err != nil {
-		return response.InternalError(err)
+		return nil, nil, err
 	}
 
 	if algo == ".squashfs" {
@@ -865,15 +1031,16 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		// Create temporary file to store the decompressed tarball in.
 		tarFile, err := os.CreateTemp(backupsPath, backup.WorkingDirPrefix+"_decompress_")
 		if err != nil {
-			return response.InternalError(err)
+			return nil, nil, err
 		}
 
-		defer func() { _ = os.Remove(tarFile.Name()) }()
+		tmpPaths = append(tmpPaths, tarFile.Name())
+		revert.Add(func() { _ = tarFile.Close() })
 
 		// Decompress to tarFile temporary file.
 		err = archive.ExtractWithFds(s, decomArgs[0], decomArgs[1:], nil, nil, tarFile)
 		if err != nil {
-			return response.InternalError(err)
+			return nil, nil, err
 		}
 
 		// We don't need the original squashfs file anymore.
@@ -884,18 +1051,69 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		backupFile = tarFile
 	}
 
-	// Parse the backup information.
+	// Rewind the backup file for reading.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
-		return response.InternalError(err)
+		return nil, nil, err
+	}
+
+	revert.Success()
+	return backupFile, cleanup, nil
+}
+
+func createFromBackup(s *state.State, r *http.Request, projectName string, data io.Reader, parents []io.Reader, pool string, instanceName string, devices map[string]map[string]string) response.Response {
+	revert := revert.New()
+	defer revert.Fail()
+
+	backupsPath := s.BackupsStoragePath(projectName)
+
+	// Store the parent backups of incremental backups first as they precede the backup in uploaded data.
+	parentLinks := make([]backup.Link, 0, len(parents))
+	parentFiles := make([]*os.File, 0, len(parents))
+	var parentCleanups []func()
+	defer func() {
+		for _, cleanup := range parentCleanups {
+			cleanup()
+		}
+	}()
+
+	for _, parent := range parents {
+		parentFile, cleanup, err := createBackupFile(s, backupsPath, parent)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		parentCleanups = append(parentCleanups, cleanup)
+		parentFiles = append(parentFiles, parentFile)
+		revert.Add(func() { _ = parentFile.Close() })
+
+		parentInfo, err := backup.GetInfo(s, parentFile, parentFile.Name())
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Failed reading parent backup: %w", err))
+		}
+
+		parentLinks = append(parentLinks, backup.Link{Info: *parentInfo, Data: parentFile})
+	}
+
+	backupFile, cleanup, err := createBackupFile(s, backupsPath, data)
+	if err != nil {
+		return response.SmartError(err)
 	}
 
+	defer cleanup()
+	revert.Add(func() { _ = backupFile.Close() })
+
 	logger.Debug("Reading backup file info")
 	bInfo, err := backup.GetInfo(s, backupFile, backupFile.Name())
 	if err != nil {
 		return response.BadRequest(err)
 	}
 
+	err = bInfo.SetParents(parentLinks)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	if bInfo.Config == nil {
 		return response.BadRequest(errors.New("Backup config is missing"))
 	}
@@ -1083,6 +1301,12 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		defer func() { _ = backupFile.Close() }()
 		defer runRevert.Fail()
 
+		defer func() {
+			for _, parentFile := range parentFiles {
+				_ = parentFile.Close()
+			}
+		}()
+
 		pool, err := storagePools.LoadByName(s, bInfo.Pool)
 		if err != nil {
 			return err
@@ -1360,7 +1584,21 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		return createFromBackup(s, r, targetProjectName, r.Body, r.Header.Get("X-LXD-pool"), r.Header.Get("X-LXD-name"), deviceMap)
+		data, err := backupImportSource(r.Context(), s, r, targetProjectName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		defer func() { _ = data.Close() }()
+
+		parents, closeParents, err := backupImportParents(r.Context(), s, r, targetProjectName, data)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		defer closeParents()
+
+		return createFromBackup(s, r, targetProjectName, data, parents, r.Header.Get("X-LXD-pool"), r.Header.Get("X-LXD-name"), deviceMap)
 	}
 
 	// Parse the request
@@ -1822,7 +2060,7 @@ func instancesPostSelectClusterMember(ctx context.Context, tx *db.ClusterTx, pla
 
 	apiPlacementGroup := placementGroup.ToAPI(configs)
 
-	filteredCandidates, err := placement.Filter(ctx, tx, candidateMembers, *apiPlacementGroup, false)
+	filteredCandidates, err := placement.Filter(ctx, tx, candidateMembers, *apiPlacementGroup, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -4,10 +4,14 @@ package instancewriter
 
 import (
 	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/canonical/lxd/lxd/idmap"
 	"github.com/canonical/lxd/shared"
@@ -19,12 +23,14 @@ type InstanceTarWriter struct {
 	tarWriter *tar.Writer
 	idmapSet  *idmap.IdmapSet
 	linkMap   map[uint64]string
+	hash      hash.Hash
 }
 
 // NewInstanceTarWriter returns a ContainerTarWriter for the provided target Writer and id map.
 func NewInstanceTarWriter(writer io.Writer, idmapSet *idmap.IdmapSet) *InstanceTarWriter {
 	ctw := new(InstanceTarWriter)
-	ctw.tarWriter = tar.NewWriter(writer)
+	ctw.hash = sha256.New()
+	ctw.tarWriter = tar.NewWriter(io.MultiWriter(writer, ctw.hash))
 	ctw.idmapSet = idmapSet
 	ctw.linkMap = map[uint64]string{}
 	return ctw
@@ -189,6 +195,30 @@ func (ctw *InstanceTarWriter) WriteFileFromReader(src io.Reader, fi os.FileInfo)
 	return err
 }
 
+// WriteChecksum adds a trailer file with the specified name to the tarball, containing the hex encoded SHA-256
+// checksum of the tarball up to and including the trailer's header. It should be the last file written.
+func (ctw *InstanceTarWriter) WriteChecksum(name string) error {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0600,
+		Size:     int64(hex.EncodedLen(sha256.Size)),
+		ModTime:  time.Now(),
+	}
+
+	err := ctw.tarWriter.WriteHeader(hdr)
+	if err != nil {
+		return fmt.Errorf("Failed writing tar header: %w", err)
+	}
+
+	_, err = io.WriteString(ctw.tarWriter, hex.EncodeToString(ctw.hash.Sum(nil)))
+	if err != nil {
+		return fmt.Errorf("Failed writing checksum: %w", err)
+	}
+
+	return nil
+}
+
 // Close finishes writing the tarball.
 func (ctw *InstanceTarWriter) Close() error {
 	err := ctw.tarWriter.Close()
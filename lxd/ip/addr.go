@@ -43,3 +43,19 @@ func (a *Addr) Flush() error {
 
 	return nil
 }
+
+// Delete deletes protocol address.
+func (a *Addr) Delete() error {
+	cmd := []string{}
+	if a.Family != "" {
+		cmd = append(cmd, a.Family)
+	}
+
+	cmd = append(cmd, "addr", "delete", "dev", a.DevName, a.Address)
+	_, err := shared.RunCommand(context.TODO(), "ip", cmd...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
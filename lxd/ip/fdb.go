@@ -0,0 +1,72 @@
+package ip
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
+)
+
+// FDB represents arguments for bridge forwarding database entry manipulation.
+type FDB struct {
+	DevName string
+	MAC     net.HardwareAddr
+	Dst     net.IP
+}
+
+// Show lists the forwarding database entries of the device having a destination address.
+func (f *FDB) Show() ([]FDB, error) {
+	out, err := shared.RunCommand(context.TODO(), "bridge", "fdb", "show", "dev", f.DevName)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := shared.SplitNTrimSpace(out, "\n", -1, true)
+	entries := make([]FDB, 0, len(lines))
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[1] != "dst" {
+			continue
+		}
+
+		mac, err := net.ParseMAC(fields[0])
+		if err != nil {
+			continue
+		}
+
+		dst := net.ParseIP(fields[2])
+		if dst == nil {
+			continue
+		}
+
+		entries = append(entries, FDB{
+			DevName: f.DevName,
+			MAC:     mac,
+			Dst:     dst,
+		})
+	}
+
+	return entries, nil
+}
+
+// Append adds a forwarding database entry, allowing multiple destinations for the same MAC address.
+func (f *FDB) Append() error {
+	_, err := shared.RunCommand(context.TODO(), "bridge", "fdb", "append", f.MAC.String(), "dev", f.DevName, "dst", f.Dst.String())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes a forwarding database entry.
+func (f *FDB) Delete() error {
+	_, err := shared.RunCommand(context.TODO(), "bridge", "fdb", "delete", f.MAC.String(), "dev", f.DevName, "dst", f.Dst.String())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,5 +1,11 @@
 package ip
 
+import (
+	"context"
+
+	"github.com/canonical/lxd/shared"
+)
+
 // Vxlan represents arguments for link of type vxlan.
 type Vxlan struct {
 	Link
@@ -52,3 +58,13 @@ func (vxlan *Vxlan) additionalArgs() []string {
 func (vxlan *Vxlan) Add() error {
 	return vxlan.add("vxlan", vxlan.additionalArgs())
 }
+
+// SetLocal changes the local address of the vxlan link in place.
+func (vxlan *Vxlan) SetLocal(local string) error {
+	_, err := shared.RunCommand(context.TODO(), "ip", "link", "set", "dev", vxlan.Name, "type", "vxlan", "local", local)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -15,6 +15,7 @@ type InstanceBackupAction string
 const (
 	InstanceBackupCreated   = InstanceBackupAction(api.EventLifecycleInstanceBackupCreated)
 	InstanceBackupDeleted   = InstanceBackupAction(api.EventLifecycleInstanceBackupDeleted)
+	InstanceBackupFailed    = InstanceBackupAction(api.EventLifecycleInstanceBackupFailed)
 	InstanceBackupRenamed   = InstanceBackupAction(api.EventLifecycleInstanceBackupRenamed)
 	InstanceBackupRetrieved = InstanceBackupAction(api.EventLifecycleInstanceBackupRetrieved)
 )
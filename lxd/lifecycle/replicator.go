@@ -13,11 +13,14 @@ type ReplicatorAction string
 
 // All supported lifecycle events for replicators.
 const (
-	ReplicatorCreated = ReplicatorAction(api.EventLifecycleReplicatorCreated)
-	ReplicatorDeleted = ReplicatorAction(api.EventLifecycleReplicatorDeleted)
-	ReplicatorRenamed = ReplicatorAction(api.EventLifecycleReplicatorRenamed)
-	ReplicatorRun     = ReplicatorAction(api.EventLifecycleReplicatorRun)
-	ReplicatorUpdated = ReplicatorAction(api.EventLifecycleReplicatorUpdated)
+	ReplicatorCreated      = ReplicatorAction(api.EventLifecycleReplicatorCreated)
+	ReplicatorDeleted      = ReplicatorAction(api.EventLifecycleReplicatorDeleted)
+	ReplicatorPromoted     = ReplicatorAction(api.EventLifecycleReplicatorPromoted)
+	ReplicatorRenamed      = ReplicatorAction(api.EventLifecycleReplicatorRenamed)
+	ReplicatorRun          = ReplicatorAction(api.EventLifecycleReplicatorRun)
+	ReplicatorRunCompleted = ReplicatorAction(api.EventLifecycleReplicatorRunCompleted)
+	ReplicatorRunFailed    = ReplicatorAction(api.EventLifecycleReplicatorRunFailed)
+	ReplicatorUpdated      = ReplicatorAction(api.EventLifecycleReplicatorUpdated)
 )
 
 // Event creates the lifecycle event for an action on a replicator.
@@ -430,6 +430,22 @@ func (c *Client) HandleEvent(event api.Event) {
 		message.WriteString(logEvent.Message)
 
 		entry.Line = message.String()
+	case api.EventTypeNetworkACL:
+		aclEvent := api.EventNetworkACL{}
+
+		err := json.Unmarshal(event.Metadata, &aclEvent)
+		if err != nil {
+			return
+		}
+
+		entry.labels["name"] = aclEvent.ACL
+
+		if event.Project != "" {
+			entry.labels["project"] = event.Project
+		}
+
+		// The line is the JSON encoded event so that it can be parsed by log processing tools.
+		entry.Line = string(event.Metadata)
 	case api.EventTypeSecurity:
 		secEvent := api.EventSecurity{}
 
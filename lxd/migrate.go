@@ -9,8 +9,10 @@ import (
 	"context"
 	"crypto/subtle"
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -19,6 +21,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/migration"
 	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared/api"
 )
 
@@ -141,6 +144,26 @@ type migrationSourceWs struct {
 	pushCertificate  string
 	pushOperationURL string
 	pushSecrets      map[string]string
+
+	// limiter optionally throttles the data sent over the control and filesystem connections.
+	limiter *util.RateLimiter
+
+	// bytesSent counts the data sent over the control and filesystem connections.
+	bytesSent atomic.Int64
+}
+
+// countingReadWriteCloser counts the bytes written to the underlying [io.ReadWriteCloser].
+type countingReadWriteCloser struct {
+	io.ReadWriteCloser
+	count *atomic.Int64
+}
+
+// Write writes p to the underlying writer and adds the number of bytes written to the counter.
+func (rw *countingReadWriteCloser) Write(p []byte) (int, error) {
+	n, err := rw.ReadWriteCloser.Write(p)
+	rw.count.Add(int64(n))
+
+	return n, err
 }
 
 // Metadata returns a map where each key is a connection name and each value is
@@ -15,6 +15,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance/operationlock"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
@@ -110,7 +111,7 @@ func (s *migrationSourceWs) Do(ctx context.Context, state *state.State, migrateO
 			return nil, fmt.Errorf("Failed getting migration source control connection: %w", err)
 		}
 
-		return wsConn, nil
+		return &countingReadWriteCloser{ReadWriteCloser: util.RateLimitedReadWriteCloser(wsConn, s.limiter), count: &s.bytesSent}, nil
 	}
 
 	filesystemConnFunc := func(ctx context.Context) (io.ReadWriteCloser, error) {
@@ -124,7 +125,7 @@ func (s *migrationSourceWs) Do(ctx context.Context, state *state.State, migrateO
 			return nil, fmt.Errorf("Failed getting migration source filesystem connection: %w", err)
 		}
 
-		return wsConn, nil
+		return &countingReadWriteCloser{ReadWriteCloser: util.RateLimitedReadWriteCloser(wsConn, s.limiter), count: &s.bytesSent}, nil
 	}
 
 	err = s.instance.MigrateSend(ctx, instance.MigrateSendArgs{
@@ -0,0 +1,537 @@
+package acl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/config"
+	"github.com/canonical/lxd/lxd/db"
+	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
+	"github.com/canonical/lxd/lxd/network/openvswitch"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/validate"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// addressSetSubjectPrefix is the prefix of the rule subjects referencing an address set.
+const addressSetSubjectPrefix = "$"
+
+// NetworkAddressSet represents a network address set.
+type NetworkAddressSet interface {
+	// Info.
+	ID() int64
+	Project() string
+	Info() *api.NetworkAddressSet
+	Etag() []any
+	UsedBy() ([]string, error)
+
+	// Modifications.
+	Update(ctx context.Context, config *api.NetworkAddressSetPut, clientType request.ClientType) error
+	Rename(ctx context.Context, newName string) error
+	Delete(ctx context.Context, clientType request.ClientType) error
+}
+
+// addressSet represents a network address set.
+type addressSet struct {
+	logger      logger.Logger
+	state       *state.State
+	id          int64
+	projectName string
+	info        *api.NetworkAddressSet
+}
+
+// LoadAddressSetByName loads and initialises a network address set from the database by project and name.
+func LoadAddressSetByName(ctx context.Context, s *state.State, projectName string, name string) (NetworkAddressSet, error) {
+	var id int64
+	var info *api.NetworkAddressSet
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		id, info, err = tx.GetNetworkAddressSet(ctx, projectName, name)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	set := &addressSet{}
+	set.init(s, id, projectName, info)
+
+	return set, nil
+}
+
+// CreateAddressSet validates supplied record and creates new network address set record in the database.
+func CreateAddressSet(ctx context.Context, s *state.State, projectName string, info *api.NetworkAddressSetsPost) error {
+	err := ValidName(info.Name)
+	if err != nil {
+		return err
+	}
+
+	err = validateAddressSetConfig(&info.NetworkAddressSetPut)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.CreateNetworkAddressSet(ctx, projectName, info)
+
+		return err
+	})
+}
+
+// init initialise internal variables.
+func (d *addressSet) init(s *state.State, id int64, projectName string, info *api.NetworkAddressSet) {
+	if info == nil {
+		d.info = &api.NetworkAddressSet{}
+	} else {
+		d.info = info
+	}
+
+	d.logger = logger.AddContext(logger.Ctx{"project": projectName, "networkAddressSet": d.info.Name})
+	d.id = id
+	d.projectName = projectName
+	d.state = s
+
+	if d.info.Addresses == nil {
+		d.info.Addresses = []string{}
+	}
+
+	if d.info.Config == nil {
+		d.info.Config = make(map[string]string)
+	}
+}
+
+// ID returns the network address set ID.
+func (d *addressSet) ID() int64 {
+	return d.id
+}
+
+// Project returns the project name.
+func (d *addressSet) Project() string {
+	return d.projectName
+}
+
+// Info returns copy of internal info for the network address set.
+func (d *addressSet) Info() *api.NetworkAddressSet {
+	// Copy internal info to prevent modification externally.
+	info := api.NetworkAddressSet{}
+	info.Name = d.info.Name
+	info.Description = d.info.Description
+	info.Addresses = append(make([]string, 0, len(d.info.Addresses)), d.info.Addresses...)
+	info.Config = util.CopyConfig(d.info.Config)
+	info.UsedBy = nil // To indicate its not populated (use UsedBy() function to populate).
+	info.Project = d.projectName
+
+	return &info
+}
+
+// Etag returns the values used for etag generation.
+func (d *addressSet) Etag() []any {
+	return []any{d.info.Name, d.info.Description, d.info.Addresses, d.info.Config}
+}
+
+// usedBy returns the names of the ACLs whose rules reference the address set.
+// If firstOnly is true then search stops at first result.
+func (d *addressSet) usedBy(ctx context.Context, firstOnly bool) ([]string, error) {
+	aclNames := []string{}
+
+	err := d.state.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projectACLNames, err := tx.GetNetworkACLs(ctx, d.projectName)
+		if err != nil {
+			return err
+		}
+
+		for _, aclName := range projectACLNames {
+			_, aclInfo, err := tx.GetNetworkACL(ctx, d.projectName, aclName)
+			if err != nil {
+				return err
+			}
+
+			if !slices.Contains(aclAddressSetNames(aclInfo), d.info.Name) {
+				continue
+			}
+
+			aclNames = append(aclNames, aclName)
+
+			if firstOnly {
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting address set usage: %w", err)
+	}
+
+	return aclNames, nil
+}
+
+// UsedBy returns a list of API endpoints referencing this address set.
+func (d *addressSet) UsedBy() ([]string, error) {
+	aclNames, err := d.usedBy(context.TODO(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	usedBy := make([]string, 0, len(aclNames))
+	for _, aclName := range aclNames {
+		usedBy = append(usedBy, api.NewURL().Path(version.APIVersion, "network-acls", aclName).Project(d.projectName).String())
+	}
+
+	return usedBy, nil
+}
+
+// isUsed returns whether or not the address set is in use.
+func (d *addressSet) isUsed() (bool, error) {
+	aclNames, err := d.usedBy(context.TODO(), true)
+	if err != nil {
+		return false, err
+	}
+
+	return len(aclNames) > 0, nil
+}
+
+// Update applies the supplied config to the address set.
+// The new addresses are applied to the firewall of the bridge networks and to the OVN networks using the address set.
+func (d *addressSet) Update(ctx context.Context, config *api.NetworkAddressSetPut, clientType request.ClientType) error {
+	err := validateAddressSetConfig(config)
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		oldConfig := d.info.Writable()
+
+		err = d.state.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetworkAddressSet(ctx, d.id, *config)
+		})
+		if err != nil {
+			return err
+		}
+
+		// Apply changes internally and reinitialise.
+		d.info.SetWritable(*config)
+		d.init(d.state, d.id, d.projectName, d.info)
+
+		revert.Add(func() {
+			_ = d.state.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.UpdateNetworkAddressSet(ctx, d.id, oldConfig)
+			})
+
+			d.info.SetWritable(oldConfig)
+			d.init(d.state, d.id, d.projectName, d.info)
+		})
+	}
+
+	// Only apply the changes to OVN once, from the member handling the request. The other members re-resolve the
+	// domain names when notified, as the notification may come from a refresh of the domain names by the leader.
+	hasBridgeNets, err := d.apply(ctx, clientType == request.ClientTypeNormal, clientType != request.ClientTypeNormal)
+	if err != nil {
+		return err
+	}
+
+	// Apply the changes to the firewall of the other cluster members.
+	if clientType == request.ClientTypeNormal && hasBridgeNets {
+		err = d.notifyMembers(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	revert.Success()
+	return nil
+}
+
+// notifyMembers asks the other cluster members to apply the address set to their firewall.
+func (d *addressSet) notifyMembers(ctx context.Context) error {
+	notifier, err := cluster.NewOperationNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
+	if err != nil {
+		return err
+	}
+
+	return notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+		op, err := client.UseProject(d.projectName).UpdateNetworkAddressSet(d.info.Name, d.info.Writable(), "")
+		if err == nil {
+			err = op.WaitContext(ctx)
+		}
+
+		return err
+	})
+}
+
+// apply applies the addresses of the address set to the firewall of this member if bridge networks use it and,
+// if applyOVN is true, to OVN if OVN networks use it. If refresh is true then the domain names are resolved again
+// rather than taken from the cache. Returns whether bridge networks use the address set.
+func (d *addressSet) apply(ctx context.Context, applyOVN bool, refresh bool) (bool, error) {
+	aclNames, err := d.usedBy(ctx, false)
+	if err != nil {
+		return false, err
+	}
+
+	aclNets := map[string]NetworkACLUsage{}
+	err = NetworkUsage(ctx, d.state, d.projectName, aclNames, aclNets)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting address set network usage: %w", err)
+	}
+
+	hasBridgeNets := false
+	hasOVNNets := false
+	for _, aclNet := range aclNets {
+		switch aclNet.Type {
+		case "bridge":
+			hasBridgeNets = true
+		case "ovn":
+			hasOVNNets = true
+		}
+	}
+
+	if !hasBridgeNets && (!hasOVNNets || !applyOVN) {
+		return hasBridgeNets, nil
+	}
+
+	addresses := addressSetAddresses(ctx, d.logger, d.info.Addresses, refresh)
+
+	if hasBridgeNets {
+		err = d.state.Firewall.NetworkApplyAddressSets([]firewallDrivers.AddressSet{{Name: addressSetName(d.id), Addresses: addresses}})
+		if err != nil {
+			return false, fmt.Errorf("Failed applying address set %q to firewall: %w", d.info.Name, err)
+		}
+	}
+
+	if hasOVNNets && applyOVN {
+		client, err := openvswitch.NewOVN(d.state.GlobalConfig.NetworkOVNNorthboundConnection(), d.state.GlobalConfig.NetworkOVNSSL)
+		if err != nil {
+			return false, fmt.Errorf("Failed getting OVN client: %w", err)
+		}
+
+		err = client.AddressSetUpdate(openvswitch.OVNAddressSet(addressSetName(d.id)), addresses...)
+		if err != nil {
+			return false, fmt.Errorf("Failed applying address set %q to OVN: %w", d.info.Name, err)
+		}
+	}
+
+	return hasBridgeNets, nil
+}
+
+// Rename renames the address set if not in use.
+func (d *addressSet) Rename(ctx context.Context, newName string) error {
+	_, err := LoadAddressSetByName(ctx, d.state, d.projectName, newName)
+	if err == nil {
+		return errors.New("An address set by that name exists already")
+	}
+
+	isUsed, err := d.isUsed()
+	if err != nil {
+		return err
+	}
+
+	if isUsed {
+		return errors.New("Cannot rename an address set that is in use")
+	}
+
+	err = ValidName(newName)
+	if err != nil {
+		return err
+	}
+
+	err = d.state.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.RenameNetworkAddressSet(ctx, d.id, newName)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Apply changes internally.
+	d.info.Name = newName
+
+	return nil
+}
+
+// Delete deletes the address set if not in use, and removes it from the firewall of the cluster members and from OVN.
+func (d *addressSet) Delete(ctx context.Context, clientType request.ClientType) error {
+	if clientType == request.ClientTypeNormal {
+		isUsed, err := d.isUsed()
+		if err != nil {
+			return err
+		}
+
+		if isUsed {
+			return errors.New("Cannot delete an address set that is in use")
+		}
+
+		// Remove the address set from the firewall of the other cluster members before removing the record.
+		notifier, err := cluster.NewOperationNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+			op, err := client.UseProject(d.projectName).DeleteNetworkAddressSet(d.info.Name)
+			if err == nil {
+				err = op.WaitContext(ctx)
+			}
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err := d.state.Firewall.NetworkDeleteAddressSets([]string{addressSetName(d.id)})
+	if err != nil {
+		return fmt.Errorf("Failed deleting address set %q from firewall: %w", d.info.Name, err)
+	}
+
+	if clientType != request.ClientTypeNormal {
+		return nil
+	}
+
+	// The address set may have been applied to OVN while in use. Skip the cleanup if OVN isn't available.
+	client, err := openvswitch.NewOVN(d.state.GlobalConfig.NetworkOVNNorthboundConnection(), d.state.GlobalConfig.NetworkOVNSSL)
+	if err == nil {
+		err = client.AddressSetDelete(openvswitch.OVNAddressSet(addressSetName(d.id)))
+		if err != nil {
+			d.logger.Warn("Failed deleting address set from OVN", logger.Ctx{"err": err})
+		}
+	}
+
+	return d.state.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteNetworkAddressSet(ctx, d.id)
+	})
+}
+
+// validateAddressSetConfig checks the config and addresses of an address set are valid.
+func validateAddressSetConfig(info *api.NetworkAddressSetPut) error {
+	for k := range info.Config {
+		// User keys are not validated.
+		if config.IsUserConfig(k) {
+			continue
+		}
+
+		return fmt.Errorf("Invalid config option %q", k)
+	}
+
+	for i, address := range info.Addresses {
+		if slices.Contains(info.Addresses[:i], address) {
+			return fmt.Errorf("Duplicate address %q", address)
+		}
+
+		if validate.IsNetworkAddress(address) == nil || validate.IsNetworkAddressCIDR(address) == nil {
+			continue
+		}
+
+		err := validate.IsDomainName(strings.TrimSuffix(address, "."))
+		if err != nil {
+			return fmt.Errorf("Invalid address %q, must be an IP address, a CIDR subnet or a domain name", address)
+		}
+	}
+
+	return nil
+}
+
+// addressSetName returns the name of the firewall and OVN address sets used for a network address set ID.
+func addressSetName(setID int64) string {
+	return fmt.Sprintf("lxd_addrset%d", setID)
+}
+
+// aclAddressSetNames returns the names of the address sets referenced by the rules of the ACL.
+func aclAddressSetNames(info *api.NetworkACL) []string {
+	setNames := []string{}
+
+	for _, rules := range [][]api.NetworkACLRule{info.Ingress, info.Egress} {
+		for _, rule := range rules {
+			for _, subjects := range []string{rule.Source, rule.Destination} {
+				for _, subject := range shared.SplitNTrimSpace(subjects, ",", -1, true) {
+					setName, isAddressSet := strings.CutPrefix(subject, addressSetSubjectPrefix)
+					if isAddressSet && !slices.Contains(setNames, setName) {
+						setNames = append(setNames, setName)
+					}
+				}
+			}
+		}
+	}
+
+	return setNames
+}
+
+// addressSetSubjects replaces the address set subjects ("$<name>") of a comma separated list of rule subjects with
+// the subjects referencing the firewall or OVN address sets ("$lxd_addrset<ID>").
+func addressSetSubjects(subjects string, setIDs map[string]int64) (string, error) {
+	if !strings.Contains(subjects, addressSetSubjectPrefix) {
+		return subjects, nil
+	}
+
+	replaced := []string{}
+	for _, subject := range shared.SplitNTrimSpace(subjects, ",", -1, true) {
+		setName, isAddressSet := strings.CutPrefix(subject, addressSetSubjectPrefix)
+		if isAddressSet {
+			setID, found := setIDs[setName]
+			if !found {
+				return "", fmt.Errorf("Network address set %q does not exist", setName)
+			}
+
+			subject = addressSetSubjectPrefix + addressSetName(setID)
+		}
+
+		replaced = append(replaced, subject)
+	}
+
+	return strings.Join(replaced, ","), nil
+}
+
+// firewallApplyAddressSets applies the addresses of the specified address sets to the firewall.
+func firewallApplyAddressSets(ctx context.Context, s *state.State, projectName string, setNames []string) error {
+	if len(setNames) == 0 {
+		return nil
+	}
+
+	setIDs := make([]int64, 0, len(setNames))
+	setEntries := make([][]string, 0, len(setNames))
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		for _, setName := range setNames {
+			setID, setInfo, err := tx.GetNetworkAddressSet(ctx, projectName, setName)
+			if err != nil {
+				return fmt.Errorf("Failed loading network address set %q: %w", setName, err)
+			}
+
+			setIDs = append(setIDs, setID)
+			setEntries = append(setEntries, setInfo.Addresses)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Resolve the domain names outside of the transaction.
+	l := logger.AddContext(logger.Ctx{"project": projectName})
+	sets := make([]firewallDrivers.AddressSet, 0, len(setIDs))
+	for i, setID := range setIDs {
+		sets = append(sets, firewallDrivers.AddressSet{
+			Name:      addressSetName(setID),
+			Addresses: addressSetAddresses(ctx, l, setEntries[i], false),
+		})
+	}
+
+	return s.Firewall.NetworkApplyAddressSets(sets)
+}
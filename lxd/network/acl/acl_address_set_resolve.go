@@ -0,0 +1,236 @@
+package acl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// addressSetResolveTimeout is the timeout for resolving a domain name of an address set.
+const addressSetResolveTimeout = 5 * time.Second
+
+// addressSetResolver resolves the domain names of the address sets. Replaced by a local resolver in tests.
+var addressSetResolver interface {
+	LookupIP(ctx context.Context, network string, host string) ([]net.IP, error)
+} = net.DefaultResolver
+
+// addressSetResolved caches the IP addresses the domain names of the address sets were last resolved to.
+var addressSetResolved = map[string][]net.IP{}
+var addressSetResolvedMu sync.Mutex
+
+// addressSetResolve resolves the domain name and caches the result. If refresh is false then a cached result is
+// returned when available. If the resolution fails then the previously cached result is kept and returned along
+// with the error. Returns whether the resolved addresses differ from the cached ones.
+func addressSetResolve(ctx context.Context, domain string, refresh bool) ([]net.IP, bool, error) {
+	domain = strings.TrimSuffix(domain, ".")
+
+	addressSetResolvedMu.Lock()
+	cached, found := addressSetResolved[domain]
+	addressSetResolvedMu.Unlock()
+
+	if found && !refresh {
+		return cached, false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, addressSetResolveTimeout)
+	defer cancel()
+
+	ips, err := addressSetResolver.LookupIP(ctx, "ip", domain)
+	if err != nil {
+		return cached, false, fmt.Errorf("Failed resolving %q: %w", domain, err)
+	}
+
+	// Sort the addresses so that changes in the order of the DNS records aren't seen as changes.
+	slices.SortFunc(ips, func(a net.IP, b net.IP) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	changed := !slices.EqualFunc(ips, cached, func(a net.IP, b net.IP) bool { return a.Equal(b) })
+
+	addressSetResolvedMu.Lock()
+	addressSetResolved[domain] = ips
+	addressSetResolvedMu.Unlock()
+
+	return ips, changed, nil
+}
+
+// addressSetAddresses converts the entries of an address set into subnets, resolving the domain names.
+// Domain names that fail to resolve are logged and skipped, unless a previous resolution is cached.
+func addressSetAddresses(ctx context.Context, l logger.Logger, entries []string, refresh bool) []net.IPNet {
+	addresses := make([]net.IPNet, 0, len(entries))
+
+	for _, entry := range entries {
+		ip := net.ParseIP(entry)
+		if ip != nil {
+			addresses = append(addresses, ipToSubnet(ip))
+			continue
+		}
+
+		_, subnet, err := net.ParseCIDR(entry)
+		if err == nil {
+			addresses = append(addresses, *subnet)
+			continue
+		}
+
+		ips, _, err := addressSetResolve(ctx, entry, refresh)
+		if err != nil {
+			l.Warn("Failed resolving address set domain name", logger.Ctx{"domain": entry, "err": err})
+		}
+
+		for _, ip := range ips {
+			addresses = append(addresses, ipToSubnet(ip))
+		}
+	}
+
+	return addresses
+}
+
+// ipToSubnet returns the single address subnet of the IP.
+func ipToSubnet(ip net.IP) net.IPNet {
+	if ip.To4() != nil {
+		return net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}
+	}
+
+	return net.IPNet{IP: ip.To16(), Mask: net.CIDRMask(128, 128)}
+}
+
+// addressSetDomains returns the domain names of the entries of an address set.
+func addressSetDomains(entries []string) []string {
+	domains := []string{}
+
+	for _, entry := range entries {
+		if net.ParseIP(entry) != nil {
+			continue
+		}
+
+		_, _, err := net.ParseCIDR(entry)
+		if err == nil {
+			continue
+		}
+
+		domains = append(domains, strings.TrimSuffix(entry, "."))
+	}
+
+	return domains
+}
+
+// RefreshAddressSets re-resolves the domain names of all address sets and applies the address sets whose resolved
+// addresses changed. This only runs on the cluster leader, which updates OVN and notifies the other members to
+// update the firewall of their bridge networks. The other members only forget the domain names no longer used.
+func RefreshAddressSets(ctx context.Context, s *state.State) error {
+	type addressSetRecord struct {
+		projectName string
+		id          int64
+		info        *api.NetworkAddressSet
+	}
+
+	var records []addressSetRecord
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projectSets, err := tx.GetNetworkAddressSetsAllProjects(ctx)
+		if err != nil {
+			return err
+		}
+
+		for projectName, setNames := range projectSets {
+			for _, setName := range setNames {
+				id, info, err := tx.GetNetworkAddressSet(ctx, projectName, setName)
+				if err != nil {
+					return err
+				}
+
+				if len(addressSetDomains(info.Addresses)) == 0 {
+					continue
+				}
+
+				records = append(records, addressSetRecord{projectName: projectName, id: id, info: info})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network address sets: %w", err)
+	}
+
+	// Forget the domain names no longer used by any address set.
+	usedDomains := map[string]bool{}
+	for _, record := range records {
+		for _, domain := range addressSetDomains(record.info.Addresses) {
+			usedDomains[domain] = true
+		}
+	}
+
+	addressSetResolvedMu.Lock()
+	for domain := range addressSetResolved {
+		if !usedDomains[domain] {
+			delete(addressSetResolved, domain)
+		}
+	}
+
+	addressSetResolvedMu.Unlock()
+
+	leaderInfo, err := s.LeaderInfo()
+	if err != nil {
+		return err
+	}
+
+	if !leaderInfo.Leader {
+		return nil
+	}
+
+	// Re-resolve each domain name once.
+	domains := map[string]bool{}
+	for _, record := range records {
+		for _, domain := range addressSetDomains(record.info.Addresses) {
+			_, seen := domains[domain]
+			if seen {
+				continue
+			}
+
+			_, changed, err := addressSetResolve(ctx, domain, true)
+			if err != nil {
+				logger.Warn("Failed refreshing address set domain name", logger.Ctx{"domain": domain, "err": err})
+			}
+
+			domains[domain] = changed
+		}
+	}
+
+	for _, record := range records {
+		changed := slices.ContainsFunc(addressSetDomains(record.info.Addresses), func(domain string) bool {
+			return domains[domain]
+		})
+
+		if !changed {
+			continue
+		}
+
+		set := &addressSet{}
+		set.init(s, record.id, record.projectName, record.info)
+
+		hasBridgeNets, err := set.apply(ctx, true, false)
+		if err != nil {
+			set.logger.Warn("Failed applying refreshed address set", logger.Ctx{"err": err})
+			continue
+		}
+
+		if hasBridgeNets && leaderInfo.Clustered {
+			err = set.notifyMembers(ctx)
+			if err != nil {
+				set.logger.Warn("Failed notifying cluster members of refreshed address set", logger.Ctx{"err": err})
+			}
+		}
+	}
+
+	return nil
+}
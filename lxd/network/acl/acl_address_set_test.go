@@ -0,0 +1,224 @@
+package acl
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// localResolver is a stand-in for the DNS resolver of the address sets.
+type localResolver map[string][]net.IP
+
+// LookupIP returns the addresses of the host, or an error if the host is unknown.
+func (r localResolver) LookupIP(ctx context.Context, network string, host string) ([]net.IP, error) {
+	ips, found := r[host]
+	if !found {
+		return nil, errors.New("No such host")
+	}
+
+	return slices.Clone(ips), nil
+}
+
+// useLocalResolver replaces the address set resolver and cache for the duration of the test.
+func useLocalResolver(t *testing.T, resolver localResolver) {
+	oldResolver := addressSetResolver
+	oldResolved := addressSetResolved
+
+	addressSetResolver = resolver
+	addressSetResolved = map[string][]net.IP{}
+
+	t.Cleanup(func() {
+		addressSetResolver = oldResolver
+		addressSetResolved = oldResolved
+	})
+}
+
+func Test_validateAddressSetConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		info      api.NetworkAddressSetPut
+		expectErr bool
+	}{
+		{
+			name: "Valid addresses",
+			info: api.NetworkAddressSetPut{
+				Addresses: []string{"10.0.0.1", "10.0.1.0/24", "fd00::1", "fd00:1::/64", "www.example.com", "example.com."},
+				Config:    map[string]string{"user.foo": "bar"},
+			},
+		},
+		{
+			name: "Invalid config key",
+			info: api.NetworkAddressSetPut{
+				Config: map[string]string{"foo": "bar"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid address",
+			info: api.NetworkAddressSetPut{
+				Addresses: []string{"10.0.0.1-10.0.0.5"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Duplicate address",
+			info: api.NetworkAddressSetPut{
+				Addresses: []string{"10.0.0.1", "10.0.0.1"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Duplicate domain name",
+			info: api.NetworkAddressSetPut{
+				Addresses: []string{"www.example.com", "www.example.com"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAddressSetConfig(&tt.info)
+			if tt.expectErr && err == nil {
+				t.Errorf("Expected an error, got none")
+			} else if !tt.expectErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func Test_addressSetSubjects(t *testing.T) {
+	setIDs := map[string]int64{"web": 1, "dns": 2}
+
+	tests := []struct {
+		name      string
+		subjects  string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "No address sets",
+			subjects: "10.0.0.1,@internal",
+			expected: "10.0.0.1,@internal",
+		},
+		{
+			name:     "Mixed subjects",
+			subjects: "10.0.0.1, $web,$dns",
+			expected: "10.0.0.1,$lxd_addrset1,$lxd_addrset2",
+		},
+		{
+			name:      "Unknown address set",
+			subjects:  "$unknown",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := addressSetSubjects(tt.subjects, setIDs)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected an error, got none")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_aclAddressSetNames(t *testing.T) {
+	info := &api.NetworkACL{
+		Ingress: []api.NetworkACLRule{
+			{Action: "allow", Source: "$web,10.0.0.1"},
+			{Action: "allow", Source: "@internal", Destination: "$dns"},
+		},
+		Egress: []api.NetworkACLRule{
+			{Action: "allow", Destination: "$web,$mail"},
+		},
+	}
+
+	result := aclAddressSetNames(info)
+	expected := []string{"web", "dns", "mail"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func Test_addressSetAddresses(t *testing.T) {
+	resolver := localResolver{
+		"www.example.com": {net.ParseIP("192.0.2.20"), net.ParseIP("192.0.2.10"), net.ParseIP("2001:db8::10")},
+	}
+
+	useLocalResolver(t, resolver)
+
+	l := logger.AddContext(logger.Ctx{"test": t.Name()})
+	entries := []string{"10.0.0.1", "10.0.1.0/24", "www.example.com.", "unknown.example.com"}
+
+	toStrings := func(addresses []net.IPNet) []string {
+		result := make([]string, 0, len(addresses))
+		for _, address := range addresses {
+			result = append(result, address.String())
+		}
+
+		return result
+	}
+
+	// Domain names are resolved, sorted and unknown ones skipped.
+	result := toStrings(addressSetAddresses(context.Background(), l, entries, false))
+	expected := []string{"10.0.0.1/32", "10.0.1.0/24", "192.0.2.10/32", "192.0.2.20/32", "2001:db8::10/128"}
+	if !slices.Equal(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+
+	// Without refresh the cached addresses are used.
+	resolver["www.example.com"] = []net.IP{net.ParseIP("192.0.2.30")}
+	result = toStrings(addressSetAddresses(context.Background(), l, entries, false))
+	if !slices.Equal(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+
+	// A refresh detects the change.
+	_, changed, err := addressSetResolve(context.Background(), "www.example.com", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !changed {
+		t.Errorf("Expected the resolved addresses to have changed")
+	}
+
+	_, changed, err = addressSetResolve(context.Background(), "www.example.com", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if changed {
+		t.Errorf("Expected the resolved addresses to be unchanged")
+	}
+
+	// A failed refresh keeps the cached addresses.
+	delete(resolver, "www.example.com")
+	ips, changed, err := addressSetResolve(context.Background(), "www.example.com", true)
+	if err == nil {
+		t.Errorf("Expected an error, got none")
+	}
+
+	if changed || len(ips) != 1 || !ips[0].Equal(net.ParseIP("192.0.2.30")) {
+		t.Errorf("Expected the cached addresses to be kept, got %v", ips)
+	}
+}
@@ -0,0 +1,113 @@
+package acl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/network/openvswitch"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// ruleName returns the name used to identify an ACL rule in the logs and to find its counters.
+// It matches the name given to the rules of the ACL's OVN port group.
+func ruleName(aclID int64, direction string, ruleIndex int) string {
+	return fmt.Sprintf("%s-%s-%d", OVNACLPortGroupName(aclID), direction, ruleIndex)
+}
+
+// parseRuleName parses a name generated by ruleName and returns the ACL ID, the rule direction and the rule index.
+// Returns false if the name isn't an ACL rule name.
+func parseRuleName(name string) (int64, string, int, bool) {
+	name, found := strings.CutPrefix(name, ovnACLPortGroupPrefix)
+	if !found {
+		return 0, "", 0, false
+	}
+
+	fields := strings.Split(name, "-")
+	if len(fields) != 3 || (fields[1] != string(ruleDirectionIngress) && fields[1] != string(ruleDirectionEgress)) {
+		return 0, "", 0, false
+	}
+
+	aclID, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, "", 0, false
+	}
+
+	ruleIndex, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return 0, "", 0, false
+	}
+
+	return aclID, fields[1], ruleIndex, true
+}
+
+// localRuleCounters returns the counters of the rules of the ACL on this member, indexed by rule name.
+// The counters of bridge networks are read from the firewall and the counters of OVN networks from the flows of
+// the local OVS integration bridge.
+func localRuleCounters(s *state.State, l logger.Logger, aclID int64, aclNets map[string]NetworkACLUsage) (map[string]api.NetworkACLRuleCounters, error) {
+	counters := make(map[string]api.NetworkACLRuleCounters)
+	namePrefix := string(OVNACLPortGroupName(aclID)) + "-"
+
+	hasOVNNets := false
+	for _, aclNet := range aclNets {
+		if aclNet.Type == "ovn" {
+			hasOVNNets = true
+			continue
+		}
+
+		netCounters, err := s.Firewall.NetworkACLRuleCounters(aclNet.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting ACL rule counters for network %q: %w", aclNet.Name, err)
+		}
+
+		for name, netCounter := range netCounters {
+			if !strings.HasPrefix(name, namePrefix) {
+				continue
+			}
+
+			ruleCounters := counters[name]
+			ruleCounters.Packets += netCounter.Packets
+			ruleCounters.Bytes += netCounter.Bytes
+			counters[name] = ruleCounters
+		}
+	}
+
+	// OVN networks share the ACL's port group, so the rules only need to be looked up once.
+	ovs := openvswitch.NewOVS()
+	if !hasOVNNets || !ovs.Installed() {
+		return counters, nil
+	}
+
+	client, err := openvswitch.NewOVN(s.GlobalConfig.NetworkOVNNorthboundConnection(), s.GlobalConfig.NetworkOVNSSL)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting OVN client: %w", err)
+	}
+
+	cookies, err := client.ACLRuleFlowCookies(namePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting OVN ACL rule flows: %w", err)
+	}
+
+	integrationBridge := s.GlobalConfig.NetworkOVNIntegrationBridge()
+	for name, ruleCookies := range cookies {
+		ruleCounters := counters[name]
+
+		for _, cookie := range ruleCookies {
+			packets, bytes, err := ovs.BridgeFlowCounters(integrationBridge, cookie)
+			if err != nil {
+				// The integration bridge may not exist if this member isn't an OVN chassis.
+				l.Debug("Failed getting OVN ACL rule flow counters", logger.Ctx{"rule": name, "cookie": cookie, "err": err})
+				continue
+			}
+
+			ruleCounters.Packets += packets
+			ruleCounters.Bytes += bytes
+		}
+
+		counters[name] = ruleCounters
+	}
+
+	return counters, nil
+}
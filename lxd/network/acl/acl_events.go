@@ -0,0 +1,293 @@
+package acl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// logEventACLCacheTTL is how long the ACLs resolved from the names of logged rules are cached.
+// This avoids querying the database for every logged packet.
+const logEventACLCacheTTL = 10 * time.Second
+
+// logEventACL represents the ACL information needed to send the events of its logged rules.
+type logEventACL struct {
+	projectName string
+	info        *api.NetworkACL
+	expiry      time.Time
+}
+
+// logEventACLCache caches the ACLs indexed by ID.
+var logEventACLCache = map[int64]logEventACL{}
+var logEventACLCacheMu sync.Mutex
+
+// sendLogEvent sends a network ACL event for a packet matched by the named ACL rule.
+// If the event doesn't specify an action, the action of the rule is used.
+func sendLogEvent(ctx context.Context, s *state.State, name string, event api.EventNetworkACL) error {
+	aclID, direction, ruleIndex, ok := parseRuleName(name)
+	if !ok {
+		return nil // Not a rule of a network ACL.
+	}
+
+	logEventACLCacheMu.Lock()
+	aclInfo, found := logEventACLCache[aclID]
+	logEventACLCacheMu.Unlock()
+
+	// The cache lock isn't held while querying the database, so that a slow query doesn't block the events of the
+	// other ACLs. Concurrent misses for the same ACL may both query it, the last one filling the cache.
+	if !found || aclInfo.expiry.Before(time.Now()) {
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			aclName, projectName, err := tx.GetNetworkACLNameAndProjectWithID(ctx, int(aclID))
+			if err != nil {
+				return err
+			}
+
+			_, info, err := tx.GetNetworkACL(ctx, projectName, aclName)
+			if err != nil {
+				return err
+			}
+
+			aclInfo = logEventACL{
+				projectName: projectName,
+				info:        info,
+				expiry:      time.Now().Add(logEventACLCacheTTL),
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("Failed loading network ACL with ID %d: %w", aclID, err)
+		}
+
+		logEventACLCacheMu.Lock()
+
+		// Drop the expired entries so that the cache doesn't retain deleted ACLs.
+		for id, cached := range logEventACLCache {
+			if cached.expiry.Before(time.Now()) {
+				delete(logEventACLCache, id)
+			}
+		}
+
+		logEventACLCache[aclID] = aclInfo
+		logEventACLCacheMu.Unlock()
+	}
+
+	rules := aclInfo.info.Ingress
+	if direction == string(ruleDirectionEgress) {
+		rules = aclInfo.info.Egress
+	}
+
+	event.ACL = aclInfo.info.Name
+	event.Direction = direction
+	event.Rule = ruleIndex
+
+	if event.Action == "" && ruleIndex < len(rules) {
+		event.Action = rules[ruleIndex].Action
+	}
+
+	return s.Events.Send(aclInfo.projectName, api.EventTypeNetworkACL, event)
+}
+
+// SendOVNLogEvent sends a network ACL event for an ACL log message received from ovn-controller.
+// Messages that aren't generated by a logged rule of a network ACL are ignored.
+func SendOVNLogEvent(ctx context.Context, s *state.State, message string) error {
+	name, event, ok := ovnParseLogEvent(message)
+	if !ok {
+		return nil
+	}
+
+	return sendLogEvent(ctx, s, name, event)
+}
+
+// ovnParseLogEvent parses the message of an OVN ACL log entry.
+// Returns the name of the rule and the event, or false if the message can't be parsed.
+func ovnParseLogEvent(message string) (string, api.EventNetworkACL, bool) {
+	// E.g. name="lxd_acl1-ingress-0", verdict=allow, severity=info, direction=to-lport: tcp,vlan_tci=0x0000,...
+	aclEntry := ovnParseLogMessage(message)
+
+	name := aclEntry["name"]
+	if name == "" {
+		return "", api.EventNetworkACL{}, false
+	}
+
+	_, protocol, found := strings.Cut(aclEntry["direction"], " ")
+	if !found {
+		return "", api.EventNetworkACL{}, false
+	}
+
+	event := api.EventNetworkACL{
+		Action:          aclEntry["verdict"],
+		Protocol:        protocol,
+		Source:          aclEntry["nw_src"],
+		Destination:     aclEntry["nw_dst"],
+		SourcePort:      aclEntry["tp_src"],
+		DestinationPort: aclEntry["tp_dst"],
+		ICMPType:        aclEntry["icmp_type"],
+		ICMPCode:        aclEntry["icmp_code"],
+	}
+
+	if event.Source == "" {
+		event.Source = aclEntry["ipv6_src"]
+	}
+
+	if event.Destination == "" {
+		event.Destination = aclEntry["ipv6_dst"]
+	}
+
+	return name, event, true
+}
+
+// firewallLoggedNetworks holds the names of the bridge networks whose firewall has logged ACL rules.
+// The kernel log is only read while there is at least one such network.
+var firewallLoggedNetworks = map[string]bool{}
+var firewallLogMonitorCancel context.CancelFunc
+var firewallLogMonitorMu sync.Mutex
+
+// FirewallLogMonitorUpdate records whether the firewall of a bridge network has logged ACL rules, and starts or
+// stops reading the kernel log depending on whether any network still has logged rules.
+func FirewallLogMonitorUpdate(s *state.State, networkName string, logged bool) {
+	firewallLogMonitorMu.Lock()
+	defer firewallLogMonitorMu.Unlock()
+
+	if logged {
+		firewallLoggedNetworks[networkName] = true
+	} else {
+		delete(firewallLoggedNetworks, networkName)
+	}
+
+	if len(firewallLoggedNetworks) > 0 && firewallLogMonitorCancel == nil {
+		ctx, cancel := context.WithCancel(s.ShutdownCtx)
+
+		err := startFirewallLogMonitor(ctx, s)
+		if err != nil {
+			cancel()
+			logger.Warn("Failed starting network ACL log monitor", logger.Ctx{"err": err})
+			return
+		}
+
+		firewallLogMonitorCancel = cancel
+	} else if len(firewallLoggedNetworks) == 0 && firewallLogMonitorCancel != nil {
+		firewallLogMonitorCancel()
+		firewallLogMonitorCancel = nil
+	}
+}
+
+// startFirewallLogMonitor starts reading the kernel log for the entries generated by the logged ACL rules of
+// bridge networks, and sends them as network ACL events. The monitor stops when the context is cancelled.
+func startFirewallLogMonitor(ctx context.Context, s *state.State) error {
+	kmsg, err := os.Open("/dev/kmsg")
+	if err != nil {
+		return fmt.Errorf("Failed opening kernel log: %w", err)
+	}
+
+	// Only consider the entries logged from now on.
+	_, err = kmsg.Seek(0, io.SeekEnd)
+	if err != nil {
+		_ = kmsg.Close()
+		return fmt.Errorf("Failed seeking to the end of the kernel log: %w", err)
+	}
+
+	// Close the kernel log when the context is cancelled, causing the reader below to exit.
+	go func() {
+		<-ctx.Done()
+		_ = kmsg.Close()
+	}()
+
+	go func() {
+		// Each read returns a single kernel log entry.
+		buf := make([]byte, 8192)
+
+		for {
+			n, err := kmsg.Read(buf)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				// Entries were overwritten before being read, carry on with the next ones.
+				if errors.Is(err, unix.EPIPE) {
+					continue
+				}
+
+				logger.Warn("Failed reading kernel log, stopping network ACL log monitor", logger.Ctx{"err": err})
+				return
+			}
+
+			name, event, ok := firewallParseLogEvent(string(buf[:n]))
+			if !ok {
+				continue
+			}
+
+			err = sendLogEvent(ctx, s, name, event)
+			if err != nil {
+				logger.Debug("Failed sending network ACL event", logger.Ctx{"rule": name, "err": err})
+			}
+		}
+	}()
+
+	return nil
+}
+
+// firewallParseLogEvent parses a kernel log entry generated by a logged ACL rule of a bridge network.
+// Returns the name of the rule and the event, or false if the entry isn't generated by an ACL rule.
+func firewallParseLogEvent(entry string) (string, api.EventNetworkACL, bool) {
+	// E.g. 4,1234,5678901,-;lxd_acl1-ingress-0 IN=eth0 OUT=lxdbr0 SRC=10.0.0.1 DST=10.0.0.2 ... PROTO=TCP SPT=40232 DPT=80 ...
+	_, message, found := strings.Cut(entry, ";")
+	if !found {
+		return "", api.EventNetworkACL{}, false
+	}
+
+	name, message, found := strings.Cut(strings.TrimSpace(message), " ")
+	if !found {
+		return "", api.EventNetworkACL{}, false
+	}
+
+	_, _, _, ok := parseRuleName(name)
+	if !ok {
+		return "", api.EventNetworkACL{}, false
+	}
+
+	fields := map[string]string{}
+	for _, field := range strings.Fields(message) {
+		key, value, found := strings.Cut(field, "=")
+		if found {
+			fields[key] = value
+		}
+	}
+
+	event := api.EventNetworkACL{
+		Source:          fields["SRC"],
+		Destination:     fields["DST"],
+		SourcePort:      fields["SPT"],
+		DestinationPort: fields["DPT"],
+	}
+
+	// Use the protocol names of the ACL rules.
+	switch fields["PROTO"] {
+	case "ICMP":
+		event.Protocol = "icmp4"
+	case "ICMPv6":
+		event.Protocol = "icmp6"
+	default:
+		event.Protocol = strings.ToLower(fields["PROTO"])
+	}
+
+	if event.Protocol == "icmp4" || event.Protocol == "icmp6" {
+		event.ICMPType = fields["TYPE"]
+		event.ICMPCode = fields["CODE"]
+	}
+
+	return name, event, true
+}
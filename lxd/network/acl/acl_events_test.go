@@ -0,0 +1,161 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_parseRuleName(t *testing.T) {
+	tests := []struct {
+		name          string
+		ruleName      string
+		expectedOK    bool
+		expectedID    int64
+		expectedDir   string
+		expectedIndex int
+	}{
+		{
+			name:          "Ingress rule",
+			ruleName:      ruleName(12, "ingress", 3),
+			expectedOK:    true,
+			expectedID:    12,
+			expectedDir:   "ingress",
+			expectedIndex: 3,
+		},
+		{
+			name:          "Egress rule",
+			ruleName:      "lxd_acl1-egress-0",
+			expectedOK:    true,
+			expectedID:    1,
+			expectedDir:   "egress",
+			expectedIndex: 0,
+		},
+		{
+			name:       "Network default rule",
+			ruleName:   "lxdbr0-ingress",
+			expectedOK: false,
+		},
+		{
+			name:       "Port group default rule",
+			ruleName:   "lxd_acl1",
+			expectedOK: false,
+		},
+		{
+			name:       "Invalid direction",
+			ruleName:   "lxd_acl1-forward-0",
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aclID, direction, ruleIndex, ok := parseRuleName(tt.ruleName)
+			if ok != tt.expectedOK {
+				t.Fatalf("Expected ok %v, got %v", tt.expectedOK, ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			if aclID != tt.expectedID || direction != tt.expectedDir || ruleIndex != tt.expectedIndex {
+				t.Errorf("Expected (%d, %q, %d), got (%d, %q, %d)", tt.expectedID, tt.expectedDir, tt.expectedIndex, aclID, direction, ruleIndex)
+			}
+		})
+	}
+}
+
+func Test_firewallParseLogEvent(t *testing.T) {
+	tests := []struct {
+		name         string
+		entry        string
+		expectedOK   bool
+		expectedName string
+		expected     api.EventNetworkACL
+	}{
+		{
+			name:         "TCP packet",
+			entry:        "4,1234,5678901,-;lxd_acl1-ingress-0 IN=eth0 OUT=lxdbr0 MAC=00:16:3e:00:00:01 SRC=10.0.0.1 DST=10.0.0.2 LEN=60 TOS=0x00 PREC=0x00 TTL=63 ID=1 DF PROTO=TCP SPT=40232 DPT=80 WINDOW=64240 RES=0x00 SYN URGP=0\n",
+			expectedOK:   true,
+			expectedName: "lxd_acl1-ingress-0",
+			expected: api.EventNetworkACL{
+				Protocol:        "tcp",
+				Source:          "10.0.0.1",
+				Destination:     "10.0.0.2",
+				SourcePort:      "40232",
+				DestinationPort: "80",
+			},
+		},
+		{
+			name:         "ICMPv6 packet",
+			entry:        "4,1235,5678902,-;lxd_acl2-egress-1 IN=lxdbr0 OUT=eth0 SRC=fd42::2 DST=fd42::1 LEN=104 TC=0 HOPLIMIT=64 FLOWLBL=0 PROTO=ICMPv6 TYPE=128 CODE=0 ID=1 SEQ=1\n",
+			expectedOK:   true,
+			expectedName: "lxd_acl2-egress-1",
+			expected: api.EventNetworkACL{
+				Protocol:    "icmp6",
+				Source:      "fd42::2",
+				Destination: "fd42::1",
+				ICMPType:    "128",
+				ICMPCode:    "0",
+			},
+		},
+		{
+			name:       "Network default rule",
+			entry:      "4,1236,5678903,-;lxdbr0-ingress IN=eth0 OUT=lxdbr0 SRC=10.0.0.1 DST=10.0.0.2 PROTO=UDP SPT=53 DPT=5353\n",
+			expectedOK: false,
+		},
+		{
+			name:       "Unrelated kernel log entry",
+			entry:      "6,1237,5678904,-;lxdbr0: port 1(veth0) entered forwarding state\n",
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, event, ok := firewallParseLogEvent(tt.entry)
+			if ok != tt.expectedOK {
+				t.Fatalf("Expected ok %v, got %v", tt.expectedOK, ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			if name != tt.expectedName {
+				t.Errorf("Expected name %q, got %q", tt.expectedName, name)
+			}
+
+			if event != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, event)
+			}
+		})
+	}
+}
+
+func Test_ovnParseLogEvent(t *testing.T) {
+	message := `name="lxd_acl3-ingress-2", verdict=drop, severity=info, direction=to-lport: udp,vlan_tci=0x0000,dl_src=00:16:3e:00:00:01,dl_dst=00:16:3e:00:00:02,nw_src=10.0.0.1,nw_dst=10.0.0.2,nw_tos=0,nw_ecn=0,nw_ttl=64,tp_src=5353,tp_dst=53`
+
+	name, event, ok := ovnParseLogEvent(message)
+	if !ok {
+		t.Fatal("Expected message to be parsed")
+	}
+
+	if name != "lxd_acl3-ingress-2" {
+		t.Errorf("Expected name %q, got %q", "lxd_acl3-ingress-2", name)
+	}
+
+	expected := api.EventNetworkACL{
+		Action:          "drop",
+		Protocol:        "udp",
+		Source:          "10.0.0.1",
+		Destination:     "10.0.0.2",
+		SourcePort:      "5353",
+		DestinationPort: "53",
+	}
+
+	if event != expected {
+		t.Errorf("Expected %+v, got %+v", expected, event)
+	}
+}
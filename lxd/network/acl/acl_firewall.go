@@ -3,6 +3,10 @@ package acl
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/canonical/lxd/lxd/db"
 	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
@@ -16,30 +20,70 @@ func FirewallApplyACLRules(ctx context.Context, s *state.State, aclProjectName s
 	var dropRules []firewallDrivers.ACLRule
 	var rejectRules []firewallDrivers.ACLRule
 	var allowRules []firewallDrivers.ACLRule
+	var addressSetIDs map[string]int64
+	var addressSetNames []string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		// Get map of address set names to DB IDs (used for generating firewall address set names).
+		addressSetIDs, err = tx.GetNetworkAddressSetIDsByNames(ctx, aclProjectName)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network address sets: %w", err)
+	}
 
 	// convertACLRules converts the ACL rules to Firewall ACL rules.
-	convertACLRules := func(direction string, logPrefix string, rules ...api.NetworkACLRule) error {
+	convertACLRules := func(aclID int64, direction string, rules ...api.NetworkACLRule) error {
 		for ruleIndex, rule := range rules {
 			if rule.State == "disabled" {
 				continue
 			}
 
+			source, sourceMatches, err := firewallPeerSubjectsToSubnets(ctx, s, aclProjectName, rule.Source)
+			if err != nil {
+				return err
+			}
+
+			destination, destinationMatches, err := firewallPeerSubjectsToSubnets(ctx, s, aclProjectName, rule.Destination)
+			if err != nil {
+				return err
+			}
+
+			// Skip rules whose network peer subjects are not peered yet, as they cannot match any traffic.
+			if !sourceMatches || !destinationMatches {
+				continue
+			}
+
+			source, err = addressSetSubjects(source, addressSetIDs)
+			if err != nil {
+				return err
+			}
+
+			destination, err = addressSetSubjects(destination, addressSetIDs)
+			if err != nil {
+				return err
+			}
+
 			firewallACLRule := firewallDrivers.ACLRule{
 				Direction:       direction,
 				Action:          rule.Action,
-				Source:          rule.Source,
-				Destination:     rule.Destination,
+				Source:          source,
+				Destination:     destination,
 				Protocol:        rule.Protocol,
 				SourcePort:      rule.SourcePort,
 				DestinationPort: rule.DestinationPort,
 				ICMPType:        rule.ICMPType,
 				ICMPCode:        rule.ICMPCode,
+				CounterName:     ruleName(aclID, direction, ruleIndex),
 			}
 
 			if rule.State == "logged" {
 				firewallACLRule.Log = true
 				// Max 29 chars.
-				firewallACLRule.LogName = fmt.Sprintf("%s-%s-%d", logPrefix, direction, ruleIndex)
+				firewallACLRule.LogName = firewallACLRule.CounterName
 			}
 
 			switch rule.Action {
@@ -61,12 +105,13 @@ func FirewallApplyACLRules(ctx context.Context, s *state.State, aclProjectName s
 
 	// Load ACLs specified by network.
 	for _, aclName := range shared.SplitNTrimSpace(aclNet.Config["security.acls"], ",", -1, true) {
+		var aclID int64
 		var aclInfo *api.NetworkACL
 
 		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			var err error
 
-			_, aclInfo, err = tx.GetNetworkACL(ctx, aclProjectName, aclName)
+			aclID, aclInfo, err = tx.GetNetworkACL(ctx, aclProjectName, aclName)
 
 			return err
 		})
@@ -74,12 +119,18 @@ func FirewallApplyACLRules(ctx context.Context, s *state.State, aclProjectName s
 			return fmt.Errorf("Failed loading ACL %q for network %q: %w", aclName, aclNet.Name, err)
 		}
 
-		err = convertACLRules("ingress", logPrefix, aclInfo.Ingress...)
+		for _, setName := range aclAddressSetNames(aclInfo) {
+			if !slices.Contains(addressSetNames, setName) {
+				addressSetNames = append(addressSetNames, setName)
+			}
+		}
+
+		err = convertACLRules(aclID, "ingress", aclInfo.Ingress...)
 		if err != nil {
 			return fmt.Errorf("Failed converting ACL %q ingress rules for network %q: %w", aclInfo.Name, aclNet.Name, err)
 		}
 
-		err = convertACLRules("egress", logPrefix, aclInfo.Egress...)
+		err = convertACLRules(aclID, "egress", aclInfo.Egress...)
 		if err != nil {
 			return fmt.Errorf("Failed converting ACL %q egress rules for network %q: %w", aclInfo.Name, aclNet.Name, err)
 		}
@@ -108,7 +159,21 @@ func FirewallApplyACLRules(ctx context.Context, s *state.State, aclProjectName s
 		LogName:   logPrefix + "-ingress",
 	})
 
-	return s.Firewall.NetworkApplyACLRules(aclNet.Name, rules)
+	// The address sets must exist before the rules referencing them are applied.
+	err = firewallApplyAddressSets(ctx, s, aclProjectName, addressSetNames)
+	if err != nil {
+		return fmt.Errorf("Failed applying address sets for network %q: %w", aclNet.Name, err)
+	}
+
+	err = s.Firewall.NetworkApplyACLRules(aclNet.Name, rules)
+	if err != nil {
+		return err
+	}
+
+	logged := slices.ContainsFunc(rules, func(rule firewallDrivers.ACLRule) bool { return rule.Log })
+	FirewallLogMonitorUpdate(s, aclNet.Name, logged)
+
+	return nil
 }
 
 // firewallACLDefaults returns the action and logging mode to use for the specified direction's default rule.
@@ -128,3 +193,122 @@ func firewallACLDefaults(netConfig map[string]string, direction string) (string,
 
 	return defaults[fmt.Sprintf("security.acls.default.%s.action", direction)], shared.IsTrue(defaults[fmt.Sprintf("security.acls.default.%s.logged", direction)])
 }
+
+// FirewallValidateACLs checks that the named ACLs can be applied to the firewall of a bridge network.
+func FirewallValidateACLs(ctx context.Context, s *state.State, projectName string, aclNames ...string) error {
+	for _, aclName := range aclNames {
+		var aclInfo *api.NetworkACL
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			_, aclInfo, err = tx.GetNetworkACL(ctx, projectName, aclName)
+
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed loading ACL %q: %w", aclName, err)
+		}
+
+		err = firewallValidateAddressSets(s, aclInfo)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// firewallValidateAddressSets checks that the firewall supports the address sets used by the ACL, as the xtables
+// firewall driver has no equivalent of nftables named sets.
+func firewallValidateAddressSets(s *state.State, info *api.NetworkACL) error {
+	if s.Firewall.String() != "xtables" || len(aclAddressSetNames(info)) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("Network ACL %q uses address sets, which aren't supported by the xtables firewall driver", info.Name)
+}
+
+// firewallPeerSubjectsToSubnets replaces the network peer subjects ("@<network>/<peer>") of a comma separated list
+// of rule subjects with the subnets of the bridge networks they are peered with.
+// Returns false if the list only contains network peer subjects and none of them is mutually peered.
+func firewallPeerSubjectsToSubnets(ctx context.Context, s *state.State, projectName string, subjects string) (string, bool, error) {
+	if subjects == "" {
+		return "", true, nil
+	}
+
+	resolved := []string{}
+	for _, subject := range shared.SplitNTrimSpace(subjects, ",", -1, true) {
+		peerRef, isNamed := strings.CutPrefix(subject, "@")
+		if !isNamed {
+			resolved = append(resolved, subject)
+			continue
+		}
+
+		networkName, peerName, found := strings.Cut(peerRef, "/")
+		if !found {
+			return "", false, fmt.Errorf("Unsupported subject %q", subject)
+		}
+
+		var targetNet *api.Network
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			networkID, err := tx.GetNetworkID(ctx, projectName, networkName)
+			if err != nil {
+				return err
+			}
+
+			_, peer, err := tx.GetNetworkPeer(ctx, networkID, peerName)
+			if err != nil {
+				return err
+			}
+
+			if peer.Status != api.NetworkStatusCreated {
+				return nil
+			}
+
+			_, targetNet, _, err = tx.GetNetworkInAnyState(ctx, peer.TargetProject, peer.TargetNetwork)
+
+			return err
+		})
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return "", false, fmt.Errorf("Failed loading network peer for subject %q: %w", subject, err)
+		}
+
+		if targetNet == nil || targetNet.Type != "bridge" {
+			continue
+		}
+
+		for _, subnet := range PeerSubnets(targetNet.Config) {
+			resolved = append(resolved, subnet.String())
+		}
+	}
+
+	if len(resolved) == 0 {
+		return "", false, nil
+	}
+
+	return strings.Join(resolved, ","), true, nil
+}
+
+// PeerSubnets returns the subnets of a bridge network that its peers are allowed to reach, given its config.
+// These are the subnets of the bridge addresses and the routes of the network.
+func PeerSubnets(netConfig map[string]string) []*net.IPNet {
+	var subnets []*net.IPNet
+
+	for _, keyPrefix := range []string{"ipv4", "ipv6"} {
+		_, subnet, err := net.ParseCIDR(netConfig[keyPrefix+".address"])
+		if err == nil {
+			subnets = append(subnets, subnet)
+		}
+
+		for _, route := range shared.SplitNTrimSpace(netConfig[keyPrefix+".routes"], ",", -1, true) {
+			_, subnet, err := net.ParseCIDR(route)
+			if err == nil {
+				subnets = append(subnets, subnet)
+			}
+		}
+	}
+
+	return subnets
+}
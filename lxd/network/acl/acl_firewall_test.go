@@ -0,0 +1,41 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerSubnets(t *testing.T) {
+	tests := []struct {
+		name      string
+		netConfig map[string]string
+		expected  []string
+	}{
+		{
+			name:      "No addresses",
+			netConfig: map[string]string{"ipv4.address": "none", "ipv6.address": "none"},
+			expected:  nil,
+		},
+		{
+			name: "Addresses and routes",
+			netConfig: map[string]string{
+				"ipv4.address": "10.0.0.1/24",
+				"ipv4.routes":  "192.0.2.0/24, 198.51.100.0/24",
+				"ipv6.address": "fd42::1/64",
+			},
+			expected: []string{"10.0.0.0/24", "192.0.2.0/24", "198.51.100.0/24", "fd42::/64"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subnets []string
+			for _, subnet := range PeerSubnets(tt.netConfig) {
+				subnets = append(subnets, subnet.String())
+			}
+
+			assert.Equal(t, tt.expected, subnets)
+		})
+	}
+}
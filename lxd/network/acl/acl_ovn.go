@@ -92,13 +92,20 @@ func OVNEnsureACLs(ctx context.Context, s *state.State, l logger.Logger, client
 
 	var err error
 	var projectID int64
+	var addressSetIDs map[string]int64
 	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		projectID, err = cluster.GetProjectID(ctx, tx.Tx(), aclProjectName)
 		if err != nil {
 			return fmt.Errorf("Failed getting project ID for project %q: %w", aclProjectName, err)
 		}
 
-		return err
+		// Get map of address set names to DB IDs (used for generating OVN address set names).
+		addressSetIDs, err = tx.GetNetworkAddressSetIDsByNames(ctx, aclProjectName)
+		if err != nil {
+			return fmt.Errorf("Failed getting network address sets for project %q: %w", aclProjectName, err)
+		}
+
+		return nil
 	})
 	if err != nil {
 		return nil, err
@@ -236,6 +243,27 @@ func OVNEnsureACLs(ctx context.Context, s *state.State, l logger.Logger, client
 		}
 	}
 
+	// Ensure the address sets referenced by the rules we are going to apply exist and are up to date.
+	addressSetNames := []string{}
+	for _, aclStatuses := range [][]aclStatus{createACLPortGroups, existingACLPortGroups} {
+		for _, aclStatus := range aclStatuses {
+			if aclStatus.aclInfo == nil {
+				continue
+			}
+
+			for _, setName := range aclAddressSetNames(aclStatus.aclInfo) {
+				if !slices.Contains(addressSetNames, setName) {
+					addressSetNames = append(addressSetNames, setName)
+				}
+			}
+		}
+	}
+
+	err = ovnApplyAddressSets(ctx, s, l, client, aclProjectName, addressSetNames)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the needed port groups and then apply ACL rules to new port groups.
 	for _, aclStatus := range createACLPortGroups {
 		portGroupName := OVNACLPortGroupName(aclNameIDs[aclStatus.name])
@@ -263,7 +291,7 @@ func OVNEnsureACLs(ctx context.Context, s *state.State, l logger.Logger, client
 		}
 
 		// Now apply our ACL rules to port group (and any per-ACL-per-network port groups needed).
-		err = ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, aclNets, peerTargetNetIDs)
+		err = ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, addressSetIDs, aclNets, peerTargetNetIDs)
 		if err != nil {
 			return nil, fmt.Errorf("Failed applying ACL rules to port group %q for security ACL %q setup: %w", portGroupName, aclStatus.name, err)
 		}
@@ -293,7 +321,7 @@ func OVNEnsureACLs(ctx context.Context, s *state.State, l logger.Logger, client
 		if aclStatus.aclInfo != nil {
 			l.Debug("Applying ACL rules to OVN port group", logger.Ctx{"networkACL": aclStatus.name, "portGroup": portGroupName})
 
-			err := ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, aclNets, peerTargetNetIDs)
+			err := ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, addressSetIDs, aclNets, peerTargetNetIDs)
 			if err != nil {
 				return nil, fmt.Errorf("Failed applying ACL rules to port group %q for security ACL %q setup: %w", portGroupName, aclStatus.name, err)
 			}
@@ -305,6 +333,35 @@ func OVNEnsureACLs(ctx context.Context, s *state.State, l logger.Logger, client
 	return cleanup, nil
 }
 
+// ovnApplyAddressSets applies the addresses of the specified address sets to OVN, creating the address sets if needed.
+func ovnApplyAddressSets(ctx context.Context, s *state.State, l logger.Logger, client *openvswitch.OVN, projectName string, setNames []string) error {
+	for _, setName := range setNames {
+		var setID int64
+		var setInfo *api.NetworkAddressSet
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			setID, setInfo, err = tx.GetNetworkAddressSet(ctx, projectName, setName)
+
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed loading network address set %q: %w", setName, err)
+		}
+
+		addrSetPrefix := openvswitch.OVNAddressSet(addressSetName(setID))
+		l.Debug("Applying address set to OVN", logger.Ctx{"networkAddressSet": setName, "addressSet": addrSetPrefix})
+
+		err = client.AddressSetUpdate(addrSetPrefix, addressSetAddresses(ctx, l, setInfo.Addresses, false)...)
+		if err != nil {
+			return fmt.Errorf("Failed applying address set %q: %w", setName, err)
+		}
+	}
+
+	return nil
+}
+
 // ovnAddReferencedACLs adds to the referencedACLNames any ACLs referenced by the rules in the supplied ACL.
 func ovnAddReferencedACLs(info *api.NetworkACL, referencedACLNames map[string]struct{}) {
 	addACLNamesFrom := func(ruleSubjects []string) {
@@ -322,6 +379,10 @@ func ovnAddReferencedACLs(info *api.NetworkACL, referencedACLNames map[string]st
 				continue // Skip if the subject is an IP CIDR or IP range.
 			}
 
+			if strings.HasPrefix(subject, addressSetSubjectPrefix) {
+				continue // Skip if the subject is an address set.
+			}
+
 			// Anything else must be a referenced ACL name.
 			// Record newly seen referenced ACL into authoritative list.
 			referencedACLNames[subject] = struct{}{}
@@ -338,7 +399,7 @@ func ovnAddReferencedACLs(info *api.NetworkACL, referencedACLNames map[string]st
 }
 
 // ovnApplyToPortGroup applies the rules in the specified ACL to the specified port group.
-func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.NetworkACL, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, aclNets map[string]NetworkACLUsage, peerTargetNetIDs map[db.NetworkPeer]int64) error {
+func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.NetworkACL, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, addressSetIDs map[string]int64, aclNets map[string]NetworkACLUsage, peerTargetNetIDs map[db.NetworkPeer]int64) error {
 	// Create slice for port group rules that has the capacity for ingress and egress rules, plus default rule.
 	portGroupRules := make([]openvswitch.OVNACLRule, 0, len(aclInfo.Ingress)+len(aclInfo.Egress)+1)
 	networkRules := make([]openvswitch.OVNACLRule, 0)
@@ -351,14 +412,15 @@ func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.
 				continue
 			}
 
-			ovnACLRule, networkSpecific, networkPeers, err := ovnRuleCriteriaToOVNACLRule(direction, &rule, portGroupName, aclNameIDs, peerTargetNetIDs)
+			ovnACLRule, networkSpecific, networkPeers, err := ovnRuleCriteriaToOVNACLRule(direction, &rule, portGroupName, aclNameIDs, addressSetIDs, peerTargetNetIDs)
 			if err != nil {
 				return err
 			}
 
+			// Always name the rule so that its counters can be found, even if it isn't logged.
+			ovnACLRule.LogName = fmt.Sprintf("%s-%s-%d", portGroupName, direction, ruleIndex)
 			if rule.State == "logged" {
 				ovnACLRule.Log = true
-				ovnACLRule.LogName = fmt.Sprintf("%s-%s-%d", portGroupName, direction, ruleIndex)
 			}
 
 			if networkSpecific {
@@ -434,7 +496,7 @@ func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.
 
 // ovnRuleCriteriaToOVNACLRule converts a LXD ACL rule into an OVNACLRule for an OVN port group or network.
 // Returns a bool indicating if any of the rule subjects are network specific.
-func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64) (openvswitch.OVNACLRule, bool, []db.NetworkPeer, error) {
+func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, addressSetIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64) (openvswitch.OVNACLRule, bool, []db.NetworkPeer, error) {
 	networkSpecific := false
 	networkPeersNeeded := make([]db.NetworkPeer, 0)
 	portGroupRule := openvswitch.OVNACLRule{
@@ -468,7 +530,7 @@ func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, por
 
 	// Add subject filters.
 	if rule.Source != "" {
-		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("src", aclNameIDs, peerTargetNetIDs, shared.SplitNTrimSpace(rule.Source, ",", -1, false)...)
+		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("src", aclNameIDs, addressSetIDs, peerTargetNetIDs, shared.SplitNTrimSpace(rule.Source, ",", -1, false)...)
 		if err != nil {
 			return openvswitch.OVNACLRule{}, false, nil, err
 		}
@@ -482,7 +544,7 @@ func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, por
 	}
 
 	if rule.Destination != "" {
-		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("dst", aclNameIDs, peerTargetNetIDs, shared.SplitNTrimSpace(rule.Destination, ",", -1, false)...)
+		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("dst", aclNameIDs, addressSetIDs, peerTargetNetIDs, shared.SplitNTrimSpace(rule.Destination, ",", -1, false)...)
 		if err != nil {
 			return openvswitch.OVNACLRule{}, false, nil, err
 		}
@@ -543,13 +605,28 @@ func ovnRulePortToOVNACLMatch(protocol string, direction string, portCriteria ..
 
 // ovnRuleSubjectToOVNACLMatch converts direction (src/dst) and subject criteria list into an OVN match statement.
 // Returns a bool indicating if any of the subjects are network specific.
-func ovnRuleSubjectToOVNACLMatch(direction string, aclNameIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64, subjectCriteria ...string) (string, bool, []db.NetworkPeer, error) {
+func ovnRuleSubjectToOVNACLMatch(direction string, aclNameIDs map[string]int64, addressSetIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64, subjectCriteria ...string) (string, bool, []db.NetworkPeer, error) {
 	fieldParts := make([]string, 0, len(subjectCriteria))
 	networkSpecific := false
 	networkPeersNeeded := make([]db.NetworkPeer, 0)
 
 	// For each criterion check if value looks like an IP range or IP CIDR, and if not use it as an ACL name.
 	for _, subjectCriterion := range subjectCriteria {
+		// Subject is an address set name. Convert to address set criteria.
+		setName, isAddressSet := strings.CutPrefix(subjectCriterion, addressSetSubjectPrefix)
+		if isAddressSet {
+			setID, found := addressSetIDs[setName]
+			if !found {
+				return "", false, nil, fmt.Errorf("Cannot find address set ID for %q", subjectCriterion)
+			}
+
+			addrSetPrefix := addressSetName(setID)
+
+			fieldParts = append(fieldParts, fmt.Sprintf("ip6.%s == $%s_ip6 || ip4.%s == $%s_ip4", direction, addrSetPrefix, direction, addrSetPrefix))
+
+			continue
+		}
+
 		if validate.IsNetworkRange(subjectCriterion) == nil {
 			firstIP, lastIP, found := strings.Cut(subjectCriterion, "-")
 			if !found {
@@ -1080,15 +1157,7 @@ func ovnParseLogEntry(logline string, syslogTimestamp string, prefix string) str
 	}
 
 	// Parse the ACL log entry.
-	aclEntry := map[string]string{}
-	for _, entry := range shared.SplitNTrimSpace(fields[4], ",", -1, true) {
-		key, value, found := strings.Cut(entry, "=")
-		if !found {
-			continue
-		}
-
-		aclEntry[strings.Trim(key, "\"")] = strings.Trim(value, "\"")
-	}
+	aclEntry := ovnParseLogMessage(fields[4])
 
 	// Filter for our ACL.
 	if !strings.HasPrefix(aclEntry["name"], prefix) {
@@ -1160,6 +1229,21 @@ func ovnParseLogEntry(logline string, syslogTimestamp string, prefix string) str
 	return string(out)
 }
 
+// ovnParseLogMessage parses the key/value pairs of the message of an OVN ACL log entry.
+func ovnParseLogMessage(message string) map[string]string {
+	aclEntry := map[string]string{}
+	for _, entry := range shared.SplitNTrimSpace(message, ",", -1, true) {
+		key, value, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+
+		aclEntry[strings.Trim(key, "\"")] = strings.Trim(value, "\"")
+	}
+
+	return aclEntry
+}
+
 // ovnParseLogEntriesFromJournald reads the OVN log entries from the systemd journal and returns them as a list of string entries.
 // Also, we chose to output the last 1000 entries to avoid overloading the system with too many log entries.
 func ovnParseLogEntriesFromJournald(ctx context.Context, systemdUnitName string, filter string) ([]string, error) {
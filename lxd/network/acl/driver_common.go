@@ -284,12 +284,18 @@ func (d *common) validateRule(ctx context.Context, direction ruleDirection, rule
 	}
 
 	var acls map[string]int64
+	var addressSetNames []string
 
 	err := d.state.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
 		// Get map of ACL names to DB IDs (used for generating OVN port group names).
 		acls, err = tx.GetNetworkACLIDsByNames(ctx, d.Project())
+		if err != nil {
+			return err
+		}
+
+		addressSetNames, err = tx.GetNetworkAddressSets(ctx, d.Project())
 
 		return err
 	})
@@ -310,7 +316,7 @@ func (d *common) validateRule(ctx context.Context, direction ruleDirection, rule
 
 	// Validate Source field.
 	if rule.Source != "" {
-		srcHasName, srcHasIPv4, srcHasIPv6, err = d.validateRuleSubjects("Source", direction, shared.SplitNTrimSpace(rule.Source, ",", -1, false), validSubjectNames)
+		srcHasName, srcHasIPv4, srcHasIPv6, err = d.validateRuleSubjects("Source", direction, shared.SplitNTrimSpace(rule.Source, ",", -1, false), validSubjectNames, addressSetNames)
 		if err != nil {
 			return fmt.Errorf("Invalid Source: %w", err)
 		}
@@ -318,7 +324,7 @@ func (d *common) validateRule(ctx context.Context, direction ruleDirection, rule
 
 	// Validate Destination field.
 	if rule.Destination != "" {
-		dstHasName, dstHasIPv4, dstHasIPv6, err = d.validateRuleSubjects("Destination", direction, shared.SplitNTrimSpace(rule.Destination, ",", -1, false), validSubjectNames)
+		dstHasName, dstHasIPv4, dstHasIPv6, err = d.validateRuleSubjects("Destination", direction, shared.SplitNTrimSpace(rule.Destination, ",", -1, false), validSubjectNames, addressSetNames)
 		if err != nil {
 			return fmt.Errorf("Invalid Destination: %w", err)
 		}
@@ -433,9 +439,10 @@ func (d *common) validateRule(ctx context.Context, direction ruleDirection, rule
 }
 
 // validateRuleSubjects checks that the source or destination subjects for a rule are valid.
-// Accepts a validSubjectNames list of valid ACL or special classifier names.
-// Returns whether the subjects include names, IPv4 and IPv6 addresses respectively.
-func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection, subjects []string, validSubjectNames []string) (hasName bool, hasIPv4 bool, hasIPv6 bool, err error) {
+// Accepts a validSubjectNames list of valid ACL or special classifier names, and a validAddressSetNames list of
+// address set names that can be referenced as "$<name>" in any field and direction.
+// Returns whether the subjects include names, IPv4 and IPv6 addresses respectively. Address sets count as names.
+func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection, subjects []string, validSubjectNames []string, validAddressSetNames []string) (hasName bool, hasIPv4 bool, hasIPv6 bool, err error) {
 	// Check if named subjects are allowed in field/direction combination.
 	allowSubjectNames := (fieldName == "Source" && direction == ruleDirectionIngress) || (fieldName == "Destination" && direction == ruleDirectionEgress)
 
@@ -514,6 +521,16 @@ func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection,
 			}
 		}
 
+		// Check if it is a reference to an existing address set.
+		setName, isAddressSet := strings.CutPrefix(subject, addressSetSubjectPrefix)
+		if isAddressSet {
+			if slices.Contains(validAddressSetNames, setName) {
+				return 0, nil // Found valid subject.
+			}
+
+			return 0, fmt.Errorf("Network address set %q does not exist", setName)
+		}
+
 		// Check if it looks like a network peer connection name.
 		if strings.HasPrefix(subject, "@") {
 			if allowSubjectNames {
@@ -612,6 +629,14 @@ func (d *common) Update(ctx context.Context, config *api.NetworkACLPut, clientTy
 		}
 	}
 
+	// Check the firewall of this member supports the updated rules before applying them to the bridge networks.
+	if len(aclNets) > 0 {
+		err = firewallValidateAddressSets(d.state, d.info)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Apply ACL changes to non-OVN networks on this member.
 	for _, aclNet := range aclNets {
 		err = FirewallApplyACLRules(ctx, d.state, d.projectName, aclNet)
@@ -838,3 +863,70 @@ func (d *common) GetLog(ctx context.Context, clientType request.ClientType) (str
 
 	return strings.Join(logEntries, "\n") + "\n", nil
 }
+
+// GetState gets the ACL state, including the counters of its rules.
+func (d *common) GetState(ctx context.Context, clientType request.ClientType) (*api.NetworkACLState, error) {
+	// Get a list of networks that are using this ACL (either directly or indirectly via a NIC).
+	aclNets := map[string]NetworkACLUsage{}
+	err := NetworkUsage(ctx, d.state, d.projectName, []string{d.info.Name}, aclNets)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting ACL network usage: %w", err)
+	}
+
+	counters, err := localRuleCounters(d.state, d.logger, d.id, aclNets)
+	if err != nil {
+		return nil, err
+	}
+
+	aclState := &api.NetworkACLState{
+		Egress:  make([]api.NetworkACLRuleCounters, len(d.info.Egress)),
+		Ingress: make([]api.NetworkACLRuleCounters, len(d.info.Ingress)),
+	}
+
+	for i := range aclState.Egress {
+		aclState.Egress[i] = counters[ruleName(d.id, string(ruleDirectionEgress), i)]
+	}
+
+	for i := range aclState.Ingress {
+		aclState.Ingress[i] = counters[ruleName(d.id, string(ruleDirectionIngress), i)]
+	}
+
+	// Aggregates the counters from the rest of the cluster.
+	if clientType == request.ClientTypeNormal && len(aclNets) > 0 {
+		// Setup notifier to reach the rest of the cluster.
+		notifier, err := cluster.NewNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return nil, err
+		}
+
+		mu := sync.Mutex{}
+		err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+			memberState, err := client.UseProject(d.projectName).GetNetworkACLState(d.info.Name)
+			if err != nil {
+				return err
+			}
+
+			// Prevent concurrent writes to the counters.
+			mu.Lock()
+			defer mu.Unlock()
+
+			sumCounters := func(total []api.NetworkACLRuleCounters, memberCounters []api.NetworkACLRuleCounters) {
+				// Only sum the rules known to both members, in case of the ACL being updated meanwhile.
+				for i := range min(len(total), len(memberCounters)) {
+					total[i].Packets += memberCounters[i].Packets
+					total[i].Bytes += memberCounters[i].Bytes
+				}
+			}
+
+			sumCounters(aclState.Egress, memberState.Egress)
+			sumCounters(aclState.Ingress, memberState.Ingress)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return aclState, nil
+}
@@ -2,6 +2,7 @@ package network
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"maps"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"slices"
@@ -29,6 +31,7 @@ import (
 	"github.com/canonical/lxd/lxd/dnsmasq"
 	"github.com/canonical/lxd/lxd/dnsmasq/dhcpalloc"
 	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
+	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/ip"
 	"github.com/canonical/lxd/lxd/network/acl"
@@ -71,6 +74,8 @@ func (n *bridge) DBType() db.NetworkType {
 func (n *bridge) Info() Info {
 	info := n.common.Info()
 	info.AddressForwards = true
+	info.LoadBalancers = true
+	info.Peering = true
 
 	return info
 }
@@ -288,7 +293,7 @@ func (n *bridge) Validate(config map[string]string) error {
 		// The default value varies depending on whether the bridge uses a tunnel or a fan setup.
 		// ---
 		//  type: integer
-		//  defaultdesc: `1400` when tunnels are configured, otherwise `1500` if `bridge.mode=standard` or `1450` if `bridge.mode=fan`
+		//  defaultdesc: `1350` when a `wireguard` tunnel is configured, `1400` when other tunnels are configured, otherwise `1500` if `bridge.mode=standard` or `1450` if `bridge.mode=fan`
 		//  shortdesc: Bridge MTU
 		//  scope: global
 		"bridge.mtu": validate.Optional(validate.IsNetworkMTU),
@@ -677,12 +682,13 @@ func (n *bridge) Validate(config map[string]string) error {
 			switch tunnelKey {
 			case "protocol":
 				// lxdmeta:generate(entities=network-bridge; group=network-conf; key=tunnel.NAME.protocol)
-				// Possible values are `vxlan` and `gre`.
+				// Possible values are `vxlan`, `gre` and `wireguard`.
+				// A `wireguard` tunnel carries a `vxlan` tunnel encrypted with WireGuard between all its peers.
 				// ---
 				//  type: string
 				//  condition: standard mode
 				//  shortdesc: Tunneling protocol
-				rules[k] = validate.Optional(validate.IsOneOf("gre", "vxlan"))
+				rules[k] = validate.Optional(validate.IsOneOf("gre", "vxlan", "wireguard"))
 			case "local":
 				// lxdmeta:generate(entities=network-bridge; group=network-conf; key=tunnel.NAME.local)
 				//
@@ -694,21 +700,47 @@ func (n *bridge) Validate(config map[string]string) error {
 				rules[k] = validate.Optional(validate.IsNetworkAddress)
 			case "remote":
 				// lxdmeta:generate(entities=network-bridge; group=network-conf; key=tunnel.NAME.remote)
-				//
+				// For a `wireguard` tunnel, this is the address of a peer that isn't managed by this cluster,
+				// whose public key is set in {config:option}`network-bridge-network-conf:tunnel.NAME.remote_public_key`.
 				// ---
 				//  type: string
-				//  condition: `gre` or `vxlan`
-				//  required: not required for multicast `vxlan`
+				//  condition: `gre`, `vxlan` or `wireguard`
+				//  required: not required for multicast `vxlan` or `wireguard`
 				//  shortdesc: Remote address for the tunnel
 				rules[k] = validate.Optional(validate.IsNetworkAddress)
+			case "remote_public_key":
+				// lxdmeta:generate(entities=network-bridge; group=network-conf; key=tunnel.NAME.remote_public_key)
+				// The public key of the remote peer is shown by `lxc network info` on the remote side.
+				// ---
+				//  type: string
+				//  condition: `wireguard`
+				//  shortdesc: Public key of the remote peer
+				rules[k] = validate.Optional(wireguardValidKey)
+			case "cluster_link":
+				// lxdmeta:generate(entities=network-bridge; group=network-conf; key=tunnel.NAME.cluster_link)
+				// All members of the linked cluster on which a network with the same name has a `wireguard` tunnel are added as peers.
+				// ---
+				//  type: string
+				//  condition: `wireguard`
+				//  shortdesc: Cluster link used to discover the remote peers
+				rules[k] = validate.Optional(validate.IsAny)
+			case "key_rotation":
+				// lxdmeta:generate(entities=network-bridge; group=network-conf; key=tunnel.NAME.key_rotation)
+				// Specify the interval in hours. Set to `0` to disable the rotation.
+				// ---
+				//  type: integer
+				//  condition: `wireguard`
+				//  defaultdesc: `0` if {config:option}`network-bridge-network-conf:tunnel.NAME.remote_public_key` is set, otherwise `720`
+				//  shortdesc: Interval at which the WireGuard key pair is rotated
+				rules[k] = validate.Optional(validate.IsUint32)
 			case "port":
 				// lxdmeta:generate(entities=network-bridge; group=network-conf; key=tunnel.NAME.port)
-				//
+				// For a `wireguard` tunnel, this is the UDP port WireGuard listens on, which must be the same on all peers.
 				// ---
 				//  type: integer
-				//  condition: `vxlan`
-				//  defaultdesc: `0`
-				//  shortdesc: Specific port to use for the `vxlan` tunnel
+				//  condition: `vxlan` or `wireguard`
+				//  defaultdesc: `0` for `vxlan`, `51820` for `wireguard`
+				//  shortdesc: Specific port to use for the `vxlan` or `wireguard` tunnel
 				rules[k] = networkValidPort
 			case "group":
 				// lxdmeta:generate(entities=network-bridge; group=network-conf; key=tunnel.NAME.group)
@@ -724,7 +756,7 @@ func (n *bridge) Validate(config map[string]string) error {
 				//
 				// ---
 				//  type: integer
-				//  condition: `vxlan`
+				//  condition: `vxlan` or `wireguard`
 				//  shortdesc: `0`
 				//  shortdesc: Specific tunnel ID to use for the `vxlan` tunnel
 				rules[k] = validate.Optional(validate.IsInt64)
@@ -819,12 +851,20 @@ func (n *bridge) Validate(config map[string]string) error {
 
 			if config["bridge.mode"] == "fan" && mtu > 1450 {
 				return errors.New("Maximum MTU for a FAN bridge is 1450")
+			} else if n.wireguardTunnel(config) != "" && mtu > 1350 {
+				return errors.New("Maximum MTU for a bridge with a WireGuard tunnel is 1350")
 			} else if n.hasTunnels(config) && mtu > 1400 {
 				return errors.New("Maximum MTU for a bridge with tunnels is 1400")
 			}
 		}
 	}
 
+	// Validate the WireGuard tunnel.
+	err = n.wireguardValidate(config)
+	if err != nil {
+		return err
+	}
+
 	// Check using same MAC address on every cluster node is safe.
 	if config["bridge.hwaddr"] != "" {
 		err = n.checkClusterWideMACSafe(config)
@@ -899,7 +939,14 @@ func (n *bridge) Validate(config map[string]string) error {
 
 	// Check Security ACLs are supported and exist.
 	if config["security.acls"] != "" {
-		err = acl.Exists(context.TODO(), n.state, n.Project(), shared.SplitNTrimSpace(config["security.acls"], ",", -1, true)...)
+		aclNames := shared.SplitNTrimSpace(config["security.acls"], ",", -1, true)
+
+		err = acl.Exists(context.TODO(), n.state, n.Project(), aclNames...)
+		if err != nil {
+			return err
+		}
+
+		err = acl.FirewallValidateACLs(context.TODO(), n.state, n.Project(), aclNames...)
 		if err != nil {
 			return err
 		}
@@ -1324,6 +1371,8 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 
 		bridge.MTU = uint32(mtuInt)
+	} else if n.wireguardTunnel(n.config) != "" {
+		bridge.MTU = 1350
 	} else if len(tunnels) > 0 {
 		bridge.MTU = 1400
 	} else if n.config["bridge.mode"] == "fan" {
@@ -1458,6 +1507,9 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Stop refreshing the WireGuard peers, the tunnel is recreated below if still configured.
+	wireguardMonitorStop(n.name)
+
 	// Cleanup any existing tunnel device.
 	for _, iface := range ifaces {
 		if strings.HasPrefix(iface.Name, n.name+"-") {
@@ -2077,6 +2129,11 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 			if err != nil {
 				return err
 			}
+		} else if tunProtocol == "wireguard" {
+			err = n.wireguardSetup(tunnel)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Bridge it and bring up.
@@ -2104,6 +2161,14 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 	}
 
+	// Start discovering the peers of the WireGuard tunnel.
+	wireguardTunnel := n.wireguardTunnel(n.config)
+	if wireguardTunnel != "" {
+		wireguardMonitorStart(n.name, func(m *wireguardMonitor) {
+			n.wireguardRefresh(wireguardTunnel, m)
+		})
+	}
+
 	// Generate and load apparmor profiles.
 	err = apparmor.NetworkLoad(n.state.OS, n)
 	if err != nil {
@@ -2175,15 +2240,7 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 	}
 
 	if fwOpts.ACL {
-		aclNet := acl.NetworkACLUsage{
-			Name:   n.Name(),
-			Type:   n.Type(),
-			ID:     n.ID(),
-			Config: n.Config(),
-		}
-
-		n.logger.Debug("Applying up firewall ACLs")
-		err = acl.FirewallApplyACLRules(context.TODO(), n.state, n.Project(), aclNet)
+		err = n.aclSetupFirewall()
 		if err != nil {
 			return err
 		}
@@ -2195,6 +2252,23 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Setup network load balancers.
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	// Setup network peers, and refresh the peers of the networks linked to this one as its subnets may have changed.
+	err = n.peerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	err = n.peerRefreshLinkedNetworks()
+	if err != nil {
+		return err
+	}
+
 	nodeEvacuated := n.state.DB.Cluster.LocalNodeIsEvacuated()
 
 	// Setup BGP.
@@ -2223,6 +2297,15 @@ func (n *bridge) Stop() error {
 		return err
 	}
 
+	// Stop probing the load balancer targets.
+	loadBalancerHealthMonitorStop(n.name)
+
+	// Stop refreshing the WireGuard peers.
+	wireguardMonitorStop(n.name)
+
+	// Stop reading the kernel log for the logged ACL rules of the network.
+	acl.FirewallLogMonitorUpdate(n.state, n.name, false)
+
 	// Kill any existing dnsmasq and forkdns daemon for this network
 	err = dnsmasq.Kill(n.name, false)
 	if err != nil {
@@ -2548,790 +2631,2500 @@ func (n *bridge) hasTunnels(config map[string]string) bool {
 	return false
 }
 
-// bootRoutesV4 returns a list of IPv4 boot routes on the network's device.
-func (n *bridge) bootRoutesV4() ([]string, error) {
-	r := &ip.Route{
-		DevName: n.name,
-		Proto:   "boot",
-		Family:  ip.FamilyV4,
-	}
+// wireguardTunnel returns the name of the WireGuard tunnel in the given config or an empty string if there is none.
+func (n *bridge) wireguardTunnel(config map[string]string) string {
+	for k, v := range config {
+		rest, found := strings.CutPrefix(k, "tunnel.")
+		if !found {
+			continue
+		}
 
-	routes, err := r.Show()
-	if err != nil {
-		return nil, err
+		name, key, _ := strings.Cut(rest, ".")
+		if key == "protocol" && v == "wireguard" {
+			return name
+		}
 	}
 
-	return routes, nil
+	return ""
 }
 
-// bootRoutesV6 returns a list of IPv6 boot routes on the network's device.
-func (n *bridge) bootRoutesV6() ([]string, error) {
-	r := &ip.Route{
-		DevName: n.name,
-		Proto:   "boot",
-		Family:  ip.FamilyV6,
+// wireguardValidate checks the WireGuard tunnel settings of the given config.
+func (n *bridge) wireguardValidate(config map[string]string) error {
+	tunnels := []string{}
+	for k, v := range config {
+		rest, found := strings.CutPrefix(k, "tunnel.")
+		if !found {
+			continue
+		}
+
+		name, key, _ := strings.Cut(rest, ".")
+		if key == "protocol" && v == "wireguard" {
+			tunnels = append(tunnels, name)
+		}
 	}
 
-	routes, err := r.Show()
-	if err != nil {
-		return nil, err
+	if len(tunnels) == 0 {
+		return nil
 	}
 
-	return routes, nil
-}
+	if len(tunnels) > 1 {
+		return errors.New("Only one WireGuard tunnel can be configured per network")
+	}
 
-// applyBootRoutesV4 applies a list of IPv4 boot routes to the network's device.
-func (n *bridge) applyBootRoutesV4(routes []string) {
-	for _, route := range routes {
-		r := &ip.Route{
-			DevName: n.name,
-			Proto:   "boot",
-			Family:  ip.FamilyV4,
-		}
+	tunnel := tunnels[0]
+	getConfig := func(key string) string {
+		return config[fmt.Sprintf("tunnel.%s.%s", tunnel, key)]
+	}
 
-		err := r.Replace(strings.Fields(route))
-		if err != nil {
-			// If it fails, then we can't stop as the route has already gone, so just log and continue.
-			n.logger.Error("Failed restoring route", logger.Ctx{"err": err})
-		}
+	if len(n.name)+len(tunnel) > 11 {
+		return fmt.Errorf("Network name too long for WireGuard tunnel interface: %s", wireguardDevName(n.name, tunnel))
 	}
-}
 
-// applyBootRoutesV6 applies a list of IPv6 boot routes to the network's device.
-func (n *bridge) applyBootRoutesV6(routes []string) {
-	for _, route := range routes {
-		r := &ip.Route{
-			DevName: n.name,
-			Proto:   "boot",
-			Family:  ip.FamilyV6,
-		}
+	if getConfig("remote") != "" && getConfig("remote_public_key") == "" {
+		return fmt.Errorf("The remote public key of WireGuard tunnel %q must be set when its remote address is set", tunnel)
+	}
 
-		err := r.Replace(strings.Fields(route))
+	clusterLinkName := getConfig("cluster_link")
+	if clusterLinkName != "" {
+		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			_, err := dbCluster.GetClusterLink(ctx, tx.Tx(), clusterLinkName)
+
+			return err
+		})
 		if err != nil {
-			// If it fails, then we can't stop as the route has already gone, so just log and continue.
-			n.logger.Error("Failed restoring route", logger.Ctx{"err": err})
+			return fmt.Errorf("Failed loading cluster link %q: %w", clusterLinkName, err)
 		}
 	}
+
+	return nil
 }
 
-func (n *bridge) fanAddress(underlay *net.IPNet, overlay *net.IPNet) (cidr string, dev string, ipStr string, err error) {
-	// Quick checks.
-	underlaySize, _ := underlay.Mask.Size()
-	if underlaySize != 16 && underlaySize != 24 {
-		return "", "", "", errors.New("Only /16 or /24 underlays are supported at this time")
+// wireguardListenPort returns the UDP port the WireGuard tunnel listens on.
+func (n *bridge) wireguardListenPort(tunnel string) string {
+	port := n.config[fmt.Sprintf("tunnel.%s.port", tunnel)]
+	if port == "" {
+		return strconv.Itoa(wireguardDefaultPort)
 	}
 
-	overlaySize, _ := overlay.Mask.Size()
-	if overlaySize != 8 && overlaySize != 16 {
-		return "", "", "", errors.New("Only /8 or /16 overlays are supported at this time")
-	}
+	return port
+}
 
-	if overlaySize+(32-underlaySize)+8 > 32 {
-		return "", "", "", errors.New("Underlay or overlay networks too large to accommodate the FAN")
+// wireguardKeyRotation returns the interval at which the key pair of the WireGuard tunnel is rotated.
+// The key pair isn't rotated by default when a manually configured peer is used, as its configuration would need
+// to be updated with the new public key.
+func (n *bridge) wireguardKeyRotation(tunnel string) time.Duration {
+	value := n.config[fmt.Sprintf("tunnel.%s.key_rotation", tunnel)]
+	if value == "" {
+		if n.config[fmt.Sprintf("tunnel.%s.remote_public_key", tunnel)] != "" {
+			return 0
+		}
+
+		return wireguardDefaultKeyRotation
 	}
 
-	// Get the IP
-	ip, dev, err := n.addressForSubnet(underlay)
+	hours, err := strconv.ParseUint(value, 10, 32)
 	if err != nil {
-		return "", "", "", err
+		return 0
 	}
 
-	ipStr = ip.String()
+	return time.Duration(hours) * time.Hour
+}
 
-	// Force into IPv4 format
-	ipBytes := ip.To4()
-	if ipBytes == nil {
-		return "", "", "", fmt.Errorf("Invalid IPv4: %s", ip)
+// wireguardSetup creates the WireGuard interface of the tunnel and the VXLAN interface carried inside it.
+// The VXLAN interface is named after the tunnel, like the interfaces of the other tunnel protocols.
+func (n *bridge) wireguardSetup(tunnel string) error {
+	_, err := exec.LookPath("wg")
+	if err != nil {
+		return errors.New("The wg tool is required to use WireGuard tunnels")
 	}
 
-	// Compute the IP
-	ipBytes[0] = overlay.IP[0]
-	if overlaySize == 16 {
-		ipBytes[1] = overlay.IP[1]
-		ipBytes[2] = ipBytes[3]
-	} else if underlaySize == 24 {
-		ipBytes[1] = ipBytes[3]
-		ipBytes[2] = 0
-	} else if underlaySize == 16 {
-		ipBytes[1] = ipBytes[2]
-		ipBytes[2] = ipBytes[3]
+	wgName := wireguardDevName(n.name, tunnel)
+	wg := &ip.Wireguard{Link: ip.Link{Name: wgName}}
+	err = wg.Add()
+	if err != nil {
+		return fmt.Errorf("Failed creating WireGuard interface %q: %w", wgName, err)
 	}
 
-	ipBytes[3] = 1
+	privateKey, _, err := wireguardLoadKey(n.name, n.wireguardKeyRotation(tunnel))
+	if err != nil {
+		return err
+	}
 
-	return ipBytes.String() + "/" + strconv.Itoa(overlaySize), dev, ipStr, err
-}
+	publicKey, err := n.wireguardApplyKey(tunnel, privateKey)
+	if err != nil {
+		return err
+	}
 
-func (n *bridge) addressForSubnet(subnet *net.IPNet) (net.IP, string, error) {
-	ifaces, err := net.Interfaces()
+	err = wg.SetUp()
 	if err != nil {
-		return net.IP{}, "", err
+		return err
 	}
 
-	for _, iface := range ifaces {
-		// Skip addresses on lo interface in case VIPs are being used on that interface that are part of
-		// the underlay subnet as is unlikely to be the actual intended underlay subnet interface.
-		if iface.Name == "lo" {
-			continue
-		}
+	return n.wireguardVXLANAdd(tunnel, publicKey)
+}
 
-		addrs, err := iface.Addrs()
-		if err != nil {
-			continue
-		}
+// wireguardApplyKey configures the private key on the WireGuard interface of the tunnel, along with the inner
+// address derived from the matching public key. Returns the public key.
+func (n *bridge) wireguardApplyKey(tunnel string, privateKey string) (string, error) {
+	wgName := wireguardDevName(n.name, tunnel)
 
-		for _, addr := range addrs {
-			ip, _, err := net.ParseCIDR(addr.String())
-			if err != nil {
-				continue
-			}
+	publicKey, err := wireguardPublicKey(privateKey)
+	if err != nil {
+		return "", err
+	}
 
-			if subnet.Contains(ip) {
-				return ip, iface.Name, nil
-			}
-		}
+	err = wireguardConfigure(wgName, wireguardKeyPath(n.name), n.wireguardListenPort(tunnel))
+	if err != nil {
+		return "", err
 	}
 
-	return net.IP{}, "", errors.New("No address found in subnet")
-}
+	addr := &ip.Addr{
+		DevName: wgName,
+		Family:  ip.FamilyV6,
+	}
 
-func (n *bridge) killForkDNS() error {
-	// Check if we have a running forkdns at all
-	pidPath := shared.VarPath("networks", n.name, "forkdns.pid")
+	err = addr.Flush()
+	if err != nil {
+		return "", err
+	}
 
-	p, err := subprocess.ImportProcess(pidPath)
+	addr.Address = wireguardInnerAddress(publicKey).String() + "/64"
+	err = addr.Add()
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil
-		}
+		return "", err
+	}
 
-		return fmt.Errorf("Could not read pid file: %s", err)
+	return publicKey, nil
+}
+
+// wireguardVXLANAdd creates the VXLAN interface carried inside the WireGuard interface of the tunnel.
+// The VXLAN traffic is sent from the inner address of the local endpoint and flooded to the inner address of each
+// peer through the forwarding database entries added by wireguardApplyPeers.
+func (n *bridge) wireguardVXLANAdd(tunnel string, publicKey string) error {
+	tunID := n.config[fmt.Sprintf("tunnel.%s.id", tunnel)]
+	if tunID == "" {
+		tunID = "1"
 	}
 
-	err = p.Stop()
-	if err != nil && err != subprocess.ErrNotRunning {
-		return fmt.Errorf("Cannot kill dnsmasq: %s", err)
+	vxlan := &ip.Vxlan{
+		Link:    ip.Link{Name: fmt.Sprintf("%s-%s", n.name, tunnel)},
+		VxlanID: tunID,
+		DevName: wireguardDevName(n.name, tunnel),
+		Local:   wireguardInnerAddress(publicKey).String(),
+		DstPort: strconv.Itoa(wireguardVXLANPort),
 	}
 
-	return nil
+	return vxlan.Add()
 }
 
-// updateForkdnsServersFile takes a list of node addresses and writes them atomically to
-// the forkdns.servers file ready for forkdns to notice and re-apply its config.
-func (n *bridge) updateForkdnsServersFile(addresses []string) error {
-	// We don't want to race with ourselves here
-	forkdnsServersLock.Lock()
-	defer forkdnsServersLock.Unlock()
-
-	permName := shared.VarPath("networks", n.name, ForkdnsServersListPath+"/"+ForkdnsServersListFile)
-	tmpName := permName + ".tmp"
+// wireguardRotate switches the WireGuard tunnel to a new private key in place, keeping its interfaces and peers.
+// The peers already accept the new key, which was announced to them during the grace period of the rotation, so
+// only the inner address of the local endpoint changes: the VXLAN traffic is moved to the address derived from the
+// new public key and the address derived from the previous one is removed.
+func (n *bridge) wireguardRotate(tunnel string, oldPublicKey string, publicKey string) error {
+	wgName := wireguardDevName(n.name, tunnel)
 
-	// Open tmp file and truncate, using explicit permissions so the final servers.conf
-	// always ends up with mode 0644 regardless of the process umask.
-	tmpFile, err := os.OpenFile(tmpName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	err := wireguardConfigure(wgName, wireguardKeyPath(n.name), n.wireguardListenPort(tunnel))
 	if err != nil {
 		return err
 	}
 
-	defer func() { _ = tmpFile.Close() }()
-
-	for _, address := range addresses {
-		_, err := tmpFile.WriteString(address + "\n")
-		if err != nil {
-			return err
-		}
+	addr := &ip.Addr{
+		DevName: wgName,
+		Address: wireguardInnerAddress(publicKey).String() + "/64",
+		Family:  ip.FamilyV6,
 	}
 
-	err = tmpFile.Close()
+	err = addr.Add()
 	if err != nil {
 		return err
 	}
 
-	// Atomically rename finished file into permanent location so forkdns can pick it up.
-	err = os.Rename(tmpName, permName)
+	vxlan := &ip.Vxlan{Link: ip.Link{Name: fmt.Sprintf("%s-%s", n.name, tunnel)}}
+	err = vxlan.SetLocal(wireguardInnerAddress(publicKey).String())
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	if oldPublicKey == "" || oldPublicKey == publicKey {
+		return nil
+	}
 
-// hasIPv4Firewall indicates whether the network has IPv4 firewall enabled.
-func (n *bridge) hasIPv4Firewall() bool {
-	// IPv4 firewall is only enabled if there is a bridge ipv4.address or fan mode, and ipv4.firewall enabled.
-	// When using fan bridge.mode, there can be an empty ipv4.address, so we assume it is active.
-	if (n.config["bridge.mode"] == "fan" || !slices.Contains([]string{"", "none"}, n.config["ipv4.address"])) && shared.IsTrueOrEmpty(n.config["ipv4.firewall"]) {
-		return true
+	oldAddr := &ip.Addr{
+		DevName: wgName,
+		Address: wireguardInnerAddress(oldPublicKey).String() + "/64",
+		Family:  ip.FamilyV6,
 	}
 
-	return false
+	return oldAddr.Delete()
 }
 
-// hasIPv6Firewall indicates whether the network has IPv6 firewall enabled.
-func (n *bridge) hasIPv6Firewall() bool {
-	// IPv6 firewall is only enabled if there is a bridge ipv6.address and ipv6.firewall enabled.
-	if !slices.Contains([]string{"", "none"}, n.config["ipv6.address"]) && shared.IsTrueOrEmpty(n.config["ipv6.firewall"]) {
-		return true
+// wireguardRefresh rotates the key pair of the WireGuard tunnel when due and updates its peers.
+// While a rotation is in progress, the next public key is announced to the peers through the network state.
+func (n *bridge) wireguardRefresh(tunnel string, m *wireguardMonitor) {
+	privateKey, _, err := wireguardLoadKey(n.name, n.wireguardKeyRotation(tunnel))
+	if err != nil {
+		n.logger.Warn("Failed loading WireGuard key", logger.Ctx{"tunnel": tunnel, "err": err})
+		return
 	}
 
-	return false
-}
-
-// hasDHCPv4 indicates whether the network has DHCPv4 enabled.
-// An empty ipv4.dhcp setting indicates enabled by default.
-func (n *bridge) hasDHCPv4() bool {
-	return shared.IsTrueOrEmpty(n.config["ipv4.dhcp"])
-}
+	publicKey, err := wireguardPublicKey(privateKey)
+	if err != nil {
+		n.logger.Warn("Failed loading WireGuard key", logger.Ctx{"tunnel": tunnel, "err": err})
+		return
+	}
 
-// hasDHCPv6 indicates whether the network has DHCPv6 enabled.
-// An empty ipv6.dhcp setting indicates enabled by default.
-func (n *bridge) hasDHCPv6() bool {
-	return shared.IsTrueOrEmpty(n.config["ipv6.dhcp"])
+	// Compare with the key in use rather than tracking the switch, so that a failed switch is retried.
+	state, err := wireguardState(wireguardDevName(n.name, tunnel))
+	if err != nil {
+		n.logger.Warn("Failed getting WireGuard tunnel state", logger.Ctx{"tunnel": tunnel, "err": err})
+		return
+	}
+
+	if state.PublicKey != publicKey {
+		err = n.wireguardRotate(tunnel, state.PublicKey, publicKey)
+		if err != nil {
+			n.logger.Warn("Failed rotating WireGuard key", logger.Ctx{"tunnel": tunnel, "err": err})
+			return
+		}
+
+		n.logger.Info("Rotated WireGuard key", logger.Ctx{"tunnel": tunnel})
+	}
+
+	peers := m.update(n.wireguardPeers(tunnel))
+
+	err = n.wireguardApplyPeers(tunnel, publicKey, peers)
+	if err != nil {
+		n.logger.Warn("Failed applying WireGuard peers", logger.Ctx{"tunnel": tunnel, "err": err})
+	}
 }
 
-// DHCPv4Subnet returns the DHCPv4 subnet (if DHCP is enabled on network).
-func (n *bridge) DHCPv4Subnet() *net.IPNet {
-	// DHCP is disabled on this network.
-	if !n.hasDHCPv4() {
-		return nil
+// wireguardPeers discovers the peers of the WireGuard tunnel, keyed by the source they were discovered from.
+// The peers are the manually configured remote, the other members of this cluster and the members of the linked
+// cluster. Also returns the sources which couldn't be queried, so their last known peers can be kept.
+func (n *bridge) wireguardPeers(tunnel string) (map[string]wireguardPeer, []string) {
+	getConfig := func(key string) string {
+		return n.config[fmt.Sprintf("tunnel.%s.%s", tunnel, key)]
 	}
 
-	// Fan mode. Extract DHCP subnet from fan bridge address. Only detectable once network has started.
-	// But if there is no address on the fan bridge then DHCP won't work anyway.
-	if n.config["bridge.mode"] == "fan" {
-		iface, err := net.InterfaceByName(n.name)
-		if err != nil {
-			return nil
+	peers := map[string]wireguardPeer{}
+	failedSources := []string{}
+
+	// Add the manually configured peer.
+	remotePublicKey := getConfig("remote_public_key")
+	if remotePublicKey != "" {
+		peer := wireguardPeer{publicKey: remotePublicKey}
+
+		remote := getConfig("remote")
+		if remote != "" {
+			peer.endpoint = net.JoinHostPort(remote, n.wireguardListenPort(tunnel))
 		}
 
-		addrs, err := iface.Addrs()
+		peers["remote"] = peer
+	}
+
+	// Add the other members of this cluster.
+	if n.state.ServerClustered {
+		var members []db.NodeInfo
+
+		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			members, err = tx.GetNodes(ctx)
+
+			return err
+		})
 		if err != nil {
-			return nil
+			n.logger.Warn("Failed loading cluster members for WireGuard peers", logger.Ctx{"err": err})
+			failedSources = append(failedSources, "member/")
 		}
 
-		for _, addr := range addrs {
-			ip, subnet, err := net.ParseCIDR(addr.String())
-			if err != nil {
+		networkCert := n.state.Endpoints.NetworkCert()
+		offlineThreshold := n.state.GlobalConfig.OfflineThreshold()
+
+		for _, member := range members {
+			if member.Name == n.state.ServerName {
 				continue
 			}
 
-			if ip != nil && ip.To4() != nil && ip.IsGlobalUnicast() {
-				return subnet // Use first IPv4 unicast address on host for DHCP subnet.
+			source := "member/" + member.Name
+			if member.IsOffline(offlineThreshold) {
+				failedSources = append(failedSources, source)
+				continue
+			}
+
+			host, _, err := net.SplitHostPort(member.Address)
+			if err != nil {
+				host = member.Address
+			}
+
+			peer, err := n.wireguardRemotePeer(host, func() (lxd.InstanceServer, error) {
+				return cluster.Connect(context.TODO(), member.Address, networkCert, n.state.ServerCert(), true)
+			})
+			if err != nil {
+				n.logger.Warn("Failed getting WireGuard peer from cluster member", logger.Ctx{"member": member.Name, "err": err})
+				failedSources = append(failedSources, source)
+				continue
 			}
+
+			peers[source] = *peer
 		}
+	}
 
-		return nil // No addresses found, means DHCP must be disabled.
+	// Add the members of the linked cluster.
+	clusterLinkName := getConfig("cluster_link")
+	if clusterLinkName != "" {
+		linkPeers, linkFailedSources, err := n.wireguardClusterLinkPeers(clusterLinkName)
+		if err != nil {
+			n.logger.Warn("Failed getting WireGuard peers from cluster link", logger.Ctx{"clusterLink": clusterLinkName, "err": err})
+			linkFailedSources = append(linkFailedSources, "link/"+clusterLinkName+"/")
+		}
+
+		maps.Copy(peers, linkPeers)
+		failedSources = append(failedSources, linkFailedSources...)
 	}
 
-	// Non-fan mode. Return configured bridge subnet directly.
-	_, subnet, err := net.ParseCIDR(n.config["ipv4.address"])
+	return peers, failedSources
+}
+
+// wireguardClusterLinkPeers returns the peers found on the members of the linked cluster, keyed by source.
+// Also returns the sources of the members which couldn't be queried.
+func (n *bridge) wireguardClusterLinkPeers(clusterLinkName string) (map[string]wireguardPeer, []string, error) {
+	var clusterLink *api.ClusterLink
+	var targetCert *x509.Certificate
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		_, clusterLink, targetCert, err = cluster.LoadClusterLinkAndCert(ctx, tx.Tx(), clusterLinkName)
+
+		return err
+	})
 	if err != nil {
-		return nil
+		return nil, nil, err
 	}
 
-	return subnet
-}
+	args := cluster.GetClusterLinkConnectionArgs(n.state.Endpoints.NetworkCert(), targetCert)
+	client, err := cluster.ConnectCluster(context.TODO(), *clusterLink, args)
+	if err != nil {
+		return nil, nil, err
+	}
 
-// DHCPv6Subnet returns the DHCPv6 subnet (if DHCP or SLAAC is enabled on network).
-func (n *bridge) DHCPv6Subnet() *net.IPNet {
-	// DHCP is disabled on this network.
-	if !n.hasDHCPv6() {
-		return nil
+	defer client.Disconnect()
+
+	peers := map[string]wireguardPeer{}
+	failedSources := []string{}
+	source := "link/" + clusterLinkName + "/"
+
+	// A standalone server is reached at the address used for the connection.
+	if !client.IsClustered() {
+		info, err := client.GetConnectionInfo()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		u, err := url.Parse(info.URL)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		peer, err := n.wireguardRemotePeer(u.Hostname(), func() (lxd.InstanceServer, error) { return client, nil })
+		if err != nil {
+			return nil, nil, err
+		}
+
+		peers[source] = *peer
+
+		return peers, failedSources, nil
 	}
 
-	_, subnet, err := net.ParseCIDR(n.config["ipv6.address"])
+	members, err := client.GetClusterMembers()
 	if err != nil {
-		return nil
+		return nil, nil, err
 	}
 
-	return subnet
+	for _, member := range members {
+		memberSource := source + member.ServerName
+		if member.Status != "Online" {
+			failedSources = append(failedSources, memberSource)
+			continue
+		}
+
+		u, err := url.Parse(member.URL)
+		if err != nil {
+			failedSources = append(failedSources, memberSource)
+			continue
+		}
+
+		peer, err := n.wireguardRemotePeer(u.Hostname(), func() (lxd.InstanceServer, error) { return client.UseTarget(member.ServerName), nil })
+		if err != nil {
+			n.logger.Warn("Failed getting WireGuard peer from linked cluster member", logger.Ctx{"clusterLink": clusterLinkName, "member": member.ServerName, "err": err})
+			failedSources = append(failedSources, memberSource)
+			continue
+		}
+
+		peers[memberSource] = *peer
+	}
+
+	return peers, failedSources, nil
 }
 
-// forwardConvertToFirewallForward converts forwards into format compatible with the firewall package.
-func (n *bridge) forwardConvertToFirewallForwards(listenAddress net.IP, defaultTargetAddress net.IP, portMaps []*forwardPortMap) []firewallDrivers.AddressForward {
-	vips := make([]firewallDrivers.AddressForward, 0, len(portMaps)+1)
+// wireguardRemotePeer returns the WireGuard peer of the network with the same name on the server returned by the
+// connect function. The peer endpoint is the given host and the port the remote tunnel listens on.
+func (n *bridge) wireguardRemotePeer(host string, connect func() (lxd.InstanceServer, error)) (*wireguardPeer, error) {
+	client, err := connect()
+	if err != nil {
+		return nil, err
+	}
 
-	if defaultTargetAddress != nil {
-		vips = append(vips, firewallDrivers.AddressForward{
-			ListenAddress: listenAddress,
-			TargetAddress: defaultTargetAddress,
-		})
+	state, err := client.GetNetworkState(n.name)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, portMap := range portMaps {
-		vips = append(vips, firewallDrivers.AddressForward{
-			ListenAddress: listenAddress,
-			Protocol:      portMap.protocol,
-			TargetAddress: portMap.target.address,
-			ListenPorts:   portMap.listenPorts,
-			TargetPorts:   portMap.target.ports,
-		})
+	if state.WireGuard == nil || state.WireGuard.PublicKey == "" {
+		return nil, fmt.Errorf("Network %q has no WireGuard tunnel", n.name)
 	}
 
-	return vips
+	return &wireguardPeer{
+		publicKey:     state.WireGuard.PublicKey,
+		nextPublicKey: state.WireGuard.NextPublicKey,
+		endpoint:      net.JoinHostPort(host, strconv.FormatUint(state.WireGuard.ListenPort, 10)),
+	}, nil
 }
 
-// bridgeProjectNetworks takes a map of all networks in all projects and returns a filtered map of bridge networks.
-func (n *bridge) bridgeProjectNetworks(projectNetworks map[string]map[int64]api.Network) map[string][]*api.Network {
-	bridgeProjectNetworks := make(map[string][]*api.Network)
-	for netProject, networks := range projectNetworks {
-		for _, ni := range networks {
-			network := ni // Local var creating pointer to rather than iterator.
+// wireguardApplyPeers configures the peers on the WireGuard interface of the tunnel and removes the stale ones.
+// A peer whose key rotation is in progress is configured with both its current and next public keys, each allowed
+// its own inner address, so that its traffic keeps being accepted when it switches keys.
+// The forwarding database of the VXLAN interface is updated so that the traffic is flooded to every peer address.
+func (n *bridge) wireguardApplyPeers(tunnel string, publicKey string, peers []wireguardPeer) error {
+	wgName := wireguardDevName(n.name, tunnel)
+
+	wantedPeers := make(map[string]wireguardPeer, len(peers))
+	for _, peer := range peers {
+		// Skip the local endpoint, in case the cluster link points back to this cluster.
+		if peer.publicKey == publicKey {
+			continue
+		}
 
-			// Skip non-bridge networks.
-			if network.Type != "bridge" {
-				continue
-			}
+		wantedPeers[peer.publicKey] = peer
 
-			if bridgeProjectNetworks[netProject] == nil {
-				bridgeProjectNetworks[netProject] = []*api.Network{&network}
-			} else {
-				bridgeProjectNetworks[netProject] = append(bridgeProjectNetworks[netProject], &network)
-			}
+		if peer.nextPublicKey != "" {
+			wantedPeers[peer.nextPublicKey] = wireguardPeer{publicKey: peer.nextPublicKey, endpoint: peer.endpoint}
 		}
 	}
 
-	return bridgeProjectNetworks
+	state, err := wireguardState(wgName)
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range state.Peers {
+		_, found := wantedPeers[peer.PublicKey]
+		if found {
+			continue
+		}
+
+		err = wireguardRemovePeer(wgName, peer.PublicKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	wantedDsts := make(map[string]net.IP, len(wantedPeers))
+	for _, peer := range wantedPeers {
+		err = wireguardSetPeer(wgName, peer)
+		if err != nil {
+			return err
+		}
+
+		dst := wireguardInnerAddress(peer.publicKey)
+		wantedDsts[dst.String()] = dst
+	}
+
+	fdb := &ip.FDB{DevName: fmt.Sprintf("%s-%s", n.name, tunnel)}
+	entries, err := fdb.Show()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.MAC.String() != wireguardFloodMAC.String() {
+			continue
+		}
+
+		_, found := wantedDsts[entry.Dst.String()]
+		if found {
+			delete(wantedDsts, entry.Dst.String())
+			continue
+		}
+
+		err = entry.Delete()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, dst := range wantedDsts {
+		entry := &ip.FDB{DevName: fdb.DevName, MAC: wireguardFloodMAC, Dst: dst}
+		err = entry.Append()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-// bridgeNetworkExternalSubnets returns a list of external subnets used by bridge networks. Networks are considered
-// to be using external subnets for their ipv4.address and/or ipv6.address if they have NAT disabled, and/or if
-// they have external NAT addresses specified.
-func (n *bridge) bridgeNetworkExternalSubnets(bridgeProjectNetworks map[string][]*api.Network) ([]externalSubnetUsage, error) {
-	externalSubnets := make([]externalSubnetUsage, 0)
-	for netProject, networks := range bridgeProjectNetworks {
-		for _, netInfo := range networks {
-			for _, keyPrefix := range []string{"ipv4", "ipv6"} {
-				// If NAT is disabled, then network subnet is an external subnet.
-				if shared.IsFalseOrEmpty(netInfo.Config[keyPrefix+".nat"]) {
-					key := keyPrefix + ".address"
+// State returns the api.NetworkState for the network, including the state of its WireGuard tunnel.
+func (n *bridge) State() (*api.NetworkState, error) {
+	state, err := n.common.State()
+	if err != nil {
+		return nil, err
+	}
 
-					_, ipNet, err := net.ParseCIDR(netInfo.Config[key])
-					if err != nil {
-						continue // Skip invalid/unspecified network addresses.
-					}
+	tunnel := n.wireguardTunnel(n.config)
+	if tunnel == "" || !n.isRunning() {
+		return state, nil
+	}
 
-					externalSubnets = append(externalSubnets, externalSubnetUsage{
-						subnet:         *ipNet,
-						networkProject: netProject,
-						networkName:    netInfo.Name,
-						usageType:      subnetUsageNetwork,
-					})
-				}
+	wireguard, err := wireguardState(wireguardDevName(n.name, tunnel))
+	if err != nil {
+		n.logger.Warn("Failed getting WireGuard tunnel state", logger.Ctx{"tunnel": tunnel, "err": err})
+		return state, nil
+	}
+
+	wireguard.Tunnel = tunnel
+	wireguard.NextPublicKey, err = wireguardNextPublicKey(n.name)
+	if err != nil {
+		n.logger.Warn("Failed loading next WireGuard key", logger.Ctx{"tunnel": tunnel, "err": err})
+	}
+
+	state.WireGuard = wireguard
+
+	return state, nil
+}
+
+// bootRoutesV4 returns a list of IPv4 boot routes on the network's device.
+func (n *bridge) bootRoutesV4() ([]string, error) {
+	r := &ip.Route{
+		DevName: n.name,
+		Proto:   "boot",
+		Family:  ip.FamilyV4,
+	}
+
+	routes, err := r.Show()
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// bootRoutesV6 returns a list of IPv6 boot routes on the network's device.
+func (n *bridge) bootRoutesV6() ([]string, error) {
+	r := &ip.Route{
+		DevName: n.name,
+		Proto:   "boot",
+		Family:  ip.FamilyV6,
+	}
+
+	routes, err := r.Show()
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// applyBootRoutesV4 applies a list of IPv4 boot routes to the network's device.
+func (n *bridge) applyBootRoutesV4(routes []string) {
+	for _, route := range routes {
+		r := &ip.Route{
+			DevName: n.name,
+			Proto:   "boot",
+			Family:  ip.FamilyV4,
+		}
+
+		err := r.Replace(strings.Fields(route))
+		if err != nil {
+			// If it fails, then we can't stop as the route has already gone, so just log and continue.
+			n.logger.Error("Failed restoring route", logger.Ctx{"err": err})
+		}
+	}
+}
+
+// applyBootRoutesV6 applies a list of IPv6 boot routes to the network's device.
+func (n *bridge) applyBootRoutesV6(routes []string) {
+	for _, route := range routes {
+		r := &ip.Route{
+			DevName: n.name,
+			Proto:   "boot",
+			Family:  ip.FamilyV6,
+		}
+
+		err := r.Replace(strings.Fields(route))
+		if err != nil {
+			// If it fails, then we can't stop as the route has already gone, so just log and continue.
+			n.logger.Error("Failed restoring route", logger.Ctx{"err": err})
+		}
+	}
+}
+
+func (n *bridge) fanAddress(underlay *net.IPNet, overlay *net.IPNet) (cidr string, dev string, ipStr string, err error) {
+	// Quick checks.
+	underlaySize, _ := underlay.Mask.Size()
+	if underlaySize != 16 && underlaySize != 24 {
+		return "", "", "", errors.New("Only /16 or /24 underlays are supported at this time")
+	}
+
+	overlaySize, _ := overlay.Mask.Size()
+	if overlaySize != 8 && overlaySize != 16 {
+		return "", "", "", errors.New("Only /8 or /16 overlays are supported at this time")
+	}
+
+	if overlaySize+(32-underlaySize)+8 > 32 {
+		return "", "", "", errors.New("Underlay or overlay networks too large to accommodate the FAN")
+	}
+
+	// Get the IP
+	ip, dev, err := n.addressForSubnet(underlay)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	ipStr = ip.String()
+
+	// Force into IPv4 format
+	ipBytes := ip.To4()
+	if ipBytes == nil {
+		return "", "", "", fmt.Errorf("Invalid IPv4: %s", ip)
+	}
+
+	// Compute the IP
+	ipBytes[0] = overlay.IP[0]
+	if overlaySize == 16 {
+		ipBytes[1] = overlay.IP[1]
+		ipBytes[2] = ipBytes[3]
+	} else if underlaySize == 24 {
+		ipBytes[1] = ipBytes[3]
+		ipBytes[2] = 0
+	} else if underlaySize == 16 {
+		ipBytes[1] = ipBytes[2]
+		ipBytes[2] = ipBytes[3]
+	}
+
+	ipBytes[3] = 1
+
+	return ipBytes.String() + "/" + strconv.Itoa(overlaySize), dev, ipStr, err
+}
+
+func (n *bridge) addressForSubnet(subnet *net.IPNet) (net.IP, string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return net.IP{}, "", err
+	}
+
+	for _, iface := range ifaces {
+		// Skip addresses on lo interface in case VIPs are being used on that interface that are part of
+		// the underlay subnet as is unlikely to be the actual intended underlay subnet interface.
+		if iface.Name == "lo" {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			ip, _, err := net.ParseCIDR(addr.String())
+			if err != nil {
+				continue
+			}
+
+			if subnet.Contains(ip) {
+				return ip, iface.Name, nil
+			}
+		}
+	}
+
+	return net.IP{}, "", errors.New("No address found in subnet")
+}
+
+func (n *bridge) killForkDNS() error {
+	// Check if we have a running forkdns at all
+	pidPath := shared.VarPath("networks", n.name, "forkdns.pid")
+
+	p, err := subprocess.ImportProcess(pidPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("Could not read pid file: %s", err)
+	}
+
+	err = p.Stop()
+	if err != nil && err != subprocess.ErrNotRunning {
+		return fmt.Errorf("Cannot kill dnsmasq: %s", err)
+	}
+
+	return nil
+}
+
+// updateForkdnsServersFile takes a list of node addresses and writes them atomically to
+// the forkdns.servers file ready for forkdns to notice and re-apply its config.
+func (n *bridge) updateForkdnsServersFile(addresses []string) error {
+	// We don't want to race with ourselves here
+	forkdnsServersLock.Lock()
+	defer forkdnsServersLock.Unlock()
+
+	permName := shared.VarPath("networks", n.name, ForkdnsServersListPath+"/"+ForkdnsServersListFile)
+	tmpName := permName + ".tmp"
+
+	// Open tmp file and truncate, using explicit permissions so the final servers.conf
+	// always ends up with mode 0644 regardless of the process umask.
+	tmpFile, err := os.OpenFile(tmpName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tmpFile.Close() }()
+
+	for _, address := range addresses {
+		_, err := tmpFile.WriteString(address + "\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	// Atomically rename finished file into permanent location so forkdns can pick it up.
+	err = os.Rename(tmpName, permName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// hasIPv4Firewall indicates whether the network has IPv4 firewall enabled.
+func (n *bridge) hasIPv4Firewall() bool {
+	// IPv4 firewall is only enabled if there is a bridge ipv4.address or fan mode, and ipv4.firewall enabled.
+	// When using fan bridge.mode, there can be an empty ipv4.address, so we assume it is active.
+	if (n.config["bridge.mode"] == "fan" || !slices.Contains([]string{"", "none"}, n.config["ipv4.address"])) && shared.IsTrueOrEmpty(n.config["ipv4.firewall"]) {
+		return true
+	}
+
+	return false
+}
+
+// hasIPv6Firewall indicates whether the network has IPv6 firewall enabled.
+func (n *bridge) hasIPv6Firewall() bool {
+	// IPv6 firewall is only enabled if there is a bridge ipv6.address and ipv6.firewall enabled.
+	if !slices.Contains([]string{"", "none"}, n.config["ipv6.address"]) && shared.IsTrueOrEmpty(n.config["ipv6.firewall"]) {
+		return true
+	}
+
+	return false
+}
+
+// hasDHCPv4 indicates whether the network has DHCPv4 enabled.
+// An empty ipv4.dhcp setting indicates enabled by default.
+func (n *bridge) hasDHCPv4() bool {
+	return shared.IsTrueOrEmpty(n.config["ipv4.dhcp"])
+}
+
+// hasDHCPv6 indicates whether the network has DHCPv6 enabled.
+// An empty ipv6.dhcp setting indicates enabled by default.
+func (n *bridge) hasDHCPv6() bool {
+	return shared.IsTrueOrEmpty(n.config["ipv6.dhcp"])
+}
+
+// DHCPv4Subnet returns the DHCPv4 subnet (if DHCP is enabled on network).
+func (n *bridge) DHCPv4Subnet() *net.IPNet {
+	// DHCP is disabled on this network.
+	if !n.hasDHCPv4() {
+		return nil
+	}
+
+	// Fan mode. Extract DHCP subnet from fan bridge address. Only detectable once network has started.
+	// But if there is no address on the fan bridge then DHCP won't work anyway.
+	if n.config["bridge.mode"] == "fan" {
+		iface, err := net.InterfaceByName(n.name)
+		if err != nil {
+			return nil
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil
+		}
+
+		for _, addr := range addrs {
+			ip, subnet, err := net.ParseCIDR(addr.String())
+			if err != nil {
+				continue
+			}
+
+			if ip != nil && ip.To4() != nil && ip.IsGlobalUnicast() {
+				return subnet // Use first IPv4 unicast address on host for DHCP subnet.
+			}
+		}
+
+		return nil // No addresses found, means DHCP must be disabled.
+	}
+
+	// Non-fan mode. Return configured bridge subnet directly.
+	_, subnet, err := net.ParseCIDR(n.config["ipv4.address"])
+	if err != nil {
+		return nil
+	}
+
+	return subnet
+}
+
+// DHCPv6Subnet returns the DHCPv6 subnet (if DHCP or SLAAC is enabled on network).
+func (n *bridge) DHCPv6Subnet() *net.IPNet {
+	// DHCP is disabled on this network.
+	if !n.hasDHCPv6() {
+		return nil
+	}
+
+	_, subnet, err := net.ParseCIDR(n.config["ipv6.address"])
+	if err != nil {
+		return nil
+	}
+
+	return subnet
+}
+
+// forwardConvertToFirewallForward converts forwards into format compatible with the firewall package.
+func (n *bridge) forwardConvertToFirewallForwards(listenAddress net.IP, defaultTargetAddress net.IP, portMaps []*forwardPortMap) []firewallDrivers.AddressForward {
+	vips := make([]firewallDrivers.AddressForward, 0, len(portMaps)+1)
+
+	if defaultTargetAddress != nil {
+		vips = append(vips, firewallDrivers.AddressForward{
+			ListenAddress: listenAddress,
+			TargetAddress: defaultTargetAddress,
+		})
+	}
+
+	for _, portMap := range portMaps {
+		vips = append(vips, firewallDrivers.AddressForward{
+			ListenAddress: listenAddress,
+			Protocol:      portMap.protocol,
+			TargetAddress: portMap.target.address,
+			ListenPorts:   portMap.listenPorts,
+			TargetPorts:   portMap.target.ports,
+		})
+	}
+
+	return vips
+}
+
+// bridgeProjectNetworks takes a map of all networks in all projects and returns a filtered map of bridge networks.
+func (n *bridge) bridgeProjectNetworks(projectNetworks map[string]map[int64]api.Network) map[string][]*api.Network {
+	bridgeProjectNetworks := make(map[string][]*api.Network)
+	for netProject, networks := range projectNetworks {
+		for _, ni := range networks {
+			network := ni // Local var creating pointer to rather than iterator.
+
+			// Skip non-bridge networks.
+			if network.Type != "bridge" {
+				continue
+			}
+
+			if bridgeProjectNetworks[netProject] == nil {
+				bridgeProjectNetworks[netProject] = []*api.Network{&network}
+			} else {
+				bridgeProjectNetworks[netProject] = append(bridgeProjectNetworks[netProject], &network)
+			}
+		}
+	}
+
+	return bridgeProjectNetworks
+}
+
+// bridgeNetworkExternalSubnets returns a list of external subnets used by bridge networks. Networks are considered
+// to be using external subnets for their ipv4.address and/or ipv6.address if they have NAT disabled, and/or if
+// they have external NAT addresses specified.
+func (n *bridge) bridgeNetworkExternalSubnets(bridgeProjectNetworks map[string][]*api.Network) ([]externalSubnetUsage, error) {
+	externalSubnets := make([]externalSubnetUsage, 0)
+	for netProject, networks := range bridgeProjectNetworks {
+		for _, netInfo := range networks {
+			for _, keyPrefix := range []string{"ipv4", "ipv6"} {
+				// If NAT is disabled, then network subnet is an external subnet.
+				if shared.IsFalseOrEmpty(netInfo.Config[keyPrefix+".nat"]) {
+					key := keyPrefix + ".address"
+
+					_, ipNet, err := net.ParseCIDR(netInfo.Config[key])
+					if err != nil {
+						continue // Skip invalid/unspecified network addresses.
+					}
+
+					externalSubnets = append(externalSubnets, externalSubnetUsage{
+						subnet:         *ipNet,
+						networkProject: netProject,
+						networkName:    netInfo.Name,
+						usageType:      subnetUsageNetwork,
+					})
+				}
+
+				// Find any external subnets used for network SNAT.
+				if netInfo.Config[keyPrefix+".nat.address"] != "" {
+					key := keyPrefix + ".nat.address"
+
+					subnetSize := 128
+					if keyPrefix == "ipv4" {
+						subnetSize = 32
+					}
+
+					_, ipNet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", netInfo.Config[key], subnetSize))
+					if err != nil {
+						return nil, fmt.Errorf("Failed parsing %q of %q in project %q: %w", key, netInfo.Name, netProject, err)
+					}
+
+					externalSubnets = append(externalSubnets, externalSubnetUsage{
+						subnet:         *ipNet,
+						networkProject: netProject,
+						networkName:    netInfo.Name,
+						usageType:      subnetUsageNetworkSNAT,
+					})
+				}
+
+				// Find any routes being used by the network.
+				for _, cidr := range shared.SplitNTrimSpace(netInfo.Config[keyPrefix+".routes"], ",", -1, true) {
+					_, ipNet, err := net.ParseCIDR(cidr)
+					if err != nil {
+						continue // Skip invalid/unspecified network addresses.
+					}
+
+					externalSubnets = append(externalSubnets, externalSubnetUsage{
+						subnet:         *ipNet,
+						networkProject: netProject,
+						networkName:    netInfo.Name,
+						usageType:      subnetUsageNetwork,
+					})
+				}
+			}
+		}
+	}
+
+	return externalSubnets, nil
+}
+
+// bridgedNICExternalRoutes returns a list of external routes currently used by bridged NICs that are connected to
+// networks specified.
+func (n *bridge) bridgedNICExternalRoutes(bridgeProjectNetworks map[string][]*api.Network) ([]externalSubnetUsage, error) {
+	externalRoutes := make([]externalSubnetUsage, 0)
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			// Get the instance's effective network project name.
+			instNetworkProject := project.NetworkProjectFromRecord(&p)
+
+			if instNetworkProject != api.ProjectDefaultName {
+				return nil // Managed bridge networks can only exist in default project.
+			}
+
+			devices := instancetype.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+
+			// Iterate through each of the instance's devices, looking for bridged NICs that are linked to
+			// networks specified.
+			for devName, devConfig := range devices {
+				if devConfig["type"] != "nic" {
+					continue
+				}
+
+				// Check whether the NIC device references one of the networks supplied.
+				if !NICUsesNetwork(devConfig, bridgeProjectNetworks[instNetworkProject]...) {
+					continue
+				}
+
+				// For bridged NICs that are connected to networks specified, check if they have any
+				// routes or external routes configured, and if so add them to the list to return.
+				for _, key := range []string{"ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external"} {
+					for _, cidr := range shared.SplitNTrimSpace(devConfig[key], ",", -1, true) {
+						_, ipNet, _ := net.ParseCIDR(cidr)
+						if ipNet == nil {
+							// Skip if NIC device doesn't have a valid route.
+							continue
+						}
+
+						externalRoutes = append(externalRoutes, externalSubnetUsage{
+							subnet:          *ipNet,
+							networkProject:  instNetworkProject,
+							networkName:     devConfig["network"],
+							instanceProject: inst.Project,
+							instanceName:    inst.Name,
+							instanceDevice:  devName,
+							usageType:       subnetUsageInstance,
+						})
+					}
+				}
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return externalRoutes, nil
+}
+
+// getExternalSubnetInUse returns information about usage of external subnets by bridge networks (and NICs
+// connected to them) on this member.
+func (n *bridge) getExternalSubnetInUse() ([]externalSubnetUsage, error) {
+	var err error
+	var projectNetworks map[string]map[int64]api.Network
+	var projectNetworksForwardsOnUplink map[string]map[int64][]string
+	var projectNetworksLoadBalancersOnUplink map[string]map[int64][]string
+	var externalSubnets []externalSubnetUsage
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Get all managed networks across all projects.
+		projectNetworks, err = tx.GetCreatedNetworks(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed loading all networks: %w", err)
+		}
+
+		// Get all network forward listen addresses for forwards assigned to this specific cluster member.
+		projectNetworksForwardsOnUplink, err = tx.GetProjectNetworkForwardListenAddressesOnMember(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed loading network forward listen addresses: %w", err)
+		}
+
+		// Get all network load balancer listen addresses for load balancers assigned to this specific cluster member.
+		projectNetworksLoadBalancersOnUplink, err = tx.GetProjectNetworkLoadBalancerListenAddressesOnMember(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed loading network load balancer listen addresses: %w", err)
+		}
+
+		externalSubnets, err = n.common.getExternalSubnetInUse(ctx, tx, n.name, true)
+		if err != nil {
+			return fmt.Errorf("Failed getting external subnets in use: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Get managed bridge networks.
+	bridgeProjectNetworks := n.bridgeProjectNetworks(projectNetworks)
+
+	// Get external subnets used by other managed bridge networks.
+	bridgeNetworkExternalSubnets, err := n.bridgeNetworkExternalSubnets(bridgeProjectNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get external routes configured on bridged NICs.
+	bridgedNICExternalRoutes, err := n.bridgedNICExternalRoutes(bridgeProjectNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	externalSubnets = append(externalSubnets, bridgeNetworkExternalSubnets...)
+	externalSubnets = append(externalSubnets, bridgedNICExternalRoutes...)
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Detect if there are any conflicting proxy devices on all instances with the to be created network forward
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			devices := instancetype.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+
+			for devName, devConfig := range devices {
+				if devConfig["type"] != "proxy" {
+					continue
+				}
+
+				proxyListenAddr, err := ProxyParseAddr(devConfig["listen"])
+				if err != nil {
+					return err
+				}
+
+				proxySubnet, err := ParseIPToNet(proxyListenAddr.Address)
+				if err != nil {
+					continue // If proxy listen is not a valid IP it cannot conflict.
+				}
+
+				externalSubnets = append(externalSubnets, externalSubnetUsage{
+					usageType:       subnetUsageProxy,
+					subnet:          *proxySubnet,
+					instanceProject: inst.Project,
+					instanceName:    inst.Name,
+					instanceDevice:  devName,
+				})
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Add forward listen addresses to this list.
+	for projectName, networks := range projectNetworksForwardsOnUplink {
+		for networkID, listenAddresses := range networks {
+			for _, listenAddress := range listenAddresses {
+				// Convert listen address to subnet.
+				listenAddressNet, err := ParseIPToNet(listenAddress)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid existing forward listen address %q", listenAddress)
+				}
+
+				// Create an externalSubnetUsage for the listen address by using the network ID
+				// of the listen address to retrieve the already loaded network name from the
+				// projectNetworks map.
+				externalSubnets = append(externalSubnets, externalSubnetUsage{
+					subnet:         *listenAddressNet,
+					networkProject: projectName,
+					networkName:    projectNetworks[projectName][networkID].Name,
+					usageType:      subnetUsageNetworkForward,
+				})
+			}
+		}
+	}
+
+	// Add load balancer listen addresses to this list.
+	for projectName, networks := range projectNetworksLoadBalancersOnUplink {
+		for networkID, listenAddresses := range networks {
+			for _, listenAddress := range listenAddresses {
+				// Convert listen address to subnet.
+				listenAddressNet, err := ParseIPToNet(listenAddress)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid existing load balancer listen address %q", listenAddress)
+				}
+
+				externalSubnets = append(externalSubnets, externalSubnetUsage{
+					subnet:         *listenAddressNet,
+					networkProject: projectName,
+					networkName:    projectNetworks[projectName][networkID].Name,
+					usageType:      subnetUsageNetworkLoadBalancer,
+				})
+			}
+		}
+	}
+
+	return externalSubnets, nil
+}
+
+// checkListenAddressNotInUse checks the listen address subnet doesn't fall within any existing network external
+// subnets, forward or load balancer listen addresses.
+func (n *bridge) checkListenAddressNotInUse(listenAddressNet *net.IPNet) (bool, error) {
+	externalSubnetsInUse, err := n.getExternalSubnetInUse()
+	if err != nil {
+		return false, err
+	}
+
+	for _, externalSubnetUser := range externalSubnetsInUse {
+		// Check if usage is from our own network.
+		if externalSubnetUser.networkProject == n.project && externalSubnetUser.networkName == n.name {
+			// Skip checking conflict with our own network's subnet or SNAT address.
+			// But do not allow other conflict with other usage types within our own network.
+			if externalSubnetUser.usageType == subnetUsageNetwork || externalSubnetUser.usageType == subnetUsageNetworkSNAT {
+				continue
+			}
+		}
+
+		if SubnetContains(&externalSubnetUser.subnet, listenAddressNet) || SubnetContains(listenAddressNet, &externalSubnetUser.subnet) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// hairpinRequired returns true if hairpin mode needs to be enabled on the NIC bridge ports for the instances to be
+// able to connect to the forward or load balancer listeners, which is the case if br_netfilter is enabled on a
+// native bridge.
+func (n *bridge) hairpinRequired() bool {
+	if n.config["bridge.driver"] == "openvswitch" {
+		return false
+	}
+
+	for _, ipVersion := range []uint{4, 6} {
+		if BridgeNetfilterEnabled(ipVersion) == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// enableNICHairpin enables hairpin mode on the bridge ports of the active instance NICs connected to the network on
+// this member. This allows instances to connect to a forward or load balancer listener targeting themselves when
+// br_netfilter is enabled.
+func (n *bridge) enableNICHairpin() error {
+	filter := dbCluster.InstanceFilter{Node: &n.state.ServerName}
+
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			// Get the instance's effective network project name.
+			instNetworkProject := project.NetworkProjectFromRecord(&p)
+
+			if instNetworkProject != api.ProjectDefaultName {
+				return nil // Managed bridge networks can only exist in default project.
+			}
+
+			devices := instancetype.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
+
+			// Iterate through each of the instance's devices, looking for bridged NICs
+			// that are linked to this network.
+			for devName, devConfig := range devices {
+				if devConfig["type"] != "nic" {
+					continue
+				}
+
+				// Check whether the NIC device references our network..
+				if !NICUsesNetwork(devConfig, &api.Network{Name: n.Name()}) {
+					continue
+				}
+
+				hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", devName)]
+				if InterfaceExists(hostName) {
+					link := &ip.Link{Name: hostName}
+					err := link.BridgeLinkSetHairpin(true)
+					if err != nil {
+						return fmt.Errorf("Error enabling hairpin mode on bridge port %q: %w", link.Name, err)
+					}
+
+					n.logger.Debug("Enabled hairpin mode on NIC bridge port", logger.Ctx{"inst": inst.Name, "project": inst.Project, "device": devName, "dev": link.Name})
+				}
+			}
+
+			return nil
+		}, filter)
+	})
+}
+
+// forwardValidate validates the forward request.
+func (n *bridge) forwardValidate(listenAddress net.IP, forward api.NetworkForwardPut) ([]*forwardPortMap, error) {
+	err := n.checkAddressNotInOVNRange(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.common.forwardValidate(listenAddress, forward)
+}
+
+// ForwardCreate creates a network forward.
+func (n *bridge) ForwardCreate(forward api.NetworkForwardsPost, clientType request.ClientType) (net.IP, error) {
+	memberSpecific := true // bridge supports per-member forwards.
+
+	// Convert listen address to subnet so we can check its valid and can be used.
+	listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+	}
+
+	if listenAddressNet.IP.IsUnspecified() {
+		return nil, api.StatusErrorf(http.StatusNotImplemented, "Automatic listen address allocation not supported for drivers of type %q", n.netType)
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check if there is an existing forward using the same listen address.
+		_, _, err := tx.GetNetworkForward(ctx, n.ID(), memberSpecific, forward.ListenAddress)
+
+		return err
+	})
+	if err == nil {
+		return nil, api.StatusErrorf(http.StatusConflict, "A forward for that listen address already exists")
+	}
+
+	_, err = n.forwardValidate(listenAddressNet.IP, forward.NetworkForwardPut)
+	if err != nil {
+		return nil, err
+	}
+
+	isValid, err := n.checkListenAddressNotInUse(listenAddressNet)
+	if err != nil {
+		return nil, err
+	} else if !isValid {
+		// This error is purposefully vague so that it doesn't reveal any names of
+		// resources potentially outside of the network.
+		return nil, fmt.Errorf("Forward listen address %q overlaps with another network or NIC", listenAddressNet.String())
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	var forwardID int64
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Create forward DB record.
+		forwardID, err = tx.CreateNetworkForward(ctx, n.ID(), memberSpecific, &forward)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteNetworkForward(ctx, n.ID(), forwardID)
+		})
+		_ = n.forwardSetupFirewall()
+		_ = n.forwardBGPSetupPrefixes()
+	})
+
+	err = n.forwardSetupFirewall()
+	if err != nil {
+		return nil, err
+	}
+
+	// If br_netfilter is enabled and bridge has forwards, we enable hairpin mode on each NIC's bridge
+	// port in case any of the forwards target the NIC and the instance attempts to connect to the
+	// forward's listener. Without hairpin mode on the target of the forward will not be able to
+	// connect to the listener.
+	if n.hairpinRequired() {
+		var listenAddresses map[int64]string
+
+		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			listenAddresses, err = tx.GetNetworkForwardListenAddresses(ctx, n.ID(), true)
+
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading network forwards: %w", err)
+		}
+
+		// If we are the first forward on this bridge, enable hairpin mode on active NIC ports.
+		if len(listenAddresses) <= 1 {
+			err = n.enableNICHairpin()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err = n.forwardBGPSetupPrefixes()
+	if err != nil {
+		return nil, fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
+	}
+
+	revert.Success()
+	return listenAddressNet.IP, nil
+}
+
+// ForwardUpdate updates a network forward.
+func (n *bridge) ForwardUpdate(listenAddress string, req api.NetworkForwardPut, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member forwards.
+
+	var curForwardID int64
+	var curForward *api.NetworkForward
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		curForwardID, curForward, err = tx.GetNetworkForward(ctx, n.ID(), memberSpecific, listenAddress)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = n.forwardValidate(net.ParseIP(curForward.ListenAddress), req)
+	if err != nil {
+		return err
+	}
+
+	curForwardEtagHash, err := util.EtagHash(curForward.Etag())
+	if err != nil {
+		return err
+	}
+
+	newForward := api.NetworkForward{
+		ListenAddress: curForward.ListenAddress,
+		Description:   req.Description,
+		Config:        req.Config,
+		Ports:         req.Ports,
+	}
+
+	newForwardEtagHash, err := util.EtagHash(newForward.Etag())
+	if err != nil {
+		return err
+	}
+
+	if curForwardEtagHash == newForwardEtagHash {
+		return nil // Nothing has changed.
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateNetworkForward(ctx, n.ID(), curForwardID, newForward.Writable())
+	})
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetworkForward(ctx, n.ID(), curForwardID, curForward.Writable())
+		})
+		_ = n.forwardSetupFirewall()
+		_ = n.forwardBGPSetupPrefixes()
+	})
+
+	err = n.forwardSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// ForwardDelete deletes a network forward.
+func (n *bridge) ForwardDelete(listenAddress string, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member forwards.
+	var forwardID int64
+	var forward *api.NetworkForward
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		forwardID, forward, err = tx.GetNetworkForward(ctx, n.ID(), memberSpecific, listenAddress)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteNetworkForward(ctx, n.ID(), forwardID)
+	})
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		newForward := api.NetworkForwardsPost{
+			NetworkForwardPut: forward.Writable(),
+			ListenAddress:     forward.ListenAddress,
+		}
+
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			_, _ = tx.CreateNetworkForward(ctx, n.ID(), memberSpecific, &newForward)
+
+			return nil
+		})
+
+		_ = n.forwardSetupFirewall()
+		_ = n.forwardBGPSetupPrefixes()
+	})
+
+	err = n.forwardSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err = n.forwardBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
+	}
+
+	revert.Success()
+	return nil
+}
+
+// forwardSetupFirewall applies all network address forwards defined for this network and this member.
+func (n *bridge) forwardSetupFirewall() error {
+	memberSpecific := true // Get all forwards for this cluster member.
+
+	var forwards map[int64]*api.NetworkForward
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		forwards, err = tx.GetNetworkForwards(ctx, n.ID(), memberSpecific)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network forwards: %w", err)
+	}
+
+	var fwForwards []firewallDrivers.AddressForward
+	ipVersions := make(map[uint]struct{})
+
+	for _, forward := range forwards {
+		// Convert listen address to subnet so we can check its valid and can be used.
+		listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
+		if err != nil {
+			return fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+		}
+
+		// Track which IP versions we are using.
+		if listenAddressNet.IP.To4() == nil {
+			ipVersions[6] = struct{}{}
+		} else {
+			ipVersions[4] = struct{}{}
+		}
+
+		portMaps, err := n.forwardValidate(listenAddressNet.IP, forward.Writable())
+		if err != nil {
+			return fmt.Errorf("Failed validating firewall address forward for listen address %q: %w", forward.ListenAddress, err)
+		}
+
+		fwForwards = append(fwForwards, n.forwardConvertToFirewallForwards(listenAddressNet.IP, net.ParseIP(forward.Config["target_address"]), portMaps)...)
+	}
+
+	if len(forwards) > 0 {
+		// Check if br_netfilter is enabled to, and warn if not.
+		brNetfilterWarning := false
+		for ipVersion := range ipVersions {
+			err = BridgeNetfilterEnabled(ipVersion)
+			if err != nil {
+				brNetfilterWarning = true
+				msg := fmt.Sprintf("IPv%d bridge netfilter not enabled. Instances using the bridge will not be able to connect to the forward listen IPs", ipVersion)
+				n.logger.Warn(msg, logger.Ctx{"err": err})
+				err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+					return tx.UpsertWarningLocalNode(ctx, n.project, entity.TypeNetwork, int(n.id), warningtype.ProxyBridgeNetfilterNotEnabled, fmt.Sprintf("%s: %v", msg, err))
+				})
+				if err != nil {
+					n.logger.Warn("Failed creating warning", logger.Ctx{"err": err})
+				}
+			}
+		}
+
+		if !brNetfilterWarning {
+			err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(n.state.DB.Cluster, n.project, warningtype.ProxyBridgeNetfilterNotEnabled, entity.TypeNetwork, int(n.id))
+			if err != nil {
+				n.logger.Warn("Failed resolving warning", logger.Ctx{"err": err})
+			}
+		}
+	}
+
+	err = n.state.Firewall.NetworkApplyForwards(n.name, fwForwards)
+	if err != nil {
+		return fmt.Errorf("Failed applying firewall address forwards: %w", err)
+	}
+
+	return nil
+}
+
+// loadBalancerValidate validates the load balancer request.
+func (n *bridge) loadBalancerValidate(listenAddress net.IP, loadBalancer api.NetworkLoadBalancerPut) ([]*loadBalancerPortMap, error) {
+	err := n.checkAddressNotInOVNRange(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	portMaps, err := n.common.loadBalancerValidate(listenAddress, loadBalancer)
+	if err != nil {
+		return nil, err
+	}
+
+	portMapsPools, err := n.loadBalancerPoolPortMaps(listenAddress, loadBalancer)
+	if err != nil {
+		return nil, err
+	}
+
+	portMaps = append(portMaps, portMapsPools...)
+	return portMaps, nil
+}
+
+// loadBalancerInstanceIsLocal returns true if the instance is running on this member.
+func (n *bridge) loadBalancerInstanceIsLocal(inst instance.Instance) bool {
+	return !n.state.ServerClustered || inst.Location() == n.state.ServerName
+}
+
+// loadBalancerPoolPortMaps returns the port maps of the load balancer ports targeting a pool.
+// As bridge load balancers are applied on the member they are defined on, only the running pool instances located
+// on this member are used as targets.
+func (n *bridge) loadBalancerPoolPortMaps(listenAddress net.IP, loadBalancer api.NetworkLoadBalancerPut) ([]*loadBalancerPortMap, error) {
+	var portMaps []*loadBalancerPortMap
+
+	listenIsIP4 := listenAddress.To4() != nil
+
+	for _, portSpec := range loadBalancer.Ports {
+		if portSpec.TargetPool == "" {
+			continue
+		}
+
+		var pool *api.NetworkLoadBalancerPool
+
+		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			pool, err = n.getLoadBalancerPool(ctx, tx.Tx(), portSpec.TargetPool)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// If the pool protocol is unset, assume a default of "tcp".
+		poolProtocol := pool.Config["protocol"]
+		if poolProtocol == "" {
+			poolProtocol = "tcp"
+		}
+
+		if poolProtocol != portSpec.Protocol {
+			return nil, fmt.Errorf("Cannot use pool protocol %q with port protocol %q", poolProtocol, portSpec.Protocol)
+		}
+
+		listenPort, err := strconv.ParseUint(portSpec.ListenPort, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("Failed converting listen port %q: %w", portSpec.ListenPort, err)
+		}
+
+		portMap := loadBalancerPortMap{
+			listenPorts: []uint64{listenPort},
+			protocol:    portSpec.Protocol,
+			targets:     make([]forwardTarget, 0, len(pool.Instances)),
+		}
+
+		for _, poolInstance := range pool.Instances {
+			inst, err := instance.LoadByProjectAndName(n.state, n.project, poolInstance.Name)
+			if err != nil {
+				return nil, fmt.Errorf("Failed loading instance %q: %w", poolInstance.Name, err)
+			}
+
+			// An instance might use its own port.
+			targetPort := pool.Config["target_port"]
+			if poolInstance.TargetPort != "" {
+				targetPort = poolInstance.TargetPort
+			}
+
+			targetPortInt, err := strconv.ParseUint(targetPort, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("Failed converting pool target port %q: %w", targetPort, err)
+			}
+
+			instanceHasNICInNetwork := false
+
+			// Find NICs connected to this network.
+			for devName, devConfig := range inst.ExpandedDevices() {
+				if devConfig["type"] != "nic" || !NICUsesNetwork(devConfig, &api.Network{Name: n.name}) {
+					continue
+				}
+
+				instanceHasNICInNetwork = true
+
+				if !n.loadBalancerInstanceIsLocal(inst) || !inst.IsRunning() {
+					continue
+				}
+
+				for _, ip := range n.loadBalancerInstanceNICAddresses(inst, devName, devConfig) {
+					// Skip IPs that don't match the listen address family.
+					if (ip.To4() != nil) != listenIsIP4 {
+						continue
+					}
+
+					portMap.targets = append(portMap.targets, forwardTarget{
+						address: ip,
+						instance: &forwardTargetInstance{
+							name:       inst.Name(),
+							uuid:       inst.LocalConfig()["volatile.uuid"],
+							deviceName: devName,
+						},
+						ports: []uint64{targetPortInt},
+					})
+				}
+			}
+
+			if !instanceHasNICInNetwork {
+				return nil, fmt.Errorf("Instance %q does not have a device in network %q", poolInstance.Name, n.name)
+			}
+		}
+
+		// If none of the pool instances can be targeted, don't bother creating a port map.
+		if len(portMap.targets) == 0 {
+			continue
+		}
+
+		// Check and configure the health check.
+		portMap.healthCheck, err = n.checkPoolHealthCheck(pool)
+		if err != nil {
+			return nil, fmt.Errorf("Failed configuring load balancer health check for pool %q: %w", pool.Name, err)
+		}
+
+		portMaps = append(portMaps, &portMap)
+	}
+
+	return portMaps, nil
+}
+
+// loadBalancerInstanceNICAddresses returns the addresses of an instance NIC connected to the network.
+// These are the static addresses of the NIC, the addresses leased by the local DHCP server and the EUI64 address
+// derived from the NIC's MAC address when using stateless IPv6.
+func (n *bridge) loadBalancerInstanceNICAddresses(inst instance.Instance, devName string, devConfig map[string]string) []net.IP {
+	var addresses []net.IP
+
+	for _, key := range []string{"ipv4.address", "ipv6.address"} {
+		ip := net.ParseIP(devConfig[key])
+		if ip != nil {
+			addresses = append(addresses, ip)
+		}
+	}
+
+	mac := devConfig["hwaddr"]
+	if mac == "" {
+		mac = inst.LocalConfig()["volatile."+devName+".hwaddr"]
+	}
+
+	hwAddr, err := net.ParseMAC(mac)
+	if err != nil {
+		return addresses
+	}
+
+	leaseAddresses, err := GetLeaseAddresses(n.name, hwAddr.String())
+	if err == nil {
+		for _, ip := range leaseAddresses {
+			if !slices.ContainsFunc(addresses, ip.Equal) {
+				addresses = append(addresses, ip)
+			}
+		}
+	}
+
+	_, netIP6, _ := net.ParseCIDR(n.config["ipv6.address"])
+	if netIP6 != nil && devConfig["ipv6.address"] == "" && shared.IsFalseOrEmpty(n.config["ipv6.dhcp.stateful"]) {
+		eui64IP6, err := eui64.ParseMAC(netIP6.IP, hwAddr)
+		if err == nil && !slices.ContainsFunc(addresses, eui64IP6.Equal) {
+			addresses = append(addresses, eui64IP6)
+		}
+	}
+
+	return addresses
+}
+
+// loadBalancerConvertToFirewallLoadBalancers converts the load balancer port maps to firewall load balancers.
+// Returns the targets to be probed by the health monitor alongside. The targets reported offline by the health
+// monitor are left out of the firewall load balancers.
+func (n *bridge) loadBalancerConvertToFirewallLoadBalancers(listenAddress net.IP, portMaps []*loadBalancerPortMap, monitor *loadBalancerHealthMonitor) ([]firewallDrivers.LoadBalancer, []loadBalancerHealthTarget) {
+	var fwLoadBalancers []firewallDrivers.LoadBalancer
+	var healthTargets []loadBalancerHealthTarget
+
+	for _, portMap := range portMaps {
+		for i, lp := range portMap.listenPorts {
+			fwLoadBalancer := firewallDrivers.LoadBalancer{
+				ListenAddress: listenAddress,
+				Protocol:      portMap.protocol,
+				ListenPort:    lp,
+			}
+
+			for _, target := range portMap.targets {
+				targetPort := lp // Default to using same port as listen port for target port.
+				targetPortsLen := len(target.ports)
+
+				if targetPortsLen == 1 {
+					// If a single target port is specified, forward all listen ports to it.
+					targetPort = target.ports[0]
+				} else if targetPortsLen > 1 {
+					// If more than 1 target port specified, use listen port index to get the
+					// target port to use.
+					targetPort = target.ports[i]
+				}
+
+				if portMap.healthCheck != nil {
+					healthTarget := loadBalancerHealthTarget{
+						protocol:    portMap.protocol,
+						address:     target.address,
+						port:        targetPort,
+						healthCheck: *portMap.healthCheck,
+					}
+
+					healthTargets = append(healthTargets, healthTarget)
+
+					if monitor != nil && monitor.status(healthTarget) == loadBalancerHealthStatusOffline {
+						continue
+					}
+				}
+
+				fwLoadBalancer.Targets = append(fwLoadBalancer.Targets, firewallDrivers.LoadBalancerTarget{
+					Address: target.address,
+					Port:    targetPort,
+				})
+			}
+
+			// Skip the listen port if all of its targets are offline.
+			if len(fwLoadBalancer.Targets) == 0 {
+				continue
+			}
+
+			fwLoadBalancers = append(fwLoadBalancers, fwLoadBalancer)
+		}
+	}
+
+	return fwLoadBalancers, healthTargets
+}
+
+// LoadBalancerCreate creates a network load balancer.
+func (n *bridge) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) (net.IP, error) {
+	memberSpecific := true // bridge supports per-member load balancers.
+
+	// Convert listen address to subnet so we can check its valid and can be used.
+	listenAddressNet, err := ParseIPToNet(loadBalancer.ListenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing load balancer listen address %q: %w", loadBalancer.ListenAddress, err)
+	}
+
+	if listenAddressNet.IP.IsUnspecified() {
+		return nil, api.StatusErrorf(http.StatusNotImplemented, "Automatic listen address allocation not supported for drivers of type %q", n.netType)
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check if there is an existing load balancer using the same listen address.
+		_, _, err := tx.GetNetworkLoadBalancer(ctx, n.ID(), memberSpecific, loadBalancer.ListenAddress)
+
+		return err
+	})
+	if err == nil {
+		return nil, api.StatusErrorf(http.StatusConflict, "A load balancer for that listen address already exists")
+	}
+
+	_, err = n.loadBalancerValidate(listenAddressNet.IP, loadBalancer.NetworkLoadBalancerPut)
+	if err != nil {
+		return nil, err
+	}
+
+	isValid, err := n.checkListenAddressNotInUse(listenAddressNet)
+	if err != nil {
+		return nil, err
+	} else if !isValid {
+		// This error is purposefully vague so that it doesn't reveal any names of
+		// resources potentially outside of the network.
+		return nil, fmt.Errorf("Load balancer listen address %q overlaps with another network or NIC", listenAddressNet.String())
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	var loadBalancerID int64
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Create load balancer DB record.
+		loadBalancerID, err = tx.CreateNetworkLoadBalancer(ctx, n.ID(), memberSpecific, &loadBalancer)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteNetworkLoadBalancer(ctx, n.ID(), loadBalancerID)
+		})
+		_ = n.loadBalancerSetupFirewall()
+		_ = n.loadBalancerBGPSetupPrefixes()
+	})
+
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return nil, err
+	}
+
+	// If br_netfilter is enabled, enable hairpin mode on each NIC's bridge port in case the instance attempts
+	// to connect to the listener of a load balancer targeting itself.
+	if n.hairpinRequired() {
+		var listenAddresses map[int64]string
+
+		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			listenAddresses, err = tx.GetNetworkLoadBalancerListenAddresses(ctx, n.ID(), true)
+
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading network load balancers: %w", err)
+		}
+
+		// If we are the first load balancer on this bridge, enable hairpin mode on active NIC ports.
+		if len(listenAddresses) <= 1 {
+			err = n.enableNICHairpin()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return nil, fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	revert.Success()
+	return listenAddressNet.IP, nil
+}
+
+// LoadBalancerUpdate updates a network load balancer.
+func (n *bridge) LoadBalancerUpdate(listenAddress string, req api.NetworkLoadBalancerPut, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member load balancers.
+
+	var curLoadBalancerID int64
+	var curLoadBalancer *api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		curLoadBalancerID, curLoadBalancer, err = tx.GetNetworkLoadBalancer(ctx, n.ID(), memberSpecific, listenAddress)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = n.loadBalancerValidate(net.ParseIP(curLoadBalancer.ListenAddress), req)
+	if err != nil {
+		return err
+	}
+
+	curLoadBalancerEtagHash, err := util.EtagHash(curLoadBalancer.Etag())
+	if err != nil {
+		return err
+	}
+
+	newLoadBalancer := api.NetworkLoadBalancer{
+		ListenAddress: curLoadBalancer.ListenAddress,
+	}
+
+	newLoadBalancer.SetWritable(req)
+
+	newLoadBalancerEtagHash, err := util.EtagHash(newLoadBalancer.Etag())
+	if err != nil {
+		return err
+	}
+
+	if curLoadBalancerEtagHash == newLoadBalancerEtagHash {
+		return nil // Nothing has changed.
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateNetworkLoadBalancer(ctx, n.ID(), curLoadBalancerID, newLoadBalancer.Writable())
+	})
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetworkLoadBalancer(ctx, n.ID(), curLoadBalancerID, curLoadBalancer.Writable())
+		})
+		_ = n.loadBalancerSetupFirewall()
+	})
+
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// LoadBalancerDelete deletes a network load balancer.
+func (n *bridge) LoadBalancerDelete(listenAddress string, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member load balancers.
+
+	var loadBalancerID int64
+	var loadBalancer *api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		loadBalancerID, loadBalancer, err = tx.GetNetworkLoadBalancer(ctx, n.ID(), memberSpecific, listenAddress)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteNetworkLoadBalancer(ctx, n.ID(), loadBalancerID)
+	})
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		newLoadBalancer := api.NetworkLoadBalancersPost{
+			NetworkLoadBalancerPut: loadBalancer.Writable(),
+			ListenAddress:          loadBalancer.ListenAddress,
+		}
+
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			_, _ = tx.CreateNetworkLoadBalancer(ctx, n.ID(), memberSpecific, &newLoadBalancer)
+
+			return nil
+		})
+
+		_ = n.loadBalancerSetupFirewall()
+		_ = n.loadBalancerBGPSetupPrefixes()
+	})
+
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	revert.Success()
+	return nil
+}
+
+// loadBalancerSetupFirewall applies all network load balancers defined for this network and this member.
+// The health monitor of the network is started if any load balancer is defined and stopped otherwise.
+func (n *bridge) loadBalancerSetupFirewall() error {
+	return n.loadBalancerApplyFirewall(false)
+}
+
+// loadBalancerApplyFirewall applies all network load balancers defined for this network and this member.
+// If onlyIfChanged is true, the firewall isn't updated when its load balancers would be the same as the ones last
+// applied, so that the rules aren't briefly removed and re-added on every refresh.
+func (n *bridge) loadBalancerApplyFirewall(onlyIfChanged bool) error {
+	memberSpecific := true // Get all load balancers for this cluster member.
+
+	var loadBalancers map[int64]*api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		loadBalancers, err = tx.GetNetworkLoadBalancers(ctx, n.ID(), memberSpecific)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
+	}
+
+	if len(loadBalancers) == 0 {
+		loadBalancerHealthMonitorStop(n.name)
+
+		err = n.state.Firewall.NetworkApplyLoadBalancers(n.name, nil)
+		if err != nil {
+			return fmt.Errorf("Failed applying firewall load balancers: %w", err)
+		}
+
+		return nil
+	}
 
-				// Find any external subnets used for network SNAT.
-				if netInfo.Config[keyPrefix+".nat.address"] != "" {
-					key := keyPrefix + ".nat.address"
+	// The load balancers are re-applied by the health monitor when a target goes offline or comes back online,
+	// as well as periodically to pick up the changes of the pool instances.
+	projectName := n.project
+	networkName := n.name
+	monitor := loadBalancerHealthMonitorStart(networkName, func() {
+		netw, err := LoadByName(n.state, projectName, networkName)
+		if err != nil {
+			return
+		}
 
-					subnetSize := 128
-					if keyPrefix == "ipv4" {
-						subnetSize = 32
-					}
+		b, ok := netw.(*bridge)
+		if !ok || !b.isRunning() {
+			return
+		}
 
-					_, ipNet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", netInfo.Config[key], subnetSize))
-					if err != nil {
-						return nil, fmt.Errorf("Failed parsing %q of %q in project %q: %w", key, netInfo.Name, netProject, err)
-					}
+		err = b.loadBalancerApplyFirewall(true)
+		if err != nil {
+			b.logger.Warn("Failed refreshing load balancers", logger.Ctx{"err": err})
+		}
+	})
 
-					externalSubnets = append(externalSubnets, externalSubnetUsage{
-						subnet:         *ipNet,
-						networkProject: netProject,
-						networkName:    netInfo.Name,
-						usageType:      subnetUsageNetworkSNAT,
-					})
-				}
+	var fwLoadBalancers []firewallDrivers.LoadBalancer
+	var healthTargets []loadBalancerHealthTarget
 
-				// Find any routes being used by the network.
-				for _, cidr := range shared.SplitNTrimSpace(netInfo.Config[keyPrefix+".routes"], ",", -1, true) {
-					_, ipNet, err := net.ParseCIDR(cidr)
-					if err != nil {
-						continue // Skip invalid/unspecified network addresses.
-					}
+	for _, loadBalancer := range loadBalancers {
+		listenAddress := net.ParseIP(loadBalancer.ListenAddress)
 
-					externalSubnets = append(externalSubnets, externalSubnetUsage{
-						subnet:         *ipNet,
-						networkProject: netProject,
-						networkName:    netInfo.Name,
-						usageType:      subnetUsageNetwork,
-					})
-				}
-			}
+		portMaps, err := n.loadBalancerValidate(listenAddress, loadBalancer.Writable())
+		if err != nil {
+			return fmt.Errorf("Failed validating firewall load balancer for listen address %q: %w", loadBalancer.ListenAddress, err)
 		}
+
+		lbFirewallLoadBalancers, lbHealthTargets := n.loadBalancerConvertToFirewallLoadBalancers(listenAddress, portMaps, monitor)
+		fwLoadBalancers = append(fwLoadBalancers, lbFirewallLoadBalancers...)
+		healthTargets = append(healthTargets, lbHealthTargets...)
 	}
 
-	return externalSubnets, nil
-}
+	monitor.sync(healthTargets)
 
-// bridgedNICExternalRoutes returns a list of external routes currently used by bridged NICs that are connected to
-// networks specified.
-func (n *bridge) bridgedNICExternalRoutes(bridgeProjectNetworks map[string][]*api.Network) ([]externalSubnetUsage, error) {
-	externalRoutes := make([]externalSubnetUsage, 0)
+	key := loadBalancerFirewallKey(fwLoadBalancers)
+	if onlyIfChanged && monitor.isApplied(key) {
+		return nil
+	}
 
-	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
-			// Get the instance's effective network project name.
-			instNetworkProject := project.NetworkProjectFromRecord(&p)
+	err = n.state.Firewall.NetworkApplyLoadBalancers(n.name, fwLoadBalancers)
+	if err != nil {
+		return fmt.Errorf("Failed applying firewall load balancers: %w", err)
+	}
 
-			if instNetworkProject != api.ProjectDefaultName {
-				return nil // Managed bridge networks can only exist in default project.
-			}
+	monitor.setApplied(key)
 
-			devices := instancetype.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+	return nil
+}
 
-			// Iterate through each of the instance's devices, looking for bridged NICs that are linked to
-			// networks specified.
-			for devName, devConfig := range devices {
-				if devConfig["type"] != "nic" {
-					continue
-				}
+// LoadBalancerPoolCreate creates a network load balancer pool.
+func (n *bridge) LoadBalancerPoolCreate(loadBalancerPool api.NetworkLoadBalancerPoolsPost) error {
+	return n.loadBalancerPoolCreate(loadBalancerPool)
+}
 
-				// Check whether the NIC device references one of the networks supplied.
-				if !NICUsesNetwork(devConfig, bridgeProjectNetworks[instNetworkProject]...) {
-					continue
-				}
+// LoadBalancerPoolUpdate updates a network load balancer pool.
+// The load balancers of this member are re-applied, while the other members pick up the change on their next refresh.
+func (n *bridge) LoadBalancerPoolUpdate(poolName string, loadBalancerPoolPut api.NetworkLoadBalancerPoolPut) error {
+	var loadBalancerPoolDB *dbCluster.NetworksLoadBalancerPool
+	var loadBalancerPool *api.NetworkLoadBalancerPool
+	var loadBalancerRequiresUpdate bool
 
-				// For bridged NICs that are connected to networks specified, check if they have any
-				// routes or external routes configured, and if so add them to the list to return.
-				for _, key := range []string{"ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external"} {
-					for _, cidr := range shared.SplitNTrimSpace(devConfig[key], ",", -1, true) {
-						_, ipNet, _ := net.ParseCIDR(cidr)
-						if ipNet == nil {
-							// Skip if NIC device doesn't have a valid route.
-							continue
-						}
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
 
-						externalRoutes = append(externalRoutes, externalSubnetUsage{
-							subnet:          *ipNet,
-							networkProject:  instNetworkProject,
-							networkName:     devConfig["network"],
-							instanceProject: inst.Project,
-							instanceName:    inst.Name,
-							instanceDevice:  devName,
-							usageType:       subnetUsageInstance,
-						})
-					}
-				}
-			}
+		loadBalancerPoolDB, loadBalancerPool, loadBalancerRequiresUpdate, err = n.loadBalancerPoolUpdateRecord(ctx, tx, poolName, loadBalancerPoolPut)
 
-			return nil
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if !loadBalancerRequiresUpdate {
+		return nil
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.UpdateNetworksLoadBalancerPool(ctx, tx.Tx(), &loadBalancerPoolDB.Row, loadBalancerPool.Config)
 		})
+		_ = n.loadBalancerSetupFirewall()
 	})
+
+	err = n.loadBalancerSetupFirewall()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return externalRoutes, nil
+	revert.Success()
+	return nil
 }
 
-// getExternalSubnetInUse returns information about usage of external subnets by bridge networks (and NICs
-// connected to them) on this member.
-func (n *bridge) getExternalSubnetInUse() ([]externalSubnetUsage, error) {
-	var err error
-	var projectNetworks map[string]map[int64]api.Network
-	var projectNetworksForwardsOnUplink map[string]map[int64][]string
-	var externalSubnets []externalSubnetUsage
+// LoadBalancerPoolDelete deletes a network load balancer pool.
+func (n *bridge) LoadBalancerPoolDelete(poolName string) error {
+	return n.loadBalancerPoolDelete(poolName)
+}
 
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Get all managed networks across all projects.
-		projectNetworks, err = tx.GetCreatedNetworks(ctx)
-		if err != nil {
-			return fmt.Errorf("Failed loading all networks: %w", err)
-		}
+// LoadBalancerPoolState returns the state of a network load balancer pool.
+// The targets are reported for the load balancers defined on this member, with the status from its health monitor.
+func (n *bridge) LoadBalancerPoolState(poolName string) (*api.NetworkLoadBalancerPoolState, error) {
+	var pool *api.NetworkLoadBalancerPool
+	var loadBalancers map[int64]*api.NetworkLoadBalancer
 
-		// Get all network forward listen addresses for forwards assigned to this specific cluster member.
-		projectNetworksForwardsOnUplink, err = tx.GetProjectNetworkForwardListenAddressesOnMember(ctx)
-		if err != nil {
-			return fmt.Errorf("Failed loading network forward listen addresses: %w", err)
-		}
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
 
-		externalSubnets, err = n.common.getExternalSubnetInUse(ctx, tx, n.name, true)
+		pool, err = n.getLoadBalancerPool(ctx, tx.Tx(), poolName)
 		if err != nil {
-			return fmt.Errorf("Failed getting external subnets in use: %w", err)
+			return err
 		}
 
-		return nil
+		loadBalancers, err = tx.GetNetworkLoadBalancers(ctx, n.ID(), true)
+
+		return err
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Get managed bridge networks.
-	bridgeProjectNetworks := n.bridgeProjectNetworks(projectNetworks)
+	// Build a map of load balancer listen addresses using the pool together with their listen ports.
+	poolListenAddresses := make(map[string][]string)
+	for _, lb := range loadBalancers {
+		for _, port := range lb.Ports {
+			if port.TargetPool == poolName {
+				poolListenAddresses[lb.ListenAddress] = append(poolListenAddresses[lb.ListenAddress], port.ListenPort)
+			}
+		}
+	}
 
-	// Get external subnets used by other managed bridge networks.
-	bridgeNetworkExternalSubnets, err := n.bridgeNetworkExternalSubnets(bridgeProjectNetworks)
+	healthCheck, err := n.checkPoolHealthCheck(pool)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Failed configuring load balancer health check for pool %q: %w", pool.Name, err)
 	}
 
-	// Get external routes configured on bridged NICs.
-	bridgedNICExternalRoutes, err := n.bridgedNICExternalRoutes(bridgeProjectNetworks)
-	if err != nil {
-		return nil, err
+	protocol := pool.Config["protocol"]
+	if protocol == "" {
+		protocol = "tcp"
 	}
 
-	externalSubnets = append(externalSubnets, bridgeNetworkExternalSubnets...)
-	externalSubnets = append(externalSubnets, bridgedNICExternalRoutes...)
+	monitor := loadBalancerHealthMonitorGet(n.name)
 
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Detect if there are any conflicting proxy devices on all instances with the to be created network forward
-		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
-			devices := instancetype.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+	poolState := &api.NetworkLoadBalancerPoolState{
+		// For the initialize size assume each instance has at least one device in the network.
+		Targets: make([]api.NetworkLoadBalancerPoolTarget, 0, len(pool.Instances)),
+	}
 
-			for devName, devConfig := range devices {
-				if devConfig["type"] != "proxy" {
+	for _, poolInstance := range pool.Instances {
+		inst, err := instance.LoadByProjectAndName(n.state, n.project, poolInstance.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading instance %q: %w", poolInstance.Name, err)
+		}
+
+		targetPort := pool.Config["target_port"]
+		if poolInstance.TargetPort != "" {
+			targetPort = poolInstance.TargetPort
+		}
+
+		targetPortInt, err := strconv.ParseUint(targetPort, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("Failed converting pool target port %q: %w", targetPort, err)
+		}
+
+		for devName, devConfig := range inst.ExpandedDevices() {
+			if devConfig["type"] != "nic" || !NICUsesNetwork(devConfig, &api.Network{Name: n.name}) {
+				continue
+			}
+
+			// Only the running instances located on this member are targeted.
+			var addresses []net.IP
+			if n.loadBalancerInstanceIsLocal(inst) && inst.IsRunning() {
+				addresses = n.loadBalancerInstanceNICAddresses(inst, devName, devConfig)
+			}
+
+			for listenAddr, listenPorts := range poolListenAddresses {
+				listenIP := net.ParseIP(listenAddr)
+				if listenIP == nil {
 					continue
 				}
 
-				proxyListenAddr, err := ProxyParseAddr(devConfig["listen"])
-				if err != nil {
-					return err
-				}
+				targetFound := false
 
-				proxySubnet, err := ParseIPToNet(proxyListenAddr.Address)
-				if err != nil {
-					continue // If proxy listen is not a valid IP it cannot conflict.
-				}
+				for _, address := range addresses {
+					// Match IPv4 target to IPv4 load balancer, IPv6 to IPv6.
+					if (address.To4() != nil) != (listenIP.To4() != nil) {
+						continue
+					}
 
-				externalSubnets = append(externalSubnets, externalSubnetUsage{
-					usageType:       subnetUsageProxy,
-					subnet:          *proxySubnet,
-					instanceProject: inst.Project,
-					instanceName:    inst.Name,
-					instanceDevice:  devName,
-				})
-			}
+					targetFound = true
 
-			return nil
-		})
-	})
-	if err != nil {
-		return nil, err
-	}
+					status := loadBalancerHealthStatusUnknown
+					if healthCheck != nil && monitor != nil {
+						status = monitor.status(loadBalancerHealthTarget{protocol: protocol, address: address, port: targetPortInt})
+						if status == "" {
+							status = loadBalancerHealthStatusPending
+						}
+					}
 
-	// Add forward listen addresses to this list.
-	for projectName, networks := range projectNetworksForwardsOnUplink {
-		for networkID, listenAddresses := range networks {
-			for _, listenAddress := range listenAddresses {
-				// Convert listen address to subnet.
-				listenAddressNet, err := ParseIPToNet(listenAddress)
-				if err != nil {
-					return nil, fmt.Errorf("Invalid existing forward listen address %q", listenAddress)
+					for _, port := range listenPorts {
+						poolState.Targets = append(poolState.Targets, api.NetworkLoadBalancerPoolTarget{
+							ListenAddress: listenAddr,
+							ListenPort:    port,
+							Name:          poolInstance.Name,
+							Address:       address.String(),
+							Port:          targetPort,
+							Device:        devName,
+							Status:        status,
+						})
+					}
 				}
 
-				// Create an externalSubnetUsage for the listen address by using the network ID
-				// of the listen address to retrieve the already loaded network name from the
-				// projectNetworks map.
-				externalSubnets = append(externalSubnets, externalSubnetUsage{
-					subnet:         *listenAddressNet,
-					networkProject: projectName,
-					networkName:    projectNetworks[projectName][networkID].Name,
-					usageType:      subnetUsageNetworkForward,
-				})
+				// Add state for instances which aren't targeted.
+				if !targetFound {
+					for _, port := range listenPorts {
+						poolState.Targets = append(poolState.Targets, api.NetworkLoadBalancerPoolTarget{
+							ListenAddress: listenAddr,
+							ListenPort:    port,
+							Name:          poolInstance.Name,
+							Device:        devName,
+							Status:        loadBalancerHealthStatusUnknown,
+						})
+					}
+				}
 			}
 		}
 	}
 
-	return externalSubnets, nil
+	return poolState, nil
 }
 
-// forwardValidate validates the forward request.
-func (n *bridge) forwardValidate(listenAddress net.IP, forward api.NetworkForwardPut) ([]*forwardPortMap, error) {
-	err := n.checkAddressNotInOVNRange(listenAddress)
-	if err != nil {
-		return nil, err
+// aclSetupFirewall applies the rules of the ACLs assigned to the network to the firewall.
+func (n *bridge) aclSetupFirewall() error {
+	if n.config["security.acls"] == "" {
+		acl.FirewallLogMonitorUpdate(n.state, n.name, false)
+		return nil
 	}
 
-	return n.common.forwardValidate(listenAddress, forward)
+	aclNet := acl.NetworkACLUsage{
+		Name:   n.Name(),
+		Type:   n.Type(),
+		ID:     n.ID(),
+		Config: n.Config(),
+	}
+
+	n.logger.Debug("Applying up firewall ACLs")
+
+	return acl.FirewallApplyACLRules(context.TODO(), n.state, n.Project(), aclNet)
 }
 
-// ForwardCreate creates a network forward.
-func (n *bridge) ForwardCreate(forward api.NetworkForwardsPost, clientType request.ClientType) (net.IP, error) {
-	memberSpecific := true // bridge supports per-member forwards.
+// PeerCreate creates a network peering.
+func (n *bridge) PeerCreate(peer api.NetworkPeersPost, clientType request.ClientType) error {
+	// The peering has already been recorded by the member the request was sent to, so only apply it locally.
+	if clientType != request.ClientTypeNormal {
+		return n.peerRefresh()
+	}
 
-	// Convert listen address to subnet so we can check its valid and can be used.
-	listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
-	if err != nil {
-		return nil, fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Perform create-time validation.
+
+	// Default to network's project if target project not specified.
+	if peer.TargetProject == "" {
+		peer.TargetProject = n.Project()
 	}
 
-	if listenAddressNet.IP.IsUnspecified() {
-		return nil, api.StatusErrorf(http.StatusNotImplemented, "Automatic listen address allocation not supported for drivers of type %q", n.netType)
+	// Target network name is required.
+	if peer.TargetNetwork == "" {
+		return api.StatusErrorf(http.StatusBadRequest, "Target network is required")
 	}
 
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Check if there is an existing forward using the same listen address.
-		_, _, err := tx.GetNetworkForward(ctx, n.ID(), memberSpecific, forward.ListenAddress)
+	var peers map[int64]*api.NetworkPeer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		// Check if there is an existing peer using the same name, or whether there is already a peering (in any
+		// state) to the target network.
+		peers, err = tx.GetNetworkPeers(ctx, n.ID())
 
 		return err
 	})
-	if err == nil {
-		return nil, api.StatusErrorf(http.StatusConflict, "A forward for that listen address already exists")
-	}
-
-	_, err = n.forwardValidate(listenAddressNet.IP, forward.NetworkForwardPut)
-	if err != nil {
-		return nil, err
-	}
-
-	externalSubnetsInUse, err := n.getExternalSubnetInUse()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	checkAddressNotInUse := func(netip *net.IPNet) (bool, error) {
-		// Check the listen address subnet doesn't fall within any existing network external subnets.
-		for _, externalSubnetUser := range externalSubnetsInUse {
-			// Check if usage is from our own network.
-			if externalSubnetUser.networkProject == n.project && externalSubnetUser.networkName == n.name {
-				// Skip checking conflict with our own network's subnet or SNAT address.
-				// But do not allow other conflict with other usage types within our own network.
-				if externalSubnetUser.usageType == subnetUsageNetwork || externalSubnetUser.usageType == subnetUsageNetworkSNAT {
-					continue
-				}
-			}
-
-			if SubnetContains(&externalSubnetUser.subnet, netip) || SubnetContains(netip, &externalSubnetUser.subnet) {
-				return false, nil
-			}
+	for _, existingPeer := range peers {
+		if peer.Name == existingPeer.Name {
+			return api.StatusErrorf(http.StatusConflict, "A peer for that name already exists")
 		}
 
-		return true, nil
+		if peer.TargetProject == existingPeer.TargetProject && peer.TargetNetwork == existingPeer.TargetNetwork {
+			return api.StatusErrorf(http.StatusConflict, "A peer for that target network already exists")
+		}
 	}
 
-	isValid, err := checkAddressNotInUse(listenAddressNet)
+	// Perform general (create and update) validation.
+	err = n.peerValidate(peer.Name, &peer.NetworkPeerPut)
 	if err != nil {
-		return nil, err
-	} else if !isValid {
-		// This error is purposefully vague so that it doesn't reveal any names of
-		// resources potentially outside of the network.
-		return nil, fmt.Errorf("Forward listen address %q overlaps with another network or NIC", listenAddressNet.String())
+		return err
 	}
 
-	revert := revert.New()
-	defer revert.Fail()
-
-	var forwardID int64
+	var peerID int64
+	var mutualExists bool
 
 	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Create forward DB record.
-		forwardID, err = tx.CreateNetworkForward(ctx, n.ID(), memberSpecific, &forward)
+		// Create peer DB record.
+		peerID, mutualExists, err = tx.CreateNetworkPeer(ctx, n.ID(), &peer)
 
 		return err
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	revert.Add(func() {
-		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			return tx.DeleteNetworkForward(ctx, n.ID(), forwardID)
-		})
-		_ = n.forwardSetupFirewall()
-		_ = n.forwardBGPSetupPrefixes()
+		_ = n.state.DB.Cluster.DeleteNetworkPeer(n.ID(), peerID)
+		_ = n.peerRefresh()
 	})
 
-	err = n.forwardSetupFirewall()
-	if err != nil {
-		return nil, err
-	}
-
-	// Check if hairpin mode needs to be enabled on active NIC bridge ports.
-	if n.config["bridge.driver"] != "openvswitch" {
-		brNetfilterEnabled := false
-		for _, ipVersion := range []uint{4, 6} {
-			if BridgeNetfilterEnabled(ipVersion) == nil {
-				brNetfilterEnabled = true
-				break
-			}
-		}
+	if mutualExists {
+		var peerInfo *api.NetworkPeer
 
-		// If br_netfilter is enabled and bridge has forwards, we enable hairpin mode on each NIC's bridge
-		// port in case any of the forwards target the NIC and the instance attempts to connect to the
-		// forward's listener. Without hairpin mode on the target of the forward will not be able to
-		// connect to the listener.
-		if brNetfilterEnabled {
-			var listenAddresses map[int64]string
+		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			// Load peering to get mutual peering info.
+			_, peerInfo, err = tx.GetNetworkPeer(ctx, n.ID(), peer.Name)
 
-			err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-				listenAddresses, err = tx.GetNetworkForwardListenAddresses(ctx, n.ID(), true)
+			return err
+		})
+		if err != nil {
+			return err
+		}
 
-				return err
-			})
-			if err != nil {
-				return nil, fmt.Errorf("Failed loading network forwards: %w", err)
-			}
+		if peerInfo.Status != api.NetworkStatusCreated {
+			return fmt.Errorf("Only peerings in %q state can be setup", api.NetworkStatusCreated)
+		}
 
-			// If we are the first forward on this bridge, enable hairpin mode on active NIC ports.
-			if len(listenAddresses) <= 1 {
-				filter := dbCluster.InstanceFilter{Node: &n.state.ServerName}
+		targetNet, err := LoadByName(n.state, peer.TargetProject, peer.TargetNetwork)
+		if err != nil {
+			return fmt.Errorf("Failed loading target network: %w", err)
+		}
 
-				err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-					return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
-						// Get the instance's effective network project name.
-						instNetworkProject := project.NetworkProjectFromRecord(&p)
+		_, ok := targetNet.(*bridge)
+		if !ok {
+			return errors.New("Target network is not bridge interface type")
+		}
 
-						if instNetworkProject != api.ProjectDefaultName {
-							return nil // Managed bridge networks can only exist in default project.
-						}
+		err = n.peerRefresh()
+		if err != nil {
+			return err
+		}
 
-						devices := instancetype.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
-
-						// Iterate through each of the instance's devices, looking for bridged NICs
-						// that are linked to this network.
-						for devName, devConfig := range devices {
-							if devConfig["type"] != "nic" {
-								continue
-							}
-
-							// Check whether the NIC device references our network..
-							if !NICUsesNetwork(devConfig, &api.Network{Name: n.Name()}) {
-								continue
-							}
-
-							hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", devName)]
-							if InterfaceExists(hostName) {
-								link := &ip.Link{Name: hostName}
-								err = link.BridgeLinkSetHairpin(true)
-								if err != nil {
-									return fmt.Errorf("Error enabling hairpin mode on bridge port %q: %w", link.Name, err)
-								}
-
-								n.logger.Debug("Enabled hairpin mode on NIC bridge port", logger.Ctx{"inst": inst.Name, "project": inst.Project, "device": devName, "dev": link.Name})
-							}
-						}
+		// Notify all other members to apply the peering.
+		notifier, err := cluster.NewOperationNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
 
-						return nil
-					}, filter)
-				})
-				if err != nil {
-					return nil, err
-				}
+		err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+			op, err := client.UseProject(n.project).CreateNetworkPeer(n.name, peer)
+			if err == nil {
+				err = op.Wait()
 			}
-		}
-	}
 
-	// Refresh exported BGP prefixes on local member.
-	err = n.forwardBGPSetupPrefixes()
-	if err != nil {
-		return nil, fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
+			return err
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	revert.Success()
-	return listenAddressNet.IP, nil
+	return nil
 }
 
-// ForwardUpdate updates a network forward.
-func (n *bridge) ForwardUpdate(listenAddress string, req api.NetworkForwardPut, clientType request.ClientType) error {
-	memberSpecific := true // bridge supports per-member forwards.
-
-	var curForwardID int64
-	var curForward *api.NetworkForward
+// PeerUpdate updates a network peering.
+func (n *bridge) PeerUpdate(peerName string, req api.NetworkPeerPut) error {
+	var curPeerID int64
+	var curPeer *api.NetworkPeer
 
 	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
-		curForwardID, curForward, err = tx.GetNetworkForward(ctx, n.ID(), memberSpecific, listenAddress)
+		curPeerID, curPeer, err = tx.GetNetworkPeer(ctx, n.ID(), peerName)
 
 		return err
 	})
@@ -3339,69 +5132,51 @@ func (n *bridge) ForwardUpdate(listenAddress string, req api.NetworkForwardPut,
 		return err
 	}
 
-	_, err = n.forwardValidate(net.ParseIP(curForward.ListenAddress), req)
+	err = n.peerValidate(peerName, &req)
 	if err != nil {
 		return err
 	}
 
-	curForwardEtagHash, err := util.EtagHash(curForward.Etag())
+	curPeerEtagHash, err := util.EtagHash(curPeer.Etag())
 	if err != nil {
 		return err
 	}
 
-	newForward := api.NetworkForward{
-		ListenAddress: curForward.ListenAddress,
-		Description:   req.Description,
-		Config:        req.Config,
-		Ports:         req.Ports,
+	newPeer := api.NetworkPeer{
+		Name: curPeer.Name,
 	}
 
-	newForwardEtagHash, err := util.EtagHash(newForward.Etag())
+	newPeer.SetWritable(req)
+
+	newPeerEtagHash, err := util.EtagHash(newPeer.Etag())
 	if err != nil {
 		return err
 	}
 
-	if curForwardEtagHash == newForwardEtagHash {
+	if curPeerEtagHash == newPeerEtagHash {
 		return nil // Nothing has changed.
 	}
 
-	revert := revert.New()
-	defer revert.Fail()
-
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		return tx.UpdateNetworkForward(ctx, n.ID(), curForwardID, newForward.Writable())
-	})
-	if err != nil {
-		return err
-	}
-
-	revert.Add(func() {
-		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			return tx.UpdateNetworkForward(ctx, n.ID(), curForwardID, curForward.Writable())
-		})
-		_ = n.forwardSetupFirewall()
-		_ = n.forwardBGPSetupPrefixes()
+	// Only the description and user config can change, which the firewall rules don't depend on.
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateNetworkPeer(ctx, n.ID(), curPeerID, newPeer.Writable())
 	})
+}
 
-	err = n.forwardSetupFirewall()
-	if err != nil {
-		return err
+// PeerDelete deletes a network peering.
+func (n *bridge) PeerDelete(peerName string, clientType request.ClientType) error {
+	// The peering has already been removed by the member the request was sent to, so only apply it locally.
+	if clientType != request.ClientTypeNormal {
+		return n.peerRefresh()
 	}
 
-	revert.Success()
-	return nil
-}
-
-// ForwardDelete deletes a network forward.
-func (n *bridge) ForwardDelete(listenAddress string, clientType request.ClientType) error {
-	memberSpecific := true // bridge supports per-member forwards.
-	var forwardID int64
-	var forward *api.NetworkForward
+	var peerID int64
+	var peer *api.NetworkPeer
 
 	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
-		forwardID, forward, err = tx.GetNetworkForward(ctx, n.ID(), memberSpecific, listenAddress)
+		peerID, peer, err = tx.GetNetworkPeer(ctx, n.ID(), peerName)
 
 		return err
 	})
@@ -3409,121 +5184,164 @@ func (n *bridge) ForwardDelete(listenAddress string, clientType request.ClientTy
 		return err
 	}
 
-	revert := revert.New()
-	defer revert.Fail()
-
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		return tx.DeleteNetworkForward(ctx, n.ID(), forwardID)
-	})
+	isUsed, err := n.peerIsUsed(peer.Name)
 	if err != nil {
 		return err
 	}
 
-	revert.Add(func() {
-		newForward := api.NetworkForwardsPost{
-			NetworkForwardPut: forward.Writable(),
-			ListenAddress:     forward.ListenAddress,
-		}
-
-		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			_, _ = tx.CreateNetworkForward(ctx, n.ID(), memberSpecific, &newForward)
+	if isUsed {
+		return errors.New("Cannot delete a Peer that is in use")
+	}
 
-			return nil
-		})
+	err = n.state.DB.Cluster.DeleteNetworkPeer(n.ID(), peerID)
+	if err != nil {
+		return err
+	}
 
-		_ = n.forwardSetupFirewall()
-		_ = n.forwardBGPSetupPrefixes()
-	})
+	// Only created peerings have firewall rules to remove.
+	if peer.Status != api.NetworkStatusCreated {
+		return nil
+	}
 
-	err = n.forwardSetupFirewall()
+	err = n.peerRefresh()
 	if err != nil {
 		return err
 	}
 
-	// Refresh exported BGP prefixes on local member.
-	err = n.forwardBGPSetupPrefixes()
+	// Notify all other members to remove the peering.
+	notifier, err := cluster.NewOperationNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
 	if err != nil {
-		return fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
+		return err
 	}
 
-	revert.Success()
-	return nil
-}
+	return notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+		op, err := client.UseProject(n.project).DeleteNetworkPeer(n.name, peerName)
+		if err == nil {
+			err = op.Wait()
+		}
 
-// forwardSetupFirewall applies all network address forwards defined for this network and this member.
-func (n *bridge) forwardSetupFirewall() error {
-	memberSpecific := true // Get all forwards for this cluster member.
+		return err
+	})
+}
 
-	var forwards map[int64]*api.NetworkForward
+// peerSetupFirewall applies the firewall rules of the created peerings of the network on the local member.
+// Traffic is routed between the peered bridges by the host, so only the subnets of both networks are allowed.
+func (n *bridge) peerSetupFirewall() error {
+	var peers map[int64]*api.NetworkPeer
 
 	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
-		forwards, err = tx.GetNetworkForwards(ctx, n.ID(), memberSpecific)
+		peers, err = tx.GetNetworkPeers(ctx, n.ID())
 
 		return err
 	})
 	if err != nil {
-		return fmt.Errorf("Failed loading network forwards: %w", err)
+		return fmt.Errorf("Failed loading network peers: %w", err)
 	}
 
-	var fwForwards []firewallDrivers.AddressForward
-	ipVersions := make(map[uint]struct{})
+	var fwPeers []firewallDrivers.NetworkPeer
 
-	for _, forward := range forwards {
-		// Convert listen address to subnet so we can check its valid and can be used.
-		listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
+	for _, peer := range peers {
+		if peer.Status != api.NetworkStatusCreated {
+			continue
+		}
+
+		targetNet, err := LoadByName(n.state, peer.TargetProject, peer.TargetNetwork)
 		if err != nil {
-			return fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+			return fmt.Errorf("Failed loading target network of peer %q: %w", peer.Name, err)
 		}
 
-		// Track which IP versions we are using.
-		if listenAddressNet.IP.To4() == nil {
-			ipVersions[6] = struct{}{}
-		} else {
-			ipVersions[4] = struct{}{}
+		targetBridge, ok := targetNet.(*bridge)
+		if !ok {
+			continue
 		}
 
-		portMaps, err := n.forwardValidate(listenAddressNet.IP, forward.Writable())
+		fwPeers = append(fwPeers, firewallDrivers.NetworkPeer{
+			TargetNetwork: targetBridge.name,
+			LocalSubnets:  acl.PeerSubnets(n.config),
+			TargetSubnets: acl.PeerSubnets(targetBridge.config),
+		})
+	}
+
+	err = n.state.Firewall.NetworkApplyPeers(n.name, fwPeers)
+	if err != nil {
+		return fmt.Errorf("Failed applying firewall network peers: %w", err)
+	}
+
+	return nil
+}
+
+// peerRefreshLinkedNetworks re-applies the peer and ACL firewall rules of the local bridge networks that have a
+// peer linked to the network, as those rules depend on the subnets and the peerings of the network.
+func (n *bridge) peerRefreshLinkedNetworks() error {
+	// Map of linked network names keyed on project name.
+	linkedNetNames := make(map[string][]string)
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkIDs, err := tx.GetNetworkPeerSourceNetworkIDs(ctx, n.ID())
 		if err != nil {
-			return fmt.Errorf("Failed validating firewall address forward for listen address %q: %w", forward.ListenAddress, err)
+			return err
 		}
 
-		fwForwards = append(fwForwards, n.forwardConvertToFirewallForwards(listenAddressNet.IP, net.ParseIP(forward.Config["target_address"]), portMaps)...)
+		for _, networkID := range networkIDs {
+			networkName, projectName, err := tx.GetNetworkNameAndProjectWithID(ctx, int(networkID))
+			if err != nil {
+				return err
+			}
+
+			linkedNetNames[projectName] = append(linkedNetNames[projectName], networkName)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading linked peer networks: %w", err)
 	}
 
-	if len(forwards) > 0 {
-		// Check if br_netfilter is enabled to, and warn if not.
-		brNetfilterWarning := false
-		for ipVersion := range ipVersions {
-			err = BridgeNetfilterEnabled(ipVersion)
+	for projectName, networkNames := range linkedNetNames {
+		for _, networkName := range networkNames {
+			linkedNet, err := LoadByName(n.state, projectName, networkName)
 			if err != nil {
-				brNetfilterWarning = true
-				msg := fmt.Sprintf("IPv%d bridge netfilter not enabled. Instances using the bridge will not be able to connect to the forward listen IPs", ipVersion)
-				n.logger.Warn(msg, logger.Ctx{"err": err})
-				err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-					return tx.UpsertWarningLocalNode(ctx, n.project, entity.TypeNetwork, int(n.id), warningtype.ProxyBridgeNetfilterNotEnabled, fmt.Sprintf("%s: %v", msg, err))
-				})
-				if err != nil {
-					n.logger.Warn("Failed creating warning", logger.Ctx{"err": err})
-				}
+				return fmt.Errorf("Failed loading network %q in project %q: %w", networkName, projectName, err)
 			}
-		}
 
-		if !brNetfilterWarning {
-			err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(n.state.DB.Cluster, n.project, warningtype.ProxyBridgeNetfilterNotEnabled, entity.TypeNetwork, int(n.id))
+			linkedBridge, ok := linkedNet.(*bridge)
+			if !ok || !linkedBridge.isRunning() {
+				continue
+			}
+
+			err = linkedBridge.peerSetupFirewall()
 			if err != nil {
-				n.logger.Warn("Failed resolving warning", logger.Ctx{"err": err})
+				return err
+			}
+
+			err = linkedBridge.aclSetupFirewall()
+			if err != nil {
+				return err
 			}
 		}
 	}
 
-	err = n.state.Firewall.NetworkApplyForwards(n.name, fwForwards)
-	if err != nil {
-		return fmt.Errorf("Failed applying firewall address forwards: %w", err)
+	return nil
+}
+
+// peerRefresh re-applies the peer and ACL firewall rules of the network and of the networks linked to it on the
+// local member, after a peering of the network has been created or deleted.
+func (n *bridge) peerRefresh() error {
+	if n.isRunning() {
+		err := n.peerSetupFirewall()
+		if err != nil {
+			return err
+		}
+
+		err = n.aclSetupFirewall()
+		if err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return n.peerRefreshLinkedNetworks()
 }
 
 // Leases returns a list of leases for the bridged network. It will reach out to other cluster members as needed.
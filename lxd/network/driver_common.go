@@ -2,10 +2,12 @@ package network
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"maps"
 	"net"
+	"net/http"
 	"os"
 	"slices"
 	"strconv"
@@ -19,6 +21,7 @@ import (
 	"github.com/canonical/lxd/lxd/config"
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/query"
 	"github.com/canonical/lxd/lxd/network/acl"
 	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/request"
@@ -787,6 +790,11 @@ func (n *common) bgpSetup(oldConfig map[string]string) error {
 		return fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
 	}
 
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
 	return nil
 }
 
@@ -810,6 +818,12 @@ func (n *common) bgpClear(config map[string]string) error {
 		return err
 	}
 
+	// Clear existing load balancer prefixes for network.
+	err = n.state.BGP.RemovePrefixByOwner(fmt.Sprintf("network_%d_load_balancer", n.id))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -1625,7 +1639,7 @@ func (n *common) Leases(projectName string, clientType request.ClientType) ([]ap
 }
 
 // PeerCreate returns ErrNotImplemented for drivers that do not support forwards.
-func (n *common) PeerCreate(forward api.NetworkPeersPost) error {
+func (n *common) PeerCreate(forward api.NetworkPeersPost, clientType request.ClientType) error {
 	return ErrNotImplemented
 }
 
@@ -1635,7 +1649,7 @@ func (n *common) PeerUpdate(peerName string, newPeer api.NetworkPeerPut) error {
 }
 
 // PeerDelete returns ErrNotImplemented for drivers that do not support forwards.
-func (n *common) PeerDelete(peerName string) error {
+func (n *common) PeerDelete(peerName string, clientType request.ClientType) error {
 	return ErrNotImplemented
 }
 
@@ -1783,6 +1797,465 @@ func (n *common) setAvailable() {
 	unavailableNetworksMu.Unlock()
 }
 
+// checkPoolHealthCheck checks the pool's health check settings and returns a health check struct if valid.
+func (n *common) checkPoolHealthCheck(pool *api.NetworkLoadBalancerPool) (*loadBalancerHealthCheck, error) {
+	// If health checks are disabled, return early.
+	if shared.IsFalse(pool.Config["healthcheck"]) {
+		return nil, nil
+	}
+
+	var err error
+
+	// Use defaults if none are provided in the pool's config.
+	// These are the values defined by OVN in https://github.com/ovn-org/ovn/blob/main/controller/pinctrl.c.
+	healthCheckConfig := map[string]uint64{
+		"healthcheck.interval":      5,
+		"healthcheck.timeout":       3,
+		"healthcheck.success_count": 1,
+		"healthcheck.failure_count": 1,
+	}
+
+	for k := range healthCheckConfig {
+		strVal, ok := pool.Config[k]
+		if !ok {
+			continue
+		}
+
+		bitSize := 64
+		if k == "healthcheck.interval" || k == "healthcheck.timeout" {
+			bitSize = 63
+		}
+
+		// We accept uint64 values for health check settings as OVN allows setting such high values.
+		// However it's unlikely those are ever used in practice, so we accept converting using a slightly smaller bitSize
+		// so some of the settings fit into an int64 when converted to time.Duration.
+		healthCheckConfig[k], err = strconv.ParseUint(strVal, 10, bitSize)
+		if err != nil {
+			return nil, fmt.Errorf("Failed converting %q: %w", k, err)
+		}
+	}
+
+	return &loadBalancerHealthCheck{
+		interval:     time.Second * time.Duration(healthCheckConfig["healthcheck.interval"]),
+		timeout:      time.Second * time.Duration(healthCheckConfig["healthcheck.timeout"]),
+		successCount: healthCheckConfig["healthcheck.success_count"],
+		failureCount: healthCheckConfig["healthcheck.failure_count"],
+	}, nil
+}
+
+// loadBalancerPoolValidate validates the load balancer pool request.
+// It also tries to fetch and returns the pool from the database in case it already exists.
+func (n *common) loadBalancerPoolValidate(ctx context.Context, tx *db.ClusterTx, poolName string, pool api.NetworkLoadBalancerPoolPut) (*dbCluster.NetworksLoadBalancerPool, error) {
+	var loadBalancerPoolDB *dbCluster.NetworksLoadBalancerPool
+
+	// Validate the pool names under the same constraints present for network names.
+	err := n.ValidateName(poolName)
+	if err != nil {
+		return nil, api.NewStatusError(http.StatusBadRequest, err.Error())
+	}
+
+	var allProjectInstances []string
+
+	// Fetch all instances in the current project.
+	// Do this before returning an error if the pool doesn't exist.
+	// This ensures the project instances are always loaded for validation.
+	allProjectInstances, err = tx.GetInstanceNames(ctx, n.project)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate if the pool exists.
+	loadBalancerPoolDB, err = dbCluster.GetNetworksLoadBalancerPool(ctx, tx.Tx(), n.ID(), poolName)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return nil, err
+	}
+
+	// Validate if the instances exist in the current project.
+	for _, instance := range pool.Instances {
+		if !slices.Contains(allProjectInstances, instance.Name) {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Instance %q does not exist in project %q", instance.Name, n.project)
+		}
+
+		// Setting the target port on an instance is optional.
+		// If unset it inherits the port from the parent pool.
+		if instance.TargetPort != "" {
+			// Validate target port.
+			err = validate.IsNetworkPort(instance.TargetPort)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	checkedFields := map[string]struct{}{}
+	rules := map[string]func(value string) error{
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=protocol)
+		// Can be either `tcp` or `udp`.
+		// ---
+		//  type: string
+		//  defaultdesc: `tcp`
+		//  required: no
+		//  shortdesc: Protocol used for ingress pool traffic.
+		"protocol": validate.Optional(validate.IsOneOf("tcp", "udp")),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=target_port)
+		//
+		// ---
+		//  type: string
+		//  required: yes
+		//  shortdesc: Port used on instances for ingress pool traffic
+		"target_port": validate.Required(validate.IsNetworkPort),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=healthcheck)
+		//
+		// ---
+		//  type: bool
+		//  defaultdesc: `true`
+		//  required: no
+		//  shortdesc: Whether to enable or disable health checks
+		"healthcheck": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=healthcheck.interval)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `5`
+		//  required: no
+		//  shortdesc: Interval in seconds between probes of the pool's instances.
+		"healthcheck.interval": validate.Optional(validate.IsUint64),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=healthcheck.timeout)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `3`
+		//  required: no
+		//  shortdesc: Timeout in seconds after a probe appears to be faulty.
+		"healthcheck.timeout": validate.Optional(validate.IsUint64),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=healthcheck.success_count)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `1`
+		//  required: no
+		//  shortdesc: Number of successful probe attempts after which an instance is considered healthy.
+		"healthcheck.success_count": validate.Optional(validate.IsUint64),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=healthcheck.failure_count)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `1`
+		//  required: no
+		//  shortdesc: Number of failed probe attempts after which an instance is considered unhealthy.
+		"healthcheck.failure_count": validate.Optional(validate.IsUint64),
+	}
+
+	// Run the validator against each field.
+	for k, validator := range rules {
+		checkedFields[k] = struct{}{} // Mark field as checked.
+		err := validator(pool.Config[k])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for pool %q option %q: %w", poolName, k, err)
+		}
+	}
+
+	// Validate config fields.
+	for k := range pool.Config {
+		_, checked := checkedFields[k]
+		if checked {
+			continue
+		}
+
+		// User keys are not validated.
+		if config.IsUserConfig(k) {
+			continue
+		}
+
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid option %q", k)
+	}
+
+	return loadBalancerPoolDB, nil
+}
+
+// loadBalancerPoolCreate creates the DB records of a network load balancer pool.
+func (n *common) loadBalancerPoolCreate(loadBalancerPool api.NetworkLoadBalancerPoolsPost) error {
+	// If no protocol is specified, default to "tcp".
+	if loadBalancerPool.Config["protocol"] == "" {
+		loadBalancerPool.Config["protocol"] = "tcp"
+	}
+
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		loadBalancerPoolDB, err := n.loadBalancerPoolValidate(ctx, tx, loadBalancerPool.Name, loadBalancerPool.NetworkLoadBalancerPoolPut)
+		if err != nil {
+			return err
+		}
+
+		if loadBalancerPoolDB != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Pool with name %q already exists on network %q", loadBalancerPool.Name, n.Name())
+		}
+
+		// Create load balancer pool DB record.
+		poolID, err := query.Create(ctx, tx.Tx(), dbCluster.NetworksLoadBalancerPoolRow{
+			NetworkID:   n.ID(),
+			Name:        loadBalancerPool.Name,
+			Description: loadBalancerPool.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Create load balancer pool config.
+		err = dbCluster.CreateNetworksLoadBalancerPoolConfig(ctx, tx.Tx(), poolID, loadBalancerPool.Config)
+		if err != nil {
+			return err
+		}
+
+		// Create load balancer pool instance records.
+		// The CLI does not make use of this but it ensures the API endpoint can be used to already add instances in a single request.
+		for _, instance := range loadBalancerPool.Instances {
+			err := n.loadBalancerPoolAddInstance(ctx, tx, poolID, instance)
+			if err != nil {
+				return fmt.Errorf("Failed adding instance %q to pool %q: %w", instance.Name, loadBalancerPool.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (n *common) loadBalancerPoolAddInstance(ctx context.Context, tx *db.ClusterTx, poolID int64, instance api.NetworkLoadBalancerPoolInstance) error {
+	// Fetch instance.
+	instanceID, err := tx.GetInstanceID(ctx, n.project, instance.Name)
+	if err != nil {
+		return err
+	}
+
+	targetPort := 0
+	if instance.TargetPort != "" {
+		targetPort, err = strconv.Atoi(instance.TargetPort)
+		if err != nil {
+			return fmt.Errorf("Failed parsing target port %q: %w", instance.TargetPort, err)
+		}
+	}
+
+	// Create load balancer pool instance DB record.
+	_, err = query.Create(ctx, tx.Tx(), dbCluster.NetworksLoadBalancerPoolInstanceRow{
+		PoolID:     poolID,
+		InstanceID: int64(instanceID),
+		TargetPort: int64(targetPort),
+	})
+	return err
+}
+
+func (n *common) loadBalancerPoolUpdateInstance(ctx context.Context, tx *db.ClusterTx, poolID int64, instance api.NetworkLoadBalancerPoolInstance) error {
+	// Fetch instance.
+	instanceID, err := tx.GetInstanceID(ctx, n.project, instance.Name)
+	if err != nil {
+		return err
+	}
+
+	targetPort := 0
+	if instance.TargetPort != "" {
+		targetPort, err = strconv.Atoi(instance.TargetPort)
+		if err != nil {
+			return fmt.Errorf("Failed parsing target port %q: %w", instance.TargetPort, err)
+		}
+	}
+
+	instanceDB := &dbCluster.NetworksLoadBalancerPoolInstanceRow{
+		PoolID:     poolID,
+		InstanceID: int64(instanceID),
+		TargetPort: int64(targetPort),
+	}
+
+	// Update load balancer pool instance DB record.
+	return dbCluster.UpdateNetworkLoadBalancerPoolInstanceRow(ctx, tx.Tx(), instanceDB)
+}
+
+func (n *common) loadBalancerPoolRemoveInstance(ctx context.Context, tx *db.ClusterTx, poolID int64, instanceName string) error {
+	// Fetch instance.
+	instanceID, err := tx.GetInstanceID(ctx, n.project, instanceName)
+	if err != nil {
+		return err
+	}
+
+	// Remove load balancer pool instance DB record.
+	return dbCluster.DeleteNetworksLoadBalancerPoolInstanceRow(ctx, tx.Tx(), poolID, int64(instanceID))
+}
+
+// loadBalancerPoolUpdateRecord validates the load balancer pool request and updates the pool DB records.
+// Returns the pool as it was before the update and whether the load balancers using the pool require an update.
+func (n *common) loadBalancerPoolUpdateRecord(ctx context.Context, tx *db.ClusterTx, poolName string, loadBalancerPoolPut api.NetworkLoadBalancerPoolPut) (*dbCluster.NetworksLoadBalancerPool, *api.NetworkLoadBalancerPool, bool, error) {
+	loadBalancerRequiresUpdate := false
+
+	loadBalancerPoolDB, err := n.loadBalancerPoolValidate(ctx, tx, poolName, loadBalancerPoolPut)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if loadBalancerPoolDB == nil {
+		return nil, nil, false, api.StatusErrorf(http.StatusNotFound, "Pool with name %q does not exist on network %q", poolName, n.Name())
+	}
+
+	allConfigs, err := dbCluster.GetNetworksLoadBalancerPoolConfig(ctx, tx.Tx(), n.ID(), &loadBalancerPoolDB.Row.ID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	allInstances, err := dbCluster.GetNetworksLoadBalancerPoolInstances(ctx, tx.Tx(), &loadBalancerPoolDB.Row.ID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	loadBalancerPool, err := loadBalancerPoolDB.ToAPI(allConfigs, allInstances)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	// Create simple list of instances currently set on the pool.
+	var poolInstances []string
+	for _, instance := range loadBalancerPool.Instances {
+		poolInstances = append(poolInstances, instance.Name)
+	}
+
+	// Check if list of instances requires an update.
+	for _, instance := range loadBalancerPoolPut.Instances {
+		// Handle new instances not present in the DB.
+		if !slices.Contains(poolInstances, instance.Name) {
+			loadBalancerRequiresUpdate = true
+
+			// Add instance to the pool.
+			// If the pool is currently referenced by a port, this requires modification of the load balancer.
+			// If the pool is unused, this only adds the instance in the database.
+			err := n.loadBalancerPoolAddInstance(ctx, tx, loadBalancerPoolDB.Row.ID, instance)
+			if err != nil {
+				return nil, nil, false, fmt.Errorf("Failed adding instance %q to pool %q: %w", instance.Name, poolName, err)
+			}
+		} else {
+			for _, instanceDB := range loadBalancerPool.Instances {
+				if instanceDB.Name == instance.Name && instanceDB.TargetPort != instance.TargetPort {
+					// Ensure the target port is up to date.
+					err := n.loadBalancerPoolUpdateInstance(ctx, tx, loadBalancerPoolDB.Row.ID, instance)
+					if err != nil {
+						return nil, nil, false, fmt.Errorf("Failed updating instance %q in pool %q: %w", instance.Name, poolName, err)
+					}
+
+					// Indicate the load balancers requires and update too.
+					loadBalancerRequiresUpdate = true
+				}
+			}
+		}
+	}
+
+	// Create simple list of instances requested to be on the pool.
+	var requestedPoolInstances []string
+	for _, instance := range loadBalancerPoolPut.Instances {
+		requestedPoolInstances = append(requestedPoolInstances, instance.Name)
+	}
+
+	// Check if list of DB instances requires an update.
+	for _, instance := range loadBalancerPool.Instances {
+		// Handle existing instances present in the DB.
+		if !slices.Contains(requestedPoolInstances, instance.Name) {
+			loadBalancerRequiresUpdate = true
+
+			// Remove instance from the pool.
+			err := n.loadBalancerPoolRemoveInstance(ctx, tx, loadBalancerPoolDB.Row.ID, instance.Name)
+			if err != nil {
+				return nil, nil, false, fmt.Errorf("Failed removing instance %q from pool %q: %w", instance.Name, poolName, err)
+			}
+		}
+	}
+
+	// If no protocol is specified, default to "tcp".
+	// This happens when the protocol gets unset.
+	if loadBalancerPoolPut.Config["protocol"] == "" {
+		loadBalancerPoolPut.Config["protocol"] = "tcp"
+	}
+
+	// Check if load balancer requires an update based on config changes.
+	for k, v := range loadBalancerPoolPut.Config {
+		if loadBalancerPool.Config[k] != v {
+			loadBalancerRequiresUpdate = true
+
+			// Stop checking further config options as the load balancer will require an update anyway.
+			break
+		}
+	}
+
+	// Check if any config options got removed which means the defaults should be applied.
+	if len(loadBalancerPool.Config) != len(loadBalancerPoolPut.Config) {
+		loadBalancerRequiresUpdate = true
+	}
+
+	// Update the pool description and config.
+	poolDBNew := &dbCluster.NetworksLoadBalancerPoolRow{
+		ID:          loadBalancerPoolDB.Row.ID,
+		NetworkID:   loadBalancerPoolDB.Row.NetworkID,
+		Name:        loadBalancerPoolDB.Row.Name,
+		Description: loadBalancerPoolPut.Description,
+	}
+
+	err = dbCluster.UpdateNetworksLoadBalancerPool(ctx, tx.Tx(), poolDBNew, loadBalancerPoolPut.Config)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return loadBalancerPoolDB, loadBalancerPool, loadBalancerRequiresUpdate, nil
+}
+
+// loadBalancerPoolDelete deletes the DB records of a network load balancer pool if unused by any load balancer.
+func (n *common) loadBalancerPoolDelete(poolName string) error {
+	var allLoadBalancers map[string][]string
+
+	// Check if the pool is still referenced by any load balancer port.
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		// Get all load balancers referencing the pool with any of their ports.
+		allLoadBalancers, err = dbCluster.GetNetworksLoadBalancersByPool(ctx, tx.Tx(), n.ID(), &poolName)
+		if err != nil {
+			return fmt.Errorf("Failed getting load balancers for network %q: %w", n.Name(), err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(allLoadBalancers) > 0 {
+		return api.StatusErrorf(http.StatusBadRequest, "Pool %q is still referenced by at least one load balancer port", poolName)
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Try to delete the pool.
+		// If it doesn't exist a not found error is returned.
+		return dbCluster.DeleteNetworksLoadBalancerPool(ctx, tx.Tx(), n.ID(), poolName)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getLoadBalancerPool returns a load balancer pool by its name.
+func (n *common) getLoadBalancerPool(ctx context.Context, tx *sql.Tx, poolName string) (*api.NetworkLoadBalancerPool, error) {
+	poolDB, err := dbCluster.GetNetworksLoadBalancerPool(ctx, tx, n.ID(), poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	allConfigs, err := dbCluster.GetNetworksLoadBalancerPoolConfig(ctx, tx, n.ID(), &poolDB.Row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	allInstances, err := dbCluster.GetNetworksLoadBalancerPoolInstances(ctx, tx, &poolDB.Row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return poolDB.ToAPI(allConfigs, allInstances)
+}
+
 // LoadBalancerPoolCreate returns ErrNotImplemented for drivers that do not support load balancer pools.
 func (n *common) LoadBalancerPoolCreate(loadBalancer api.NetworkLoadBalancerPoolsPost) error {
 	return ErrNotImplemented
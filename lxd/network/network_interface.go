@@ -75,8 +75,8 @@ type Network interface {
 	LoadBalancerPoolState(poolName string) (*api.NetworkLoadBalancerPoolState, error)
 
 	// Peerings.
-	PeerCreate(forward api.NetworkPeersPost) error
+	PeerCreate(forward api.NetworkPeersPost, clientType request.ClientType) error
 	PeerUpdate(peerName string, newPeer api.NetworkPeerPut) error
-	PeerDelete(peerName string) error
+	PeerDelete(peerName string, clientType request.ClientType) error
 	PeerUsedBy(peerName string) ([]string, error)
 }
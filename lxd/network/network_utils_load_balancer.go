@@ -0,0 +1,337 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
+)
+
+// Status values of the load balancer targets probed by a health monitor.
+const (
+	loadBalancerHealthStatusOnline  = "online"
+	loadBalancerHealthStatusOffline = "offline"
+	loadBalancerHealthStatusPending = "pending"
+	loadBalancerHealthStatusUnknown = "unknown"
+)
+
+// loadBalancerHealthRefreshInterval is the interval at which the load balancers of a network are refreshed, so that
+// changes of the pool instances (started, stopped or assigned a new address) are picked up.
+const loadBalancerHealthRefreshInterval = 30 * time.Second
+
+// loadBalancerHealthMonitors holds the running load balancer health monitors keyed by network name.
+var loadBalancerHealthMonitors = map[string]*loadBalancerHealthMonitor{}
+var loadBalancerHealthMonitorsMu sync.Mutex
+
+// loadBalancerHealthTarget represents a load balancer target probed by a health monitor.
+type loadBalancerHealthTarget struct {
+	protocol    string
+	address     net.IP
+	port        uint64
+	healthCheck loadBalancerHealthCheck
+}
+
+// String returns the protocol, address and port of the target.
+func (t loadBalancerHealthTarget) String() string {
+	return t.protocol + "/" + net.JoinHostPort(t.address.String(), strconv.FormatUint(t.port, 10))
+}
+
+// loadBalancerHealthProbe represents the health state of a probed target.
+type loadBalancerHealthProbe struct {
+	healthCheck loadBalancerHealthCheck
+	status      string
+	successes   uint64
+	failures    uint64
+	cancel      context.CancelFunc
+}
+
+// loadBalancerHealthMonitor probes the targets of the load balancers of a network.
+// The load balancers are refreshed whenever a target goes offline or comes back online.
+type loadBalancerHealthMonitor struct {
+	mu      sync.Mutex
+	probes  map[string]*loadBalancerHealthProbe
+	applied *string // Key of the firewall load balancers last applied, see loadBalancerFirewallKey.
+	refresh chan struct{}
+	cancel  context.CancelFunc
+}
+
+// loadBalancerHealthMonitorStart returns the health monitor of the network, starting it if not running yet.
+// The apply function is called when a target changes status and every loadBalancerHealthRefreshInterval, and is
+// expected to only apply the firewall load balancers if they changed.
+func loadBalancerHealthMonitorStart(networkName string, apply func()) *loadBalancerHealthMonitor {
+	loadBalancerHealthMonitorsMu.Lock()
+	defer loadBalancerHealthMonitorsMu.Unlock()
+
+	m, found := loadBalancerHealthMonitors[networkName]
+	if found {
+		return m
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m = &loadBalancerHealthMonitor{
+		probes:  make(map[string]*loadBalancerHealthProbe),
+		refresh: make(chan struct{}, 1),
+		cancel:  cancel,
+	}
+
+	loadBalancerHealthMonitors[networkName] = m
+
+	go func() {
+		ticker := time.NewTicker(loadBalancerHealthRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.refresh:
+			case <-ticker.C:
+			}
+
+			apply()
+		}
+	}()
+
+	return m
+}
+
+// loadBalancerHealthMonitorGet returns the health monitor of the network or nil if not running.
+func loadBalancerHealthMonitorGet(networkName string) *loadBalancerHealthMonitor {
+	loadBalancerHealthMonitorsMu.Lock()
+	defer loadBalancerHealthMonitorsMu.Unlock()
+
+	return loadBalancerHealthMonitors[networkName]
+}
+
+// LoadBalancerRefresh triggers the load balancers of the network to be refreshed if it has any.
+// This is used when a pool instance starts or stops so that it doesn't need to wait for the periodic refresh.
+func LoadBalancerRefresh(networkName string) {
+	m := loadBalancerHealthMonitorGet(networkName)
+	if m != nil {
+		m.triggerRefresh()
+	}
+}
+
+// loadBalancerHealthMonitorStop stops the health monitor of the network and all of its probes.
+func loadBalancerHealthMonitorStop(networkName string) {
+	loadBalancerHealthMonitorsMu.Lock()
+	m, found := loadBalancerHealthMonitors[networkName]
+	delete(loadBalancerHealthMonitors, networkName)
+	loadBalancerHealthMonitorsMu.Unlock()
+
+	if !found {
+		return
+	}
+
+	m.sync(nil)
+	m.cancel()
+}
+
+// sync starts probing the new targets and stops probing the targets which are no longer used.
+// The state of targets already probed with the same health check configuration is kept.
+func (m *loadBalancerHealthMonitor) sync(targets []loadBalancerHealthTarget) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wanted := make(map[string]loadBalancerHealthTarget, len(targets))
+	for _, target := range targets {
+		wanted[target.String()] = target
+	}
+
+	for key, probe := range m.probes {
+		target, found := wanted[key]
+		if found && target.healthCheck == probe.healthCheck {
+			continue
+		}
+
+		probe.cancel()
+		delete(m.probes, key)
+	}
+
+	for key, target := range wanted {
+		_, found := m.probes[key]
+		if found {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		m.probes[key] = &loadBalancerHealthProbe{
+			healthCheck: target.healthCheck,
+			status:      loadBalancerHealthStatusPending,
+			cancel:      cancel,
+		}
+
+		go m.probe(ctx, key, target)
+	}
+}
+
+// triggerRefresh triggers the load balancers to be refreshed unless already pending.
+func (m *loadBalancerHealthMonitor) triggerRefresh() {
+	select {
+	case m.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// isApplied returns whether the firewall load balancers identified by key are the ones last applied.
+func (m *loadBalancerHealthMonitor) isApplied(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.applied != nil && *m.applied == key
+}
+
+// setApplied records the key of the firewall load balancers last applied.
+func (m *loadBalancerHealthMonitor) setApplied(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.applied = &key
+}
+
+// status returns the status of the target or an empty string if it isn't probed.
+func (m *loadBalancerHealthMonitor) status(target loadBalancerHealthTarget) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	probe, found := m.probes[target.String()]
+	if !found {
+		return ""
+	}
+
+	return probe.status
+}
+
+// probe checks the target at the configured interval until the context is cancelled.
+func (m *loadBalancerHealthMonitor) probe(ctx context.Context, key string, target loadBalancerHealthTarget) {
+	interval := max(target.healthCheck.interval, time.Second)
+
+	timeout := target.healthCheck.timeout
+	if timeout <= 0 || timeout > interval {
+		timeout = interval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := loadBalancerHealthProbeTarget(ctx, target, timeout)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if m.record(key, err == nil) {
+			m.triggerRefresh()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// record updates the state of the probed target with the result of a health check.
+// Returns true if the target went offline or came back online.
+func (m *loadBalancerHealthMonitor) record(key string, healthy bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	probe, found := m.probes[key]
+	if !found {
+		return false
+	}
+
+	if healthy {
+		probe.failures = 0
+		probe.successes++
+
+		if probe.status != loadBalancerHealthStatusOnline && probe.successes >= probe.healthCheck.successCount {
+			wasOffline := probe.status == loadBalancerHealthStatusOffline
+			probe.status = loadBalancerHealthStatusOnline
+
+			return wasOffline
+		}
+
+		return false
+	}
+
+	probe.successes = 0
+	probe.failures++
+
+	if probe.status != loadBalancerHealthStatusOffline && probe.failures >= probe.healthCheck.failureCount {
+		probe.status = loadBalancerHealthStatusOffline
+
+		return true
+	}
+
+	return false
+}
+
+// loadBalancerHealthProbeTarget checks whether the target is reachable.
+// A TCP target is healthy if a connection can be established. A UDP target is healthy unless the datagram sent to
+// it is rejected with an ICMP port unreachable message, as an answer can't be expected from any UDP service.
+func loadBalancerHealthProbeTarget(ctx context.Context, target loadBalancerHealthTarget, timeout time.Duration) error {
+	dialer := net.Dialer{Timeout: timeout}
+	address := net.JoinHostPort(target.address.String(), strconv.FormatUint(target.port, 10))
+
+	conn, err := dialer.DialContext(ctx, target.protocol, address)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	if target.protocol != "udp" {
+		return nil
+	}
+
+	err = conn.SetDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Write([]byte{})
+	if err != nil {
+		return err
+	}
+
+	// A rejection is reported as syscall.ECONNREFUSED, while no answer at all ends with the deadline exceeded.
+	_, err = conn.Read(make([]byte, 1))
+	if err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+		return err
+	}
+
+	return nil
+}
+
+// loadBalancerFirewallKey returns a key identifying the listen ports and targets of the firewall load balancers,
+// regardless of their order.
+func loadBalancerFirewallKey(fwLoadBalancers []firewallDrivers.LoadBalancer) string {
+	entries := make([]string, 0, len(fwLoadBalancers))
+	for _, fwLoadBalancer := range fwLoadBalancers {
+		targets := make([]string, 0, len(fwLoadBalancer.Targets))
+		for _, target := range fwLoadBalancer.Targets {
+			targets = append(targets, net.JoinHostPort(target.Address.String(), strconv.FormatUint(target.Port, 10)))
+		}
+
+		slices.Sort(targets)
+
+		listen := net.JoinHostPort(fwLoadBalancer.ListenAddress.String(), strconv.FormatUint(fwLoadBalancer.ListenPort, 10))
+		entries = append(entries, fwLoadBalancer.Protocol+"/"+listen+" "+strings.Join(targets, ","))
+	}
+
+	slices.Sort(entries)
+
+	return strings.Join(entries, "\n")
+}
@@ -0,0 +1,38 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
+)
+
+func Test_loadBalancerFirewallKey(t *testing.T) {
+	listen := net.ParseIP("192.0.2.1")
+	target1 := firewallDrivers.LoadBalancerTarget{Address: net.ParseIP("10.0.0.1"), Port: 80}
+	target2 := firewallDrivers.LoadBalancerTarget{Address: net.ParseIP("10.0.0.2"), Port: 80}
+
+	lbs := []firewallDrivers.LoadBalancer{
+		{ListenAddress: listen, Protocol: "tcp", ListenPort: 80, Targets: []firewallDrivers.LoadBalancerTarget{target1, target2}},
+		{ListenAddress: listen, Protocol: "tcp", ListenPort: 443, Targets: []firewallDrivers.LoadBalancerTarget{target1}},
+	}
+
+	// The order of the load balancers and of their targets doesn't matter.
+	reordered := []firewallDrivers.LoadBalancer{
+		{ListenAddress: listen, Protocol: "tcp", ListenPort: 443, Targets: []firewallDrivers.LoadBalancerTarget{target1}},
+		{ListenAddress: listen, Protocol: "tcp", ListenPort: 80, Targets: []firewallDrivers.LoadBalancerTarget{target2, target1}},
+	}
+
+	assert.Equal(t, loadBalancerFirewallKey(lbs), loadBalancerFirewallKey(reordered))
+
+	// A target going offline changes the key.
+	offline := []firewallDrivers.LoadBalancer{
+		{ListenAddress: listen, Protocol: "tcp", ListenPort: 80, Targets: []firewallDrivers.LoadBalancerTarget{target1}},
+		{ListenAddress: listen, Protocol: "tcp", ListenPort: 443, Targets: []firewallDrivers.LoadBalancerTarget{target1}},
+	}
+
+	assert.NotEqual(t, loadBalancerFirewallKey(lbs), loadBalancerFirewallKey(offline))
+	assert.NotEqual(t, loadBalancerFirewallKey(lbs), loadBalancerFirewallKey(nil))
+}
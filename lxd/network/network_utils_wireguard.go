@@ -0,0 +1,380 @@
+package network
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// wireguardDefaultPort is the default UDP port WireGuard tunnels listen on.
+const wireguardDefaultPort = 51820
+
+// wireguardVXLANPort is the destination port of the VXLAN traffic carried inside WireGuard tunnels.
+const wireguardVXLANPort = 4789
+
+// wireguardDefaultKeyRotation is the default interval at which the key pair of a WireGuard tunnel is rotated.
+const wireguardDefaultKeyRotation = 30 * 24 * time.Hour
+
+// wireguardKeyGracePeriod is the time during which a new key pair is announced to the peers before being used.
+// It spans several peer refreshes, so that all the peers accept the new key by the time the tunnel switches to it.
+const wireguardKeyGracePeriod = 5 * wireguardRefreshInterval
+
+// wireguardPersistentKeepalive is the interval in seconds at which keepalive packets are sent to the peers,
+// keeping the NAT and stateful firewall mappings open between the tunnel endpoints.
+const wireguardPersistentKeepalive = 25
+
+// wireguardRefreshInterval is the interval at which the peers of a WireGuard tunnel are refreshed.
+const wireguardRefreshInterval = time.Minute
+
+// wireguardFloodMAC is the MAC address of the forwarding database entries used to flood the VXLAN traffic to the peers.
+var wireguardFloodMAC = net.HardwareAddr{0, 0, 0, 0, 0, 0}
+
+// wireguardMonitors holds the running WireGuard peer monitors keyed by network name.
+var wireguardMonitors = map[string]*wireguardMonitor{}
+var wireguardMonitorsMu sync.Mutex
+
+// wireguardPeer represents a peer of a WireGuard tunnel.
+type wireguardPeer struct {
+	publicKey     string
+	nextPublicKey string
+	endpoint      string
+}
+
+// wireguardMonitor periodically refreshes the peers of the WireGuard tunnel of a network.
+type wireguardMonitor struct {
+	mu     sync.Mutex
+	peers  map[string]wireguardPeer
+	cancel context.CancelFunc
+}
+
+// wireguardMonitorStart starts the WireGuard peer monitor of the network, replacing any monitor already running.
+// The refresh function is called straight away and then every wireguardRefreshInterval.
+func wireguardMonitorStart(networkName string, refresh func(m *wireguardMonitor)) {
+	wireguardMonitorStop(networkName)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m := &wireguardMonitor{
+		peers:  make(map[string]wireguardPeer),
+		cancel: cancel,
+	}
+
+	wireguardMonitorsMu.Lock()
+	wireguardMonitors[networkName] = m
+	wireguardMonitorsMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(wireguardRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			refresh(m)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// wireguardMonitorStop stops the WireGuard peer monitor of the network.
+func wireguardMonitorStop(networkName string) {
+	wireguardMonitorsMu.Lock()
+	m, found := wireguardMonitors[networkName]
+	delete(wireguardMonitors, networkName)
+	wireguardMonitorsMu.Unlock()
+
+	if found {
+		m.cancel()
+	}
+}
+
+// update replaces the known peers with the discovered ones. The peers of the sources which failed to be queried are
+// kept as last known, so that a temporarily unreachable source doesn't interrupt the traffic already flowing.
+// A failed source also matches all the sources it is a prefix of. Returns the resulting peers.
+func (m *wireguardMonitor) update(peers map[string]wireguardPeer, failedSources []string) []wireguardPeer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for source, peer := range m.peers {
+		for _, failedSource := range failedSources {
+			if strings.HasPrefix(source, failedSource) {
+				peers[source] = peer
+				break
+			}
+		}
+	}
+
+	m.peers = peers
+
+	result := make([]wireguardPeer, 0, len(peers))
+	for _, peer := range peers {
+		result = append(result, peer)
+	}
+
+	return result
+}
+
+// wireguardDevName returns the name of the WireGuard interface of a tunnel.
+func wireguardDevName(networkName string, tunnelName string) string {
+	return fmt.Sprintf("%s-%s-wg", networkName, tunnelName)
+}
+
+// wireguardKeyPath returns the path of the private key file of the WireGuard tunnel of a network.
+func wireguardKeyPath(networkName string) string {
+	return shared.VarPath("networks", networkName, "wireguard.key")
+}
+
+// wireguardNextKeyPath returns the path of the private key file the WireGuard tunnel of a network switches to once
+// its key rotation completes.
+func wireguardNextKeyPath(networkName string) string {
+	return shared.VarPath("networks", networkName, "wireguard.key.next")
+}
+
+// wireguardValidKey validates a base64 encoded WireGuard key.
+func wireguardValidKey(value string) error {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		return fmt.Errorf("Invalid WireGuard key %q", value)
+	}
+
+	return nil
+}
+
+// wireguardPublicKey returns the base64 encoded public key matching a base64 encoded private key.
+func wireguardPublicKey(privateKey string) (string, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("Failed decoding WireGuard private key: %w", err)
+	}
+
+	key, err := ecdh.X25519().NewPrivateKey(keyBytes)
+	if err != nil {
+		return "", fmt.Errorf("Failed parsing WireGuard private key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// wireguardLoadKey returns the private key of the WireGuard tunnel of a network, along with the next private key
+// while a key rotation is in progress. A new key is generated if none exists yet.
+// The key pair is rotated in two steps so that the peers never drop the traffic of the tunnel. Once the key is older
+// than the rotation interval, a next key is generated and announced to the peers, which then accept both keys.
+// The next key replaces the current one after wireguardKeyGracePeriod.
+func wireguardLoadKey(networkName string, rotation time.Duration) (string, string, error) {
+	keyPath := wireguardKeyPath(networkName)
+	nextKeyPath := wireguardNextKeyPath(networkName)
+
+	info, err := os.Stat(nextKeyPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", "", err
+	}
+
+	if err == nil && time.Since(info.ModTime()) >= wireguardKeyGracePeriod {
+		err = os.Rename(nextKeyPath, keyPath)
+		if err != nil {
+			return "", "", fmt.Errorf("Failed switching to the next WireGuard private key: %w", err)
+		}
+	}
+
+	privateKey, created, err := wireguardReadKey(keyPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		privateKey, err = wireguardGenerateKey(keyPath)
+		if err != nil {
+			return "", "", err
+		}
+
+		return privateKey, "", nil
+	} else if err != nil {
+		return "", "", err
+	}
+
+	nextPrivateKey, _, err := wireguardReadKey(nextKeyPath)
+	if err == nil {
+		return privateKey, nextPrivateKey, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", "", err
+	}
+
+	if rotation <= 0 || time.Since(created) < rotation {
+		return privateKey, "", nil
+	}
+
+	nextPrivateKey, err = wireguardGenerateKey(nextKeyPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	return privateKey, nextPrivateKey, nil
+}
+
+// wireguardNextPublicKey returns the public key the WireGuard tunnel of a network switches to once its key rotation
+// completes, or an empty string if no rotation is in progress.
+func wireguardNextPublicKey(networkName string) (string, error) {
+	nextPrivateKey, _, err := wireguardReadKey(wireguardNextKeyPath(networkName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return wireguardPublicKey(nextPrivateKey)
+}
+
+// wireguardReadKey returns the private key stored in the given file along with the time it was written.
+func wireguardReadKey(path string) (string, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Failed reading WireGuard private key: %w", err)
+	}
+
+	return strings.TrimSpace(string(content)), info.ModTime(), nil
+}
+
+// wireguardGenerateKey generates a new private key and stores it in the given file.
+func wireguardGenerateKey(path string) (string, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("Failed generating WireGuard private key: %w", err)
+	}
+
+	privateKey := base64.StdEncoding.EncodeToString(key.Bytes())
+
+	// Write the key to a temporary file first so that the key file is replaced atomically.
+	err = os.WriteFile(path+".new", []byte(privateKey+"\n"), 0600)
+	if err != nil {
+		return "", fmt.Errorf("Failed writing WireGuard private key: %w", err)
+	}
+
+	err = os.Rename(path+".new", path)
+	if err != nil {
+		return "", fmt.Errorf("Failed writing WireGuard private key: %w", err)
+	}
+
+	return privateKey, nil
+}
+
+// wireguardInnerAddress returns the IPv6 link-local address used inside the WireGuard tunnel by the endpoint with
+// the given public key. Deriving the address from the public key allows each endpoint to compute the address of its
+// peers, and to restrict each peer to its own address, from the public keys alone.
+func wireguardInnerAddress(publicKey string) net.IP {
+	hash := sha256.Sum256([]byte(publicKey))
+
+	address := make(net.IP, net.IPv6len)
+	address[0] = 0xfe
+	address[1] = 0x80
+	copy(address[8:], hash[:8])
+
+	return address
+}
+
+// wireguardConfigure sets the private key and listen port of the WireGuard interface.
+func wireguardConfigure(devName string, keyPath string, listenPort string) error {
+	_, err := shared.RunCommand(context.TODO(), "wg", "set", devName, "private-key", keyPath, "listen-port", listenPort)
+	if err != nil {
+		return fmt.Errorf("Failed configuring WireGuard interface %q: %w", devName, err)
+	}
+
+	return nil
+}
+
+// wireguardSetPeer adds or updates a peer of the WireGuard interface.
+// The peer is only allowed to send traffic from its own inner address.
+func wireguardSetPeer(devName string, peer wireguardPeer) error {
+	args := []string{"set", devName, "peer", peer.publicKey, "allowed-ips", wireguardInnerAddress(peer.publicKey).String() + "/128", "persistent-keepalive", strconv.Itoa(wireguardPersistentKeepalive)}
+	if peer.endpoint != "" {
+		args = append(args, "endpoint", peer.endpoint)
+	}
+
+	_, err := shared.RunCommand(context.TODO(), "wg", args...)
+	if err != nil {
+		return fmt.Errorf("Failed setting WireGuard peer %q on %q: %w", peer.publicKey, devName, err)
+	}
+
+	return nil
+}
+
+// wireguardRemovePeer removes a peer from the WireGuard interface.
+func wireguardRemovePeer(devName string, publicKey string) error {
+	_, err := shared.RunCommand(context.TODO(), "wg", "set", devName, "peer", publicKey, "remove")
+	if err != nil {
+		return fmt.Errorf("Failed removing WireGuard peer %q from %q: %w", publicKey, devName, err)
+	}
+
+	return nil
+}
+
+// wireguardState returns the state of the WireGuard interface as reported by "wg show dump".
+func wireguardState(devName string) (*api.NetworkStateWireGuard, error) {
+	out, err := shared.RunCommand(context.TODO(), "wg", "show", devName, "dump")
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting WireGuard state of %q: %w", devName, err)
+	}
+
+	state := &api.NetworkStateWireGuard{
+		Peers: []api.NetworkStateWireGuardPeer{},
+	}
+
+	for i, line := range shared.SplitNTrimSpace(out, "\n", -1, true) {
+		fields := strings.Split(line, "\t")
+
+		// The first line describes the interface: private-key public-key listen-port fwmark.
+		if i == 0 {
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("Invalid WireGuard interface state %q", line)
+			}
+
+			state.PublicKey = fields[1]
+			state.ListenPort, _ = strconv.ParseUint(fields[2], 10, 64)
+
+			continue
+		}
+
+		// The other lines describe the peers: public-key preshared-key endpoint allowed-ips latest-handshake
+		// transfer-rx transfer-tx persistent-keepalive.
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("Invalid WireGuard peer state %q", line)
+		}
+
+		peer := api.NetworkStateWireGuardPeer{
+			PublicKey: fields[0],
+		}
+
+		if fields[2] != "(none)" {
+			peer.Endpoint = fields[2]
+		}
+
+		handshake, _ := strconv.ParseInt(fields[4], 10, 64)
+		if handshake > 0 {
+			peer.LatestHandshake = time.Unix(handshake, 0)
+		}
+
+		peer.BytesReceived, _ = strconv.ParseUint(fields[5], 10, 64)
+		peer.BytesSent, _ = strconv.ParseUint(fields[6], 10, 64)
+
+		state.Peers = append(state.Peers, peer)
+	}
+
+	return state, nil
+}
@@ -0,0 +1,63 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWireguardLoadKey(t *testing.T) {
+	t.Setenv("LXD_DIR", t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Dir(wireguardKeyPath("lxdbr0")), 0700))
+
+	// A key is generated when none exists yet.
+	privateKey, nextPrivateKey, err := wireguardLoadKey("lxdbr0", time.Hour)
+	require.NoError(t, err)
+	assert.NoError(t, wireguardValidKey(privateKey))
+	assert.Empty(t, nextPrivateKey)
+
+	// The key is kept until it is older than the rotation interval.
+	key, nextKey, err := wireguardLoadKey("lxdbr0", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, privateKey, key)
+	assert.Empty(t, nextKey)
+
+	// Once it is, a next key is generated and announced alongside the current one.
+	past := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(wireguardKeyPath("lxdbr0"), past, past))
+
+	key, nextPrivateKey, err = wireguardLoadKey("lxdbr0", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, privateKey, key)
+	assert.NoError(t, wireguardValidKey(nextPrivateKey))
+	assert.NotEqual(t, privateKey, nextPrivateKey)
+
+	nextPublicKey, err := wireguardNextPublicKey("lxdbr0")
+	require.NoError(t, err)
+	expectedPublicKey, err := wireguardPublicKey(nextPrivateKey)
+	require.NoError(t, err)
+	assert.Equal(t, expectedPublicKey, nextPublicKey)
+
+	// The next key is kept during the grace period.
+	key, nextKey, err = wireguardLoadKey("lxdbr0", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, privateKey, key)
+	assert.Equal(t, nextPrivateKey, nextKey)
+
+	// The next key replaces the current one after the grace period, even if the rotation was disabled meanwhile.
+	past = time.Now().Add(-wireguardKeyGracePeriod)
+	require.NoError(t, os.Chtimes(wireguardNextKeyPath("lxdbr0"), past, past))
+
+	key, nextKey, err = wireguardLoadKey("lxdbr0", 0)
+	require.NoError(t, err)
+	assert.Equal(t, nextPrivateKey, key)
+	assert.Empty(t, nextKey)
+
+	nextPublicKey, err = wireguardNextPublicKey("lxdbr0")
+	require.NoError(t, err)
+	assert.Empty(t, nextPublicKey)
+}
@@ -141,7 +141,7 @@ type OVNACLRule struct {
 	Match     string // Match criteria. See OVN Southbound database's Logical_Flow table match column usage.
 	Priority  int    // Priority (between 0 and 32767, inclusive). Higher values take precedence.
 	Log       bool   // Whether or not to log matched packets.
-	LogName   string // Log label name (requires Log be true).
+	LogName   string // Log label name, also used to identify the rule's counters.
 }
 
 // OVNLoadBalancerTarget represents an OVN load balancer Virtual IP target.
@@ -1839,10 +1839,10 @@ func (o *OVN) aclRuleAddAppendArgs(args []string, entityTable string, entityName
 
 		if rule.Log {
 			args = append(args, "log=true")
+		}
 
-			if rule.LogName != "" {
-				args = append(args, "name="+rule.LogName)
-			}
+		if rule.LogName != "" {
+			args = append(args, "name="+rule.LogName)
 		}
 
 		for k, v := range externalIDs {
@@ -1917,6 +1917,40 @@ func (o *OVN) PortGroupPortClearACLRules(portGroupName OVNPortGroup, portName OV
 	return nil
 }
 
+// ACLRuleFlowCookies returns the OpenFlow cookies of the logical flows generated for the ACL rules whose name
+// starts with the specified prefix, indexed by ACL rule name.
+// The OpenFlow flows installed by ovn-controller use the first 32 bits of their logical flow UUID as cookie.
+func (o *OVN) ACLRuleFlowCookies(namePrefix string) (map[string][]string, error) {
+	output, err := o.nbctl("--format=csv", "--no-headings", "--data=bare", "--columns=_uuid,name", "list", "acl")
+	if err != nil {
+		return nil, err
+	}
+
+	cookies := make(map[string][]string)
+	for line := range strings.SplitSeq(strings.TrimSpace(output), "\n") {
+		aclUUID, name, found := strings.Cut(strings.TrimSpace(line), ",")
+		if !found || len(aclUUID) < 8 || !strings.HasPrefix(name, namePrefix) {
+			continue
+		}
+
+		// The logical flows generated for an ACL use the first 8 characters of its UUID as stage hint.
+		output, err := o.sbctl("--format=csv", "--no-headings", "--data=bare", "--columns=_uuid", "find", "logical_flow", "external_ids:stage-hint="+aclUUID[:8])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, flowUUID := range shared.SplitNTrimSpace(strings.TrimSpace(output), "\n", -1, true) {
+			if len(flowUUID) < 8 {
+				continue
+			}
+
+			cookies[name] = append(cookies[name], "0x"+flowUUID[:8])
+		}
+	}
+
+	return cookies, nil
+}
+
 // loadBalancerUUIDs returns a map of UUID records for the named load balancer.
 // All load balancers for all protocols matching the given name are returned.
 // The returned map is keyed by protocol which helps differentiating the UUIDs.
@@ -2534,6 +2568,43 @@ func (o *OVN) AddressSetAdd(addressSetPrefix OVNAddressSet, addresses ...net.IPN
 	return nil
 }
 
+// AddressSetUpdate replaces the addresses of the address sets with the supplied addresses, or creates new address
+// sets if needed. The address set name used is "<addressSetPrefix>_ip<IP version>", e.g. "foo_ip4".
+func (o *OVN) AddressSetUpdate(addressSetPrefix OVNAddressSet, addresses ...net.IPNet) error {
+	args := []string{
+		"clear", "address_set", fmt.Sprintf("%s_ip%d", addressSetPrefix, 4), "addresses",
+		"--", "clear", "address_set", fmt.Sprintf("%s_ip%d", addressSetPrefix, 6), "addresses",
+	}
+
+	for _, address := range addresses {
+		var ipVersion uint = 4
+		if address.IP.To4() == nil {
+			ipVersion = 6
+		}
+
+		args = append(args, "--", "add", "address_set", fmt.Sprintf("%s_ip%d", addressSetPrefix, ipVersion), "addresses", fmt.Sprintf(`"%s"`, address.String()))
+	}
+
+	// Optimistically assume the address sets exist.
+	_, err := o.nbctl(args...)
+	if err != nil {
+		// Try creating the address sets one at a time, but ignore errors here in case one of the address
+		// sets already exists. If there was a problem creating the address set it will be revealed when we
+		// run the original command again next.
+		for _, ipVersion := range []uint{4, 6} {
+			_, _ = o.nbctl("create", "address_set", fmt.Sprintf("name=%s_ip%d", addressSetPrefix, ipVersion))
+		}
+
+		// Try original command again.
+		_, err := o.nbctl(args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // AddressSetRemove removes the supplied addresses from the address set.
 // The address set name used is "<addressSetPrefix>_ip<IP version>", e.g. "foo_ip4".
 func (o *OVN) AddressSetRemove(addressSetPrefix OVNAddressSet, addresses ...net.IPNet) error {
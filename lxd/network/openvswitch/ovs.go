@@ -371,6 +371,40 @@ func (o *OVS) BridgePortList(bridgeName string) ([]string, error) {
 	return ports, nil
 }
 
+// BridgeFlowCounters returns the total packet and byte counters of the OpenFlow flows of the bridge using the specified cookie.
+func (o *OVS) BridgeFlowCounters(bridgeName string, cookie string) (packets uint64, bytes uint64, err error) {
+	output, err := shared.RunCommand(context.TODO(), "ovs-ofctl", "dump-flows", bridgeName, "cookie="+cookie+"/-1")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// E.g. " cookie=0x1d3b2e4f, duration=63.412s, table=44, n_packets=12, n_bytes=1008, priority=2002,ip actions=resubmit(,45)"
+	for line := range strings.SplitSeq(strings.TrimSpace(output), "\n") {
+		for field := range strings.SplitSeq(line, ", ") {
+			key, value, found := strings.Cut(strings.TrimSpace(field), "=")
+			if !found {
+				continue
+			}
+
+			switch key {
+			case "n_packets":
+				count, err := strconv.ParseUint(value, 10, 64)
+				if err == nil {
+					packets += count
+				}
+
+			case "n_bytes":
+				count, err := strconv.ParseUint(value, 10, 64)
+				if err == nil {
+					bytes += count
+				}
+			}
+		}
+	}
+
+	return packets, bytes, nil
+}
+
 // HardwareOffloadingEnabled returns true if hardware offloading is enabled.
 func (o *OVS) HardwareOffloadingEnabled() bool {
 	// ovs-vsctl's get command doesn't support its --format flag, so we always get the output quoted.
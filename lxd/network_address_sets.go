@@ -0,0 +1,737 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/network/acl"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// Network address sets are managed with the network ACL entitlements of their project.
+var networkAddressSetsCmd = APIEndpoint{
+	Path:            "network-address-sets",
+	MetricsType:     entity.TypeNetwork,
+	ProjectSpecific: true,
+
+	Get:  APIEndpointAction{Handler: networkAddressSetsGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients},
+	Post: APIEndpointAction{Handler: networkAddressSetsPost, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateNetworkACLs)},
+}
+
+var networkAddressSetCmd = APIEndpoint{
+	Path:            "network-address-sets/{name}",
+	MetricsType:     entity.TypeNetwork,
+	ProjectSpecific: true,
+
+	Delete: APIEndpointAction{Handler: networkAddressSetDelete, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanDeleteNetworkACLs)},
+	Get:    APIEndpointAction{Handler: networkAddressSetGet, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanViewNetworkACLs)},
+	Put:    APIEndpointAction{Handler: networkAddressSetPut, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanEditNetworkACLs)},
+	Patch:  APIEndpointAction{Handler: networkAddressSetPut, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanEditNetworkACLs)},
+	Post:   APIEndpointAction{Handler: networkAddressSetPost, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanEditNetworkACLs)},
+}
+
+// API endpoints.
+
+// swagger:operation GET /1.0/network-address-sets network-address-sets network_address_sets_get
+//
+//  Get the network address sets
+//
+//  Returns a list of network address sets (URLs).
+//
+//  ---
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      example: default
+//    - in: query
+//      name: all-projects
+//      description: Retrieve network address sets from all projects
+//      type: boolean
+//      example: true
+//  responses:
+//    "200":
+//      description: API endpoints
+//      schema:
+//        type: object
+//        description: Sync response
+//        properties:
+//          type:
+//            type: string
+//            description: Response type
+//            example: sync
+//          status:
+//            type: string
+//            description: Status description
+//            example: Success
+//          status_code:
+//            type: integer
+//            description: Status code
+//            example: 200
+//          metadata:
+//            type: array
+//            description: List of endpoints
+//            items:
+//              type: string
+//            example: |-
+//              [
+//                "/1.0/network-address-sets/foo",
+//                "/1.0/network-address-sets/bar"
+//              ]
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/network-address-sets?recursion=1 network-address-sets network_address_sets_get_recursion1
+//
+//	Get the network address sets
+//
+//	Returns a list of network address sets (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: all-projects
+//	    description: Retrieve network address sets from all projects
+//	    type: boolean
+//	    example: true
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of network address sets
+//	          items:
+//	            $ref: "#/definitions/NetworkAddressSet"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	requestProjectName, allProjects, err := request.ProjectParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var effectiveProjectName string
+	if !allProjects {
+		// Project specific requests require an effective project, when "features.networks" is enabled this is the requested project, otherwise it is the default project.
+		effectiveProjectName, _, err = project.NetworkProject(s.DB.Cluster, requestProjectName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	recursion, _ := util.IsRecursionRequest(r)
+
+	var setNames map[string][]string
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		if allProjects {
+			// Get list of network address sets across all projects.
+			var err error
+			setNames, err = tx.GetNetworkAddressSetsAllProjects(ctx)
+
+			return err
+		}
+
+		// Get list of network address sets.
+		sets, err := tx.GetNetworkAddressSets(ctx, effectiveProjectName)
+		if err != nil {
+			return err
+		}
+
+		// Address set names should be mapped to the requested project for project specific requests.
+		setNames = map[string][]string{requestProjectName: sets}
+
+		return nil
+	})
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	// Address sets are visible to the identities that can view the network ACLs of their project.
+	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanViewNetworkACLs, entity.TypeProject)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resultString := []string{}
+	resultMap := []*api.NetworkAddressSet{}
+	for projectName, sets := range setNames {
+		if !userHasPermission(entity.ProjectURL(projectName)) {
+			continue
+		}
+
+		for _, setName := range sets {
+			if recursion == 0 {
+				resultString = append(resultString, api.NewURL().Path(version.APIVersion, "network-address-sets", setName).String())
+				continue
+			}
+
+			setProjectName := projectName
+			if !allProjects {
+				setProjectName = effectiveProjectName
+			}
+
+			netAddressSet, err := acl.LoadAddressSetByName(r.Context(), s, setProjectName, setName)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			info := netAddressSet.Info()
+			info.UsedBy, _ = netAddressSet.UsedBy() // Ignore errors in UsedBy, will return nil.
+			info.UsedBy = project.FilterUsedBy(r.Context(), s.Authorizer, info.UsedBy)
+			info.Project = projectName
+
+			resultMap = append(resultMap, info)
+		}
+	}
+
+	if recursion == 0 {
+		return response.SyncResponse(true, resultString)
+	}
+
+	return response.SyncResponse(true, resultMap)
+}
+
+// swagger:operation POST /1.0/network-address-sets network-address-sets network_address_sets_post
+//
+//	Add a network address set
+//
+//	Creates a new network address set.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: address-set
+//	    description: Address set
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkAddressSetsPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	effectiveProjectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.NetworkAddressSetsPost{}
+
+	// Parse the request into a record.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	_, err = acl.LoadAddressSetByName(r.Context(), s, effectiveProjectName, req.Name)
+	if err == nil {
+		return response.BadRequest(errors.New("The network address set already exists"))
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		err = acl.CreateAddressSet(ctx, s, effectiveProjectName, &req)
+		if err != nil {
+			return err
+		}
+
+		netAddressSet, err := acl.LoadAddressSetByName(ctx, s, effectiveProjectName, req.Name)
+		if err != nil {
+			return err
+		}
+
+		s.Events.SendLifecycle(effectiveProjectName, lifecycle.NetworkAddressSetCreated.Event(netAddressSet, request.CreateRequestor(ctx), nil))
+
+		return nil
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: request.ProjectParam(r),
+		Type:        operationtype.NetworkAddressSetCreate,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+		EntityURL:   entity.ProjectURL(effectiveProjectName),
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// swagger:operation DELETE /1.0/network-address-sets/{name} network-address-sets network_address_set_delete
+//
+//	Delete the network address set
+//
+//	Removes the network address set.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	effectiveProjectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	setName := r.PathValue("name")
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	clientType := requestor.ClientType()
+
+	// Load the address set before creating the operation so we can return a synchronous 404 if not found.
+	netAddressSet, err := acl.LoadAddressSetByName(r.Context(), s, effectiveProjectName, setName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		err := netAddressSet.Delete(ctx, clientType)
+		if err != nil {
+			return fmt.Errorf("Failed deleting network address set %q: %w", setName, err)
+		}
+
+		if !clientType.IsClusterOperationNotification() {
+			s.Events.SendLifecycle(effectiveProjectName, lifecycle.NetworkAddressSetDeleted.Event(netAddressSet, request.CreateRequestor(ctx), nil))
+		}
+
+		return nil
+	}
+
+	if clientType.IsClusterOperationNotification() {
+		err := run(r.Context(), nil)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.EmptySyncResponse
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: request.ProjectParam(r),
+		Type:        operationtype.NetworkAddressSetDelete,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+		EntityURL:   entity.ProjectURL(effectiveProjectName),
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// swagger:operation GET /1.0/network-address-sets/{name} network-address-sets network_address_set_get
+//
+//	Get the network address set
+//
+//	Gets a specific network address set.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Address set
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/NetworkAddressSet"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	netAddressSet, err := acl.LoadAddressSetByName(r.Context(), s, projectName, r.PathValue("name"))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info := netAddressSet.Info()
+	info.UsedBy, err = netAddressSet.UsedBy()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info.UsedBy = project.FilterUsedBy(r.Context(), s.Authorizer, info.UsedBy)
+
+	return response.SyncResponseETag(true, info, netAddressSet.Etag())
+}
+
+// swagger:operation PATCH /1.0/network-address-sets/{name} network-address-sets network_address_set_patch
+//
+//  Partially update the network address set
+//
+//  Updates a subset of the network address set configuration.
+//
+//  ---
+//  consumes:
+//    - application/json
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      example: default
+//    - in: body
+//      name: address-set
+//      description: Address set configuration
+//      required: true
+//      schema:
+//        $ref: "#/definitions/NetworkAddressSetPut"
+//  responses:
+//    "202":
+//      $ref: "#/responses/Operation"
+//    "400":
+//      $ref: "#/responses/BadRequest"
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "412":
+//      $ref: "#/responses/PreconditionFailed"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+// swagger:operation PUT /1.0/network-address-sets/{name} network-address-sets network_address_set_put
+//
+//	Update the network address set
+//
+//	Updates the entire network address set configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: address-set
+//	    description: Address set configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkAddressSetPut"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the existing network address set.
+	netAddressSet, err := acl.LoadAddressSetByName(r.Context(), s, projectName, r.PathValue("name"))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = util.EtagCheck(r, netAddressSet.Etag())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.NetworkAddressSetPut{}
+
+	// Decode the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if r.Method == http.MethodPatch {
+		// If the address set is being updated via "patch" method, then keep the existing addresses if
+		// not specified and merge all existing config with the keys that are present in the request config.
+		if req.Addresses == nil {
+			req.Addresses = netAddressSet.Info().Addresses
+		}
+
+		if req.Config == nil {
+			req.Config = map[string]string{}
+		}
+
+		for k, v := range netAddressSet.Info().Config {
+			_, ok := req.Config[k]
+			if !ok {
+				req.Config[k] = v
+			}
+		}
+	}
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	clientType := requestor.ClientType()
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		err = netAddressSet.Update(ctx, &req, clientType)
+		if err != nil {
+			return err
+		}
+
+		if !clientType.IsClusterOperationNotification() {
+			s.Events.SendLifecycle(projectName, lifecycle.NetworkAddressSetUpdated.Event(netAddressSet, request.CreateRequestor(ctx), nil))
+		}
+
+		return nil
+	}
+
+	if clientType.IsClusterOperationNotification() {
+		// Operation notification from the member handling the request: handle synchronously.
+		err := run(r.Context(), nil)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.EmptySyncResponse
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: request.ProjectParam(r),
+		Type:        operationtype.NetworkAddressSetUpdate,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+		EntityURL:   entity.ProjectURL(projectName),
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// swagger:operation POST /1.0/network-address-sets/{name} network-address-sets network_address_set_post
+//
+//	Rename the network address set
+//
+//	Renames an existing network address set.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: address-set
+//	    description: Address set rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkAddressSetPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	setName := r.PathValue("name")
+	effectiveProjectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.NetworkAddressSetPost{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Get the existing network address set.
+	netAddressSet, err := acl.LoadAddressSetByName(r.Context(), s, effectiveProjectName, setName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		err = netAddressSet.Rename(ctx, req.Name)
+		if err != nil {
+			return err
+		}
+
+		lc := lifecycle.NetworkAddressSetRenamed.Event(netAddressSet, request.CreateRequestor(ctx), logger.Ctx{"old_name": setName})
+		s.Events.SendLifecycle(effectiveProjectName, lc)
+
+		return nil
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: request.ProjectParam(r),
+		Type:        operationtype.NetworkAddressSetRename,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+		EntityURL:   entity.ProjectURL(effectiveProjectName),
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+func networkAddressSetsRefreshTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := acl.RefreshAddressSets(ctx, stateFunc())
+		if err != nil {
+			logger.Error("Failed refreshing network address sets", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
@@ -276,19 +276,38 @@ func networkPeersPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Network driver %q does not support peering", n.Type()))
 	}
 
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	clientType := requestor.ClientType()
+
 	run := func(ctx context.Context, op *operations.Operation) error {
-		err = n.PeerCreate(req)
+		err = n.PeerCreate(req, clientType)
 		if err != nil {
 			return fmt.Errorf("Failed creating peer: %w", err)
 		}
 
-		requestor := request.CreateRequestor(ctx)
-		lc := lifecycle.NetworkPeerCreated.Event(n, req.Name, requestor, nil)
-		s.Events.SendLifecycle(effectiveProjectName, lc)
+		if !clientType.IsClusterOperationNotification() {
+			requestor := request.CreateRequestor(ctx)
+			lc := lifecycle.NetworkPeerCreated.Event(n, req.Name, requestor, nil)
+			s.Events.SendLifecycle(effectiveProjectName, lc)
+		}
 
 		return nil
 	}
 
+	if clientType.IsClusterOperationNotification() {
+		// Handle cluster operation notification synchronously.
+		err := run(r.Context(), nil)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.EmptySyncResponse
+	}
+
 	args := operations.OperationArgs{
 		ProjectName: details.requestProject.Name,
 		Type:        operationtype.NetworkPeerCreate,
@@ -365,19 +384,38 @@ func networkPeerDelete(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Network driver %q does not support peering", n.Type()))
 	}
 
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	clientType := requestor.ClientType()
+
 	peerName := r.PathValue("peerName")
 	run := func(ctx context.Context, op *operations.Operation) error {
-		err = n.PeerDelete(peerName)
+		err = n.PeerDelete(peerName, clientType)
 		if err != nil {
 			return fmt.Errorf("Failed deleting peer: %w", err)
 		}
 
-		requestor := request.CreateRequestor(ctx)
-		s.Events.SendLifecycle(effectiveProjectName, lifecycle.NetworkPeerDeleted.Event(n, peerName, requestor, nil))
+		if !clientType.IsClusterOperationNotification() {
+			requestor := request.CreateRequestor(ctx)
+			s.Events.SendLifecycle(effectiveProjectName, lifecycle.NetworkPeerDeleted.Event(n, peerName, requestor, nil))
+		}
 
 		return nil
 	}
 
+	if clientType.IsClusterOperationNotification() {
+		// Handle cluster operation notification synchronously.
+		err := run(r.Context(), nil)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.EmptySyncResponse
+	}
+
 	args := operations.OperationArgs{
 		ProjectName: details.requestProject.Name,
 		Type:        operationtype.NetworkPeerDelete,
@@ -342,8 +342,8 @@ func constructSingleOperation(s *state.State, dbOp cluster.Operation, resources
 
 	op.inputs = inputs
 
-	// If the operation is durable, load the run hook.
-	if op.class == operationtype.OperationClassDurable {
+	// If the operation is durable, load the run hook. Bulk operations don't have a run hook, their children do.
+	if op.class == operationtype.OperationClassDurable && !op.dbOpType.IsBulk() {
 		runHook, ok := getDurableOperationRunHook(op.dbOpType)
 		if !ok {
 			return nil, fmt.Errorf("No run hook is defined for durable operation %q", op.dbOpType.Description())
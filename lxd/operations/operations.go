@@ -30,6 +30,10 @@ import (
 	"github.com/canonical/lxd/shared/version"
 )
 
+// durableProgressPersistenceInterval is the minimum interval between two writes of the progress of a durable operation
+// to the database.
+const durableProgressPersistenceInterval = 5 * time.Second
+
 var operationsLock sync.Mutex
 var operations = make(map[string]*Operation)
 
@@ -1148,6 +1152,12 @@ func (op *Operation) Children() []*Operation {
 	return op.children
 }
 
+// State returns the state of the daemon the operation is running on. It gives statically defined durable operation
+// run hooks access to the daemon.
+func (op *Operation) State() *state.State {
+	return op.state
+}
+
 // IsChild returns true if the Operation is a child operation.
 func (op *Operation) IsChild() bool {
 	return op.parent != nil
@@ -1222,7 +1232,26 @@ func (op *Operation) updateProgress(action string, data ioprogress.ProgressData)
 	metadata["progress"] = progress
 
 	// Write the updated metadata.
-	return op.UpdateMetadata(metadata)
+	err := op.UpdateMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	// Durable operations may be restarted on another cluster member, which only sees the metadata saved to the
+	// database. Periodically persist their progress so that it isn't lost when they are relocated.
+	if op.class != operationtype.OperationClassDurable {
+		return nil
+	}
+
+	op.lock.Lock()
+	persist := op.updatedAt.Sub(op.lastPersistenceAttempt) >= durableProgressPersistenceInterval
+	op.lock.Unlock()
+
+	if !persist {
+		return nil
+	}
+
+	return op.Persist()
 }
 
 func (op *Operation) sendEvent(eventMessage any) {
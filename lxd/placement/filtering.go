@@ -3,53 +3,196 @@ package placement
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
 	"net/http"
 	"slices"
+	"strconv"
 
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/shared/api"
 )
 
-// Filter filters the provided slice of candidate cluster members using the provided [api.PlacementGroup].
-func Filter(ctx context.Context, tx *db.ClusterTx, candidates []db.NodeInfo, apiPlacementGroup api.PlacementGroup, evacuation bool) ([]db.NodeInfo, error) {
-	// Get policy and rigor from config.
-	policy := apiPlacementGroup.Config["policy"]
-	rigor := apiPlacementGroup.Config["rigor"]
+// defaultClusterGroup is the cluster group that all cluster members belong to unless removed from it.
+const defaultClusterGroup = "default"
 
-	// If this is an evacuation request, exclude instances on the source cluster member.
-	// This allows placement decisions to be made based on where instances will be, not where they currently are.
-	var memberID *int64
-	if evacuation {
-		sourceMemberID := tx.GetNodeID()
-		memberID = &sourceMemberID
+// memberScopes maps cluster member IDs to the names of the placement scopes they belong to.
+// A nil memberScopes places each cluster member in a scope of its own.
+type memberScopes map[int64][]string
+
+// of returns the scopes of the given cluster member.
+func (m memberScopes) of(memberID int64) []string {
+	scopes := m[memberID]
+	if len(scopes) == 0 {
+		return []string{"member/" + strconv.FormatInt(memberID, 10)}
+	}
+
+	return scopes
+}
+
+// groupPolicy holds the placement settings of a placement group.
+type groupPolicy struct {
+	policy      string
+	rigor       string
+	maxPerScope int
+	scopes      memberScopes
+}
+
+// loadGroupPolicy loads the placement settings of the provided [api.PlacementGroup].
+func loadGroupPolicy(ctx context.Context, tx *db.ClusterTx, apiPlacementGroup api.PlacementGroup) (*groupPolicy, error) {
+	// Get policy, rigor and scope from config.
+	gp := &groupPolicy{
+		policy: apiPlacementGroup.Config["policy"],
+		rigor:  apiPlacementGroup.Config["rigor"],
 	}
 
-	memberToInst, err := cluster.GetInstancesInPlacementGroup(ctx, tx.Tx(), apiPlacementGroup.Name, apiPlacementGroup.Project, memberID)
+	if apiPlacementGroup.Config["max_per_scope"] != "" {
+		var err error
+		gp.maxPerScope, err = strconv.Atoi(apiPlacementGroup.Config["max_per_scope"])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid max_per_scope of placement group %q: %w", apiPlacementGroup.Name, err)
+		}
+	}
+
+	var err error
+	gp.scopes, err = getMemberScopes(ctx, tx, apiPlacementGroup.Config["scope"])
+	if err != nil {
+		return nil, err
+	}
+
+	return gp, nil
+}
+
+// Filter filters the provided slice of candidate cluster members using the provided [api.PlacementGroup].
+// If evacuatedMemberID is not nil, the instances on that cluster member are ignored.
+func Filter(ctx context.Context, tx *db.ClusterTx, candidates []db.NodeInfo, apiPlacementGroup api.PlacementGroup, evacuatedMemberID *int64) ([]db.NodeInfo, error) {
+	gp, err := loadGroupPolicy(ctx, tx, apiPlacementGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	// During an evacuation, the instances on the source cluster member are excluded.
+	// This allows placement decisions to be made based on where instances will be, not where they currently are.
+	memberToInst, err := cluster.GetInstancesInPlacementGroup(ctx, tx.Tx(), apiPlacementGroup.Name, apiPlacementGroup.Project, evacuatedMemberID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get compliant cluster members using the placement group.
-	filteredCandidates, err := getCompliantMembers(policy, rigor, candidates, memberToInst)
+	filteredCandidates, err := getCompliantMembers(gp.policy, gp.rigor, gp.maxPerScope, candidates, gp.scopes, memberToInst)
 	if err != nil {
-		return nil, api.StatusErrorf(http.StatusConflict, "Failed filtering candidate cluster members using placement group %q with %q policy and %q rigor: %w", apiPlacementGroup.Name, policy, rigor, err)
+		return nil, api.StatusErrorf(http.StatusConflict, "Failed filtering candidate cluster members using placement group %q with %q policy and %q rigor: %w", apiPlacementGroup.Name, gp.policy, gp.rigor, err)
 	}
 
 	return filteredCandidates, nil
 }
 
+// getMemberScopes returns the placement scopes of all cluster members for the given placement group scope.
+// With the cluster group scope, the default cluster group is ignored for cluster members that belong to other
+// cluster groups, as it usually contains all cluster members.
+func getMemberScopes(ctx context.Context, tx *db.ClusterTx, scope string) (memberScopes, error) {
+	if scope == "" || scope == api.PlacementScopeMember {
+		return nil, nil
+	}
+
+	members, err := tx.GetNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting cluster members: %w", err)
+	}
+
+	scopes := make(memberScopes, len(members))
+
+	switch scope {
+	case api.PlacementScopeFailureDomain:
+		memberFailureDomains, err := tx.GetNodesFailureDomains(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting cluster member failure domains: %w", err)
+		}
+
+		for _, member := range members {
+			scopes[member.ID] = []string{"failure-domain/" + strconv.FormatUint(memberFailureDomains[member.Address], 10)}
+		}
+
+	case api.PlacementScopeClusterGroup:
+		for _, member := range members {
+			groups := member.Groups
+			if len(groups) > 1 {
+				groups = slices.DeleteFunc(slices.Clone(groups), func(group string) bool { return group == defaultClusterGroup })
+			}
+
+			for _, group := range groups {
+				scopes[member.ID] = append(scopes[member.ID], "cluster-group/"+group)
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("Invalid placement scope %q", scope)
+	}
+
+	return scopes, nil
+}
+
+// mostPopulatedScope returns the scope with the most instances, preferring the first scope in name order on ties.
+func mostPopulatedScope(scopeToCount map[string]int) string {
+	var target string
+	maxInstances := -1
+	for _, scope := range slices.Sorted(maps.Keys(scopeToCount)) {
+		if scopeToCount[scope] > maxInstances {
+			maxInstances = scopeToCount[scope]
+			target = scope
+		}
+	}
+
+	return target
+}
+
 // getCompliantMembers gets compliant cluster members from the provided candidates based on the given placement policy and rigor.
-func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo, memberToInst map[int64][]int64) ([]db.NodeInfo, error) {
+// The policy applies to the scopes of the cluster members, and at most maxPerScope instances are placed in each scope
+// (no limit if zero).
+func getCompliantMembers(policy string, rigor string, maxPerScope int, candidates []db.NodeInfo, scopes memberScopes, memberToInst map[int64][]int64) ([]db.NodeInfo, error) {
 	var compliantCandidates []db.NodeInfo
 
+	// Count the instances in each scope.
+	scopeToCount := make(map[string]int)
+	for memberID, instances := range memberToInst {
+		for _, scope := range scopes.of(memberID) {
+			scopeToCount[scope] += len(instances)
+		}
+	}
+
+	// instanceCount returns the number of instances in the most populated scope of the candidate.
+	instanceCount := func(c db.NodeInfo) int {
+		count := 0
+		for _, scope := range scopes.of(c.ID) {
+			count = max(count, scopeToCount[scope])
+		}
+
+		return count
+	}
+
+	// inScope returns whether the candidate belongs to the given scope.
+	inScope := func(c db.NodeInfo, scope string) bool {
+		return slices.Contains(scopes.of(c.ID), scope)
+	}
+
+	// Exclude the candidates whose scope already reached the limit, whatever the policy and rigor.
+	if maxPerScope > 0 {
+		candidates = slices.DeleteFunc(slices.Clone(candidates), func(c db.NodeInfo) bool {
+			return instanceCount(c) >= maxPerScope
+		})
+
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("No eligible cluster members available with fewer than %d instances in their scope", maxPerScope)
+		}
+	}
+
 	switch {
 	case policy == api.PlacementPolicySpread && rigor == api.PlacementRigorStrict:
-		// Spread + Strict: Place at most one instance per cluster member.
-		// Filter out candidates that already have instances.
+		// Spread + Strict: Place at most one instance per scope.
+		// Filter out candidates in scopes that already have instances.
 		for _, c := range candidates {
-			_, hasInst := memberToInst[c.ID]
-			if !hasInst {
+			if instanceCount(c) == 0 {
 				compliantCandidates = append(compliantCandidates, c)
 			}
 		}
@@ -61,13 +204,13 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		return compliantCandidates, nil
 
 	case policy == api.PlacementPolicySpread && rigor == api.PlacementRigorPermissive:
-		// Spread + Permissive: Prefer spreading instances evenly across cluster members.
-		// The number of instances per cluster member differs by at most one.
+		// Spread + Permissive: Prefer spreading instances evenly across scopes.
+		// The number of instances per scope differs by at most one.
 
 		// Find the minimum instance count among candidates.
 		counts := make([]int, 0, len(candidates))
 		for _, c := range candidates {
-			counts = append(counts, len(memberToInst[c.ID]))
+			counts = append(counts, instanceCount(c))
 		}
 
 		minInstances := 0
@@ -76,10 +219,9 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		}
 
 		// Filter candidates to only those with at most minInstances instances.
-		// This ensures the number of instances per cluster member differs by at most one.
+		// This ensures the number of instances per scope differs by at most one.
 		for _, c := range candidates {
-			instanceCount := len(memberToInst[c.ID])
-			if instanceCount <= minInstances {
+			if instanceCount(c) <= minInstances {
 				compliantCandidates = append(compliantCandidates, c)
 			}
 		}
@@ -91,29 +233,19 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		return compliantCandidates, nil
 
 	case policy == api.PlacementPolicyCompact && rigor == api.PlacementRigorStrict:
-		// Compact + Strict: Place all instances on the same cluster member.
-		// The member with the most instances determines the cluster member.
-		if len(memberToInst) == 0 {
+		// Compact + Strict: Place all instances in the same scope.
+		// The scope with the most instances determines the scope.
+		if len(scopeToCount) == 0 {
 			// No instances yet.
-			// All candidates are valid (first instance determines the member).
+			// All candidates are valid (first instance determines the scope).
 			return candidates, nil
 		}
 
-		// Find which member has the most instances from this placement group.
-		var targetMemberID int64
-		maxInstances := -1
-		for memberID, instances := range memberToInst {
-			if len(instances) > maxInstances {
-				maxInstances = len(instances)
-				targetMemberID = memberID
-			}
-		}
-
-		// Filter candidates to only include the node with the most instances.
+		// Filter candidates to only include the ones in the scope with the most instances.
+		targetScope := mostPopulatedScope(scopeToCount)
 		for _, c := range candidates {
-			if c.ID == targetMemberID {
+			if inScope(c, targetScope) {
 				compliantCandidates = append(compliantCandidates, c)
-				break
 			}
 		}
 
@@ -124,32 +256,26 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		return compliantCandidates, nil
 
 	case policy == api.PlacementPolicyCompact && rigor == api.PlacementRigorPermissive:
-		// Compact + Permissive: Prefer to place all instances on the same cluster member.
-		if len(memberToInst) == 0 {
+		// Compact + Permissive: Prefer to place all instances in the same scope.
+		if len(scopeToCount) == 0 {
 			// No instances yet.
-			// All candidates are valid (first instance determines preferred member).
+			// All candidates are valid (first instance determines preferred scope).
 			return candidates, nil
 		}
 
-		// Find which member has the most instances from this placement group.
-		var preferredMemberID int64
-		maxInstances := -1
-		for memberID, instances := range memberToInst {
-			if len(instances) > maxInstances {
-				maxInstances = len(instances)
-				preferredMemberID = memberID
+		// Check if candidates are available in the preferred scope.
+		preferredScope := mostPopulatedScope(scopeToCount)
+		for _, c := range candidates {
+			if inScope(c, preferredScope) {
+				compliantCandidates = append(compliantCandidates, c)
 			}
 		}
 
-		// Check if preferred member is in candidates.
-		for _, c := range candidates {
-			if c.ID == preferredMemberID {
-				// Preferred member is available.
-				return []db.NodeInfo{c}, nil
-			}
+		if len(compliantCandidates) > 0 {
+			return compliantCandidates, nil
 		}
 
-		// Preferred node is not available - fall back to all candidates.
+		// Preferred scope is not available - fall back to all candidates.
 		return candidates, nil
 
 	default:
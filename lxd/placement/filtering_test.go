@@ -615,7 +615,7 @@ func (s *filteringSuite) TestFilter() {
 				return err
 			}
 
-			got, err := Filter(ctx, tx, tt.args.candidates, *apiPlacementGroup, false)
+			got, err := Filter(ctx, tx, tt.args.candidates, *apiPlacementGroup, nil)
 			if tt.wantErr {
 				s.Error(err)
 				return nil
@@ -631,3 +631,175 @@ func (s *filteringSuite) TestFilter() {
 		}
 	}
 }
+
+func (s *filteringSuite) TestGetCompliantMembersScopes() {
+	candidates := []db.NodeInfo{
+		{ID: 1, Name: "member01"},
+		{ID: 2, Name: "member02"},
+		{ID: 3, Name: "member03"},
+		{ID: 4, Name: "member04"},
+	}
+
+	// Two racks of two cluster members each.
+	scopes := memberScopes{
+		1: {"failure-domain/1"},
+		2: {"failure-domain/1"},
+		3: {"failure-domain/2"},
+		4: {"failure-domain/2"},
+	}
+
+	tests := []struct {
+		name         string
+		policy       string
+		rigor        string
+		maxPerScope  int
+		memberToInst map[int64][]int64
+		want         []int64
+		wantErr      bool
+	}{
+		{
+			name:         "spread/strict: rack with an instance is excluded",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorStrict,
+			memberToInst: map[int64][]int64{1: {10}},
+			want:         []int64{3, 4},
+		},
+		{
+			name:         "spread/strict: no rack left",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorStrict,
+			memberToInst: map[int64][]int64{2: {10}, 4: {11}},
+			wantErr:      true,
+		},
+		{
+			name:         "spread/permissive: least populated rack",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorPermissive,
+			memberToInst: map[int64][]int64{1: {10}, 2: {11}, 3: {12}},
+			want:         []int64{3, 4},
+		},
+		{
+			name:         "compact/strict: rack with the most instances",
+			policy:       api.PlacementPolicyCompact,
+			rigor:        api.PlacementRigorStrict,
+			memberToInst: map[int64][]int64{2: {10}},
+			want:         []int64{1, 2},
+		},
+		{
+			name:         "compact/strict: rack with the most instances is full",
+			policy:       api.PlacementPolicyCompact,
+			rigor:        api.PlacementRigorStrict,
+			maxPerScope:  2,
+			memberToInst: map[int64][]int64{1: {10}, 2: {11}},
+			wantErr:      true,
+		},
+		{
+			name:         "compact/permissive: falls back to other racks when preferred rack is full",
+			policy:       api.PlacementPolicyCompact,
+			rigor:        api.PlacementRigorPermissive,
+			maxPerScope:  1,
+			memberToInst: map[int64][]int64{1: {10}},
+			want:         []int64{3, 4},
+		},
+		{
+			name:         "spread/permissive: all racks are full",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorPermissive,
+			maxPerScope:  1,
+			memberToInst: map[int64][]int64{1: {10}, 3: {11}},
+			wantErr:      true,
+		},
+	}
+
+	for i, tt := range tests {
+		s.T().Logf("Case %d: %s", i, tt.name)
+
+		got, err := getCompliantMembers(tt.policy, tt.rigor, tt.maxPerScope, candidates, scopes, tt.memberToInst)
+		if tt.wantErr {
+			s.Error(err)
+			continue
+		}
+
+		s.Require().NoError(err)
+
+		gotIDs := make([]int64, 0, len(got))
+		for _, member := range got {
+			gotIDs = append(gotIDs, member.ID)
+		}
+
+		s.ElementsMatch(tt.want, gotIDs)
+	}
+}
+
+func (s *filteringSuite) TestPlanMoves() {
+	members := []db.NodeInfo{
+		{ID: 1, Name: "member01"},
+		{ID: 2, Name: "member02"},
+		{ID: 3, Name: "member03"},
+	}
+
+	// candidatesFor returns the same candidate cluster members for all the given instances.
+	candidatesFor := func(members []db.NodeInfo, instances ...int64) map[int64][]db.NodeInfo {
+		candidates := make(map[int64][]db.NodeInfo, len(instances))
+		for _, instID := range instances {
+			candidates[instID] = members
+		}
+
+		return candidates
+	}
+
+	tests := []struct {
+		name         string
+		policy       string
+		rigor        string
+		memberToInst map[int64][]int64
+		candidates   map[int64][]db.NodeInfo
+		want         []Move
+	}{
+		{
+			name:         "spread/strict: instances colocated after a restore are spread again",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorStrict,
+			memberToInst: map[int64][]int64{1: {10, 11, 12}},
+			candidates:   candidatesFor(members, 10, 11, 12),
+			want:         []Move{{InstanceID: 10, SourceID: 1, TargetID: 2}, {InstanceID: 11, SourceID: 1, TargetID: 3}},
+		},
+		{
+			name:         "spread/strict: instances without a compliant cluster member stay in place",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorStrict,
+			memberToInst: map[int64][]int64{1: {10, 11, 12}},
+			candidates:   candidatesFor(members[:2], 10, 11, 12),
+			want:         []Move{{InstanceID: 10, SourceID: 1, TargetID: 2}},
+		},
+		{
+			name:         "spread/permissive: balanced instances stay in place",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorPermissive,
+			memberToInst: map[int64][]int64{1: {10}, 2: {11}, 3: {12, 13}},
+			candidates:   candidatesFor(members, 10, 11, 12, 13),
+		},
+		{
+			name:         "compact/strict: instances join the most populated cluster member",
+			policy:       api.PlacementPolicyCompact,
+			rigor:        api.PlacementRigorStrict,
+			memberToInst: map[int64][]int64{1: {10, 11}, 2: {12}},
+			candidates:   candidatesFor(members, 10, 11, 12),
+			want:         []Move{{InstanceID: 12, SourceID: 2, TargetID: 1}},
+		},
+		{
+			name:         "spread/strict: instances on a cluster member that is not a candidate stay in place",
+			policy:       api.PlacementPolicySpread,
+			rigor:        api.PlacementRigorStrict,
+			memberToInst: map[int64][]int64{1: {10, 11}},
+			candidates:   candidatesFor(members[1:], 10, 11),
+		},
+	}
+
+	for i, tt := range tests {
+		s.T().Logf("Case %d: %s", i, tt.name)
+
+		got := planMoves(tt.policy, tt.rigor, 0, nil, tt.memberToInst, tt.candidates)
+		s.Equal(tt.want, got)
+	}
+}
@@ -0,0 +1,79 @@
+package placement
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Move represents the migration of an instance of a placement group to another cluster member.
+type Move struct {
+	InstanceID int64
+	SourceID   int64
+	TargetID   int64
+}
+
+// Rebalance computes the moves needed to bring the instances of the provided [api.PlacementGroup] back in line with
+// its policy. The memberToInst map gives the instances of the placement group on each cluster member, and the
+// candidates map gives the cluster members each instance can run on. Instances whose current cluster member is not a
+// candidate (for example because it is evacuated or offline) are left in place.
+func Rebalance(ctx context.Context, tx *db.ClusterTx, apiPlacementGroup api.PlacementGroup, memberToInst map[int64][]int64, candidates map[int64][]db.NodeInfo) ([]Move, error) {
+	gp, err := loadGroupPolicy(ctx, tx, apiPlacementGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	return planMoves(gp.policy, gp.rigor, gp.maxPerScope, gp.scopes, memberToInst, candidates), nil
+}
+
+// planMoves places each instance again, in instance ID order, as if it was being created with the other instances of
+// the placement group in their current or planned location. An instance is only moved if its current cluster member
+// is not compliant, in which case it is moved to the compliant cluster member with the fewest instances of the
+// placement group. Instances that cannot be placed in a compliant cluster member are left in place.
+func planMoves(policy string, rigor string, maxPerScope int, scopes memberScopes, memberToInst map[int64][]int64, candidates map[int64][]db.NodeInfo) []Move {
+	// Work on a copy of the instance locations, updated as moves are planned.
+	placed := make(map[int64][]int64, len(memberToInst))
+	instToMember := make(map[int64]int64)
+	for memberID, instances := range memberToInst {
+		placed[memberID] = slices.Clone(instances)
+		for _, instID := range instances {
+			instToMember[instID] = memberID
+		}
+	}
+
+	var moves []Move
+	for _, instID := range slices.Sorted(maps.Keys(instToMember)) {
+		memberID := instToMember[instID]
+		isMember := func(c db.NodeInfo) bool { return c.ID == memberID }
+
+		if !slices.ContainsFunc(candidates[instID], isMember) {
+			continue
+		}
+
+		// Take the instance out of the placement group while evaluating its placement.
+		placed[memberID] = slices.DeleteFunc(placed[memberID], func(id int64) bool { return id == instID })
+		if len(placed[memberID]) == 0 {
+			delete(placed, memberID)
+		}
+
+		targetID := memberID
+		compliant, err := getCompliantMembers(policy, rigor, maxPerScope, candidates[instID], scopes, placed)
+		if err == nil && !slices.ContainsFunc(compliant, isMember) {
+			target := slices.MinFunc(compliant, func(a db.NodeInfo, b db.NodeInfo) int {
+				return cmp.Or(cmp.Compare(len(placed[a.ID]), len(placed[b.ID])), strings.Compare(a.Name, b.Name))
+			})
+
+			targetID = target.ID
+			moves = append(moves, Move{InstanceID: instID, SourceID: memberID, TargetID: targetID})
+		}
+
+		placed[targetID] = append(placed[targetID], instID)
+	}
+
+	return moves
+}
@@ -5,21 +5,30 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 
 	"github.com/canonical/lxd/lxd/auth"
+	lxdCluster "github.com/canonical/lxd/lxd/cluster"
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
 	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/placement"
 	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/ioprogress"
+	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/validate"
 )
 
@@ -44,6 +53,15 @@ var placementGroupCmd = APIEndpoint{
 	Post:   APIEndpointAction{Handler: placementGroupPost, AccessHandler: allowPermission(entity.TypePlacementGroup, auth.EntitlementCanEdit, "name")},
 }
 
+var placementGroupRebalanceCmd = APIEndpoint{
+	Path:            "placement-groups/{name}/rebalance",
+	MetricsType:     entity.TypePlacementGroup,
+	ProjectSpecific: true,
+
+	Get:  APIEndpointAction{Handler: placementGroupRebalanceGet, AccessHandler: allowPermission(entity.TypePlacementGroup, auth.EntitlementCanView, "name")},
+	Post: APIEndpointAction{Handler: placementGroupRebalancePost, AccessHandler: allowPermission(entity.TypePlacementGroup, auth.EntitlementCanEdit, "name")},
+}
+
 func placementGroupEtag(group api.PlacementGroup) any {
 	return []any{group.Name, group.Project, group.Description, group.Config}
 }
@@ -725,6 +743,286 @@ func placementGroupPost(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponseLocation(true, nil, entity.PlacementGroupURL(projectName, placementGroupName).String())
 }
 
+// placementGroupRebalanceMove is a planned migration of an instance of a placement group.
+type placementGroupRebalanceMove struct {
+	inst   instance.Instance
+	source db.NodeInfo
+	target db.NodeInfo
+	live   bool
+}
+
+// placementGroupRebalancePlan computes the instance migrations needed to bring the placement group back in line with
+// its policy. Instances that cannot be migrated are left in place.
+func placementGroupRebalancePlan(ctx context.Context, s *state.State, projectName string, placementGroupName string) ([]placementGroupRebalanceMove, error) {
+	var moves []placementGroupRebalanceMove
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		placementGroup, err := cluster.GetPlacementGroup(ctx, tx.Tx(), placementGroupName, projectName)
+		if err != nil {
+			return err
+		}
+
+		configs, err := cluster.PlacementGroupsConfigStore().GetByEntityIDs(ctx, tx.Tx(), placementGroup.Row.ID)
+		if err != nil {
+			return fmt.Errorf("Failed getting placement group config: %w", err)
+		}
+
+		memberToInst, err := cluster.GetInstancesInPlacementGroup(ctx, tx.Tx(), placementGroupName, projectName, nil)
+		if err != nil {
+			return fmt.Errorf("Failed getting placement group instances: %w", err)
+		}
+
+		instIDs := make(map[int64]bool)
+		for _, instances := range memberToInst {
+			for _, instID := range instances {
+				instIDs[instID] = true
+			}
+		}
+
+		allMembers, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		membersByID := make(map[int64]db.NodeInfo, len(allMembers))
+		for _, member := range allMembers {
+			membersByID[member.ID] = member
+		}
+
+		// Load the instances of the placement group and the cluster members they can be migrated to.
+		instances := make(map[int64]instance.Instance, len(instIDs))
+		candidates := make(map[int64][]db.NodeInfo, len(instIDs))
+		err = tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			instID := int64(dbInst.ID)
+			if !instIDs[instID] {
+				return nil
+			}
+
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q in project %q: %w", dbInst.Name, dbInst.Project, err)
+			}
+
+			instances[instID] = inst
+
+			migrate, _ := inst.CanMigrate()
+			if !migrate {
+				return nil
+			}
+
+			candidates[instID], err = tx.GetCandidateMembers(ctx, allMembers, []int{inst.Architecture()}, "", limits.GetRestrictedClusterGroups(&p), s.GlobalConfig.OfflineThreshold())
+			if err != nil {
+				return err
+			}
+
+			return nil
+		}, cluster.InstanceFilter{Project: &projectName})
+		if err != nil {
+			return err
+		}
+
+		placementMoves, err := placement.Rebalance(ctx, tx, *placementGroup.ToAPI(configs), memberToInst, candidates)
+		if err != nil {
+			return err
+		}
+
+		for _, move := range placementMoves {
+			inst := instances[move.InstanceID]
+
+			// Running instances are live-migrated where possible, others are stopped and started again on the target.
+			_, live := inst.CanMigrate()
+			live = live && inst.LocalConfig()["volatile.last_state.power"] == instance.PowerStateRunning
+
+			moves = append(moves, placementGroupRebalanceMove{
+				inst:   inst,
+				source: membersByID[move.SourceID],
+				target: membersByID[move.TargetID],
+				live:   live,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return moves, nil
+}
+
+// swagger:operation GET /1.0/placement-groups/{name}/rebalance placement-groups placement_group_rebalance_get
+//
+//	Get the placement group rebalance plan
+//
+//	Returns the instance migrations that rebalancing the placement group would perform, without performing them.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Rebalance plan
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/PlacementGroupRebalance"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func placementGroupRebalanceGet(d *Daemon, r *http.Request) response.Response {
+	projectName := request.ProjectParam(r)
+	placementGroupName := r.PathValue("name")
+
+	moves, err := placementGroupRebalancePlan(r.Context(), d.State(), projectName, placementGroupName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	plan := api.PlacementGroupRebalance{
+		Moves: make([]api.PlacementGroupRebalanceMove, 0, len(moves)),
+	}
+
+	for _, move := range moves {
+		plan.Moves = append(plan.Moves, api.PlacementGroupRebalanceMove{
+			Instance: move.inst.Name(),
+			Source:   move.source.Name,
+			Target:   move.target.Name,
+			Live:     move.live,
+		})
+	}
+
+	return response.SyncResponse(true, plan)
+}
+
+// swagger:operation POST /1.0/placement-groups/{name}/rebalance placement-groups placement_group_rebalance_post
+//
+//	Rebalance the placement group
+//
+//	Migrates the instances of the placement group so that their placement complies with its policy again.
+//	Running virtual machines are live-migrated where possible.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func placementGroupRebalancePost(d *Daemon, r *http.Request) response.Response {
+	projectName := request.ProjectParam(r)
+	placementGroupName := r.PathValue("name")
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("Placement groups can only be rebalanced in a cluster"))
+	}
+
+	moves, err := placementGroupRebalancePlan(r.Context(), s, projectName, placementGroupName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Moving an instance requires the permission to edit it.
+	for _, move := range moves {
+		err := s.Authorizer.CheckPermission(r.Context(), entity.InstanceURL(projectName, move.inst.Name()), auth.EntitlementCanEdit)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	requestor := request.CreateRequestor(r.Context())
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		for _, move := range moves {
+			reportPlacementGroupRebalanceProgress(op, fmt.Sprintf("Migrating %q to %q", move.inst.Name(), move.target.Name))
+
+			// Let the cluster member hosting the instance migrate it, stopping and starting it again if needed.
+			source, err := lxdCluster.Connect(ctx, move.source.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+			if err != nil {
+				return fmt.Errorf("Failed connecting to cluster member %q: %w", move.source.Name, err)
+			}
+
+			source = source.UseProject(projectName).UseTarget(move.target.Name)
+
+			migrateOp, err := source.MigrateInstance(move.inst.Name(), api.InstancePost{
+				Name:      move.inst.Name(),
+				Migration: true,
+				Live:      move.live,
+			})
+			if err != nil {
+				return fmt.Errorf("Failed migrating instance %q to %q: %w", move.inst.Name(), move.target.Name, err)
+			}
+
+			err = migrateOp.WaitContext(ctx)
+			if err != nil {
+				return fmt.Errorf("Failed migrating instance %q to %q: %w", move.inst.Name(), move.target.Name, err)
+			}
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.PlacementGroupRebalanced.Event(projectName, placementGroupName, requestor, logger.Ctx{"moves": len(moves)}))
+
+		return nil
+	}
+
+	placementGroupURL := entity.PlacementGroupURL(projectName, placementGroupName)
+	args := operations.OperationArgs{
+		ProjectName:       projectName,
+		EntityURL:         placementGroupURL,
+		Type:              operationtype.PlacementGroupRebalance,
+		Class:             operationtype.OperationClassTask,
+		RunHook:           run,
+		ConflictReference: placementGroupURL.String(), // Prevents concurrent rebalancing of the same placement group.
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// reportPlacementGroupRebalanceProgress reports the progress of a placement group rebalance in the operation metadata.
+func reportPlacementGroupRebalanceProgress(progressReporter ioprogress.ProgressReporter, message string) {
+	handler := progressReporter.ProgressHandler("rebalance")
+	handler(ioprogress.ProgressData{Text: message})
+}
+
 // placementGroupValidateConfig validates the configuration keys/values for placement groups.
 func placementGroupValidateConfig(config map[string]string) error {
 	placementGroupConfigKeys := map[string]func(value string) error{
@@ -750,6 +1048,28 @@ func placementGroupValidateConfig(config map[string]string) error {
 		//  required: "yes"
 		//  shortdesc: Enforcement level of the placement policy
 		"rigor": validate.IsOneOf(api.PlacementRigorStrict, api.PlacementRigorPermissive),
+
+		// lxdmeta:generate(entities=placement-group; group=placement-group; key=scope)
+		// Determines what the policy applies to.
+		//
+		// Possible values are `member` (individual cluster members), `failure-domain` (failure domains of
+		// cluster members) and `cluster-group` (cluster groups).
+		// With `cluster-group`, the `default` cluster group is ignored for cluster members that belong to
+		// other cluster groups.
+		// See {ref}`clustering-instance-placement` for more information.
+		// ---
+		//  type: string
+		//  defaultdesc: `member`
+		//  shortdesc: Scope of the placement policy
+		"scope": validate.Optional(validate.IsOneOf(api.PlacementScopeMember, api.PlacementScopeFailureDomain, api.PlacementScopeClusterGroup)),
+
+		// lxdmeta:generate(entities=placement-group; group=placement-group; key=max_per_scope)
+		// Instances are never placed in a scope that already contains this number of instances of the
+		// placement group, regardless of the rigor.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of instances per scope
+		"max_per_scope": validate.Optional(validate.IsInRange(1, math.MaxUint32)),
 	}
 
 	for k, v := range config {
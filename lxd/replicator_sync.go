@@ -0,0 +1,643 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/client"
+	lxdCluster "github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// replicatorProjectObjects holds the project level objects that are replicated alongside the instances of a project.
+// Objects are only included if the project has the corresponding feature enabled. Otherwise they belong to the
+// default project, which is shared with other projects and therefore not replicated.
+type replicatorProjectObjects struct {
+	// syncProfiles, syncNetworks and syncVolumes indicate which features are enabled in the project.
+	syncProfiles bool
+	syncNetworks bool
+	syncVolumes  bool
+
+	profiles []api.Profile
+	networks []api.Network
+	acls     []api.NetworkACL
+
+	// forwards holds the network forwards of each network, keyed by network name.
+	// Only forwards that are not specific to a cluster member are included.
+	forwards map[string][]api.NetworkForward
+
+	volumes []*db.StorageVolume
+}
+
+// replicatorLoadProjectObjects loads the project level objects of the given project that are replicated.
+func replicatorLoadProjectObjects(ctx context.Context, s *state.State, p *api.Project) (*replicatorProjectObjects, error) {
+	objects := &replicatorProjectObjects{
+		syncProfiles: project.ProfileProjectFromRecord(p) == p.Name,
+		syncNetworks: project.NetworkProjectFromRecord(p) == p.Name,
+		syncVolumes:  project.StorageVolumeProjectFromRecord(p, dbCluster.StoragePoolVolumeTypeCustom) == p.Name,
+		forwards:     map[string][]api.NetworkForward{},
+	}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		if objects.syncProfiles {
+			dbProfiles, err := dbCluster.GetProfiles(ctx, tx.Tx(), dbCluster.ProfileFilter{Project: &p.Name})
+			if err != nil {
+				return fmt.Errorf("Failed loading profiles: %w", err)
+			}
+
+			for _, dbProfile := range dbProfiles {
+				apiProfile, err := dbProfile.ToAPI(ctx, tx.Tx(), nil, nil)
+				if err != nil {
+					return fmt.Errorf("Failed loading profile %q: %w", dbProfile.Name, err)
+				}
+
+				objects.profiles = append(objects.profiles, *apiProfile)
+			}
+		}
+
+		if objects.syncNetworks {
+			aclNames, err := tx.GetNetworkACLs(ctx, p.Name)
+			if err != nil {
+				return fmt.Errorf("Failed loading network ACLs: %w", err)
+			}
+
+			for _, aclName := range aclNames {
+				_, acl, err := tx.GetNetworkACL(ctx, p.Name, aclName)
+				if err != nil {
+					return fmt.Errorf("Failed loading network ACL %q: %w", aclName, err)
+				}
+
+				objects.acls = append(objects.acls, *acl)
+			}
+
+			networks, err := tx.GetCreatedNetworksByProject(ctx, p.Name)
+			if err != nil {
+				return fmt.Errorf("Failed loading networks: %w", err)
+			}
+
+			for networkID, network := range networks {
+				objects.networks = append(objects.networks, network)
+
+				forwards, err := tx.GetNetworkForwards(ctx, networkID, false)
+				if err != nil {
+					return fmt.Errorf("Failed loading forwards of network %q: %w", network.Name, err)
+				}
+
+				for _, forward := range forwards {
+					// Forwards of member specific networks (such as bridges) don't make sense on another cluster.
+					if forward.Location != "" {
+						continue
+					}
+
+					objects.forwards[network.Name] = append(objects.forwards[network.Name], *forward)
+				}
+			}
+		}
+
+		if objects.syncVolumes {
+			volType := dbCluster.StoragePoolVolumeTypeCustom
+			volumes, err := tx.GetStorageVolumes(ctx, false, db.StorageVolumeFilter{Type: &volType, Project: &p.Name})
+			if err != nil {
+				return fmt.Errorf("Failed loading custom volumes: %w", err)
+			}
+
+			for _, vol := range volumes {
+				// Snapshots are transferred along with their parent volume.
+				if shared.IsSnapshot(vol.Name) {
+					continue
+				}
+
+				objects.volumes = append(objects.volumes, vol)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort networks by name so that they are synced in a stable order.
+	slices.SortFunc(objects.networks, func(a api.Network, b api.Network) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return objects, nil
+}
+
+// replicatorWaitOperation waits for the remote operation returned by a client call to complete.
+func replicatorWaitOperation(op lxd.Operation, err error) error {
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+// replicatorRunProjectSyncOperationRunHook is the run hook of the durable replicator run child operation that copies
+// the network ACLs, networks, network forwards and profiles of the project to the target cluster. Objects that already
+// exist on the target cluster are updated to match the local project.
+func replicatorRunProjectSyncOperationRunHook(ctx context.Context, op *operations.Operation) error {
+	run, err := replicatorRunFromOperation(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	objects, err := replicatorLoadProjectObjects(ctx, op.State(), run.sourceProject)
+	if err != nil {
+		return err
+	}
+
+	dstClient, err := run.connectTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	return replicatorSyncProjectObjects(objects, dstClient)
+}
+
+// replicatorSyncProjectObjects creates or updates the given objects on the target cluster. Objects are synced in
+// dependency order, and a failure to sync one object doesn't prevent the others from being synced.
+func replicatorSyncProjectObjects(objects *replicatorProjectObjects, dstClient lxd.InstanceServer) error {
+	var errs []error
+
+	if objects.syncNetworks {
+		targetACLNames, err := dstClient.GetNetworkACLNames()
+		if err != nil {
+			return fmt.Errorf("Failed listing network ACLs on target: %w", err)
+		}
+
+		// Create the missing ACLs without rules first, as rules can reference other ACLs.
+		for _, acl := range objects.acls {
+			if slices.Contains(targetACLNames, acl.Name) {
+				continue
+			}
+
+			err = replicatorWaitOperation(dstClient.CreateNetworkACL(api.NetworkACLsPost{
+				NetworkACLPost: api.NetworkACLPost{Name: acl.Name},
+				NetworkACLPut:  api.NetworkACLPut{Description: acl.Description, Config: acl.Config},
+			}))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed creating network ACL %q on target: %w", acl.Name, err))
+			}
+		}
+
+		for _, acl := range objects.acls {
+			err = replicatorWaitOperation(dstClient.UpdateNetworkACL(acl.Name, acl.Writable(), ""))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed updating network ACL %q on target: %w", acl.Name, err))
+			}
+		}
+
+		targetNetworkNames, err := dstClient.GetNetworkNames()
+		if err != nil {
+			return errors.Join(append(errs, fmt.Errorf("Failed listing networks on target: %w", err))...)
+		}
+
+		for _, network := range objects.networks {
+			networkPut := network.Writable()
+
+			// Volatile keys are specific to the cluster the network runs on.
+			for key := range networkPut.Config {
+				if strings.HasPrefix(key, "volatile.") {
+					delete(networkPut.Config, key)
+				}
+			}
+
+			if slices.Contains(targetNetworkNames, network.Name) {
+				err = replicatorWaitOperation(dstClient.UpdateNetwork(network.Name, networkPut, ""))
+			} else {
+				err = replicatorWaitOperation(dstClient.CreateNetwork(api.NetworksPost{
+					Name:       network.Name,
+					Type:       network.Type,
+					NetworkPut: networkPut,
+				}))
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed syncing network %q to target: %w", network.Name, err))
+				continue
+			}
+
+			forwards := objects.forwards[network.Name]
+			if len(forwards) == 0 {
+				continue
+			}
+
+			targetListenAddresses, err := dstClient.GetNetworkForwardAddresses(network.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed listing forwards of network %q on target: %w", network.Name, err))
+				continue
+			}
+
+			for _, forward := range forwards {
+				if slices.Contains(targetListenAddresses, forward.ListenAddress) {
+					err = replicatorWaitOperation(dstClient.UpdateNetworkForward(network.Name, forward.ListenAddress, forward.Writable(), ""))
+				} else {
+					err = replicatorWaitOperation(dstClient.CreateNetworkForward(network.Name, api.NetworkForwardsPost{
+						ListenAddress:     forward.ListenAddress,
+						NetworkForwardPut: forward.Writable(),
+					}))
+				}
+
+				if err != nil {
+					errs = append(errs, fmt.Errorf("Failed syncing forward %q of network %q to target: %w", forward.ListenAddress, network.Name, err))
+				}
+			}
+		}
+	}
+
+	if objects.syncProfiles {
+		targetProfileNames, err := dstClient.GetProfileNames()
+		if err != nil {
+			return errors.Join(append(errs, fmt.Errorf("Failed listing profiles on target: %w", err))...)
+		}
+
+		for _, profile := range objects.profiles {
+			if slices.Contains(targetProfileNames, profile.Name) {
+				err = replicatorWaitOperation(dstClient.UpdateProfile(profile.Name, profile.Writable(), ""))
+			} else {
+				err = dstClient.CreateProfile(api.ProfilesPost{Name: profile.Name, ProfilePut: profile.Writable()})
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed syncing profile %q to target: %w", profile.Name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// replicatorRunVolumeForwardOperationRunHook is the run hook of the durable replicator run child operation that
+// replicates a custom volume, along with its snapshots, to the target cluster.
+func replicatorRunVolumeForwardOperationRunHook(ctx context.Context, op *operations.Operation) error {
+	s := op.State()
+
+	run, err := replicatorRunFromOperation(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	poolName, err := operations.GetOperationInputValue[string](op, operationInputKeyReplicatorPool)
+	if err != nil {
+		return err
+	}
+
+	volName, err := operations.GetOperationInputValue[string](op, operationInputKeyReplicatorVolume)
+	if err != nil {
+		return err
+	}
+
+	location, err := operations.GetOperationInputValue[string](op, operationInputKeyReplicatorLocation)
+	if err != nil {
+		return err
+	}
+
+	// Only load the replicated volume, rather than all the project objects for each volume of the run.
+	var volumes []*db.StorageVolume
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		volType := dbCluster.StoragePoolVolumeTypeCustom
+		volumes, err = tx.GetStorageVolumes(ctx, false, db.StorageVolumeFilter{Type: &volType, Project: &run.sourceProject.Name, Name: &volName})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading custom volume %q: %w", volName, err)
+	}
+
+	idx := slices.IndexFunc(volumes, func(vol *db.StorageVolume) bool {
+		return vol.Pool == poolName && vol.Location == location
+	})
+	if idx < 0 {
+		// The volume was deleted since the run started, it is removed from the target by the reconciliation.
+		logger.Warn("Skipping replication of deleted custom volume", logger.Ctx{"project": op.Project(), "pool": poolName, "volume": volName})
+		return nil
+	}
+
+	vol := volumes[idx]
+
+	dstClient, err := run.connectTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	return replicateCustomVolume(ctx, s, op, op.Project(), vol, run.nodeAddressByName[vol.Location], dstClient, run.targetCertPEM(), run.bandwidthLimit)
+}
+
+// replicateCustomVolume refreshes a custom volume and its snapshots on the target cluster. Custom volumes on pools
+// that are local to another cluster member are sent by that member.
+func replicateCustomVolume(ctx context.Context, s *state.State, op *operations.Operation, projectName string, vol *db.StorageVolume, memberAddress string, dstClient lxd.InstanceServer, targetCertPEM string, bandwidthLimit int64) error {
+	// Refresh the existing volume in place on the cluster member that holds it.
+	refresh := false
+	dstVolClient := dstClient
+	targetVol, _, err := dstClient.GetStoragePoolVolume(vol.Pool, vol.Type, vol.Name)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return fmt.Errorf("Failed getting custom volume %q from target: %w", vol.Name, err)
+	} else if err == nil {
+		refresh = true
+		if targetVol.Location != "" && targetVol.Location != "none" {
+			dstVolClient = dstClient.UseTarget(targetVol.Location)
+		}
+	}
+
+	// Set up a push-mode migration sink on the destination.
+	destOp, err := dstVolClient.CreateStoragePoolVolume(vol.Pool, api.StorageVolumesPost{
+		Name:             vol.Name,
+		Type:             vol.Type,
+		ContentType:      vol.ContentType,
+		StorageVolumePut: vol.Writable(),
+		Source: api.StorageVolumeSource{
+			Type:    api.SourceTypeMigration,
+			Mode:    "push",
+			Refresh: refresh,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed requesting custom volume create on destination for %q: %w", vol.Name, err)
+	}
+
+	destOpCancelled := false
+	defer func() {
+		if !destOpCancelled {
+			_ = destOp.Cancel()
+		}
+	}()
+
+	destOpAPI := destOp.Get()
+	destSecrets, err := destOpAPI.WebsocketSecrets()
+	if err != nil {
+		return fmt.Errorf("Failed getting websocket secrets from destination for custom volume %q: %w", vol.Name, err)
+	}
+
+	pushTarget := api.StorageVolumePostTarget{
+		Operation:   destOp.URL().String(),
+		Websockets:  destSecrets,
+		Certificate: targetCertPEM,
+	}
+
+	// Custom volume on another cluster member: ask the hosting cluster member to push the volume.
+	if vol.Location != "" && vol.Location != s.ServerName {
+		if memberAddress == "" {
+			return fmt.Errorf("Failed resolving cluster member address for custom volume %q", vol.Name)
+		}
+
+		memberClient, err := lxdCluster.Connect(ctx, memberAddress, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+		if err != nil {
+			return fmt.Errorf("Failed connecting to hosting cluster member for custom volume %q: %w", vol.Name, err)
+		}
+
+		srcMigrateOp, _, err := memberClient.RawOperation(http.MethodPost, "/internal/replicators/migrate-volume", internalReplicatorMigrateVolumePost{
+			Project:        projectName,
+			Pool:           vol.Pool,
+			Volume:         vol.Name,
+			Target:         pushTarget,
+			BandwidthLimit: bandwidthLimit,
+		}, "")
+		if err != nil {
+			return fmt.Errorf("Failed starting push migration for custom volume %q: %w", vol.Name, err)
+		}
+
+		destOpCancelled = true
+
+		err = srcMigrateOp.Wait()
+		_ = op.ExtendMetadata(map[string]any{"bytes_transferred": replicatorBytesTransferred(srcMigrateOp.Get().Metadata)})
+		if err != nil {
+			return fmt.Errorf("Replication of custom volume %q failed on hosting cluster member: %w", vol.Name, err)
+		}
+
+		return destOp.Wait()
+	}
+
+	migrArgs, err := replicatorVolumeMigrationSourceOperationArgs(s, projectName, vol.Location, vol.Pool, vol.Name, &pushTarget, bandwidthLimit)
+	if err != nil {
+		return err
+	}
+
+	var srcOp *operations.Operation
+	if op.Requestor() != nil {
+		srcOp, err = operations.ScheduleUserOperationFromOperation(s, op, migrArgs)
+	} else {
+		srcOp, err = operations.ScheduleServerOperation(s, migrArgs)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	destOpCancelled = true
+
+	err = srcOp.Wait(context.Background())
+	_ = op.ExtendMetadata(map[string]any{"bytes_transferred": replicatorBytesTransferred(srcOp.Metadata())})
+	if err != nil {
+		return fmt.Errorf("Replication of custom volume %q failed on source: %w", vol.Name, err)
+	}
+
+	return destOp.Wait()
+}
+
+// replicatorVolumeMigrationSourceOperationArgs returns the arguments of an operation that push-migrates the given
+// custom volume, including its snapshots, to the migration sink described by pushTarget, limiting the transfer to
+// bandwidthLimit bytes per second.
+func replicatorVolumeMigrationSourceOperationArgs(s *state.State, projectName string, location string, poolName string, volName string, pushTarget *api.StorageVolumePostTarget, bandwidthLimit int64) (operations.OperationArgs, error) {
+	srcMigration, err := newStorageMigrationSource(false, pushTarget)
+	if err != nil {
+		return operations.OperationArgs{}, fmt.Errorf("Failed setting up migration source for custom volume %q: %w", volName, err)
+	}
+
+	srcMigration.limiter = util.NewRateLimiter(bandwidthLimit)
+
+	return operations.OperationArgs{
+		ProjectName: projectName,
+		EntityURL:   entity.StorageVolumeURL(projectName, location, poolName, dbCluster.StoragePoolVolumeTypeNameCustom, volName),
+		Type:        operationtype.VolumeMigrate,
+		Class:       operationtype.OperationClassTask,
+		RunHook: func(ctx context.Context, innerOp *operations.Operation) error {
+			err := srcMigration.DoStorage(s, projectName, poolName, volName, innerOp)
+
+			// Record the amount of data sent so that it can be reported in the replicator run history.
+			_ = innerOp.ExtendMetadata(map[string]any{"bytes_transferred": srcMigration.bytesSent.Load()})
+
+			return err
+		},
+	}, nil
+}
+
+// replicatorRunReconcileOperationRunHook is the run hook of the durable replicator run child operation that deletes
+// the instances and project level objects that exist on the target cluster but were deleted from the local project.
+func replicatorRunReconcileOperationRunHook(ctx context.Context, op *operations.Operation) error {
+	s := op.State()
+	projectName := op.Project()
+
+	run, err := replicatorRunFromOperation(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	objects, err := replicatorLoadProjectObjects(ctx, s, run.sourceProject)
+	if err != nil {
+		return err
+	}
+
+	var instanceNames []string
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbInstances, err := dbCluster.GetInstances(ctx, tx.Tx(), dbCluster.InstanceFilter{Project: &projectName})
+		if err != nil {
+			return err
+		}
+
+		for _, dbInst := range dbInstances {
+			instanceNames = append(instanceNames, dbInst.Name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed listing project instances: %w", err)
+	}
+
+	dstClient, err := run.connectTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	return replicatorReconcileProjectObjects(projectName, objects, instanceNames, dstClient)
+}
+
+// replicatorReconcileProjectObjects deletes the instances and objects of the target project that don't exist in the
+// local project. Deletions happen in reverse dependency order, and a failure to delete one item doesn't prevent the
+// others from being deleted.
+func replicatorReconcileProjectObjects(projectName string, objects *replicatorProjectObjects, instanceNames []string, dstClient lxd.InstanceServer) error {
+	var errs []error
+
+	deleted := func(kind string, name string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed deleting %s %q from target: %w", kind, name, err))
+			return
+		}
+
+		logger.Info("Deleted replicated item removed from project", logger.Ctx{"project": projectName, "type": kind, "name": name})
+	}
+
+	targetInstanceNames, err := dstClient.GetInstanceNames(api.InstanceTypeAny)
+	if err != nil {
+		return fmt.Errorf("Failed listing instances on target: %w", err)
+	}
+
+	for _, instName := range targetInstanceNames {
+		if !slices.Contains(instanceNames, instName) {
+			deleted("instance", instName, replicatorWaitOperation(dstClient.DeleteInstance(instName, false)))
+		}
+	}
+
+	if objects.syncVolumes {
+		localVolumes := make([]string, 0, len(objects.volumes))
+		for _, vol := range objects.volumes {
+			localVolumes = append(localVolumes, vol.Pool+"/"+vol.Name)
+		}
+
+		targetPoolNames, err := dstClient.GetStoragePoolNames()
+		if err != nil {
+			return errors.Join(append(errs, fmt.Errorf("Failed listing storage pools on target: %w", err))...)
+		}
+
+		for _, poolName := range targetPoolNames {
+			targetVolumes, err := dstClient.GetStoragePoolVolumes(poolName)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed listing volumes of storage pool %q on target: %w", poolName, err))
+				continue
+			}
+
+			for _, vol := range targetVolumes {
+				if vol.Type != dbCluster.StoragePoolVolumeTypeNameCustom || shared.IsSnapshot(vol.Name) || slices.Contains(localVolumes, poolName+"/"+vol.Name) {
+					continue
+				}
+
+				volClient := dstClient
+				if vol.Location != "" && vol.Location != "none" {
+					volClient = dstClient.UseTarget(vol.Location)
+				}
+
+				deleted("custom volume", poolName+"/"+vol.Name, replicatorWaitOperation(volClient.DeleteStoragePoolVolume(poolName, vol.Type, vol.Name)))
+			}
+		}
+	}
+
+	if objects.syncProfiles {
+		targetProfileNames, err := dstClient.GetProfileNames()
+		if err != nil {
+			return errors.Join(append(errs, fmt.Errorf("Failed listing profiles on target: %w", err))...)
+		}
+
+		for _, profileName := range targetProfileNames {
+			if profileName == "default" || slices.ContainsFunc(objects.profiles, func(p api.Profile) bool { return p.Name == profileName }) {
+				continue
+			}
+
+			deleted("profile", profileName, dstClient.DeleteProfile(profileName))
+		}
+	}
+
+	if objects.syncNetworks {
+		targetNetworks, err := dstClient.GetNetworks()
+		if err != nil {
+			return errors.Join(append(errs, fmt.Errorf("Failed listing networks on target: %w", err))...)
+		}
+
+		for _, network := range targetNetworks {
+			if !network.Managed {
+				continue
+			}
+
+			targetForwards, err := dstClient.GetNetworkForwards(network.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed listing forwards of network %q on target: %w", network.Name, err))
+				continue
+			}
+
+			for _, forward := range targetForwards {
+				if forward.Location != "" || slices.ContainsFunc(objects.forwards[network.Name], func(f api.NetworkForward) bool { return f.ListenAddress == forward.ListenAddress }) {
+					continue
+				}
+
+				deleted("network forward", network.Name+"/"+forward.ListenAddress, replicatorWaitOperation(dstClient.DeleteNetworkForward(network.Name, forward.ListenAddress)))
+			}
+		}
+
+		for _, network := range targetNetworks {
+			if !network.Managed || slices.ContainsFunc(objects.networks, func(n api.Network) bool { return n.Name == network.Name }) {
+				continue
+			}
+
+			deleted("network", network.Name, replicatorWaitOperation(dstClient.DeleteNetwork(network.Name)))
+		}
+
+		targetACLNames, err := dstClient.GetNetworkACLNames()
+		if err != nil {
+			return errors.Join(append(errs, fmt.Errorf("Failed listing network ACLs on target: %w", err))...)
+		}
+
+		for _, aclName := range targetACLNames {
+			if slices.ContainsFunc(objects.acls, func(acl api.NetworkACL) bool { return acl.Name == aclName }) {
+				continue
+			}
+
+			deleted("network ACL", aclName, replicatorWaitOperation(dstClient.DeleteNetworkACL(aclName)))
+		}
+	}
+
+	return errors.Join(errs...)
+}
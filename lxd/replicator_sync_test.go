@@ -0,0 +1,314 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// replicatorTestTarget is a stand-in for the target cluster of a replicator.
+// It records the calls that change the target project, and fails those on the items listed in fail.
+type replicatorTestTarget struct {
+	lxd.InstanceServer
+
+	instances []string
+	acls      []string
+	networks  []api.Network
+	forwards  map[string][]api.NetworkForward
+	profiles  []string
+	volumes   map[string][]api.StorageVolume
+
+	// networkConfigs holds the config of the networks created or updated on the target.
+	networkConfigs map[string]map[string]string
+
+	fail  []string
+	calls []string
+}
+
+// call records a call and returns its outcome.
+func (r *replicatorTestTarget) call(action string, name string) error {
+	r.calls = append(r.calls, action+" "+name)
+	for _, fail := range r.fail {
+		if fail == name {
+			return fmt.Errorf("Failed %s %q", action, name)
+		}
+	}
+
+	return nil
+}
+
+// operation records a call that returns a remote operation.
+func (r *replicatorTestTarget) operation(action string, name string) (lxd.Operation, error) {
+	err := r.call(action, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return replicatorTestOperation{}, nil
+}
+
+func (r *replicatorTestTarget) UseTarget(name string) lxd.InstanceServer {
+	return r
+}
+
+func (r *replicatorTestTarget) GetInstanceNames(instanceType api.InstanceType) ([]string, error) {
+	return r.instances, nil
+}
+
+func (r *replicatorTestTarget) DeleteInstance(name string, force bool) (lxd.Operation, error) {
+	return r.operation("delete-instance", name)
+}
+
+func (r *replicatorTestTarget) GetNetworkACLNames() ([]string, error) {
+	return r.acls, nil
+}
+
+func (r *replicatorTestTarget) CreateNetworkACL(acl api.NetworkACLsPost) (lxd.Operation, error) {
+	return r.operation("create-acl", acl.Name)
+}
+
+func (r *replicatorTestTarget) UpdateNetworkACL(name string, acl api.NetworkACLPut, ETag string) (lxd.Operation, error) {
+	return r.operation("update-acl", name)
+}
+
+func (r *replicatorTestTarget) DeleteNetworkACL(name string) (lxd.Operation, error) {
+	return r.operation("delete-acl", name)
+}
+
+func (r *replicatorTestTarget) GetNetworkNames() ([]string, error) {
+	names := make([]string, 0, len(r.networks))
+	for _, network := range r.networks {
+		names = append(names, network.Name)
+	}
+
+	return names, nil
+}
+
+func (r *replicatorTestTarget) GetNetworks() ([]api.Network, error) {
+	return r.networks, nil
+}
+
+func (r *replicatorTestTarget) CreateNetwork(network api.NetworksPost) (lxd.Operation, error) {
+	r.networkConfigs[network.Name] = network.Config
+	return r.operation("create-network", network.Name)
+}
+
+func (r *replicatorTestTarget) UpdateNetwork(name string, network api.NetworkPut, ETag string) (lxd.Operation, error) {
+	r.networkConfigs[name] = network.Config
+	return r.operation("update-network", name)
+}
+
+func (r *replicatorTestTarget) DeleteNetwork(name string) (lxd.Operation, error) {
+	return r.operation("delete-network", name)
+}
+
+func (r *replicatorTestTarget) GetNetworkForwardAddresses(networkName string) ([]string, error) {
+	addresses := make([]string, 0, len(r.forwards[networkName]))
+	for _, forward := range r.forwards[networkName] {
+		addresses = append(addresses, forward.ListenAddress)
+	}
+
+	return addresses, nil
+}
+
+func (r *replicatorTestTarget) GetNetworkForwards(networkName string) ([]api.NetworkForward, error) {
+	return r.forwards[networkName], nil
+}
+
+func (r *replicatorTestTarget) CreateNetworkForward(networkName string, forward api.NetworkForwardsPost) (lxd.Operation, error) {
+	return r.operation("create-forward", networkName+"/"+forward.ListenAddress)
+}
+
+func (r *replicatorTestTarget) UpdateNetworkForward(networkName string, listenAddress string, forward api.NetworkForwardPut, ETag string) (lxd.Operation, error) {
+	return r.operation("update-forward", networkName+"/"+listenAddress)
+}
+
+func (r *replicatorTestTarget) DeleteNetworkForward(networkName string, listenAddress string) (lxd.Operation, error) {
+	return r.operation("delete-forward", networkName+"/"+listenAddress)
+}
+
+func (r *replicatorTestTarget) GetProfileNames() ([]string, error) {
+	return r.profiles, nil
+}
+
+func (r *replicatorTestTarget) CreateProfile(profile api.ProfilesPost) error {
+	return r.call("create-profile", profile.Name)
+}
+
+func (r *replicatorTestTarget) UpdateProfile(name string, profile api.ProfilePut, ETag string) (lxd.Operation, error) {
+	return r.operation("update-profile", name)
+}
+
+func (r *replicatorTestTarget) DeleteProfile(name string) error {
+	return r.call("delete-profile", name)
+}
+
+func (r *replicatorTestTarget) GetStoragePoolNames() ([]string, error) {
+	names := make([]string, 0, len(r.volumes))
+	for poolName := range r.volumes {
+		names = append(names, poolName)
+	}
+
+	return names, nil
+}
+
+func (r *replicatorTestTarget) GetStoragePoolVolumes(pool string) ([]api.StorageVolume, error) {
+	return r.volumes[pool], nil
+}
+
+func (r *replicatorTestTarget) DeleteStoragePoolVolume(pool string, volType string, name string) (lxd.Operation, error) {
+	return r.operation("delete-volume", pool+"/"+name)
+}
+
+func TestReplicatorSyncProjectObjects(t *testing.T) {
+	objects := &replicatorProjectObjects{
+		syncProfiles: true,
+		syncNetworks: true,
+		acls:         []api.NetworkACL{{Name: "acl1"}, {Name: "acl2"}},
+		networks: []api.Network{
+			{Name: "net1", Type: "ovn", Config: map[string]string{"ipv4.address": "auto", "volatile.network.ipv4.address": "10.0.0.1"}},
+			{Name: "net2", Type: "ovn", Config: map[string]string{}},
+		},
+		forwards: map[string][]api.NetworkForward{
+			"net1": {{ListenAddress: "192.0.2.1"}, {ListenAddress: "192.0.2.2"}},
+		},
+		profiles: []api.Profile{{Name: "default"}, {Name: "web"}},
+	}
+
+	target := &replicatorTestTarget{
+		acls:           []string{"acl2"},
+		networks:       []api.Network{{Name: "net1"}},
+		forwards:       map[string][]api.NetworkForward{"net1": {{ListenAddress: "192.0.2.2"}}},
+		profiles:       []string{"default"},
+		networkConfigs: map[string]map[string]string{},
+	}
+
+	err := replicatorSyncProjectObjects(objects, target)
+	require.NoError(t, err)
+
+	// Missing ACLs are created before any ACL rules are set, missing objects are created, existing ones updated,
+	// and volatile network keys are left out.
+	assert.Equal(t, []string{
+		"create-acl acl1",
+		"update-acl acl1",
+		"update-acl acl2",
+		"update-network net1",
+		"create-forward net1/192.0.2.1",
+		"update-forward net1/192.0.2.2",
+		"create-network net2",
+		"update-profile default",
+		"create-profile web",
+	}, target.calls)
+
+	assert.Equal(t, map[string]string{"ipv4.address": "auto"}, target.networkConfigs["net1"])
+}
+
+func TestReplicatorSyncProjectObjectsFeatures(t *testing.T) {
+	objects := &replicatorProjectObjects{
+		acls:     []api.NetworkACL{{Name: "acl1"}},
+		networks: []api.Network{{Name: "net1"}},
+		profiles: []api.Profile{{Name: "web"}},
+	}
+
+	// Objects of features that the project doesn't have are left alone.
+	target := &replicatorTestTarget{}
+	err := replicatorSyncProjectObjects(objects, target)
+	require.NoError(t, err)
+	assert.Empty(t, target.calls)
+}
+
+func TestReplicatorSyncProjectObjectsErrors(t *testing.T) {
+	objects := &replicatorProjectObjects{
+		syncProfiles: true,
+		syncNetworks: true,
+		networks:     []api.Network{{Name: "net1"}, {Name: "net2"}},
+		forwards: map[string][]api.NetworkForward{
+			"net1": {{ListenAddress: "192.0.2.1"}},
+		},
+		profiles: []api.Profile{{Name: "web"}, {Name: "db"}},
+	}
+
+	target := &replicatorTestTarget{fail: []string{"net1", "web"}, networkConfigs: map[string]map[string]string{}}
+
+	// A failure to sync an object doesn't prevent the others from being synced, and the forwards of a network
+	// that failed to sync are skipped.
+	err := replicatorSyncProjectObjects(objects, target)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `Failed syncing network "net1" to target`)
+	assert.ErrorContains(t, err, `Failed syncing profile "web" to target`)
+	assert.Equal(t, []string{
+		"create-network net1",
+		"create-network net2",
+		"create-profile web",
+		"create-profile db",
+	}, target.calls)
+}
+
+func TestReplicatorReconcileProjectObjects(t *testing.T) {
+	objects := &replicatorProjectObjects{
+		syncProfiles: true,
+		syncNetworks: true,
+		syncVolumes:  true,
+		acls:         []api.NetworkACL{{Name: "acl1"}},
+		networks:     []api.Network{{Name: "net1"}},
+		forwards: map[string][]api.NetworkForward{
+			"net1": {{ListenAddress: "192.0.2.1"}},
+		},
+		profiles: []api.Profile{{Name: "web"}},
+		volumes:  []*db.StorageVolume{{StorageVolume: api.StorageVolume{Name: "vol1", Pool: "pool1", Type: "custom"}}},
+	}
+
+	target := &replicatorTestTarget{
+		instances: []string{"c1", "c2"},
+		acls:      []string{"acl1", "acl2"},
+		networks:  []api.Network{{Name: "net1", Managed: true}, {Name: "net2", Managed: true}, {Name: "eth0"}},
+		forwards: map[string][]api.NetworkForward{
+			"net1": {{ListenAddress: "192.0.2.1"}, {ListenAddress: "192.0.2.2"}, {ListenAddress: "192.0.2.3", Location: "member1"}},
+		},
+		profiles: []string{"default", "web", "db"},
+		volumes: map[string][]api.StorageVolume{
+			"pool1": {
+				{Name: "vol1", Type: "custom"},
+				{Name: "vol1/snap0", Type: "custom"},
+				{Name: "vol2", Type: "custom", Location: "member1"},
+				{Name: "c2", Type: "container"},
+			},
+		},
+		fail: []string{"c2"},
+	}
+
+	// Only the items missing from the local project are deleted, in reverse dependency order, and a failure to
+	// delete one item doesn't prevent the others from being deleted.
+	err := replicatorReconcileProjectObjects("p1", objects, []string{"c1"}, target)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `Failed deleting instance "c2" from target`)
+	assert.Equal(t, []string{
+		"delete-instance c2",
+		"delete-volume pool1/vol2",
+		"delete-profile db",
+		"delete-forward net1/192.0.2.2",
+		"delete-network net2",
+		"delete-acl acl2",
+	}, target.calls)
+}
+
+func TestReplicatorReconcileProjectObjectsFeatures(t *testing.T) {
+	target := &replicatorTestTarget{
+		instances: []string{"c1"},
+		acls:      []string{"acl1"},
+		networks:  []api.Network{{Name: "net1", Managed: true}},
+		profiles:  []string{"default", "web"},
+		volumes:   map[string][]api.StorageVolume{"pool1": {{Name: "vol1", Type: "custom"}}},
+	}
+
+	// Objects of features that the project doesn't have belong to the default project and are kept.
+	err := replicatorReconcileProjectObjects("p1", &replicatorProjectObjects{}, nil, target)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"delete-instance c1"}, target.calls)
+}
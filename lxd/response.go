@@ -122,3 +122,22 @@ func forwardedResponseIfVolumeIsRemote(ctx context.Context, s *state.State) resp
 
 	return response.ForwardedResponse(client)
 }
+
+// forwardedResponseIfBucketIsRemote checks for the presence of the ctxStorageBucketDetails key in the request context.
+// If it is present, the forwardingNodeInfo field of the storageBucketDetails struct is used to forward the request to
+// the cluster member holding the bucket.
+func forwardedResponseIfBucketIsRemote(ctx context.Context, s *state.State) response.Response {
+	storageBucketDetails, err := request.GetContextValue[storageBucketDetails](ctx, ctxStorageBucketDetails)
+	if err != nil {
+		return nil
+	} else if storageBucketDetails.forwardingNodeInfo == nil {
+		return nil
+	}
+
+	client, err := cluster.Connect(ctx, storageBucketDetails.forwardingNodeInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.ForwardedResponse(client)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
@@ -47,6 +48,7 @@ import (
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/lxd/storage/block"
 	"github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/storage/encryption"
 	"github.com/canonical/lxd/lxd/storage/filesystem"
 	"github.com/canonical/lxd/lxd/storage/memorypipe"
 	"github.com/canonical/lxd/lxd/util"
@@ -62,6 +64,11 @@ import (
 var unavailablePools = make(map[string]struct{})
 var unavailablePoolsMu = sync.Mutex{}
 
+// overcommitReservations holds the space per pool allocated by volume creations and resizes that passed the
+// overcommit check and are still in progress, as the driver may not account for it yet.
+var overcommitReservations = make(map[string]int64)
+var overcommitReservationsMu = sync.Mutex{}
+
 // instanceDiskVolumeEffectiveFields fields from the instance disks that are applied to the volume's effective
 // config (but not stored in the disk's volume database record).
 var instanceDiskVolumeEffectiveFields = []string{
@@ -361,6 +368,10 @@ func (b *lxdBackend) GetNewVolume(volType drivers.VolumeType, contentType driver
 
 	// Set a new UUID.
 	newVol.Config()["volatile.uuid"] = uuid.New().String()
+
+	// A new volume is only a clone if the caller makes it one.
+	delete(newVol.Config(), "volatile.clone.origin")
+
 	return newVol
 }
 
@@ -378,6 +389,193 @@ func (b *lxdBackend) GetResources() (*api.ResourcesStoragePool, error) {
 	return b.driver.GetResources()
 }
 
+// checkOvercommit checks that creating the volume wouldn't allocate more space to the volumes of the pool
+// than allowed by the pool's "overcommit.ratio" setting.
+// The returned function must be called once the volume is created to release the space reserved for it.
+func (b *lxdBackend) checkOvercommit(vol drivers.Volume) (revert.Hook, error) {
+	if vol.IsSnapshot() {
+		return func() {}, nil
+	}
+
+	return b.checkOvercommitGrowth("", vol.ConfigSize())
+}
+
+// checkOvercommitGrowth checks that growing a volume from oldSize to newSize wouldn't allocate more space to
+// the volumes of the pool than allowed by the pool's "overcommit.ratio" setting.
+// An empty size means that the volume doesn't allocate any space up front.
+// The provisioned space is queried from the driver on every call, which can take a while on pools with many
+// volumes, so this is a noop unless the pool has an overcommit ratio.
+// The checks of a pool are serialized and the extra space is reserved until the returned function is called, so
+// that concurrent volume creations can't together exceed the ratio.
+func (b *lxdBackend) checkOvercommitGrowth(oldSize string, newSize string) (revert.Hook, error) {
+	if b.db.Config["overcommit.ratio"] == "" {
+		return func() {}, nil
+	}
+
+	ratio, err := strconv.ParseFloat(b.db.Config["overcommit.ratio"], 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid overcommit ratio: %w", err)
+	}
+
+	oldSizeBytes, err := units.ParseByteSizeString(oldSize)
+	if err != nil {
+		return nil, err
+	}
+
+	newSizeBytes, err := units.ParseByteSizeString(newSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the extra space allocated to the volume counts, and volumes without a size don't allocate any.
+	extraBytes := newSizeBytes - max(oldSizeBytes, 0)
+	if newSizeBytes <= 0 || extraBytes <= 0 {
+		return func() {}, nil
+	}
+
+	unlock, err := locking.Lock(context.TODO(), drivers.OperationLockName("CheckOvercommit", b.name, "", "", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	res, err := b.driver.GetResources()
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting storage pool resources: %w", err)
+	}
+
+	if res.Space.Total == 0 {
+		return func() {}, nil
+	}
+
+	overcommitReservationsMu.Lock()
+	provisioned := res.Space.Provisioned + uint64(overcommitReservations[b.name])
+	overcommitReservationsMu.Unlock()
+
+	maxProvisioned := uint64(float64(res.Space.Total) * ratio)
+	if provisioned+uint64(extraBytes) > maxProvisioned {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Allocating %s would exceed the overcommit ratio of storage pool %q (%s allocated out of %s allowed)", units.GetByteSizeStringIEC(extraBytes, 2), b.name, units.GetByteSizeStringIEC(int64(provisioned), 2), units.GetByteSizeStringIEC(int64(maxProvisioned), 2))
+	}
+
+	overcommitReservationsMu.Lock()
+	overcommitReservations[b.name] += extraBytes
+	overcommitReservationsMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			overcommitReservationsMu.Lock()
+			defer overcommitReservationsMu.Unlock()
+
+			overcommitReservations[b.name] -= extraBytes
+			if overcommitReservations[b.name] <= 0 {
+				delete(overcommitReservations, b.name)
+			}
+		})
+	}, nil
+}
+
+// CheckInstanceQuota checks that growing the instance's root volume from oldSize to the size of its root disk
+// device wouldn't allocate more space than allowed by the pool's "overcommit.ratio" setting.
+// An empty oldSize means that the root disk device didn't set a size.
+// The returned function must be called once the root volume is resized to release the space reserved for it.
+func (b *lxdBackend) CheckInstanceQuota(inst instance.Instance, oldSize string) (revert.Hook, error) {
+	if b.db.Config["overcommit.ratio"] == "" {
+		return func() {}, nil
+	}
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := InstanceContentType(inst)
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+
+	dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
+	if err != nil {
+		return nil, err
+	}
+
+	oldVol := b.GetVolume(volType, contentType, volStorageName, dbVol.Config)
+	if oldSize != "" {
+		oldVol.SetConfigSize(oldSize)
+	}
+
+	newVol := b.GetVolume(volType, contentType, volStorageName, dbVol.Config)
+	err = b.applyInstanceRootDiskOverrides(inst, &newVol)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.checkOvercommitGrowth(oldVol.ConfigSize(), newVol.ConfigSize())
+}
+
+// Scrub checks the integrity of the data stored in the pool.
+// Returns drivers.ErrCorrupted if corrupted data is found and drivers.ErrNotSupported if the driver can't scrub.
+func (b *lxdBackend) Scrub(progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(nil)
+	l.Debug("Scrub started")
+	defer l.Debug("Scrub finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	return b.driver.Scrub(progressReporter)
+}
+
+// VerifyVolume checks the integrity of a volume by reading all of its data.
+// Block volumes are hashed and their SHA-256 checksum is returned, filesystem volumes have all their files read.
+// Returns drivers.ErrCorrupted if the data can't be read back.
+func (b *lxdBackend) VerifyVolume(projectName string, volName string, volType drivers.VolumeType, progressReporter ioprogress.ProgressReporter) (string, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "volType": volType})
+	l.Debug("VerifyVolume started")
+	defer l.Debug("VerifyVolume finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return "", err
+	}
+
+	dbVol, err := VolumeDBGet(b, projectName, volName, volType)
+	if err != nil {
+		return "", err
+	}
+
+	// Get the volume name on storage.
+	var volStorageName string
+	if volType == drivers.VolumeTypeCustom {
+		volStorageName = project.StorageVolume(projectName, volName)
+	} else {
+		volStorageName = project.Instance(projectName, volName)
+	}
+
+	vol := b.GetVolume(volType, drivers.ContentType(dbVol.ContentType), volStorageName, dbVol.Config)
+
+	var checksum string
+	err = vol.MountTask(func(mountPath string, _ ioprogress.ProgressReporter) error {
+		if vol.ContentType() == drivers.ContentTypeFS {
+			return verifyVolumeFiles(mountPath, progressReporter)
+		}
+
+		diskPath, err := b.driver.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+
+		checksum, err = verifyVolumeDisk(diskPath, progressReporter)
+		return err
+	}, progressReporter)
+	if err != nil {
+		return "", fmt.Errorf("Failed verifying volume %q: %w", volName, err)
+	}
+
+	return checksum, nil
+}
+
 // IsUsed returns whether the storage pool is used by any volumes or profiles (excluding image volumes).
 func (b *lxdBackend) IsUsed() (bool, error) {
 	usedBy, err := UsedBy(context.TODO(), b.state, b, true, true, cluster.StoragePoolVolumeTypeNameImage)
@@ -790,6 +988,13 @@ func (b *lxdBackend) CreateInstance(inst instance.Instance, progressReporter iop
 		return err
 	}
 
+	releaseOvercommit, err := b.checkOvercommit(vol)
+	if err != nil {
+		return err
+	}
+
+	defer releaseOvercommit()
+
 	err = b.driver.CreateVolume(vol, nil, progressReporter)
 	if err != nil {
 		return err
@@ -811,6 +1016,40 @@ func (b *lxdBackend) CreateInstance(inst instance.Instance, progressReporter iop
 	return nil
 }
 
+// importEncryptionKey sets the key of an encrypted volume imported from a backup or received from a migration or
+// copy. The first config is the volume's and the others its snapshots'. Optimized transfers carry the volume in its
+// encrypted form and so require the original key, which must be unwrappable by this server. Otherwise the volume is
+// re-encrypted as it's received, so it gets a new key shared by the volume and its snapshots.
+func (b *lxdBackend) importEncryptionKey(optimized bool, volConfig map[string]string, snapConfigs ...map[string]string) error {
+	wrappedKey := volConfig["volatile.encryption.key"]
+	if wrappedKey == "" {
+		return nil
+	}
+
+	if optimized {
+		_, err := encryption.UnwrapKey(context.TODO(), wrappedKey)
+		if err != nil {
+			return fmt.Errorf("Encrypted volume can't be transferred in optimized mode as its key can't be unwrapped: %w", err)
+		}
+
+		return nil
+	}
+
+	newKey, err := encryption.NewKey(context.TODO(), b.state.GlobalConfig.StorageEncryptionKeyProvider(), b.state.ServerClustered)
+	if err != nil {
+		return fmt.Errorf("Failed generating encryption key: %w", err)
+	}
+
+	volConfig["volatile.encryption.key"] = newKey
+	for _, snapConfig := range snapConfigs {
+		if snapConfig["volatile.encryption.key"] != "" {
+			snapConfig["volatile.encryption.key"] = newKey
+		}
+	}
+
+	return nil
+}
+
 // CreateInstanceFromBackup restores a backup file onto the storage device. Because the backup file
 // is unpacked and restored onto the storage device before the instance is created in the database
 // it is necessary to return two functions; a post hook that can be run once the instance has been
@@ -829,6 +1068,9 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 	l.Debug("CreateInstanceFromBackup started")
 	defer l.Debug("CreateInstanceFromBackup finished")
 
+	// Incremental backups restore the snapshots stored in their parent backups too.
+	snapshots := srcBackup.RestoredSnapshots()
+
 	// Validate the names in the backup.yaml file as these could be malicious.
 	err := instancetype.ValidName(srcBackup.Name, false)
 	if err != nil {
@@ -840,7 +1082,7 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 		return nil, nil, err
 	}
 
-	for _, snapName := range srcBackup.Snapshots {
+	for _, snapName := range snapshots {
 		snapInstName := srcBackup.Name + shared.SnapshotDelimiter + snapName
 		err = instancetype.ValidName(snapInstName, true)
 		if err != nil {
@@ -898,6 +1140,18 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 
 	volumeConfig = rootVol.Config
 
+	snapConfigs := make([]map[string]string, 0, len(rootVol.Snapshots))
+	for _, volSnap := range rootVol.Snapshots {
+		if volSnap != nil {
+			snapConfigs = append(snapConfigs, volSnap.Config)
+		}
+	}
+
+	err = b.importEncryptionKey(*srcBackup.OptimizedStorage, volumeConfig, snapConfigs...)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Don't use GetNewVolume as the new volume' UUID got already set beforehand.
 	vol := b.GetVolume(volType, contentType, volStorageName, volumeConfig)
 
@@ -924,6 +1178,13 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 
 	volCopy := drivers.NewVolumeCopy(vol, sourceSnapshots...)
 
+	releaseOvercommit, err := b.checkOvercommit(vol)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer releaseOvercommit()
+
 	// Unpack the backup into the new storage volume(s).
 	volPostHook, revertHook, err := b.driver.CreateVolumeFromBackup(volCopy, srcBackup, srcData, progressReporter)
 	if err != nil {
@@ -943,7 +1204,7 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 		_ = b.removeInstanceSymlink(instanceType, srcBackup.Project, srcBackup.Name)
 	})
 
-	if len(srcBackup.Snapshots) > 0 {
+	if len(snapshots) > 0 {
 		err = b.ensureInstanceSnapshotSymlink(instanceType, srcBackup.Project, srcBackup.Name)
 		if err != nil {
 			return nil, nil, err
@@ -988,7 +1249,7 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 
 		postHookRevert.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, inst.Name(), volType) })
 
-		for i, backupFileSnap := range srcBackup.Snapshots {
+		for i, backupFileSnap := range snapshots {
 			var volumeSnapDescription string
 			var volumeSnapConfig map[string]string
 			var volumeSnapExpiryDate time.Time
@@ -1254,7 +1515,10 @@ func (b *lxdBackend) CreateInstanceFromCopy(ctx context.Context, inst instance.I
 
 	revert.Add(func() { _ = b.DeleteInstance(inst, progressReporter) })
 
-	if b.Name() == srcPool.Name() {
+	// Copies of encrypted volumes are encrypted with their own key so can't be created from the source's blocks.
+	encrypted := volumeCopyEncrypted(rootVol.Config, vol.Config())
+
+	if b.Name() == srcPool.Name() && !encrypted {
 		l.Debug("CreateInstanceFromCopy same-pool mode detected")
 
 		// Validate config and create database entry for new storage volume.
@@ -1312,6 +1576,13 @@ func (b *lxdBackend) CreateInstanceFromCopy(ctx context.Context, inst instance.I
 		volCopy := drivers.NewVolumeCopy(vol, targetSnapshots...)
 		srcVolCopy := drivers.NewVolumeCopy(srcVol, sourceSnapshots...)
 
+		releaseOvercommit, err := b.checkOvercommit(vol)
+		if err != nil {
+			return err
+		}
+
+		defer releaseOvercommit()
+
 		err = b.driver.CreateVolumeFromCopy(volCopy, srcVolCopy, allowInconsistent, progressReporter)
 		if err != nil {
 			return err
@@ -1324,7 +1595,12 @@ func (b *lxdBackend) CreateInstanceFromCopy(ctx context.Context, inst instance.I
 		// Negotiate the migration type to use.
 		offeredTypes := srcPool.MigrationTypes(contentType, false, snapshots)
 		offerHeader := migration.TypesToHeader(offeredTypes...)
-		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, false, snapshots))
+		ourTypes := b.MigrationTypes(contentType, false, snapshots)
+		if encrypted {
+			ourTypes = decryptedMigrationTypes(ourTypes)
+		}
+
+		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), ourTypes)
 		if err != nil {
 			return fmt.Errorf("Failed negotiating copy migration type: %w", err)
 		}
@@ -1543,7 +1819,10 @@ func (b *lxdBackend) RefreshCustomVolume(ctx context.Context, projectName, srcPr
 	srcVolStorageName := project.StorageVolume(srcProjectName, customVol.Name)
 	srcVol := srcPool.GetVolume(drivers.VolumeTypeCustom, contentType, srcVolStorageName, customVol.Config)
 
-	if srcPool == b {
+	// Encrypted volumes have their own key so can't be refreshed from the source's blocks.
+	encrypted := volumeCopyEncrypted(customVol.Config, dbVol.Config)
+
+	if srcPool == b && !encrypted {
 		l.Debug("RefreshCustomVolume same-pool mode detected")
 
 		// Only refresh the snapshots that the target needs.
@@ -1603,7 +1882,12 @@ func (b *lxdBackend) RefreshCustomVolume(ctx context.Context, projectName, srcPr
 		// Negotiate the migration type to use.
 		offeredTypes := srcPool.MigrationTypes(contentType, true, snapshots)
 		offerHeader := migration.TypesToHeader(offeredTypes...)
-		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, true, snapshots))
+		ourTypes := b.MigrationTypes(contentType, true, snapshots)
+		if encrypted {
+			ourTypes = decryptedMigrationTypes(ourTypes)
+		}
+
+		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), ourTypes)
 		if err != nil {
 			return fmt.Errorf("Failed negotiating copy migration type: %w", err)
 		}
@@ -1841,7 +2125,10 @@ func (b *lxdBackend) RefreshInstance(ctx context.Context, inst instance.Instance
 		}
 	}
 
-	if b.Name() == srcPool.Name() {
+	// Encrypted volumes have their own key so can't be refreshed from the source's blocks.
+	encrypted := volumeCopyEncrypted(rootVol.Config, vol.Config())
+
+	if b.Name() == srcPool.Name() && !encrypted {
 		l.Debug("RefreshInstance same-pool mode detected")
 
 		// Create database entries for new storage volume snapshots.
@@ -1902,7 +2189,12 @@ func (b *lxdBackend) RefreshInstance(ctx context.Context, inst instance.Instance
 		// Negotiate the migration type to use.
 		offeredTypes := srcPool.MigrationTypes(contentType, true, snapshots)
 		offerHeader := migration.TypesToHeader(offeredTypes...)
-		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, true, snapshots))
+		ourTypes := b.MigrationTypes(contentType, true, snapshots)
+		if encrypted {
+			ourTypes = decryptedMigrationTypes(ourTypes)
+		}
+
+		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), ourTypes)
 		if err != nil {
 			return fmt.Errorf("Failed negotiating copy migration type: %w", err)
 		}
@@ -2220,9 +2512,13 @@ func (b *lxdBackend) CreateInstanceFromImage(ctx context.Context, inst instance.
 	}
 
 	// Ensure the required image variant exists; nil means fall back to slow-unpack.
-	imgVol, err := b.EnsureImage(ctx, fingerprint, inst.Project().Name, inst, progressReporter)
-	if err != nil {
-		return err
+	// Encrypted volumes can't be cloned from the unencrypted image volumes so are always unpacked.
+	var imgVol *drivers.Volume
+	if !vol.IsEncrypted() {
+		imgVol, err = b.EnsureImage(ctx, fingerprint, inst.Project().Name, inst, progressReporter)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Clone from the cached image volume when one was prepared; otherwise
@@ -2239,6 +2535,13 @@ func (b *lxdBackend) CreateInstanceFromImage(ctx context.Context, inst instance.
 
 		vol.SetConfigSize(newVolSize)
 
+		releaseOvercommit, err := b.checkOvercommit(vol)
+		if err != nil {
+			return err
+		}
+
+		defer releaseOvercommit()
+
 		err = b.driver.CreateVolumeFromCopy(drivers.NewVolumeCopy(vol), drivers.NewVolumeCopy(*imgVol), false, progressReporter)
 		if errors.Is(err, drivers.ErrCannotBeShrunk) {
 			// Cached image is larger than the requested instance size and
@@ -2252,6 +2555,13 @@ func (b *lxdBackend) CreateInstanceFromImage(ctx context.Context, inst instance.
 			return err
 		}
 	} else {
+		releaseOvercommit, err := b.checkOvercommit(vol)
+		if err != nil {
+			return err
+		}
+
+		defer releaseOvercommit()
+
 		err = b.driver.CreateVolume(vol, &volFiller, progressReporter)
 		if err != nil {
 			return err
@@ -2356,6 +2666,22 @@ func (b *lxdBackend) CreateInstanceFromMigration(ctx context.Context, inst insta
 
 	isRemoteClusterMove := args.ClusterMoveSourceName != "" && b.driver.Info().Remote
 
+	if dbVol == nil && rootVol != nil {
+		snapConfigs := make([]map[string]string, 0, len(rootVol.Snapshots))
+		for _, volSnap := range rootVol.Snapshots {
+			if volSnap != nil {
+				snapConfigs = append(snapConfigs, volSnap.Config)
+			}
+		}
+
+		// Remote cluster moves keep the existing volume and so its key.
+		optimized := isRemoteClusterMove || (args.MigrationType.FSType != migration.MigrationFSType_RSYNC && args.MigrationType.FSType != migration.MigrationFSType_BLOCK_AND_RSYNC)
+		err = b.importEncryptionKey(optimized, volumeConfig, snapConfigs...)
+		if err != nil {
+			return err
+		}
+	}
+
 	volStorageName := project.Instance(inst.Project().Name, inst.Name())
 
 	var vol drivers.Volume
@@ -2483,7 +2809,7 @@ func (b *lxdBackend) CreateInstanceFromMigration(ctx context.Context, inst insta
 	// will still be able to accommodate it.
 	if args.VolumeSize > 0 && contentType == drivers.ContentTypeBlock {
 		l.Debug("Setting volume size from offer header", logger.Ctx{"size": args.VolumeSize})
-		args.Config["size"] = strconv.FormatInt(args.VolumeSize, 10)
+		args.Config["size"] = strconv.FormatInt(drivers.BlockDevSize(vol, args.VolumeSize), 10)
 	} else if args.Config["size"] != "" {
 		l.Debug("Using volume size from root disk config", logger.Ctx{"size": args.Config["size"]})
 	}
@@ -2566,6 +2892,15 @@ func (b *lxdBackend) CreateInstanceFromMigration(ctx context.Context, inst insta
 
 	volCopy := drivers.NewVolumeCopy(vol, targetSnapshots...)
 
+	if !args.Refresh && !isRemoteClusterMove {
+		releaseOvercommit, err := b.checkOvercommit(vol)
+		if err != nil {
+			return err
+		}
+
+		defer releaseOvercommit()
+	}
+
 	err = b.driver.CreateVolumeFromMigration(volCopy, conn, args, &preFiller, progressReporter)
 	if err != nil {
 		return err
@@ -2767,6 +3102,13 @@ func (b *lxdBackend) CreateInstanceFromConversion(inst instance.Instance, conn i
 		return fmt.Errorf("Volume size (%s) is less than source disk size (%s)", volSize, imgSize)
 	}
 
+	releaseOvercommit, err := b.checkOvercommit(vol)
+	if err != nil {
+		return err
+	}
+
+	defer releaseOvercommit()
+
 	err = b.driver.CreateVolume(vol, &volFiller, progressReporter)
 	if err != nil {
 		return err
@@ -3050,6 +3392,8 @@ var instanceVolumeConfigPolicy = api.ConfigKeyPolicy{
 		"size",
 		"size.state",
 		"block.filesystem",
+		"block.encryption",
+		"volatile.encryption.key",
 	},
 }
 
@@ -3057,6 +3401,9 @@ var instanceVolumeConfigPolicy = api.ConfigKeyPolicy{
 var customVolumeConfigPolicy = api.ConfigKeyPolicy{
 	Immutable: []string{
 		"block.filesystem",
+		"block.encryption",
+		"volatile.clone.origin",
+		"volatile.encryption.key",
 		"volatile.uuid",
 	},
 }
@@ -3106,6 +3453,8 @@ func (b *lxdBackend) UpdateInstance(ctx context.Context, inst instance.Instance,
 		return err
 	}
 
+	restoreHiddenVolumeConfig(newConfig, dbVol.Config)
+
 	// Apply config changes if there are any.
 	changedConfig, userOnly := b.detectChangedConfig(dbVol.Config, newConfig)
 	if len(changedConfig) != 0 {
@@ -3386,11 +3735,15 @@ func (b *lxdBackend) CleanupInstancePaths(inst instance.Instance, _ ioprogress.P
 }
 
 // BackupInstance creates an instance backup.
-func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, version uint32, progressReporter ioprogress.ProgressReporter) error {
-	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots})
+func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, parentSnapshot string, version uint32, progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots, "parentSnapshot": parentSnapshot})
 	l.Debug("BackupInstance started")
 	defer l.Debug("BackupInstance finished")
 
+	if parentSnapshot != "" && (!optimized || !snapshots || !b.driver.Info().IncrementalBackups) {
+		return errors.New("Incremental backups require optimized storage backups including snapshots on a storage driver supporting them")
+	}
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -3437,9 +3790,19 @@ func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancew
 		}
 	}
 
+	// Incremental backups only contain the snapshots taken after their parent snapshot.
+	if parentSnapshot != "" {
+		parentIndex := slices.Index(snapNames, parentSnapshot)
+		if parentIndex < 0 {
+			return api.StatusErrorf(http.StatusNotFound, "Parent snapshot %q not found", parentSnapshot)
+		}
+
+		snapNames = snapNames[parentIndex+1:]
+	}
+
 	volCopy := drivers.NewVolumeCopy(vol, sourceSnapshots...)
 
-	err = b.driver.BackupVolume(volCopy, inst.Project().Name, tarWriter, optimized, snapNames, progressReporter)
+	err = b.driver.BackupVolume(volCopy, inst.Project().Name, tarWriter, optimized, snapNames, parentSnapshot, progressReporter)
 	if err != nil {
 		return err
 	}
@@ -4436,6 +4799,8 @@ func (b *lxdBackend) updateVolumeDescriptionOnly(ctx context.Context, projectNam
 	}
 
 	if newConfig != nil {
+		restoreHiddenVolumeConfig(newConfig, curVol.Config)
+
 		changedConfig, _ := b.detectChangedConfig(curVol.Config, newConfig)
 		if len(changedConfig) != 0 {
 			return errors.New("Volume config is not editable")
@@ -4496,6 +4861,26 @@ func (b *lxdBackend) CreateBucket(projectName string, bucket api.StorageBucketsP
 		return errors.New("Storage pool does not support buckets")
 	}
 
+	// Buckets of local storage pools are addressed by name on the built-in S3 gateway of the member, so their
+	// names must be unique on the member.
+	if !b.driver.Info().Remote {
+		err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			_, err := tx.GetStoragePoolLocalBucket(ctx, bucket.Name)
+			if err == nil {
+				return api.StatusErrorf(http.StatusConflict, "A bucket for that name already exists on this member")
+			}
+
+			if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Validate config and create database entry for new storage bucket.
 	revert := revert.New()
 	defer revert.Fail()
@@ -4513,8 +4898,14 @@ func (b *lxdBackend) CreateBucket(projectName string, bucket api.StorageBucketsP
 
 	revert.Add(func() { _ = BucketDBDelete(context.TODO(), b, bucketID) })
 
-	// Handle per-driver implementation for remote storage drivers.
-	err = b.driver.CreateBucket(bucketVol)
+	if b.driver.Info().Remote {
+		// Handle per-driver implementation for remote storage drivers.
+		err = b.driver.CreateBucket(bucketVol)
+	} else {
+		// Buckets of local storage pools are stored in a custom volume served by the built-in S3 gateway.
+		err = b.CreateCustomVolume(context.TODO(), projectName, LocalBucketVolumeName(bucket.Name), "Storage bucket "+bucket.Name, localBucketVolumeConfig(bucket.Config), drivers.ContentTypeFS, nil)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -4587,8 +4978,13 @@ func (b *lxdBackend) UpdateBucket(projectName string, bucketName string, bucket
 
 	changedConfig, userOnly := b.detectChangedConfig(curBucket.Config, bucket.Config)
 	if len(changedConfig) > 0 && !userOnly {
-		// Handle per-driver implementation for remote storage drivers.
-		err = b.driver.UpdateBucket(curBucketVol, changedConfig)
+		if b.driver.Info().Remote {
+			// Handle per-driver implementation for remote storage drivers.
+			err = b.driver.UpdateBucket(curBucketVol, changedConfig)
+		} else {
+			err = b.updateLocalBucketVolume(projectName, curBucket.Name, changedConfig)
+		}
+
 		if err != nil {
 			return err
 		}
@@ -4632,8 +5028,17 @@ func (b *lxdBackend) DeleteBucket(projectName string, bucketName string) error {
 	bucketVolName := project.StorageVolume(projectName, bucket.Name)
 	bucketVol := b.GetVolume(drivers.VolumeTypeBucket, drivers.ContentTypeFS, bucketVolName, bucket.Config)
 
-	// Handle per-driver implementation for remote storage drivers.
-	err = b.driver.DeleteBucket(bucketVol)
+	if b.driver.Info().Remote {
+		// Handle per-driver implementation for remote storage drivers.
+		err = b.driver.DeleteBucket(bucketVol)
+	} else {
+		// Delete the custom volume holding the bucket, ignoring it if it's already gone.
+		err = b.DeleteCustomVolume(context.TODO(), projectName, LocalBucketVolumeName(bucket.Name), nil)
+		if response.IsNotFoundError(err) {
+			err = nil
+		}
+	}
+
 	if err != nil {
 		return err
 	}
@@ -4682,8 +5087,15 @@ func (b *lxdBackend) CreateBucketKey(projectName string, bucketName string, key
 		return nil, err
 	}
 
-	// Handle per-driver implementation for remote storage drivers.
-	newCreds, err := b.driver.CreateBucketKey(bucketVol, key.Name, creds, key.Role)
+	var newCreds *drivers.S3Credentials
+	if b.driver.Info().Remote {
+		// Handle per-driver implementation for remote storage drivers.
+		newCreds, err = b.driver.CreateBucketKey(bucketVol, key.Name, creds, key.Role)
+	} else {
+		// Keys of buckets of local storage pools are only used by the built-in S3 gateway.
+		newCreds, err = b.localBucketKeyCredentials(bucket.ID, -1, creds)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -4784,8 +5196,15 @@ func (b *lxdBackend) UpdateBucketKey(projectName string, bucketName string, keyN
 		return err
 	}
 
-	// Handle per-driver implementation for remote storage drivers.
-	newCreds, err := b.driver.UpdateBucketKey(bucketVol, keyName, creds, key.Role)
+	var newCreds *drivers.S3Credentials
+	if b.driver.Info().Remote {
+		// Handle per-driver implementation for remote storage drivers.
+		newCreds, err = b.driver.UpdateBucketKey(bucketVol, keyName, creds, key.Role)
+	} else {
+		// Keys of buckets of local storage pools are only used by the built-in S3 gateway.
+		newCreds, err = b.localBucketKeyCredentials(bucket.ID, curBucketKey.ID, creds)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -4869,10 +5288,111 @@ func (b *lxdBackend) GetBucketURL(bucketName string) *url.URL {
 		return nil
 	}
 
+	if !b.driver.Info().Remote {
+		// Buckets of local storage pools are served by the built-in S3 gateway of the member.
+		address := b.state.Endpoints.StorageBucketsAddress()
+		if address == "" {
+			return nil
+		}
+
+		// Use the first global address of the member when listening on all addresses.
+		if util.IsWildCardAddress(address) {
+			addresses, err := util.ListenAddresses(address)
+			if err != nil || len(addresses) == 0 {
+				return nil
+			}
+
+			address = addresses[0]
+		}
+
+		return &url.URL{Scheme: "https", Host: address, Path: "/" + bucketName}
+	}
+
 	// Handle per-driver implementation for remote storage drivers.
 	return b.driver.GetBucketURL(bucketName)
 }
 
+// updateLocalBucketVolume applies the changed config of a bucket of a local storage pool to its custom volume.
+func (b *lxdBackend) updateLocalBucketVolume(projectName string, bucketName string, changedConfig map[string]string) error {
+	volName := LocalBucketVolumeName(bucketName)
+
+	vol, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	newConfig := make(map[string]string, len(vol.Config))
+	maps.Copy(newConfig, vol.Config)
+
+	for k, v := range localBucketVolumeConfig(changedConfig) {
+		if v == "" {
+			delete(newConfig, k)
+		} else {
+			newConfig[k] = v
+		}
+	}
+
+	return b.UpdateCustomVolume(context.TODO(), projectName, volName, vol.Description, newConfig, nil)
+}
+
+// localBucketKeyCredentials returns the credentials of a key of a bucket of a local storage pool, generating the
+// missing access and secret keys. As the built-in S3 gateway identifies keys by their access key, it must not be
+// used by another key of the member. The keyID is the ID of the key being updated or -1 for a new key.
+func (b *lxdBackend) localBucketKeyCredentials(bucketID int64, keyID int64, creds drivers.S3Credentials) (*drivers.S3Credentials, error) {
+	if creds.AccessKey == "" {
+		accessKey, err := shared.RandomCryptoString()
+		if err != nil {
+			return nil, fmt.Errorf("Failed generating access key: %w", err)
+		}
+
+		creds.AccessKey = strings.ToUpper(accessKey[:20])
+	} else if strings.ContainsAny(creds.AccessKey, "/, \t\r\n") {
+		return nil, errors.New("Access key cannot contain slashes, commas or white space")
+	}
+
+	if creds.SecretKey == "" {
+		secretKey, err := shared.RandomCryptoString()
+		if err != nil {
+			return nil, fmt.Errorf("Failed generating secret key: %w", err)
+		}
+
+		creds.SecretKey = secretKey[:40]
+	}
+
+	err := b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		bucket, err := tx.GetStoragePoolLocalBucketByAccessKey(ctx, creds.AccessKey)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		if bucket.ID != bucketID {
+			return api.StatusErrorf(http.StatusConflict, "Access key is already in use")
+		}
+
+		keys, err := tx.GetStoragePoolBucketKeys(ctx, bucketID, db.StorageBucketKeyFilter{AccessKey: &creds.AccessKey})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if key.ID != keyID {
+				return api.StatusErrorf(http.StatusConflict, "Access key is already in use")
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &creds, nil
+}
+
 // CreateCustomVolume creates an empty custom volume.
 func (b *lxdBackend) CreateCustomVolume(ctx context.Context, projectName string, volName string, desc string, config map[string]string, contentType drivers.ContentType, progressReporter ioprogress.ProgressReporter) error {
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "desc": desc, "config": config, "contentType": contentType})
@@ -4905,6 +5425,13 @@ func (b *lxdBackend) CreateCustomVolume(ctx context.Context, projectName string,
 
 	revert.Add(func() { _ = VolumeDBDelete(b, projectName, volName, vol.Type()) })
 
+	releaseOvercommit, err := b.checkOvercommit(vol)
+	if err != nil {
+		return err
+	}
+
+	defer releaseOvercommit()
+
 	// Create the empty custom volume on the storage device.
 	err = b.driver.CreateVolume(vol, nil, progressReporter)
 	if err != nil {
@@ -5024,9 +5551,12 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(ctx context.Context, projectName
 	srcVolStorageName := project.StorageVolume(srcProjectName, customVol.Name)
 	srcVol := srcPool.GetVolume(drivers.VolumeTypeCustom, contentType, srcVolStorageName, customVol.Config)
 
+	// Copies of encrypted volumes are encrypted with their own key so can't be created from the source's blocks.
+	encrypted := volumeCopyEncrypted(customVol.Config, config)
+
 	// If the source and target are in the same pool then use CreateVolumeFromCopy rather than
 	// migration system as it will be quicker.
-	if srcPool == b {
+	if srcPool == b && !encrypted {
 		l.Debug("CreateCustomVolumeFromCopy same-pool mode detected")
 
 		// Get the volume name on storage.
@@ -5069,6 +5599,13 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(ctx context.Context, projectName
 		volCopy := drivers.NewVolumeCopy(vol, targetSnapshots...)
 		srcVolCopy := drivers.NewVolumeCopy(srcVol, sourceSnapshots...)
 
+		releaseOvercommit, err := b.checkOvercommit(vol)
+		if err != nil {
+			return err
+		}
+
+		defer releaseOvercommit()
+
 		err = b.driver.CreateVolumeFromCopy(volCopy, srcVolCopy, false, progressReporter)
 		if err != nil {
 			return err
@@ -5092,7 +5629,12 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(ctx context.Context, projectName
 	// Negotiate the migration type to use.
 	offeredTypes := srcPool.MigrationTypes(contentType, false, snapshots)
 	offerHeader := migration.TypesToHeader(offeredTypes...)
-	migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, false, snapshots))
+	ourTypes := b.MigrationTypes(contentType, false, snapshots)
+	if encrypted {
+		ourTypes = decryptedMigrationTypes(ourTypes)
+	}
+
+	migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), ourTypes)
 	if err != nil {
 		return fmt.Errorf("Failed negotiating copy migration type: %w", err)
 	}
@@ -5197,6 +5739,168 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(ctx context.Context, projectName
 	return nil
 }
 
+// CreateCustomVolumeFromClone creates a custom volume as a copy-on-write clone of an existing custom volume or
+// custom volume snapshot in the same pool. The source may be in another project. Snapshots are not cloned.
+// If the driver's clones depend on their origin, the origin's UUID is recorded in the clone's config so that the
+// origin can't be deleted while the clone exists.
+func (b *lxdBackend) CreateCustomVolumeFromClone(ctx context.Context, projectName, srcProjectName, volName, desc string, config map[string]string, srcVolName string, progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "srcProjectName": srcProjectName, "volName": volName, "desc": desc, "config": config, "srcVolName": srcVolName})
+	l.Debug("CreateCustomVolumeFromClone started")
+	defer l.Debug("CreateCustomVolumeFromClone finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	if !b.driver.Info().VolumeClones {
+		return api.StatusErrorf(http.StatusBadRequest, "Storage pool driver %q doesn't support volume clones", b.driver.Info().Name)
+	}
+
+	if srcProjectName == "" {
+		srcProjectName = projectName
+	}
+
+	// Check source volume exists and get its config.
+	srcDBVol, err := VolumeDBGet(b, srcProjectName, srcVolName, drivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	srcUUID := srcDBVol.Config["volatile.uuid"]
+	if srcUUID == "" {
+		return fmt.Errorf(`Storage volume %q is missing the required "volatile.uuid" setting`, srcVolName)
+	}
+
+	// Use the source volume's config if not supplied.
+	if config == nil {
+		config = srcDBVol.Config
+	}
+
+	// Clones share the blocks of their origin so can't be encrypted with their own key.
+	if volumeCopyEncrypted(srcDBVol.Config, config) {
+		return api.StatusErrorf(http.StatusBadRequest, "Encrypted storage volumes can't be cloned, copy them instead")
+	}
+
+	// Use the source volume's description if not supplied.
+	if desc == "" {
+		desc = srcDBVol.Description
+	}
+
+	contentDBType, err := cluster.StoragePoolVolumeContentTypeFromName(srcDBVol.ContentType)
+	if err != nil {
+		return err
+	}
+
+	contentType := VolumeDBContentTypeToContentType(contentDBType)
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	srcVol := b.GetVolume(drivers.VolumeTypeCustom, contentType, project.StorageVolume(srcProjectName, srcVolName), srcDBVol.Config)
+
+	vol := b.GetNewVolume(drivers.VolumeTypeCustom, contentType, project.StorageVolume(projectName, volName), config)
+	if b.driver.Info().VolumeClonesDependOnOrigin {
+		vol.Config()["volatile.clone.origin"] = srcUUID
+	}
+
+	// Validate config and create database entry for new storage volume.
+	err = VolumeDBCreate(b, projectName, volName, desc, vol.Type(), false, vol.Config(), time.Now().UTC(), time.Time{}, vol.ContentType(), false, true)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = VolumeDBDelete(b, projectName, volName, vol.Type()) })
+
+	releaseOvercommit, err := b.checkOvercommit(vol)
+	if err != nil {
+		return err
+	}
+
+	defer releaseOvercommit()
+
+	err = b.driver.CreateVolumeFromCopy(drivers.NewVolumeCopy(vol), drivers.NewVolumeCopy(srcVol), false, progressReporter)
+	if err != nil {
+		return err
+	}
+
+	eventCtx := logger.Ctx{"type": vol.Type()}
+	if !b.Driver().Info().Remote {
+		eventCtx["location"] = b.state.ServerName
+	}
+
+	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeCreated.Event(ctx, vol, string(vol.Type()), projectName, eventCtx))
+
+	revert.Success()
+	return nil
+}
+
+// checkCustomVolumeClones returns an error if other volumes depend on the given custom volume or snapshot because
+// they were cloned from it.
+func (b *lxdBackend) checkCustomVolumeClones(projectName string, volName string) error {
+	if !b.driver.Info().VolumeClonesDependOnOrigin {
+		return nil
+	}
+
+	clones, err := b.customVolumeClones(projectName, volName)
+	if err != nil {
+		return err
+	}
+
+	if len(clones) > 0 {
+		return api.StatusErrorf(http.StatusBadRequest, "Storage volume %q is the origin of clones that must be deleted first: %s", volName, strings.Join(clones, ", "))
+	}
+
+	return nil
+}
+
+// customVolumeClones returns the names of the custom volumes in the pool that were cloned from the given custom
+// volume or, unless the volume is a snapshot, from one of its snapshots. Names are in the form "<project>/<volume>".
+func (b *lxdBackend) customVolumeClones(projectName string, volName string) ([]string, error) {
+	var clones []string
+
+	err := b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		volType := cluster.StoragePoolVolumeTypeCustom
+		poolID := b.ID()
+		volumes, err := tx.GetStorageVolumes(ctx, true, db.StorageVolumeFilter{Type: &volType, PoolID: &poolID})
+		if err != nil {
+			return fmt.Errorf("Failed loading storage volumes: %w", err)
+		}
+
+		// Collect the UUIDs of the volume and its snapshots.
+		var uuids []string
+		for _, vol := range volumes {
+			if vol.Project != projectName {
+				continue
+			}
+
+			if vol.Name == volName || (!shared.IsSnapshot(volName) && strings.HasPrefix(vol.Name, volName+shared.SnapshotDelimiter)) {
+				uuids = append(uuids, vol.Config["volatile.uuid"])
+			}
+		}
+
+		for _, vol := range volumes {
+			origin := vol.Config["volatile.clone.origin"]
+
+			// Snapshots of a clone don't record the origin.
+			if origin == "" || shared.IsSnapshot(vol.Name) {
+				continue
+			}
+
+			if slices.Contains(uuids, origin) {
+				clones = append(clones, vol.Project+"/"+vol.Name)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clones, nil
+}
+
 // migrationIndexHeaderSend sends the migration index header to target and waits for confirmation of receipt.
 func (b *lxdBackend) migrationIndexHeaderSend(l logger.Logger, indexHeaderVersion uint32, conn io.ReadWriteCloser, info *migration.Info) (*migration.InfoResponse, error) {
 	infoResp := migration.InfoResponse{}
@@ -5442,7 +6146,7 @@ func (b *lxdBackend) CreateCustomVolumeFromMigration(ctx context.Context, projec
 	// The target should use this value if present, otherwise it might get an error like
 	// "no space left on device".
 	if args.VolumeSize > 0 {
-		vol.SetConfigSize(strconv.FormatInt(args.VolumeSize, 10))
+		vol.SetConfigSize(strconv.FormatInt(drivers.BlockDevSize(vol, args.VolumeSize), 10))
 	}
 
 	// Receive index header from source if applicable and respond confirming receipt.
@@ -5466,6 +6170,26 @@ func (b *lxdBackend) CreateCustomVolumeFromMigration(ctx context.Context, projec
 	defer revert.Fail()
 
 	if !args.Refresh {
+		var snapConfigs []map[string]string
+		if srcInfo != nil && srcInfo.Config != nil {
+			customVol, err := srcInfo.Config.CustomVolume()
+			if err != nil {
+				return fmt.Errorf("Failed getting the custom volume: %w", err)
+			}
+
+			for _, srcSnap := range customVol.Snapshots {
+				if srcSnap != nil {
+					snapConfigs = append(snapConfigs, srcSnap.Config)
+				}
+			}
+		}
+
+		optimized := args.MigrationType.FSType != migration.MigrationFSType_RSYNC && args.MigrationType.FSType != migration.MigrationFSType_BLOCK_AND_RSYNC
+		err = b.importEncryptionKey(optimized, vol.Config(), snapConfigs...)
+		if err != nil {
+			return err
+		}
+
 		// Validate config and create database entry for new storage volume.
 		// Strip unsupported config keys (in case the export was made from a different type of storage pool).
 		err = VolumeDBCreate(b, projectName, args.Name, args.Description, vol.Type(), false, vol.Config(), time.Now().UTC(), time.Time{}, vol.ContentType(), true, true)
@@ -5543,6 +6267,15 @@ func (b *lxdBackend) CreateCustomVolumeFromMigration(ctx context.Context, projec
 
 	volCopy := drivers.NewVolumeCopy(vol, targetSnapshots...)
 
+	if !args.Refresh {
+		releaseOvercommit, err := b.checkOvercommit(vol)
+		if err != nil {
+			return err
+		}
+
+		defer releaseOvercommit()
+	}
+
 	err = b.driver.CreateVolumeFromMigration(volCopy, conn, args, nil, progressReporter)
 	if err != nil {
 		return err
@@ -5757,6 +6490,8 @@ func (b *lxdBackend) UpdateCustomVolume(ctx context.Context, projectName string,
 		return err
 	}
 
+	restoreHiddenVolumeConfig(newConfig, curVol.Config)
+
 	// Get content type.
 	dbContentType, err := cluster.StoragePoolVolumeContentTypeFromName(curVol.ContentType)
 	if err != nil {
@@ -5814,6 +6549,17 @@ func (b *lxdBackend) UpdateCustomVolume(ctx context.Context, projectName string,
 			}
 		}
 
+		_, ok := changedConfig["size"]
+		if ok {
+			oldVol := b.GetVolume(drivers.VolumeTypeCustom, contentType, volStorageName, curVol.Config)
+			releaseOvercommit, err := b.checkOvercommitGrowth(oldVol.ConfigSize(), newVol.ConfigSize())
+			if err != nil {
+				return err
+			}
+
+			defer releaseOvercommit()
+		}
+
 		sharedVolume, ok := changedConfig["security.shared"]
 		if ok && shared.IsFalseOrEmpty(sharedVolume) && curVol.ContentType == cluster.StoragePoolVolumeContentTypeNameBlock {
 			err = allowRemoveSecurityShared(b.state, projectName, &curVol.StorageVolume)
@@ -5907,6 +6653,8 @@ func (b *lxdBackend) UpdateCustomVolumeSnapshot(ctx context.Context, projectName
 	}
 
 	if newConfig != nil {
+		restoreHiddenVolumeConfig(newConfig, curVol.Config)
+
 		changedConfig, _ := b.detectChangedConfig(curVol.Config, newConfig)
 		if len(changedConfig) != 0 {
 			return errors.New("Volume config is not editable")
@@ -5986,6 +6734,11 @@ func (b *lxdBackend) DeleteCustomVolume(ctx context.Context, projectName string,
 		return errors.New("Volume name cannot be a snapshot")
 	}
 
+	err := b.checkCustomVolumeClones(projectName, volName)
+	if err != nil {
+		return err
+	}
+
 	// Retrieve a list of snapshots.
 	snapshots, err := VolumeDBSnapshotsGet(b, projectName, volName, drivers.VolumeTypeCustom)
 	if err != nil {
@@ -6536,6 +7289,11 @@ func (b *lxdBackend) DeleteCustomVolumeSnapshot(ctx context.Context, projectName
 		return errors.New("Volume name must be a snapshot")
 	}
 
+	err := b.checkCustomVolumeClones(projectName, volName)
+	if err != nil {
+		return err
+	}
+
 	// Get the volume.
 	volume, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
 	if err != nil {
@@ -7929,7 +8687,7 @@ func (b *lxdBackend) BackupCustomVolume(projectName string, volName string, tarW
 
 	volCopy := drivers.NewVolumeCopy(vol, sourceSnapshots...)
 
-	err = b.driver.BackupVolume(volCopy, projectName, tarWriter, optimized, snapNames, progressReporter)
+	err = b.driver.BackupVolume(volCopy, projectName, tarWriter, optimized, snapNames, "", progressReporter)
 	if err != nil {
 		return err
 	}
@@ -8000,6 +8758,13 @@ func (b *lxdBackend) CreateCustomVolumeFromISO(ctx context.Context, projectName
 		Fill: b.isoFiller(srcData),
 	}
 
+	releaseOvercommit, err := b.checkOvercommit(vol)
+	if err != nil {
+		return err
+	}
+
+	defer releaseOvercommit()
+
 	// Unpack the ISO into the new storage volume(s).
 	err = b.driver.CreateVolume(vol, &volFiller, progressReporter)
 	if err != nil {
@@ -8069,6 +8834,13 @@ func (b *lxdBackend) CreateCustomVolumeFromTarball(ctx context.Context, projectN
 
 	revert.Add(func() { _ = VolumeDBDelete(b, projectName, volName, vol.Type()) })
 
+	releaseOvercommit, err := b.checkOvercommit(vol)
+	if err != nil {
+		return err
+	}
+
+	defer releaseOvercommit()
+
 	// Create new empty volume.
 	err = b.driver.CreateVolume(vol, nil, nil)
 	if err != nil {
@@ -8184,6 +8956,16 @@ func (b *lxdBackend) CreateCustomVolumeFromBackup(ctx context.Context, srcBackup
 	// Get the volume name on storage.
 	volStorageName := project.StorageVolume(srcBackup.Project, srcBackup.Name)
 
+	snapConfigs := make([]map[string]string, 0, len(customVol.Snapshots))
+	for _, snapshot := range customVol.Snapshots {
+		snapConfigs = append(snapConfigs, snapshot.Config)
+	}
+
+	err = b.importEncryptionKey(*srcBackup.OptimizedStorage, customVol.Config, snapConfigs...)
+	if err != nil {
+		return err
+	}
+
 	vol := b.GetNewVolume(drivers.VolumeTypeCustom, drivers.ContentType(customVol.ContentType), volStorageName, customVol.Config)
 
 	volExists, err := b.driver.HasVolume(vol)
@@ -8244,6 +9026,13 @@ func (b *lxdBackend) CreateCustomVolumeFromBackup(ctx context.Context, srcBackup
 
 	volCopy := drivers.NewVolumeCopy(vol, sourceSnapshots...)
 
+	releaseOvercommit, err := b.checkOvercommit(vol)
+	if err != nil {
+		return err
+	}
+
+	defer releaseOvercommit()
+
 	// Unpack the backup into the new storage volume(s).
 	volPostHook, revertHook, err := b.driver.CreateVolumeFromBackup(volCopy, srcBackup, srcData, progressReporter)
 	if err != nil {
@@ -90,6 +90,16 @@ func (b *mockBackend) GetResources() (*api.ResourcesStoragePool, error) {
 	return nil, nil
 }
 
+// Scrub ...
+func (b *mockBackend) Scrub(progressReporter ioprogress.ProgressReporter) error {
+	return nil
+}
+
+// VerifyVolume ...
+func (b *mockBackend) VerifyVolume(projectName string, volName string, volType drivers.VolumeType, progressReporter ioprogress.ProgressReporter) (string, error) {
+	return "", nil
+}
+
 // IsUsed ...
 func (b *mockBackend) IsUsed() (bool, error) {
 	return false, nil
@@ -236,7 +246,7 @@ func (b *mockBackend) RefreshInstance(ctx context.Context, inst instance.Instanc
 }
 
 // BackupInstance ...
-func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, version uint32, progressReporter ioprogress.ProgressReporter) error {
+func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, parentSnapshot string, version uint32, progressReporter ioprogress.ProgressReporter) error {
 	return nil
 }
 
@@ -245,6 +255,11 @@ func (b *mockBackend) GetInstanceUsage(inst instance.Instance) (*VolumeUsage, er
 	return nil, nil
 }
 
+// CheckInstanceQuota ...
+func (b *mockBackend) CheckInstanceQuota(inst instance.Instance, oldSize string) (revert.Hook, error) {
+	return func() {}, nil
+}
+
 // SetInstanceQuota ...
 func (b *mockBackend) SetInstanceQuota(inst instance.Instance, size string, vmStateSize string, progressReporter ioprogress.ProgressReporter) error {
 	return nil
@@ -355,6 +370,11 @@ func (b *mockBackend) CreateCustomVolumeFromCopy(ctx context.Context, projectNam
 	return nil
 }
 
+// CreateCustomVolumeFromClone ...
+func (b *mockBackend) CreateCustomVolumeFromClone(ctx context.Context, projectName, srcProjectName, volName, desc string, config map[string]string, srcVolName string, progressReporter ioprogress.ProgressReporter) error {
+	return nil
+}
+
 // RenameCustomVolume ...
 func (b *mockBackend) RenameCustomVolume(ctx context.Context, projectName string, volName string, newVolName string, progressReporter ioprogress.ProgressReporter) error {
 	return nil
@@ -544,7 +544,7 @@ func (d *alletra) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info,
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *alletra) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *alletra) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parentSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, progressReporter)
 }
 
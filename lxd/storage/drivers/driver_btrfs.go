@@ -98,6 +98,8 @@ func (d *btrfs) Info() Info {
 		DefaultVMBlockFilesystemSize: d.defaultVMBlockFilesystemSize(),
 		OptimizedImages:              true,
 		OptimizedBackups:             true,
+		IncrementalBackups:           true,
+		VolumeClones:                 true,
 		OptimizedBackupHeader:        true,
 		PreservesInodes:              !d.state.OS.RunningInUserNS,
 		Remote:                       d.isRemote(),
@@ -107,7 +109,7 @@ func (d *btrfs) Info() Info {
 		DirectIO:                     true,
 		IOUring:                      true,
 		MountedRoot:                  true,
-		Buckets:                      false,
+		Buckets:                      true,
 		PopulateParentVolumeUUID:     false,
 	}
 }
@@ -521,6 +523,22 @@ func (d *btrfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return genericVFSGetResources(d)
 }
 
+// Scrub scrubs the btrfs filesystem and reports any uncorrectable errors found.
+func (d *btrfs) Scrub(progressReporter ioprogress.ProgressReporter) error {
+	_, err := shared.RunCommand(context.TODO(), "btrfs", "scrub", "start", "-B", GetPoolMountPath(d.name))
+	if err != nil {
+		// btrfs scrub exits with status 3 when it finds uncorrectable errors.
+		status, _ := shared.ExitStatus(err)
+		if status == 3 {
+			return fmt.Errorf(`%w: Uncorrectable errors found, see "btrfs scrub status" for details`, ErrCorrupted)
+		}
+
+		return fmt.Errorf("Failed scrubbing btrfs filesystem: %w", err)
+	}
+
+	return nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *btrfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
 	var rsyncFeatures []string
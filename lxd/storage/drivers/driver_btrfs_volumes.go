@@ -168,10 +168,17 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 	revert := revert.New()
 	defer revert.Fail()
 
+	// Incremental backups are restored by receiving the volume data of their whole chain of backups.
+	chain := srcBackup.Chain(srcData)
+	var chainSnapshots []string
+	for _, link := range chain {
+		chainSnapshots = append(chainSnapshots, link.Info.Snapshots...)
+	}
+
 	// Define a revert function that will be used both to revert if an error occurs inside this
 	// function but also return it for use from the calling functions if no error internally.
 	revertHook := func() {
-		for _, snapName := range srcBackup.Snapshots {
+		for _, snapName := range chainSnapshots {
 			fullSnapshotName := GetSnapshotVolumeName(vol.name, snapName)
 			snapVol := NewVolume(d, d.name, vol.volType, vol.contentType, fullSnapshotName, vol.config, vol.poolConfig)
 			_ = d.DeleteVolumeSnapshot(snapVol, progressReporter)
@@ -183,43 +190,65 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 	// Only execute the revert function if we have had an error internally.
 	revert.Add(revertHook)
 
-	// Find the compression algorithm used for backup source data.
-	_, err = srcData.Seek(0, io.SeekStart)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	_, _, unpacker, err := shared.DetectCompressionFile(srcData)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Load optimized backup header file if specified.
-	var optimizedHeader *BTRFSMetaDataHeader
-	if *srcBackup.OptimizedHeader {
-		optimizedHeader, err = d.loadOptimizedBackupHeader(srcData, GetVolumeMountPath(d.name, vol.volType, ""))
-		if err != nil {
-			return nil, nil, err
+	// loadHeader loads the optimized header of a backup of the chain.
+	loadHeader := func(link backup.Link) (*BTRFSMetaDataHeader, error) {
+		// Load optimized backup header file if specified.
+		if *link.Info.OptimizedHeader {
+			return d.loadOptimizedBackupHeader(link.Data, GetVolumeMountPath(d.name, vol.volType, ""))
 		}
-	}
 
-	// Populate optimized header with pseudo data for unified handling when backup doesn't contain the
-	// optimized header file. This approach can only be used to restore root subvolumes (not sub-subvolumes).
-	if optimizedHeader == nil {
-		optimizedHeader = &BTRFSMetaDataHeader{}
-		for _, snapName := range srcBackup.Snapshots {
-			optimizedHeader.Subvolumes = append(optimizedHeader.Subvolumes, BTRFSSubVolume{
+		// Populate optimized header with pseudo data for unified handling when backup doesn't contain the
+		// optimized header file. This approach can only be used to restore root subvolumes (not sub-subvolumes).
+		header := &BTRFSMetaDataHeader{}
+		for _, snapName := range link.Info.Snapshots {
+			header.Subvolumes = append(header.Subvolumes, BTRFSSubVolume{
 				Snapshot: snapName,
 				Path:     string(filepath.Separator),
 				Readonly: true, // Snapshots are made readonly.
 			})
 		}
 
-		optimizedHeader.Subvolumes = append(optimizedHeader.Subvolumes, BTRFSSubVolume{
+		header.Subvolumes = append(header.Subvolumes, BTRFSSubVolume{
 			Snapshot: "",
 			Path:     string(filepath.Separator),
 			Readonly: false,
 		})
+
+		return header, nil
+	}
+
+	// Load the optimized headers of the backup chain.
+	headers := make([]*BTRFSMetaDataHeader, 0, len(chain))
+	unpackers := make([][]string, 0, len(chain))
+	for _, link := range chain {
+		// Find the compression algorithm used for backup source data.
+		_, err = link.Data.Seek(0, io.SeekStart)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, _, unpacker, err := shared.DetectCompressionFile(link.Data)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		header, err := loadHeader(link)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		headers = append(headers, header)
+		unpackers = append(unpackers, unpacker)
+	}
+
+	// Combine the snapshot subvolumes of the whole chain with the main volume subvolumes of the last backup.
+	optimizedHeader := &BTRFSMetaDataHeader{}
+	for i, header := range headers {
+		for _, subVol := range header.Subvolumes {
+			if subVol.Snapshot != "" || i == len(headers)-1 {
+				optimizedHeader.Subvolumes = append(optimizedHeader.Subvolumes, subVol)
+			}
+		}
 	}
 
 	// Create a temporary directory to unpack the backup into.
@@ -275,11 +304,11 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 
 	var copyOps []btrfsCopyOp
 
-	// unpackVolume unpacks all subvolumes in a LXD volume from a backup tarball file.
-	unpackVolume := func(v Volume, srcFilePrefix string) error {
+	// unpackVolume unpacks all subvolumes in a LXD volume from a backup tarball file of the chain.
+	unpackVolume := func(v Volume, linkIndex int, srcFilePrefix string) error {
 		_, snapName, _ := api.GetParentAndSnapshotName(v.name)
 
-		for _, subVol := range optimizedHeader.Subvolumes {
+		for _, subVol := range headers[linkIndex].Subvolumes {
 			if subVol.Snapshot != snapName {
 				continue // Skip any subvolumes that dont belong to our volume (empty for main).
 			}
@@ -305,7 +334,7 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 			d.Logger().Debug("Unpacking optimized volume", logger.Ctx{"name": v.name, "source": srcFilePath, "unpackPath": tmpUnpackDir, "path": subVolTargetPath})
 
 			// Unpack the volume into the temporary unpackDir.
-			unpackedSubVolPath, err := unpackSubVolume(srcData, unpacker, srcFilePath, tmpUnpackDir)
+			unpackedSubVolPath, err := unpackSubVolume(chain[linkIndex].Data, unpackers[linkIndex], srcFilePath, tmpUnpackDir)
 			if err != nil {
 				return err
 			}
@@ -319,15 +348,17 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 		return nil
 	}
 
-	if len(srcBackup.Snapshots) > 0 {
+	if len(chainSnapshots) > 0 {
 		// Create new snapshots directory.
 		err := createParentSnapshotDirIfMissing(d.name, vol.volType, vol.name)
 		if err != nil {
 			return nil, nil, err
 		}
+	}
 
-		// Restore backup snapshots from oldest to newest.
-		for _, snapName := range srcBackup.Snapshots {
+	// Restore backup snapshots from oldest to newest.
+	for linkIndex, link := range chain {
+		for _, snapName := range link.Info.Snapshots {
 			// Defend against path traversal attacks.
 			err := instancetype.ValidSnapName(snapName)
 			if err != nil {
@@ -349,7 +380,7 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 			}
 
 			srcFilePrefix = filepath.Join(snapDir, srcFilePrefix)
-			err = unpackVolume(snapVol, srcFilePrefix)
+			err = unpackVolume(snapVol, linkIndex, srcFilePrefix)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -370,7 +401,7 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 		srcFilePrefix = "volume"
 	}
 
-	err = unpackVolume(vol.Volume, srcFilePrefix)
+	err = unpackVolume(vol.Volume, len(chain)-1, srcFilePrefix)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -410,6 +441,20 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 		}
 	}
 
+	// Remove the snapshots that were deleted before the last backup of the chain.
+	restoredSnapshots := srcBackup.RestoredSnapshots()
+	for _, snapName := range chainSnapshots {
+		if slices.Contains(restoredSnapshots, snapName) {
+			continue
+		}
+
+		snapVol, _ := vol.NewSnapshot(snapName)
+		err = d.DeleteVolumeSnapshot(snapVol, progressReporter)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	revert.Success()
 	return nil, revertHook, nil
 }
@@ -1441,7 +1486,7 @@ func (d *btrfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volS
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *btrfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *btrfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parentSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -1614,6 +1659,13 @@ func (d *btrfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *inst
 
 	// Backup snapshots if populated.
 	lastVolPath := "" // Used as parent for differential exports.
+
+	// Incremental backups are sent from the parent snapshot onwards.
+	if parentSnapshot != "" {
+		parentVol, _ := vol.NewSnapshot(parentSnapshot)
+		lastVolPath = parentVol.MountPath()
+	}
+
 	for _, snapName := range snapshots {
 		snapVol, _ := vol.NewSnapshot(snapName)
 
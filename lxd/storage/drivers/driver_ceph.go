@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os/exec"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/canonical/lxd/lxd/migration"
 	"github.com/canonical/lxd/shared"
@@ -399,6 +401,8 @@ func (d *ceph) Validate(config map[string]string) error {
 		"volatile.pool.pristine": validate.IsAny,
 	}
 
+	maps.Insert(rules, maps.All(thinProvisioningRules()))
+
 	for configOption, configOptionValue := range config {
 		oldValue, ok := d.config[configOption]
 
@@ -528,9 +532,139 @@ func (d *ceph) GetResources() (*api.ResourcesStoragePool, error) {
 	res.Space.Total = spaceAvailable + spaceUsed
 	res.Space.Used = spaceUsed
 
+	res.Space.Provisioned, err = d.rbdProvisionedSize()
+	if err != nil {
+		return nil, err
+	}
+
 	return &res, nil
 }
 
+// cephScrubPollInterval is how often the deep scrub of an OSD pool is checked for completion.
+const cephScrubPollInterval = 10 * time.Second
+
+// cephScrubStallTimeout is how long the deep scrub of an OSD pool may go without any placement group being scrubbed
+// before it is reported as still pending.
+const cephScrubStallTimeout = 30 * time.Minute
+
+// Scrub deep scrubs the OSD pool and reports the inconsistent placement groups found.
+// Deep scrubs run in the background, so this waits for all placement groups of the pool to have been deep scrubbed.
+// If Ceph doesn't make progress with the deep scrub, such as when scrubbing is disabled, an error is returned
+// without checking for inconsistencies, as those could only be from previous scrubs.
+func (d *ceph) Scrub(progressReporter ioprogress.ProgressReporter) error {
+	poolName := d.config["ceph.osd.pool_name"]
+
+	startStamps, err := d.deepScrubStamps()
+	if err != nil {
+		return err
+	}
+
+	_, err = shared.RunCommand(
+		context.TODO(),
+		"ceph",
+		"--name", "client."+d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"osd",
+		"pool",
+		"deep-scrub",
+		poolName)
+	if err != nil {
+		return fmt.Errorf("Failed starting deep scrub of OSD pool %q: %w", poolName, err)
+	}
+
+	lastScrubbed := 0
+	lastProgress := time.Now()
+	for {
+		stamps, err := d.deepScrubStamps()
+		if err != nil {
+			return err
+		}
+
+		scrubbed := 0
+		for pgID, stamp := range stamps {
+			if stamp != startStamps[pgID] {
+				scrubbed++
+			}
+		}
+
+		if scrubbed == len(stamps) {
+			break
+		}
+
+		if scrubbed > lastScrubbed {
+			lastScrubbed = scrubbed
+			lastProgress = time.Now()
+
+			if progressReporter != nil {
+				handler := progressReporter.ProgressHandler("scrub")
+				handler(ioprogress.ProgressData{Text: fmt.Sprintf("Deep scrubbed %d of %d placement groups", scrubbed, len(stamps))})
+			}
+		} else if time.Since(lastProgress) > cephScrubStallTimeout {
+			return fmt.Errorf("Deep scrub of OSD pool %q still pending: %d of %d placement groups scrubbed", poolName, scrubbed, len(stamps))
+		}
+
+		time.Sleep(cephScrubPollInterval)
+	}
+
+	out, err := shared.RunCommand(
+		context.TODO(),
+		"rados",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"list-inconsistent-pg",
+		poolName)
+	if err != nil {
+		return fmt.Errorf("Failed listing inconsistent placement groups of OSD pool %q: %w", poolName, err)
+	}
+
+	var placementGroups []string
+	err = json.Unmarshal([]byte(out), &placementGroups)
+	if err != nil {
+		return fmt.Errorf("Failed parsing inconsistent placement groups: %w", err)
+	}
+
+	if len(placementGroups) > 0 {
+		return fmt.Errorf("%w in OSD pool %q: Inconsistent placement groups %s", ErrCorrupted, poolName, strings.Join(placementGroups, ", "))
+	}
+
+	return nil
+}
+
+// deepScrubStamps returns the time of the last deep scrub of each placement group of the OSD pool, keyed by ID.
+func (d *ceph) deepScrubStamps() (map[string]string, error) {
+	out, err := shared.RunCommand(
+		context.TODO(),
+		"ceph",
+		"--name", "client."+d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"pg",
+		"ls-by-pool",
+		d.config["ceph.osd.pool_name"],
+		"--format", "json")
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing placement groups of OSD pool %q: %w", d.config["ceph.osd.pool_name"], err)
+	}
+
+	var pgs struct {
+		PGStats []struct {
+			PGID               string `json:"pgid"`
+			LastDeepScrubStamp string `json:"last_deep_scrub_stamp"`
+		} `json:"pg_stats"`
+	}
+
+	err = json.Unmarshal([]byte(out), &pgs)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing placement groups: %w", err)
+	}
+
+	stamps := make(map[string]string, len(pgs.PGStats))
+	for _, pg := range pgs.PGStats {
+		stamps[pg.PGID] = pg.LastDeepScrubStamp
+	}
+
+	return stamps, nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *ceph) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
 	var rsyncFeatures []string
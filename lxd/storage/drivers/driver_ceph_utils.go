@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -148,6 +149,45 @@ func (d *ceph) rbdCreateVolume(vol Volume, size string) error {
 	return err
 }
 
+// rbdProvisionedSize returns the total size of the RBD images in the OSD pool.
+// Snapshots are not included as they don't allocate additional space up front.
+func (d *ceph) rbdProvisionedSize() (uint64, error) {
+	var stdout bytes.Buffer
+
+	err := shared.RunCommandWithFds(context.TODO(), nil, &stdout,
+		"rbd",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"--pool", d.config["ceph.osd.pool_name"],
+		"ls",
+		"--long",
+		"--format", "json")
+	if err != nil {
+		return 0, err
+	}
+
+	var images []struct {
+		Size     uint64 `json:"size"`
+		Snapshot string `json:"snapshot"`
+	}
+
+	err = json.NewDecoder(&stdout).Decode(&images)
+	if err != nil {
+		return 0, fmt.Errorf("Failed parsing RBD image list: %w", err)
+	}
+
+	var provisioned uint64
+	for _, image := range images {
+		if image.Snapshot != "" {
+			continue
+		}
+
+		provisioned += image.Size
+	}
+
+	return provisioned, nil
+}
+
 // rbdDeleteVolume deletes an RBD storage volume.
 //   - In case the RBD storage volume that is supposed to be deleted does not
 //     exist this command will still exit 0. This means that if the caller wants
@@ -200,13 +240,19 @@ func (d *ceph) rbdMapVolume(vol Volume) (string, error) {
 // rbdUnmapVolume unmaps a given RBD storage volume.
 // This is a precondition in order to delete an RBD storage volume can.
 func (d *ceph) rbdUnmapVolume(vol Volume, unmapUntilEINVAL bool) error {
+	// Close the decrypted mapping first as it holds the RBD device.
+	err := blockDevClose(vol)
+	if err != nil {
+		return err
+	}
+
 	busyCount := 0
 	rbdVol := d.getRBDVolumeName(vol, "", false, false)
 
 	ourDeactivate := false
 
 again:
-	_, err := shared.RunCommand(
+	_, err = shared.RunCommand(
 		context.TODO(),
 		"rbd",
 		"--id", d.config["ceph.user.name"],
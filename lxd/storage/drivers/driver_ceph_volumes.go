@@ -155,14 +155,9 @@ func (d *ceph) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter i
 
 	revert.Add(func() { _ = d.rbdUnmapVolume(vol, true) })
 
-	// Get filesystem.
-	RBDFilesystem := vol.ConfigBlockFilesystem()
-
-	if vol.contentType == ContentTypeFS {
-		_, err = makeFSType(devPath, RBDFilesystem, nil)
-		if err != nil {
-			return err
-		}
+	err = blockDevFormat(vol, devPath)
+	if err != nil {
+		return err
 	}
 
 	// For VMs, also create the filesystem volume.
@@ -1190,6 +1185,16 @@ func (d *ceph) commonVolumeRules() map[string]func(value string) error {
 		//  shortdesc: Mount options for block-backed file system volumes
 		//  scope: global
 		"block.mount_options": validate.IsAny,
+		// lxdmeta:generate(entities=storage-ceph,storage-lvm; group=volume-conf; key=block.encryption)
+		// The only supported value is `luks2`, which encrypts the block device of the volume with LUKS2.
+		// A random key is generated when the volume is created and stored in `volatile.encryption.key`, wrapped by the key provider set in {config:option}`server-miscellaneous:storage.encryption.key_provider`.
+		// ---
+		//  type: string
+		//  condition: block-based volume
+		//  defaultdesc: same as `volume.block.encryption`
+		//  shortdesc: Encryption of the block device of the storage volume
+		//  scope: global
+		"block.encryption": validate.Optional(validate.IsOneOf(blockEncryptionTypes...)),
 	}
 }
 
@@ -1347,6 +1352,11 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, p
 		return nil
 	}
 
+	err = blockDevCheckResize(vol, oldSizeBytes, sizeBytes)
+	if err != nil {
+		return err
+	}
+
 	inUse := vol.MountInUse()
 
 	// Resize filesystem if needed.
@@ -1406,13 +1416,9 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, p
 			return err
 		}
 
-		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as it is
-		// expected the caller will do all necessary post resize actions themselves).
-		if vol.IsVMBlock() && !allowUnsafeResize {
-			err = d.moveGPTAltHeader(devPath)
-			if err != nil {
-				return err
-			}
+		err = d.blockDevResized(vol, devPath, allowUnsafeResize)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -1423,7 +1429,11 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, p
 func (d *ceph) GetVolumeDiskPath(vol Volume) (string, error) {
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
 		_, devPath, err := d.getRBDMappedDevPath(vol, false)
-		return devPath, err
+		if err != nil {
+			return "", err
+		}
+
+		return blockDevPath(vol, devPath), nil
 	}
 
 	return "", ErrNotSupported
@@ -1559,6 +1569,16 @@ func (d *ceph) MountVolume(vol Volume, progressReporter ioprogress.ProgressRepor
 		revert.Add(func() { _ = d.rbdUnmapVolume(vol, true) })
 	}
 
+	// Open the decrypted mapping if needed.
+	volDevPath, opened, err := blockDevOpen(vol, volDevPath)
+	if err != nil {
+		return err
+	}
+
+	if opened {
+		revert.Add(func() { _ = blockDevClose(vol) })
+	}
+
 	switch vol.contentType {
 	case ContentTypeFS:
 		mountPath := vol.MountPath()
@@ -1879,7 +1899,7 @@ func (d *ceph) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcArgs
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *ceph) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *ceph) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parentSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, progressReporter)
 }
 
@@ -2030,7 +2050,7 @@ func (d *ceph) MountVolumeSnapshot(snapVol Volume, progressReporter ioprogress.P
 
 		// Clone snapshot.
 		cloneName := fmt.Sprintf("%s_%s_start_clone", parentName, snapshotOnlyName)
-		cloneVol := NewVolume(d, d.name, VolumeType("snapshots"), ContentTypeFS, cloneName, nil, nil)
+		cloneVol := NewVolume(d, d.name, VolumeType("snapshots"), ContentTypeFS, cloneName, snapVol.config, snapVol.poolConfig)
 
 		err = d.rbdCreateClone(parentVol, prefixedSnapOnlyName, cloneVol)
 		if err != nil {
@@ -2047,6 +2067,12 @@ func (d *ceph) MountVolumeSnapshot(snapVol Volume, progressReporter ioprogress.P
 
 		revert.Add(func() { _ = d.rbdUnmapVolume(cloneVol, true) })
 
+		// Open the decrypted mapping of the clone if needed.
+		rbdDevPath, _, err = blockDevOpen(cloneVol, rbdDevPath)
+		if err != nil {
+			return err
+		}
+
 		RBDFilesystem := snapVol.ConfigBlockFilesystem()
 		mountFlags, mountOptions := filesystem.ResolveMountOptions(strings.Split(snapVol.ConfigBlockMountOptions(), ","))
 		mountOptions = addNoRecoveryMountOption(mountOptions, RBDFilesystem)
@@ -2080,7 +2106,13 @@ func (d *ceph) MountVolumeSnapshot(snapVol Volume, progressReporter ioprogress.P
 		d.logger.Debug("Mounted RBD volume snapshot", logger.Ctx{"dev": rbdDevPath, "path": mountPath, "options": mountOptions})
 	} else if snapVol.contentType == ContentTypeBlock {
 		// Activate RBD volume if needed.
-		_, _, err := d.getRBDMappedDevPath(snapVol, true)
+		_, devPath, err := d.getRBDMappedDevPath(snapVol, true)
+		if err != nil {
+			return err
+		}
+
+		// Open the decrypted mapping if needed.
+		_, _, err = blockDevOpen(snapVol, devPath)
 		if err != nil {
 			return err
 		}
@@ -2128,7 +2160,7 @@ func (d *ceph) UnmountVolumeSnapshot(snapVol Volume, progressReporter ioprogress
 
 		parentName, snapshotOnlyName, _ := api.GetParentAndSnapshotName(snapVol.name)
 		cloneName := fmt.Sprintf("%s_%s_start_clone", parentName, snapshotOnlyName)
-		cloneVol := NewVolume(d, d.name, VolumeType("snapshots"), ContentTypeFS, cloneName, nil, nil)
+		cloneVol := NewVolume(d, d.name, VolumeType("snapshots"), ContentTypeFS, cloneName, snapVol.config, snapVol.poolConfig)
 
 		err = d.rbdUnmapVolume(cloneVol, true)
 		if err != nil {
@@ -18,12 +18,14 @@ import (
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/lxd/storage/block"
+	"github.com/canonical/lxd/lxd/storage/encryption"
 	"github.com/canonical/lxd/lxd/storage/filesystem"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/ioprogress"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/validate"
 )
 
 type common struct {
@@ -136,6 +138,17 @@ func (d *common) fillVolumeConfig(vol *Volume, excludedKeys ...string) error {
 			continue
 		}
 
+		// I/O limits are only relevant for volumes attached to instances.
+		if vol.volType != VolumeTypeCustom && !vol.volType.IsInstance() && (volKey == "limits.iops" || volKey == "limits.bandwidth") {
+			continue
+		}
+
+		// block.encryption isn't relevant for image volumes as they are shared by instances, and volumes created
+		// from a source keep the encryption of their source.
+		if (vol.volType == VolumeTypeImage || vol.hasSource) && volKey == "block.encryption" {
+			continue
+		}
+
 		if vol.config[volKey] == "" {
 			vol.config[volKey] = d.config[k]
 		}
@@ -162,6 +175,12 @@ func (d *common) validateVolume(vol Volume, driverRules map[string]func(value st
 	// Merge driver specific rules into common rules.
 	maps.Copy(rules, driverRules)
 
+	// Volumes that can be encrypted store their wrapped key.
+	_, ok := rules["block.encryption"]
+	if ok {
+		rules["volatile.encryption.key"] = validate.Optional(encryption.ValidateWrappedKey)
+	}
+
 	// Run the validator against each field.
 	for k, validator := range rules {
 		checkedFields[k] = struct{}{} // Mark field as checked.
@@ -367,6 +386,11 @@ func (d *common) runFiller(vol Volume, devPath string, filler *VolumeFiller, all
 	return nil
 }
 
+// Scrub checks the integrity of the data stored in the pool.
+func (d *common) Scrub(progressReporter ioprogress.ProgressReporter) error {
+	return ErrNotSupported
+}
+
 // CreateVolume creates a new storage volume on disk.
 func (d *common) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter ioprogress.ProgressReporter) error {
 	return ErrNotSupported
@@ -473,7 +497,7 @@ func (d *common) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcAr
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *common) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *common) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parentSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	return ErrNotSupported
 }
 
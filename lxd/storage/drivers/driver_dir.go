@@ -51,7 +51,7 @@ func (d *dir) Info() Info {
 		DirectIO:                     true,
 		IOUring:                      true,
 		MountedRoot:                  true,
-		Buckets:                      false,
+		Buckets:                      true,
 		PopulateParentVolumeUUID:     false,
 	}
 }
@@ -101,7 +101,7 @@ func (d *lvm) Info() Info {
 		DirectIO:                     true,
 		IOUring:                      true,
 		MountedRoot:                  false,
-		Buckets:                      false,
+		Buckets:                      true,
 		PopulateParentVolumeUUID:     false,
 	}
 }
@@ -588,10 +588,19 @@ func (d *lvm) Validate(config map[string]string) error {
 		//  shortdesc: Force using an existing non-empty volume group
 		//  scope: global
 		"lvm.vg.force_reuse": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=storage-lvm; group=pool-conf; key=lvm.thinpool_metadata_threshold)
+		// A warning is raised when the used space of the thin pool metadata volume reaches this percentage of its size.
+		// ---
+		//  type: integer
+		//  defaultdesc: `80`
+		//  shortdesc: Used thin pool metadata percentage that raises a warning
+		//  scope: global
+		"lvm.thinpool_metadata_threshold": validate.Optional(validate.IsInRange(1, 100)),
 	}
 
 	// Append common local pool rules.
 	maps.Insert(rules, maps.All(d.commonRules.LocalPoolRules()))
+	maps.Insert(rules, maps.All(thinProvisioningRules()))
 
 	err := d.validatePool(config, rules, d.commonVolumeRules())
 	if err != nil {
@@ -786,13 +795,22 @@ func (d *lvm) GetResources() (*api.ResourcesStoragePool, error) {
 	// used space using the thinpool logical volume allocated (data and meta) percentages.
 	if d.usesThinpool() {
 		volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], "", "", d.thinpoolName())
-		totalSize, usedSize, err := d.thinPoolVolumeUsage(volDevPath)
+		dataSize, dataUsed, metadataSize, metadataUsed, err := d.thinPoolDataMetadataUsage(volDevPath)
 		if err != nil {
 			return nil, err
 		}
 
-		res.Space.Total = totalSize
-		res.Space.Used = usedSize
+		res.Space.Total = dataSize + metadataSize
+		res.Space.Used = dataUsed + metadataUsed
+		res.Metadata = &api.ResourcesStoragePoolSpace{
+			Total: metadataSize,
+			Used:  metadataUsed,
+		}
+
+		res.Space.Provisioned, err = d.thinPoolProvisionedSize(d.config["lvm.vg_name"], d.thinpoolName())
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		// If thinpools are not in use, calculate used space in volume group.
 		args := []string{
@@ -827,6 +845,9 @@ func (d *lvm) GetResources() (*api.ResourcesStoragePool, error) {
 		}
 
 		res.Space.Used = total - free
+
+		// Thick logical volumes are fully allocated when created.
+		res.Space.Provisioned = res.Space.Used
 	}
 
 	return &res, nil
@@ -346,14 +346,16 @@ func (d *lvm) createLogicalVolume(vgName, thinPoolName string, vol Volume, makeT
 
 	volDevPath := d.lvmDevPath(vgName, vol.volType, vol.contentType, vol.name)
 
-	if vol.contentType == ContentTypeFS {
-		_, err = makeFSType(volDevPath, vol.ConfigBlockFilesystem(), nil)
-		if err != nil {
-			return fmt.Errorf("Error making filesystem on LVM logical volume: %w", err)
-		}
-	} else if !d.usesThinpool() {
+	err = blockDevFormat(vol, volDevPath)
+	if err != nil {
+		return fmt.Errorf("Error formatting LVM logical volume: %w", err)
+	}
+
+	if vol.contentType != ContentTypeFS && !d.usesThinpool() {
 		// Make sure we get an empty LV.
-		err := block.ClearBlock(volDevPath, 0)
+		err := blockDevTask(vol, volDevPath, func(devPath string) error {
+			return block.ClearBlock(devPath, 0)
+		})
 		if err != nil {
 			return fmt.Errorf("Error clearing LVM logical volume: %w", err)
 		}
@@ -657,6 +659,17 @@ func (d *lvm) logicalVolumeSize(volDevPath string) (int64, error) {
 }
 
 func (d *lvm) thinPoolVolumeUsage(volDevPath string) (totalSize uint64, usedSize uint64, err error) {
+	dataSize, dataUsed, metadataSize, metadataUsed, err := d.thinPoolDataMetadataUsage(volDevPath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return dataSize + metadataSize, dataUsed + metadataUsed, nil
+}
+
+// thinPoolDataMetadataUsage returns the size and usage of the data and metadata of a thin volume.
+// The metadata size and usage are only available for the thin pool volume itself.
+func (d *lvm) thinPoolDataMetadataUsage(volDevPath string) (dataSize uint64, dataUsed uint64, metadataSize uint64, metadataUsed uint64, err error) {
 	args := []string{
 		volDevPath,
 		"--noheadings",
@@ -668,27 +681,27 @@ func (d *lvm) thinPoolVolumeUsage(volDevPath string) (totalSize uint64, usedSize
 
 	out, err := shared.RunCommand(context.TODO(), "lvs", args...)
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, 0, 0, err
 	}
 
 	parts := shared.SplitNTrimSpace(out, ",", -1, true)
 	if len(parts) < 4 {
-		return 0, 0, errors.New("Unexpected output from lvs command")
+		return 0, 0, 0, 0, errors.New("Unexpected output from lvs command")
 	}
 
-	dataSize, err := strconv.ParseUint(parts[0], 10, 64)
+	dataSize, err = strconv.ParseUint(parts[0], 10, 64)
 	if err != nil {
-		return 0, 0, fmt.Errorf("Failed parsing thin volume total size (%q): %w", parts[0], err)
+		return 0, 0, 0, 0, fmt.Errorf("Failed parsing thin volume total size (%q): %w", parts[0], err)
 	}
 
 	// Used percentage is not available if thin volume isn't activated.
 	if parts[1] == "" {
-		return 0, 0, ErrNotSupported
+		return 0, 0, 0, 0, ErrNotSupported
 	}
 
 	dataPerc, err := strconv.ParseFloat(parts[1], 64)
 	if err != nil {
-		return 0, 0, fmt.Errorf("Failed parsing thin volume used percentage (%q): %w", parts[1], err)
+		return 0, 0, 0, 0, fmt.Errorf("Failed parsing thin volume used percentage (%q): %w", parts[1], err)
 	}
 
 	metaPerc := float64(0)
@@ -697,24 +710,65 @@ func (d *lvm) thinPoolVolumeUsage(volDevPath string) (totalSize uint64, usedSize
 	if parts[2] != "" {
 		metaPerc, err = strconv.ParseFloat(parts[2], 64)
 		if err != nil {
-			return 0, 0, fmt.Errorf("Failed parsing thin pool metadata used percentage (%q): %w", parts[2], err)
+			return 0, 0, 0, 0, fmt.Errorf("Failed parsing thin pool metadata used percentage (%q): %w", parts[2], err)
 		}
 	}
 
-	metadataSize := uint64(0)
-
 	// For thin volumes there is no metadata size. This is only for the thin pool volume itself.
 	if parts[3] != "" {
 		metadataSize, err = strconv.ParseUint(parts[3], 10, 64)
 		if err != nil {
-			return 0, 0, fmt.Errorf("Failed parsing thin pool metadata size (%q): %w", parts[3], err)
+			return 0, 0, 0, 0, fmt.Errorf("Failed parsing thin pool metadata size (%q): %w", parts[3], err)
 		}
 	}
 
-	totalSize = dataSize + metadataSize
-	usedSize = uint64(float64(dataSize)*dataPerc/100) + uint64(float64(metadataSize)*metaPerc/100)
+	dataUsed = uint64(float64(dataSize) * dataPerc / 100)
+	metadataUsed = uint64(float64(metadataSize) * metaPerc / 100)
+
+	return dataSize, dataUsed, metadataSize, metadataUsed, nil
+}
+
+// thinPoolProvisionedSize returns the total size of the thin volumes allocated from the thin pool.
+// Snapshots are left out as they share their blocks with their volume, like on ZFS.
+func (d *lvm) thinPoolProvisionedSize(vgName string, thinPoolName string) (uint64, error) {
+	args := []string{
+		vgName,
+		"--noheadings",
+		"--units", "b",
+		"--nosuffix",
+		"--separator", ",",
+		"--select", "pool_lv=" + thinPoolName,
+		"-o", "lv_name,lv_size",
+	}
+
+	out, err := shared.RunCommand(context.TODO(), "lvs", args...)
+	if err != nil {
+		return 0, err
+	}
+
+	var provisioned uint64
+	for line := range strings.SplitSeq(out, "\n") {
+		lvName, sizeStr, found := strings.Cut(strings.TrimSpace(line), ",")
+		if !found {
+			continue
+		}
+
+		// Volume names have their hyphens escaped, so a lone lvmSnapshotSeparator after the volume type
+		// prefix means that the logical volume is a snapshot.
+		_, volName, _ := strings.Cut(lvName, "_")
+		if strings.Count(volName, lvmSnapshotSeparator)%2 != 0 {
+			continue
+		}
+
+		size, err := strconv.ParseUint(sizeStr, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("Failed parsing thin volume size (%q): %w", sizeStr, err)
+		}
+
+		provisioned += size
+	}
 
-	return totalSize, usedSize, nil
+	return provisioned, nil
 }
 
 // parseLogicalVolumeSnapshot parses a raw logical volume name (from lvs command) and checks whether it is a
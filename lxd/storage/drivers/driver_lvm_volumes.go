@@ -217,6 +217,12 @@ func (d *lvm) DeleteVolume(vol Volume, progressReporter ioprogress.ProgressRepor
 			}
 		}
 
+		// Close the decrypted mapping of encrypted volumes if left open.
+		err = blockDevClose(vol)
+		if err != nil {
+			return err
+		}
+
 		err = d.removeLogicalVolume(d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name))
 		if err != nil {
 			return fmt.Errorf("Error removing LVM logical volume: %w", err)
@@ -315,6 +321,7 @@ func (d *lvm) FillVolumeConfig(vol Volume) error {
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *lvm) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
+		"block.encryption":    validate.Optional(validate.IsOneOf(blockEncryptionTypes...)),
 		"block.mount_options": validate.IsAny,
 		"block.filesystem":    validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
 		// lxdmeta:generate(entities=storage-lvm; group=volume-conf; key=lvm.stripes)
@@ -541,6 +548,11 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 			l.Debug("Logical volume filesystem grown")
 		}
 	} else {
+		err = blockDevCheckResize(vol, oldSizeBytes, sizeBytes)
+		if err != nil {
+			return err
+		}
+
 		// Only perform pre-resize checks if we are not in "unsafe" mode.
 		// In unsafe mode we expect the caller to know what they are doing and understand the risks.
 		if !allowUnsafeResize {
@@ -558,8 +570,14 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 			return err
 		}
 
+		err = blockDevGrow(vol)
+		if err != nil {
+			return err
+		}
+
 		// The new blocks in a grown volume will need clearing if using a thick pool.
-		needsClearing := !d.usesThinpool() && (oldSizeBytes < sizeBytes)
+		// This isn't needed for encrypted volumes as previous content of the blocks can't be decrypted.
+		needsClearing := !d.usesThinpool() && (oldSizeBytes < sizeBytes) && !vol.IsEncrypted()
 
 		// VM block volumes need the GPT header moved on normal resize scenarios.
 		needsGPTHeaderMove := vol.IsVMBlock() && !allowUnsafeResize
@@ -594,7 +612,7 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 		// expected the caller will do all necessary post resize actions themselves).
 		// Do this after the new blocks have been cleared.
 		if needsGPTHeaderMove {
-			err = d.moveGPTAltHeader(volDevPath)
+			err = blockDevTask(vol, volDevPath, d.moveGPTAltHeader)
 			if err != nil {
 				return err
 			}
@@ -608,7 +626,7 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 func (d *lvm) GetVolumeDiskPath(vol Volume) (string, error) {
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
 		volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
-		return volDevPath, nil
+		return blockDevPath(vol, volDevPath), nil
 	}
 
 	return "", ErrNotSupported
@@ -797,7 +815,9 @@ func (d *lvm) mountCommon(vol Volume, progressReporter ioprogress.ProgressReport
 				}
 			} else {
 				d.logger.Debug("Regenerating filesystem UUID", logger.Ctx{"dev": volDevPath, "fs": tmpVolFsType})
-				err = regenerateFilesystemUUID(mountVol.ConfigBlockFilesystem(), volDevPath)
+				err = blockDevTask(mountVol, volDevPath, func(devPath string) error {
+					return regenerateFilesystemUUID(mountVol.ConfigBlockFilesystem(), devPath)
+				})
 				if err != nil {
 					return err
 				}
@@ -810,8 +830,18 @@ func (d *lvm) mountCommon(vol Volume, progressReporter ioprogress.ProgressReport
 			return err
 		}
 
+		// Open the decrypted mapping of encrypted volumes.
+		mountDevPath, opened, err := blockDevOpen(mountVol, volDevPath)
+		if err != nil {
+			return err
+		}
+
+		if opened {
+			revert.Add(func() { _ = blockDevClose(mountVol) })
+		}
+
 		// Finally attempt to mount the volume that needs mounting.
-		err = TryMount(context.TODO(), volDevPath, mountPath, mountVol.ConfigBlockFilesystem(), mountFlags, mountOptions)
+		err = TryMount(context.TODO(), mountDevPath, mountPath, mountVol.ConfigBlockFilesystem(), mountFlags, mountOptions)
 		if err != nil {
 			return fmt.Errorf("Failed mounting LVM snapshot volume: %w", err)
 		}
@@ -829,6 +859,18 @@ func (d *lvm) mountCommon(vol Volume, progressReporter ioprogress.ProgressReport
 		revert.Add(func() { _, _ = d.deactivateVolume(vol) })
 	}
 
+	// Open the decrypted mapping of encrypted block volumes.
+	if vol.contentType == ContentTypeBlock {
+		_, opened, err := blockDevOpen(vol, d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name))
+		if err != nil {
+			return err
+		}
+
+		if opened {
+			revert.Add(func() { _ = blockDevClose(vol) })
+		}
+	}
+
 	if vol.IsVMBlock() {
 		// For VMs, mount the filesystem volume.
 		fsVol := vol.NewVMBlockFilesystemVolume()
@@ -886,16 +928,34 @@ func (d *lvm) unmountCommon(vol Volume, keepBlockDev bool, progressReporter iopr
 
 	// Check if already mounted.
 	if vol.contentType == ContentTypeFS && filesystem.IsMountPoint(mountPath) {
+		err = TryUnmount(mountPath, 0)
+		if err != nil {
+			return false, fmt.Errorf("Failed unmounting LVM logical volume: %w", err)
+		}
+
+		d.logger.Debug("Unmounted logical volume", logger.Ctx{"volName": vol.name, "path": mountPath, "keepBlockDev": keepBlockDev})
+
+		// Close the decrypted mapping of encrypted volumes.
+		err = blockDevClose(vol)
+		if err != nil {
+			return false, err
+		}
+
 		if vol.IsSnapshot() {
 			// Check if a temporary snapshot exists, and if so remove it.
-			tmpVolName := vol.name + tmpVolSuffix
-			tmpVolDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, tmpVolName)
+			tmpVol := NewVolume(d, d.name, vol.volType, vol.contentType, vol.name+tmpVolSuffix, vol.config, vol.poolConfig)
+			tmpVolDevPath := d.lvmDevPath(d.config["lvm.vg_name"], tmpVol.volType, tmpVol.contentType, tmpVol.name)
 			exists, err := d.logicalVolumeExists(tmpVolDevPath)
 			if err != nil {
 				return true, fmt.Errorf("Failed checking existence of temporary LVM snapshot volume %q: %w", tmpVolDevPath, err)
 			}
 
 			if exists {
+				err = blockDevClose(tmpVol)
+				if err != nil {
+					return true, err
+				}
+
 				err = d.removeLogicalVolume(tmpVolDevPath)
 				if err != nil {
 					return true, fmt.Errorf("Failed removing temporary LVM snapshot volume %q: %w", tmpVolDevPath, err)
@@ -903,17 +963,18 @@ func (d *lvm) unmountCommon(vol Volume, keepBlockDev bool, progressReporter iopr
 			}
 		}
 
-		err = TryUnmount(mountPath, 0)
-		if err != nil {
-			return false, fmt.Errorf("Failed unmounting LVM logical volume: %w", err)
-		}
-
-		d.logger.Debug("Unmounted logical volume", logger.Ctx{"volName": vol.name, "path": mountPath, "keepBlockDev": keepBlockDev})
-
 		ourUnmount = true
 	} else if IsContentBlock(vol.contentType) {
 		volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
 		keepBlockDev = keepBlockDev || !shared.PathExists(volDevPath)
+
+		// Close the decrypted mapping of encrypted block volumes unless the block device is kept.
+		if !keepBlockDev {
+			err = blockDevClose(vol)
+			if err != nil {
+				return false, err
+			}
+		}
 	}
 
 	// We only deactivate filesystem volumes if an unmount was needed to better align with our
@@ -1024,7 +1085,7 @@ func (d *lvm) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcArgs
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *lvm) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, _ bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *lvm) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, _ bool, snapshots []string, parentSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, progressReporter)
 }
 
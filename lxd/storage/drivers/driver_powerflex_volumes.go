@@ -79,8 +79,7 @@ func (d *powerflex) CreateVolume(vol Volume, filler *VolumeFiller, progressRepor
 	// This removes the given volume only without any parent(s) or child(s).
 	revert.Add(func() { _ = client.deleteVolume(id, "ONLY_ME") })
 
-	volumeFilesystem := vol.ConfigBlockFilesystem()
-	if vol.contentType == ContentTypeFS {
+	if blockDevNeedsFormat(vol) {
 		devPath, cleanup, err := d.getMappedDevPath(vol, true)
 		if err != nil {
 			return err
@@ -88,7 +87,7 @@ func (d *powerflex) CreateVolume(vol Volume, filler *VolumeFiller, progressRepor
 
 		revert.Add(cleanup)
 
-		_, err = makeFSType(devPath, volumeFilesystem, nil)
+		err = blockDevFormat(vol, devPath)
 		if err != nil {
 			return err
 		}
@@ -833,6 +832,16 @@ func (d *powerflex) FillVolumeConfig(vol Volume) error {
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *powerflex) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-powerflex; group=volume-conf; key=block.encryption)
+		// The only supported value is `luks2`, which encrypts the block device of the volume with LUKS2.
+		// A random key is generated when the volume is created and stored in `volatile.encryption.key`, wrapped by the key provider set in {config:option}`server-miscellaneous:storage.encryption.key_provider`.
+		// ---
+		//  type: string
+		//  condition: block-based volume
+		//  defaultdesc: same as `volume.block.encryption`
+		//  shortdesc: Encryption of the block device of the storage volume
+		//  scope: global
+		"block.encryption": validate.Optional(validate.IsOneOf(blockEncryptionTypes...)),
 		// lxdmeta:generate(entities=storage-powerflex; group=volume-conf; key=block.filesystem)
 		// Valid options: `btrfs`, `ext4`, `xfs`
 		// If not set, `ext4` is assumed.
@@ -1054,13 +1063,9 @@ func (d *powerflex) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bo
 			return fmt.Errorf("Failed waiting for volume %q to change its size: %w", vol.name, err)
 		}
 
-		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as it is
-		// expected the caller will do all necessary post resize actions themselves).
-		if vol.IsVMBlock() && !allowUnsafeResize {
-			err = d.moveGPTAltHeader(devPath)
-			if err != nil {
-				return err
-			}
+		err = d.blockDevResized(vol, devPath, allowUnsafeResize)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -1079,7 +1084,11 @@ func (d *powerflex) GetVolumeDiskPath(vol Volume) (string, error) {
 
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
 		devPath, _, err := d.getMappedDevPath(vol, false)
-		return devPath, err
+		if err != nil {
+			return "", err
+		}
+
+		return blockDevPath(vol, devPath), nil
 	}
 
 	return "", ErrNotSupported
@@ -1216,7 +1225,7 @@ func (d *powerflex) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSr
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *powerflex) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *powerflex) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parentSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, progressReporter)
 }
 
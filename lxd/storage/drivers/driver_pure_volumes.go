@@ -25,6 +25,15 @@ import (
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *pure) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-pure; group=volume-conf; key=block.encryption)
+		// The only supported value is `luks2`, which encrypts the block device of the volume with LUKS2.
+		// A random key is generated when the volume is created and stored in `volatile.encryption.key`, wrapped by the key provider set in {config:option}`server-miscellaneous:storage.encryption.key_provider`.
+		// ---
+		//  type: string
+		//  condition: block-based volume
+		//  defaultdesc: same as `volume.block.encryption`
+		//  shortdesc: Encryption of the block device of the storage volume
+		"block.encryption": validate.Optional(validate.IsOneOf(blockEncryptionTypes...)),
 		// lxdmeta:generate(entities=storage-pure; group=volume-conf; key=block.filesystem)
 		// Valid options: `btrfs`, `ext4`, `xfs`
 		// If not set, `ext4` is assumed.
@@ -79,8 +88,7 @@ func (d *pure) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter i
 
 	revert.Add(func() { _ = client.deleteVolume(vol.pool, volName) })
 
-	volumeFilesystem := vol.ConfigBlockFilesystem()
-	if vol.contentType == ContentTypeFS {
+	if blockDevNeedsFormat(vol) {
 		devPath, cleanup, err := d.getMappedDevPath(vol, true)
 		if err != nil {
 			return err
@@ -88,7 +96,7 @@ func (d *pure) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter i
 
 		revert.Add(cleanup)
 
-		_, err = makeFSType(devPath, volumeFilesystem, nil)
+		err = blockDevFormat(vol, devPath)
 		if err != nil {
 			return err
 		}
@@ -805,6 +813,11 @@ func (d *pure) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, p
 		return nil
 	}
 
+	err = blockDevCheckResize(vol, oldSizeBytes, sizeBytes)
+	if err != nil {
+		return err
+	}
+
 	connector, err := d.connector()
 	if err != nil {
 		return err
@@ -918,13 +931,9 @@ func (d *pure) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, p
 			return err
 		}
 
-		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as it is
-		// expected the caller will do all necessary post resize actions themselves).
-		if vol.IsVMBlock() && !allowUnsafeResize {
-			err = d.moveGPTAltHeader(devPath)
-			if err != nil {
-				return err
-			}
+		err = d.blockDevResized(vol, devPath, allowUnsafeResize)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -935,7 +944,11 @@ func (d *pure) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, p
 func (d *pure) GetVolumeDiskPath(vol Volume) (string, error) {
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
 		devPath, _, err := d.getMappedDevPath(vol, false)
-		return devPath, err
+		if err != nil {
+			return "", err
+		}
+
+		return blockDevPath(vol, devPath), nil
 	}
 
 	return "", ErrNotSupported
@@ -1088,7 +1101,7 @@ func (d *pure) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcArgs
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *pure) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *pure) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parentSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, progressReporter)
 }
 
@@ -35,6 +35,15 @@ type Info struct {
 	// Whether driver generates an optimised backup header file in backup.
 	OptimizedBackupHeader bool
 
+	// Whether driver supports incremental optimized backups based on a snapshot.
+	IncrementalBackups bool
+
+	// Whether driver supports copy-on-write clones of custom volumes within the pool.
+	VolumeClones bool
+
+	// Whether volume clones keep depending on the volume or snapshot they were cloned from.
+	VolumeClonesDependOnOrigin bool
+
 	// Whether driver preserves inodes when volumes are moved hosts.
 	PreservesInodes bool
 
@@ -143,6 +143,9 @@ func (d *zfs) Info() Info {
 		DefaultVMBlockFilesystemSize: d.defaultVMBlockFilesystemSize(),
 		OptimizedImages:              true,
 		OptimizedBackups:             true,
+		IncrementalBackups:           true,
+		VolumeClones:                 true,
+		VolumeClonesDependOnOrigin:   true,
 		PreservesInodes:              true,
 		Remote:                       d.isRemote(),
 		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
@@ -150,7 +153,7 @@ func (d *zfs) Info() Info {
 		RunningCopyFreeze:            false,
 		DirectIO:                     zfsDirectIO,
 		MountedRoot:                  false,
-		Buckets:                      false,
+		Buckets:                      true,
 		PopulateParentVolumeUUID:     false,
 	}
 
@@ -517,6 +520,7 @@ func (d *zfs) Validate(config map[string]string) error {
 
 	// Append common local pool rules.
 	maps.Insert(rules, maps.All(d.commonRules.LocalPoolRules()))
+	maps.Insert(rules, maps.All(thinProvisioningRules()))
 
 	return d.validatePool(config, rules, d.commonVolumeRules())
 }
@@ -718,9 +722,44 @@ func (d *zfs) GetResources() (*api.ResourcesStoragePool, error) {
 	res.Space.Total = used + available
 	res.Space.Used = used
 
+	res.Space.Provisioned, err = d.provisionedSize(d.config["zfs.pool_name"])
+	if err != nil {
+		return nil, err
+	}
+
 	return &res, nil
 }
 
+// Scrub scrubs the zpool backing the storage pool and reports any data errors found.
+func (d *zfs) Scrub(progressReporter ioprogress.ProgressReporter) error {
+	// Scrubs apply to the whole zpool, even if the storage pool only uses one of its datasets.
+	zpoolName, _, _ := strings.Cut(d.config["zfs.pool_name"], "/")
+
+	_, err := shared.RunCommand(context.TODO(), "zpool", "scrub", "-w", zpoolName)
+	if err != nil {
+		return fmt.Errorf("Failed scrubbing zpool %q: %w", zpoolName, err)
+	}
+
+	out, err := shared.RunCommand(context.TODO(), "zpool", "status", zpoolName)
+	if err != nil {
+		return fmt.Errorf("Failed getting status of zpool %q: %w", zpoolName, err)
+	}
+
+	for line := range strings.SplitSeq(out, "\n") {
+		summary, ok := strings.CutPrefix(strings.TrimSpace(line), "errors:")
+		if !ok {
+			continue
+		}
+
+		summary = strings.TrimSpace(summary)
+		if summary != "No known data errors" {
+			return fmt.Errorf("%w in zpool %q: %s", ErrCorrupted, zpoolName, summary)
+		}
+	}
+
+	return nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing
 // migrations between pools in preference order.
 func (d *zfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
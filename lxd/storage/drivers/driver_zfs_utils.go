@@ -320,6 +320,49 @@ func (d *zfs) getDatasetProperty(dataset string, key string) (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
+// provisionedSize returns the total size allocated to the datasets below the given dataset.
+// This is the size of the ZFS volumes and the quota of the filesystem datasets.
+func (d *zfs) provisionedSize(dataset string) (uint64, error) {
+	out, err := shared.RunCommand(context.TODO(), "zfs", "list", "-H", "-p", "-r", "-t", "filesystem,volume", "-o", "type,volsize,quota,refquota", dataset)
+	if err != nil {
+		return 0, err
+	}
+
+	var provisioned uint64
+	for line := range strings.SplitSeq(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+
+		var sizes []string
+		if fields[0] == "volume" {
+			sizes = []string{fields[1]}
+		} else {
+			sizes = fields[2:]
+		}
+
+		var size uint64
+		for _, value := range sizes {
+			// Unset properties are reported as "-" or "0".
+			if value == "-" {
+				continue
+			}
+
+			valueBytes, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("Failed parsing dataset size (%q): %w", value, err)
+			}
+
+			size = max(size, valueBytes)
+		}
+
+		provisioned += size
+	}
+
+	return provisioned, nil
+}
+
 func (d *zfs) getDatasetProperties(dataset string, keys ...string) (map[string]string, error) {
 	output, err := shared.RunCommand(context.TODO(), "zfs", "get", "-H", "-p", "-o", "property,value", strings.Join(keys, ","), dataset)
 	if err != nil {
@@ -151,7 +151,7 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter io
 			return err
 		}
 
-		if vol.contentType == ContentTypeFS {
+		if blockDevNeedsFormat(vol) {
 			activated, volPath, err := d.activateVolume(vol)
 			if err != nil {
 				return err
@@ -161,7 +161,7 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter io
 				defer func() { _, _ = d.deactivateVolume(vol) }()
 			}
 
-			_, err = makeFSType(volPath, vol.ConfigBlockFilesystem(), nil)
+			err = blockDevFormat(vol, volPath)
 			if err != nil {
 				return err
 			}
@@ -440,10 +440,17 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 	revert := revert.New()
 	defer revert.Fail()
 
+	// Incremental backups are restored by receiving the volume data of their whole chain of backups.
+	chain := srcBackup.Chain(srcData)
+	var chainSnapshots []string
+	for _, link := range chain {
+		chainSnapshots = append(chainSnapshots, link.Info.Snapshots...)
+	}
+
 	// Define a revert function that will be used both to revert if an error occurs inside this
 	// function but also return it for use from the calling functions if no error internally.
 	revertHook := func() {
-		for _, snapName := range srcBackup.Snapshots {
+		for _, snapName := range chainSnapshots {
 			fullSnapshotName := GetSnapshotVolumeName(vol.name, snapName)
 			snapVol := NewVolume(d, d.name, vol.volType, vol.contentType, fullSnapshotName, vol.config, vol.poolConfig)
 			_ = d.DeleteVolumeSnapshot(snapVol, progressReporter)
@@ -509,18 +516,7 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 	vols = append(vols, vol.Volume)
 
 	for _, v := range vols {
-		// Find the compression algorithm used for backup source data.
-		_, err := srcData.Seek(0, io.SeekStart)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		_, _, unpacker, err := shared.DetectCompressionFile(srcData)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		if len(srcBackup.Snapshots) > 0 {
+		if len(chainSnapshots) > 0 {
 			// Create new snapshots directory.
 			err := createParentSnapshotDirIfMissing(d.name, v.volType, v.name)
 			if err != nil {
@@ -528,32 +524,46 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 			}
 		}
 
-		// Restore backups from oldest to newest.
-		for _, snapName := range srcBackup.Snapshots {
-			// Defend against path traversal attacks.
-			err := instancetype.ValidSnapName(snapName)
+		var unpacker []string
+		for _, link := range chain {
+			// Find the compression algorithm used for backup source data.
+			_, err := link.Data.Seek(0, io.SeekStart)
 			if err != nil {
-				return nil, nil, fmt.Errorf("Invalid snapshot name %q: %w", snapName, err)
+				return nil, nil, err
 			}
 
-			prefix := "snapshots"
-			fileName := snapName + ".bin"
-			switch v.volType {
-			case VolumeTypeVM:
-				prefix = "virtual-machine-snapshots"
-				if v.contentType == ContentTypeFS {
-					fileName = snapName + "-config.bin"
+			_, _, unpacker, err = shared.DetectCompressionFile(link.Data)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			// Restore backups from oldest to newest.
+			for _, snapName := range link.Info.Snapshots {
+				// Defend against path traversal attacks.
+				err := instancetype.ValidSnapName(snapName)
+				if err != nil {
+					return nil, nil, fmt.Errorf("Invalid snapshot name %q: %w", snapName, err)
 				}
 
-			case VolumeTypeCustom:
-				prefix = "volume-snapshots"
-			}
+				prefix := "snapshots"
+				fileName := snapName + ".bin"
+				switch v.volType {
+				case VolumeTypeVM:
+					prefix = "virtual-machine-snapshots"
+					if v.contentType == ContentTypeFS {
+						fileName = snapName + "-config.bin"
+					}
 
-			srcFile := "backup/" + prefix + "/" + fileName
-			dstSnapshot := d.dataset(v, false) + "@snapshot-" + snapName
-			err = unpackVolume(v, srcData, unpacker, srcFile, dstSnapshot)
-			if err != nil {
-				return nil, nil, err
+				case VolumeTypeCustom:
+					prefix = "volume-snapshots"
+				}
+
+				srcFile := "backup/" + prefix + "/" + fileName
+				dstSnapshot := d.dataset(v, false) + "@snapshot-" + snapName
+				err = unpackVolume(v, link.Data, unpacker, srcFile, dstSnapshot)
+				if err != nil {
+					return nil, nil, err
+				}
 			}
 		}
 
@@ -571,7 +581,7 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 			fileName = "volume.bin"
 		}
 
-		err = unpackVolume(v, srcData, unpacker, "backup/"+fileName, d.dataset(v, false))
+		err := unpackVolume(v, srcData, unpacker, "backup/"+fileName, d.dataset(v, false))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -582,9 +592,11 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 			return nil, nil, err
 		}
 
-		// Remove only the internal snapshots.
+		// Remove only the internal snapshots and the ones that were deleted before the last backup of the chain.
+		restoredSnapshots := srcBackup.RestoredSnapshots()
 		for _, entry := range entries {
-			if strings.Contains(entry, "@snapshot-") {
+			_, snapName, isSnapshot := strings.Cut(entry, "@snapshot-")
+			if isSnapshot && slices.Contains(restoredSnapshots, snapName) {
 				continue
 			}
 
@@ -667,7 +679,20 @@ func (d *zfs) CreateVolumeFromCopy(vol VolumeCopy, srcVol VolumeCopy, allowIncon
 	rebase := d.config["zfs.clone_copy"] == "rebase" && (srcVol.volType == VolumeTypeContainer || srcVol.volType == VolumeTypeVM)
 
 	// Use full copy mode when zfs.clone_copy is false or rebase mode is enabled or source volume has snapshots.
+	// Custom volume clones are always cloned regardless of zfs.clone_copy.
 	fullCopy := shared.IsFalse(d.config["zfs.clone_copy"]) || rebase || len(vol.Snapshots) > 0
+	if vol.volType == VolumeTypeCustom && vol.config["volatile.clone.origin"] != "" {
+		if len(vol.Snapshots) > 0 {
+			return errors.New("Cannot clone volume with snapshots")
+		}
+
+		// Promotion would make the origin depend on the clone instead.
+		if shared.IsTrue(vol.config["zfs.promote"]) {
+			return errors.New("Cannot promote volume clones")
+		}
+
+		fullCopy = false
+	}
 
 	// Validate that promotion can be done if requested.
 	if shared.IsTrue(vol.config["zfs.promote"]) {
@@ -1986,6 +2011,16 @@ func (d *zfs) hasAnyImageVariant(vol Volume) (bool, error) {
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *zfs) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-zfs; group=volume-conf; key=block.encryption)
+		// The only supported value is `luks2`, which encrypts the block device of the volume with LUKS2.
+		// A random key is generated when the volume is created and stored in `volatile.encryption.key`, wrapped by the key provider set in {config:option}`server-miscellaneous:storage.encryption.key_provider`.
+		// ---
+		//  type: string
+		//  condition: ZFS volume (`zfs.block_mode` enabled or content type `block`)
+		//  defaultdesc: same as `volume.block.encryption`
+		//  shortdesc: Encryption of the block device of the storage volume
+		//  scope: global
+		"block.encryption": validate.Optional(validate.IsOneOf(blockEncryptionTypes...)),
 		// lxdmeta:generate(entities=storage-zfs; group=volume-conf; key=block.filesystem)
 		// Valid options: `btrfs`, `ext4`, `xfs`
 		// If not set, `ext4` is assumed.
@@ -2092,6 +2127,23 @@ func (d *zfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		delete(commonRules, "block.mount_options")
 	}
 
+	// Only volumes backed by a ZFS volume can be encrypted.
+	if vol.contentType == ContentTypeFS && !vol.IsBlockBacked() {
+		delete(commonRules, "block.encryption")
+	}
+
+	if vol.volType == VolumeTypeCustom {
+		// lxdmeta:generate(entities=storage-zfs; group=volume-conf; key=volatile.clone.origin)
+		// This is set on custom volumes created with `lxc storage volume copy --clone`.
+		// The origin volume or snapshot can't be deleted while the clone exists.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  shortdesc: UUID of the volume or snapshot the volume was cloned from
+		//  scope: global
+		commonRules["volatile.clone.origin"] = validate.Optional(validate.IsUUID)
+	}
+
 	return d.validateVolume(vol, commonRules, removeUnknownKeys)
 }
 
@@ -2212,6 +2264,11 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 			return nil
 		}
 
+		err = blockDevCheckResize(vol, oldVolSizeBytes, sizeBytes)
+		if err != nil {
+			return err
+		}
+
 		if vol.contentType == ContentTypeFS {
 			if vol.volType == VolumeTypeImage {
 				return fmt.Errorf("Image volumes cannot be resized: %w", ErrCannotBeShrunk)
@@ -2289,6 +2346,11 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 			if err != nil {
 				return err
 			}
+
+			err = blockDevGrow(vol)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as
@@ -2453,6 +2515,16 @@ func (d *zfs) getVolumeDiskPathFromDataset(dataset string) (string, error) {
 
 // GetVolumeDiskPath returns the location of a root disk block device.
 func (d *zfs) GetVolumeDiskPath(vol Volume) (string, error) {
+	devPath, err := d.getVolumeDevPath(vol)
+	if err != nil {
+		return "", err
+	}
+
+	return blockDevPath(vol, devPath), nil
+}
+
+// getVolumeDevPath returns the location of the ZFS volume's block device.
+func (d *zfs) getVolumeDevPath(vol Volume) (string, error) {
 	// Wait up to 30 seconds for the device to appear.
 	// Don't use d.state.ShutdownCtx here as this is used during instance stop during LXD shutdown after it is
 	// canceled.
@@ -2613,7 +2685,7 @@ func (d *zfs) activateVolume(vol Volume) (bool, string, error) {
 		d.logger.Debug("Activated ZFS volume", logger.Ctx{"volName": vol.Name(), "dev": dataset})
 	}
 
-	volumeDiskPath, err := d.GetVolumeDiskPath(vol)
+	volumeDiskPath, err := d.getVolumeDevPath(vol)
 	if err != nil {
 		return false, "", fmt.Errorf("Failed getting volume disk path: %v", err)
 	}
@@ -2639,7 +2711,13 @@ func (d *zfs) deactivateVolume(vol Volume) (bool, error) {
 		return false, nil
 	}
 
-	devPath, err := d.GetVolumeDiskPath(vol)
+	// Close the decrypted mapping first as it holds the zvol.
+	err = blockDevClose(vol)
+	if err != nil {
+		return false, err
+	}
+
+	devPath, err := d.getVolumeDevPath(vol)
 	if err != nil {
 		return false, fmt.Errorf("Failed locating zvol for deactivation: %w", err)
 	}
@@ -2747,6 +2825,16 @@ func (d *zfs) MountVolume(vol Volume, progressReporter ioprogress.ProgressReport
 			revert.Add(func() { _, _ = d.deactivateVolume(vol) })
 		}
 
+		// Open the decrypted mapping if needed.
+		volPath, opened, err := blockDevOpen(vol, volPath)
+		if err != nil {
+			return err
+		}
+
+		if opened {
+			revert.Add(func() { _ = blockDevClose(vol) })
+		}
+
 		if !IsContentBlock(vol.contentType) && d.isBlockBacked(vol) && !filesystem.IsMountPoint(mountPath) {
 			err := vol.EnsureMountPath()
 			if err != nil {
@@ -3229,7 +3317,7 @@ func (d *zfs) readonlySnapshot(vol Volume) (string, revert.Hook, error) {
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, parentSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -3264,7 +3352,7 @@ func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instan
 	// Backup VM config volumes first.
 	if vol.IsVMBlock() {
 		fsVol := NewVolumeCopy(vol.NewVMBlockFilesystemVolume())
-		err := d.BackupVolume(fsVol, projectName, tarWriter, optimized, snapshots, progressReporter)
+		err := d.BackupVolume(fsVol, projectName, tarWriter, optimized, snapshots, parentSnapshot, progressReporter)
 		if err != nil {
 			return err
 		}
@@ -3322,18 +3410,20 @@ func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instan
 		return tmpFile.Close()
 	}
 
-	// Handle snapshots.
+	// Incremental backups are sent from the parent snapshot onwards.
 	finalParent := ""
+	if parentSnapshot != "" {
+		parentVol, _ := vol.NewSnapshot(parentSnapshot)
+		finalParent = d.dataset(parentVol, false)
+	}
+
+	// Handle snapshots.
 	if len(snapshots) > 0 {
-		for i, snapName := range snapshots {
+		for _, snapName := range snapshots {
 			snapshot, _ := vol.NewSnapshot(snapName)
 
 			// Figure out parent and current subvolumes.
-			parent := ""
-			if i > 0 {
-				oldSnapshot, _ := vol.NewSnapshot(snapshots[i-1])
-				parent = d.dataset(oldSnapshot, false)
-			}
+			parent := finalParent
 
 			// Make a binary zfs backup.
 			prefix := "snapshots"
@@ -3571,6 +3661,25 @@ func (d *zfs) mountVolumeSnapshot(snapVol Volume, snapshotDataset string, mountP
 			d.logger.Debug("Activated ZFS snapshot volume", logger.Ctx{"dev": snapshotDataset})
 		}
 
+		if snapVol.contentType == ContentTypeBlock && snapVol.IsEncrypted() {
+			ctx, cancel := context.WithTimeout(context.TODO(), time.Second*30)
+			defer cancel()
+			volPath, err := d.tryGetVolumeDiskPathFromDataset(ctx, snapshotDataset)
+			if err != nil {
+				return nil, err
+			}
+
+			// Open the decrypted mapping of the snapshot.
+			_, opened, err := blockDevOpen(snapVol, volPath)
+			if err != nil {
+				return nil, err
+			}
+
+			if opened {
+				revert.Add(func() { _ = blockDevClose(snapVol) })
+			}
+		}
+
 		if snapVol.contentType != ContentTypeBlock && d.isBlockBacked(snapVol) && !filesystem.IsMountPoint(mountPath) {
 			err = snapVol.EnsureMountPath()
 			if err != nil {
@@ -3620,6 +3729,16 @@ func (d *zfs) mountVolumeSnapshot(snapVol Volume, snapshotDataset string, mountP
 				return nil, err
 			}
 
+			// Open the decrypted mapping if needed.
+			volPath, opened, err := blockDevOpen(mountVol, volPath)
+			if err != nil {
+				return nil, err
+			}
+
+			if opened {
+				revert.Add(func() { _ = blockDevClose(mountVol) })
+			}
+
 			tmpVolFsType := mountVol.ConfigBlockFilesystem()
 			mountOptions = addNoRecoveryMountOption(mountOptions, tmpVolFsType)
 
@@ -3712,6 +3831,18 @@ func (d *zfs) UnmountVolumeSnapshot(snapVol Volume, progressReporter ioprogress.
 			d.logger.Debug("Unmounted ZFS snapshot dataset", logger.Ctx{"dev": snapshotDataset, "path": mountPath})
 			ourUnmount = true
 
+			// Close the decrypted mappings of the snapshot or of its temporary volume.
+			err = blockDevClose(snapVol)
+			if err != nil {
+				return true, err
+			}
+
+			tmpVol := NewVolume(d, d.name, snapVol.volType, snapVol.contentType, snapVol.name+tmpVolSuffix, snapVol.config, snapVol.poolConfig)
+			err = blockDevClose(tmpVol)
+			if err != nil {
+				return true, err
+			}
+
 			parent, snapshotOnlyName, _ := api.GetParentAndSnapshotName(snapVol.Name())
 			parentVol := NewVolume(d, d.Name(), snapVol.volType, snapVol.contentType, parent, snapVol.config, snapVol.poolConfig)
 			parentDataset := d.dataset(parentVol, false)
@@ -3745,6 +3876,13 @@ func (d *zfs) UnmountVolumeSnapshot(snapVol Volume, progressReporter ioprogress.
 				return false, ErrInUse
 			}
 
+			if snapVol.contentType == ContentTypeBlock {
+				err = blockDevClose(snapVol)
+				if err != nil {
+					return false, err
+				}
+			}
+
 			err := d.setDatasetProperties(parentDataset, "snapdev=hidden")
 			if err != nil {
 				return false, err
@@ -3976,6 +4114,11 @@ func (d *zfs) FillVolumeConfig(vol Volume) error {
 		return err
 	}
 
+	// Datasets can't be encrypted, so don't inherit the pool encryption for them.
+	if vol.contentType == ContentTypeFS && !d.isBlockBacked(vol) && vol.config["block.encryption"] == d.config["volume.block.encryption"] {
+		delete(vol.config, "block.encryption")
+	}
+
 	// Only validate filesystem config keys for filesystem volumes.
 	if d.isBlockBacked(vol) && vol.ContentType() == ContentTypeFS {
 		// Inherit block mode from pool if not set.
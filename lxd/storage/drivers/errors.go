@@ -14,6 +14,9 @@ var ErrNotSupported = errors.New("Not supported")
 // ErrCannotBeShrunk is the "Cannot be shrunk" error.
 var ErrCannotBeShrunk = errors.New("Cannot be shrunk")
 
+// ErrCorrupted is the "Corrupted data found" error.
+var ErrCorrupted = errors.New("Corrupted data found")
+
 // ErrInUse indicates operation cannot proceed as resource is in use.
 var ErrInUse = errors.New("In use")
 
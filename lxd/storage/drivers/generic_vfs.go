@@ -1222,6 +1222,16 @@ func mountVolume(d Driver, vol Volume, getDevicePath getVolumePathFunc, progress
 
 	revert.Add(cleanup)
 
+	// Open the decrypted mapping of encrypted volumes.
+	volDevPath, opened, err := blockDevOpen(vol, volDevPath)
+	if err != nil {
+		return err
+	}
+
+	if opened {
+		revert.Add(func() { _ = blockDevClose(vol) })
+	}
+
 	switch vol.contentType {
 	case ContentTypeFS:
 		mountPath := vol.MountPath()
@@ -1294,6 +1304,11 @@ func unmountVolume(d Driver, vol Volume, keepBlockDev bool, getDevicePath getVol
 
 		// Attempt to unmap.
 		if !keepBlockDev {
+			err = blockDevClose(vol)
+			if err != nil {
+				return false, err
+			}
+
 			err = unmapVolume(vol)
 			if err != nil {
 				return false, err
@@ -1321,7 +1336,12 @@ func unmountVolume(d Driver, vol Volume, keepBlockDev bool, getDevicePath getVol
 				}
 
 				// Attempt to unmap.
-				err := unmapVolume(vol)
+				err := blockDevClose(vol)
+				if err != nil {
+					return false, err
+				}
+
+				err = unmapVolume(vol)
 				if err != nil {
 					return false, err
 				}
@@ -26,6 +26,7 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/ioprogress"
 	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/validate"
 )
 
 // noKillRetryOpts is used as the default [shared.RunCommandRetryOpts] for storage operations.
@@ -442,6 +443,10 @@ func shrinkFileSystem(fsType string, devPath string, vol Volume, byteSize int64,
 		fsType = DefaultFilesystem
 	}
 
+	if vol.IsEncrypted() {
+		return fmt.Errorf("Encrypted volumes cannot be shrunk: %w", ErrCannotBeShrunk)
+	}
+
 	if !filesystemTypeCanBeShrunk(fsType) {
 		return ErrCannotBeShrunk
 	}
@@ -512,7 +517,14 @@ func growFileSystem(fsType string, devPath string, vol Volume) error {
 	}
 
 	return vol.MountTask(func(mountPath string, progressReporter ioprogress.ProgressReporter) error {
-		var err error
+		// Grow the decrypted mapping of encrypted volumes to fill the block device first.
+		err := blockDevGrow(vol)
+		if err != nil {
+			return err
+		}
+
+		devPath = blockDevPath(vol, devPath)
+
 		switch fsType {
 		case "ext4":
 			_, err = shared.RunCommandRetry(context.TODO(), noKillRetryOpts, "resize2fs", devPath)
@@ -1005,3 +1017,37 @@ func ValidVolumeName(volumeName string) error {
 
 	return nil
 }
+
+// thinProvisioningRules returns the pool config rules for tracking the usage of thinly provisioned pools.
+func thinProvisioningRules() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-lvm,storage-zfs,storage-ceph; group=pool-conf; key=overcommit.ratio)
+		// The ratio is applied to the total size of the storage pool. For example, `1.5` allows allocating up to
+		// 150% of the pool size to its volumes. Creating or growing a volume that would allocate more than that fails.
+		// ---
+		//  type: string
+		//  defaultdesc: unlimited
+		//  shortdesc: Maximum ratio of space allocated to volumes to the pool size
+		//  scope: global
+		"overcommit.ratio": validate.Optional(func(value string) error {
+			ratio, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("Invalid overcommit ratio: %w", err)
+			}
+
+			if ratio <= 0 {
+				return errors.New("Overcommit ratio must be greater than 0")
+			}
+
+			return nil
+		}),
+		// lxdmeta:generate(entities=storage-lvm,storage-zfs,storage-ceph; group=pool-conf; key=capacity.threshold)
+		// A warning is raised when the used space of the storage pool reaches this percentage of its size.
+		// ---
+		//  type: integer
+		//  defaultdesc: `90`
+		//  shortdesc: Used space percentage that raises a warning
+		//  scope: global
+		"capacity.threshold": validate.Optional(validate.IsInRange(1, 100)),
+	}
+}
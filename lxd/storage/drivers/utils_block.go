@@ -0,0 +1,225 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/canonical/lxd/lxd/storage/encryption"
+	"github.com/canonical/lxd/shared"
+)
+
+// blockEncryptionTypes are the supported values of the block.encryption volume option.
+var blockEncryptionTypes = []string{"luks2"}
+
+// luksHeaderSectors is the size in 512 bytes sectors of the LUKS2 header at the start of the block device of
+// encrypted volumes.
+const luksHeaderSectors = 32768
+
+// BlockDevSize returns the size of the block device of a volume needed to hold dataSizeBytes of data.
+// This accounts for the LUKS2 header of encrypted volumes.
+func BlockDevSize(vol Volume, dataSizeBytes int64) int64 {
+	if vol.IsEncrypted() {
+		return dataSizeBytes + luksHeaderSectors*512
+	}
+
+	return dataSizeBytes
+}
+
+// The blockDev functions below are used by the block-backed drivers to set up and use the block device of a volume.
+// They hide whether the data of the volume is stored in its block device directly or in the decrypted mapping of
+// its LUKS formatted block device, so that drivers don't need to handle encrypted volumes themselves.
+
+// blockDevNeedsFormat indicates whether a newly created block device of a volume needs formatting with blockDevFormat.
+func blockDevNeedsFormat(vol Volume) bool {
+	return vol.contentType == ContentTypeFS || vol.IsEncrypted()
+}
+
+// blockDevFormat formats the newly created block device of a volume. The block device of encrypted volumes is
+// formatted as a LUKS2 device and filesystem volumes get a filesystem on the device holding their data.
+func blockDevFormat(vol Volume, devPath string) error {
+	if vol.IsEncrypted() {
+		err := luksFormat(vol, devPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if vol.contentType != ContentTypeFS {
+		return nil
+	}
+
+	return blockDevTask(vol, devPath, func(devPath string) error {
+		_, err := makeFSType(devPath, vol.ConfigBlockFilesystem(), nil)
+		return err
+	})
+}
+
+// blockDevOpen returns the path of the device holding the data of a volume whose block device is at devPath.
+// The decrypted mapping of encrypted volumes is opened if not already open, in which case opened is true.
+func blockDevOpen(vol Volume, devPath string) (dataPath string, opened bool, err error) {
+	return luksOpen(vol, devPath)
+}
+
+// blockDevClose closes the device opened by blockDevOpen if still open.
+func blockDevClose(vol Volume) error {
+	return luksClose(vol)
+}
+
+// blockDevTask runs the task against the device holding the data of a volume whose block device is at devPath,
+// opening it for the duration of the task if needed.
+func blockDevTask(vol Volume, devPath string, task func(devPath string) error) error {
+	dataPath, opened, err := blockDevOpen(vol, devPath)
+	if err != nil {
+		return err
+	}
+
+	if opened {
+		defer func() { _ = blockDevClose(vol) }()
+	}
+
+	return task(dataPath)
+}
+
+// blockDevPath returns the path of the device holding the data of a volume whose block device is at devPath.
+// For encrypted volumes, this is the path of the decrypted mapping opened by blockDevOpen.
+func blockDevPath(vol Volume, devPath string) string {
+	if vol.IsEncrypted() {
+		return luksDevPath(vol)
+	}
+
+	return devPath
+}
+
+// blockDevCheckResize returns an error if the block device of a volume can't be resized from oldSizeBytes to
+// sizeBytes.
+func blockDevCheckResize(vol Volume, oldSizeBytes int64, sizeBytes int64) error {
+	if vol.IsEncrypted() && sizeBytes < oldSizeBytes {
+		return fmt.Errorf("Encrypted volumes cannot be shrunk: %w", ErrCannotBeShrunk)
+	}
+
+	return nil
+}
+
+// blockDevGrow grows the device holding the data of a volume to the size of its resized block device.
+func blockDevGrow(vol Volume) error {
+	return luksResize(vol)
+}
+
+// blockDevResized finishes the resize of a block volume whose block device is at devPath. The device holding the
+// data is grown, and the VM GPT alt header moved to the end of the disk if needed (not needed in unsafe resize mode
+// as it is expected the caller will do all necessary post resize actions themselves).
+func (d *common) blockDevResized(vol Volume, devPath string, allowUnsafeResize bool) error {
+	err := blockDevGrow(vol)
+	if err != nil {
+		return err
+	}
+
+	if !vol.IsVMBlock() || allowUnsafeResize {
+		return nil
+	}
+
+	return blockDevTask(vol, devPath, d.moveGPTAltHeader)
+}
+
+// luksKey returns the unwrapped key of an encrypted volume.
+func luksKey(vol Volume) ([]byte, error) {
+	wrappedKey := vol.config["volatile.encryption.key"]
+	if wrappedKey == "" {
+		return nil, fmt.Errorf("Encrypted volume %q has no key", vol.name)
+	}
+
+	return encryption.UnwrapKey(context.TODO(), wrappedKey)
+}
+
+// luksRunCommand runs cryptsetup with the key of an encrypted volume passed on stdin.
+func luksRunCommand(vol Volume, args ...string) error {
+	key, err := luksKey(vol)
+	if err != nil {
+		return err
+	}
+
+	return shared.RunCommandWithFds(context.TODO(), bytes.NewReader(key), nil, "cryptsetup", args...)
+}
+
+// luksMapperName returns the device mapper name of the decrypted mapping of an encrypted volume.
+// The name is derived from the volume identity so that it fits within the device mapper name length limit.
+func luksMapperName(vol Volume) string {
+	hash := sha256.Sum256([]byte(vol.pool + "/" + string(vol.volType) + "/" + string(vol.contentType) + "/" + vol.name))
+	return fmt.Sprintf("lxd-crypt-%x", hash[:16])
+}
+
+// luksDevPath returns the path of the decrypted mapping of an encrypted volume.
+func luksDevPath(vol Volume) string {
+	return filepath.Join("/dev/mapper", luksMapperName(vol))
+}
+
+// luksFormat formats the block device of an encrypted volume as a LUKS2 device.
+// As volume keys are random, a single iteration of PBKDF2 is enough to derive the key slot key.
+func luksFormat(vol Volume, devPath string) error {
+	_, err := exec.LookPath("cryptsetup")
+	if err != nil {
+		return errors.New("Encrypted volumes require the cryptsetup command")
+	}
+
+	err = luksRunCommand(vol, "luksFormat", "--batch-mode", "--type", "luks2", "--offset", strconv.Itoa(luksHeaderSectors), "--pbkdf", "pbkdf2", "--pbkdf-force-iterations", "1000", "--key-file", "-", devPath)
+	if err != nil {
+		return fmt.Errorf("Failed formatting encrypted volume %q: %w", vol.name, err)
+	}
+
+	return nil
+}
+
+// luksOpen opens the decrypted mapping of an encrypted volume's block device if not already open.
+// Returns the path of the mapping and whether it was opened by this call.
+// For unencrypted volumes, devPath is returned as is.
+func luksOpen(vol Volume, devPath string) (string, bool, error) {
+	if !vol.IsEncrypted() {
+		return devPath, false, nil
+	}
+
+	mapperPath := luksDevPath(vol)
+	if shared.PathExists(mapperPath) {
+		return mapperPath, false, nil
+	}
+
+	err := luksRunCommand(vol, "open", "--type", "luks2", "--allow-discards", "--key-file", "-", devPath, luksMapperName(vol))
+	if err != nil {
+		return "", false, fmt.Errorf("Failed opening encrypted volume %q: %w", vol.name, err)
+	}
+
+	return mapperPath, true, nil
+}
+
+// luksClose closes the decrypted mapping of an encrypted volume if open.
+func luksClose(vol Volume) error {
+	if !vol.IsEncrypted() || !shared.PathExists(luksDevPath(vol)) {
+		return nil
+	}
+
+	_, err := shared.RunCommandRetry(context.TODO(), noKillRetryOpts, "cryptsetup", "close", luksMapperName(vol))
+	if err != nil {
+		return fmt.Errorf("Failed closing encrypted volume %q: %w", vol.name, err)
+	}
+
+	return nil
+}
+
+// luksResize grows the decrypted mapping of an encrypted volume to the size of its block device if open.
+func luksResize(vol Volume) error {
+	if !vol.IsEncrypted() || !shared.PathExists(luksDevPath(vol)) {
+		return nil
+	}
+
+	err := luksRunCommand(vol, "resize", "--key-file", "-", luksMapperName(vol))
+	if err != nil {
+		return fmt.Errorf("Failed resizing encrypted volume %q: %w", vol.name, err)
+	}
+
+	return nil
+}
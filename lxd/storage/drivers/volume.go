@@ -381,6 +381,15 @@ func (v Volume) IsBlockBacked() bool {
 	return v.driver.isBlockBacked(v) || v.mountFilesystemProbe
 }
 
+// IsEncrypted indicates whether the volume's block device is encrypted.
+func (v Volume) IsEncrypted() bool {
+	if v.config["block.encryption"] == "" {
+		return false
+	}
+
+	return v.contentType == ContentTypeBlock || (v.contentType == ContentTypeFS && v.IsBlockBacked())
+}
+
 // Type returns the volume type.
 func (v Volume) Type() VolumeType {
 	return v.volType
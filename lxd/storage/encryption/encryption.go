@@ -0,0 +1,148 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// KeySize is the size in bytes of the keys of encrypted volumes.
+const KeySize = 64
+
+// DefaultKeyProvider is the key provider used to wrap new keys when none is configured.
+const DefaultKeyProvider = "file"
+
+// KeyProvider wraps and unwraps the keys of encrypted volumes using a key encryption key that it holds, so that
+// only wrapped keys need to be stored.
+type KeyProvider interface {
+	// WrapKey encrypts a volume key. The clustered argument indicates that the key must be unwrappable by all
+	// cluster members.
+	WrapKey(ctx context.Context, key []byte, clustered bool) ([]byte, error)
+
+	// UnwrapKey decrypts a volume key encrypted by WrapKey.
+	UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error)
+}
+
+// providers contains the constructors of the supported key providers.
+var providers = map[string]func() (KeyProvider, error){
+	"file": newFileProvider,
+}
+
+// providerCache contains the key providers loaded so far.
+var providerCache = map[string]KeyProvider{}
+var providerCacheMu sync.Mutex
+
+// Providers returns the names of the supported key providers.
+func Providers() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// loadProvider returns the key provider with the given name.
+func loadProvider(name string) (KeyProvider, error) {
+	providerCacheMu.Lock()
+	defer providerCacheMu.Unlock()
+
+	provider, ok := providerCache[name]
+	if ok {
+		return provider, nil
+	}
+
+	newProvider, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown key provider %q", name)
+	}
+
+	provider, err := newProvider()
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading key provider %q: %w", name, err)
+	}
+
+	providerCache[name] = provider
+
+	return provider, nil
+}
+
+// NewKey generates a new volume key and returns it wrapped by the given key provider.
+// The wrapped key is prefixed with the name of the provider so that it can be unwrapped with the same provider
+// even if another one is configured later on. The clustered argument indicates that the server is part of a cluster,
+// in which case the key must be unwrappable by all cluster members.
+func NewKey(ctx context.Context, providerName string, clustered bool) (string, error) {
+	if providerName == "" {
+		providerName = DefaultKeyProvider
+	}
+
+	provider, err := loadProvider(providerName)
+	if err != nil {
+		return "", err
+	}
+
+	key := make([]byte, KeySize)
+	_, err = rand.Read(key)
+	if err != nil {
+		return "", fmt.Errorf("Failed generating volume key: %w", err)
+	}
+
+	wrappedKey, err := provider.WrapKey(ctx, key, clustered)
+	if err != nil {
+		return "", fmt.Errorf("Failed wrapping volume key with key provider %q: %w", providerName, err)
+	}
+
+	return providerName + ":" + base64.StdEncoding.EncodeToString(wrappedKey), nil
+}
+
+// UnwrapKey returns the volume key of a wrapped key returned by NewKey.
+func UnwrapKey(ctx context.Context, wrappedKey string) ([]byte, error) {
+	providerName, encodedKey, ok := strings.Cut(wrappedKey, ":")
+	if !ok || providerName == "" || encodedKey == "" {
+		return nil, errors.New("Invalid wrapped volume key")
+	}
+
+	provider, err := loadProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	rawKey, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid wrapped volume key: %w", err)
+	}
+
+	key, err := provider.UnwrapKey(ctx, rawKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed unwrapping volume key with key provider %q: %w", providerName, err)
+	}
+
+	return key, nil
+}
+
+// ValidateWrappedKey checks that a wrapped key has the format returned by NewKey.
+func ValidateWrappedKey(wrappedKey string) error {
+	providerName, encodedKey, ok := strings.Cut(wrappedKey, ":")
+	if !ok || encodedKey == "" {
+		return errors.New("Invalid wrapped volume key")
+	}
+
+	_, ok = providers[providerName]
+	if !ok {
+		return fmt.Errorf("Unknown key provider %q", providerName)
+	}
+
+	_, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return fmt.Errorf("Invalid wrapped volume key: %w", err)
+	}
+
+	return nil
+}
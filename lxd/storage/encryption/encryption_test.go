@@ -0,0 +1,95 @@
+package encryption
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeys(t *testing.T) {
+	lxdDir := t.TempDir()
+	t.Setenv("LXD_DIR", lxdDir)
+
+	// Don't reuse a file provider loaded with another LXD_DIR.
+	providerCacheMu.Lock()
+	delete(providerCache, "file")
+	providerCacheMu.Unlock()
+
+	ctx := context.Background()
+
+	// In a cluster, the key encryption key isn't generated as it must be identical on all members.
+	_, err := NewKey(ctx, "", true)
+	assert.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(lxdDir, "storage-encryption.key"))
+
+	// The key encryption key is generated along with the first key.
+	wrappedKey, err := NewKey(ctx, "", false)
+	require.NoError(t, err)
+	assert.NoError(t, ValidateWrappedKey(wrappedKey))
+	assert.FileExists(t, filepath.Join(lxdDir, "storage-encryption.key"))
+
+	key, err := UnwrapKey(ctx, wrappedKey)
+	require.NoError(t, err)
+	assert.Len(t, key, KeySize)
+
+	// Keys are random.
+	otherWrappedKey, err := NewKey(ctx, "file", false)
+	require.NoError(t, err)
+
+	otherKey, err := UnwrapKey(ctx, otherWrappedKey)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+
+	// In a cluster, an existing key encryption key is used.
+	clusterWrappedKey, err := NewKey(ctx, "file", true)
+	require.NoError(t, err)
+
+	_, err = UnwrapKey(ctx, clusterWrappedKey)
+	require.NoError(t, err)
+
+	// Keys can't be unwrapped with another key encryption key.
+	err = os.WriteFile(filepath.Join(lxdDir, "storage-encryption.key"), make([]byte, fileKeySize), 0600)
+	require.NoError(t, err)
+
+	_, err = UnwrapKey(ctx, wrappedKey)
+	assert.Error(t, err)
+
+	// Keys can't be unwrapped without the key encryption key.
+	err = os.Remove(filepath.Join(lxdDir, "storage-encryption.key"))
+	require.NoError(t, err)
+
+	_, err = UnwrapKey(ctx, wrappedKey)
+	assert.Error(t, err)
+}
+
+func TestValidateWrappedKey(t *testing.T) {
+	tests := []struct {
+		wrappedKey string
+		wantErr    bool
+	}{
+		{wrappedKey: "file:Zm9v"},
+		{wrappedKey: "", wantErr: true},
+		{wrappedKey: "file:", wantErr: true},
+		{wrappedKey: "Zm9v", wantErr: true},
+		{wrappedKey: "vault:Zm9v", wantErr: true},
+		{wrappedKey: "file:not base64", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.wrappedKey, func(t *testing.T) {
+			err := ValidateWrappedKey(test.wrappedKey)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	_, err := NewKey(context.Background(), "vault", false)
+	assert.Error(t, err)
+}
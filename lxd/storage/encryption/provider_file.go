@@ -0,0 +1,127 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/canonical/lxd/shared"
+)
+
+// fileKeySize is the size in bytes of the key encryption key of the file key provider.
+const fileKeySize = 32
+
+// fileProvider is a key provider using a key encryption key stored in a local file.
+// In a cluster, the same file must be present on all members. The key encryption key is therefore only generated
+// automatically on standalone servers, as a key generated by one member couldn't be used by the others.
+type fileProvider struct {
+	path string
+	mu   sync.Mutex
+}
+
+func newFileProvider() (KeyProvider, error) {
+	return &fileProvider{path: shared.VarPath("storage-encryption.key")}, nil
+}
+
+// loadKey returns the key encryption key, generating it first if create is true and it doesn't exist.
+// The key is never generated if clustered is true, as it must then be identical on all cluster members.
+func (p *fileProvider) loadKey(create bool, clustered bool) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, err := os.ReadFile(p.path)
+	if errors.Is(err, os.ErrNotExist) && clustered {
+		return nil, fmt.Errorf("Key encryption key file %q is missing, it must contain %d random bytes and be copied to all cluster members", p.path, fileKeySize)
+	} else if errors.Is(err, os.ErrNotExist) && create {
+		key = make([]byte, fileKeySize)
+		_, err = rand.Read(key)
+		if err != nil {
+			return nil, fmt.Errorf("Failed generating key encryption key: %w", err)
+		}
+
+		f, err := os.OpenFile(p.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("Failed creating key encryption key file %q: %w", p.path, err)
+		}
+
+		_, err = f.Write(key)
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(p.path)
+			return nil, fmt.Errorf("Failed writing key encryption key file %q: %w", p.path, err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed writing key encryption key file %q: %w", p.path, err)
+		}
+
+		return key, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed reading key encryption key file %q: %w", p.path, err)
+	}
+
+	if len(key) != fileKeySize {
+		return nil, fmt.Errorf("Key encryption key file %q must contain %d bytes", p.path, fileKeySize)
+	}
+
+	return key, nil
+}
+
+// aead returns the cipher used to wrap keys.
+func (p *fileProvider) aead(create bool, clustered bool) (cipher.AEAD, error) {
+	key, err := p.loadKey(create, clustered)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// WrapKey encrypts a volume key with AES-GCM, prefixing the result with the random nonce used.
+// The key encryption key is generated along with the first key, unless clustered is true.
+func (p *fileProvider) WrapKey(ctx context.Context, key []byte, clustered bool) ([]byte, error) {
+	aead, err := p.aead(true, clustered)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, key, nil), nil
+}
+
+// UnwrapKey decrypts a volume key encrypted by WrapKey.
+func (p *fileProvider) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	aead, err := p.aead(false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, errors.New("Wrapped key is too short")
+	}
+
+	nonce, ciphertext := wrappedKey[:aead.NonceSize()], wrappedKey[aead.NonceSize():]
+
+	key, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Wrapped key wasn't wrapped by the key encryption key in %q, which must be identical on all cluster members", p.path)
+	}
+
+	return key, nil
+}
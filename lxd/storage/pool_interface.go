@@ -54,6 +54,8 @@ type Pool interface {
 	ToAPI() api.StoragePool
 
 	GetResources() (*api.ResourcesStoragePool, error)
+	Scrub(progressReporter ioprogress.ProgressReporter) error
+	VerifyVolume(projectName string, volName string, volType drivers.VolumeType, progressReporter ioprogress.ProgressReporter) (string, error)
 	IsUsed() (bool, error)
 	Delete(clientType request.ClientType, progressReporter ioprogress.ProgressReporter) error
 	Update(clientType request.ClientType, newDesc string, newConfig map[string]string, progressReporter ioprogress.ProgressReporter) error
@@ -85,9 +87,10 @@ type Pool interface {
 
 	MigrateInstance(ctx context.Context, inst instance.Instance, conn io.ReadWriteCloser, args *migration.VolumeSourceArgs, progressReporter ioprogress.ProgressReporter) error
 	RefreshInstance(ctx context.Context, inst instance.Instance, src instance.Instance, srcSnapshots []instance.Instance, allowInconsistent bool, progressReporter ioprogress.ProgressReporter) error
-	BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, version uint32, progressReporter ioprogress.ProgressReporter) error
+	BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, parentSnapshot string, version uint32, progressReporter ioprogress.ProgressReporter) error
 
 	GetInstanceUsage(inst instance.Instance) (*VolumeUsage, error)
+	CheckInstanceQuota(inst instance.Instance, oldSize string) (revert.Hook, error)
 	SetInstanceQuota(inst instance.Instance, size string, vmStateSize string, progressReporter ioprogress.ProgressReporter) error
 
 	MountInstance(inst instance.Instance, progressReporter ioprogress.ProgressReporter) (*MountInfo, error)
@@ -119,6 +122,7 @@ type Pool interface {
 	// Custom volumes.
 	CreateCustomVolume(ctx context.Context, projectName string, volName string, desc string, config map[string]string, contentType drivers.ContentType, progressReporter ioprogress.ProgressReporter) error
 	CreateCustomVolumeFromCopy(ctx context.Context, projectName, srcProjectName, volName, desc string, config map[string]string, srcPoolName, srcVolName string, snapshots bool, progressReporter ioprogress.ProgressReporter) error
+	CreateCustomVolumeFromClone(ctx context.Context, projectName, srcProjectName, volName, desc string, config map[string]string, srcVolName string, progressReporter ioprogress.ProgressReporter) error
 	UpdateCustomVolume(ctx context.Context, projectName string, volName string, newDesc string, newConfig map[string]string, progressReporter ioprogress.ProgressReporter) error
 	RenameCustomVolume(ctx context.Context, projectName string, volName string, newVolName string, progressReporter ioprogress.ProgressReporter) error
 	DeleteCustomVolume(ctx context.Context, projectName string, volName string, progressReporter ioprogress.ProgressReporter) error
@@ -0,0 +1,421 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRegion is the region used to sign requests when none is configured.
+const DefaultRegion = "us-east-1"
+
+// defaultPartSize is the initial size of the parts of multipart uploads. It grows as parts are uploaded so that the
+// maximum number of parts isn't reached for large objects.
+const defaultPartSize = 16 * 1024 * 1024
+
+// Client is a minimal S3 client for a single bucket of a remote S3 compatible storage, using path-style requests.
+type Client struct {
+	endpoint   *url.URL
+	bucket     string
+	region     string
+	accessKey  string
+	secretKey  string
+	httpClient *http.Client
+
+	// partSize is the initial size of the parts of multipart uploads, overridden in tests.
+	partSize int
+}
+
+// Object is an object listed in a bucket.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// NewClient returns a client for the bucket at the given endpoint URL. If httpClient is nil, http.DefaultClient is
+// used.
+func NewClient(endpoint string, bucket string, region string, accessKey string, secretKey string, httpClient *http.Client) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid S3 endpoint %q: %w", endpoint, err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("Invalid S3 endpoint %q: Must be an HTTP or HTTPS URL", endpoint)
+	}
+
+	if bucket == "" {
+		return nil, errors.New("S3 bucket name is required")
+	}
+
+	if region == "" {
+		region = DefaultRegion
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		endpoint:   u,
+		bucket:     bucket,
+		region:     region,
+		accessKey:  accessKey,
+		secretKey:  secretKey,
+		httpClient: httpClient,
+		partSize:   defaultPartSize,
+	}, nil
+}
+
+// do sends a signed request for the given object key (or the bucket itself if empty) and returns the response if
+// its status code is a success.
+func (c *Client) do(ctx context.Context, method string, key string, query url.Values, body []byte) (*http.Response, error) {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + c.bucket + "/" + key
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = query.Encode()
+
+	var reader io.Reader
+	payloadHash := emptyPayloadHash
+	if body != nil {
+		reader = bytes.NewReader(body)
+		hash := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(hash[:])
+	}
+
+	r, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+
+	SignRequest(r, c.accessKey, c.secretKey, c.region, payloadHash, time.Now())
+
+	resp, err := c.httpClient.Do(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		defer func() { _ = resp.Body.Close() }()
+
+		return nil, responseError(resp)
+	}
+
+	return resp, nil
+}
+
+// doXML sends a signed request and decodes its XML response into v.
+func (c *Client) doXML(ctx context.Context, method string, key string, query url.Values, body []byte, v any) error {
+	resp, err := c.do(ctx, method, key, query, body)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	err = xml.NewDecoder(io.LimitReader(resp.Body, maxXMLBodySize)).Decode(v)
+	if err != nil {
+		return fmt.Errorf("Failed decoding S3 response: %w", err)
+	}
+
+	return nil
+}
+
+// responseError returns the S3 error of a failed response.
+func responseError(resp *http.Response) error {
+	s3Err := &Error{StatusCode: resp.StatusCode, Code: http.StatusText(resp.StatusCode)}
+
+	var body struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+
+	err := xml.NewDecoder(io.LimitReader(resp.Body, maxXMLBodySize)).Decode(&body)
+	if err == nil && body.Code != "" {
+		s3Err.Code = body.Code
+		s3Err.Message = body.Message
+	}
+
+	return s3Err
+}
+
+// IsNotFound returns whether the error is an S3 error for a missing bucket, object or upload.
+func IsNotFound(err error) bool {
+	var s3Err *Error
+
+	return errors.As(err, &s3Err) && s3Err.StatusCode == http.StatusNotFound
+}
+
+// CheckBucket checks that the bucket exists and that the credentials give access to it.
+func (c *Client) CheckBucket(ctx context.Context) error {
+	resp, err := c.do(ctx, http.MethodHead, "", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// GetObject returns the content of an object along with its size.
+func (c *Client) GetObject(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return resp.Body, resp.ContentLength, nil
+}
+
+// PutObject stores an object in a single request.
+func (c *Client) PutObject(ctx context.Context, key string, data []byte) error {
+	if data == nil {
+		data = []byte{}
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, key, nil, data)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// DeleteObject deletes an object.
+func (c *Client) DeleteObject(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// ListObjects lists the objects whose key starts with prefix.
+func (c *Client) ListObjects(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+
+	query := url.Values{}
+	query.Set("list-type", "2")
+	query.Set("prefix", prefix)
+
+	for {
+		var result struct {
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+			Contents              []struct {
+				Key          string `xml:"Key"`
+				Size         int64  `xml:"Size"`
+				LastModified string `xml:"LastModified"`
+			} `xml:"Contents"`
+		}
+
+		err := c.doXML(ctx, http.MethodGet, "", query, nil, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range result.Contents {
+			lastModified, _ := time.Parse(time.RFC3339, entry.LastModified)
+			objects = append(objects, Object{Key: entry.Key, Size: entry.Size, LastModified: lastModified})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// NewObjectWriter returns a writer storing the data written to it as an object. The data is sent using a multipart
+// upload once it exceeds a single part, so the object size doesn't need to be known in advance. The object is only
+// created once the writer is closed, and Abort must be called instead if writing the data fails.
+func (c *Client) NewObjectWriter(ctx context.Context, key string) *ObjectWriter {
+	return &ObjectWriter{ctx: ctx, client: c, key: key, partSize: c.partSize}
+}
+
+// ObjectWriter writes an object, see Client.NewObjectWriter.
+type ObjectWriter struct {
+	ctx    context.Context
+	client *Client
+	key    string
+
+	buf      []byte
+	partSize int
+	uploadID string
+	parts    []completedPart
+	size     int64
+	err      error
+}
+
+// completedPart is an uploaded part of a multipart upload.
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// Write buffers the data and uploads full parts.
+func (w *ObjectWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), w.partSize-len(w.buf))
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+
+		if len(w.buf) < w.partSize {
+			continue
+		}
+
+		w.err = w.uploadPart()
+		if w.err != nil {
+			return written, w.err
+		}
+	}
+
+	w.size += int64(written)
+
+	return written, nil
+}
+
+// uploadPart uploads the buffered data as the next part of the multipart upload, creating the upload if needed.
+func (w *ObjectWriter) uploadPart() error {
+	if w.uploadID == "" {
+		var result struct {
+			UploadID string `xml:"UploadId"`
+		}
+
+		err := w.client.doXML(w.ctx, http.MethodPost, w.key, url.Values{"uploads": []string{""}}, nil, &result)
+		if err != nil {
+			return fmt.Errorf("Failed creating multipart upload: %w", err)
+		}
+
+		w.uploadID = result.UploadID
+	}
+
+	partNumber := len(w.parts) + 1
+	if partNumber > maxPartNumber {
+		return fmt.Errorf("Object exceeds the maximum number of parts (%d)", maxPartNumber)
+	}
+
+	query := url.Values{}
+	query.Set("partNumber", strconv.Itoa(partNumber))
+	query.Set("uploadId", w.uploadID)
+
+	resp, err := w.client.do(w.ctx, http.MethodPut, w.key, query, w.buf)
+	if err != nil {
+		return fmt.Errorf("Failed uploading part %d: %w", partNumber, err)
+	}
+
+	_ = resp.Body.Close()
+
+	w.parts = append(w.parts, completedPart{PartNumber: partNumber, ETag: resp.Header.Get("ETag")})
+	w.buf = w.buf[:0]
+
+	// Grow the parts every thousand parts so the part limit allows for very large objects.
+	if partNumber%1000 == 0 {
+		w.partSize *= 2
+	}
+
+	return nil
+}
+
+// Size returns the amount of data written so far.
+func (w *ObjectWriter) Size() int64 {
+	return w.size
+}
+
+// Close uploads the remaining data and creates the object.
+func (w *ObjectWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	// Small objects are sent in a single request.
+	if w.uploadID == "" {
+		w.err = w.client.PutObject(w.ctx, w.key, w.buf)
+		if w.err != nil {
+			return w.err
+		}
+
+		w.err = errors.New("Object writer is closed")
+
+		return nil
+	}
+
+	if len(w.buf) > 0 {
+		w.err = w.uploadPart()
+		if w.err != nil {
+			w.Abort()
+			return w.err
+		}
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: w.parts})
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+
+	// Errors can be reported in the body of a successful response once the upload is being completed.
+	err = w.client.doXML(w.ctx, http.MethodPost, w.key, url.Values{"uploadId": []string{w.uploadID}}, body, &result)
+	if err == nil && result.Code != "" {
+		err = &Error{StatusCode: http.StatusInternalServerError, Code: result.Code, Message: result.Message}
+	}
+
+	if err != nil {
+		w.err = fmt.Errorf("Failed completing multipart upload: %w", err)
+		w.Abort()
+		return w.err
+	}
+
+	w.uploadID = ""
+	w.err = errors.New("Object writer is closed")
+
+	return nil
+}
+
+// Abort discards the data written so far, cancelling the multipart upload if one was started.
+func (w *ObjectWriter) Abort() {
+	if w.err == nil {
+		w.err = errors.New("Object writer is aborted")
+	}
+
+	w.buf = nil
+	if w.uploadID == "" {
+		return
+	}
+
+	// Use a separate context as the upload context may be the reason for aborting.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := w.client.do(ctx, http.MethodDelete, w.key, url.Values{"uploadId": []string{w.uploadID}}, nil)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+
+	w.uploadID = ""
+}
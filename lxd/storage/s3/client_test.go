@@ -0,0 +1,116 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, tg *testGateway, accessKey string) *Client {
+	creds := tg.keys[accessKey]
+
+	client, err := NewClient(tg.server.URL, "mybucket", "", creds.AccessKey, creds.SecretKey, nil)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestClientObjects(t *testing.T) {
+	tg := newTestGateway(t)
+	client := newTestClient(t, tg, "admin")
+	ctx := context.Background()
+
+	require.NoError(t, client.CheckBucket(ctx))
+
+	require.NoError(t, client.PutObject(ctx, "backups/a b.tar.gz", []byte("hello")))
+	require.NoError(t, client.PutObject(ctx, "backups/c", nil))
+	require.NoError(t, client.PutObject(ctx, "other", []byte("world")))
+
+	objects, err := client.ListObjects(ctx, "backups/")
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "backups/a b.tar.gz", objects[0].Key)
+	assert.Equal(t, int64(5), objects[0].Size)
+	assert.False(t, objects[0].LastModified.IsZero())
+	assert.Equal(t, "backups/c", objects[1].Key)
+
+	reader, size, err := client.GetObject(ctx, "backups/a b.tar.gz")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, int64(5), size)
+
+	require.NoError(t, client.DeleteObject(ctx, "backups/a b.tar.gz"))
+	_, _, err = client.GetObject(ctx, "backups/a b.tar.gz")
+	assert.True(t, IsNotFound(err))
+
+	// Errors are returned with their S3 code.
+	err = newTestClient(t, tg, "reader").PutObject(ctx, "forbidden", []byte("data"))
+	var s3Err *Error
+	require.ErrorAs(t, err, &s3Err)
+	assert.Equal(t, http.StatusForbidden, s3Err.StatusCode)
+	assert.Equal(t, "AccessDenied", s3Err.Code)
+
+	_, err = NewClient("ftp://localhost", "mybucket", "", "admin", "adminsecret", nil)
+	assert.Error(t, err)
+}
+
+func TestClientObjectWriter(t *testing.T) {
+	tg := newTestGateway(t)
+	client := newTestClient(t, tg, "admin")
+	client.partSize = minPartSize
+	ctx := context.Background()
+
+	// Small objects are sent in a single request.
+	w := client.NewObjectWriter(ctx, "small")
+	_, err := w.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	reader, _, err := client.GetObject(ctx, "small")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	_ = reader.Close()
+	assert.Equal(t, "hello", string(data))
+
+	// Large objects are sent using a multipart upload.
+	payload := bytes.Repeat([]byte("0123456789"), (2*minPartSize+100)/10)
+	w = client.NewObjectWriter(ctx, "large")
+	_, err = io.Copy(w, bytes.NewReader(payload))
+	require.NoError(t, err)
+	assert.Len(t, w.parts, 2)
+	require.NoError(t, w.Close())
+	assert.Equal(t, int64(len(payload)), w.Size())
+
+	reader, size, err := client.GetObject(ctx, "large")
+	require.NoError(t, err)
+	data, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	_ = reader.Close()
+	assert.Equal(t, int64(len(payload)), size)
+	assert.True(t, bytes.Equal(payload, data))
+
+	// Aborted uploads don't leave anything behind.
+	w = client.NewObjectWriter(ctx, "aborted")
+	_, err = io.Copy(w, bytes.NewReader(payload))
+	require.NoError(t, err)
+	w.Abort()
+	assert.Error(t, w.Close())
+
+	_, _, err = client.GetObject(ctx, "aborted")
+	assert.True(t, IsNotFound(err))
+
+	entries, err := os.ReadDir(filepath.Join(tg.bucket.Path, internalDir, "uploads"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
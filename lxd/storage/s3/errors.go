@@ -0,0 +1,136 @@
+package s3
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"syscall"
+)
+
+// Error is an S3 error, as returned to clients in the XML error response.
+type Error struct {
+	Code       string
+	Message    string
+	StatusCode int
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func newError(statusCode int, code string, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), StatusCode: statusCode}
+}
+
+func errAccessDenied(format string, args ...any) *Error {
+	return newError(http.StatusForbidden, "AccessDenied", format, args...)
+}
+
+func errInvalidArgument(format string, args ...any) *Error {
+	return newError(http.StatusBadRequest, "InvalidArgument", format, args...)
+}
+
+func errInvalidRequest(format string, args ...any) *Error {
+	return newError(http.StatusBadRequest, "InvalidRequest", format, args...)
+}
+
+func errNotImplemented(format string, args ...any) *Error {
+	return newError(http.StatusNotImplemented, "NotImplemented", format, args...)
+}
+
+func errSignatureDoesNotMatch() *Error {
+	return newError(http.StatusForbidden, "SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided")
+}
+
+func errNoSuchBucket(bucketName string) *Error {
+	return newError(http.StatusNotFound, "NoSuchBucket", "The bucket %q does not exist", bucketName)
+}
+
+func errNoSuchKey() *Error {
+	return newError(http.StatusNotFound, "NoSuchKey", "The specified key does not exist")
+}
+
+func errNoSuchUpload() *Error {
+	return newError(http.StatusNotFound, "NoSuchUpload", "The specified multipart upload does not exist")
+}
+
+func errQuotaExceeded() *Error {
+	return newError(http.StatusBadRequest, "QuotaExceeded", "The bucket quota has been exceeded")
+}
+
+func errMissingContentLength() *Error {
+	return newError(http.StatusLengthRequired, "MissingContentLength", "You must provide the Content-Length HTTP header")
+}
+
+func errEntityTooLarge() *Error {
+	return newError(http.StatusBadRequest, "EntityTooLarge", "Your proposed upload exceeds the maximum allowed object size")
+}
+
+func errIncompleteBody() *Error {
+	return newError(http.StatusBadRequest, "IncompleteBody", "You did not provide the number of bytes specified by the Content-Length HTTP header")
+}
+
+func errBadDigest() *Error {
+	return newError(http.StatusBadRequest, "BadDigest", "The Content-MD5 you specified did not match what was received")
+}
+
+func errMalformedXML() *Error {
+	return newError(http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed or did not validate against the published schema")
+}
+
+func errInternal(err error) *Error {
+	return newError(http.StatusInternalServerError, "InternalError", "%v", err)
+}
+
+// toError converts any error to an S3 error.
+func toError(err error) *Error {
+	var s3Err *Error
+	if errors.As(err, &s3Err) {
+		return s3Err
+	}
+
+	if errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EDQUOT) {
+		return errQuotaExceeded()
+	}
+
+	return errInternal(err)
+}
+
+// writeError writes the XML error response of err.
+func writeError(w http.ResponseWriter, r *http.Request, requestID string, err error) {
+	s3Err := toError(err)
+
+	if r.Method == http.MethodHead {
+		w.WriteHeader(s3Err.StatusCode)
+		return
+	}
+
+	writeXML(w, s3Err.StatusCode, struct {
+		XMLName   xml.Name `xml:"Error"`
+		Code      string   `xml:"Code"`
+		Message   string   `xml:"Message"`
+		Resource  string   `xml:"Resource"`
+		RequestID string   `xml:"RequestId"`
+	}{
+		Code:      s3Err.Code,
+		Message:   s3Err.Message,
+		Resource:  r.URL.Path,
+		RequestID: requestID,
+	})
+}
+
+// writeXML writes an XML response.
+func writeXML(w http.ResponseWriter, statusCode int, v any) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,357 @@
+// Package s3 implements an S3 compatible API for buckets stored as plain files in a local directory.
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// xmlNamespace is the XML namespace of S3 responses.
+const xmlNamespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// maxXMLBodySize is the maximum size of XML request bodies.
+const maxXMLBodySize = 2 * 1024 * 1024
+
+// unsupportedSubresources are the subresources of buckets and objects which aren't implemented.
+var unsupportedSubresources = []string{"accelerate", "acl", "analytics", "attributes", "cors", "encryption", "intelligent-tiering", "inventory", "legal-hold", "lifecycle", "logging", "metrics", "notification", "object-lock", "ownershipControls", "policy", "policyStatus", "publicAccessBlock", "replication", "requestPayment", "restore", "retention", "select", "tagging", "torrent", "versions", "website"}
+
+// Credentials are the credentials of a bucket key.
+type Credentials struct {
+	AccessKey string
+	SecretKey string
+
+	// Bucket is the name of the bucket the credentials give access to.
+	Bucket string
+
+	// ReadOnly restricts the credentials to reading objects.
+	ReadOnly bool
+}
+
+// Bucket is a bucket stored in a local directory.
+type Bucket struct {
+	Name      string
+	Path      string
+	CreatedAt time.Time
+
+	// Quota is the maximum disk space used by the bucket in bytes, zero means unlimited.
+	Quota int64
+
+	// Usage returns the disk space used by the bucket. When not set, the size of the files of the bucket is used.
+	Usage func() (int64, error)
+}
+
+// Gateway serves the S3 API for local buckets.
+type Gateway struct {
+	// Credentials returns the credentials of an access key, or nil if the access key doesn't exist.
+	Credentials func(ctx context.Context, accessKey string) (*Credentials, error)
+
+	// OpenBucket makes a bucket available and returns it along with a function releasing it once the request is
+	// done, or a nil bucket if it doesn't exist.
+	OpenBucket func(ctx context.Context, bucketName string) (*Bucket, func(), error)
+
+	// GetBucket returns a bucket without making its files available, or nil if it doesn't exist.
+	GetBucket func(ctx context.Context, bucketName string) (*Bucket, error)
+
+	// now returns the current time, overridden in tests.
+	now func() time.Time
+
+	// quotas tracks the usage of the buckets, keyed by bucket name.
+	quotas   map[string]*bucketQuota
+	quotasMu sync.Mutex
+}
+
+// request is an authenticated request for a bucket.
+type request struct {
+	w     http.ResponseWriter
+	r     *http.Request
+	query url.Values
+	store *store
+	key   string
+
+	// body is the verified payload of the request and size its decoded size (-1 if unknown).
+	body io.Reader
+	size int64
+}
+
+// ServeHTTP handles an S3 request.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := strings.ToUpper(randomID()[:16])
+	w.Header().Set("Server", "LXD")
+	w.Header().Set("X-Amz-Request-Id", requestID)
+
+	err := g.serve(w, r)
+	if err != nil {
+		s3Err := toError(err)
+		if s3Err.StatusCode >= http.StatusInternalServerError {
+			logger.Warn("Failed handling storage bucket request", logger.Ctx{"method": r.Method, "url": r.URL.String(), "err": err})
+		}
+
+		writeError(w, r, requestID, s3Err)
+	}
+}
+
+// serve authenticates the request and dispatches it.
+func (g *Gateway) serve(w http.ResponseWriter, r *http.Request) error {
+	auth, err := parseAuth(r)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if g.now != nil {
+		now = g.now()
+	}
+
+	err = auth.checkTime(now)
+	if err != nil {
+		return err
+	}
+
+	creds, err := g.Credentials(r.Context(), auth.accessKey)
+	if err != nil {
+		return err
+	}
+
+	if creds == nil {
+		return newError(http.StatusForbidden, "InvalidAccessKeyId", "The access key ID you provided does not exist in our records")
+	}
+
+	signingKey, err := auth.verify(r, creds.SecretKey)
+	if err != nil {
+		return err
+	}
+
+	bucketName, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucketName == "" {
+		if r.Method != http.MethodGet {
+			return newError(http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource")
+		}
+
+		return g.listBuckets(w, r, creds)
+	}
+
+	if bucketName != creds.Bucket {
+		return errAccessDenied("Access to bucket %q is denied", bucketName)
+	}
+
+	if creds.ReadOnly && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return errAccessDenied("The key is read-only")
+	}
+
+	query := r.URL.Query()
+	for _, subresource := range unsupportedSubresources {
+		if query.Has(subresource) {
+			return errNotImplemented("The %q subresource is not supported", subresource)
+		}
+	}
+
+	bucket, release, err := g.OpenBucket(r.Context(), bucketName)
+	if err != nil {
+		return err
+	}
+
+	if bucket == nil {
+		return errNoSuchBucket(bucketName)
+	}
+
+	defer release()
+
+	s, err := openStore(bucket, g.bucketQuota(bucket))
+	if err != nil {
+		return err
+	}
+
+	defer s.close()
+
+	body, size, err := auth.payloadReader(r, signingKey)
+	if err != nil {
+		return err
+	}
+
+	req := &request{w: w, r: r, query: query, store: s, key: key, body: body, size: size}
+	if key == "" {
+		return req.serveBucket()
+	}
+
+	return req.serveObject()
+}
+
+// bucketQuota returns the usage tracker of a bucket.
+func (g *Gateway) bucketQuota(bucket *Bucket) *bucketQuota {
+	g.quotasMu.Lock()
+	defer g.quotasMu.Unlock()
+
+	if g.quotas == nil {
+		g.quotas = map[string]*bucketQuota{}
+	}
+
+	// Start over if the bucket was recreated.
+	q := g.quotas[bucket.Name]
+	if q == nil || !q.createdAt.Equal(bucket.CreatedAt) {
+		q = &bucketQuota{createdAt: bucket.CreatedAt}
+		g.quotas[bucket.Name] = q
+	}
+
+	return q
+}
+
+// listBuckets lists the bucket of the credentials.
+func (g *Gateway) listBuckets(w http.ResponseWriter, r *http.Request, creds *Credentials) error {
+	type bucketEntry struct {
+		Name         string `xml:"Name"`
+		CreationDate string `xml:"CreationDate"`
+	}
+
+	result := struct {
+		XMLName xml.Name      `xml:"ListAllMyBucketsResult"`
+		Xmlns   string        `xml:"xmlns,attr"`
+		Owner   owner         `xml:"Owner"`
+		Buckets []bucketEntry `xml:"Buckets>Bucket"`
+	}{
+		Xmlns: xmlNamespace,
+		Owner: defaultOwner,
+	}
+
+	bucket, err := g.GetBucket(r.Context(), creds.Bucket)
+	if err != nil {
+		return err
+	}
+
+	if bucket != nil {
+		result.Buckets = append(result.Buckets, bucketEntry{Name: bucket.Name, CreationDate: formatTime(bucket.CreatedAt)})
+	}
+
+	writeXML(w, http.StatusOK, result)
+
+	return nil
+}
+
+// serveBucket handles the requests targeting a bucket.
+func (req *request) serveBucket() error {
+	switch req.r.Method {
+	case http.MethodHead:
+		req.w.WriteHeader(http.StatusOK)
+		return nil
+	case http.MethodGet:
+		switch {
+		case req.query.Has("location"):
+			writeXML(req.w, http.StatusOK, struct {
+				XMLName xml.Name `xml:"LocationConstraint"`
+				Xmlns   string   `xml:"xmlns,attr"`
+			}{Xmlns: xmlNamespace})
+
+			return nil
+		case req.query.Has("versioning"):
+			writeXML(req.w, http.StatusOK, struct {
+				XMLName xml.Name `xml:"VersioningConfiguration"`
+				Xmlns   string   `xml:"xmlns,attr"`
+			}{Xmlns: xmlNamespace})
+
+			return nil
+		case req.query.Has("uploads"):
+			return req.listMultipartUploads()
+		case req.query.Get("list-type") == "2":
+			return req.listObjectsV2()
+		}
+
+		return req.listObjects()
+	case http.MethodPut:
+		if len(req.query) > 0 {
+			return errNotImplemented("Bucket configuration is not supported")
+		}
+
+		return newError(http.StatusConflict, "BucketAlreadyOwnedByYou", "Your previous request to create the named bucket succeeded and you already own it")
+	case http.MethodPost:
+		if req.query.Has("delete") {
+			return req.deleteObjects()
+		}
+	case http.MethodDelete:
+		return errNotImplemented("Buckets can only be deleted through the LXD API")
+	}
+
+	return newError(http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource")
+}
+
+// serveObject handles the requests targeting an object.
+func (req *request) serveObject() error {
+	uploadID := req.query.Get("uploadId")
+
+	switch req.r.Method {
+	case http.MethodGet, http.MethodHead:
+		if uploadID != "" && req.r.Method == http.MethodGet {
+			return req.listParts(uploadID)
+		}
+
+		return req.getObject()
+	case http.MethodPut:
+		if uploadID != "" {
+			return req.uploadPart(uploadID)
+		}
+
+		if req.r.Header.Get("X-Amz-Copy-Source") != "" {
+			return req.copyObject()
+		}
+
+		return req.putObject()
+	case http.MethodPost:
+		if req.query.Has("uploads") {
+			return req.createMultipartUpload()
+		}
+
+		if uploadID != "" {
+			return req.completeMultipartUpload(uploadID)
+		}
+	case http.MethodDelete:
+		if uploadID != "" {
+			return req.abortMultipartUpload(uploadID)
+		}
+
+		err := req.store.delete(req.key)
+		if err != nil {
+			return err
+		}
+
+		req.w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+
+	return newError(http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource")
+}
+
+// readXML decodes the XML body of the request into v.
+func (req *request) readXML(v any) error {
+	err := xml.NewDecoder(io.LimitReader(req.body, maxXMLBodySize)).Decode(v)
+	if err != nil {
+		return errMalformedXML()
+	}
+
+	// Read the rest of the body to verify its signature.
+	return req.drain()
+}
+
+// owner is the owner of buckets and objects.
+type owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+// defaultOwner is the owner reported for all buckets and objects.
+var defaultOwner = owner{ID: "lxd", DisplayName: "lxd"}
+
+// formatTime formats a time as expected in XML responses.
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// quoteETag returns the quoted form of an ETag used in headers and XML responses.
+func quoteETag(etag string) string {
+	return `"` + etag + `"`
+}
@@ -0,0 +1,496 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Hex(data string) string {
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+// testGateway is a gateway serving a single bucket from a temporary directory.
+type testGateway struct {
+	t      *testing.T
+	server *httptest.Server
+	bucket *Bucket
+	keys   map[string]*Credentials
+}
+
+func newTestGateway(t *testing.T) *testGateway {
+	tg := &testGateway{
+		t:      t,
+		bucket: &Bucket{Name: "mybucket", Path: t.TempDir(), CreatedAt: time.Now()},
+		keys: map[string]*Credentials{
+			"admin":    {AccessKey: "admin", SecretKey: "adminsecret", Bucket: "mybucket"},
+			"reader":   {AccessKey: "reader", SecretKey: "readersecret", Bucket: "mybucket", ReadOnly: true},
+			"other":    {AccessKey: "other", SecretKey: "othersecret", Bucket: "otherbucket"},
+			"notfound": nil,
+		},
+	}
+
+	gateway := &Gateway{
+		Credentials: func(ctx context.Context, accessKey string) (*Credentials, error) {
+			return tg.keys[accessKey], nil
+		},
+		OpenBucket: func(ctx context.Context, bucketName string) (*Bucket, func(), error) {
+			if bucketName != tg.bucket.Name {
+				return nil, nil, nil
+			}
+
+			return tg.bucket, func() {}, nil
+		},
+		GetBucket: func(ctx context.Context, bucketName string) (*Bucket, error) {
+			if bucketName != tg.bucket.Name {
+				return nil, nil
+			}
+
+			return tg.bucket, nil
+		},
+	}
+
+	tg.server = httptest.NewServer(gateway)
+	t.Cleanup(tg.server.Close)
+
+	return tg
+}
+
+// do performs a request signed with the given key.
+func (tg *testGateway) do(accessKey string, method string, path string, body string, headers map[string]string) *http.Response {
+	r, err := http.NewRequest(method, tg.server.URL+path, strings.NewReader(body))
+	require.NoError(tg.t, err)
+
+	for name, value := range headers {
+		r.Header.Set(name, value)
+	}
+
+	secretKey := "invalid"
+	creds := tg.keys[accessKey]
+	if creds != nil {
+		secretKey = creds.SecretKey
+	}
+
+	SignRequest(r, accessKey, secretKey, "us-east-1", sha256Hex(body), time.Now())
+
+	resp, err := http.DefaultClient.Do(r)
+	require.NoError(tg.t, err)
+
+	tg.t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return resp
+}
+
+// errorCode returns the S3 error code of a response.
+func errorCode(t *testing.T, resp *http.Response) string {
+	result := struct {
+		Code string `xml:"Code"`
+	}{}
+
+	require.NoError(t, xml.NewDecoder(resp.Body).Decode(&result))
+
+	return result.Code
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestGatewayObjects(t *testing.T) {
+	tg := newTestGateway(t)
+
+	resp := tg.do("admin", http.MethodPut, "/mybucket/dir/hello.txt", "hello", map[string]string{"Content-Type": "text/plain", "X-Amz-Meta-Owner": "ci"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	md5Sum := md5.Sum([]byte("hello"))
+	assert.Equal(t, quoteETag(hex.EncodeToString(md5Sum[:])), resp.Header.Get("ETag"))
+
+	// Objects are plain files.
+	data, err := os.ReadFile(filepath.Join(tg.bucket.Path, "dir", "hello.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	resp = tg.do("admin", http.MethodGet, "/mybucket/dir/hello.txt", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "hello", readBody(t, resp))
+	assert.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "ci", resp.Header.Get("X-Amz-Meta-Owner"))
+	assert.Equal(t, quoteETag(hex.EncodeToString(md5Sum[:])), resp.Header.Get("ETag"))
+
+	resp = tg.do("admin", http.MethodGet, "/mybucket/dir/hello.txt", "", map[string]string{"Range": "bytes=1-2"})
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "el", readBody(t, resp))
+
+	resp = tg.do("admin", http.MethodHead, "/mybucket/dir/hello.txt", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(5), resp.ContentLength)
+
+	resp = tg.do("admin", http.MethodGet, "/mybucket/missing", "", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "NoSuchKey", errorCode(t, resp))
+
+	// Files added directly to the volume are objects too.
+	require.NoError(t, os.WriteFile(filepath.Join(tg.bucket.Path, "direct.txt"), []byte("direct"), 0644))
+	resp = tg.do("admin", http.MethodGet, "/mybucket/direct.txt", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "direct", readBody(t, resp))
+	assert.NotEmpty(t, resp.Header.Get("ETag"))
+
+	// Copy with the same metadata.
+	resp = tg.do("admin", http.MethodPut, "/mybucket/copy.txt", "", map[string]string{"X-Amz-Copy-Source": "/mybucket/dir/hello.txt"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = tg.do("admin", http.MethodGet, "/mybucket/copy.txt", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "hello", readBody(t, resp))
+	assert.Equal(t, "ci", resp.Header.Get("X-Amz-Meta-Owner"))
+
+	// Delete.
+	resp = tg.do("admin", http.MethodDelete, "/mybucket/dir/hello.txt", "", nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	_, err = os.Stat(filepath.Join(tg.bucket.Path, "dir"))
+	assert.True(t, os.IsNotExist(err), "Empty parent directories should be removed")
+
+	deleteBody := `<Delete><Object><Key>copy.txt</Key></Object><Object><Key>missing</Key></Object></Delete>`
+	resp = tg.do("admin", http.MethodPost, "/mybucket?delete", deleteBody, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, readBody(t, resp), "<Deleted><Key>copy.txt</Key></Deleted>")
+
+	resp = tg.do("admin", http.MethodHead, "/mybucket/copy.txt", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestGatewayList(t *testing.T) {
+	tg := newTestGateway(t)
+
+	for _, key := range []string{"a.txt", "a/b.txt", "a/c/d.txt", "b.txt", "c d.txt"} {
+		resp := tg.do("admin", http.MethodPut, "/mybucket/"+uriEncode(key, false), key, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// Internal files and lost+found are hidden.
+	require.NoError(t, os.Mkdir(filepath.Join(tg.bucket.Path, "lost+found"), 0700))
+
+	type listResult struct {
+		Keys                  []string `xml:"Contents>Key"`
+		Prefixes              []string `xml:"CommonPrefixes>Prefix"`
+		IsTruncated           bool     `xml:"IsTruncated"`
+		NextMarker            string   `xml:"NextMarker"`
+		NextContinuationToken string   `xml:"NextContinuationToken"`
+	}
+
+	list := func(query string) listResult {
+		resp := tg.do("admin", http.MethodGet, "/mybucket?"+query, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		result := listResult{}
+		require.NoError(t, xml.NewDecoder(resp.Body).Decode(&result))
+
+		return result
+	}
+
+	result := list("")
+	assert.Equal(t, []string{"a.txt", "a/b.txt", "a/c/d.txt", "b.txt", "c d.txt"}, result.Keys)
+	assert.False(t, result.IsTruncated)
+
+	result = list("delimiter=%2F")
+	assert.Equal(t, []string{"a.txt", "b.txt", "c d.txt"}, result.Keys)
+	assert.Equal(t, []string{"a/"}, result.Prefixes)
+
+	result = list("list-type=2&prefix=a%2F&delimiter=%2F")
+	assert.Equal(t, []string{"a/b.txt"}, result.Keys)
+	assert.Equal(t, []string{"a/c/"}, result.Prefixes)
+
+	result = list("encoding-type=url&prefix=c")
+	assert.Equal(t, []string{"c%20d.txt"}, result.Keys)
+
+	// Pagination.
+	result = list("max-keys=2&delimiter=%2F")
+	assert.Equal(t, []string{"a.txt"}, result.Keys)
+	assert.Equal(t, []string{"a/"}, result.Prefixes)
+	assert.True(t, result.IsTruncated)
+
+	result = list("max-keys=2&delimiter=%2F&marker=" + result.NextMarker)
+	assert.Equal(t, []string{"b.txt", "c d.txt"}, result.Keys)
+	assert.False(t, result.IsTruncated)
+
+	var keys []string
+	token := ""
+	for {
+		result = list("list-type=2&max-keys=2&continuation-token=" + token)
+		keys = append(keys, result.Keys...)
+		if !result.IsTruncated {
+			break
+		}
+
+		token = result.NextContinuationToken
+	}
+
+	assert.Equal(t, []string{"a.txt", "a/b.txt", "a/c/d.txt", "b.txt", "c d.txt"}, keys)
+
+	// Buckets.
+	resp := tg.do("admin", http.MethodGet, "/", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, readBody(t, resp), "<Name>mybucket</Name>")
+}
+
+func TestGatewayKeys(t *testing.T) {
+	tg := newTestGateway(t)
+
+	for _, key := range []string{"dir/", "a//b", "a/./b", "a/../b", ".lxd-s3/metadata", "lost+found/x"} {
+		resp := tg.do("admin", http.MethodPut, "/mybucket/"+key, "data", nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, key)
+	}
+
+	resp := tg.do("admin", http.MethodPut, "/mybucket/file", "data", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// A key can't be nested under an object.
+	resp = tg.do("admin", http.MethodPut, "/mybucket/file/nested", "data", nil)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "InvalidArgument", errorCode(t, resp))
+
+	// Symlinks can't escape the bucket.
+	require.NoError(t, os.Symlink("/etc/hostname", filepath.Join(tg.bucket.Path, "escape")))
+	resp = tg.do("admin", http.MethodGet, "/mybucket/escape", "", nil)
+	assert.NotEqual(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGatewayAuth(t *testing.T) {
+	tg := newTestGateway(t)
+
+	resp := tg.do("notfound", http.MethodGet, "/mybucket", "", nil)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "InvalidAccessKeyId", errorCode(t, resp))
+
+	tg.keys["admin"] = &Credentials{AccessKey: "admin", SecretKey: "changed", Bucket: "mybucket"}
+	r, err := http.NewRequest(http.MethodGet, tg.server.URL+"/mybucket", nil)
+	require.NoError(t, err)
+	SignRequest(r, "admin", "adminsecret", "us-east-1", emptyPayloadHash, time.Now())
+	resp, err = http.DefaultClient.Do(r)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "SignatureDoesNotMatch", errorCode(t, resp))
+
+	tg.keys["admin"].SecretKey = "adminsecret"
+
+	resp = tg.do("admin", http.MethodPut, "/mybucket/key", "data", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Read-only keys can only read.
+	resp = tg.do("reader", http.MethodGet, "/mybucket/key", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = tg.do("reader", http.MethodPut, "/mybucket/key", "data", nil)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "AccessDenied", errorCode(t, resp))
+
+	resp = tg.do("reader", http.MethodDelete, "/mybucket/key", "", nil)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// Keys only give access to their bucket.
+	resp = tg.do("other", http.MethodGet, "/mybucket/key", "", nil)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "AccessDenied", errorCode(t, resp))
+
+	// Unsupported subresources.
+	resp = tg.do("admin", http.MethodGet, "/mybucket?policy", "", nil)
+	require.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
+
+func TestGatewayQuota(t *testing.T) {
+	tg := newTestGateway(t)
+	tg.bucket.Quota = 1024
+
+	resp := tg.do("admin", http.MethodPut, "/mybucket/first", strings.Repeat("a", 600), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = tg.do("admin", http.MethodPut, "/mybucket/second", strings.Repeat("a", 600), nil)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "QuotaExceeded", errorCode(t, resp))
+
+	// Replacing an object only accounts for the size difference.
+	resp = tg.do("admin", http.MethodPut, "/mybucket/first", strings.Repeat("a", 700), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGatewayQuotaTracking(t *testing.T) {
+	tg := newTestGateway(t)
+	tg.bucket.Quota = 1024
+
+	var measured atomic.Int64
+	tg.bucket.Usage = func() (int64, error) {
+		measured.Add(1)
+		return DirUsage(tg.bucket.Path)
+	}
+
+	// Concurrent writes can't exceed the quota together.
+	var wg sync.WaitGroup
+	var succeeded atomic.Int64
+	for i := range 8 {
+		wg.Go(func() {
+			resp := tg.do("admin", http.MethodPut, fmt.Sprintf("/mybucket/key%d", i), strings.Repeat("a", 600), nil)
+			if resp.StatusCode == http.StatusOK {
+				succeeded.Add(1)
+			}
+		})
+	}
+
+	wg.Wait()
+	assert.Equal(t, int64(1), succeeded.Load())
+
+	// The usage is tracked as objects are written and deleted rather than measured on every write.
+	keys, err := filepath.Glob(filepath.Join(tg.bucket.Path, "key*"))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+
+	resp := tg.do("admin", http.MethodDelete, "/mybucket/"+filepath.Base(keys[0]), "", nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = tg.do("admin", http.MethodPut, "/mybucket/other", strings.Repeat("a", 900), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = tg.do("admin", http.MethodPut, "/mybucket/more", strings.Repeat("a", 200), nil)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	assert.Equal(t, int64(1), measured.Load())
+}
+
+func TestGatewayContentMD5(t *testing.T) {
+	tg := newTestGateway(t)
+
+	sum := md5.Sum([]byte("data"))
+	resp := tg.do("admin", http.MethodPut, "/mybucket/key", "data", map[string]string{"Content-MD5": base64.StdEncoding.EncodeToString(sum[:])})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = tg.do("admin", http.MethodPut, "/mybucket/key", "other", map[string]string{"Content-MD5": base64.StdEncoding.EncodeToString(sum[:])})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "BadDigest", errorCode(t, resp))
+}
+
+func TestGatewayMultipart(t *testing.T) {
+	tg := newTestGateway(t)
+
+	resp := tg.do("admin", http.MethodPost, "/mybucket/big?uploads", "", map[string]string{"Content-Type": "application/x-tar"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	initiate := struct {
+		UploadID string `xml:"UploadId"`
+	}{}
+
+	require.NoError(t, xml.NewDecoder(resp.Body).Decode(&initiate))
+	require.NotEmpty(t, initiate.UploadID)
+
+	parts := []string{strings.Repeat("a", minPartSize), "tail"}
+	etags := make([]string, len(parts))
+	for i, part := range parts {
+		resp = tg.do("admin", http.MethodPut, fmt.Sprintf("/mybucket/big?partNumber=%d&uploadId=%s", i+1, initiate.UploadID), part, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		etags[i] = resp.Header.Get("ETag")
+	}
+
+	resp = tg.do("admin", http.MethodGet, "/mybucket/big?uploadId="+initiate.UploadID, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, strings.Count(readBody(t, resp), "<Part>"))
+
+	resp = tg.do("admin", http.MethodGet, "/mybucket?uploads", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, readBody(t, resp), initiate.UploadID)
+
+	// Parts must be listed in order.
+	complete := fmt.Sprintf("<CompleteMultipartUpload><Part><PartNumber>2</PartNumber><ETag>%s</ETag></Part><Part><PartNumber>1</PartNumber><ETag>%s</ETag></Part></CompleteMultipartUpload>", etags[1], etags[0])
+	resp = tg.do("admin", http.MethodPost, "/mybucket/big?uploadId="+initiate.UploadID, complete, nil)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "InvalidPartOrder", errorCode(t, resp))
+
+	complete = fmt.Sprintf("<CompleteMultipartUpload><Part><PartNumber>1</PartNumber><ETag>%s</ETag></Part><Part><PartNumber>2</PartNumber><ETag>%s</ETag></Part></CompleteMultipartUpload>", etags[0], etags[1])
+	resp = tg.do("admin", http.MethodPost, "/mybucket/big?uploadId="+initiate.UploadID, complete, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var md5s []byte
+	for _, part := range parts {
+		sum := md5.Sum([]byte(part))
+		md5s = append(md5s, sum[:]...)
+	}
+
+	result := struct {
+		ETag string `xml:"ETag"`
+	}{}
+
+	require.NoError(t, xml.NewDecoder(resp.Body).Decode(&result))
+	sum := md5.Sum(md5s)
+	assert.Equal(t, quoteETag(hex.EncodeToString(sum[:])+"-2"), result.ETag)
+
+	resp = tg.do("admin", http.MethodGet, "/mybucket/big", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-tar", resp.Header.Get("Content-Type"))
+	assert.Equal(t, strings.Join(parts, ""), readBody(t, resp))
+
+	// The upload is gone once completed.
+	resp = tg.do("admin", http.MethodDelete, "/mybucket/big?uploadId="+initiate.UploadID, "", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "NoSuchUpload", errorCode(t, resp))
+
+	entries, err := os.ReadDir(filepath.Join(tg.bucket.Path, internalDir, "uploads"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestGatewayChunkedUpload(t *testing.T) {
+	tg := newTestGateway(t)
+
+	now := time.Now()
+	body := &bytes.Buffer{}
+
+	r, err := http.NewRequest(http.MethodPut, tg.server.URL+"/mybucket/chunked", body)
+	require.NoError(t, err)
+	r.Header.Set("Content-Encoding", "aws-chunked")
+	r.Header.Set("X-Amz-Decoded-Content-Length", "11")
+	SignRequest(r, "admin", "adminsecret", "us-east-1", streamingPayload, now)
+
+	// Sign the chunks, chaining from the request signature.
+	_, seed, _ := strings.Cut(r.Header.Get("Authorization"), "Signature=")
+	key := signingKey("adminsecret", now.UTC().Format(yyyymmdd), "us-east-1")
+	scope := now.UTC().Format(yyyymmdd) + "/us-east-1/s3/aws4_request"
+	prev := seed
+	for _, chunk := range []string{"hello ", "world", ""} {
+		toSign := signV4Algorithm + "-PAYLOAD\n" + now.UTC().Format(iso8601Format) + "\n" + scope + "\n" + prev + "\n" + emptyPayloadHash + "\n" + sha256Hex(chunk)
+		prev = hex.EncodeToString(hmacSHA256(key, toSign))
+		fmt.Fprintf(body, "%x;chunk-signature=%s\r\n%s\r\n", len(chunk), prev, chunk)
+	}
+
+	r.ContentLength = int64(body.Len())
+	r.Body = io.NopCloser(bytes.NewReader(body.Bytes()))
+
+	resp, err := http.DefaultClient.Do(r)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	data, err := os.ReadFile(filepath.Join(tg.bucket.Path, "chunked"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(data))
+}
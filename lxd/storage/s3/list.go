@@ -0,0 +1,272 @@
+package s3
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxListKeys is the maximum number of entries returned by a listing.
+const maxListKeys = 1000
+
+// listEntry is an object in a listing.
+type listEntry struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+	Owner        *owner `xml:"Owner,omitempty"`
+}
+
+// commonPrefix is a group of objects sharing a prefix up to the delimiter in a listing.
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// listResult is a page of a listing.
+type listResult struct {
+	objects   []*objectInfo
+	prefixes  []string
+	truncated bool
+	next      string
+}
+
+// parseMaxKeys parses a maximum number of entries parameter.
+func parseMaxKeys(value string) (int, error) {
+	if value == "" {
+		return maxListKeys, nil
+	}
+
+	maxKeys, err := strconv.Atoi(value)
+	if err != nil || maxKeys < 0 {
+		return -1, errInvalidArgument("Invalid maximum number of entries %q", value)
+	}
+
+	return min(maxKeys, maxListKeys), nil
+}
+
+// list returns the objects and common prefixes after marker, up to maxKeys entries.
+func (req *request) list(prefix string, delimiter string, marker string, maxKeys int) (*listResult, error) {
+	keys, err := req.store.list(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &listResult{}
+	count := 0
+	for _, key := range keys {
+		if key <= marker {
+			continue
+		}
+
+		entry := key
+		isPrefix := false
+		if delimiter != "" {
+			i := strings.Index(key[len(prefix):], delimiter)
+			if i >= 0 {
+				entry = key[:len(prefix)+i+len(delimiter)]
+				isPrefix = true
+			}
+		}
+
+		if isPrefix && (entry <= marker || (len(result.prefixes) > 0 && result.prefixes[len(result.prefixes)-1] == entry)) {
+			// Common prefix already returned.
+			continue
+		}
+
+		if count == maxKeys {
+			result.truncated = true
+			break
+		}
+
+		if isPrefix {
+			result.prefixes = append(result.prefixes, entry)
+		} else {
+			info, err := req.store.stat(key)
+			if err != nil {
+				// The object was removed in the meantime.
+				continue
+			}
+
+			result.objects = append(result.objects, info)
+		}
+
+		result.next = entry
+		count++
+	}
+
+	return result, nil
+}
+
+// encoder returns the function encoding keys in listings as requested by the encoding-type parameter.
+func (req *request) encoder() (func(string) string, error) {
+	switch req.query.Get("encoding-type") {
+	case "":
+		return func(s string) string { return s }, nil
+	case "url":
+		return func(s string) string { return uriEncode(s, false) }, nil
+	}
+
+	return nil, errInvalidArgument("Invalid encoding type")
+}
+
+// entries returns the XML entries of a listing.
+func (r *listResult) entries(encode func(string) string, withOwner bool) ([]listEntry, []commonPrefix) {
+	contents := make([]listEntry, 0, len(r.objects))
+	for _, info := range r.objects {
+		entry := listEntry{
+			Key:          encode(info.key),
+			LastModified: formatTime(info.modTime),
+			ETag:         quoteETag(info.etag),
+			Size:         info.size,
+			StorageClass: "STANDARD",
+		}
+
+		if withOwner {
+			entry.Owner = &defaultOwner
+		}
+
+		contents = append(contents, entry)
+	}
+
+	prefixes := make([]commonPrefix, 0, len(r.prefixes))
+	for _, prefix := range r.prefixes {
+		prefixes = append(prefixes, commonPrefix{Prefix: encode(prefix)})
+	}
+
+	return contents, prefixes
+}
+
+// listObjects lists the objects of the bucket (ListObjects version 1).
+func (req *request) listObjects() error {
+	encode, err := req.encoder()
+	if err != nil {
+		return err
+	}
+
+	maxKeys, err := parseMaxKeys(req.query.Get("max-keys"))
+	if err != nil {
+		return err
+	}
+
+	prefix := req.query.Get("prefix")
+	delimiter := req.query.Get("delimiter")
+	marker := req.query.Get("marker")
+
+	result, err := req.list(prefix, delimiter, marker, maxKeys)
+	if err != nil {
+		return err
+	}
+
+	contents, prefixes := result.entries(encode, true)
+
+	response := struct {
+		XMLName        xml.Name       `xml:"ListBucketResult"`
+		Xmlns          string         `xml:"xmlns,attr"`
+		Name           string         `xml:"Name"`
+		Prefix         string         `xml:"Prefix"`
+		Marker         string         `xml:"Marker"`
+		NextMarker     string         `xml:"NextMarker,omitempty"`
+		MaxKeys        int            `xml:"MaxKeys"`
+		Delimiter      string         `xml:"Delimiter,omitempty"`
+		IsTruncated    bool           `xml:"IsTruncated"`
+		EncodingType   string         `xml:"EncodingType,omitempty"`
+		Contents       []listEntry    `xml:"Contents"`
+		CommonPrefixes []commonPrefix `xml:"CommonPrefixes"`
+	}{
+		Xmlns:          xmlNamespace,
+		Name:           req.store.bucket.Name,
+		Prefix:         encode(prefix),
+		Marker:         encode(marker),
+		MaxKeys:        maxKeys,
+		Delimiter:      encode(delimiter),
+		IsTruncated:    result.truncated,
+		EncodingType:   req.query.Get("encoding-type"),
+		Contents:       contents,
+		CommonPrefixes: prefixes,
+	}
+
+	if result.truncated {
+		response.NextMarker = encode(result.next)
+	}
+
+	writeXML(req.w, http.StatusOK, response)
+
+	return nil
+}
+
+// listObjectsV2 lists the objects of the bucket (ListObjects version 2).
+func (req *request) listObjectsV2() error {
+	encode, err := req.encoder()
+	if err != nil {
+		return err
+	}
+
+	maxKeys, err := parseMaxKeys(req.query.Get("max-keys"))
+	if err != nil {
+		return err
+	}
+
+	prefix := req.query.Get("prefix")
+	delimiter := req.query.Get("delimiter")
+	startAfter := req.query.Get("start-after")
+	continuationToken := req.query.Get("continuation-token")
+
+	marker := startAfter
+	if continuationToken != "" {
+		token, err := base64.RawURLEncoding.DecodeString(continuationToken)
+		if err != nil {
+			return errInvalidArgument("The continuation token provided is incorrect")
+		}
+
+		marker = string(token)
+	}
+
+	result, err := req.list(prefix, delimiter, marker, maxKeys)
+	if err != nil {
+		return err
+	}
+
+	contents, prefixes := result.entries(encode, req.query.Get("fetch-owner") == "true")
+
+	response := struct {
+		XMLName               xml.Name       `xml:"ListBucketResult"`
+		Xmlns                 string         `xml:"xmlns,attr"`
+		Name                  string         `xml:"Name"`
+		Prefix                string         `xml:"Prefix"`
+		StartAfter            string         `xml:"StartAfter,omitempty"`
+		ContinuationToken     string         `xml:"ContinuationToken,omitempty"`
+		NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+		KeyCount              int            `xml:"KeyCount"`
+		MaxKeys               int            `xml:"MaxKeys"`
+		Delimiter             string         `xml:"Delimiter,omitempty"`
+		IsTruncated           bool           `xml:"IsTruncated"`
+		EncodingType          string         `xml:"EncodingType,omitempty"`
+		Contents              []listEntry    `xml:"Contents"`
+		CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+	}{
+		Xmlns:             xmlNamespace,
+		Name:              req.store.bucket.Name,
+		Prefix:            encode(prefix),
+		StartAfter:        encode(startAfter),
+		ContinuationToken: continuationToken,
+		KeyCount:          len(contents) + len(prefixes),
+		MaxKeys:           maxKeys,
+		Delimiter:         encode(delimiter),
+		IsTruncated:       result.truncated,
+		EncodingType:      req.query.Get("encoding-type"),
+		Contents:          contents,
+		CommonPrefixes:    prefixes,
+	}
+
+	if result.truncated {
+		response.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(result.next))
+	}
+
+	writeXML(req.w, http.StatusOK, response)
+
+	return nil
+}
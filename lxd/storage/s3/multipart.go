@@ -0,0 +1,556 @@
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Multipart upload limits.
+const (
+	maxPartNumber = 10000
+	minPartSize   = 5 * 1024 * 1024
+)
+
+// uploadIDPattern matches valid multipart upload IDs.
+var uploadIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// partFilePattern matches the metadata files of uploaded parts.
+var partFilePattern = regexp.MustCompile(`^([0-9]{5})\.json$`)
+
+// uploadMetadata is the metadata of a multipart upload.
+type uploadMetadata struct {
+	Key       string            `json:"key"`
+	Initiated time.Time         `json:"initiated"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+// partMetadata is the metadata of an uploaded part.
+type partMetadata struct {
+	ETag    string    `json:"etag"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// uploadPartEntry is an entry of a multipart upload part listing.
+type uploadPartEntry struct {
+	PartNumber   int    `xml:"PartNumber"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+}
+
+// uploadDir returns the directory holding the parts of a multipart upload.
+func uploadDir(uploadID string) string {
+	return path.Join(internalDir, "uploads", uploadID)
+}
+
+// partPath returns the path of the data of a part, its metadata file being the same path with a ".json" suffix.
+func partPath(uploadID string, partNumber int) string {
+	return path.Join(uploadDir(uploadID), fmt.Sprintf("%05d", partNumber))
+}
+
+// loadUpload returns the metadata of a multipart upload of the object at key.
+func (s *store) loadUpload(uploadID string, key string) (*uploadMetadata, error) {
+	if !uploadIDPattern.MatchString(uploadID) {
+		return nil, errNoSuchUpload()
+	}
+
+	data, err := s.root.ReadFile(path.Join(uploadDir(uploadID), "upload.json"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errNoSuchUpload()
+		}
+
+		return nil, err
+	}
+
+	upload := &uploadMetadata{}
+	err = json.Unmarshal(data, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != "" && upload.Key != key {
+		return nil, errNoSuchUpload()
+	}
+
+	return upload, nil
+}
+
+// loadParts returns the uploaded parts of a multipart upload, indexed by part number.
+func (s *store) loadParts(uploadID string) (map[int]*partMetadata, error) {
+	entries, err := fs.ReadDir(s.root.FS(), uploadDir(uploadID))
+	if err != nil {
+		return nil, err
+	}
+
+	parts := map[int]*partMetadata{}
+	for _, entry := range entries {
+		match := partFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		partNumber, _ := strconv.Atoi(match[1])
+
+		data, err := s.root.ReadFile(path.Join(uploadDir(uploadID), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		part := &partMetadata{}
+		err = json.Unmarshal(data, part)
+		if err != nil {
+			return nil, err
+		}
+
+		parts[partNumber] = part
+	}
+
+	return parts, nil
+}
+
+// createMultipartUpload starts a multipart upload.
+func (req *request) createMultipartUpload() error {
+	err := validateKey(req.key)
+	if err != nil {
+		return err
+	}
+
+	err = req.drain()
+	if err != nil {
+		return err
+	}
+
+	uploadID := randomID()
+	err = req.store.root.MkdirAll(uploadDir(uploadID), 0700)
+	if err != nil {
+		return err
+	}
+
+	upload := uploadMetadata{
+		Key:       req.key,
+		Initiated: time.Now().UTC(),
+		Headers:   uploadHeaders(req.r.Header),
+	}
+
+	err = req.store.writeJSON(path.Join(uploadDir(uploadID), "upload.json"), upload)
+	if err != nil {
+		_ = req.store.root.RemoveAll(uploadDir(uploadID))
+		return err
+	}
+
+	writeXML(req.w, http.StatusOK, struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Xmlns    string   `xml:"xmlns,attr"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		UploadID string   `xml:"UploadId"`
+	}{
+		Xmlns:    xmlNamespace,
+		Bucket:   req.store.bucket.Name,
+		Key:      req.key,
+		UploadID: uploadID,
+	})
+
+	return nil
+}
+
+// uploadPart stores a part of a multipart upload.
+func (req *request) uploadPart(uploadID string) error {
+	partNumber, err := strconv.Atoi(req.query.Get("partNumber"))
+	if err != nil || partNumber < 1 || partNumber > maxPartNumber {
+		return errInvalidArgument("Part number must be an integer between 1 and %d", maxPartNumber)
+	}
+
+	if req.r.Header.Get("X-Amz-Copy-Source") != "" {
+		return errNotImplemented("Copying parts is not supported")
+	}
+
+	_, err = req.store.loadUpload(uploadID, req.key)
+	if err != nil {
+		return err
+	}
+
+	err = checkUploadSize(req.size)
+	if err != nil {
+		return err
+	}
+
+	partName := partPath(uploadID, partNumber)
+	done, err := req.store.reserveQuota(req.size, req.store.existingSize(partName))
+	if err != nil {
+		return err
+	}
+
+	tmpName, sum, err := req.store.writeTemp(req.body, req.size)
+	if err != nil {
+		done(false)
+		return err
+	}
+
+	err = checkContentMD5(req.r.Header, sum)
+	if err == nil {
+		err = req.store.root.Rename(tmpName, partName)
+	}
+
+	done(err == nil)
+	if err != nil {
+		_ = req.store.root.Remove(tmpName)
+		return err
+	}
+
+	part := partMetadata{
+		ETag:    hex.EncodeToString(sum),
+		Size:    req.size,
+		ModTime: time.Now().UTC(),
+	}
+
+	err = req.store.writeJSON(partName+".json", part)
+	if err != nil {
+		return err
+	}
+
+	req.w.Header().Set("ETag", quoteETag(part.ETag))
+	req.w.WriteHeader(http.StatusOK)
+
+	return nil
+}
+
+// completeMultipartUpload assembles the parts of a multipart upload into the object.
+func (req *request) completeMultipartUpload(uploadID string) error {
+	upload, err := req.store.loadUpload(uploadID, req.key)
+	if err != nil {
+		return err
+	}
+
+	input := struct {
+		Parts []completedPart `xml:"Part"`
+	}{}
+
+	err = req.readXML(&input)
+	if err != nil {
+		return err
+	}
+
+	if len(input.Parts) == 0 {
+		return errMalformedXML()
+	}
+
+	parts, err := req.store.loadParts(uploadID)
+	if err != nil {
+		return err
+	}
+
+	for i, selected := range input.Parts {
+		if i > 0 && selected.PartNumber <= input.Parts[i-1].PartNumber {
+			return newError(http.StatusBadRequest, "InvalidPartOrder", "The list of parts was not in ascending order")
+		}
+	}
+
+	for i, selected := range input.Parts {
+		part := parts[selected.PartNumber]
+		if part == nil || strings.Trim(selected.ETag, `"`) != part.ETag {
+			return newError(http.StatusBadRequest, "InvalidPart", "Part %d could not be found or its ETag doesn't match", selected.PartNumber)
+		}
+
+		if i < len(input.Parts)-1 && part.Size < minPartSize {
+			return newError(http.StatusBadRequest, "EntityTooSmall", "Part %d is smaller than the minimum allowed size", selected.PartNumber)
+		}
+	}
+
+	f, tmpName, err := req.store.createTemp()
+	if err != nil {
+		return err
+	}
+
+	// The ETag of multipart objects is the MD5 of the concatenated MD5s of their parts.
+	hash := md5.New()
+	for _, selected := range input.Parts {
+		err = req.store.appendPart(f, uploadID, selected.PartNumber)
+		if err != nil {
+			break
+		}
+
+		sum, _ := hex.DecodeString(parts[selected.PartNumber].ETag)
+		hash.Write(sum)
+	}
+
+	if err == nil {
+		err = f.Sync()
+	}
+
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = req.store.root.Remove(tmpName)
+		return err
+	}
+
+	// The selected parts make up the object, so only the replaced object and the other parts free space.
+	freed := req.store.existingSize(req.key)
+	for partNumber, part := range parts {
+		if !slices.ContainsFunc(input.Parts, func(selected completedPart) bool { return selected.PartNumber == partNumber }) {
+			freed += part.Size
+		}
+	}
+
+	etag := fmt.Sprintf("%s-%d", hex.EncodeToString(hash.Sum(nil)), len(input.Parts))
+	info, err := req.store.commit(tmpName, req.key, etag, upload.Headers)
+	if err != nil {
+		return err
+	}
+
+	_ = req.store.root.RemoveAll(uploadDir(uploadID))
+	req.store.freeQuota(freed)
+
+	writeXML(req.w, http.StatusOK, struct {
+		XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+		Xmlns    string   `xml:"xmlns,attr"`
+		Location string   `xml:"Location"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		ETag     string   `xml:"ETag"`
+	}{
+		Xmlns:    xmlNamespace,
+		Location: "https://" + req.r.Host + "/" + req.store.bucket.Name + "/" + uriEncode(req.key, false),
+		Bucket:   req.store.bucket.Name,
+		Key:      req.key,
+		ETag:     quoteETag(info.etag),
+	})
+
+	return nil
+}
+
+// appendPart appends the data of a part to w.
+func (s *store) appendPart(w io.Writer, uploadID string, partNumber int) error {
+	f, err := s.root.Open(partPath(uploadID, partNumber))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	_, err = io.Copy(w, f)
+
+	return err
+}
+
+// abortMultipartUpload removes a multipart upload and its parts.
+func (req *request) abortMultipartUpload(uploadID string) error {
+	_, err := req.store.loadUpload(uploadID, req.key)
+	if err != nil {
+		return err
+	}
+
+	parts, err := req.store.loadParts(uploadID)
+	if err != nil {
+		return err
+	}
+
+	err = req.store.root.RemoveAll(uploadDir(uploadID))
+	if err != nil {
+		return err
+	}
+
+	for _, part := range parts {
+		req.store.freeQuota(part.Size)
+	}
+
+	req.w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// listParts lists the uploaded parts of a multipart upload.
+func (req *request) listParts(uploadID string) error {
+	_, err := req.store.loadUpload(uploadID, req.key)
+	if err != nil {
+		return err
+	}
+
+	maxParts, err := parseMaxKeys(req.query.Get("max-parts"))
+	if err != nil {
+		return err
+	}
+
+	marker := 0
+	if req.query.Get("part-number-marker") != "" {
+		marker, err = strconv.Atoi(req.query.Get("part-number-marker"))
+		if err != nil {
+			return errInvalidArgument("Invalid part number marker")
+		}
+	}
+
+	parts, err := req.store.loadParts(uploadID)
+	if err != nil {
+		return err
+	}
+
+	partNumbers := make([]int, 0, len(parts))
+	for partNumber := range parts {
+		if partNumber > marker {
+			partNumbers = append(partNumbers, partNumber)
+		}
+	}
+
+	slices.Sort(partNumbers)
+
+	truncated := len(partNumbers) > maxParts
+	if truncated {
+		partNumbers = partNumbers[:maxParts]
+	}
+
+	entries := make([]uploadPartEntry, 0, len(partNumbers))
+	nextMarker := marker
+	for _, partNumber := range partNumbers {
+		part := parts[partNumber]
+		entries = append(entries, uploadPartEntry{
+			PartNumber:   partNumber,
+			LastModified: formatTime(part.ModTime),
+			ETag:         quoteETag(part.ETag),
+			Size:         part.Size,
+		})
+
+		nextMarker = partNumber
+	}
+
+	writeXML(req.w, http.StatusOK, struct {
+		XMLName              xml.Name          `xml:"ListPartsResult"`
+		Xmlns                string            `xml:"xmlns,attr"`
+		Bucket               string            `xml:"Bucket"`
+		Key                  string            `xml:"Key"`
+		UploadID             string            `xml:"UploadId"`
+		Initiator            owner             `xml:"Initiator"`
+		Owner                owner             `xml:"Owner"`
+		StorageClass         string            `xml:"StorageClass"`
+		PartNumberMarker     int               `xml:"PartNumberMarker"`
+		NextPartNumberMarker int               `xml:"NextPartNumberMarker"`
+		MaxParts             int               `xml:"MaxParts"`
+		IsTruncated          bool              `xml:"IsTruncated"`
+		Parts                []uploadPartEntry `xml:"Part"`
+	}{
+		Xmlns:                xmlNamespace,
+		Bucket:               req.store.bucket.Name,
+		Key:                  req.key,
+		UploadID:             uploadID,
+		Initiator:            defaultOwner,
+		Owner:                defaultOwner,
+		StorageClass:         "STANDARD",
+		PartNumberMarker:     marker,
+		NextPartNumberMarker: nextMarker,
+		MaxParts:             maxParts,
+		IsTruncated:          truncated,
+		Parts:                entries,
+	})
+
+	return nil
+}
+
+// listMultipartUploads lists the multipart uploads in progress.
+func (req *request) listMultipartUploads() error {
+	maxUploads, err := parseMaxKeys(req.query.Get("max-uploads"))
+	if err != nil {
+		return err
+	}
+
+	prefix := req.query.Get("prefix")
+	keyMarker := req.query.Get("key-marker")
+	uploadIDMarker := req.query.Get("upload-id-marker")
+
+	type uploadEntry struct {
+		Key          string `xml:"Key"`
+		UploadID     string `xml:"UploadId"`
+		Initiator    owner  `xml:"Initiator"`
+		Owner        owner  `xml:"Owner"`
+		StorageClass string `xml:"StorageClass"`
+		Initiated    string `xml:"Initiated"`
+	}
+
+	entries, err := fs.ReadDir(req.store.root.FS(), path.Join(internalDir, "uploads"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	uploads := []uploadEntry{}
+	for _, entry := range entries {
+		upload, err := req.store.loadUpload(entry.Name(), "")
+		if err != nil || !strings.HasPrefix(upload.Key, prefix) {
+			continue
+		}
+
+		if upload.Key < keyMarker || (upload.Key == keyMarker && (uploadIDMarker == "" || entry.Name() <= uploadIDMarker)) {
+			continue
+		}
+
+		uploads = append(uploads, uploadEntry{
+			Key:          upload.Key,
+			UploadID:     entry.Name(),
+			Initiator:    defaultOwner,
+			Owner:        defaultOwner,
+			StorageClass: "STANDARD",
+			Initiated:    formatTime(upload.Initiated),
+		})
+	}
+
+	slices.SortFunc(uploads, func(a uploadEntry, b uploadEntry) int {
+		if a.Key != b.Key {
+			return strings.Compare(a.Key, b.Key)
+		}
+
+		return strings.Compare(a.UploadID, b.UploadID)
+	})
+
+	truncated := len(uploads) > maxUploads
+	if truncated {
+		uploads = uploads[:maxUploads]
+	}
+
+	result := struct {
+		XMLName            xml.Name      `xml:"ListMultipartUploadsResult"`
+		Xmlns              string        `xml:"xmlns,attr"`
+		Bucket             string        `xml:"Bucket"`
+		KeyMarker          string        `xml:"KeyMarker"`
+		UploadIDMarker     string        `xml:"UploadIdMarker"`
+		NextKeyMarker      string        `xml:"NextKeyMarker,omitempty"`
+		NextUploadIDMarker string        `xml:"NextUploadIdMarker,omitempty"`
+		Prefix             string        `xml:"Prefix"`
+		MaxUploads         int           `xml:"MaxUploads"`
+		IsTruncated        bool          `xml:"IsTruncated"`
+		Uploads            []uploadEntry `xml:"Upload"`
+	}{
+		Xmlns:          xmlNamespace,
+		Bucket:         req.store.bucket.Name,
+		KeyMarker:      keyMarker,
+		UploadIDMarker: uploadIDMarker,
+		Prefix:         prefix,
+		MaxUploads:     maxUploads,
+		IsTruncated:    truncated,
+		Uploads:        uploads,
+	}
+
+	if truncated && len(uploads) > 0 {
+		result.NextKeyMarker = uploads[len(uploads)-1].Key
+		result.NextUploadIDMarker = uploads[len(uploads)-1].UploadID
+	}
+
+	writeXML(req.w, http.StatusOK, result)
+
+	return nil
+}
@@ -0,0 +1,308 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxDeleteObjects is the maximum number of objects deleted by a single request.
+const maxDeleteObjects = 1000
+
+// responseHeaderOverrides maps the query parameters overriding response headers of object reads to their header.
+var responseHeaderOverrides = map[string]string{
+	"response-cache-control":       "Cache-Control",
+	"response-content-disposition": "Content-Disposition",
+	"response-content-encoding":    "Content-Encoding",
+	"response-content-language":    "Content-Language",
+	"response-content-type":        "Content-Type",
+	"response-expires":             "Expires",
+}
+
+// uploadHeaders returns the headers of an upload which are stored along with the object.
+func uploadHeaders(header http.Header) map[string]string {
+	headers := map[string]string{}
+
+	for _, name := range storedHeaders {
+		value := header.Get(name)
+		if name == "Content-Encoding" {
+			// The aws-chunked encoding only applies to the upload itself.
+			var encodings []string
+			for encoding := range strings.SplitSeq(value, ",") {
+				encoding = strings.TrimSpace(encoding)
+				if encoding != "" && encoding != "aws-chunked" {
+					encodings = append(encodings, encoding)
+				}
+			}
+
+			value = strings.Join(encodings, ",")
+		}
+
+		if value != "" {
+			headers[name] = value
+		}
+	}
+
+	for name, values := range header {
+		if strings.HasPrefix(name, "X-Amz-Meta-") {
+			headers[name] = strings.Join(values, ",")
+		}
+	}
+
+	return headers
+}
+
+// checkContentMD5 checks the MD5 of the payload against the Content-MD5 header of the request.
+func checkContentMD5(header http.Header, sum []byte) error {
+	contentMD5 := header.Get("Content-MD5")
+	if contentMD5 == "" {
+		return nil
+	}
+
+	expected, err := base64.StdEncoding.DecodeString(contentMD5)
+	if err != nil || len(expected) != len(sum) {
+		return newError(http.StatusBadRequest, "InvalidDigest", "The Content-MD5 you specified is not valid")
+	}
+
+	if !bytes.Equal(expected, sum) {
+		return errBadDigest()
+	}
+
+	return nil
+}
+
+// checkUploadSize checks the size of an uploaded payload.
+func checkUploadSize(size int64) error {
+	if size < 0 {
+		return errMissingContentLength()
+	}
+
+	if size > maxObjectSize {
+		return errEntityTooLarge()
+	}
+
+	return nil
+}
+
+// getObject returns the content of an object, or only its headers for HEAD requests.
+func (req *request) getObject() error {
+	f, info, err := req.store.open(req.key)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	header := req.w.Header()
+	for name, value := range info.headers {
+		header.Set(name, value)
+	}
+
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "binary/octet-stream")
+	}
+
+	for param, name := range responseHeaderOverrides {
+		value := req.query.Get(param)
+		if value != "" {
+			header.Set(name, value)
+		}
+	}
+
+	header.Set("ETag", quoteETag(info.etag))
+	http.ServeContent(req.w, req.r, "", info.modTime, f)
+
+	return nil
+}
+
+// putObject stores an object.
+func (req *request) putObject() error {
+	err := validateKey(req.key)
+	if err != nil {
+		return err
+	}
+
+	err = checkUploadSize(req.size)
+	if err != nil {
+		return err
+	}
+
+	done, err := req.store.reserveQuota(req.size, req.store.existingSize(req.key))
+	if err != nil {
+		return err
+	}
+
+	tmpName, sum, err := req.store.writeTemp(req.body, req.size)
+	if err != nil {
+		done(false)
+		return err
+	}
+
+	err = checkContentMD5(req.r.Header, sum)
+	if err != nil {
+		_ = req.store.root.Remove(tmpName)
+		done(false)
+		return err
+	}
+
+	info, err := req.store.commit(tmpName, req.key, hex.EncodeToString(sum), uploadHeaders(req.r.Header))
+	done(err == nil)
+	if err != nil {
+		return err
+	}
+
+	req.w.Header().Set("ETag", quoteETag(info.etag))
+	req.w.WriteHeader(http.StatusOK)
+
+	return nil
+}
+
+// copyObject copies an object within the bucket.
+func (req *request) copyObject() error {
+	err := validateKey(req.key)
+	if err != nil {
+		return err
+	}
+
+	source, err := url.PathUnescape(req.r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		return errInvalidArgument("Invalid copy source")
+	}
+
+	source, _, _ = strings.Cut(source, "?")
+	sourceBucket, sourceKey, _ := strings.Cut(strings.TrimPrefix(source, "/"), "/")
+	if sourceKey == "" {
+		return errInvalidArgument("Invalid copy source")
+	}
+
+	if sourceBucket != req.store.bucket.Name {
+		return errNotImplemented("Copying objects between buckets is not supported")
+	}
+
+	directive := req.r.Header.Get("X-Amz-Metadata-Directive")
+	if directive == "" {
+		directive = "COPY"
+	}
+
+	if directive != "COPY" && directive != "REPLACE" {
+		return errInvalidArgument("Unknown metadata directive %q", directive)
+	}
+
+	if sourceKey == req.key && directive == "COPY" {
+		return errInvalidRequest("This copy request is illegal because it is trying to copy an object to itself without changing the object's metadata")
+	}
+
+	f, info, err := req.store.open(sourceKey)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	ifMatch := req.r.Header.Get("X-Amz-Copy-Source-If-Match")
+	ifNoneMatch := req.r.Header.Get("X-Amz-Copy-Source-If-None-Match")
+	if (ifMatch != "" && strings.Trim(ifMatch, `"`) != info.etag) || (ifNoneMatch != "" && strings.Trim(ifNoneMatch, `"`) == info.etag) {
+		return newError(http.StatusPreconditionFailed, "PreconditionFailed", "At least one of the preconditions you specified did not hold")
+	}
+
+	headers := info.headers
+	if directive == "REPLACE" {
+		headers = uploadHeaders(req.r.Header)
+	}
+
+	done, err := req.store.reserveQuota(info.size, req.store.existingSize(req.key))
+	if err != nil {
+		return err
+	}
+
+	tmpName, sum, err := req.store.writeTemp(f, info.size)
+	if err != nil {
+		done(false)
+		return err
+	}
+
+	newInfo, err := req.store.commit(tmpName, req.key, hex.EncodeToString(sum), headers)
+	done(err == nil)
+	if err != nil {
+		return err
+	}
+
+	writeXML(req.w, http.StatusOK, struct {
+		XMLName      xml.Name `xml:"CopyObjectResult"`
+		Xmlns        string   `xml:"xmlns,attr"`
+		LastModified string   `xml:"LastModified"`
+		ETag         string   `xml:"ETag"`
+	}{
+		Xmlns:        xmlNamespace,
+		LastModified: formatTime(newInfo.modTime),
+		ETag:         quoteETag(newInfo.etag),
+	})
+
+	return nil
+}
+
+// deleteObjects deletes multiple objects.
+func (req *request) deleteObjects() error {
+	input := struct {
+		Quiet   bool `xml:"Quiet"`
+		Objects []struct {
+			Key string `xml:"Key"`
+		} `xml:"Object"`
+	}{}
+
+	err := req.readXML(&input)
+	if err != nil {
+		return err
+	}
+
+	if len(input.Objects) == 0 || len(input.Objects) > maxDeleteObjects {
+		return errMalformedXML()
+	}
+
+	type deleted struct {
+		Key string `xml:"Key"`
+	}
+
+	type deleteError struct {
+		Key     string `xml:"Key"`
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+
+	result := struct {
+		XMLName xml.Name      `xml:"DeleteResult"`
+		Xmlns   string        `xml:"xmlns,attr"`
+		Deleted []deleted     `xml:"Deleted"`
+		Errors  []deleteError `xml:"Error"`
+	}{
+		Xmlns: xmlNamespace,
+	}
+
+	for _, object := range input.Objects {
+		err := req.store.delete(object.Key)
+		if err != nil {
+			s3Err := toError(err)
+			result.Errors = append(result.Errors, deleteError{Key: object.Key, Code: s3Err.Code, Message: s3Err.Message})
+			continue
+		}
+
+		if !input.Quiet {
+			result.Deleted = append(result.Deleted, deleted{Key: object.Key})
+		}
+	}
+
+	writeXML(req.w, http.StatusOK, result)
+
+	return nil
+}
+
+// drain reads the rest of the request body, verifying its signature.
+func (req *request) drain() error {
+	_, err := io.Copy(io.Discard, req.body)
+	return err
+}
@@ -0,0 +1,241 @@
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxChunkSize is the maximum size of a chunk of an aws-chunked payload.
+const maxChunkSize = 16 * 1024 * 1024
+
+// maxChunkHeaderSize is the maximum size of a chunk header or trailer line of an aws-chunked payload.
+const maxChunkHeaderSize = 4096
+
+// payloadReader returns a reader of the decoded request payload which fails if the payload doesn't match the signed
+// payload hash, along with the decoded payload length (-1 if unknown).
+func (a *authRequest) payloadReader(r *http.Request, signingKey []byte) (io.Reader, int64, error) {
+	switch a.payloadHash {
+	case UnsignedPayload:
+		return r.Body, r.ContentLength, nil
+	case streamingPayload, streamingPayloadTrailer, streamingUnsignedPayloadTrailer:
+		decodedLength, err := strconv.ParseInt(r.Header.Get("X-Amz-Decoded-Content-Length"), 10, 64)
+		if err != nil || decodedLength < 0 {
+			return nil, -1, errMissingContentLength()
+		}
+
+		reader := &chunkedReader{
+			r:             bufio.NewReaderSize(r.Body, maxChunkHeaderSize),
+			remaining:     decodedLength,
+			trailer:       a.payloadHash != streamingPayload,
+			date:          a.date,
+			scope:         a.scope(),
+			prevSignature: a.signature,
+		}
+
+		if a.payloadHash != streamingUnsignedPayloadTrailer {
+			reader.signingKey = signingKey
+		}
+
+		return reader, decodedLength, nil
+	}
+
+	expected, _ := hex.DecodeString(a.payloadHash)
+	if r.ContentLength == 0 && a.payloadHash != emptyPayloadHash {
+		return nil, -1, newError(http.StatusBadRequest, "XAmzContentSHA256Mismatch", "The provided x-amz-content-sha256 header does not match what was computed")
+	}
+
+	return &hashReader{r: r.Body, hash: sha256.New(), expected: expected}, r.ContentLength, nil
+}
+
+// hashReader is a reader which fails at the end of the payload if its SHA256 doesn't match the expected one.
+type hashReader struct {
+	r        io.Reader
+	hash     hash.Hash
+	expected []byte
+}
+
+// Read reads from the payload.
+func (h *hashReader) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	h.hash.Write(p[:n])
+
+	if err == io.EOF && !bytes.Equal(h.hash.Sum(nil), h.expected) {
+		return n, newError(http.StatusBadRequest, "XAmzContentSHA256Mismatch", "The provided x-amz-content-sha256 header does not match what was computed")
+	}
+
+	return n, err
+}
+
+// chunkedReader decodes an aws-chunked payload, verifying the signature of each chunk and of the trailer when
+// a signing key is set.
+type chunkedReader struct {
+	r             *bufio.Reader
+	remaining     int64
+	trailer       bool
+	signingKey    []byte
+	date          time.Time
+	scope         string
+	prevSignature string
+
+	chunk []byte
+	err   error
+}
+
+// Read reads from the decoded payload.
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	for len(c.chunk) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+
+		c.err = c.readChunk()
+	}
+
+	n := copy(p, c.chunk)
+	c.chunk = c.chunk[n:]
+
+	return n, nil
+}
+
+// readChunk reads the next chunk of the payload. It returns io.EOF after the last chunk.
+func (c *chunkedReader) readChunk() error {
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+
+	sizeStr, ext, _ := strings.Cut(line, ";")
+	size, err := strconv.ParseInt(sizeStr, 16, 64)
+	if err != nil || size < 0 {
+		return errInvalidArgument("Invalid chunk size %q", sizeStr)
+	}
+
+	if size > maxChunkSize {
+		return errInvalidArgument("Chunk size %d exceeds the maximum of %d", size, maxChunkSize)
+	}
+
+	if size > c.remaining {
+		return errInvalidArgument("Chunked payload exceeds the decoded content length")
+	}
+
+	if cap(c.chunk) < int(size) {
+		c.chunk = make([]byte, size)
+	}
+
+	c.chunk = c.chunk[:size]
+	_, err = io.ReadFull(c.r, c.chunk)
+	if err != nil {
+		return errIncompleteBody()
+	}
+
+	if c.signingKey != nil {
+		signature, found := strings.CutPrefix(ext, "chunk-signature=")
+		if !found {
+			return errSignatureDoesNotMatch()
+		}
+
+		chunkHash := sha256.Sum256(c.chunk)
+		toSign := signV4Algorithm + "-PAYLOAD\n" + c.date.UTC().Format(iso8601Format) + "\n" + c.scope + "\n" + c.prevSignature + "\n" + emptyPayloadHash + "\n" + hex.EncodeToString(chunkHash[:])
+		expected := hex.EncodeToString(hmacSHA256(c.signingKey, toSign))
+		if !hmac.Equal([]byte(expected), []byte(signature)) {
+			return errSignatureDoesNotMatch()
+		}
+
+		c.prevSignature = signature
+	}
+
+	c.remaining -= size
+
+	if size > 0 || !c.trailer {
+		// The chunk data is followed by a new line, except for the last chunk of payloads with trailers.
+		line, err = c.readLine()
+		if err != nil {
+			return err
+		}
+
+		if line != "" {
+			return errInvalidArgument("Malformed chunked payload")
+		}
+	}
+
+	if size > 0 {
+		return nil
+	}
+
+	if c.remaining != 0 {
+		return errIncompleteBody()
+	}
+
+	if c.trailer {
+		err = c.readTrailer()
+		if err != nil {
+			return err
+		}
+	}
+
+	return io.EOF
+}
+
+// readTrailer reads the trailing headers of the payload and checks their signature.
+func (c *chunkedReader) readTrailer() error {
+	var trailer strings.Builder
+	var signature string
+
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return err
+		}
+
+		if line == "" {
+			break
+		}
+
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			return errInvalidArgument("Malformed chunked payload trailer")
+		}
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "x-amz-trailer-signature" {
+			signature = strings.TrimSpace(value)
+			continue
+		}
+
+		trailer.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	if c.signingKey == nil {
+		return nil
+	}
+
+	trailerHash := sha256.Sum256([]byte(trailer.String()))
+	toSign := signV4Algorithm + "-TRAILER\n" + c.date.UTC().Format(iso8601Format) + "\n" + c.scope + "\n" + c.prevSignature + "\n" + hex.EncodeToString(trailerHash[:])
+	expected := hex.EncodeToString(hmacSHA256(c.signingKey, toSign))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errSignatureDoesNotMatch()
+	}
+
+	return nil
+}
+
+// readLine reads a CRLF terminated line of the payload.
+func (c *chunkedReader) readLine() (string, error) {
+	line, err := c.r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return "", errInvalidArgument("Chunk header too long")
+	} else if err != nil {
+		return "", errIncompleteBody()
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(string(line), "\n"), "\r"), nil
+}
@@ -0,0 +1,384 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signature version 4 constants.
+const (
+	signV4Algorithm = "AWS4-HMAC-SHA256"
+	signV4Service   = "s3"
+	signV4Request   = "aws4_request"
+	iso8601Format   = "20060102T150405Z"
+	yyyymmdd        = "20060102"
+
+	// UnsignedPayload is the payload hash of requests whose payload isn't signed.
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+	streamingPayload                = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	streamingPayloadTrailer         = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD-TRAILER"
+	streamingUnsignedPayloadTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+
+	// emptyPayloadHash is the SHA256 of an empty payload.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	// maxClockSkew is the maximum difference allowed between the time of a request and the server time.
+	maxClockSkew = 15 * time.Minute
+
+	// maxPresignedExpiry is the maximum validity of presigned URLs.
+	maxPresignedExpiry = 7 * 24 * time.Hour
+)
+
+// authRequest holds the signature version 4 authentication details of a request.
+type authRequest struct {
+	accessKey     string
+	date          time.Time
+	scopeDate     string
+	region        string
+	signedHeaders []string
+	signature     string
+	payloadHash   string
+	presigned     bool
+	expires       time.Duration
+}
+
+// parseAuth extracts the signature version 4 authentication details of a request, either from its Authorization
+// header or from its query string for presigned URLs.
+func parseAuth(r *http.Request) (*authRequest, error) {
+	header := r.Header.Get("Authorization")
+	if header != "" {
+		return parseAuthHeader(r, header)
+	}
+
+	if r.URL.Query().Has("X-Amz-Algorithm") {
+		return parsePresigned(r)
+	}
+
+	return nil, errAccessDenied("Anonymous access is not allowed")
+}
+
+// parseAuthHeader parses an Authorization header such as
+// `AWS4-HMAC-SHA256 Credential=AKID/20130524/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-date, Signature=abc`.
+func parseAuthHeader(r *http.Request, header string) (*authRequest, error) {
+	algorithm, fields, _ := strings.Cut(header, " ")
+	if algorithm != signV4Algorithm {
+		return nil, errInvalidRequest("Only the %s authorization mechanism is supported", signV4Algorithm)
+	}
+
+	auth := &authRequest{}
+	var credential string
+	for field := range strings.SplitSeq(fields, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		switch key {
+		case "Credential":
+			credential = value
+		case "SignedHeaders":
+			auth.signedHeaders = strings.Split(value, ";")
+		case "Signature":
+			auth.signature = value
+		}
+	}
+
+	if credential == "" || len(auth.signedHeaders) == 0 || auth.signature == "" {
+		return nil, errInvalidArgument("Malformed authorization header")
+	}
+
+	err := auth.parseCredential(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	if amzDate != "" {
+		auth.date, err = time.Parse(iso8601Format, amzDate)
+	} else {
+		auth.date, err = http.ParseTime(r.Header.Get("Date"))
+	}
+
+	if err != nil {
+		return nil, errAccessDenied("Missing or invalid request date")
+	}
+
+	auth.payloadHash = r.Header.Get("X-Amz-Content-Sha256")
+	if auth.payloadHash == "" {
+		return nil, errInvalidRequest("Missing required header for this request: x-amz-content-sha256")
+	}
+
+	err = auth.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return auth, nil
+}
+
+// parsePresigned parses the authentication query parameters of a presigned URL.
+func parsePresigned(r *http.Request) (*authRequest, error) {
+	query := r.URL.Query()
+	if query.Get("X-Amz-Algorithm") != signV4Algorithm {
+		return nil, errInvalidRequest("Only the %s authorization mechanism is supported", signV4Algorithm)
+	}
+
+	auth := &authRequest{
+		presigned:     true,
+		signedHeaders: strings.Split(query.Get("X-Amz-SignedHeaders"), ";"),
+		signature:     query.Get("X-Amz-Signature"),
+		payloadHash:   UnsignedPayload,
+	}
+
+	if query.Get("X-Amz-SignedHeaders") == "" || auth.signature == "" {
+		return nil, errAccessDenied("Missing presigned URL parameters")
+	}
+
+	err := auth.parseCredential(query.Get("X-Amz-Credential"))
+	if err != nil {
+		return nil, err
+	}
+
+	auth.date, err = time.Parse(iso8601Format, query.Get("X-Amz-Date"))
+	if err != nil {
+		return nil, errAccessDenied("Missing or invalid X-Amz-Date")
+	}
+
+	expires, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+	if err != nil || expires < 0 {
+		return nil, errAccessDenied("Missing or invalid X-Amz-Expires")
+	}
+
+	auth.expires = time.Duration(expires) * time.Second
+	if auth.expires > maxPresignedExpiry {
+		return nil, errAccessDenied("X-Amz-Expires must be less than a week")
+	}
+
+	if query.Has("X-Amz-Content-Sha256") {
+		auth.payloadHash = query.Get("X-Amz-Content-Sha256")
+	}
+
+	err = auth.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return auth, nil
+}
+
+// parseCredential parses the credential such as `AKID/20130524/us-east-1/s3/aws4_request`.
+func (a *authRequest) parseCredential(credential string) error {
+	parts := strings.Split(credential, "/")
+	if len(parts) != 5 || parts[0] == "" || parts[3] != signV4Service || parts[4] != signV4Request {
+		return errAccessDenied("Malformed credential")
+	}
+
+	a.accessKey = parts[0]
+	a.scopeDate = parts[1]
+	a.region = parts[2]
+
+	return nil
+}
+
+// validate checks the consistency of the authentication details.
+func (a *authRequest) validate() error {
+	if a.scopeDate != a.date.UTC().Format(yyyymmdd) {
+		return errAccessDenied("Credential date doesn't match the request date")
+	}
+
+	if !slices.Contains(a.signedHeaders, "host") {
+		return errAccessDenied("The host header must be signed")
+	}
+
+	switch a.payloadHash {
+	case UnsignedPayload, streamingPayload, streamingPayloadTrailer, streamingUnsignedPayloadTrailer:
+	default:
+		_, err := hex.DecodeString(a.payloadHash)
+		if err != nil || len(a.payloadHash) != sha256.Size*2 {
+			return errInvalidArgument("Invalid x-amz-content-sha256 value")
+		}
+	}
+
+	return nil
+}
+
+// scope returns the credential scope of the request.
+func (a *authRequest) scope() string {
+	return a.scopeDate + "/" + a.region + "/" + signV4Service + "/" + signV4Request
+}
+
+// checkTime checks the request isn't too old or too far in the future, or expired for presigned URLs.
+func (a *authRequest) checkTime(now time.Time) error {
+	if a.presigned {
+		if now.Before(a.date.Add(-maxClockSkew)) {
+			return errAccessDenied("Request is not valid yet")
+		}
+
+		if now.After(a.date.Add(a.expires)) {
+			return errAccessDenied("Request has expired")
+		}
+
+		return nil
+	}
+
+	skew := now.Sub(a.date)
+	if skew < -maxClockSkew || skew > maxClockSkew {
+		return newError(http.StatusForbidden, "RequestTimeTooSkewed", "The difference between the request time and the server's time is too large")
+	}
+
+	return nil
+}
+
+// verify checks the request signature against the given secret key and returns the signing key.
+func (a *authRequest) verify(r *http.Request, secretKey string) ([]byte, error) {
+	key := signingKey(secretKey, a.scopeDate, a.region)
+	canonical := canonicalRequest(r, a.signedHeaders, a.payloadHash, a.presigned)
+	expected := hex.EncodeToString(hmacSHA256(key, stringToSign(a.date, a.scope(), canonical)))
+
+	if !hmac.Equal([]byte(expected), []byte(a.signature)) {
+		return nil, errSignatureDoesNotMatch()
+	}
+
+	return key, nil
+}
+
+// SignRequest signs the request with the given credentials using the AWS signature version 4 Authorization header.
+// The payloadHash is the hex encoded SHA256 of the request body or UnsignedPayload.
+func SignRequest(r *http.Request, accessKey string, secretKey string, region string, payloadHash string, now time.Time) {
+	now = now.UTC()
+	r.Header.Set("X-Amz-Date", now.Format(iso8601Format))
+	r.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := []string{"host"}
+	for name := range r.Header {
+		name = strings.ToLower(name)
+		if name == "content-md5" || name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			signedHeaders = append(signedHeaders, name)
+		}
+	}
+
+	slices.Sort(signedHeaders)
+
+	scope := now.Format(yyyymmdd) + "/" + region + "/" + signV4Service + "/" + signV4Request
+	key := signingKey(secretKey, now.Format(yyyymmdd), region)
+	canonical := canonicalRequest(r, signedHeaders, payloadHash, false)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign(now, scope, canonical)))
+
+	r.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", signV4Algorithm, accessKey, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+// signingKey derives the signing key of the given secret key and scope.
+func signingKey(secretKey string, date string, region string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, signV4Service)
+
+	return hmacSHA256(key, signV4Request)
+}
+
+// stringToSign returns the string to sign of a canonical request.
+func stringToSign(date time.Time, scope string, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+
+	return signV4Algorithm + "\n" + date.UTC().Format(iso8601Format) + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+}
+
+// canonicalRequest returns the canonical form of a request.
+func canonicalRequest(r *http.Request, signedHeaders []string, payloadHash string, presigned bool) string {
+	var sb strings.Builder
+
+	sb.WriteString(r.Method + "\n")
+	sb.WriteString(canonicalURI(r.URL.Path) + "\n")
+	sb.WriteString(canonicalQuery(r.URL.Query(), presigned) + "\n")
+
+	for _, name := range signedHeaders {
+		sb.WriteString(name + ":" + canonicalHeaderValue(r, name) + "\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Join(signedHeaders, ";") + "\n")
+	sb.WriteString(payloadHash)
+
+	return sb.String()
+}
+
+// canonicalURI returns the URI encoded path of a request. S3 doesn't normalize nor double encode paths.
+func canonicalURI(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	return uriEncode(path, false)
+}
+
+// canonicalQuery returns the sorted and URI encoded query string of a request.
+func canonicalQuery(query url.Values, presigned bool) string {
+	params := make([]string, 0, len(query))
+	for key, values := range query {
+		if presigned && key == "X-Amz-Signature" {
+			continue
+		}
+
+		for _, value := range values {
+			params = append(params, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	slices.Sort(params)
+
+	return strings.Join(params, "&")
+}
+
+// canonicalHeaderValue returns the canonical value of a request header.
+func canonicalHeaderValue(r *http.Request, name string) string {
+	switch name {
+	case "host":
+		return r.Host
+	case "content-length":
+		if r.Header.Get("Content-Length") == "" && r.ContentLength >= 0 {
+			return strconv.FormatInt(r.ContentLength, 10)
+		}
+	case "transfer-encoding":
+		return strings.Join(r.TransferEncoding, ",")
+	}
+
+	values := make([]string, 0, len(r.Header.Values(name)))
+	for _, value := range r.Header.Values(name) {
+		values = append(values, strings.Join(strings.Fields(value), " "))
+	}
+
+	return strings.Join(values, ",")
+}
+
+// uriEncode encodes every byte of s but the unreserved characters of RFC 3986, and the slashes unless encodeSlash
+// is set.
+func uriEncode(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+
+	var sb strings.Builder
+	for i := range len(s) {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			sb.WriteByte(c)
+			continue
+		}
+
+		sb.WriteByte('%')
+		sb.WriteByte(hexDigits[c>>4])
+		sb.WriteByte(hexDigits[c&15])
+	}
+
+	return sb.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
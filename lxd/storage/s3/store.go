@@ -0,0 +1,567 @@
+package s3
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+// internalDir is the directory of a bucket holding the object metadata, the multipart uploads and temporary files.
+const internalDir = ".lxd-s3"
+
+// hiddenNames are the top level entries of a bucket which aren't objects.
+var hiddenNames = []string{internalDir, "lost+found"}
+
+// maxObjectSize is the maximum size of an object uploaded in a single request.
+const maxObjectSize = 5 * 1024 * 1024 * 1024
+
+// maxKeyLength is the maximum length of an object key.
+const maxKeyLength = 1024
+
+// storedHeaders are the headers of an upload which are stored along with the object and returned when it's read.
+var storedHeaders = []string{"Cache-Control", "Content-Disposition", "Content-Encoding", "Content-Language", "Content-Type", "Expires"}
+
+// objectMetadata is the metadata stored along with an object.
+type objectMetadata struct {
+	Key     string            `json:"key"`
+	ETag    string            `json:"etag"`
+	Size    int64             `json:"size"`
+	ModTime int64             `json:"mod_time"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// objectInfo describes a stored object.
+type objectInfo struct {
+	key     string
+	size    int64
+	modTime time.Time
+	etag    string
+	headers map[string]string
+}
+
+// quotaRefreshInterval is how long the tracked usage of a bucket is trusted before being measured again, so that
+// changes made outside of the gateway are eventually accounted for.
+const quotaRefreshInterval = time.Minute
+
+// bucketQuota tracks the disk space used by a bucket across concurrent requests, so that the quota can be enforced
+// without measuring the usage of the bucket on every write.
+type bucketQuota struct {
+	mu        sync.Mutex
+	createdAt time.Time
+
+	// usage is the last measured usage of the bucket, adjusted by the writes and deletions since.
+	usage int64
+
+	// measured is when the usage was last measured, zero when it needs measuring again.
+	measured time.Time
+
+	// reserved is the space reserved by the writes in progress.
+	reserved int64
+
+	// writes is the number of writes in progress.
+	writes int
+}
+
+// store gives access to the objects of a bucket.
+type store struct {
+	bucket *Bucket
+	root   *os.Root
+	quota  *bucketQuota
+}
+
+// openStore opens the directory of a bucket, whose usage is tracked by quota.
+func openStore(bucket *Bucket, quota *bucketQuota) (*store, error) {
+	root, err := os.OpenRoot(bucket.Path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed opening bucket %q: %w", bucket.Name, err)
+	}
+
+	return &store{bucket: bucket, root: root, quota: quota}, nil
+}
+
+// close closes the directory of the bucket.
+func (s *store) close() {
+	_ = s.root.Close()
+}
+
+// validateKey checks that key is usable as a file path within the bucket.
+func validateKey(key string) error {
+	if len(key) > maxKeyLength {
+		return newError(http.StatusBadRequest, "KeyTooLongError", "Your key is too long")
+	}
+
+	if key == "" || !utf8.ValidString(key) || strings.ContainsRune(key, 0) {
+		return errInvalidArgument("Invalid object key")
+	}
+
+	segments := strings.Split(key, "/")
+	if slices.Contains(hiddenNames, segments[0]) {
+		return errInvalidArgument("Object key %q is reserved", segments[0])
+	}
+
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return errInvalidArgument("Object keys cannot end with a slash nor contain empty, %q or %q segments", ".", "..")
+		}
+
+		if len(segment) > 255 {
+			return newError(http.StatusBadRequest, "KeyTooLongError", "Your key contains a segment longer than 255 bytes")
+		}
+	}
+
+	return nil
+}
+
+// metadataPath returns the path of the metadata file of an object.
+func metadataPath(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(hash[:])
+
+	return path.Join(internalDir, "metadata", name[:2], name+".json")
+}
+
+// fallbackETag returns the ETag of objects without valid metadata, such as files added to the volume directly.
+func fallbackETag(fi fs.FileInfo) string {
+	return fmt.Sprintf("%x-%x", fi.ModTime().UnixNano(), fi.Size())
+}
+
+// stat returns the information of an object.
+func (s *store) stat(key string) (*objectInfo, error) {
+	err := validateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := s.root.Stat(key)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || isNotDir(err) {
+			return nil, errNoSuchKey()
+		}
+
+		return nil, err
+	}
+
+	if !fi.Mode().IsRegular() {
+		return nil, errNoSuchKey()
+	}
+
+	return s.info(key, fi), nil
+}
+
+// info returns the information of the object at key with the given file information.
+func (s *store) info(key string, fi fs.FileInfo) *objectInfo {
+	info := &objectInfo{
+		key:     key,
+		size:    fi.Size(),
+		modTime: fi.ModTime(),
+		etag:    fallbackETag(fi),
+	}
+
+	data, err := s.root.ReadFile(metadataPath(key))
+	if err != nil {
+		return info
+	}
+
+	meta := objectMetadata{}
+	err = json.Unmarshal(data, &meta)
+	if err != nil {
+		return info
+	}
+
+	// Ignore the metadata if the object was modified without going through the gateway.
+	if meta.Key != key || meta.Size != fi.Size() || meta.ModTime != fi.ModTime().UnixNano() {
+		return info
+	}
+
+	info.etag = meta.ETag
+	info.headers = meta.Headers
+
+	return info
+}
+
+// open opens an object for reading.
+func (s *store) open(key string) (*os.File, *objectInfo, error) {
+	info, err := s.stat(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := s.root.Open(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, info, nil
+}
+
+// reserveQuota reserves room in the bucket for size more bytes once replaced bytes are freed.
+// The returned function must be called once the write is done, indicating whether the data was written.
+// Reservations are serialized per bucket so that concurrent writes can't exceed the quota together.
+func (s *store) reserveQuota(size int64, replaced int64) (func(written bool), error) {
+	q := s.quota
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if s.bucket.Quota <= 0 {
+		// Measure the usage again if a quota is set later on.
+		q.measured = time.Time{}
+		return func(bool) {}, nil
+	}
+
+	// Only measure the usage while no writes are in progress, as their data may be partially written.
+	if q.writes == 0 && time.Since(q.measured) > quotaRefreshInterval {
+		usage, err := s.bucket.usage()
+		if err != nil {
+			return nil, err
+		}
+
+		q.usage = usage
+		q.measured = time.Now()
+	}
+
+	if q.usage+q.reserved+size-replaced > s.bucket.Quota {
+		return nil, errQuotaExceeded()
+	}
+
+	q.reserved += size
+	q.writes++
+
+	return func(written bool) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		q.reserved -= size
+		q.writes--
+
+		if written {
+			q.usage += size - replaced
+		}
+	}, nil
+}
+
+// freeQuota records that size bytes were freed in the bucket.
+func (s *store) freeQuota(size int64) {
+	s.quota.mu.Lock()
+	s.quota.usage -= size
+	s.quota.mu.Unlock()
+}
+
+// existingSize returns the size of the object at key, or 0 if it doesn't exist.
+func (s *store) existingSize(key string) int64 {
+	fi, err := s.root.Stat(key)
+	if err != nil || !fi.Mode().IsRegular() {
+		return 0
+	}
+
+	return fi.Size()
+}
+
+// createTemp creates a temporary file within the bucket.
+func (s *store) createTemp() (*os.File, string, error) {
+	tmpDir := path.Join(internalDir, "tmp")
+	err := s.root.MkdirAll(tmpDir, 0700)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name := path.Join(tmpDir, randomID())
+	f, err := s.root.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return f, name, nil
+}
+
+// writeTemp writes exactly size bytes of r into a temporary file and returns its name and MD5.
+func (s *store) writeTemp(r io.Reader, size int64) (string, []byte, error) {
+	f, name, err := s.createTemp()
+	if err != nil {
+		return "", nil, err
+	}
+
+	hash := md5.New()
+	n, err := io.Copy(io.MultiWriter(f, hash), io.LimitReader(r, size+1))
+	if err == nil {
+		err = f.Sync()
+	}
+
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err == nil && n != size {
+		err = errIncompleteBody()
+	}
+
+	if err != nil {
+		_ = s.root.Remove(name)
+		return "", nil, err
+	}
+
+	return name, hash.Sum(nil), nil
+}
+
+// prepareKey makes sure that an object can be stored at key, creating its parent directories.
+func (s *store) prepareKey(key string) error {
+	dir := path.Dir(key)
+	if dir != "." {
+		// Check that no parent of the key is an object.
+		parent := ""
+		for segment := range strings.SplitSeq(dir, "/") {
+			parent = path.Join(parent, segment)
+
+			fi, err := s.root.Lstat(parent)
+			if errors.Is(err, fs.ErrNotExist) {
+				break
+			} else if err != nil {
+				return err
+			}
+
+			if !fi.IsDir() {
+				return errInvalidArgument("Object key %q conflicts with the existing object %q", key, parent)
+			}
+		}
+
+		err := s.root.MkdirAll(dir, 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	fi, err := s.root.Lstat(key)
+	if err == nil && fi.IsDir() {
+		// Empty directories are leftovers which can be replaced.
+		err = s.root.Remove(key)
+		if err != nil {
+			return errInvalidArgument("Object key %q conflicts with existing objects", key)
+		}
+	}
+
+	return nil
+}
+
+// commit moves the temporary file tmpName to key and records its metadata.
+func (s *store) commit(tmpName string, key string, etag string, headers map[string]string) (*objectInfo, error) {
+	err := s.prepareKey(key)
+	if err != nil {
+		_ = s.root.Remove(tmpName)
+		return nil, err
+	}
+
+	err = s.root.Rename(tmpName, key)
+	if err != nil {
+		_ = s.root.Remove(tmpName)
+		return nil, err
+	}
+
+	fi, err := s.root.Stat(key)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := objectMetadata{
+		Key:     key,
+		ETag:    etag,
+		Size:    fi.Size(),
+		ModTime: fi.ModTime().UnixNano(),
+		Headers: headers,
+	}
+
+	err = s.writeJSON(metadataPath(key), meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &objectInfo{key: key, size: meta.Size, modTime: fi.ModTime(), etag: etag, headers: headers}, nil
+}
+
+// writeJSON atomically writes v as JSON to the file at name.
+func (s *store) writeJSON(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	err = s.root.MkdirAll(path.Dir(name), 0700)
+	if err != nil {
+		return err
+	}
+
+	f, tmpName, err := s.createTemp()
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = s.root.Rename(tmpName, name)
+	}
+
+	if err != nil {
+		_ = s.root.Remove(tmpName)
+		return err
+	}
+
+	return nil
+}
+
+// delete removes an object along with its metadata and the directories it leaves empty.
+func (s *store) delete(key string) error {
+	err := validateKey(key)
+	if err != nil {
+		return err
+	}
+
+	fi, err := s.root.Lstat(key)
+	if errors.Is(err, fs.ErrNotExist) || isNotDir(err) || (err == nil && fi.IsDir()) {
+		// Deleting a missing object succeeds.
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = s.root.Remove(key)
+	if err != nil {
+		return err
+	}
+
+	s.freeQuota(fi.Size())
+
+	_ = s.root.Remove(metadataPath(key))
+
+	for dir := path.Dir(key); dir != "."; dir = path.Dir(dir) {
+		if s.root.Remove(dir) != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// list returns the sorted keys of the objects starting with prefix.
+func (s *store) list(prefix string) ([]string, error) {
+	base := "."
+	i := strings.LastIndex(prefix, "/")
+	if i >= 0 {
+		base = prefix[:i]
+	}
+
+	if !fs.ValidPath(base) {
+		// No valid key can start with the prefix.
+		return nil, nil
+	}
+
+	var keys []string
+	err := fs.WalkDir(s.root.FS(), base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == base {
+				return fs.SkipAll
+			}
+
+			// Skip unreadable directories.
+			return nil
+		}
+
+		if !strings.Contains(p, "/") && slices.Contains(hiddenNames, p) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() {
+			if p != "." && !strings.HasPrefix(p+"/", prefix) && !strings.HasPrefix(prefix, p+"/") {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.Type().IsRegular() && strings.HasPrefix(p, prefix) {
+			keys = append(keys, p)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(keys)
+
+	return keys, nil
+}
+
+// usage returns the disk space used by the bucket.
+func (b *Bucket) usage() (int64, error) {
+	if b.Usage != nil {
+		return b.Usage()
+	}
+
+	return DirUsage(b.Path)
+}
+
+// DirUsage returns the total size of the files within a directory.
+func DirUsage(dir string) (int64, error) {
+	var total int64
+
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err == nil {
+			total += fi.Size()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	return total, nil
+}
+
+// isNotDir returns whether err is caused by a path component not being a directory.
+func isNotDir(err error) bool {
+	return errors.Is(err, syscall.ENOTDIR)
+}
+
+// randomID returns a random hex encoded identifier.
+func randomID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
@@ -2,9 +2,14 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -32,6 +37,7 @@ import (
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/lxd/storage/block"
 	"github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/storage/encryption"
 	"github.com/canonical/lxd/lxd/sys"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
@@ -163,6 +169,37 @@ func VolumeDBContentTypeToContentType(volDBType cluster.StoragePoolVolumeContent
 	return drivers.ContentTypeFS
 }
 
+// hiddenVolumeConfigKeys are the volume config keys that aren't returned by the API.
+var hiddenVolumeConfigKeys = []string{
+	"volatile.encryption.key",
+}
+
+// VolumeAPIConfig returns a copy of the volume config without the keys that aren't returned by the API.
+func VolumeAPIConfig(config map[string]string) map[string]string {
+	apiConfig := maps.Clone(config)
+	for _, key := range hiddenVolumeConfigKeys {
+		delete(apiConfig, key)
+	}
+
+	return apiConfig
+}
+
+// restoreHiddenVolumeConfig copies the keys that aren't returned by the API from the current volume config into a
+// new config that lacks them, so that updating a volume through the API doesn't remove them.
+func restoreHiddenVolumeConfig(newConfig map[string]string, curConfig map[string]string) {
+	if newConfig == nil {
+		return
+	}
+
+	for _, key := range hiddenVolumeConfigKeys {
+		_, found := newConfig[key]
+		value, curFound := curConfig[key]
+		if !found && curFound {
+			newConfig[key] = value
+		}
+	}
+}
+
 // VolumeDBGet loads a volume from the database.
 func VolumeDBGet(pool Pool, projectName string, volumeName string, volumeType drivers.VolumeType) (*db.StorageVolume, error) {
 	p, ok := pool.(*lxdBackend)
@@ -255,6 +292,25 @@ func VolumeDBCreate(pool Pool, projectName string, volumeName string, volumeDesc
 		return err
 	}
 
+	// Generate the key of new encrypted volumes. Snapshots share the key of their parent volume, which may differ
+	// from the key of the source snapshot when the parent volume was re-encrypted by a copy.
+	if snapshot && volumeConfig["volatile.encryption.key"] != "" {
+		parentName, _, _ := api.GetParentAndSnapshotName(volumeName)
+		parentVol, err := VolumeDBGet(pool, projectName, parentName, volumeType)
+		if err != nil && !response.IsNotFoundError(err) {
+			return err
+		}
+
+		if parentVol != nil && parentVol.Config["volatile.encryption.key"] != "" {
+			volumeConfig["volatile.encryption.key"] = parentVol.Config["volatile.encryption.key"]
+		}
+	} else if !snapshot && volumeConfig["block.encryption"] != "" && volumeConfig["volatile.encryption.key"] == "" {
+		volumeConfig["volatile.encryption.key"], err = encryption.NewKey(context.TODO(), p.state.GlobalConfig.StorageEncryptionKeyProvider(), p.state.ServerClustered)
+		if err != nil {
+			return fmt.Errorf("Failed generating encryption key: %w", err)
+		}
+	}
+
 	// Special zfs.promote handling.
 	// We don't want to store this in the database as it's a one-time operation.
 	// So record its value, remove it from the config, and then restore it after DB insertion.
@@ -401,10 +457,99 @@ func BucketDBDelete(ctx context.Context, pool Pool, bucketID int64) error {
 	return nil
 }
 
+// localBucketVolumePrefix is the prefix of the custom volumes holding the buckets of local storage pools.
+const localBucketVolumePrefix = "bucket_"
+
+// LocalBucketVolumeName returns the name of the custom volume holding a bucket of a local storage pool.
+func LocalBucketVolumeName(bucketName string) string {
+	return localBucketVolumePrefix + bucketName
+}
+
+// LocalBucketFromVolumeName returns the name of the bucket held by a custom volume of a local storage pool and
+// whether the volume name matches the naming of bucket volumes.
+func LocalBucketFromVolumeName(volName string) (string, bool) {
+	return strings.CutPrefix(volName, localBucketVolumePrefix)
+}
+
+// localBucketVolumeConfig returns the config of the custom volume holding a bucket of a local storage pool.
+func localBucketVolumeConfig(bucketConfig map[string]string) map[string]string {
+	volConfig := make(map[string]string, len(bucketConfig))
+	for k, v := range bucketConfig {
+		// The volatile keys of the bucket don't apply to its volume.
+		if strings.HasPrefix(k, "volatile.") {
+			continue
+		}
+
+		volConfig[k] = v
+	}
+
+	return volConfig
+}
+
 // poolAndVolumeCommonRules returns a map of pool and volume config common rules common to all drivers.
 // When vol argument is nil function returns pool specific rules.
 func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error {
 	rules := map[string]func(string) error{
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=backups.schedule)
+		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic backups (the default).
+		// Set it to `@never` to disable automatic backups of a volume when `volume.backups.schedule` is set on the pool.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.schedule`
+		//  shortdesc: Schedule for automatic volume backups
+		//  scope: global
+		"backups.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=backups.expiry)
+		// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.expiry`
+		//  shortdesc: Time until scheduled backups are deleted
+		//  scope: global
+		"backups.expiry": func(value string) error {
+			// Validate expression
+			_, err := shared.GetExpiry(time.Time{}, value)
+			return err
+		},
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=backups.retain)
+		// When more scheduled backups exist, the oldest ones are deleted after each scheduled backup.
+		// Backups that were created manually are never deleted.
+		// ---
+		//  type: integer
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.retain` or `0` (unlimited)
+		//  shortdesc: Number of scheduled backups to keep
+		//  scope: global
+		"backups.retain": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=backups.optimized)
+		//
+		// ---
+		//  type: bool
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.optimized` or `false`
+		//  shortdesc: Whether scheduled backups use the storage driver's optimized format
+		//  scope: global
+		"backups.optimized": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=backups.compression)
+		//
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.compression` or `backups.compression_algorithm` of the server
+		//  shortdesc: Compression algorithm for scheduled backups
+		//  scope: global
+		"backups.compression": validate.Optional(validate.IsCompressionAlgorithm),
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=backups.target)
+		// Set this option to upload scheduled backups to a backup target instead of storing them on the server.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.target`
+		//  shortdesc: Backup target to upload scheduled backups to
+		//  scope: global
+		"backups.target": validate.IsAny,
 		// Note: size should not be modifiable for non-custom volumes and should be checked
 		// in the relevant volume update functions.
 
@@ -430,7 +575,7 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		//  shortdesc: Size/quota of the storage volume
 		//  scope: global
 
-		// lxdmeta:generate(entities=storage-cephobject; group=bucket-conf; key=size)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephobject,storage-dir,storage-lvm,storage-zfs; group=bucket-conf; key=size)
 		//
 		// ---
 		//  type: string
@@ -508,6 +653,31 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		rules["security.shared"] = validate.Optional(validate.IsBool)
 	}
 
+	// I/O limits are enforced when attaching custom and instance volumes.
+	if vol == nil || vol.Type() == drivers.VolumeTypeCustom || vol.Type().IsInstance() {
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=limits.iops)
+		// The limit applies to reads and writes separately, and is enforced on the disk devices backed by the volume unless they set their own I/O limits.
+		// See {ref}`storage-configure-IO`.
+		// ---
+		//  type: integer
+		//  condition: custom or instance volume
+		//  defaultdesc: same as `volume.limits.iops`
+		//  shortdesc: I/O operations per second limit of the storage volume
+		//  scope: global
+		rules["limits.iops"] = validate.Optional(validate.IsUint32)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=limits.bandwidth)
+		// Specify the value in byte/s (various suffixes supported, see {ref}`instances-limit-units`).
+		// The limit applies to reads and writes separately, and is enforced on the disk devices backed by the volume unless they set their own I/O limits.
+		// See {ref}`storage-configure-IO`.
+		// ---
+		//  type: string
+		//  condition: custom or instance volume
+		//  defaultdesc: same as `volume.limits.bandwidth`
+		//  shortdesc: Bandwidth limit of the storage volume
+		//  scope: global
+		rules["limits.bandwidth"] = validate.Optional(validate.IsSize)
+	}
+
 	// Those keys are only valid for volumes.
 	if vol != nil {
 		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.uuid)
@@ -1183,6 +1353,41 @@ func VolumeUsedByDaemon(s *state.State, poolName string, volumeName string) (boo
 	return false, nil
 }
 
+// VolumeUsedByLocalBucket returns the bucket of a local storage pool held by the custom volume, or nil if the
+// volume doesn't hold a bucket.
+func VolumeUsedByLocalBucket(s *state.State, poolName string, projectName string, volumeName string) (*db.StorageBucket, error) {
+	bucketName, ok := LocalBucketFromVolumeName(volumeName)
+	if !ok {
+		return nil, nil
+	}
+
+	var bucket *db.StorageBucket
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		buckets, err := tx.GetStoragePoolBuckets(ctx, true, db.StorageBucketFilter{
+			PoolName: &poolName,
+			Project:  &projectName,
+			Name:     &bucketName,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, b := range buckets {
+			if b.Location != "" {
+				bucket = b
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bucket, nil
+}
+
 // FallbackMigrationType returns the fallback migration transport to use based on volume content type.
 func FallbackMigrationType(contentType drivers.ContentType) migration.MigrationFSType {
 	if drivers.IsContentBlock(contentType) {
@@ -1192,6 +1397,24 @@ func FallbackMigrationType(contentType drivers.ContentType) migration.MigrationF
 	return migration.MigrationFSType_RSYNC
 }
 
+// volumeCopyEncrypted indicates whether a copy between volumes with the given configs involves an encrypted volume.
+// Such copies transfer the volume in its decrypted form so that the copy is encrypted with its own key.
+func volumeCopyEncrypted(srcConfig map[string]string, config map[string]string) bool {
+	return srcConfig["block.encryption"] != "" || config["block.encryption"] != ""
+}
+
+// decryptedMigrationTypes returns the migration types that transfer volumes in their decrypted form.
+func decryptedMigrationTypes(types []migration.Type) []migration.Type {
+	decryptedTypes := make([]migration.Type, 0, len(types))
+	for _, migrationType := range types {
+		if migrationType.FSType == migration.MigrationFSType_RSYNC || migrationType.FSType == migration.MigrationFSType_BLOCK_AND_RSYNC {
+			decryptedTypes = append(decryptedTypes, migrationType)
+		}
+	}
+
+	return decryptedTypes
+}
+
 // RenderSnapshotUsage can be used as an optional argument to Instance.Render() to return snapshot usage.
 // As this is a relatively expensive operation it is provided as an optional feature rather than on by default.
 func RenderSnapshotUsage(s *state.State, snapInst instance.Instance) func(response any) error {
@@ -1419,3 +1642,105 @@ func VolumeDetermineNextSnapshotName(ctx context.Context, s *state.State, pool s
 
 	return pattern, nil
 }
+
+// verifyVolumeDisk reads the whole block device or file at diskPath and returns its SHA-256 checksum.
+// Returns drivers.ErrCorrupted if the data can't be read back.
+func verifyVolumeDisk(diskPath string, progressReporter ioprogress.ProgressReporter) (string, error) {
+	diskSize, err := block.DiskSizeBytes(diskPath)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(diskPath)
+	if err != nil {
+		return "", err
+	}
+
+	rc := ioprogress.NewProgressReader(f, ioprogress.WithProgressReporter("verify", progressReporter), ioprogress.WithLength(diskSize))
+	defer func() { _ = rc.Close() }()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, rc)
+	if err != nil {
+		if errors.Is(err, unix.EIO) {
+			return "", fmt.Errorf("%w in %q: %v", drivers.ErrCorrupted, diskPath, err)
+		}
+
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// verifyVolumeFiles reads every regular file below mountPath.
+// Returns drivers.ErrCorrupted listing the files that can't be read back.
+func verifyVolumeFiles(mountPath string, progressReporter ioprogress.ProgressReporter) error {
+	var corrupted []string
+
+	progressWrapper := ioprogress.NewProgressReaderWrapper(ioprogress.WithProgressReporter("verify", progressReporter))
+
+	err := filepath.WalkDir(mountPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Files may be removed while the volume is in use.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			if errors.Is(err, unix.EIO) {
+				corrupted = append(corrupted, path)
+				return nil
+			}
+
+			return err
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			if errors.Is(err, unix.EIO) {
+				corrupted = append(corrupted, path)
+				return nil
+			}
+
+			return err
+		}
+
+		rc := progressWrapper(f)
+		_, err = io.Copy(io.Discard, rc)
+		_ = rc.Close()
+		if err != nil {
+			if errors.Is(err, unix.EIO) {
+				corrupted = append(corrupted, path)
+				return nil
+			}
+
+			return fmt.Errorf("Failed reading %q: %w", path, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(corrupted) > 0 {
+		for i := range corrupted {
+			corrupted[i], _ = filepath.Rel(mountPath, corrupted[i])
+		}
+
+		if len(corrupted) > 10 {
+			return fmt.Errorf("%w in %d files including: %s", drivers.ErrCorrupted, len(corrupted), strings.Join(corrupted[:10], ", "))
+		}
+
+		return fmt.Errorf("%w in files: %s", drivers.ErrCorrupted, strings.Join(corrupted, ", "))
+	}
+
+	return nil
+}
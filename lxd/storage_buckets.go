@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/db"
@@ -16,11 +18,16 @@ import (
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/storage/s3"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/version"
 )
 
@@ -290,9 +297,12 @@ func storagePoolBucketsGet(d *Daemon, r *http.Request) response.Response {
 		buckets := make([]*api.StorageBucket, 0, len(filteredDBBuckets))
 		urlToStorageBucket := make(map[*api.URL]auth.EntitlementReporter, len(filteredDBBuckets))
 		for _, dbBucket := range filteredDBBuckets {
-			u := pool.GetBucketURL(dbBucket.Name)
-			if u != nil {
-				dbBucket.S3URL = u.String()
+			// Buckets of local storage pools are served by the member holding them.
+			if driverInfo.Remote || dbBucket.Location == s.ServerName {
+				u := pool.GetBucketURL(dbBucket.Name)
+				if u != nil {
+					dbBucket.S3URL = u.String()
+				}
 			}
 
 			buckets = append(buckets, &dbBucket.StorageBucket)
@@ -366,6 +376,11 @@ func storagePoolBucketGet(d *Daemon, r *http.Request) response.Response {
 		return resp
 	}
 
+	resp = forwardedResponseIfBucketIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
 	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
 	if err != nil {
 		return response.SmartError(err)
@@ -614,6 +629,11 @@ func storagePoolBucketPut(d *Daemon, r *http.Request) response.Response {
 		return resp
 	}
 
+	resp = forwardedResponseIfBucketIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
 	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
 	if err != nil {
 		return response.SmartError(err)
@@ -733,6 +753,11 @@ func storagePoolBucketDelete(d *Daemon, r *http.Request) response.Response {
 		return resp
 	}
 
+	resp = forwardedResponseIfBucketIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
 	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
 	if err != nil {
 		return response.SmartError(err)
@@ -779,6 +804,9 @@ func storagePoolBucketDelete(d *Daemon, r *http.Request) response.Response {
 
 // doStorageBucketDelete deletes a storage bucket in the given project and pool.
 func doStorageBucketDelete(pool storagePools.Pool, projectName string, name string) error {
+	// Release the volume of the bucket if the built-in S3 gateway kept it mounted.
+	storageBucketUnmount(pool.Name(), projectName, name)
+
 	err := pool.DeleteBucket(projectName, name)
 	if err != nil {
 		return fmt.Errorf("Failed deleting storage bucket %q: %w", name, err)
@@ -890,6 +918,11 @@ func storagePoolBucketKeysGet(d *Daemon, r *http.Request) response.Response {
 		return resp
 	}
 
+	resp = forwardedResponseIfBucketIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
 	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
 	if err != nil {
 		return response.SmartError(err)
@@ -987,6 +1020,11 @@ func storagePoolBucketKeysPost(d *Daemon, r *http.Request) response.Response {
 		return resp
 	}
 
+	resp = forwardedResponseIfBucketIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
 	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
 	if err != nil {
 		return response.SmartError(err)
@@ -1081,6 +1119,11 @@ func storagePoolBucketKeyDelete(d *Daemon, r *http.Request) response.Response {
 		return resp
 	}
 
+	resp = forwardedResponseIfBucketIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
 	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
 	if err != nil {
 		return response.SmartError(err)
@@ -1175,6 +1218,11 @@ func storagePoolBucketKeyGet(d *Daemon, r *http.Request) response.Response {
 		return resp
 	}
 
+	resp = forwardedResponseIfBucketIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
 	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
 	if err != nil {
 		return response.SmartError(err)
@@ -1262,6 +1310,11 @@ func storagePoolBucketKeyPut(d *Daemon, r *http.Request) response.Response {
 		return resp
 	}
 
+	resp = forwardedResponseIfBucketIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
 	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
 	if err != nil {
 		return response.SmartError(err)
@@ -1325,6 +1378,10 @@ const ctxStorageBucketDetails request.CtxKey = "storage-bucket-details"
 type storageBucketDetails struct {
 	bucketName string
 	pool       storagePools.Pool
+
+	// forwardingNodeInfo is the cluster member holding the bucket when it's on a local storage pool of
+	// another member and no target was specified.
+	forwardingNodeInfo *db.NodeInfo
 }
 
 // addStorageBucketDetailsToContext extracts storageBucketDetails from the http.Request and adds it to the
@@ -1358,5 +1415,255 @@ func addStorageBucketDetailsToContext(d *Daemon, r *http.Request) error {
 	bucketName := r.PathValue("bucketName")
 
 	details.bucketName = bucketName
-	return nil
+
+	// Buckets of remote storage pools are available on all members. Otherwise when no target is specified, find
+	// the member holding the bucket unless the request has already been forwarded by another member.
+	if pool.Driver().Info().Remote || !s.ServerClustered || bucketName == "" || request.QueryParam(r, "target") != "" {
+		return nil
+	}
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return err
+	}
+
+	if requestor.IsForwarded() {
+		return nil
+	}
+
+	return s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		bucket, err := tx.GetStoragePoolBucket(ctx, pool.ID(), effectiveProjectName, false, bucketName)
+		if err != nil {
+			// Let the handlers report missing or ambiguous buckets.
+			if api.StatusErrorCheck(err, http.StatusNotFound, http.StatusConflict) {
+				return nil
+			}
+
+			return err
+		}
+
+		if bucket.Location == s.ServerName {
+			return nil
+		}
+
+		nodeInfo, err := tx.GetNodeByName(ctx, bucket.Location)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster member of storage bucket %q: %w", bucketName, err)
+		}
+
+		details.forwardingNodeInfo = &nodeInfo
+
+		return nil
+	})
+}
+
+// storageBucketCredentials returns the credentials of the bucket key of a local storage pool bucket with the given
+// access key, or nil if there is none on this member.
+func storageBucketCredentials(ctx context.Context, s *state.State, accessKey string) (*s3.Credentials, error) {
+	var creds *s3.Credentials
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		bucket, err := tx.GetStoragePoolLocalBucketByAccessKey(ctx, accessKey)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		bucketKeys, err := tx.GetStoragePoolBucketKeys(ctx, bucket.ID, db.StorageBucketKeyFilter{AccessKey: &accessKey})
+		if err != nil {
+			return err
+		}
+
+		if len(bucketKeys) != 1 {
+			return fmt.Errorf("Found %d keys with the same access key for storage bucket %q", len(bucketKeys), bucket.Name)
+		}
+
+		creds = &s3.Credentials{
+			AccessKey: bucketKeys[0].AccessKey,
+			SecretKey: bucketKeys[0].SecretKey,
+			Bucket:    bucket.Name,
+			ReadOnly:  bucketKeys[0].Role != "admin",
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return creds, nil
+}
+
+// storageBucketMountIdleTimeout is how long the custom volume holding a local storage pool bucket stays mounted after
+// the last request of the built-in S3 gateway, so that successive requests don't mount it again.
+const storageBucketMountIdleTimeout = 5 * time.Minute
+
+// storageBucketMount is a mount of the custom volume holding a local storage pool bucket, shared by the requests of
+// the built-in S3 gateway.
+type storageBucketMount struct {
+	pool     storagePools.Pool
+	project  string
+	volName  string
+	refCount int
+	timer    *time.Timer
+}
+
+// unmount unmounts the volume of the bucket.
+func (m *storageBucketMount) unmount() {
+	_, err := m.pool.UnmountCustomVolume(m.project, m.volName, nil)
+	if err != nil && !errors.Is(err, storageDrivers.ErrInUse) {
+		logger.Warn("Failed unmounting volume of storage bucket", logger.Ctx{"pool": m.pool.Name(), "project": m.project, "volume": m.volName, "err": err})
+	}
+}
+
+// storageBucketMounts are the mounted volumes of local storage pool buckets, keyed by pool, project and volume name.
+var storageBucketMounts = map[string]*storageBucketMount{}
+var storageBucketMountsMu sync.Mutex
+
+// storageBucketMountKey returns the key of the volume of a local storage pool bucket in storageBucketMounts.
+func storageBucketMountKey(poolName string, projectName string, volName string) string {
+	return poolName + "/" + projectName + "/" + volName
+}
+
+// storageBucketLoad returns the local storage pool bucket for the built-in S3 gateway without mounting its volume,
+// along with its pool, or a nil bucket if there is none on this member.
+func storageBucketLoad(ctx context.Context, s *state.State, bucketName string) (*s3.Bucket, storagePools.Pool, *db.StorageBucket, error) {
+	var bucket *db.StorageBucket
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		bucket, err = tx.GetStoragePoolLocalBucket(ctx, bucketName)
+		return err
+	})
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, nil, nil, nil
+		}
+
+		return nil, nil, nil, err
+	}
+
+	pool, err := storagePools.LoadByName(s, bucket.PoolName)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed loading storage pool: %w", err)
+	}
+
+	volName := storagePools.LocalBucketVolumeName(bucket.Name)
+
+	vol, err := storagePools.VolumeDBGet(pool, bucket.Project, volName, storageDrivers.VolumeTypeCustom)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed loading volume of storage bucket %q: %w", bucket.Name, err)
+	}
+
+	var quota int64
+	if bucket.Config["size"] != "" {
+		quota, err = units.ParseByteSizeString(bucket.Config["size"])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("Failed parsing size of storage bucket %q: %w", bucket.Name, err)
+		}
+	}
+
+	volStorageName := project.StorageVolume(bucket.Project, volName)
+	mountPath := storageDrivers.GetVolumeMountPath(pool.Name(), storageDrivers.VolumeTypeCustom, volStorageName)
+
+	s3Bucket := &s3.Bucket{
+		Name:      bucket.Name,
+		Path:      mountPath,
+		CreatedAt: vol.CreatedAt,
+		Quota:     quota,
+		Usage: func() (int64, error) {
+			usage, err := pool.GetCustomVolumeUsage(bucket.Project, volName)
+			if err != nil {
+				return -1, err
+			}
+
+			// Fallback to the size of the files for drivers unable to report the usage of volumes.
+			if usage.Used <= 0 {
+				return s3.DirUsage(mountPath)
+			}
+
+			return usage.Used, nil
+		},
+	}
+
+	return s3Bucket, pool, bucket, nil
+}
+
+// storageBucketOpen mounts the custom volume holding a local storage pool bucket for the built-in S3 gateway and
+// returns the bucket along with a function releasing the volume, or a nil bucket if there is none on this member.
+// The volume is shared by concurrent requests and only unmounted once unused for storageBucketMountIdleTimeout.
+func storageBucketOpen(ctx context.Context, s *state.State, bucketName string) (*s3.Bucket, func(), error) {
+	s3Bucket, pool, bucket, err := storageBucketLoad(ctx, s, bucketName)
+	if err != nil || s3Bucket == nil {
+		return nil, nil, err
+	}
+
+	volName := storagePools.LocalBucketVolumeName(bucket.Name)
+	key := storageBucketMountKey(pool.Name(), bucket.Project, volName)
+
+	storageBucketMountsMu.Lock()
+	defer storageBucketMountsMu.Unlock()
+
+	m := storageBucketMounts[key]
+	if m == nil {
+		_, err = pool.MountCustomVolume(bucket.Project, volName, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed mounting volume of storage bucket %q: %w", bucket.Name, err)
+		}
+
+		m = &storageBucketMount{pool: pool, project: bucket.Project, volName: volName}
+		storageBucketMounts[key] = m
+	} else if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+
+	m.refCount++
+
+	release := func() {
+		storageBucketMountsMu.Lock()
+		defer storageBucketMountsMu.Unlock()
+
+		m.refCount--
+		if m.refCount > 0 {
+			return
+		}
+
+		m.timer = time.AfterFunc(storageBucketMountIdleTimeout, func() {
+			storageBucketMountsMu.Lock()
+			defer storageBucketMountsMu.Unlock()
+
+			if m.refCount > 0 || storageBucketMounts[key] != m {
+				return
+			}
+
+			delete(storageBucketMounts, key)
+			m.unmount()
+		})
+	}
+
+	return s3Bucket, release, nil
+}
+
+// storageBucketUnmount unmounts the volume of a local storage pool bucket kept mounted by the built-in S3 gateway,
+// unless it is being used by a request.
+func storageBucketUnmount(poolName string, projectName string, bucketName string) {
+	key := storageBucketMountKey(poolName, projectName, storagePools.LocalBucketVolumeName(bucketName))
+
+	storageBucketMountsMu.Lock()
+	defer storageBucketMountsMu.Unlock()
+
+	m := storageBucketMounts[key]
+	if m == nil || m.refCount > 0 {
+		return
+	}
+
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+
+	delete(storageBucketMounts, key)
+	m.unmount()
 }
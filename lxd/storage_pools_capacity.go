@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// storagePoolCapacityDrivers are the storage drivers supporting the pool capacity thresholds.
+var storagePoolCapacityDrivers = []string{"ceph", "lvm", "zfs"}
+
+// storagePoolCapacityDefaultThreshold is the default used space percentage raising a warning.
+const storagePoolCapacityDefaultThreshold = 90
+
+// storagePoolCapacityDefaultMetadataThreshold is the default used thin pool metadata percentage raising a warning.
+const storagePoolCapacityDefaultMetadataThreshold = 80
+
+// storagePoolCapacity caches the resources of the local storage pools as last checked, for use by the metrics endpoint.
+var storagePoolCapacity map[string]api.ResourcesStoragePool
+var storagePoolCapacityMu sync.Mutex
+
+func storagePoolCapacityTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := storagePoolCapacityCheck(ctx, stateFunc())
+		if err != nil {
+			logger.Error("Failed checking storage pool capacity", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(5 * time.Minute)
+}
+
+// storagePoolCapacityCheck retrieves the resources of the storage pools available on this member.
+// A warning is raised for the pools whose used space or thin pool metadata exceeds the configured thresholds,
+// and any existing warning is resolved otherwise.
+func storagePoolCapacityCheck(ctx context.Context, s *state.State) error {
+	var poolNames []string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+
+		return err
+	})
+	if err != nil && !response.IsNotFoundError(err) {
+		return fmt.Errorf("Failed loading storage pools: %w", err)
+	}
+
+	capacity := make(map[string]api.ResourcesStoragePool, len(poolNames))
+
+	for _, poolName := range poolNames {
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			logger.Warn("Failed loading storage pool", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		if pool.LocalStatus() != api.StoragePoolStatusCreated {
+			continue
+		}
+
+		res, err := pool.GetResources()
+		if err != nil {
+			logger.Warn("Failed getting storage pool resources", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		capacity[poolName] = *res
+
+		if !slices.Contains(storagePoolCapacityDrivers, pool.Driver().Info().Name) {
+			continue
+		}
+
+		config := pool.Driver().Config()
+
+		// The thin pool metadata is reported as part of the pool space, so exclude it from the data usage.
+		space := res.Space
+		if res.Metadata != nil {
+			space.Used -= res.Metadata.Used
+			space.Total -= res.Metadata.Total
+		}
+
+		storagePoolCapacityWarning(ctx, s, pool, warningtype.StoragePoolSpaceThreshold, "space", space, config["capacity.threshold"], storagePoolCapacityDefaultThreshold)
+
+		if res.Metadata != nil {
+			storagePoolCapacityWarning(ctx, s, pool, warningtype.StoragePoolMetadataThreshold, "thin pool metadata", *res.Metadata, config["lvm.thinpool_metadata_threshold"], storagePoolCapacityDefaultMetadataThreshold)
+		}
+	}
+
+	storagePoolCapacityMu.Lock()
+	storagePoolCapacity = capacity
+	storagePoolCapacityMu.Unlock()
+
+	return nil
+}
+
+// storagePoolCapacityWarning raises a warning of the given type if the used space exceeds the threshold percentage
+// and resolves any existing warning otherwise.
+func storagePoolCapacityWarning(ctx context.Context, s *state.State, pool storagePools.Pool, warningType warningtype.Type, description string, space api.ResourcesStoragePoolSpace, thresholdValue string, defaultThreshold uint64) {
+	threshold := defaultThreshold
+	if thresholdValue != "" {
+		value, err := strconv.ParseUint(thresholdValue, 10, 64)
+		if err == nil {
+			threshold = value
+		}
+	}
+
+	if space.Total == 0 || space.Used*100 < space.Total*threshold {
+		_ = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningType, entity.TypeStoragePool, int(pool.ID()))
+		return
+	}
+
+	msg := fmt.Sprintf("Storage pool %q %s usage is %d%% (%s used out of %s), above the %d%% threshold", pool.Name(), description, space.Used*100/space.Total, units.GetByteSizeStringIEC(int64(space.Used), 2), units.GetByteSizeStringIEC(int64(space.Total), 2), threshold)
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertWarningLocalNode(ctx, "", entity.TypeStoragePool, int(pool.ID()), warningType, msg)
+	})
+	if err != nil {
+		logger.Warn("Failed creating storage pool capacity warning", logger.Ctx{"pool": pool.Name(), "err": err})
+	}
+}
+
+// storagePoolCapacityMetrics adds the last checked resources of the local storage pools to the metric set.
+func storagePoolCapacityMetrics(out *metrics.MetricSet) {
+	storagePoolCapacityMu.Lock()
+	defer storagePoolCapacityMu.Unlock()
+
+	for poolName, res := range storagePoolCapacity {
+		labels := map[string]string{"pool": poolName}
+
+		out.AddSamples(metrics.StoragePoolSizeBytes, metrics.Sample{Labels: labels, Value: float64(res.Space.Total)})
+		out.AddSamples(metrics.StoragePoolUsedBytes, metrics.Sample{Labels: labels, Value: float64(res.Space.Used)})
+
+		if res.Space.Provisioned > 0 {
+			out.AddSamples(metrics.StoragePoolProvisionedBytes, metrics.Sample{Labels: labels, Value: float64(res.Space.Provisioned)})
+		}
+
+		if res.Metadata != nil {
+			out.AddSamples(metrics.StoragePoolMetadataSizeBytes, metrics.Sample{Labels: labels, Value: float64(res.Metadata.Total)})
+			out.AddSamples(metrics.StoragePoolMetadataUsedBytes, metrics.Sample{Labels: labels, Value: float64(res.Metadata.Used)})
+		}
+	}
+}
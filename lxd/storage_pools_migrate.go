@@ -0,0 +1,652 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project/limits"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/ioprogress"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+)
+
+var storagePoolMigrateCmd = APIEndpoint{
+	Path:        "storage-pools/{poolName}/migrate",
+	MetricsType: entity.TypeStoragePool,
+
+	Post: APIEndpointAction{Handler: storagePoolMigratePost, AccessHandler: allowPermission(entity.TypeStoragePool, auth.EntitlementCanEdit, "poolName")},
+}
+
+func init() {
+	operations.RegisterDurableOperationRunHook(operationtype.StoragePoolMigrate, storagePoolMigrateOperationRunHook)
+}
+
+const (
+	operationInputKeyStoragePoolMigratePool    operations.InputKey = "pool"
+	operationInputKeyStoragePoolMigrateRequest operations.InputKey = "request"
+	operationInputKeyStoragePoolMigrateMember  operations.InputKey = "member"
+)
+
+// The volume being moved is recorded in the metadata of a storage pool migration operation, so that a restarted
+// operation can clean up after the interrupted move before carrying on.
+const (
+	storagePoolMigrateMetadataType     = "migrating_type"
+	storagePoolMigrateMetadataProject  = "migrating_project"
+	storagePoolMigrateMetadataName     = "migrating_name"
+	storagePoolMigrateMetadataTempName = "migrating_temp_name"
+)
+
+// swagger:operation POST /1.0/storage-pools/{poolName}/migrate storage storage_pool_migrate_post
+//
+//	Migrate the storage pool
+//
+//	Moves all instances, custom volumes and image volumes of the storage pool to another storage pool.
+//	Profile root disk devices using the storage pool are updated to use the target pool once no instance uses it anymore.
+//	Instances and instances using the custom volumes must be stopped.
+//	When clustered, the volumes located on the targeted cluster member are moved.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	  - in: body
+//	    name: storage pool
+//	    description: Storage pool migration request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/StoragePoolMigratePost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolMigratePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseToNode(r.Context(), s, request.QueryParam(r, "target"))
+	if resp != nil {
+		return resp
+	}
+
+	poolName := r.PathValue("poolName")
+	srcPool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.StoragePoolMigratePost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Pool == "" {
+		return response.BadRequest(errors.New("No target storage pool provided"))
+	}
+
+	if req.Pool == srcPool.Name() {
+		return response.BadRequest(errors.New("Target storage pool must be different from the source storage pool"))
+	}
+
+	// Moving volumes into the target pool requires the same permission as for the source pool.
+	err = s.Authorizer.CheckPermission(r.Context(), entity.StoragePoolURL(req.Pool), auth.EntitlementCanEdit)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	dstPool, err := storagePools.LoadByName(s, req.Pool)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if dstPool.Status() != api.StoragePoolStatusCreated {
+		return response.BadRequest(fmt.Errorf("Target storage pool %q is not in created state", dstPool.Name()))
+	}
+
+	err = storagePoolMigrateCheck(r.Context(), s, srcPool)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	args := operations.OperationArgs{
+		EntityURL:         entity.StoragePoolURL(poolName),
+		Type:              operationtype.StoragePoolMigrate,
+		Class:             operationtype.OperationClassDurable,
+		ConflictReference: entity.StoragePoolURL(poolName).String(),
+		Metadata:          map[string]any{},
+	}
+
+	err = args.SetInputValue(operationInputKeyStoragePoolMigratePool, poolName)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = args.SetInputValue(operationInputKeyStoragePoolMigrateRequest, req)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = args.SetInputValue(operationInputKeyStoragePoolMigrateMember, s.ServerName)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// storagePoolMigrateOperationRunHook is the run hook of the durable [operationtype.StoragePoolMigrate] operation.
+// It moves the instances, custom volumes and image volumes left on the source pool, so that a restarted operation
+// carries on where the interrupted one stopped.
+func storagePoolMigrateOperationRunHook(ctx context.Context, op *operations.Operation) error {
+	s := op.State()
+
+	poolName, err := operations.GetOperationInputValue[string](op, operationInputKeyStoragePoolMigratePool)
+	if err != nil {
+		return err
+	}
+
+	req, err := operations.GetOperationInputValue[api.StoragePoolMigratePost](op, operationInputKeyStoragePoolMigrateRequest)
+	if err != nil {
+		return err
+	}
+
+	memberName, err := operations.GetOperationInputValue[string](op, operationInputKeyStoragePoolMigrateMember)
+	if err != nil {
+		return err
+	}
+
+	// The volumes of local pools can only be moved by the member holding them.
+	if memberName != s.ServerName {
+		return fmt.Errorf("Migration of storage pool %q was interrupted on cluster member %q and must be run again on that member", poolName, memberName)
+	}
+
+	srcPool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return err
+	}
+
+	dstPool, err := storagePools.LoadByName(s, req.Pool)
+	if err != nil {
+		return err
+	}
+
+	err = storagePoolMigrateResume(ctx, s, srcPool, dstPool, op)
+	if err != nil {
+		return fmt.Errorf("Failed cleaning up interrupted move: %w", err)
+	}
+
+	err = storagePoolMigrateCheck(ctx, s, srcPool)
+	if err != nil {
+		return err
+	}
+
+	vols, err := storagePoolMigrateVolumes(ctx, s, srcPool, true)
+	if err != nil {
+		return err
+	}
+
+	// Move the instances first, then the custom volumes they may be using and finally the image volumes which
+	// the instance volumes may have been created from.
+	var instances []instance.Instance
+	var customVols []*api.StorageVolume
+	var images []string
+	for _, vol := range vols {
+		switch vol.Type {
+		case dbCluster.StoragePoolVolumeTypeNameContainer, dbCluster.StoragePoolVolumeTypeNameVM:
+			inst, err := instance.LoadByProjectAndName(s, vol.Project, vol.Name)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q in project %q: %w", vol.Name, vol.Project, err)
+			}
+
+			if s.ServerClustered && inst.Location() != s.ServerName {
+				continue
+			}
+
+			instances = append(instances, inst)
+		case dbCluster.StoragePoolVolumeTypeNameCustom:
+			customVols = append(customVols, &vol.StorageVolume)
+		case dbCluster.StoragePoolVolumeTypeNameImage:
+			images = append(images, vol.Name)
+		}
+	}
+
+	total := len(instances) + len(customVols) + len(images)
+	done := 0
+
+	for _, inst := range instances {
+		done++
+		reportStoragePoolMigrateProgress(op, fmt.Sprintf("Moving instance %q in project %q (%d/%d)", inst.Name(), inst.Project().Name, done, total))
+
+		tempName, err := instance.MoveTemporaryName(inst)
+		if err != nil {
+			return err
+		}
+
+		err = storagePoolMigrateSetCurrent(op, string(entity.TypeInstance), inst.Project().Name, inst.Name(), tempName)
+		if err != nil {
+			return err
+		}
+
+		instReq := api.InstancePost{
+			Name: inst.Name(),
+			Pool: dstPool.Name(),
+		}
+
+		err = instancePostMigration(ctx, s, inst, instReq, nil, "", op)
+		if err != nil {
+			return fmt.Errorf("Failed moving instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+		}
+	}
+
+	for _, vol := range customVols {
+		done++
+		reportStoragePoolMigrateProgress(op, fmt.Sprintf("Moving custom volume %q in project %q (%d/%d)", vol.Name, vol.Project, done, total))
+
+		err = storagePoolMigrateSetCurrent(op, string(entity.TypeStorageVolume), vol.Project, vol.Name, "")
+		if err != nil {
+			return err
+		}
+
+		err = storagePoolMigrateCustomVolume(ctx, s, srcPool, dstPool, vol, op)
+		if err != nil {
+			return fmt.Errorf("Failed moving custom volume %q in project %q: %w", vol.Name, vol.Project, err)
+		}
+	}
+
+	for _, fingerprint := range images {
+		done++
+		reportStoragePoolMigrateProgress(op, fmt.Sprintf("Moving image volume %q (%d/%d)", fingerprint, done, total))
+
+		// Image volumes are only a cache of the image files, recreate them from the local image file if any.
+		if shared.PathExists(shared.VarPath("images", fingerprint)) {
+			_, err = dstPool.EnsureImage(ctx, fingerprint, api.ProjectDefaultName, nil, op)
+			if err != nil {
+				return fmt.Errorf("Failed creating image volume %q: %w", fingerprint, err)
+			}
+		}
+
+		err = srcPool.DeleteImage(ctx, fingerprint, op)
+		if err != nil {
+			return fmt.Errorf("Failed deleting image volume %q: %w", fingerprint, err)
+		}
+	}
+
+	err = storagePoolMigrateSetCurrent(op, "", "", "", "")
+	if err != nil {
+		return err
+	}
+
+	// Profile root disk devices can only be pointed at the target pool once no instance relies on them anymore.
+	remainingVols, err := storagePoolMigrateVolumes(ctx, s, srcPool, false)
+	if err != nil {
+		return err
+	}
+
+	instancesRemaining := false
+	for _, vol := range remainingVols {
+		if vol.Type == dbCluster.StoragePoolVolumeTypeNameContainer || vol.Type == dbCluster.StoragePoolVolumeTypeNameVM {
+			instancesRemaining = true
+			break
+		}
+	}
+
+	if instancesRemaining {
+		reportStoragePoolMigrateProgress(op, "Not updating profiles as instances on other cluster members still use the storage pool")
+	} else {
+		reportStoragePoolMigrateProgress(op, "Updating profiles")
+
+		err = storagePoolMigrateProfiles(ctx, s, srcPool.Name(), dstPool.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	s.Events.SendLifecycle("", lifecycle.StoragePoolMigrated.Event(srcPool.Name(), op.EventLifecycleRequestor(), map[string]any{"pool": dstPool.Name()}))
+
+	return nil
+}
+
+func reportStoragePoolMigrateProgress(progressReporter ioprogress.ProgressReporter, message string) {
+	if progressReporter == nil {
+		return
+	}
+
+	handler := progressReporter.ProgressHandler("migrate")
+	handler(ioprogress.ProgressData{Text: message})
+}
+
+// storagePoolMigrateVolumes returns the storage volumes of a pool, excluding snapshots.
+// If memberSpecific is true, only the volumes available on this member are returned.
+func storagePoolMigrateVolumes(ctx context.Context, s *state.State, pool storagePools.Pool, memberSpecific bool) ([]*db.StorageVolume, error) {
+	poolID := pool.ID()
+
+	var dbVols []*db.StorageVolume
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		dbVols, err = tx.GetStorageVolumes(ctx, memberSpecific, db.StorageVolumeFilter{PoolID: &poolID})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading storage volumes of pool %q: %w", pool.Name(), err)
+	}
+
+	vols := make([]*db.StorageVolume, 0, len(dbVols))
+	for _, vol := range dbVols {
+		if shared.IsSnapshot(vol.Name) {
+			continue
+		}
+
+		vols = append(vols, vol)
+	}
+
+	return vols, nil
+}
+
+// storagePoolMigrateCheck checks that the volumes of a pool available on this member can be moved.
+func storagePoolMigrateCheck(ctx context.Context, s *state.State, pool storagePools.Pool) error {
+	vols, err := storagePoolMigrateVolumes(ctx, s, pool, true)
+	if err != nil {
+		return err
+	}
+
+	for _, vol := range vols {
+		switch vol.Type {
+		case dbCluster.StoragePoolVolumeTypeNameContainer, dbCluster.StoragePoolVolumeTypeNameVM:
+			inst, err := instance.LoadByProjectAndName(s, vol.Project, vol.Name)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q in project %q: %w", vol.Name, vol.Project, err)
+			}
+
+			if s.ServerClustered && inst.Location() != s.ServerName {
+				continue
+			}
+
+			if inst.IsRunning() {
+				return api.StatusErrorf(http.StatusBadRequest, "Instance %q in project %q must be stopped", vol.Name, vol.Project)
+			}
+
+		case dbCluster.StoragePoolVolumeTypeNameCustom:
+			used, err := storagePools.VolumeUsedByDaemon(s, pool.Name(), vol.Name)
+			if err != nil {
+				return err
+			}
+
+			if used {
+				return api.StatusErrorf(http.StatusBadRequest, "Custom volume %q is used by LXD itself and cannot be moved", vol.Name)
+			}
+
+			bucket, err := storagePools.VolumeUsedByLocalBucket(s, pool.Name(), vol.Project, vol.Name)
+			if err != nil {
+				return err
+			}
+
+			if bucket != nil {
+				return api.StatusErrorf(http.StatusBadRequest, "Custom volume %q holds storage bucket %q in project %q and cannot be moved", vol.Name, bucket.Name, vol.Project)
+			}
+
+			err = storagePools.VolumeUsedByInstanceDevices(s, pool.Name(), vol.Project, &vol.StorageVolume, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
+				inst, err := instance.Load(s, dbInst, project)
+				if err != nil {
+					return err
+				}
+
+				if inst.IsRunning() {
+					return api.StatusErrorf(http.StatusBadRequest, "Custom volume %q in project %q is still in use by running instance %q", vol.Name, vol.Project, inst.Name())
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// storagePoolMigrateSetCurrent records the volume being moved in the operation metadata.
+func storagePoolMigrateSetCurrent(op *operations.Operation, volType string, projectName string, name string, tempName string) error {
+	metadata := op.Metadata()
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+
+	metadata[storagePoolMigrateMetadataType] = volType
+	metadata[storagePoolMigrateMetadataProject] = projectName
+	metadata[storagePoolMigrateMetadataName] = name
+	metadata[storagePoolMigrateMetadataTempName] = tempName
+
+	err := op.UpdateMetadata(metadata)
+	if err != nil {
+		return fmt.Errorf("Failed updating operation metadata: %w", err)
+	}
+
+	err = op.Persist()
+	if err != nil {
+		return fmt.Errorf("Failed persisting operation metadata: %w", err)
+	}
+
+	return nil
+}
+
+// storagePoolMigrateResume cleans up after the volume move interrupted when the operation was restarted.
+// A copy of the volume left on the target pool is deleted if the source volume still exists. Otherwise the move
+// completed but for the final rename of an instance moved under a temporary name.
+func storagePoolMigrateResume(ctx context.Context, s *state.State, srcPool storagePools.Pool, dstPool storagePools.Pool, op *operations.Operation) error {
+	metadata := op.Metadata()
+	volType, _ := metadata[storagePoolMigrateMetadataType].(string)
+	projectName, _ := metadata[storagePoolMigrateMetadataProject].(string)
+	name, _ := metadata[storagePoolMigrateMetadataName].(string)
+	tempName, _ := metadata[storagePoolMigrateMetadataTempName].(string)
+
+	switch entity.Type(volType) {
+	case entity.TypeInstance:
+		tempInst, err := instance.LoadByProjectAndName(s, projectName, tempName)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		_, err = instance.LoadByProjectAndName(s, projectName, name)
+		if err == nil {
+			logger.Warn("Removing instance copy left over by interrupted storage pool migration", logger.Ctx{"project": projectName, "instance": name, "pool": dstPool.Name()})
+			return tempInst.Delete(ctx, true, "", op)
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		return tempInst.Rename(ctx, name, false)
+	case entity.TypeStorageVolume:
+		var srcExists, dstExists bool
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			_, err := tx.GetStoragePoolVolume(ctx, srcPool.ID(), projectName, dbCluster.StoragePoolVolumeTypeCustom, name, true)
+			if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+
+			srcExists = err == nil
+
+			_, err = tx.GetStoragePoolVolume(ctx, dstPool.ID(), projectName, dbCluster.StoragePoolVolumeTypeCustom, name, true)
+			if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+
+			dstExists = err == nil
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if srcExists && dstExists {
+			logger.Warn("Removing custom volume copy left over by interrupted storage pool migration", logger.Ctx{"project": projectName, "volume": name, "pool": dstPool.Name()})
+			return dstPool.DeleteCustomVolume(ctx, projectName, name, op)
+		}
+	}
+
+	return nil
+}
+
+// storagePoolMigrateCustomVolume moves a custom volume and its snapshots to another pool, updating the devices
+// using it.
+func storagePoolMigrateCustomVolume(ctx context.Context, s *state.State, srcPool storagePools.Pool, dstPool storagePools.Pool, vol *api.StorageVolume, op *operations.Operation) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Check that moving the volume into the target pool doesn't exceed the project limits.
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		limitsReq := api.StorageVolumesPost{
+			StorageVolumePut: api.StorageVolumePut{
+				Description: vol.Description,
+				Config:      vol.Config,
+			},
+			Name: vol.Name,
+			Type: vol.Type,
+		}
+
+		return limits.AllowVolumeMove(ctx, s.GlobalConfig, tx, vol.Project, srcPool.Name(), vol.Name, vol.Project, dstPool.Name(), limitsReq)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Update devices using the volume in instances and profiles.
+	cleanup, err := storagePoolVolumeUpdateUsers(ctx, s, vol.Project, srcPool.Name(), vol, dstPool.Name(), vol)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(cleanup)
+
+	err = dstPool.CreateCustomVolumeFromCopy(ctx, vol.Project, vol.Project, vol.Name, "", nil, srcPool.Name(), vol.Name, true, op)
+	if err != nil {
+		return err
+	}
+
+	err = srcPool.DeleteCustomVolume(ctx, vol.Project, vol.Name, op)
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// storagePoolMigrateProfiles points the root disk devices of profiles using a pool to another pool.
+func storagePoolMigrateProfiles(ctx context.Context, s *state.State, oldPoolName string, newPoolName string) error {
+	var profiles []api.Profile
+	projects := make(map[string]*api.Project)
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProjects, err := dbCluster.GetProjects(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed loading projects: %w", err)
+		}
+
+		for _, p := range dbProjects {
+			projects[p.Name], err = p.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return fmt.Errorf("Failed loading config for project %q: %w", p.Name, err)
+			}
+		}
+
+		dbProfiles, err := dbCluster.GetProfiles(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed loading profiles: %w", err)
+		}
+
+		profileConfigs, err := dbCluster.GetConfig(ctx, tx.Tx(), "profile")
+		if err != nil {
+			return fmt.Errorf("Failed loading profile configs: %w", err)
+		}
+
+		profileDevices, err := dbCluster.GetDevices(ctx, tx.Tx(), "profile")
+		if err != nil {
+			return fmt.Errorf("Failed loading profile devices: %w", err)
+		}
+
+		for _, profile := range dbProfiles {
+			apiProfile, err := profile.ToAPI(ctx, tx.Tx(), profileConfigs, profileDevices)
+			if err != nil {
+				return fmt.Errorf("Failed getting API Profile %q: %w", profile.Name, err)
+			}
+
+			profiles = append(profiles, *apiProfile)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range profiles {
+		rootDevKey, rootDev, err := api.GetRootDiskDevice(profile.Devices)
+		if err != nil || rootDev["pool"] != oldPoolName {
+			continue
+		}
+
+		newDevices := make(map[string]map[string]string, len(profile.Devices))
+		for devName, dev := range profile.Devices {
+			newDevices[devName] = make(map[string]string, len(dev))
+			for key, val := range dev {
+				newDevices[devName][key] = val
+			}
+		}
+
+		newDevices[rootDevKey]["pool"] = newPoolName
+
+		pUpdate := api.ProfilePut{
+			Config:      profile.Config,
+			Description: profile.Description,
+			Devices:     newDevices,
+		}
+
+		err = doProfileUpdate(ctx, s, *projects[profile.Project], profile.Name, &profile, pUpdate)
+		if err != nil {
+			return fmt.Errorf("Failed updating profile %q in project %q: %w", profile.Name, profile.Project, err)
+		}
+	}
+
+	return nil
+}
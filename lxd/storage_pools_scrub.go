@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/ioprogress"
+)
+
+var storagePoolScrubCmd = APIEndpoint{
+	Path:        "storage-pools/{poolName}/scrub",
+	MetricsType: entity.TypeStoragePool,
+
+	Post: APIEndpointAction{Handler: storagePoolScrubPost, AccessHandler: allowPermission(entity.TypeStoragePool, auth.EntitlementCanEdit, "poolName")},
+}
+
+// swagger:operation POST /1.0/storage-pools/{poolName}/scrub storage storage_pool_scrub_post
+//
+//	Scrub the storage pool
+//
+//	Checks the integrity of the data stored in the storage pool.
+//	The storage driver's own scrubbing is used where available, otherwise all volumes of the pool are read back.
+//	A warning is raised if corrupted data is found.
+//	When clustered, the storage pool is scrubbed on the targeted cluster member.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolScrubPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseToNode(r.Context(), s, request.QueryParam(r, "target"))
+	if resp != nil {
+		return resp
+	}
+
+	poolName := r.PathValue("poolName")
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		return storagePoolScrub(ctx, s, pool, op)
+	}
+
+	args := operations.OperationArgs{
+		EntityURL:         entity.StoragePoolURL(poolName),
+		Type:              operationtype.StoragePoolScrub,
+		Class:             operationtype.OperationClassTask,
+		ConflictReference: entity.StoragePoolURL(poolName).String(),
+		RunHook:           run,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// storagePoolScrub checks the integrity of the data stored in the pool.
+// If the storage driver can't scrub the pool, the volumes of the pool available on this member are verified instead.
+// A warning is raised if corrupted data is found and any existing warning is resolved otherwise.
+func storagePoolScrub(ctx context.Context, s *state.State, pool storagePools.Pool, progressReporter ioprogress.ProgressReporter) error {
+	err := pool.Scrub(progressReporter)
+	if errors.Is(err, storageDrivers.ErrNotSupported) {
+		err = storagePoolScrubVolumes(ctx, s, pool, progressReporter)
+	}
+
+	if err != nil {
+		if errors.Is(err, storageDrivers.ErrCorrupted) {
+			_ = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.UpsertWarningLocalNode(ctx, "", entity.TypeStoragePool, int(pool.ID()), warningtype.StoragePoolCorrupted, err.Error())
+			})
+		}
+
+		return fmt.Errorf("Failed scrubbing storage pool %q: %w", pool.Name(), err)
+	}
+
+	_ = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningtype.StoragePoolCorrupted, entity.TypeStoragePool, int(pool.ID()))
+
+	return nil
+}
+
+// storagePoolScrubVolumes verifies the instance and custom volumes of the pool available on this member.
+// Returns storageDrivers.ErrCorrupted listing the volumes in which corrupted data was found.
+func storagePoolScrubVolumes(ctx context.Context, s *state.State, pool storagePools.Pool, progressReporter ioprogress.ProgressReporter) error {
+	vols, err := storagePoolMigrateVolumes(ctx, s, pool, true)
+	if err != nil {
+		return err
+	}
+
+	var corrupted []string
+	for _, vol := range vols {
+		switch vol.Type {
+		case dbCluster.StoragePoolVolumeTypeNameContainer, dbCluster.StoragePoolVolumeTypeNameVM, dbCluster.StoragePoolVolumeTypeNameCustom:
+		default:
+			continue
+		}
+
+		// Instance volumes are only verified by the member running the instance.
+		if s.ServerClustered && vol.Type != dbCluster.StoragePoolVolumeTypeNameCustom {
+			inst, err := instance.LoadByProjectAndName(s, vol.Project, vol.Name)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q in project %q: %w", vol.Name, vol.Project, err)
+			}
+
+			if inst.Location() != s.ServerName {
+				continue
+			}
+		}
+
+		if progressReporter != nil {
+			handler := progressReporter.ProgressHandler("scrub")
+			handler(ioprogress.ProgressData{Text: fmt.Sprintf("Verifying %s volume %q in project %q", vol.Type, vol.Name, vol.Project)})
+		}
+
+		_, err := storageVolumeVerify(s, pool, vol.Project, vol, progressReporter)
+		if err != nil {
+			if !errors.Is(err, storageDrivers.ErrCorrupted) {
+				return err
+			}
+
+			corrupted = append(corrupted, fmt.Sprintf("%s/%s (project %q)", vol.Type, vol.Name, vol.Project))
+		}
+	}
+
+	if len(corrupted) > 0 {
+		return fmt.Errorf("%w in volumes: %s", storageDrivers.ErrCorrupted, strings.Join(corrupted, ", "))
+	}
+
+	return nil
+}
@@ -835,6 +835,7 @@ func storagePoolVolumesGet(d *Daemon, r *http.Request) response.Response {
 				vol.UsedBy = project.FilterUsedBy(r.Context(), s.Authorizer, volumeUsedBy)
 			}
 
+			vol.Config = storagePools.VolumeAPIConfig(vol.Config)
 			volumes = append(volumes, vol)
 			urlToVolume[entity.StorageVolumeURL(vol.Project, vol.Location, vol.Pool, vol.Type, vol.Name)] = vol
 		}
@@ -1006,7 +1007,14 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		case "tar":
 			return createStoragePoolVolumeFromTarball(s, r, requestProjectName, projectName, r.Body, poolName, r.Header.Get("X-LXD-name"))
 		default:
-			return createStoragePoolVolumeFromBackup(s, r, requestProjectName, projectName, r.Body, poolName, r.Header.Get("X-LXD-name"))
+			data, err := backupImportSource(r.Context(), s, r, projectName)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			defer func() { _ = data.Close() }()
+
+			return createStoragePoolVolumeFromBackup(s, r, requestProjectName, projectName, data, poolName, r.Header.Get("X-LXD-name"))
 		}
 	}
 
@@ -1054,6 +1062,16 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 			req.Source.Project = requestProjectName
 		}
 
+		if req.Source.Clone {
+			if req.Source.Pool != poolName {
+				return response.BadRequest(errors.New("Volume clones must be created in the storage pool of their source"))
+			}
+
+			if req.Source.Refresh {
+				return response.BadRequest(errors.New("Volume clones cannot be refreshed"))
+			}
+		}
+
 		sourceProjectName, err := project.StorageVolumeProject(s.DB.Cluster, req.Source.Project, cluster.StoragePoolVolumeTypeCustom)
 		if err != nil {
 			return response.SmartError(err)
@@ -1120,6 +1138,10 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 			return response.SmartError(err)
 		}
 
+		if req.Source.Clone {
+			return response.BadRequest(errors.New("Volume clones must be created on the cluster member of their source"))
+		}
+
 		if nodeAddress == "" {
 			return response.BadRequest(errors.New("The source is currently offline"))
 		}
@@ -1317,6 +1339,10 @@ func doVolumeCreateOrCopy(s *state.State, r *http.Request, requestProjectName st
 		}
 
 		run = func(ctx context.Context, op *operations.Operation) error {
+			if req.Source.Clone {
+				return pool.CreateCustomVolumeFromClone(ctx, projectName, srcProjectName, req.Name, req.Description, req.Config, req.Source.Name, op)
+			}
+
 			return pool.CreateCustomVolumeFromCopy(ctx, projectName, srcProjectName, req.Name, req.Description, req.Config, req.Source.Pool, req.Source.Name, !req.Source.VolumeOnly, op)
 		}
 	}
@@ -1663,6 +1689,16 @@ func storagePoolVolumePost(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(errors.New("Volume is used by LXD itself and cannot be renamed"))
 	}
 
+	// Check if the volume holds a bucket of a local storage pool.
+	bucket, err := storagePools.VolumeUsedByLocalBucket(s, details.pool.Name(), effectiveProjectName, details.volumeName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if bucket != nil {
+		return response.BadRequest(fmt.Errorf("Volume holds storage bucket %q and cannot be renamed", bucket.Name))
+	}
+
 	var dbVolume *db.StorageVolume
 	var volumeNotFound bool
 	var targetIsSet bool
@@ -2160,7 +2196,10 @@ func storagePoolVolumeGet(d *Daemon, r *http.Request) response.Response {
 
 	etag := []any{details.volumeName, dbVolume.Type, dbVolume.Config}
 
-	return response.SyncResponseETag(true, dbVolume.StorageVolume, etag)
+	vol := dbVolume.StorageVolume
+	vol.Config = storagePools.VolumeAPIConfig(dbVolume.Config)
+
+	return response.SyncResponseETag(true, vol, etag)
 }
 
 // swagger:operation PUT /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName} storage storage_pool_volume_type_put
@@ -2304,6 +2343,8 @@ func storagePoolVolumePut(d *Daemon, r *http.Request) response.Response {
 				if err != nil {
 					return err
 				}
+
+				storagePoolVolumeApplyLimits(s, effectiveProjectName, &dbVolume.StorageVolume, dbVolume.Config, req.Config)
 			}
 		case cluster.StoragePoolVolumeTypeContainer, cluster.StoragePoolVolumeTypeVM:
 			inst, err := instance.LoadByProjectAndName(s, effectiveProjectName, dbVolume.Name)
@@ -2317,6 +2358,8 @@ func storagePoolVolumePut(d *Daemon, r *http.Request) response.Response {
 				return err
 			}
 
+			storagePoolVolumeApplyLimits(s, effectiveProjectName, &dbVolume.StorageVolume, dbVolume.Config, req.Config)
+
 		case cluster.StoragePoolVolumeTypeImage:
 			// Handle image update requests.
 			err = details.pool.UpdateImage(ctx, dbVolume.Name, req.Description, req.Config, op)
@@ -2459,7 +2502,14 @@ func storagePoolVolumePatch(d *Daemon, r *http.Request) response.Response {
 	}
 
 	run := func(ctx context.Context, op *operations.Operation) error {
-		return details.pool.UpdateCustomVolume(ctx, effectiveProjectName, dbVolume.Name, req.Description, req.Config, op)
+		err := details.pool.UpdateCustomVolume(ctx, effectiveProjectName, dbVolume.Name, req.Description, req.Config, op)
+		if err != nil {
+			return err
+		}
+
+		storagePoolVolumeApplyLimits(s, effectiveProjectName, &dbVolume.StorageVolume, dbVolume.Config, req.Config)
+
+		return nil
 	}
 
 	volumeURL := entity.StorageVolumeURL(effectiveProjectName, details.location, details.pool.Name(), details.volumeTypeName, details.volumeName)
@@ -2778,12 +2828,19 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 	defer func() { _ = os.Remove(backupFile.Name()) }()
 	revert.Add(func() { _ = backupFile.Close() })
 
-	// Stream uploaded backup data into temporary file.
-	_, err = io.Copy(backupFile, data)
+	// Stream uploaded backup data into temporary file, verifying its checksum on the way.
+	verifier := backup.NewChecksumVerifier(s, backupFile.Name())
+	_, err = io.Copy(io.MultiWriter(backupFile, verifier), data)
 	if err != nil {
+		_ = verifier.Close()
 		return response.InternalError(err)
 	}
 
+	err = verifier.Close()
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	// Detect squashfs compression and convert to tarball.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
@@ -365,6 +365,19 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 		}
 	}
 
+	var backupTarget *backup.Target
+	if req.Target != "" {
+		backupTarget, err = backup.LoadTarget(r.Context(), s, req.Target)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Backups uploaded to a backup target aren't tracked by the server, so name them after their creation time.
+		if req.Name == "" {
+			req.Name = "backup-" + time.Now().UTC().Format("20060102-150405")
+		}
+	}
+
 	if req.Name == "" {
 		var backups []string
 
@@ -418,6 +431,7 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 		return response.BadRequest(err)
 	}
 
+	key := backup.VolumeBackupKey(details.pool.Name(), details.volumeName, backupName)
 	fullName := details.volumeName + shared.SnapshotDelimiter + backupName
 	volumeOnly := req.VolumeOnly
 
@@ -454,6 +468,28 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 		},
 	}
 
+	// Backups for a backup target are uploaded to it instead of being stored on the server.
+	if backupTarget != nil {
+		req.Name = backupName
+
+		args.RunHook = func(ctx context.Context, op *operations.Operation) error {
+			err := volumeBackupCreateOnTarget(ctx, s, backupTarget, key, effectiveProjectName, details.pool.Name(), details.volumeName, req)
+			if err != nil {
+				return fmt.Errorf("Create volume backup: %w", err)
+			}
+
+			s.Events.SendLifecycle(effectiveProjectName, lifecycle.StorageVolumeBackupCreated.Event(details.pool.Name(), details.volumeTypeName, fullName, effectiveProjectName, op.EventLifecycleRequestor(), logger.Ctx{"type": details.volumeTypeName, "backup_target": backupTarget.Name(), "key": key}))
+
+			return nil
+		}
+
+		args.Metadata = map[string]any{
+			api.MetadataEntityURL: volumeURL.String(),
+			"backup_target":       req.Target,
+			"backup_key":          key,
+		}
+	}
+
 	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
 	if err != nil {
 		return response.InternalError(err)
@@ -436,7 +436,7 @@ func storagePoolVolumeSnapshotsTypeGet(d *Daemon, r *http.Request) response.Resp
 			vol.UsedBy = project.FilterUsedBy(r.Context(), s.Authorizer, volumeUsedBy)
 
 			snap := &api.StorageVolumeSnapshot{}
-			snap.Config = vol.Config
+			snap.Config = storagePools.VolumeAPIConfig(vol.Config)
 			snap.Description = vol.Description
 			snap.Name = vol.Name
 			snap.CreatedAt = vol.CreatedAt
@@ -661,7 +661,7 @@ func storagePoolVolumeSnapshotTypeGet(d *Daemon, r *http.Request) response.Respo
 	}
 
 	snapshot := &api.StorageVolumeSnapshot{}
-	snapshot.Config = dbVolume.Config
+	snapshot.Config = storagePools.VolumeAPIConfig(dbVolume.Config)
 	snapshot.Description = dbVolume.Description
 	snapshot.Name = details.snapshotName
 	snapshot.ExpiresAt = &expiry
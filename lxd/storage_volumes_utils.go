@@ -8,6 +8,7 @@ import (
 	"github.com/canonical/lxd/lxd/backup"
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/device"
 	"github.com/canonical/lxd/lxd/device/config"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/state"
@@ -140,6 +141,49 @@ func storagePoolVolumeUpdateUsers(ctx context.Context, s *state.State, projectNa
 	return cleanup, nil
 }
 
+// storagePoolVolumeApplyLimits re-applies the I/O limits of the disk devices backed by the volume to the instances
+// running on this member after the limits set on the volume changed. Failures are logged as the volume update
+// itself has already succeeded, and the new limits are applied on the next instance start regardless.
+func storagePoolVolumeApplyLimits(s *state.State, projectName string, vol *api.StorageVolume, oldConfig map[string]string, newConfig map[string]string) {
+	if newConfig["limits.iops"] == oldConfig["limits.iops"] && newConfig["limits.bandwidth"] == oldConfig["limits.bandwidth"] {
+		return
+	}
+
+	var instances []instance.Instance
+	var instancesDevices [][]string
+
+	err := storagePools.VolumeUsedByInstanceDevices(s, vol.Pool, projectName, vol, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
+		if dbInst.Node != s.ServerName {
+			return nil
+		}
+
+		inst, err := instance.Load(s, dbInst, project)
+		if err != nil {
+			return err
+		}
+
+		instances = append(instances, inst)
+		instancesDevices = append(instancesDevices, usedByDevices)
+
+		return nil
+	})
+	if err != nil {
+		logger.Warn("Failed finding instances using storage volume to apply its I/O limits", logger.Ctx{"project": projectName, "pool": vol.Pool, "volume": vol.Name, "err": err})
+		return
+	}
+
+	for i, inst := range instances {
+		if !inst.IsRunning() {
+			continue
+		}
+
+		err := device.DiskApplyLimits(s, inst, instancesDevices[i])
+		if err != nil {
+			logger.Warn("Failed applying storage volume I/O limits to instance", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "volume": vol.Name, "err": err})
+		}
+	}
+}
+
 // storagePoolVolumeUsedByGet returns a list of URL resources that use the volume.
 func storagePoolVolumeUsedByGet(s *state.State, requestProjectName string, vol *db.StorageVolume) ([]string, error) {
 	if vol.Type == cluster.StoragePoolVolumeTypeNameContainer {
@@ -166,6 +210,18 @@ func storagePoolVolumeUsedByGet(s *state.State, requestProjectName string, vol *
 		return []string{api.NewURL().Path(version.APIVersion).String()}, nil
 	}
 
+	// Check if the volume holds a bucket of a local storage pool.
+	if vol.Type == cluster.StoragePoolVolumeTypeNameCustom {
+		bucket, err := storagePools.VolumeUsedByLocalBucket(s, vol.Pool, vol.Project, vol.Name)
+		if err != nil {
+			return []string{}, err
+		}
+
+		if bucket != nil {
+			return []string{api.NewURL().Path(version.APIVersion, "storage-pools", vol.Pool, "buckets", bucket.Name).Project(bucket.Project).Target(bucket.Location).String()}, nil
+		}
+	}
+
 	// Look for instances using this volume.
 	volumeUsedBy := []string{}
 
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/ioprogress"
+)
+
+var storagePoolVolumeTypeVerifyCmd = APIEndpoint{
+	Path:            "storage-pools/{poolName}/volumes/{type}/{volumeName}/verify",
+	MetricsType:     entity.TypeStoragePool,
+	ProjectSpecific: true,
+
+	Post: APIEndpointAction{Handler: storagePoolVolumeTypeVerifyPost, AccessHandler: allowPermission(entity.TypeStorageVolume, auth.EntitlementCanEdit, "poolName", "type", "volumeName")},
+}
+
+// swagger:operation POST /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/verify storage storage_pool_volume_type_verify_post
+//
+//	Verify the storage volume
+//
+//	Checks the integrity of the storage volume by reading back all of its data.
+//	The SHA-256 checksum of block volumes is returned in the "checksum" field of the operation metadata.
+//	A warning is raised if corrupted data is found.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeTypeVerifyPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	details, err := request.GetContextValue[storageVolumeDetails](r.Context(), ctxStorageVolumeDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Check that the storage volume type is valid.
+	if !slices.Contains([]cluster.StoragePoolVolumeType{cluster.StoragePoolVolumeTypeCustom, cluster.StoragePoolVolumeTypeContainer, cluster.StoragePoolVolumeTypeVM}, details.volumeType) {
+		return response.BadRequest(fmt.Errorf("Invalid storage volume type %q", details.volumeTypeName))
+	}
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
+	if details.volumeType != cluster.StoragePoolVolumeTypeCustom {
+		resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, effectiveProjectName, details.volumeName, instancetype.Any)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if resp != nil {
+			return resp
+		}
+	}
+
+	volType := storagePools.VolumeDBTypeToType(details.volumeType)
+	dbVol, err := storagePools.VolumeDBGet(details.pool, effectiveProjectName, details.volumeName, volType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		checksum, err := storageVolumeVerify(s, details.pool, effectiveProjectName, dbVol, op)
+		if err != nil {
+			return err
+		}
+
+		if checksum != "" {
+			return op.ExtendMetadata(map[string]any{"checksum": checksum})
+		}
+
+		return nil
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: request.ProjectParam(r),
+		EntityURL:   entity.StorageVolumeURL(request.ProjectParam(r), details.location, details.pool.Name(), details.volumeTypeName, details.volumeName),
+		Type:        operationtype.VolumeVerify,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// storageVolumeVerify checks the integrity of a storage volume and returns the checksum of block volumes.
+// A warning is raised if corrupted data is found and any existing warning is resolved otherwise.
+func storageVolumeVerify(s *state.State, pool storagePools.Pool, projectName string, dbVol *db.StorageVolume, progressReporter ioprogress.ProgressReporter) (string, error) {
+	volDBType, err := cluster.StoragePoolVolumeTypeFromName(dbVol.Type)
+	if err != nil {
+		return "", err
+	}
+
+	checksum, err := pool.VerifyVolume(projectName, dbVol.Name, storagePools.VolumeDBTypeToType(volDBType), progressReporter)
+	if err != nil {
+		if errors.Is(err, storageDrivers.ErrCorrupted) {
+			_ = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.UpsertWarningLocalNode(ctx, projectName, entity.TypeStorageVolume, int(dbVol.ID), warningtype.StorageVolumeCorrupted, err.Error())
+			})
+		}
+
+		return "", err
+	}
+
+	_ = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, projectName, warningtype.StorageVolumeCorrupted, entity.TypeStorageVolume, int(dbVol.ID))
+
+	return checksum, nil
+}
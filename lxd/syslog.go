@@ -10,14 +10,16 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
 
-	"github.com/canonical/lxd/lxd/events"
+	"github.com/canonical/lxd/lxd/network/acl"
+	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
 )
 
 // StartSyslogListener starts the log monitor.
-func StartSyslogListener(ctx context.Context, eventServer *events.Server) error {
+func StartSyslogListener(ctx context.Context, s *state.State) error {
 	var listenConfig net.ListenConfig
 
 	sockFile := shared.VarPath("syslog.socket")
@@ -127,10 +129,18 @@ func StartSyslogListener(ctx context.Context, eventServer *events.Server) error
 				event.Context["application"] = applicationName
 			}
 
-			err = eventServer.Send("", api.EventTypeOVN, event)
+			err = s.Events.Send("", api.EventTypeOVN, event)
 			if err != nil {
 				continue
 			}
+
+			// Also send the entries of the logged network ACL rules as network ACL events.
+			if strings.HasPrefix(moduleName, "acl_log") {
+				err = acl.SendOVNLogEvent(ctx, s, message)
+				if err != nil {
+					logger.Debug("Failed sending network ACL event", logger.Ctx{"err": err})
+				}
+			}
 		}
 	}()
 
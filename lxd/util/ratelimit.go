@@ -0,0 +1,101 @@
+package util
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket that limits throughput to a fixed number of bytes per second.
+// It can be shared between multiple readers or writers so that they are limited as a whole.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// now and sleep are overridden in tests.
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewRateLimiter returns a [RateLimiter] allowing bytesPerSecond bytes per second on average.
+// A bytesPerSecond value less than or equal to zero disables rate limiting.
+func NewRateLimiter(bytesPerSecond int64) *RateLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	return &RateLimiter{
+		rate:   float64(bytesPerSecond),
+		burst:  float64(bytesPerSecond),
+		tokens: float64(bytesPerSecond),
+		last:   time.Now(),
+		now:    time.Now,
+		sleep:  time.Sleep,
+	}
+}
+
+// Wait blocks until n bytes can be transferred without exceeding the configured rate.
+func (l *RateLimiter) Wait(n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens -= float64(n)
+
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+
+	l.mu.Unlock()
+
+	if delay > 0 {
+		l.sleep(delay)
+	}
+}
+
+// chunk returns the largest write size that is paced individually.
+// Large writes are split so that a single call cannot exceed the burst size.
+func (l *RateLimiter) chunk() int {
+	return max(int(l.burst), 1)
+}
+
+type rateLimitedReadWriteCloser struct {
+	io.ReadWriteCloser
+	limiter *RateLimiter
+}
+
+// RateLimitedReadWriteCloser returns an [io.ReadWriteCloser] whose writes are throttled by the given [RateLimiter].
+// Reads are passed through as is. If limiter is nil, the original rwc is returned.
+func RateLimitedReadWriteCloser(rwc io.ReadWriteCloser, limiter *RateLimiter) io.ReadWriteCloser {
+	if limiter == nil {
+		return rwc
+	}
+
+	return &rateLimitedReadWriteCloser{ReadWriteCloser: rwc, limiter: limiter}
+}
+
+// Write writes p to the underlying writer, pacing the writes so that the limiter's rate is respected.
+func (rw *rateLimitedReadWriteCloser) Write(p []byte) (int, error) {
+	written := 0
+	chunkSize := rw.limiter.chunk()
+	for written < len(p) {
+		end := min(written+chunkSize, len(p))
+
+		rw.limiter.Wait(end - written)
+		n, err := rw.ReadWriteCloser.Write(p[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
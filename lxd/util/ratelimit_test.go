@@ -0,0 +1,82 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+type nopReadWriteCloser struct {
+	bytes.Buffer
+}
+
+func (*nopReadWriteCloser) Close() error {
+	return nil
+}
+
+func TestNewRateLimiterDisabled(t *testing.T) {
+	limiter := NewRateLimiter(0)
+	if limiter != nil {
+		t.Fatal("Expected nil limiter for a zero rate")
+	}
+
+	// A nil limiter must not block or panic.
+	limiter.Wait(1024)
+
+	rwc := &nopReadWriteCloser{}
+	if RateLimitedReadWriteCloser(rwc, nil) != io.ReadWriteCloser(rwc) {
+		t.Fatal("Expected original ReadWriteCloser to be returned when limiter is nil")
+	}
+}
+
+func TestRateLimitedReadWriteCloser(t *testing.T) {
+	limiter := NewRateLimiter(1000)
+
+	clock := time.Now()
+	limiter.last = clock
+	limiter.now = func() time.Time {
+		return clock
+	}
+
+	var slept time.Duration
+	limiter.sleep = func(d time.Duration) {
+		slept += d
+		clock = clock.Add(d)
+	}
+
+	rwc := &nopReadWriteCloser{}
+	limited := RateLimitedReadWriteCloser(rwc, limiter)
+
+	// The first second worth of data fits in the initial burst.
+	n, err := limited.Write(make([]byte, 1000))
+	if err != nil {
+		t.Fatalf("Unexpected write error: %v", err)
+	}
+
+	if n != 1000 {
+		t.Fatalf("Expected to write 1000 bytes, wrote %d", n)
+	}
+
+	if slept > 0 {
+		t.Fatalf("Expected no throttling within burst, slept %v", slept)
+	}
+
+	// Another 3 seconds worth of data must be paced over roughly 3 seconds.
+	n, err = limited.Write(make([]byte, 3000))
+	if err != nil {
+		t.Fatalf("Unexpected write error: %v", err)
+	}
+
+	if n != 3000 {
+		t.Fatalf("Expected to write 3000 bytes, wrote %d", n)
+	}
+
+	if rwc.Len() != 4000 {
+		t.Fatalf("Expected 4000 bytes in underlying writer, got %d", rwc.Len())
+	}
+
+	if slept < 2900*time.Millisecond || slept > 3100*time.Millisecond {
+		t.Fatalf("Expected about 3s of throttling, slept %v", slept)
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"time"
+)
+
+// BackupTargetsPost represents the fields available for a new backup target.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type BackupTargetsPost struct {
+	BackupTargetPut `yaml:",inline"`
+
+	// lxdmeta:generate(entities=backup-target; group=properties; key=name)
+	//
+	// ---
+	//  type: string
+	//  required: yes
+	//  shortdesc: Name of the backup target
+
+	// Name of the backup target
+	// Example: s3-backups
+	Name string `json:"name" yaml:"name"`
+}
+
+// BackupTargetPost represents the fields available for renaming a backup target.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type BackupTargetPost struct {
+	// New name of the backup target
+	// Example: s3-backups
+	Name string `json:"name" yaml:"name"`
+}
+
+// BackupTargetPut represents the modifiable fields of a backup target.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type BackupTargetPut struct {
+	// lxdmeta:generate(entities=backup-target; group=properties; key=description)
+	//
+	// ---
+	//  type: string
+	//  required: no
+	//  shortdesc: Description of the backup target
+
+	// Description of the backup target
+	// Example: Off-site backups
+	Description string `json:"description" yaml:"description"`
+
+	// lxdmeta:generate(entities=backup-target; group=properties; key=config)
+	//
+	// ---
+	//  type: string set
+	//  required: no
+	//  shortdesc: Backup target configuration map (refer to {ref}`ref-backup-target-config`)
+
+	// Backup target configuration map (refer to doc/reference/backup_target_config.md)
+	// Example: {"s3.endpoint": "https://s3.example.com", "s3.bucket": "backups"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// BackupTarget represents a remote location backups can be uploaded to.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type BackupTarget struct {
+	// Name of the backup target
+	// Example: s3-backups
+	Name string `json:"name" yaml:"name"`
+
+	// Description of the backup target
+	// Example: Off-site backups
+	Description string `json:"description" yaml:"description"`
+
+	// Backup target configuration map (refer to doc/reference/backup_target_config.md)
+	// Example: {"s3.endpoint": "https://s3.example.com", "s3.bucket": "backups"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// Writable converts a full BackupTarget struct into a [BackupTargetPut] struct (filters read-only fields).
+func (target *BackupTarget) Writable() BackupTargetPut {
+	return BackupTargetPut{
+		Description: target.Description,
+		Config:      target.Config,
+	}
+}
+
+// BackupTargetBackup represents a backup stored on a backup target.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type BackupTargetBackup struct {
+	// Key of the backup, relative to the project
+	// Example: instances/c1/backup0
+	Key string `json:"key" yaml:"key"`
+
+	// Size of the backup in bytes
+	// Example: 104857600
+	Size int64 `json:"size" yaml:"size"`
+
+	// When the backup was uploaded
+	// Example: 2021-03-23T16:38:37.753398689-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
@@ -8,18 +8,19 @@ import (
 
 // LXD event types.
 const (
-	EventTypeLifecycle = "lifecycle"
-	EventTypeLogging   = "logging"
-	EventTypeOperation = "operation"
-	EventTypeOVN       = "ovn"
-	EventTypeSecurity  = "security"
+	EventTypeLifecycle  = "lifecycle"
+	EventTypeLogging    = "logging"
+	EventTypeNetworkACL = "network-acl"
+	EventTypeOperation  = "operation"
+	EventTypeOVN        = "ovn"
+	EventTypeSecurity   = "security"
 )
 
 // Event represents an event entry (over websocket)
 //
 // swagger:model
 type Event struct {
-	// Event type (one of operation, logging, lifecycle, ovn or security)
+	// Event type (one of operation, logging, lifecycle, ovn, security or network-acl)
 	// Example: lifecycle
 	Type string `yaml:"type" json:"type"`
 
@@ -27,7 +28,7 @@ type Event struct {
 	// Example: 2021-02-24T19:00:45.452649098-05:00
 	Timestamp time.Time `yaml:"timestamp" json:"timestamp"`
 
-	// JSON encoded metadata (see EventLogging, EventLifecycle, Operation or EventSecurity)
+	// JSON encoded metadata (see EventLogging, EventLifecycle, Operation, EventSecurity or EventNetworkACL)
 	// Example: {"action": "instance-started", "source": "/1.0/instances/c1", "context": {}}
 	Metadata json.RawMessage `yaml:"metadata" json:"metadata"`
 
@@ -139,6 +140,36 @@ func (event *Event) ToLogging() (EventLogRecord, error) {
 			Msg:  e.Description,
 			Ctx:  ctx,
 		}, nil
+	case EventTypeNetworkACL:
+		e := &EventNetworkACL{}
+		err := json.Unmarshal(event.Metadata, &e)
+		if err != nil {
+			return EventLogRecord{}, err
+		}
+
+		ctx := []any{"acl", e.ACL, "direction", e.Direction, "rule", e.Rule, "protocol", e.Protocol, "source", e.Source, "destination", e.Destination}
+		if e.SourcePort != "" {
+			ctx = append(ctx, "source_port", e.SourcePort)
+		}
+
+		if e.DestinationPort != "" {
+			ctx = append(ctx, "destination_port", e.DestinationPort)
+		}
+
+		if e.ICMPType != "" {
+			ctx = append(ctx, "icmp_type", e.ICMPType, "icmp_code", e.ICMPCode)
+		}
+
+		if event.Project != "" {
+			ctx = append(ctx, "project", event.Project)
+		}
+
+		return EventLogRecord{
+			Time: event.Timestamp,
+			Lvl:  "info",
+			Msg:  "Network ACL rule matched, Action: " + e.Action,
+			Ctx:  ctx,
+		}, nil
 	case EventTypeOperation:
 		e := &Operation{}
 		err := json.Unmarshal(event.Metadata, &e)
@@ -2,6 +2,10 @@ package api
 
 // Define consts for all the lifecycle events.
 const (
+	EventLifecycleBackupTargetCreated               = "backup-target-created"
+	EventLifecycleBackupTargetDeleted               = "backup-target-deleted"
+	EventLifecycleBackupTargetRenamed               = "backup-target-renamed"
+	EventLifecycleBackupTargetUpdated               = "backup-target-updated"
 	EventLifecycleCertificateCreated                = "certificate-created"
 	EventLifecycleCertificateDeleted                = "certificate-deleted"
 	EventLifecycleCertificateUpdated                = "certificate-updated"
@@ -25,8 +29,11 @@ const (
 	EventLifecycleClusterLinkUpdated                = "cluster-link-updated"
 	EventLifecycleReplicatorCreated                 = "replicator-created"
 	EventLifecycleReplicatorDeleted                 = "replicator-deleted"
+	EventLifecycleReplicatorPromoted                = "replicator-promoted"
 	EventLifecycleReplicatorRenamed                 = "replicator-renamed"
 	EventLifecycleReplicatorRun                     = "replicator-run"
+	EventLifecycleReplicatorRunCompleted            = "replicator-run-completed"
+	EventLifecycleReplicatorRunFailed               = "replicator-run-failed"
 	EventLifecycleReplicatorUpdated                 = "replicator-updated"
 	EventLifecycleClusterTokenCreated               = "cluster-token-created"
 	EventLifecycleConfigUpdated                     = "config-updated"
@@ -42,6 +49,7 @@ const (
 	EventLifecycleImageUpdated                      = "image-updated"
 	EventLifecycleInstanceBackupCreated             = "instance-backup-created"
 	EventLifecycleInstanceBackupDeleted             = "instance-backup-deleted"
+	EventLifecycleInstanceBackupFailed              = "instance-backup-failed"
 	EventLifecycleInstanceBackupRenamed             = "instance-backup-renamed"
 	EventLifecycleInstanceBackupRetrieved           = "instance-backup-retrieved"
 	EventLifecycleInstanceConsole                   = "instance-console"
@@ -79,6 +87,10 @@ const (
 	EventLifecycleNetworkACLDeleted                 = "network-acl-deleted"
 	EventLifecycleNetworkACLRenamed                 = "network-acl-renamed"
 	EventLifecycleNetworkACLUpdated                 = "network-acl-updated"
+	EventLifecycleNetworkAddressSetCreated          = "network-address-set-created"
+	EventLifecycleNetworkAddressSetDeleted          = "network-address-set-deleted"
+	EventLifecycleNetworkAddressSetRenamed          = "network-address-set-renamed"
+	EventLifecycleNetworkAddressSetUpdated          = "network-address-set-updated"
 	EventLifecycleNetworkCreated                    = "network-created"
 	EventLifecycleNetworkDeleted                    = "network-deleted"
 	EventLifecycleNetworkForwardCreated             = "network-forward-created"
@@ -109,6 +121,7 @@ const (
 	EventLifecycleProjectUpdated                    = "project-updated"
 	EventLifecycleStoragePoolCreated                = "storage-pool-created"
 	EventLifecycleStoragePoolDeleted                = "storage-pool-deleted"
+	EventLifecycleStoragePoolMigrated               = "storage-pool-migrated"
 	EventLifecycleStoragePoolUpdated                = "storage-pool-updated"
 	EventLifecycleStorageBucketCreated              = "storage-bucket-created"
 	EventLifecycleStorageBucketUpdated              = "storage-bucket-updated"
@@ -119,6 +132,7 @@ const (
 	EventLifecycleStorageVolumeCreated              = "storage-volume-created"
 	EventLifecycleStorageVolumeBackupCreated        = "storage-volume-backup-created"
 	EventLifecycleStorageVolumeBackupDeleted        = "storage-volume-backup-deleted"
+	EventLifecycleStorageVolumeBackupFailed         = "storage-volume-backup-failed"
 	EventLifecycleStorageVolumeBackupRenamed        = "storage-volume-backup-renamed"
 	EventLifecycleStorageVolumeBackupRetrieved      = "storage-volume-backup-retrieved"
 	EventLifecycleStorageVolumeDeleted              = "storage-volume-deleted"
@@ -147,4 +161,5 @@ const (
 	EventLifecyclePlacementGroupDeleted             = "placement-group-deleted"
 	EventLifecyclePlacementGroupRenamed             = "placement-group-renamed"
 	EventLifecyclePlacementGroupUpdated             = "placement-group-updated"
+	EventLifecyclePlacementGroupRebalanced          = "placement-group-rebalanced"
 )
@@ -0,0 +1,50 @@
+package api
+
+// EventNetworkACL represents a network ACL event entry, sent when a logged ACL rule matches traffic.
+//
+// API extension: network_acl_counters.
+type EventNetworkACL struct {
+	// Name of the ACL
+	// Example: web-servers
+	ACL string `json:"acl" yaml:"acl"`
+
+	// Direction of the matched rule (ingress or egress)
+	// Example: ingress
+	Direction string `json:"direction" yaml:"direction"`
+
+	// Index of the matched rule in the rules of its direction
+	// Example: 0
+	Rule int `json:"rule" yaml:"rule"`
+
+	// Action of the matched rule
+	// Example: allow
+	Action string `json:"action" yaml:"action"`
+
+	// Protocol of the matched packet
+	// Example: tcp
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+
+	// Source address of the matched packet
+	// Example: 10.0.0.2
+	Source string `json:"source" yaml:"source"`
+
+	// Destination address of the matched packet
+	// Example: 10.0.0.3
+	Destination string `json:"destination" yaml:"destination"`
+
+	// Source port of the matched packet
+	// Example: 40232
+	SourcePort string `json:"source_port,omitempty" yaml:"source_port,omitempty"`
+
+	// Destination port of the matched packet
+	// Example: 80
+	DestinationPort string `json:"destination_port,omitempty" yaml:"destination_port,omitempty"`
+
+	// ICMP type of the matched packet
+	// Example: 8
+	ICMPType string `json:"icmp_type,omitempty" yaml:"icmp_type,omitempty"`
+
+	// ICMP code of the matched packet
+	// Example: 0
+	ICMPCode string `json:"icmp_code,omitempty" yaml:"icmp_code,omitempty"`
+}
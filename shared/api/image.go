@@ -62,6 +62,12 @@ type ImagesPost struct {
 	//
 	// API extension: image_create_aliases
 	Aliases []ImageAlias `json:"aliases" yaml:"aliases"`
+
+	// Whether to sign the image with the server key (when turning an instance into an image)
+	// Example: true
+	//
+	// API extension: image_signatures
+	Sign bool `json:"sign" yaml:"sign"`
 }
 
 // ImagesPostSource represents the source of a new LXD image
@@ -229,6 +235,11 @@ type Image struct {
 	//
 	// API extension: image_extended_metadata
 	ReleaseTitle string `json:"release_title,omitempty" yaml:"release_title,omitempty"`
+
+	// Image signature
+	//
+	// API extension: image_signatures
+	Signature *ImageSignature `json:"signature,omitempty" yaml:"signature,omitempty"`
 }
 
 // Writable converts a full Image struct into a ImagePut struct (filters read-only fields).
@@ -256,6 +267,38 @@ func (img *Image) URL(apiVersion string, project string) *URL {
 	return NewURL().Path(apiVersion, "images", img.Fingerprint).Project(project)
 }
 
+// Image signature verification statuses.
+const (
+	// ImageSignatureStatusVerified is used for images signed by a trusted key.
+	ImageSignatureStatusVerified = "verified"
+
+	// ImageSignatureStatusUnverified is used for signed images that couldn't be checked against a trusted key.
+	ImageSignatureStatusUnverified = "unverified"
+)
+
+// ImageSignature represents the detached signature of a LXD image
+//
+// swagger:model
+//
+// API extension: image_signatures.
+type ImageSignature struct {
+	// Detached signature of the image fingerprint
+	// Example: -----BEGIN SSH SIGNATURE-----
+	Signature string `json:"signature" yaml:"signature"`
+
+	// Type of signature (gpg or ssh)
+	// Example: ssh
+	Type string `json:"type" yaml:"type"`
+
+	// Fingerprint of the trusted key that made the signature
+	// Example: SHA256:S8Mfw58kRYYsOipu/Ukux1kjHFdzY37wa2nuecFIMKk
+	KeyFingerprint string `json:"key_fingerprint" yaml:"key_fingerprint"`
+
+	// Verification status (verified or unverified)
+	// Example: verified
+	Status string `json:"status" yaml:"status"`
+}
+
 // ImageAlias represents an alias from the alias list of a LXD image
 //
 // swagger:model
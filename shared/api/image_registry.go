@@ -6,6 +6,8 @@ const (
 	ImageRegistryProtocolSimpleStreams = "simplestreams"
 	// Image registry protocol "LXD".
 	ImageRegistryProtocolLXD = "lxd"
+	// Image registry protocol "OCI".
+	ImageRegistryProtocolOCI = "oci"
 )
 
 // ImageRegistriesPost represents the fields of a new image registry.
@@ -20,7 +22,7 @@ type ImageRegistriesPost struct {
 	// Example: ubuntu
 	Name string `json:"name" yaml:"name"`
 
-	// Protocol used by the image registry ("SimpleStreams" or "LXD")
+	// Protocol used by the image registry ("SimpleStreams", "LXD" or "OCI")
 	// Example: simplestreams
 	Protocol string `json:"protocol" yaml:"protocol"`
 }
@@ -67,7 +69,7 @@ type ImageRegistry struct {
 	// Example: My new image registry
 	Description string `json:"description" yaml:"description"`
 
-	// Protocol used by the image registry ("SimpleStreams" or "LXD")
+	// Protocol used by the image registry ("SimpleStreams", "LXD" or "OCI")
 	// Example: simplestreams
 	Protocol string `json:"protocol" yaml:"protocol"`
 
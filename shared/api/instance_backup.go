@@ -43,6 +43,18 @@ type InstanceBackupsPost struct {
 	//
 	// API extension: backup_metadata_version
 	Version uint32 `json:"version" yaml:"version"`
+
+	// Name of the backup target to upload the backup to, instead of storing it on the server
+	// Example: s3-backups
+	//
+	// API extension: backup_targets
+	Target string `json:"target" yaml:"target"`
+
+	// Name of the snapshot to base an incremental optimized backup on
+	// Example: snap0
+	//
+	// API extension: backup_incremental
+	ParentSnapshot string `json:"parent_snapshot" yaml:"parent_snapshot"`
 }
 
 // InstanceBackup represents a LXD instance backup.
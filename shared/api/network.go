@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // NetworksPost represents the fields of a new LXD network
 //
 // swagger:model
@@ -213,6 +217,11 @@ type NetworkState struct {
 	//
 	// API extension: network_state_ovn
 	OVN *NetworkStateOVN `json:"ovn" yaml:"ovn"`
+
+	// Additional WireGuard tunnel information
+	//
+	// API extension: network_bridge_tunnel_wireguard
+	WireGuard *NetworkStateWireGuard `json:"wireguard" yaml:"wireguard"`
 }
 
 // NetworkStateAddress represents a network address
@@ -347,3 +356,56 @@ type NetworkStateOVN struct {
 	// OVN network chassis name
 	Chassis string `json:"chassis" yaml:"chassis"`
 }
+
+// NetworkStateWireGuard represents the state of the WireGuard tunnel of a bridge network
+//
+// swagger:model
+//
+// API extension: network_bridge_tunnel_wireguard.
+type NetworkStateWireGuard struct {
+	// Name of the tunnel
+	// Example: dc2
+	Tunnel string `json:"tunnel" yaml:"tunnel"`
+
+	// Public key of the tunnel endpoint
+	// Example: xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// Public key the tunnel endpoint switches to once its key rotation completes, empty outside a rotation
+	// Example: Hx2fQW0mFbQe3oYkLQHfN1ZkQmNPuBl1fJ6XN1s0Gm4=
+	NextPublicKey string `json:"next_public_key,omitempty" yaml:"next_public_key,omitempty"`
+
+	// UDP port the tunnel endpoint listens on
+	// Example: 51820
+	ListenPort uint64 `json:"listen_port" yaml:"listen_port"`
+
+	// List of peers of the tunnel endpoint
+	Peers []NetworkStateWireGuardPeer `json:"peers" yaml:"peers"`
+}
+
+// NetworkStateWireGuardPeer represents a peer of the WireGuard tunnel of a bridge network
+//
+// swagger:model
+//
+// API extension: network_bridge_tunnel_wireguard.
+type NetworkStateWireGuardPeer struct {
+	// Public key of the peer
+	// Example: TrMvSoP4jYQlY6RIzBgbssQqY3vxI2Pi+y71lOWWXX0=
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// Address and port of the peer endpoint
+	// Example: 203.0.113.10:51820
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+
+	// Time of the latest handshake with the peer
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LatestHandshake time.Time `json:"latest_handshake" yaml:"latest_handshake"`
+
+	// Number of bytes received from the peer
+	// Example: 250542118
+	BytesReceived uint64 `json:"bytes_received" yaml:"bytes_received"`
+
+	// Number of bytes sent to the peer
+	// Example: 17524040140
+	BytesSent uint64 `json:"bytes_sent" yaml:"bytes_sent"`
+}
@@ -23,7 +23,7 @@ type NetworkACLRule struct {
 	Action string `json:"action" yaml:"action"`
 
 	// lxdmeta:generate(entities=network-acl; group=rule-properties; key=source)
-	// Sources can be specified as CIDR or IP ranges, source subject name selectors (for ingress rules), or be left empty for any.
+	// Sources can be specified as CIDR or IP ranges, address sets (`$<name>`), source subject name selectors (for ingress rules), or be left empty for any.
 	// ---
 	//  type: string
 	//  required: no
@@ -34,7 +34,7 @@ type NetworkACLRule struct {
 	Source string `json:"source,omitempty" yaml:"source,omitempty"`
 
 	// lxdmeta:generate(entities=network-acl; group=rule-properties; key=destination)
-	// Destinations can be specified as CIDR or IP ranges, destination subject name selectors (for egress rules), or be left empty for any.
+	// Destinations can be specified as CIDR or IP ranges, address sets (`$<name>`), destination subject name selectors (for egress rules), or be left empty for any.
 	// ---
 	//  type: string
 	//  required: no
@@ -302,3 +302,31 @@ type NetworkACLsPost struct {
 	NetworkACLPost `yaml:",inline"`
 	NetworkACLPut  `yaml:",inline"`
 }
+
+// NetworkACLRuleCounters represents the counters of a network ACL rule.
+//
+// swagger:model
+//
+// API extension: network_acl_counters.
+type NetworkACLRuleCounters struct {
+	// Number of packets matched by the rule
+	// Example: 1024
+	Packets uint64 `json:"packets" yaml:"packets"`
+
+	// Number of bytes matched by the rule
+	// Example: 65536
+	Bytes uint64 `json:"bytes" yaml:"bytes"`
+}
+
+// NetworkACLState represents the state of a network ACL.
+//
+// swagger:model
+//
+// API extension: network_acl_counters.
+type NetworkACLState struct {
+	// Counters of the egress rules (in the same order as the rules)
+	Egress []NetworkACLRuleCounters `json:"egress" yaml:"egress"`
+
+	// Counters of the ingress rules (in the same order as the rules)
+	Ingress []NetworkACLRuleCounters `json:"ingress" yaml:"ingress"`
+}
@@ -0,0 +1,99 @@
+package api
+
+// NetworkAddressSetPost used for renaming an address set.
+//
+// swagger:model
+//
+// API extension: network_address_sets.
+type NetworkAddressSetPost struct {
+	// lxdmeta:generate(entities=network-address-set; group=address-set-properties; key=name)
+	//
+	// ---
+	//  type: string
+	//  required: yes
+	//  shortdesc: Unique name of the address set in the project
+
+	// The new name for the address set
+	// Example: web-servers
+	Name string `json:"name" yaml:"name"` // Name of address set.
+}
+
+// NetworkAddressSetPut used for updating an address set.
+//
+// swagger:model
+//
+// API extension: network_address_sets.
+type NetworkAddressSetPut struct {
+	// lxdmeta:generate(entities=network-address-set; group=address-set-properties; key=description)
+	//
+	// ---
+	//  type: string
+	//  required: no
+	//  shortdesc: Description of the address set
+
+	// Description of the address set
+	// Example: Web servers
+	Description string `json:"description" yaml:"description"`
+
+	// lxdmeta:generate(entities=network-address-set; group=address-set-properties; key=addresses)
+	// Each entry can be an IP address, a CIDR subnet or a fully qualified domain name.
+	// Domain names are resolved by LXD and periodically re-resolved to keep the set up to date.
+	// ---
+	//  type: string list
+	//  required: no
+	//  shortdesc: Addresses of the address set
+
+	// List of addresses (IP addresses, CIDR subnets or domain names)
+	// Example: ["10.0.0.1", "10.0.1.0/24", "www.example.com"]
+	Addresses []string `json:"addresses" yaml:"addresses"`
+
+	// lxdmeta:generate(entities=network-address-set; group=address-set-properties; key=config)
+	// The only supported keys are `user.*` custom keys.
+	// ---
+	//  type: string set
+	//  required: no
+	//  shortdesc: User-provided free-form key/value pairs
+
+	// Address set configuration map
+	// Example: {"user.mykey": "foo"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// NetworkAddressSet used for displaying an address set.
+//
+// swagger:model
+//
+// API extension: network_address_sets.
+type NetworkAddressSet struct {
+	NetworkAddressSetPost `yaml:",inline"`
+	NetworkAddressSetPut  `yaml:",inline"`
+
+	// List of URLs of objects using this address set
+	// Read only: true
+	// Example: ["/1.0/network-acls/web"]
+	UsedBy []string `json:"used_by" yaml:"used_by"` // Resources that use the address set.
+
+	// Project name
+	// Example: project1
+	Project string `json:"project" yaml:"project"` // Project the address set belongs to.
+}
+
+// Writable converts a full NetworkAddressSet struct into a NetworkAddressSetPut struct (filters read-only fields).
+func (set *NetworkAddressSet) Writable() NetworkAddressSetPut {
+	return set.NetworkAddressSetPut
+}
+
+// SetWritable sets applicable values from NetworkAddressSetPut struct to NetworkAddressSet struct.
+func (set *NetworkAddressSet) SetWritable(put NetworkAddressSetPut) {
+	set.NetworkAddressSetPut = put
+}
+
+// NetworkAddressSetsPost used for creating an address set.
+//
+// swagger:model
+//
+// API extension: network_address_sets.
+type NetworkAddressSetsPost struct {
+	NetworkAddressSetPost `yaml:",inline"`
+	NetworkAddressSetPut  `yaml:",inline"`
+}
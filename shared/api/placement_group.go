@@ -18,6 +18,17 @@ const (
 	PlacementRigorPermissive string = "permissive"
 )
 
+const (
+	// PlacementScopeMember applies the placement policy to individual cluster members.
+	PlacementScopeMember string = "member"
+
+	// PlacementScopeFailureDomain applies the placement policy to the failure domains of cluster members.
+	PlacementScopeFailureDomain string = "failure-domain"
+
+	// PlacementScopeClusterGroup applies the placement policy to cluster groups.
+	PlacementScopeClusterGroup string = "cluster-group"
+)
+
 // PlacementGroup represents a group of instances that should be scheduled.
 //
 // API extension: instance_placement_groups.
@@ -85,3 +96,33 @@ type PlacementGroupPost struct {
 	// Example: pg2
 	Name string `json:"name" yaml:"name"`
 }
+
+// PlacementGroupRebalance represents the instance migrations needed to bring a placement group back in line with its
+// policy.
+//
+// API extension: placement_group_rebalance.
+type PlacementGroupRebalance struct {
+	// List of planned instance migrations.
+	Moves []PlacementGroupRebalanceMove `json:"moves" yaml:"moves"`
+}
+
+// PlacementGroupRebalanceMove represents the migration of an instance of a placement group to another cluster member.
+//
+// API extension: placement_group_rebalance.
+type PlacementGroupRebalanceMove struct {
+	// Name of the instance.
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Cluster member the instance is currently on.
+	// Example: server01
+	Source string `json:"source" yaml:"source"`
+
+	// Cluster member the instance is moved to.
+	// Example: server02
+	Target string `json:"target" yaml:"target"`
+
+	// Whether the instance is live-migrated.
+	// Example: true
+	Live bool `json:"live" yaml:"live"`
+}
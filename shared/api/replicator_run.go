@@ -0,0 +1,64 @@
+package api
+
+import (
+	"time"
+)
+
+// ReplicatorRun represents a finished run of a replicator.
+//
+// swagger:model
+//
+// API extension: replicator_run_history.
+type ReplicatorRun struct {
+	// Identifier of the run.
+	// Example: 42
+	ID int64 `json:"id" yaml:"id"`
+
+	// Timestamp when the run started.
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// Timestamp when the run finished.
+	// Example: 2021-03-23T17:42:12.164253411-04:00
+	FinishedAt time.Time `json:"finished_at" yaml:"finished_at"`
+
+	// Status of the run (Completed or Failed).
+	// Example: Failed
+	Status string `json:"status" yaml:"status"`
+
+	// Number of bytes sent to the target cluster during the run.
+	// Example: 1073741824
+	BytesTransferred int64 `json:"bytes_transferred" yaml:"bytes_transferred"`
+
+	// Outcome of each instance replicated by the run.
+	Instances []ReplicatorRunInstance `json:"instances" yaml:"instances"`
+}
+
+// ReplicatorRunInstance represents the outcome of the replication of an instance during a replicator run.
+//
+// swagger:model
+//
+// API extension: replicator_run_history.
+type ReplicatorRunInstance struct {
+	// Name of the replicated item. Instances are identified by their name, custom volumes by
+	// their pool and name (pool/volume), and other tasks of the run by their description.
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// Cluster member of the replicated item, set for custom volumes on local storage pools.
+	// Example: lxd01
+	Location string `json:"location" yaml:"location"`
+
+	// Status of the instance replication (Completed, Failed or Skipped).
+	// Instances already replicated by an interrupted run are skipped when the run is resumed.
+	// Example: Failed
+	Status string `json:"status" yaml:"status"`
+
+	// Error message if the instance replication failed.
+	// Example: Failed connecting to target cluster
+	Error string `json:"error" yaml:"error"`
+
+	// Number of bytes sent to the target cluster for the instance.
+	// Example: 536870912
+	BytesTransferred int64 `json:"bytes_transferred" yaml:"bytes_transferred"`
+}
@@ -12,6 +12,10 @@ const (
 
 	// ReplicatorStatusFailed represents a failed replicator run.
 	ReplicatorStatusFailed = "Failed"
+
+	// ReplicatorStatusSkipped represents an instance that was already replicated by the interrupted run that a
+	// replicator run resumed.
+	ReplicatorStatusSkipped = "Skipped"
 )
 
 // ReplicatorState represents the state of a replicator job.
@@ -31,7 +35,13 @@ type ReplicatorState struct {
 //
 // API extension: replicators.
 type ReplicatorStatePut struct {
-	// Action to perform on the replicator (start, restore).
+	// Action to perform on the replicator (start, restore, promote).
 	// Example: start
 	Action string `json:"action" yaml:"action"`
+
+	// Whether to promote the project even if the target cluster can't be reached (promote action only).
+	// Example: false
+	//
+	// API extension: replicator_promote.
+	Force bool `json:"force" yaml:"force"`
 }
@@ -801,6 +801,11 @@ type ResourcesStoragePool struct {
 
 	// DIsk inode usage
 	Inodes ResourcesStoragePoolInodes `json:"inodes" yaml:"inodes,omitempty"`
+
+	// Thin pool metadata usage (only reported by LVM thin pools)
+	//
+	// API extension: storage_pool_thin_provisioning.
+	Metadata *ResourcesStoragePoolSpace `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
 // ResourcesStoragePoolSpace represents the space available to a given storage pool
@@ -816,6 +821,12 @@ type ResourcesStoragePoolSpace struct {
 	// Total disk space (bytes)
 	// Example: 420100937728
 	Total uint64 `json:"total" yaml:"total"`
+
+	// Disk space allocated to the volumes of thinly provisioned pools (bytes)
+	// Example: 644245094400
+	//
+	// API extension: storage_pool_thin_provisioning.
+	Provisioned uint64 `json:"provisioned,omitempty" yaml:"provisioned,omitempty"`
 }
 
 // ResourcesStoragePoolInodes represents the inodes available to a given storage pool
@@ -109,6 +109,17 @@ func (storagePool *StoragePool) SetWritable(put StoragePoolPut) {
 	storagePool.Config = put.Config
 }
 
+// StoragePoolMigratePost represents the fields required to migrate the volumes of a LXD storage pool to another pool
+//
+// swagger:model
+//
+// API extension: storage_pool_migrate.
+type StoragePoolMigratePost struct {
+	// Name of the storage pool to move the volumes to
+	// Example: remote
+	Pool string `json:"pool" yaml:"pool"`
+}
+
 // StoragePoolState represents the state of a storage pool.
 //
 // swagger:model
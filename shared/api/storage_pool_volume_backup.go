@@ -62,6 +62,12 @@ type StoragePoolVolumeBackupsPost struct {
 	//
 	// API extension: backup_metadata_version
 	Version uint32 `json:"version" yaml:"version"`
+
+	// Name of the backup target to upload the backup to, instead of storing it on the server
+	// Example: s3-backups
+	//
+	// API extension: backup_targets
+	Target string `json:"target" yaml:"target"`
 }
 
 // StoragePoolVolumeBackupPost represents the fields available for the renaming of a volume backup
@@ -64,6 +64,9 @@ type ProductVersionItem struct {
 	HashSha256               string `json:"sha256,omitempty"`
 	Size                     int64  `json:"size"`
 	DeltaBase                string `json:"delta_base,omitempty"`
+
+	// Detached signature of the image fingerprint (set on the rootfs item, or the metadata item for unified images).
+	LXDSignature string `json:"lxd_signature,omitempty"`
 }
 
 // ToLXD converts the products data into a list of LXD images and associated downloadable files.
@@ -206,6 +209,15 @@ func (s *Products) ToLXD() ([]api.Image, map[string][][]string) {
 				image.ReleaseCodename = product.ReleaseCodename
 				image.ReleaseTitle = product.ReleaseTitle
 
+				signature := meta.LXDSignature
+				if root != nil {
+					signature = root.LXDSignature
+				}
+
+				if signature != "" {
+					image.Signature = &api.ImageSignature{Signature: signature}
+				}
+
 				if root != nil {
 					image.Properties["type"] = root.FileType
 					if root.FileType == "disk1.img" || root.FileType == "disk-kvm.img" || root.FileType == "uefi1.img" {
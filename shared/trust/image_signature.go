@@ -0,0 +1,339 @@
+package trust
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"golang.org/x/crypto/ssh"
+)
+
+// Supported image signature types.
+const (
+	// ImageSignatureTypeGPG is a detached ASCII armored OpenPGP signature.
+	ImageSignatureTypeGPG = "gpg"
+
+	// ImageSignatureTypeSSH is a detached SSH signature (as produced by `ssh-keygen -Y sign`).
+	ImageSignatureTypeSSH = "ssh"
+)
+
+// ImageSignatureNamespace is the namespace SSH image signatures must be created for.
+// For example `ssh-keygen -Y sign -f key -n lxd-image`.
+const ImageSignatureNamespace = "lxd-image"
+
+const (
+	sshSignatureMagic   = "SSHSIG"
+	sshSignatureVersion = 1
+	sshSignatureHash    = "sha512"
+	sshSignatureBegin   = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureEnd     = "-----END SSH SIGNATURE-----"
+	gpgSignatureBegin   = "-----BEGIN PGP SIGNATURE-----"
+	gpgPublicKeyBegin   = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+	gpgPublicKeyEnd     = "-----END PGP PUBLIC KEY BLOCK-----"
+)
+
+// ImageSignatureKeyring holds the keys trusted to sign images.
+type ImageSignatureKeyring struct {
+	gpg openpgp.EntityList
+	ssh []ssh.PublicKey
+}
+
+// ParseImageSignatureKeys parses a list of trusted image signing keys and returns the keys trusted for images of the
+// given remote server.
+// Each key can be an ASCII armored OpenPGP public key block, an SSH public key in the authorized_keys format or a PEM
+// encoded X.509 certificate, in which case its public key is trusted for SSH signatures.
+// Keys listed after a "[<server URL>]" line are only trusted for images of that server, while keys listed before
+// any such line are trusted for images of all servers. All keys are validated regardless of the server.
+func ParseImageSignatureKeys(keys string, server string) (*ImageSignatureKeyring, error) {
+	keyring := &ImageSignatureKeyring{}
+	server = strings.TrimSuffix(server, "/")
+
+	// Whether the keys of the current section are trusted for the server.
+	trusted := true
+
+	lines := strings.Split(keys, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			sectionServer := strings.TrimSuffix(strings.TrimSpace(line[1:len(line)-1]), "/")
+			if sectionServer == "" {
+				return nil, fmt.Errorf("Missing server URL on line %d", i+1)
+			}
+
+			trusted = sectionServer == server
+			continue
+		}
+
+		var gpgEntities openpgp.EntityList
+		var sshKey ssh.PublicKey
+
+		switch {
+		case line == gpgPublicKeyBegin:
+			block, end, err := armoredBlock(lines, i, gpgPublicKeyEnd)
+			if err != nil {
+				return nil, err
+			}
+
+			entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(block))
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing OpenPGP public key on line %d: %w", i+1, err)
+			}
+
+			gpgEntities = entities
+			i = end
+		case line == "-----BEGIN CERTIFICATE-----":
+			block, end, err := armoredBlock(lines, i, "-----END CERTIFICATE-----")
+			if err != nil {
+				return nil, err
+			}
+
+			certBlock, _ := pem.Decode([]byte(block))
+			if certBlock == nil {
+				return nil, fmt.Errorf("Invalid certificate on line %d", i+1)
+			}
+
+			cert, err := x509.ParseCertificate(certBlock.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing certificate on line %d: %w", i+1, err)
+			}
+
+			publicKey, err := ssh.NewPublicKey(cert.PublicKey)
+			if err != nil {
+				return nil, fmt.Errorf("Unsupported certificate key on line %d: %w", i+1, err)
+			}
+
+			sshKey = publicKey
+			i = end
+		default:
+			publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing SSH public key on line %d: %w", i+1, err)
+			}
+
+			sshKey = publicKey
+		}
+
+		if !trusted {
+			continue
+		}
+
+		keyring.gpg = append(keyring.gpg, gpgEntities...)
+		if sshKey != nil {
+			keyring.ssh = append(keyring.ssh, sshKey)
+		}
+	}
+
+	return keyring, nil
+}
+
+// armoredBlock returns the armored block starting at the given line along with the index of its last line.
+func armoredBlock(lines []string, start int, endMarker string) (string, int, error) {
+	for i := start; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == endMarker {
+			return strings.Join(lines[start:i+1], "\n") + "\n", i, nil
+		}
+	}
+
+	return "", -1, fmt.Errorf("Missing %q for block on line %d", endMarker, start+1)
+}
+
+// Empty returns whether the keyring doesn't hold any key.
+func (k *ImageSignatureKeyring) Empty() bool {
+	return len(k.gpg) == 0 && len(k.ssh) == 0
+}
+
+// Verify checks that signature is a valid signature of the image fingerprint by one of the keys of the keyring.
+// It returns the type of the signature and the fingerprint of the key that made it.
+func (k *ImageSignatureKeyring) Verify(fingerprint string, signature string) (string, string, error) {
+	sigType, err := ImageSignatureType(signature)
+	if err != nil {
+		return "", "", err
+	}
+
+	if sigType == ImageSignatureTypeGPG {
+		signer, err := openpgp.CheckArmoredDetachedSignature(k.gpg, strings.NewReader(fingerprint), strings.NewReader(signature), nil)
+		if err != nil {
+			return "", "", fmt.Errorf("Invalid image signature: %w", err)
+		}
+
+		return sigType, strings.ToUpper(hex.EncodeToString(signer.PrimaryKey.Fingerprint[:])), nil
+	}
+
+	publicKey, sig, err := parseSSHSignature(signature)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, trusted := range k.ssh {
+		if !bytes.Equal(trusted.Marshal(), publicKey.Marshal()) {
+			continue
+		}
+
+		err = trusted.Verify(sshSignedData(fingerprint), sig)
+		if err != nil {
+			return "", "", fmt.Errorf("Invalid image signature: %w", err)
+		}
+
+		return sigType, ssh.FingerprintSHA256(trusted), nil
+	}
+
+	return "", "", fmt.Errorf("Image signature key %q isn't trusted", ssh.FingerprintSHA256(publicKey))
+}
+
+// ImageSignatureType returns the type of the given detached image signature.
+func ImageSignatureType(signature string) (string, error) {
+	signature = strings.TrimSpace(signature)
+
+	switch {
+	case strings.HasPrefix(signature, gpgSignatureBegin):
+		return ImageSignatureTypeGPG, nil
+	case strings.HasPrefix(signature, sshSignatureBegin):
+		return ImageSignatureTypeSSH, nil
+	}
+
+	return "", errors.New("Unknown image signature format")
+}
+
+// SignImage returns a detached SSH signature of the image fingerprint made with the given key.
+func SignImage(fingerprint string, key crypto.Signer) (string, error) {
+	signer, err := ssh.NewSignerFromSigner(key)
+	if err != nil {
+		return "", fmt.Errorf("Unsupported signing key: %w", err)
+	}
+
+	var sig *ssh.Signature
+	algoSigner, ok := signer.(ssh.AlgorithmSigner)
+	if ok && signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// Plain "ssh-rsa" signatures rely on SHA-1 and are rejected by SSH signature verifiers.
+		sig, err = algoSigner.SignWithAlgorithm(nil, sshSignedData(fingerprint), ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = signer.Sign(nil, sshSignedData(fingerprint))
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("Failed signing image: %w", err)
+	}
+
+	blob := ssh.Marshal(struct {
+		Magic     [6]byte
+		Version   uint32
+		PublicKey []byte
+		Namespace string
+		Reserved  string
+		Hash      string
+		Signature []byte
+	}{
+		Magic:     [6]byte([]byte(sshSignatureMagic)),
+		Version:   sshSignatureVersion,
+		PublicKey: signer.PublicKey().Marshal(),
+		Namespace: ImageSignatureNamespace,
+		Hash:      sshSignatureHash,
+		Signature: ssh.Marshal(sig),
+	})
+
+	encoded := base64.StdEncoding.EncodeToString(blob)
+
+	var sb strings.Builder
+	sb.WriteString(sshSignatureBegin + "\n")
+	for len(encoded) > 70 {
+		sb.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+
+	sb.WriteString(encoded + "\n")
+	sb.WriteString(sshSignatureEnd + "\n")
+
+	return sb.String(), nil
+}
+
+// parseSSHSignature parses an armored SSH signature and returns the public key and signature it holds.
+func parseSSHSignature(signature string) (ssh.PublicKey, *ssh.Signature, error) {
+	signature = strings.TrimSpace(signature)
+	signature = strings.TrimPrefix(signature, sshSignatureBegin)
+	signature, found := strings.CutSuffix(signature, sshSignatureEnd)
+	if !found {
+		return nil, nil, errors.New("Invalid SSH signature armor")
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(signature), ""))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid SSH signature encoding: %w", err)
+	}
+
+	if !bytes.HasPrefix(blob, []byte(sshSignatureMagic)) {
+		return nil, nil, errors.New("Invalid SSH signature magic")
+	}
+
+	var fields struct {
+		Version   uint32
+		PublicKey []byte
+		Namespace string
+		Reserved  string
+		Hash      string
+		Signature []byte
+	}
+
+	err = ssh.Unmarshal(blob[len(sshSignatureMagic):], &fields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid SSH signature: %w", err)
+	}
+
+	if fields.Version != sshSignatureVersion {
+		return nil, nil, fmt.Errorf("Unsupported SSH signature version %d", fields.Version)
+	}
+
+	if fields.Namespace != ImageSignatureNamespace {
+		return nil, nil, fmt.Errorf("SSH signature namespace %q doesn't match %q", fields.Namespace, ImageSignatureNamespace)
+	}
+
+	if fields.Hash != sshSignatureHash {
+		return nil, nil, fmt.Errorf("Unsupported SSH signature hash algorithm %q", fields.Hash)
+	}
+
+	publicKey, err := ssh.ParsePublicKey(fields.PublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid SSH signature public key: %w", err)
+	}
+
+	sig := &ssh.Signature{}
+	err = ssh.Unmarshal(fields.Signature, sig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid SSH signature: %w", err)
+	}
+
+	if sig.Format == ssh.KeyAlgoRSA {
+		return nil, nil, errors.New("SHA-1 based SSH signatures aren't supported")
+	}
+
+	return publicKey, sig, nil
+}
+
+// sshSignedData returns the data actually signed by an SSH signature of the image fingerprint.
+func sshSignedData(fingerprint string) []byte {
+	hash := sha512.Sum512([]byte(fingerprint))
+
+	return ssh.Marshal(struct {
+		Magic     [6]byte
+		Namespace string
+		Reserved  string
+		Hash      string
+		Message   []byte
+	}{
+		Magic:     [6]byte([]byte(sshSignatureMagic)),
+		Namespace: ImageSignatureNamespace,
+		Hash:      sshSignatureHash,
+		Message:   hash[:],
+	})
+}
@@ -0,0 +1,161 @@
+package trust
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+const testImageFingerprint = "06b86454720d36b20f94e31c6812e05ec51c1b568cf3a8abd273769d213394bb"
+
+// Created with `ssh-keygen -Y sign -f key -n lxd-image` over testImageFingerprint.
+const testSSHPublicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDrcvt9eqGCxKP9xlRg/me+DMrcbs4TVBpnz9PVhDqJt test"
+const testSSHSignature = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgOty+316oYLEo/3GVGD+Z74Mytx
+uzhNUGmfP09WEOom0AAAAJbHhkLWltYWdlAAAAAAAAAAZzaGE1MTIAAABTAAAAC3NzaC1l
+ZDI1NTE5AAAAQO5bLhoyNj2wABxUYHYTkb+TX5zlmCuPi0sq7EhcMHtjuRKJvnkejAyMl+
+Rh7i18JILD4mviRnb8gPVOZJFt+gs=
+-----END SSH SIGNATURE-----
+`
+
+// Created with `gpg --armor --detach-sign` over testImageFingerprint.
+const testGPGPublicKey = `-----BEGIN PGP PUBLIC KEY BLOCK-----
+
+mQENBGrSP98BCACw8hRxjmDvyw8YHZcO0u7nXNwGuaCM4DSG3Gm14waDAjKLdkfy
+fkQQNe68YRtEkQ9WAgxBgXzRYt00oJt5knp0i1nrJ0U4mrnEU6wQ9APWA8EmlInc
+Hr+GWBpiR3wgmGhFCdj9Di0glQkO4tvBvr1A3IhSUy8BhcyjiqXofDFYT99AkEkd
+UdE4b+wp0/AVhBYWdjnoYZA59dYvx59TzHxw1o7L+d7tqupLer8cdAWE9FnHpwxF
+tMPZsWHVa7mNs0rGI/+olKU5GcTb2YCX5Y0WHJr7xpB50VbUCspCOvoV/k8gh4yZ
+TUr5FcsnWekF3VGBXWOUQGg3UnFXvDcPydK3ABEBAAG0F1Rlc3QgPHRlc3RAZXhh
+bXBsZS5jb20+iQFOBBMBCgA4FiEEPR0UAIywDP4yhnsQYwO49DzLtqsFAmrSP98C
+GwMFCwkIBwIGFQoJCAsCBBYCAwECHgECF4AACgkQYwO49DzLtqvj6wf/XANfnfEo
+J0Lb5zxVipb6nxcagg+R62Vm2AkSjUcMtpKMEa7AAiXzNLhtYdsOuwoLlRNZ2Goq
+CIdwFgb/wsUv9f2v2UWpK6OBglWLgzkX4vQ2m96XFAH8Kp6eTWX+bbasqJ+LHALB
+7JWPLlcVcT12o4fmHfi4duIO9lrnMq8XEVVLoa3P4jWBEyGa77XPU5Wko+NanV4U
+1T5RQi/oXF6Lad2AyKw/lovmUGAGlK6D75LtH8X6NOljOZlsQ3KKN4JIpS1Gbtv6
+0vdEyTxxm41YtCJsVIv7VGEFekp2R4LgwZVXSxWWsB4kkvIcM8rT4TD8owtmwH1/
+No/n1rDeMdMyeg==
+=HhI/
+-----END PGP PUBLIC KEY BLOCK-----
+`
+
+const testGPGSignature = `-----BEGIN PGP SIGNATURE-----
+
+iQEzBAABCgAdFiEEPR0UAIywDP4yhnsQYwO49DzLtqsFAmrSP98ACgkQYwO49DzL
+tqu9Awf/UY9a6F5F3GyyZRzP7zMlwrNo5CvQ94Yxn+GLlStQiCVyRMX1FfdqDaJv
+yZm5+bo8o7lO2tqLwK+n0QJu6UJQ1lruSp/Qn/25mrC9m/eiCsTWg47OJ/mfiaFA
+ufNGdiG1MVliClZxbj3EyuckIn36WYsK6pLV7zGiRcW0CAX9hWtOyUC2EGQBrTyk
+zV4AhncJ5q3gQDjkSuZKH6SHZKragr+iPpbOXI+QoDpQeP15Vzer8rOcKeJkPVnR
+MWD4c+LHJCrR8nHa1sMFQFLvtQE6v4S1lW4w8Umx7+Pi85HPv71pJucmazg5h6G2
+XBnBOIKggX2pUOsaDdsAivbiqEwZ5Q==
+=DfR1
+-----END PGP SIGNATURE-----
+`
+
+func TestParseImageSignatureKeys(t *testing.T) {
+	keyring, err := ParseImageSignatureKeys("", "")
+	require.NoError(t, err)
+	assert.True(t, keyring.Empty())
+
+	keyring, err = ParseImageSignatureKeys("# Trusted keys\n"+testSSHPublicKey+"\n\n"+testGPGPublicKey, "")
+	require.NoError(t, err)
+	assert.Len(t, keyring.ssh, 1)
+	assert.Len(t, keyring.gpg, 1)
+
+	_, err = ParseImageSignatureKeys("ssh-ed25519 invalid", "")
+	assert.Error(t, err)
+
+	_, err = ParseImageSignatureKeys(strings.TrimSuffix(testGPGPublicKey, "-----END PGP PUBLIC KEY BLOCK-----\n"), "")
+	assert.Error(t, err)
+
+	// Keys of a server section are only trusted for that server, but are always validated.
+	keys := testSSHPublicKey + "\n[https://images.example.com/]\n" + testGPGPublicKey
+	keyring, err = ParseImageSignatureKeys(keys, "https://images.example.com")
+	require.NoError(t, err)
+	assert.Len(t, keyring.ssh, 1)
+	assert.Len(t, keyring.gpg, 1)
+
+	keyring, err = ParseImageSignatureKeys(keys, "https://other.example.com")
+	require.NoError(t, err)
+	assert.Len(t, keyring.ssh, 1)
+	assert.Empty(t, keyring.gpg)
+
+	_, err = ParseImageSignatureKeys("[https://images.example.com]\nssh-ed25519 invalid", "https://other.example.com")
+	assert.Error(t, err)
+
+	_, err = ParseImageSignatureKeys("[]\n"+testSSHPublicKey, "")
+	assert.Error(t, err)
+}
+
+func TestImageSignatureVerify(t *testing.T) {
+	keyring, err := ParseImageSignatureKeys(testSSHPublicKey+"\n"+testGPGPublicKey, "")
+	require.NoError(t, err)
+
+	sigType, keyFingerprint, err := keyring.Verify(testImageFingerprint, testSSHSignature)
+	require.NoError(t, err)
+	assert.Equal(t, ImageSignatureTypeSSH, sigType)
+	assert.Equal(t, "SHA256:S8Mfw58kRYYsOipu/Ukux1kjHFdzY37wa2nuecFIMKk", keyFingerprint)
+
+	sigType, keyFingerprint, err = keyring.Verify(testImageFingerprint, testGPGSignature)
+	require.NoError(t, err)
+	assert.Equal(t, ImageSignatureTypeGPG, sigType)
+	assert.Equal(t, "3D1D14008CB00CFE32867B106303B8F43CCBB6AB", keyFingerprint)
+
+	// Signatures of another image are rejected.
+	otherFingerprint := strings.Repeat("0", len(testImageFingerprint))
+
+	_, _, err = keyring.Verify(otherFingerprint, testSSHSignature)
+	assert.Error(t, err)
+
+	_, _, err = keyring.Verify(otherFingerprint, testGPGSignature)
+	assert.Error(t, err)
+
+	// Signatures by untrusted keys are rejected.
+	emptyKeyring, err := ParseImageSignatureKeys("", "")
+	require.NoError(t, err)
+
+	_, _, err = emptyKeyring.Verify(testImageFingerprint, testSSHSignature)
+	assert.Error(t, err)
+
+	_, _, err = emptyKeyring.Verify(testImageFingerprint, testGPGSignature)
+	assert.Error(t, err)
+
+	// Unknown formats are rejected.
+	_, _, err = keyring.Verify(testImageFingerprint, "invalid")
+	assert.Error(t, err)
+}
+
+func TestSignImage(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	for _, key := range []crypto.Signer{ecdsaKey, rsaKey} {
+		signer, err := ssh.NewSignerFromSigner(key)
+		require.NoError(t, err)
+
+		keyring, err := ParseImageSignatureKeys(string(ssh.MarshalAuthorizedKey(signer.PublicKey())), "")
+		require.NoError(t, err)
+
+		signature, err := SignImage(testImageFingerprint, key)
+		require.NoError(t, err)
+
+		sigType, keyFingerprint, err := keyring.Verify(testImageFingerprint, signature)
+		require.NoError(t, err)
+		assert.Equal(t, ImageSignatureTypeSSH, sigType)
+		assert.Equal(t, ssh.FingerprintSHA256(signer.PublicKey()), keyFingerprint)
+
+		_, _, err = keyring.Verify(testImageFingerprint[1:], signature)
+		assert.Error(t, err)
+	}
+}
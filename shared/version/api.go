@@ -502,6 +502,32 @@ var APIExtensions = []string{
 	"access_management_expiry",
 	"cluster_links_public",
 	"durable_operations",
+	"replicator_incremental_runs",
+	"replicator_run_history",
+	"replicator_promote",
+	"replicator_project_objects",
+	"replicator_live_snapshot",
+	"placement_group_scope",
+	"placement_group_rebalance",
+	"image_protocol_oci",
+	"image_signatures",
+	"durable_operations_backups_images_replicators",
+	"storage_buckets_local",
+	"backup_targets",
+	"backups_schedule",
+	"backup_incremental",
+	"storage_volume_encryption",
+	"storage_volume_limits",
+	"storage_pool_migrate",
+	"storage_volume_clone",
+	"storage_volume_verify",
+	"storage_pool_thin_provisioning",
+	"network_load_balancer_bridge",
+	"network_bridge_tunnel_wireguard",
+	"network_peer_bridge",
+	"network_acl_counters",
+	"network_address_sets",
+	"network_acl_bridge_log_prefix",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
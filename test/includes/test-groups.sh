@@ -66,6 +66,7 @@ readonly test_group_cluster_storage=(
     "clustering_recovery"
     "clustering_storage"
     "clustering_storage_single_node"
+    "clustering_storage_volume_encryption"
 )
 
 readonly test_group_replicator_storage=(
@@ -147,7 +148,11 @@ readonly test_group_network=(
     "dns"
     "network"
     "network_acl"
+    "network_address_set"
+    "network_bridge_wireguard"
     "network_forward"
+    "network_load_balancer"
+    "network_peer_bridge"
     "network_zone"
     "network_ovn"
 )
@@ -241,6 +246,9 @@ readonly test_group_standalone_storage=(
     "backup_volume_rename_delete"
     "backup_instance_uuid"
     "backup_volume_expiry"
+    "backup_schedule"
+    "backup_incremental"
+    "backup_target"
     "backup_export_import_recover"
     "backup_inconsistent_config"
     "container_copy_incremental"
@@ -300,6 +308,12 @@ readonly test_group_standalone_storage=(
     "storage_buckets"
     "storage_volume_import"
     "storage_volume_initial_config"
+    "storage_volume_encryption"
+    "storage_volume_limits"
+    "storage_pool_migrate"
+    "storage_volume_clone"
+    "storage_volume_verify"
+    "storage_pool_thin_provisioning"
 )
 
 # shellcheck disable=SC2034
@@ -1096,6 +1096,178 @@ test_backup_volume_expiry() {
   lxc storage volume delete "${poolName}" vol1
 }
 
+test_backup_schedule() {
+  local poolName monitorPID
+  poolName="lxdtest-$(basename "${LXD_DIR}")"
+
+  ensure_import_testimage
+
+  # Check that the backup schedule options are validated.
+  ! lxc init testimage c1 -d "${SMALL_ROOT_DISK}" -c backups.schedule=invalid || false
+  ! lxc init testimage c1 -d "${SMALL_ROOT_DISK}" -c backups.retain=-1 || false
+  ! lxc init testimage c1 -d "${SMALL_ROOT_DISK}" -c backups.compression=invalid || false
+
+  # Schedule backups every minute, keeping the two most recent ones.
+  lxc profile create backups
+  lxc profile set backups backups.schedule='* * * * *' backups.retain=2 backups.compression=none
+  lxc init testimage c1 -d "${SMALL_ROOT_DISK}" --profile default --profile backups
+  lxc storage volume create "${poolName}" vol1 size=1MiB backups.schedule='* * * * *' backups.retain=1
+
+  # Backups created manually aren't subject to the retention.
+  lxc query -X POST -d '{"name":"manual"}' /1.0/instances/c1/backups
+
+  # Run the scheduled backup task a few times.
+  for _ in 1 2 3; do
+    lxc query -X POST /internal/testing/backup-scheduled-task
+    sleep 1
+  done
+
+  lxc query /1.0/instances/c1/backups | jq --exit-status 'length == 3'
+  lxc query /1.0/instances/c1/backups | jq --exit-status 'map(select(endswith("/manual"))) | length == 1'
+  lxc query /1.0/instances/c1/backups | jq --exit-status 'map(select(contains("/scheduled-"))) | length == 2'
+  lxc query /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups | jq --exit-status 'length == 1'
+
+  # Check that a failing scheduled backup is reported with a lifecycle event.
+  stdbuf -oL lxc monitor --type=lifecycle > "${TEST_DIR}/backup-schedule.log" &
+  monitorPID=$!
+  sleep 0.1
+  lxc config set c1 backups.target=missing
+  lxc query -X POST /internal/testing/backup-scheduled-task
+  sleep 0.1
+  kill_go_proc "${monitorPID}" || true
+  grep -F "instance-backup-failed" "${TEST_DIR}/backup-schedule.log"
+  rm "${TEST_DIR}/backup-schedule.log"
+
+  # Cleanup.
+  lxc delete c1
+  lxc profile delete backups
+  lxc storage volume delete "${poolName}" vol1
+}
+
+test_backup_incremental() {
+  local lxd_backend poolName snap0UUID
+  lxd_backend=$(storage_backend "$LXD_DIR")
+  if [ "${lxd_backend}" != "zfs" ] && [ "${lxd_backend}" != "btrfs" ]; then
+    export TEST_UNMET_REQUIREMENT="${lxd_backend} driver does not support incremental backups"
+    return
+  fi
+
+  poolName="lxdtest-$(basename "${LXD_DIR}")"
+
+  ensure_import_testimage
+
+  lxc launch testimage c1 -d "${SMALL_ROOT_DISK}"
+
+  # Incremental backups must be optimized and based on an existing snapshot.
+  ! lxc export c1 "${LXD_DIR}/c1-invalid.tar.gz" --parent-snapshot snap0 || false
+  ! lxc export c1 "${LXD_DIR}/c1-invalid.tar.gz" --optimized-storage --parent-snapshot snap0 || false
+
+  # Create a full backup followed by two incremental backups.
+  lxc exec c1 -- sh -c "echo full > /root/state"
+  lxc snapshot c1 snap0
+  snap0UUID="$(lxc storage volume get "${poolName}" container/c1/snap0 volatile.uuid)"
+  lxc export c1 "${LXD_DIR}/c1-0.tar.gz" --optimized-storage
+
+  lxc exec c1 -- sh -c "echo incremental1 > /root/state"
+  lxc snapshot c1 snap1
+  lxc export c1 "${LXD_DIR}/c1-1.tar.gz" --optimized-storage --parent-snapshot snap0
+
+  lxc exec c1 -- sh -c "echo incremental2 > /root/state"
+  lxc delete c1/snap0
+  lxc export c1 "${LXD_DIR}/c1-2.tar.gz" --optimized-storage --parent-snapshot snap1
+
+  # Check that the incremental backups only contain the snapshots taken after their parent.
+  mkdir "${LXD_DIR}/incremental"
+  tar --warning=no-timestamp -xzf "${LXD_DIR}/c1-1.tar.gz" -C "${LXD_DIR}/incremental"
+  [ -f "${LXD_DIR}/incremental/backup/snapshots/snap1.bin" ]
+  [ ! -f "${LXD_DIR}/incremental/backup/snapshots/snap0.bin" ]
+  [ "$(yq .parent < "${LXD_DIR}/incremental/backup/index.yaml")" = "${snap0UUID}" ]
+  rm -rf "${LXD_DIR}/incremental"
+
+  # Incremental backups can't be imported without their whole chain.
+  ! lxc import "${LXD_DIR}/c1-2.tar.gz" c2 || false
+  ! lxc import "${LXD_DIR}/c1-2.tar.gz" c2 --parent "${LXD_DIR}/c1-1.tar.gz" || false
+  ! lxc import "${LXD_DIR}/c1-2.tar.gz" c2 --parent "${LXD_DIR}/c1-1.tar.gz" --parent "${LXD_DIR}/c1-0.tar.gz" || false
+
+  # Restore the chain.
+  lxc import "${LXD_DIR}/c1-1.tar.gz" c2 --parent "${LXD_DIR}/c1-0.tar.gz"
+  lxc start c2
+  [ "$(lxc exec c2 -- cat /root/state)" = "incremental1" ]
+  [ "$(lxc query /1.0/instances/c2/snapshots | jq -r 'length')" = "2" ]
+  lxc delete --force c2
+
+  lxc import "${LXD_DIR}/c1-2.tar.gz" c2 --parent "${LXD_DIR}/c1-0.tar.gz" --parent "${LXD_DIR}/c1-1.tar.gz"
+  lxc start c2
+  [ "$(lxc exec c2 -- cat /root/state)" = "incremental2" ]
+  [ "$(lxc query /1.0/instances/c2/snapshots | jq -r '.[]')" = "/1.0/instances/c2/snapshots/snap1" ]
+  lxc stop --force c2
+  lxc restore c2 snap1
+  lxc start c2
+  [ "$(lxc exec c2 -- cat /root/state)" = "incremental1" ]
+
+  lxc delete --force c1 c2
+  rm -f "${LXD_DIR}"/c1-*.tar.gz
+}
+
+test_backup_target() {
+  local lxd_backend poolName port creds accessKey secretKey key
+  lxd_backend=$(storage_backend "$LXD_DIR")
+
+  if ! [[ "${lxd_backend}" =~ ^(btrfs|dir|lvm|zfs)$ ]]; then
+    export TEST_UNMET_REQUIREMENT="Backup target tests require a storage driver supporting local storage buckets"
+    return
+  fi
+
+  poolName="lxdtest-$(basename "${LXD_DIR}")"
+
+  # Use a local storage bucket as the S3 storage of the backup target.
+  port="$(local_tcp_port)"
+  lxc config set core.storage_buckets_address "127.0.0.1:${port}"
+  creds=$(lxc storage bucket create "${poolName}" lxdbackups)
+  accessKey=$(echo "${creds}" | awk '{ if ($2 == "access" && $3 == "key:") {print $4}}')
+  secretKey=$(echo "${creds}" | awk '{ if ($2 == "secret" && $3 == "key:") {print $4}}')
+
+  # Check that the configuration and the bucket access are validated.
+  ! lxc backup-target create s3 s3.endpoint="https://127.0.0.1:${port}" s3.bucket=lxdbackups || false
+  ! lxc backup-target create s3 s3.endpoint="https://127.0.0.1:${port}" s3.bucket=missing s3.access_key="${accessKey}" s3.secret_key="${secretKey}" s3.ca_certificate="$(cat "${LXD_DIR}/server.crt")" || false
+
+  lxc backup-target create s3 s3.endpoint="https://127.0.0.1:${port}" s3.bucket=lxdbackups s3.prefix=lxd s3.access_key="${accessKey}" s3.secret_key="${secretKey}" s3.ca_certificate="$(cat "${LXD_DIR}/server.crt")"
+  lxc backup-target list | grep -wF s3
+  [ "$(lxc backup-target get s3 s3.prefix)" = "lxd" ]
+
+  # Upload an instance backup and check that it isn't stored on the server.
+  ensure_import_testimage
+  lxc init testimage c1 -d "${SMALL_ROOT_DISK}"
+  lxc snapshot c1
+  lxc export c1 backup0 --backup-target s3
+  lxc query /1.0/instances/c1/backups | jq --exit-status 'length == 0'
+  lxc backup-target list-backups s3 --format csv | grep -F "instances/c1/backup0,"
+
+  # Restore it under a new name.
+  lxc import instances/c1/backup0 c2 --backup-target s3
+  lxc info c2 | grep -F snap0
+  ! lxc import instances/c1/missing c3 --backup-target s3 || false
+
+  # Upload and restore a custom volume backup.
+  lxc storage volume create "${poolName}" vol1 size=1MiB
+  lxc storage volume export "${poolName}" vol1 --backup-target s3 --compression=none
+  key="$(lxc backup-target list-backups s3 --format csv | awk -F, '/^volumes\// {print $1}')"
+  lxc storage volume import "${poolName}" "${key}" vol2 --backup-target s3
+  lxc storage volume show "${poolName}" vol2
+
+  # Renaming and deleting the backup target keeps the backups.
+  lxc backup-target rename s3 s3-renamed
+  lxc backup-target delete s3-renamed
+  [ "$(lxc storage bucket show "${poolName}" lxdbackups | wc -l)" -gt 0 ]
+
+  # Cleanup.
+  lxc delete c1 c2
+  lxc storage volume delete "${poolName}" vol1
+  lxc storage volume delete "${poolName}" vol2
+  lxc storage bucket delete "${poolName}" lxdbackups
+  lxc config unset core.storage_buckets_address
+}
+
 test_backup_export_import_recover() {
   lxd_backend=$(storage_backend "$LXD_DIR")
 
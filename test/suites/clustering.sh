@@ -1065,6 +1065,105 @@ test_clustering_storage_single_node() {
   kill_lxd "${LXD_ONE_DIR}"
 }
 
+test_clustering_storage_volume_encryption() {
+  # The random storage backend is not supported in clustering tests,
+  # since we need to have the same storage driver on all nodes, so use the driver chosen for the standalone pool.
+  local poolDriver
+  poolDriver="$(storage_backend "${LXD_INITIAL_DIR}")"
+  if [ "${poolDriver}" != "zfs" ] && [ "${poolDriver}" != "lvm" ] && [ "${poolDriver}" != "ceph" ]; then
+    export TEST_UNMET_REQUIREMENT="${poolDriver} driver does not support encrypted storage volumes"
+    return 0
+  fi
+
+  if ! command -v cryptsetup >/dev/null; then
+    export TEST_UNMET_REQUIREMENT="cryptsetup is required for encrypted storage volumes"
+    return 0
+  fi
+
+  spawn_lxd_and_bootstrap_cluster "${poolDriver}"
+
+  local cert
+  cert="$(cert_to_yaml "${LXD_ONE_DIR}/cluster.crt")"
+
+  spawn_lxd_and_join_cluster "${cert}" 2 1 "${LXD_ONE_DIR}" "${poolDriver}"
+
+  local volume_config="size=64MiB block.encryption=luks2"
+  if [ "${poolDriver}" = "zfs" ]; then
+    volume_config="${volume_config} zfs.block_mode=true"
+  fi
+
+  # In a cluster, the key encryption key isn't generated as it must be identical on all members.
+  # shellcheck disable=SC2086
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc storage volume create data vol1 ${volume_config} || false
+  # shellcheck disable=SC2086
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage volume create data vol1 ${volume_config} 2>&1 | grep -F "be copied to all cluster members"
+  [ ! -e "${LXD_ONE_DIR}/storage-encryption.key" ]
+  [ ! -e "${LXD_TWO_DIR}/storage-encryption.key" ]
+
+  # Provide the same key encryption key to all members.
+  head -c 32 /dev/urandom > "${LXD_ONE_DIR}/storage-encryption.key"
+  chmod 0600 "${LXD_ONE_DIR}/storage-encryption.key"
+  cp -a "${LXD_ONE_DIR}/storage-encryption.key" "${LXD_TWO_DIR}/storage-encryption.key"
+
+  # shellcheck disable=SC2086
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage volume create data vol1 ${volume_config}
+  # shellcheck disable=SC2086
+  LXD_DIR="${LXD_TWO_DIR}" lxc storage volume create data vol2 ${volume_config}
+
+  # The wrapped key isn't returned by the API.
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc storage volume show data vol1 | grep -F volatile.encryption.key || false
+
+  # Each member can use the encrypted volumes it created.
+  LXD_DIR="${LXD_TWO_DIR}" ensure_import_testimage
+  LXD_DIR="${LXD_ONE_DIR}" lxc init testimage c1 --target node1
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage volume attach data vol1 c1 /mnt
+  LXD_DIR="${LXD_ONE_DIR}" lxc start c1
+  echo foo | LXD_DIR="${LXD_ONE_DIR}" lxc file push - c1/mnt/foo
+  LXD_DIR="${LXD_ONE_DIR}" lxc stop -f c1
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc init testimage c2 --target node2
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage volume attach data vol2 c2 /mnt
+  LXD_DIR="${LXD_ONE_DIR}" lxc start c2
+  LXD_DIR="${LXD_ONE_DIR}" lxc stop -f c2
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage volume detach data vol2 c2
+
+  if [ "${poolDriver}" = "ceph" ]; then
+    # Remote volumes encrypted on one member can be used on the others.
+    LXD_DIR="${LXD_ONE_DIR}" lxc storage volume detach data vol1 c1
+    LXD_DIR="${LXD_ONE_DIR}" lxc storage volume attach data vol1 c2 /mnt
+    LXD_DIR="${LXD_ONE_DIR}" lxc start c2
+    [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc exec c2 -- cat /mnt/foo)" = "foo" ]
+    LXD_DIR="${LXD_ONE_DIR}" lxc stop -f c2
+
+    # A member with a different key encryption key reports it.
+    head -c 32 /dev/urandom > "${LXD_TWO_DIR}/storage-encryption.key"
+    ! LXD_DIR="${LXD_ONE_DIR}" lxc start c2 || false
+    LXD_DIR="${LXD_ONE_DIR}" lxc start c2 2>&1 | grep -F "must be identical on all cluster members"
+    cp -a "${LXD_ONE_DIR}/storage-encryption.key" "${LXD_TWO_DIR}/storage-encryption.key"
+  fi
+
+  # Clean up.
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete -f c1 c2
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage volume delete data vol1
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage volume delete data vol2
+  LXD_DIR="${LXD_ONE_DIR}" lxc image delete testimage
+
+  printf 'config: {}\ndevices: {}' | LXD_DIR="${LXD_ONE_DIR}" lxc profile edit default
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage delete data
+
+  LXD_DIR="${LXD_TWO_DIR}" lxd shutdown
+  LXD_DIR="${LXD_ONE_DIR}" lxd shutdown
+
+  rm -f "${LXD_TWO_DIR}/unix.socket"
+  rm -f "${LXD_ONE_DIR}/unix.socket"
+
+  teardown_clustering_netns
+  teardown_clustering_bridge
+
+  kill_lxd "${LXD_ONE_DIR}"
+  kill_lxd "${LXD_TWO_DIR}"
+}
+
 test_clustering_network() {
   spawn_lxd_and_bootstrap_cluster
 
@@ -3796,6 +3895,24 @@ test_clustering_evacuation() {
   [ "${evac_c1_node}" != "${evac_c2_node}" ]
 
   LXD_DIR="${LXD_ONE_DIR}" lxc cluster restore node1 --force
+
+  echo "==> Test rebalance: spread/strict"
+  echo "Verify the restore brought both instances back on node1"
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc list -f csv -c L evac-c1)" = "node1" ]
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc list -f csv -c L evac-c2)" = "node1" ]
+
+  echo "Verify the dry run plans a single migration off node1 without performing it"
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc placement-group rebalance pg-evac-spread-strict --dry-run -f csv | wc -l)" = "1" ]
+  LXD_DIR="${LXD_ONE_DIR}" lxc placement-group rebalance pg-evac-spread-strict --dry-run -f csv | grep -F ',node1,'
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc list -f csv -c L evac-c2)" = "node1" ]
+
+  echo "Rebalance and verify instances are on different nodes"
+  LXD_DIR="${LXD_ONE_DIR}" lxc placement-group rebalance pg-evac-spread-strict
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc list -f csv -c L evac-c1)" != "$(LXD_DIR="${LXD_ONE_DIR}" lxc list -f csv -c L evac-c2)" ]
+
+  echo "Verify a balanced placement group has nothing to rebalance"
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc placement-group rebalance pg-evac-spread-strict --dry-run -f csv | wc -l)" = "0" ]
+
   LXD_DIR="${LXD_ONE_DIR}" lxc delete evac-c1 evac-c2 --force
 
   echo "==> Test: spread/strict with insufficient nodes for strict enforcement"
@@ -119,12 +119,21 @@ EOF
   lxc network set "${netName}" security.acls=testacl
 
   echo "Verify corresponding firewall rules"
+  local aclID
+  aclID="$(lxd sql global --format csv "SELECT id FROM networks_acls WHERE name = 'testacl'")"
   if [ "$firewallDriver" = "xtables" ]; then
-    iptables -w -S | grep -xF -- "-A lxd_acl_${netName} -s 192.168.1.2/32 -d ${daddr} -o ${netName} -p tcp -m multiport --dports 22,2222:2223 -j ACCEPT"
+    iptables -w -S | grep -xF -- "-A lxd_acl_${netName} -s 192.168.1.2/32 -d ${daddr} -o ${netName} -p tcp -m multiport --dports 22,2222:2223 -m comment --comment lxd_acl${aclID}-ingress-1 -j ACCEPT"
   else
-    nft -nn list chain inet lxd "acl.${netName}" | grep -F "oifname \"${netName}\" ip saddr 192.168.1.2 ip daddr ${daddr} tcp dport { 22, 2222-2223 } accept"
+    nft -nn list chain inet lxd "acl.${netName}" | grep -F "oifname \"${netName}\" ip saddr 192.168.1.2 ip daddr ${daddr} tcp dport { 22, 2222-2223 } counter packets 0 bytes 0 accept comment \"lxd_acl${aclID}-ingress-1\""
   fi
 
+  echo "Verify ACL rule counters"
+  acl_state_output="$(lxc query "/1.0/network-acls/testacl/state")"
+  jq --exit-status '.ingress | length == 2' <<< "${acl_state_output}"
+  jq --exit-status '.ingress[1].packets == 0 and .ingress[1].bytes == 0' <<< "${acl_state_output}"
+  jq --exit-status '.egress == []' <<< "${acl_state_output}"
+  [ "$(lxc network acl show-log testacl --counters -f csv | grep -c '^ingress,')" = 2 ]
+
   echo "Stop applying ACL to test network"
   lxc network unset "${netName}" security.acls
 
@@ -0,0 +1,79 @@
+test_network_address_set() {
+  firewallDriver=$(lxc info | awk -F ":" '/firewall:/{gsub(/ /, "", $0); print $2}')
+  netName=lxdt$$
+
+  # Check basic address set creation, listing, deletion and project namespacing support.
+  ! lxc network address-set create 192.168.1.1 || false # Don't allow non-hostname compatible names.
+  ! lxc network address-set create testset foo_bar || false # Invalid address.
+  ! lxc network address-set create testset 192.0.2.10 192.0.2.10 || false # Duplicate address.
+  lxc network address-set create testset 192.0.2.10 198.51.100.0/24 --description "Test set"
+  lxc project create testproj -c features.networks=true
+  lxc network address-set create testset --project testproj
+  [ "$(lxc network address-set ls -f csv | grep -cwF 'testset')" = 1 ]
+  [ "$(lxc network address-set ls -f csv --project testproj | grep -cwF 'testset')" = 1 ]
+  [ "$(lxc network address-set ls --all-projects -f csv | grep -cwF 'testset')" = 2 ]
+  lxc network address-set delete testset --project testproj
+  lxc project delete testproj
+
+  set_show_output="$(lxc query /1.0/network-address-sets/testset)"
+  jq --exit-status '.description == "Test set"' <<< "${set_show_output}"
+  jq --exit-status '.addresses == ["192.0.2.10", "198.51.100.0/24"]' <<< "${set_show_output}"
+
+  # Address set edits.
+  lxc network address-set add testset 2001:db8::10 localhost
+  ! lxc network address-set add testset 192.0.2.10 || false # Duplicate address.
+  lxc network address-set remove testset 198.51.100.0/24
+  ! lxc network address-set remove testset 198.51.100.0/24 || false # Address not in the set.
+  jq --exit-status '.addresses == ["192.0.2.10", "2001:db8::10", "localhost"]' <<< "$(lxc query /1.0/network-address-sets/testset)"
+
+  # Address set custom config.
+  lxc network address-set set testset user.somekey foo
+  [ "$(lxc network address-set get testset user.somekey)" = "foo" ]
+  ! lxc network address-set set testset non.userkey foo || false
+  lxc network address-set unset testset user.somekey
+  [ "$(lxc network address-set get testset user.somekey)" = "" ]
+
+  # Referencing address sets in ACL rules.
+  lxc network acl create testacl
+  ! lxc network acl rule add testacl ingress action=allow source='$unknown' || false # Unknown address set.
+  lxc network acl rule add testacl ingress action=allow source='$testset' protocol=tcp destination_port=22
+  jq --exit-status '.used_by == ["/1.0/network-acls/testacl"]' <<< "$(lxc query /1.0/network-address-sets/testset)"
+  ! lxc network address-set delete testset || false # In use by an ACL.
+  ! lxc network address-set rename testset testset2 || false # In use by an ACL.
+
+  if [ "$firewallDriver" = "nftables" ]; then
+    lxc network create "${netName}" ipv4.address=192.0.2.1/24 ipv6.address=fd42:4242:4242:1010::1/64 security.acls=testacl
+
+    echo "Verify the rule matches the named sets of the address set"
+    nft -nn list chain inet lxd "acl.${netName}" | grep -E "ip saddr @lxd_addrset[0-9]+_ip4 tcp dport 22 .*accept"
+    nft -nn list chain inet lxd "acl.${netName}" | grep -E "ip6 saddr @lxd_addrset[0-9]+_ip6 tcp dport 22 .*accept"
+    nft list sets inet lxd | grep -F "192.0.2.10"
+    nft list sets inet lxd | grep -F "127.0.0.1" # Resolved domain name.
+
+    echo "Verify address set updates are applied without rewriting the rules"
+    lxc network address-set add testset 203.0.113.0/24
+    nft list sets inet lxd | grep -F "203.0.113.0/24"
+    lxc network address-set remove testset 192.0.2.10
+    ! nft list sets inet lxd | grep -F "192.0.2.10" || false
+
+    lxc network delete "${netName}"
+  else
+    echo "Verify ACLs using address sets can't be assigned to bridge networks with xtables"
+    ! lxc network create "${netName}" ipv4.address=192.0.2.1/24 ipv6.address=none security.acls=testacl || false
+  fi
+
+  lxc network acl rule remove testacl ingress --force
+  jq --exit-status '.used_by == []' <<< "$(lxc query /1.0/network-address-sets/testset)"
+  lxc network acl delete testacl
+
+  # Address set rename.
+  ! lxc network address-set rename testset 192.168.1.1 || false # Don't allow non-hostname compatible names.
+  lxc network address-set rename testset testset2
+  lxc network address-set show testset2
+
+  lxc network address-set delete testset2
+  [ "$(lxc network address-set ls -f csv || echo fail)" = "" ]
+  if [ "$firewallDriver" = "nftables" ]; then
+    ! nft list sets inet lxd | grep -F "lxd_addrset" || false
+  fi
+}
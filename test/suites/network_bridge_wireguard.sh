@@ -0,0 +1,64 @@
+test_network_bridge_wireguard() {
+  if ! command -v wg >/dev/null; then
+    export TEST_UNMET_REQUIREMENT="The wg tool is required for WireGuard tunnels"
+    return
+  fi
+
+  if ! modprobe wireguard; then
+    export TEST_UNMET_REQUIREMENT="WireGuard kernel support is required for WireGuard tunnels"
+    return
+  fi
+
+  netName=lxdt$$
+  peerKey="TrMvSoP4jYQlY6RIzBgbssQqY3vxI2Pi+y71lOWWXX0="
+
+  lxc network create "${netName}" ipv4.address=192.0.2.1/24 ipv6.address=none
+
+  # Check invalid WireGuard tunnel configurations are rejected.
+  ! lxc network set "${netName}" tunnel.dc.protocol=wireguard tunnel.dc.remote=203.0.113.10 || false
+  ! lxc network set "${netName}" tunnel.dc.protocol=wireguard tunnel.dc.remote_public_key=invalid || false
+  ! lxc network set "${netName}" tunnel.dc.protocol=wireguard tunnel.dc.cluster_link=missing || false
+  ! lxc network set "${netName}" tunnel.dc.protocol=wireguard tunnel.dc2.protocol=wireguard || false
+  ! lxc network set "${netName}" tunnel.dc.protocol=wireguard bridge.mtu=1400 || false
+
+  # Check the tunnel interfaces are created with the default MTU.
+  lxc network set "${netName}" tunnel.dc.protocol=wireguard tunnel.dc.remote=203.0.113.10 tunnel.dc.remote_public_key="${peerKey}"
+  [ "$(< "/sys/class/net/${netName}/mtu")" = "1350" ]
+  [ -d "/sys/class/net/${netName}-dc-wg" ]
+  [ -e "/sys/class/net/${netName}/brif/${netName}-dc" ]
+
+  # Check the key pair is generated by LXD and exposed in the network state.
+  [ "$(stat -c %a "${LXD_DIR}/networks/${netName}/wireguard.key")" = "600" ]
+  publicKey="$(lxc query "/1.0/networks/${netName}/state" | jq --exit-status --raw-output '.wireguard.public_key')"
+  [ "${publicKey}" = "$(wg pubkey < "${LXD_DIR}/networks/${netName}/wireguard.key")" ]
+  [ "$(lxc query "/1.0/networks/${netName}/state" | jq --exit-status --raw-output '.wireguard.listen_port')" = "51820" ]
+  lxc network info "${netName}" | grep -F "Public key: ${publicKey}"
+
+  # Check no next key is announced outside a key rotation.
+  [ "$(lxc query "/1.0/networks/${netName}/state" | jq --raw-output '.wireguard.next_public_key')" = "null" ]
+
+  # Check the manually configured peer is added along with its forwarding database entry.
+  for _ in $(seq 10); do
+    if wg show "${netName}-dc-wg" peers | grep -xF "${peerKey}"; then
+      break
+    fi
+
+    sleep 1
+  done
+
+  wg show "${netName}-dc-wg" endpoints | grep -F "203.0.113.10:51820"
+  wg show "${netName}-dc-wg" allowed-ips | grep -F "${peerKey}" | grep -F "fe80:"
+  bridge fdb show dev "${netName}-dc" | grep -F "00:00:00:00:00:00 dst fe80:"
+
+  # Check the key pair is kept when the network is reconfigured.
+  lxc network set "${netName}" tunnel.dc.port=51821
+  [ "$(lxc query "/1.0/networks/${netName}/state" | jq --exit-status --raw-output '.wireguard.public_key')" = "${publicKey}" ]
+  [ "$(lxc query "/1.0/networks/${netName}/state" | jq --exit-status --raw-output '.wireguard.listen_port')" = "51821" ]
+
+  # Check removing the tunnel removes its interfaces.
+  lxc network show "${netName}" | sed '/tunnel\.dc\./d' | lxc network edit "${netName}"
+  ! [ -d "/sys/class/net/${netName}-dc-wg" ] || false
+  [ "$(lxc query "/1.0/networks/${netName}/state" | jq --raw-output '.wireguard')" = "null" ]
+
+  lxc network delete "${netName}"
+}
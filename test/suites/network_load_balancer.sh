@@ -0,0 +1,156 @@
+test_network_load_balancer() {
+  ensure_import_testimage
+  ensure_has_localhost_remote "${LXD_ADDR}"
+
+  firewallDriver=$(lxc info | awk -F ":" '/firewall:/{gsub(/ /, "", $0); print $2}')
+  netName=lxdt$$
+
+  lxc network create "${netName}" \
+        ipv4.address=192.0.2.1/24 \
+        ipv6.address=fd42:4242:4242:1010::1/64
+
+  # Check creating a load balancer with an unspecified address fails.
+  ! lxc network load-balancer create "${netName}" 0.0.0.0 || false
+
+  # Check creating an empty load balancer doesn't create any firewall rules.
+  lxc network load-balancer create "${netName}" 198.51.100.1
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -t nat -S | grep -F "generated for LXD network-load-balancer ${netName}" || false
+  else
+    ! nft -nn list chain inet lxd "lbprert.${netName}" || false
+  fi
+
+  # Check the load balancer is exported via BGP prefixes.
+  lxc query /internal/testing/bgp | grep -F "198.51.100.1/32"
+
+  # Check a listen address can't be used by both a forward and a load balancer.
+  ! lxc network forward create "${netName}" 198.51.100.1 || false
+
+  # Check a port targeting a single backend creates a plain DNAT rule.
+  lxc network load-balancer backend add "${netName}" 198.51.100.1 b1 192.0.2.2 8080
+  lxc network load-balancer port add "${netName}" 198.51.100.1 tcp 80 target_backend=b1
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -t nat -S | grep -F -- "-A PREROUTING -d 198.51.100.1/32 -p tcp -m tcp --dport 80 -m comment --comment \"generated for LXD network-load-balancer ${netName}\" -j DNAT --to-destination 192.0.2.2:8080"
+    iptables -w -t nat -S | grep -F -- "-A POSTROUTING -s 192.0.2.2/32 -d 192.0.2.2/32 -p tcp -m tcp --dport 8080 -m comment --comment \"generated for LXD network-load-balancer ${netName}\" -j MASQUERADE"
+  else
+    nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "ip daddr 198.51.100.1 tcp dport 80 dnat ip to 192.0.2.2:8080"
+    nft -nn list chain inet lxd "lbout.${netName}" | grep -F "ip daddr 198.51.100.1 tcp dport 80 dnat ip to 192.0.2.2:8080"
+    nft -nn list chain inet lxd "lbpstrt.${netName}" | grep -F "ip saddr 192.0.2.2 ip daddr 192.0.2.2 tcp dport 8080 masquerade"
+  fi
+
+  # Check a port targeting multiple backends spreads the connections over them.
+  lxc network load-balancer backend add "${netName}" 198.51.100.1 b2 192.0.2.3 8080
+  lxc network load-balancer port remove "${netName}" 198.51.100.1 tcp 80
+  lxc network load-balancer port add "${netName}" 198.51.100.1 tcp 80 target_backend=b1,b2
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -t nat -S | grep -F -- "-m statistic --mode nth --every 2 --packet 0 -m comment --comment \"generated for LXD network-load-balancer ${netName}\" -j DNAT --to-destination 192.0.2.2:8080"
+    iptables -w -t nat -S | grep -F -- "-m comment --comment \"generated for LXD network-load-balancer ${netName}\" -j DNAT --to-destination 192.0.2.3:8080"
+  else
+    nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "numgen inc mod 2"
+  fi
+
+  # Check UDP ports use a hash of the source address with nftables.
+  lxc network load-balancer port add "${netName}" 198.51.100.1 udp 53 target_backend=b1,b2
+  if [ "$firewallDriver" != "xtables" ]; then
+    nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "udp dport 53"
+  fi
+
+  # Check a pool of instances can be targeted.
+  lxc init testimage c1
+  lxc config device add c1 eth0 nic network="${netName}" ipv4.address=192.0.2.10
+  lxc network load-balancer pool create "${netName}" web target_port=8080 healthcheck.interval=1
+  lxc network load-balancer pool instance add "${netName}" web c1
+  lxc network load-balancer port add "${netName}" 198.51.100.1 tcp 443 target_pool=web
+
+  # Check stopped instances aren't targeted.
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -t nat -S | grep -F -- "--to-destination 192.0.2.10:8080" || false
+  else
+    ! nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "tcp dport 443" || false
+  fi
+
+  [ "$(lxc query "/1.0/networks/${netName}/load-balancer-pools/web/state" | jq --exit-status --raw-output '.targets[0].status')" = "unknown" ]
+
+  # Check the instance is targeted once started. Health checks are disabled so that it isn't reported offline yet.
+  lxc network load-balancer pool set "${netName}" web healthcheck=false
+  lxc start c1
+  for _ in $(seq 10); do
+    if [ "$firewallDriver" = "xtables" ]; then
+      iptables -w -t nat -S | grep -F -- "--to-destination 192.0.2.10:8080" && break
+    else
+      nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "tcp dport 443 dnat ip to 192.0.2.10:8080" && break
+    fi
+
+    sleep 1
+  done
+
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -t nat -S | grep -F -- "--to-destination 192.0.2.10:8080"
+  else
+    nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "tcp dport 443 dnat ip to 192.0.2.10:8080"
+  fi
+
+  # Check the instance is reported offline when not listening on the target port and taken out of the load balancer.
+  lxc network load-balancer pool set "${netName}" web healthcheck=true
+  for _ in $(seq 10); do
+    if lxc query "/1.0/networks/${netName}/load-balancer-pools/web/state" | jq --exit-status '.targets[] | select(.address == "192.0.2.10" and .status == "offline")'; then
+      break
+    fi
+
+    sleep 1
+  done
+
+  lxc query "/1.0/networks/${netName}/load-balancer-pools/web/state" | jq --exit-status '.targets[] | select(.address == "192.0.2.10" and .status == "offline")'
+
+  for _ in $(seq 10); do
+    if [ "$firewallDriver" = "xtables" ]; then
+      iptables -w -t nat -S | grep -qF -- "--to-destination 192.0.2.10:8080" || break
+    else
+      nft -nn list chain inet lxd "lbprert.${netName}" | grep -qF "tcp dport 443" || break
+    fi
+
+    sleep 1
+  done
+
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -t nat -S | grep -F -- "--to-destination 192.0.2.10:8080" || false
+  else
+    ! nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "tcp dport 443" || false
+  fi
+
+  # Check disabling health checks reports an unknown status and targets the instance again.
+  lxc network load-balancer pool set "${netName}" web healthcheck=false
+  lxc query "/1.0/networks/${netName}/load-balancer-pools/web/state" | jq --exit-status '.targets[] | select(.address == "192.0.2.10" and .status == "unknown")'
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -t nat -S | grep -F -- "--to-destination 192.0.2.10:8080"
+  else
+    nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "tcp dport 443 dnat ip to 192.0.2.10:8080"
+  fi
+
+  # Check deleting the load balancer removes its BGP prefix and firewall rules.
+  lxc network load-balancer delete "${netName}" 198.51.100.1
+  ! lxc query /internal/testing/bgp | grep -F "198.51.100.1/32" || false
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -t nat -S | grep -F "generated for LXD network-load-balancer ${netName}" || false
+  else
+    ! nft -nn list chain inet lxd "lbprert.${netName}" || false
+    ! nft -nn list chain inet lxd "lbout.${netName}" || false
+    ! nft -nn list chain inet lxd "lbpstrt.${netName}" || false
+  fi
+
+  lxc network load-balancer pool delete "${netName}" web
+  lxc delete -f c1
+
+  # Check deleting the network clears the load balancer firewall rules.
+  lxc network load-balancer create "${netName}" 198.51.100.1
+  lxc network load-balancer backend add "${netName}" 198.51.100.1 b1 192.0.2.2 8080
+  lxc network load-balancer port add "${netName}" 198.51.100.1 tcp 80 target_backend=b1
+  lxc network delete "${netName}"
+
+  ! lxc query /internal/testing/bgp | grep -F "198.51.100.1/32" || false
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -t nat -S | grep -F "generated for LXD network-load-balancer ${netName}" || false
+  else
+    ! nft -nn list chain inet lxd "lbprert.${netName}" || false
+  fi
+}
@@ -0,0 +1,88 @@
+test_network_peer_bridge() {
+  firewallDriver=$(lxc info | awk -F ":" '/firewall:/{gsub(/ /, "", $0); print $2}')
+
+  netName1=lxdt$$a
+  netName2=lxdt$$b
+
+  lxc network create "${netName1}" ipv4.address=192.0.2.1/24 ipv6.address=none
+  lxc project create foo -c features.networks=true
+  lxc network create "${netName2}" ipv4.address=198.51.100.1/24 ipv6.address=none --project foo
+
+  # Check a peering is pending until it is mutual, and doesn't create any firewall rules.
+  lxc network peer create "${netName1}" foo "foo/${netName2}"
+  [ "$(lxc query "/1.0/networks/${netName1}/peers/foo" | jq --exit-status --raw-output '.status')" = "Pending" ]
+
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -S | grep -F "generated for LXD network-peer ${netName1}" || false
+  else
+    ! nft -nn list chain inet lxd "peerfwd.${netName1}" || false
+    ! nft -nn list chain inet lxd "peerpstrt.${netName1}" || false
+  fi
+
+  # Check duplicate peerings are rejected.
+  ! lxc network peer create "${netName1}" foo "foo/${netName2}" || false
+  ! lxc network peer create "${netName1}" bar "foo/${netName2}" || false
+
+  # Check the mutual peering creates the firewall rules on both networks.
+  lxc network peer create "${netName2}" default "default/${netName1}" --project foo
+  [ "$(lxc query "/1.0/networks/${netName1}/peers/foo" | jq --exit-status --raw-output '.status')" = "Created" ]
+  [ "$(lxc query "/1.0/networks/${netName2}/peers/default?project=foo" | jq --exit-status --raw-output '.status')" = "Created" ]
+
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -S "lxd_peer_${netName1}" | grep -F -- "-s 192.0.2.0/24 -d 198.51.100.0/24 -o ${netName2}" | grep -F -- "-j RETURN"
+    iptables -w -S "lxd_peer_${netName1}" | grep -F -- "-o ${netName2}" | grep -F -- "-j DROP"
+    iptables -w -t nat -S POSTROUTING | grep -F -- "-s 192.0.2.0/24 -d 198.51.100.0/24 -o ${netName2}" | grep -F -- "-j RETURN"
+    iptables -w -S "lxd_peer_${netName2}" | grep -F -- "-s 198.51.100.0/24 -d 192.0.2.0/24 -o ${netName1}" | grep -F -- "-j RETURN"
+  else
+    nft -nn list chain inet lxd "peerfwd.${netName1}" | grep -F "oifname \"${netName2}\" ip saddr 192.0.2.0/24 ip daddr 198.51.100.0/24 accept"
+    nft -nn list chain inet lxd "peerfwd.${netName1}" | grep -F "iifname \"${netName1}\" oifname \"${netName2}\" drop"
+    nft -nn list chain inet lxd "peerpstrt.${netName1}" | grep -F "ip saddr 192.0.2.0/24 ip daddr 198.51.100.0/24 snat ip to ip saddr"
+    nft -nn list chain inet lxd "peerfwd.${netName2}" | grep -F "oifname \"${netName1}\" ip saddr 198.51.100.0/24 ip daddr 192.0.2.0/24 accept"
+  fi
+
+  # Check the peer subnets follow the network configuration.
+  lxc network set "${netName2}" ipv4.routes=203.0.113.0/24 --project foo
+
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -S "lxd_peer_${netName1}" | grep -F -- "-s 192.0.2.0/24 -d 203.0.113.0/24 -o ${netName2}"
+  else
+    nft -nn list chain inet lxd "peerfwd.${netName1}" | grep -F "203.0.113.0/24"
+  fi
+
+  # Check the peering can be referred to by the ACLs of the network.
+  lxc network acl create peeracl
+  lxc network acl rule add peeracl ingress action=allow source="@${netName1}/foo"
+  lxc network set "${netName1}" security.acls=peeracl
+
+  if [ "$firewallDriver" != "xtables" ]; then
+    nft -nn list chain inet lxd "acl.${netName1}" | grep -F "198.51.100.0/24"
+  fi
+
+  # Check a peering used by an ACL cannot be deleted.
+  ! lxc network peer delete "${netName1}" foo || false
+  lxc network unset "${netName1}" security.acls
+  lxc network acl delete peeracl
+
+  # Check the description of the peering can be updated.
+  lxc network peer set "${netName1}" foo --property description=test
+  [ "$(lxc network peer get "${netName1}" foo description --property)" = "test" ]
+
+  # Check deleting the peering removes the firewall rules on both networks.
+  lxc network peer delete "${netName1}" foo
+  [ "$(lxc query "/1.0/networks/${netName2}/peers/default?project=foo" | jq --exit-status --raw-output '.status')" = "Errored" ]
+
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -S | grep -F "generated for LXD network-peer ${netName1}" || false
+    ! iptables -w -S | grep -F "generated for LXD network-peer ${netName2}" || false
+    ! iptables -w -t nat -S | grep -F "generated for LXD network-peer ${netName1}" || false
+  else
+    ! nft -nn list chain inet lxd "peerfwd.${netName1}" || false
+    ! nft -nn list chain inet lxd "peerfwd.${netName2}" || false
+    ! nft -nn list chain inet lxd "peerpstrt.${netName2}" || false
+  fi
+
+  lxc network peer delete "${netName2}" default --project foo
+  lxc network delete "${netName2}" --project foo
+  lxc network delete "${netName1}"
+  lxc project delete foo
+}
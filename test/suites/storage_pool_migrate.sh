@@ -0,0 +1,63 @@
+test_storage_pool_migrate() {
+  ensure_import_testimage
+
+  lxc storage create migrate-src dir
+  lxc storage create migrate-dst dir
+
+  # A profile using the source pool for its root disk.
+  lxc profile create migrate
+  lxc profile device add migrate root disk path=/ pool=migrate-src
+
+  lxc init testimage c1 -s migrate-src
+  lxc snapshot c1 snap0
+  lxc init testimage c2 -p default -p migrate
+
+  lxc storage volume create migrate-src vol1
+  lxc storage volume snapshot migrate-src vol1 snap0
+  lxc storage volume attach migrate-src vol1 c1 /mnt
+
+  sub_test "Verify storage pool migration checks"
+
+  ! lxc storage migrate migrate-src migrate-src || false
+  ! lxc storage migrate migrate-src missing || false
+
+  # Instances using the pool must be stopped.
+  lxc start c1
+  ! lxc storage migrate migrate-src migrate-dst || false
+  lxc stop -f c1
+
+  sub_test "Verify storage pool migration"
+
+  lxc storage migrate migrate-src migrate-dst
+
+  # Instances are moved with their snapshots.
+  [ "$(lxc config device get c1 root pool)" = "migrate-dst" ]
+  lxc storage volume show migrate-dst container/c1
+  lxc storage volume show migrate-dst container/c1/snap0
+  lxc storage volume show migrate-dst container/c2
+
+  # Custom volumes are moved with their snapshots and the devices using them are updated.
+  lxc storage volume show migrate-dst vol1
+  lxc storage volume show migrate-dst vol1/snap0
+  [ "$(lxc config device get c1 vol1 pool)" = "migrate-dst" ]
+
+  # Profile root disks are updated.
+  [ "$(lxc profile device get migrate root pool)" = "migrate-dst" ]
+
+  # Nothing is left on the source pool.
+  [ -z "$(lxc storage volume list migrate-src --format csv)" ]
+
+  # The moved instances are usable.
+  lxc start c1
+  lxc exec c1 -- touch /mnt/foo
+  lxc stop -f c1
+
+  # Running the migration again is a no-op.
+  lxc storage migrate migrate-src migrate-dst
+
+  lxc delete c1 c2
+  lxc profile delete migrate
+  lxc storage volume delete migrate-dst vol1
+  lxc storage delete migrate-src
+  lxc storage delete migrate-dst
+}
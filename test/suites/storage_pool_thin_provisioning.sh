@@ -0,0 +1,66 @@
+test_storage_pool_thin_provisioning() {
+  local lxd_backend
+  lxd_backend=$(storage_backend "$LXD_DIR")
+  if [ "${lxd_backend}" != "zfs" ] && [ "${lxd_backend}" != "lvm" ] && [ "${lxd_backend}" != "ceph" ]; then
+    # The overcommit ratio can't be set on pools that don't report provisioned space.
+    ! lxc storage set "lxdtest-$(basename "${LXD_DIR}")" overcommit.ratio=1.5 || false
+    export TEST_UNMET_REQUIREMENT="${lxd_backend} driver does not report provisioned space"
+    return 0
+  fi
+
+  local pool
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+
+  if [ "$lxd_backend" = "zfs" ] || [ "$lxd_backend" = "lvm" ]; then
+    pool="storage-thin"
+    lxc storage create "${pool}" "${lxd_backend}" size=1GiB
+  fi
+
+  sub_test "Verify provisioned space reporting"
+
+  lxc storage volume create "${pool}" vol1 size=256MiB --type=block
+  lxc storage info "${pool}" | grep -F "space provisioned:"
+  lxc query "/1.0/storage-pools/${pool}/resources" | jq --exit-status '.space.provisioned >= 268435456'
+
+  if [ "$lxd_backend" = "lvm" ]; then
+    # Thin pools report their metadata usage.
+    lxc storage info "${pool}" | grep -F "metadata space used:"
+    lxc query "/1.0/storage-pools/${pool}/resources" | jq --exit-status '.metadata.total > 0'
+  fi
+
+  sub_test "Verify overcommit ratio"
+
+  ! lxc storage set "${pool}" overcommit.ratio=foo || false
+  ! lxc storage set "${pool}" overcommit.ratio=0 || false
+  ! lxc storage set "${pool}" capacity.threshold=101 || false
+
+  ensure_import_testimage
+  lxc init testimage c1 -s "${pool}" -d root,size=256MiB
+
+  # Volumes can't be allocated beyond the overcommit ratio.
+  lxc storage set "${pool}" overcommit.ratio=0.0001
+  ! lxc storage volume create "${pool}" vol2 size=256MiB --type=block || false
+  ! lxc storage volume copy "${pool}/vol1" "${pool}/vol2" || false
+
+  # Volumes can't be grown beyond the overcommit ratio.
+  ! lxc storage volume set "${pool}" vol1 size=512MiB || false
+  [ "$(lxc storage volume get "${pool}" vol1 size)" = "256MiB" ]
+
+  # Neither can instance root disks.
+  ! lxc config device set c1 root size=512MiB || false
+  [ "$(lxc config device get c1 root size)" = "256MiB" ]
+  lxc delete c1
+
+  # Snapshots aren't subject to the overcommit ratio.
+  lxc storage volume snapshot "${pool}" vol1 snap0
+
+  lxc storage unset "${pool}" overcommit.ratio
+  lxc storage volume create "${pool}" vol2 size=256MiB --type=block
+
+  lxc storage volume delete "${pool}" vol1
+  lxc storage volume delete "${pool}" vol2
+
+  if [ "$lxd_backend" = "zfs" ] || [ "$lxd_backend" = "lvm" ]; then
+    lxc storage delete "${pool}"
+  fi
+}
@@ -0,0 +1,65 @@
+test_storage_volume_clone() {
+  local lxd_backend
+  lxd_backend=$(storage_backend "$LXD_DIR")
+  if [ "${lxd_backend}" != "zfs" ] && [ "${lxd_backend}" != "btrfs" ]; then
+    export TEST_UNMET_REQUIREMENT="${lxd_backend} driver does not support volume clones"
+    return 0
+  fi
+
+  local pool
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+
+  ensure_import_testimage
+  lxc project create clone-project -c features.images=false -c features.profiles=false -c features.storage.volumes=true
+
+  sub_test "Verify custom volume clones across projects"
+
+  lxc storage volume create "${pool}" golden size=32MiB
+  lxc init testimage c1 -s "${pool}"
+  lxc storage volume attach "${pool}" golden c1 /mnt
+  lxc start c1
+  echo foo | lxc file push - c1/mnt/foo
+  lxc stop -f c1
+  lxc storage volume detach "${pool}" golden c1
+  lxc storage volume snapshot "${pool}" golden snap0
+
+  # Clones must be in the same pool as their source and can't be refreshed.
+  lxc storage create clone-other dir
+  ! lxc storage volume copy "${pool}/golden" clone-other/vol1 --clone || false
+  lxc storage delete clone-other
+  ! lxc storage volume copy "${pool}/golden" "${pool}/vol1" --clone --refresh || false
+
+  lxc storage volume copy "${pool}/golden" "${pool}/vol1" --clone --target-project clone-project
+  lxc storage volume copy "${pool}/golden/snap0" "${pool}/vol2" --clone --target-project clone-project
+
+  # Snapshots aren't cloned.
+  ! lxc storage volume show "${pool}" vol1/snap0 --project clone-project || false
+
+  lxc init testimage c2 -s "${pool}" --project clone-project
+  lxc storage volume attach "${pool}" vol1 c2 /mnt --project clone-project
+  lxc start c2 --project clone-project
+  [ "$(lxc exec c2 --project clone-project -- cat /mnt/foo)" = "foo" ]
+  lxc delete -f c2 --project clone-project
+
+  if [ "${lxd_backend}" = "zfs" ]; then
+    # The origin is recorded and can't be deleted while clones exist.
+    [ "$(lxc storage volume get "${pool}" vol1 volatile.clone.origin --project clone-project)" = "$(lxc storage volume get "${pool}" golden volatile.uuid)" ]
+    [ "$(lxc storage volume get "${pool}" vol2 volatile.clone.origin --project clone-project)" = "$(lxc storage volume get "${pool}" golden/snap0 volatile.uuid)" ]
+    ! lxc storage volume set "${pool}" vol1 volatile.clone.origin="$(uuidgen)" --project clone-project || false
+    ! lxc storage volume delete "${pool}" golden || false
+    lxc storage volume delete "${pool}" vol1 --project clone-project
+    ! lxc storage volume delete "${pool}" golden/snap0 || false
+    ! lxc storage volume delete "${pool}" golden || false
+    lxc storage volume delete "${pool}" vol2 --project clone-project
+    lxc storage volume delete "${pool}" golden
+  else
+    # Clones don't depend on their source.
+    [ "$(lxc storage volume get "${pool}" vol1 volatile.clone.origin --project clone-project)" = "" ]
+    lxc storage volume delete "${pool}" vol1 --project clone-project
+    lxc storage volume delete "${pool}" golden
+    lxc storage volume delete "${pool}" vol2 --project clone-project
+  fi
+
+  lxc delete c1
+  lxc project delete clone-project
+}
@@ -0,0 +1,124 @@
+test_storage_volume_encryption() {
+  local lxd_backend
+  lxd_backend=$(storage_backend "$LXD_DIR")
+  if [ "${lxd_backend}" != "zfs" ] && [ "${lxd_backend}" != "lvm" ] && [ "${lxd_backend}" != "ceph" ]; then
+    export TEST_UNMET_REQUIREMENT="${lxd_backend} driver does not support encrypted storage volumes"
+    return 0
+  fi
+
+  if ! command -v cryptsetup >/dev/null; then
+    export TEST_UNMET_REQUIREMENT="cryptsetup is required for encrypted storage volumes"
+    return 0
+  fi
+
+  ensure_import_testimage
+
+  local pool
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+
+  if [ "$lxd_backend" = "zfs" ] || [ "$lxd_backend" = "lvm" ]; then
+    pool="storage-encryption"
+    lxc storage create "${pool}" "${lxd_backend}" size=1GiB
+  fi
+
+  if [ "$lxd_backend" = "zfs" ]; then
+    lxc storage set "${pool}" volume.zfs.block_mode=true
+  fi
+
+  sub_test "Verify encrypted custom volumes"
+
+  # Only LUKS2 is supported.
+  ! lxc storage volume create "${pool}" vol1 block.encryption=luks1 || false
+
+  # A wrapped key is generated along with the volume, and isn't returned by the API.
+  lxc storage volume create "${pool}" vol1 size=64MiB block.encryption=luks2
+  wrapped_key="$(lxd sql global --format csv "SELECT storage_volumes_config.value FROM storage_volumes_config JOIN storage_volumes ON storage_volumes.id = storage_volumes_config.storage_volume_id WHERE storage_volumes.name = 'vol1' AND storage_volumes_config.key = 'volatile.encryption.key'")"
+  echo "${wrapped_key}" | grep -xE 'file:[A-Za-z0-9+/=]+'
+  [ -f "${LXD_DIR}/storage-encryption.key" ]
+  [ "$(lxc storage volume get "${pool}" vol1 volatile.encryption.key)" = "" ]
+  ! lxc storage volume show "${pool}" vol1 | grep -F volatile.encryption.key || false
+  ! lxc query "/1.0/storage-pools/${pool}/volumes?recursion=1" | grep -F volatile.encryption.key || false
+
+  # Updating the volume through the API keeps its key.
+  lxc storage volume show "${pool}" vol1 | sed 's/^description:.*/description: encrypted/' | lxc storage volume edit "${pool}" vol1
+  [ "$(lxd sql global --format csv "SELECT storage_volumes_config.value FROM storage_volumes_config JOIN storage_volumes ON storage_volumes.id = storage_volumes_config.storage_volume_id WHERE storage_volumes.name = 'vol1' AND storage_volumes_config.key = 'volatile.encryption.key'")" = "${wrapped_key}" ]
+
+  # Encryption can't be changed once the volume is created.
+  ! lxc storage volume set "${pool}" vol1 block.encryption= || false
+  ! lxc storage volume set "${pool}" vol1 volatile.encryption.key=file:Zm9v || false
+
+  # The volume can be written to and snapshotted.
+  lxc init testimage c1 -s "${pool}"
+  lxc storage volume attach "${pool}" vol1 c1 /mnt
+  lxc start c1
+  echo foo | lxc file push - c1/mnt/foo
+  [ "$(lxc exec c1 -- cat /mnt/foo)" = "foo" ]
+  lxc storage volume snapshot "${pool}" vol1 snap0
+  [ "$(lxd sql global --format csv "SELECT storage_volumes_snapshots_config.value FROM storage_volumes_snapshots_config JOIN storage_volumes_snapshots ON storage_volumes_snapshots.id = storage_volumes_snapshots_config.storage_volume_snapshot_id WHERE storage_volumes_snapshots.name = 'snap0' AND storage_volumes_snapshots_config.key = 'volatile.encryption.key'")" = "${wrapped_key}" ]
+  ! lxc storage volume show "${pool}" vol1/snap0 | grep -F volatile.encryption.key || false
+
+  # The volume is mounted through its decrypted mapping, which is closed when unmounted.
+  ls /dev/mapper/lxd-crypt-*
+  lxc stop -f c1
+  ! ls /dev/mapper/lxd-crypt-* || false
+
+  # Encrypted volumes can be grown but not shrunk.
+  lxc storage volume set "${pool}" vol1 size=96MiB
+  ! lxc storage volume set "${pool}" vol1 size=64MiB || false
+
+  # Copies keep the data, and are re-encrypted with a new key shared by their snapshots.
+  lxc storage volume copy "${pool}/vol1" "${pool}/vol2"
+  copy_key="$(lxd sql global --format csv "SELECT storage_volumes_config.value FROM storage_volumes_config JOIN storage_volumes ON storage_volumes.id = storage_volumes_config.storage_volume_id WHERE storage_volumes.name = 'vol2' AND storage_volumes_config.key = 'volatile.encryption.key'")"
+  echo "${copy_key}" | grep -xE 'file:[A-Za-z0-9+/=]+'
+  [ "${copy_key}" != "${wrapped_key}" ]
+  [ "$(lxd sql global --format csv "SELECT storage_volumes_snapshots_config.value FROM storage_volumes_snapshots_config JOIN storage_volumes_snapshots ON storage_volumes_snapshots.id = storage_volumes_snapshots_config.storage_volume_snapshot_id JOIN storage_volumes ON storage_volumes.id = storage_volumes_snapshots.storage_volume_id WHERE storage_volumes.name = 'vol2' AND storage_volumes_snapshots_config.key = 'volatile.encryption.key'")" = "${copy_key}" ]
+  lxc storage volume detach "${pool}" vol1 c1
+  lxc storage volume attach "${pool}" vol2 c1 /mnt
+  lxc start c1
+  [ "$(lxc exec c1 -- cat /mnt/foo)" = "foo" ]
+  lxc stop -f c1
+  lxc storage volume detach "${pool}" vol2 c1
+
+  # Encrypted volumes can't be cloned as clones would share their key.
+  ! lxc storage volume copy "${pool}/vol1" "${pool}/vol3" --clone || false
+
+  # Non optimized backups are restored into a volume encrypted with a new key.
+  lxc storage volume export "${pool}" vol1 "${TEST_DIR}/vol1.tar.gz"
+  lxc storage volume delete "${pool}" vol1
+  lxc storage volume import "${pool}" "${TEST_DIR}/vol1.tar.gz" vol1
+  [ "$(lxc storage volume get "${pool}" vol1 block.encryption)" = "luks2" ]
+  lxc storage volume attach "${pool}" vol1 c1 /mnt
+  lxc start c1
+  [ "$(lxc exec c1 -- cat /mnt/foo)" = "foo" ]
+  lxc delete -f c1
+  rm "${TEST_DIR}/vol1.tar.gz"
+
+  lxc storage volume delete "${pool}" vol1
+  lxc storage volume delete "${pool}" vol2
+
+  sub_test "Verify encrypted instance root volumes"
+
+  lxc launch testimage c1 -s "${pool}" --device root,initial.block.encryption=luks2
+  [ "$(lxc storage volume get "${pool}" container/c1 block.encryption)" = "luks2" ]
+  lxc exec c1 -- touch /root/foo
+  lxc snapshot c1 snap0
+  lxc restart -f c1
+  lxc exec c1 -- stat /root/foo
+  lxc delete -f c1
+
+  # Pool level default.
+  lxc storage set "${pool}" volume.block.encryption=luks2
+  lxc storage volume create "${pool}" vol1 size=64MiB
+  [ "$(lxc storage volume get "${pool}" vol1 block.encryption)" = "luks2" ]
+  lxc storage volume delete "${pool}" vol1
+  lxc storage unset "${pool}" volume.block.encryption
+
+  if [ "$lxd_backend" = "zfs" ]; then
+    # Filesystem datasets can't be encrypted.
+    ! lxc storage volume create "${pool}" vol1 zfs.block_mode=false block.encryption=luks2 || false
+  fi
+
+  if [ "$lxd_backend" = "zfs" ] || [ "$lxd_backend" = "lvm" ]; then
+    lxc storage delete "${pool}"
+  fi
+}
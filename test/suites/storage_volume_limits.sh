@@ -0,0 +1,39 @@
+test_storage_volume_limits() {
+  local pool
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+
+  sub_test "Verify storage volume I/O limits validation"
+
+  lxc storage volume create "${pool}" vol1
+  lxc storage volume set "${pool}" vol1 limits.iops=1000 limits.bandwidth=10MiB
+  [ "$(lxc storage volume get "${pool}" vol1 limits.iops)" = "1000" ]
+  [ "$(lxc storage volume get "${pool}" vol1 limits.bandwidth)" = "10MiB" ]
+  ! lxc storage volume set "${pool}" vol1 limits.iops=foo || false
+  ! lxc storage volume set "${pool}" vol1 limits.iops=-1 || false
+  ! lxc storage volume set "${pool}" vol1 limits.bandwidth=foo || false
+
+  # The limits are reported by the volume info.
+  lxc storage volume info "${pool}" vol1 | grep -xF "IOPS limit: 1000"
+  lxc storage volume info "${pool}" vol1 | grep -xF "Bandwidth limit: 10MiB/s"
+
+  lxc storage volume unset "${pool}" vol1 limits.iops
+  ! lxc storage volume info "${pool}" vol1 | grep -F "IOPS limit:" || false
+  lxc storage volume delete "${pool}" vol1
+
+  sub_test "Verify storage volume I/O limits inheritance"
+
+  lxc storage set "${pool}" volume.limits.iops=500
+  lxc storage volume create "${pool}" vol1
+  [ "$(lxc storage volume get "${pool}" vol1 limits.iops)" = "500" ]
+
+  # Instance root volumes inherit the pool defaults too.
+  ensure_import_testimage
+  lxc init testimage c1 -s "${pool}"
+  [ "$(lxc storage volume get "${pool}" container/c1 limits.iops)" = "500" ]
+  lxc storage volume set "${pool}" container/c1 limits.iops=200
+  [ "$(lxc storage volume get "${pool}" container/c1 limits.iops)" = "200" ]
+  lxc delete c1
+
+  lxc storage unset "${pool}" volume.limits.iops
+  lxc storage volume delete "${pool}" vol1
+}
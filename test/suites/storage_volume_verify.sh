@@ -0,0 +1,58 @@
+test_storage_volume_verify() {
+  local pool
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+
+  ensure_import_testimage
+
+  sub_test "Verify storage volumes"
+
+  lxc storage volume create "${pool}" vol1
+  lxc init testimage c1 -s "${pool}"
+  lxc storage volume attach "${pool}" vol1 c1 /mnt
+  lxc start c1
+  echo lxd-verify-data-1234 | lxc file push - c1/mnt/foo
+  lxc stop -f c1
+
+  lxc storage volume verify "${pool}" vol1 | grep -xF "Storage volume vol1 verified"
+  lxc storage volume verify "${pool}" container/c1
+
+  # Block volumes have a stable checksum.
+  lxc storage volume create "${pool}" vol2 --type=block size=32MiB
+  checksum="$(lxc storage volume verify "${pool}" vol2 | awk '/^SHA-256 checksum:/ {print $3}')"
+  [ "${#checksum}" = "64" ]
+  [ "$(lxc storage volume verify "${pool}" vol2 | awk '/^SHA-256 checksum:/ {print $3}')" = "${checksum}" ]
+  lxc storage volume delete "${pool}" vol2
+
+  # Images and snapshots can't be verified.
+  ! lxc storage volume verify "${pool}" image/"$(lxc image list testimage -c F --format csv)" || false
+  ! lxc storage volume verify "${pool}" vol1/snap0 || false
+  ! lxc storage volume verify "${pool}" missing || false
+
+  # No warning is raised for healthy volumes.
+  ! lxc warning list --format csv | grep -F "Corrupted data found" || false
+
+  sub_test "Verify storage pool scrub"
+
+  # Pools whose driver can't scrub have their volumes verified instead.
+  lxc storage scrub "${pool}" | grep -xF "Storage pool ${pool} scrubbed"
+
+  ! lxc warning list --format csv | grep -F "Corrupted data found" || false
+
+  sub_test "Verify backup checksums"
+
+  lxc storage volume export "${pool}" vol1 "${TEST_DIR}/vol1.tar" --compression none
+  [ "$(tar -tf "${TEST_DIR}/vol1.tar" | tail -n1)" = "backup/checksum" ]
+
+  # Backups are imported when their checksums match.
+  lxc storage volume import "${pool}" "${TEST_DIR}/vol1.tar" vol3
+  lxc storage volume delete "${pool}" vol3
+
+  # Corrupted backups are rejected.
+  sed -i 's/lxd-verify-data-1234/lxd-verify-data-5678/' "${TEST_DIR}/vol1.tar"
+  ! lxc storage volume import "${pool}" "${TEST_DIR}/vol1.tar" vol3 || false
+  ! lxc storage volume show "${pool}" vol3 || false
+  rm "${TEST_DIR}/vol1.tar"
+
+  lxc delete c1
+  lxc storage volume delete "${pool}" vol1
+}